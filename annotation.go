@@ -0,0 +1,91 @@
+package diffview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Annotation is a note on a diff, attached to a whole file, one of its
+// hunks, or a single line: a reviewer's, or an Annotator's when Source is
+// set.
+type Annotation struct {
+	Path      string    `json:"path"`
+	Hunk      int       `json:"hunk,omitempty"` // hunk in the file, from 1, or 0 for the whole file
+	Line      int       `json:"line,omitempty"` // line in the new version, or 0 for the whole hunk or file
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	Source    string    `json:"source,omitempty"` // Annotator that made the note, or empty for a reviewer's
+	Icon      string    `json:"icon,omitempty"`   // One-cell mark for the gutter of an Annotator's line notes
+	Badge     string    `json:"badge,omitempty"`  // Short label for the file header of an Annotator's file notes
+}
+
+// Location describes where the note is attached, such as "api.go",
+// "api.go hunk 2" or "api.go:42".
+func (a Annotation) Location() string {
+	switch {
+	case a.Line > 0:
+		return fmt.Sprintf("%s:%d", a.Path, a.Line)
+	case a.Hunk > 0:
+		return fmt.Sprintf("%s hunk %d", a.Path, a.Hunk)
+	default:
+		return a.Path
+	}
+}
+
+// Annotator analyzes a diff, such as for lint results, TODOs or test
+// coverage, and annotates what it finds for the viewer to show alongside
+// the diff. Paths are as the viewer shows them, without git's "a/" or "b/"
+// prefix. Line annotations may leave Hunk 0; the viewer finds the hunk.
+type Annotator interface {
+	// Name identifies the annotator, and is the Source of annotations
+	// that don't set one.
+	Name() string
+	Annotate(diff *Diff) []Annotation
+}
+
+// AnnotationStore records annotations and retrieves them in the order they
+// were recorded.
+type AnnotationStore interface {
+	Load(path string) ([]Annotation, error)
+	Append(path string, a Annotation) error
+}
+
+// ReviewSummary formats annotations as a Markdown review: one heading per
+// file, in the order files were first annotated, with the file's notes
+// followed by its hunk and line notes in diff order.
+func ReviewSummary(annotations []Annotation) string {
+	var paths []string
+	byPath := make(map[string][]Annotation)
+	for _, a := range annotations {
+		if _, ok := byPath[a.Path]; !ok {
+			paths = append(paths, a.Path)
+		}
+		byPath[a.Path] = append(byPath[a.Path], a)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Review notes\n")
+	for _, path := range paths {
+		notes := byPath[path]
+		sort.SliceStable(notes, func(i, j int) bool {
+			if notes[i].Hunk != notes[j].Hunk {
+				return notes[i].Hunk < notes[j].Hunk
+			}
+			return notes[i].Line < notes[j].Line
+		})
+		fmt.Fprintf(&sb, "\n## `%s`\n\n", path)
+		for _, a := range notes {
+			switch {
+			case a.Line > 0:
+				fmt.Fprintf(&sb, "- **Line %d:** %s\n", a.Line, a.Text)
+			case a.Hunk > 0:
+				fmt.Fprintf(&sb, "- **Hunk %d:** %s\n", a.Hunk, a.Text)
+			default:
+				fmt.Fprintf(&sb, "- %s\n", a.Text)
+			}
+		}
+	}
+	return sb.String()
+}
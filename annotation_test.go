@@ -0,0 +1,35 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotation_Location(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "api.go", diffview.Annotation{Path: "api.go"}.Location())
+	assert.Equal(t, "api.go hunk 2", diffview.Annotation{Path: "api.go", Hunk: 2}.Location())
+	assert.Equal(t, "api.go:42", diffview.Annotation{Path: "api.go", Hunk: 2, Line: 42}.Location())
+}
+
+func TestReviewSummary(t *testing.T) {
+	t.Parallel()
+
+	summary := diffview.ReviewSummary([]diffview.Annotation{
+		{Path: "store.go", Hunk: 1, Line: 30, Text: "Check the error"},
+		{Path: "api.go", Hunk: 2, Text: "Split this up"},
+		{Path: "store.go", Hunk: 1, Line: 12, Text: "Why a map?"},
+		{Path: "api.go", Text: "Needs tests"},
+	})
+
+	assert.Equal(t, "# Review notes\n"+
+		"\n## `store.go`\n\n"+
+		"- **Line 12:** Why a map?\n"+
+		"- **Line 30:** Check the error\n"+
+		"\n## `api.go`\n\n"+
+		"- Needs tests\n"+
+		"- **Hunk 2:** Split this up\n", summary)
+}
@@ -0,0 +1,204 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+)
+
+// noteEditor is the status bar prompt for writing a review note. Tab widens
+// what the note is attached to: the line, then its hunk, then its file.
+type noteEditor struct {
+	active  bool
+	targets []diffview.Annotation // narrowest first
+	scope   int                   // index into targets
+	text    string
+}
+
+// newNoteEditor returns an open prompt attaching the note to targets[0].
+func newNoteEditor(targets []diffview.Annotation) noteEditor {
+	return noteEditor{active: true, targets: targets}
+}
+
+// target returns where the note will be attached.
+func (e noteEditor) target() diffview.Annotation {
+	return e.targets[e.scope]
+}
+
+// update handles a key press while the prompt is open. When the prompt
+// closes, done is true and save reports whether there is a note to save.
+func (e *noteEditor) update(msg tea.KeyMsg) (done, save bool) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		e.active = false
+		return true, false
+	case tea.KeyEnter:
+		e.active = false
+		return true, e.text != ""
+	case tea.KeyTab:
+		e.scope = (e.scope + 1) % len(e.targets)
+	case tea.KeyBackspace:
+		if e.text != "" {
+			runes := []rune(e.text)
+			e.text = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		e.text = ""
+	case tea.KeyRunes, tea.KeySpace:
+		e.text += string(msg.Runes)
+	}
+	return false, false
+}
+
+// noteTargets returns what a note on src can be attached to, narrowest
+// first: the line, its hunk and its file, or just the file if the line
+// isn't in a hunk.
+func noteTargets(diff *diffview.Diff, src sourceRow) []diffview.Annotation {
+	file := diffview.Annotation{Path: src.path}
+	if diff == nil {
+		return []diffview.Annotation{file}
+	}
+	for _, f := range diff.Files {
+		if f.Path() != src.path {
+			continue
+		}
+		for i, h := range f.Hunks {
+			if src.line >= h.NewStart && src.line < h.NewStart+max(h.NewCount, 1) {
+				hunk := diffview.Annotation{Path: src.path, Hunk: i + 1}
+				line := diffview.Annotation{Path: src.path, Hunk: i + 1, Line: src.line}
+				return []diffview.Annotation{line, hunk, file}
+			}
+		}
+	}
+	return []diffview.Annotation{file}
+}
+
+// notesOn returns the notes on the file at path, followed by those on its
+// hunk numbered hunk, including the hunk's line notes. A hunk of 0 matches
+// only the file's own notes.
+func notesOn(notes []diffview.Annotation, path string, hunk int) []diffview.Annotation {
+	var onFile, onHunk []diffview.Annotation
+	for _, n := range notes {
+		switch {
+		case n.Path != path:
+		case n.Hunk == 0:
+			onFile = append(onFile, n)
+		case n.Hunk == hunk:
+			onHunk = append(onHunk, n)
+		}
+	}
+	return append(onFile, onHunk...)
+}
+
+// renderedHunk returns the path of the file holding the nth rendered hunk,
+// counting from 1 as hunk positions do, and the hunk's number in that file.
+func renderedHunk(diff *diffview.Diff, n int) (path string, hunk int, ok bool) {
+	if diff == nil || n < 1 {
+		return "", 0, false
+	}
+	for _, file := range diff.Files {
+		if !file.Visible() {
+			continue
+		}
+		if n <= len(file.Hunks) {
+			return file.Path(), n, true
+		}
+		n -= len(file.Hunks)
+	}
+	return "", 0, false
+}
+
+// defaultAnnotationIcon marks annotated lines whose annotator sets no icon.
+const defaultAnnotationIcon = "●"
+
+// runAnnotators collects the annotations each annotator makes on diff,
+// naming their source and finding the hunks of line annotations without one.
+func runAnnotators(diff *diffview.Diff, annotators []diffview.Annotator) []diffview.Annotation {
+	if diff == nil {
+		return nil
+	}
+	var found []diffview.Annotation
+	for _, annotator := range annotators {
+		for _, a := range annotator.Annotate(diff) {
+			if a.Source == "" {
+				a.Source = annotator.Name()
+			}
+			if a.Line > 0 && a.Hunk == 0 {
+				a.Hunk = noteTargets(diff, sourceRow{path: a.Path, line: a.Line})[0].Hunk
+			}
+			found = append(found, a)
+		}
+	}
+	return found
+}
+
+// annotationIcons maps each line with an annotator's note to the icon drawn
+// beside it, the first note's if there are several.
+func annotationIcons(annotations []diffview.Annotation) map[lineKey]string {
+	icons := make(map[lineKey]string)
+	for _, a := range annotations {
+		key := lineKey{file: a.Path, line: a.Line}
+		if a.Source == "" || a.Line == 0 || icons[key] != "" {
+			continue
+		}
+		icons[key] = annotationIcon(a)
+	}
+	return icons
+}
+
+// annotationBadges returns the badges of annotators' file notes by path,
+// joined with " · " when a file has several.
+func annotationBadges(annotations []diffview.Annotation) map[string]string {
+	badges := make(map[string]string)
+	for _, a := range annotations {
+		if a.Source == "" || a.Hunk != 0 || a.Line != 0 || a.Badge == "" {
+			continue
+		}
+		if badges[a.Path] != "" {
+			badges[a.Path] += " · "
+		}
+		badges[a.Path] += a.Badge
+	}
+	return badges
+}
+
+// annotationIcon returns the one-cell icon for an annotator's note.
+func annotationIcon(a diffview.Annotation) string {
+	if a.Icon == "" {
+		return defaultAnnotationIcon
+	}
+	return ansi.Truncate(a.Icon, 1, "")
+}
+
+// noteLabel formats a note for the status bar: a reviewer's with a pencil,
+// an annotator's with its icon and source.
+func noteLabel(a diffview.Annotation) string {
+	if a.Source == "" {
+		return "✎ " + a.Text
+	}
+	return annotationIcon(a) + " " + a.Source + ": " + a.Text
+}
+
+// annotationDetails lists the notes here in full with where each is
+// attached, followed by a summary of every annotator's findings in the diff.
+func annotationDetails(here, findings []diffview.Annotation) string {
+	var sb strings.Builder
+	sb.WriteString("Notes here\n")
+	if len(here) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, n := range here {
+		sb.WriteString("  " + n.Location() + "\n")
+		sb.WriteString("    " + noteLabel(n) + "\n")
+	}
+	if len(findings) > 0 {
+		fmt.Fprintf(&sb, "\nAll findings (%d)\n", len(findings))
+		for _, n := range findings {
+			sb.WriteString("  " + n.Location() + "  " + noteLabel(n) + "\n")
+		}
+	}
+	return sb.String()
+}
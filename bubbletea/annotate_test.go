@@ -0,0 +1,207 @@
+package bubbletea_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noteStore(loaded ...diffview.Annotation) (*mock.AnnotationStore, *[]diffview.Annotation) {
+	var appended []diffview.Annotation
+	return &mock.AnnotationStore{
+		LoadFn: func(string) ([]diffview.Annotation, error) { return loaded, nil },
+		AppendFn: func(_ string, a diffview.Annotation) error {
+			appended = append(appended, a)
+			return nil
+		},
+	}, &appended
+}
+
+func statusBar(d *bubbletea.Driver) string {
+	lines := strings.Split(d.Frame(), "\n")
+	return lines[len(lines)-1]
+}
+
+func TestModel_Annotate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("attaches a note to the line at the top of the view", func(t *testing.T) {
+		t.Parallel()
+
+		store, appended := noteStore()
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go"),
+			bubbletea.WithAnnotations(store, "notes.jsonl")), 100, 10)
+
+		require.NoError(t, d.Press("j", "j", "j", "c"))
+		assert.Contains(t, statusBar(d), "note on a.go:2 (tab: widen): █")
+		d.Type("check this")
+		require.NoError(t, d.Press("enter"))
+
+		require.Len(t, *appended, 1)
+		note := (*appended)[0]
+		assert.Equal(t, diffview.Annotation{Path: "a.go", Hunk: 1, Line: 2, Text: "check this"},
+			diffview.Annotation{Path: note.Path, Hunk: note.Hunk, Line: note.Line, Text: note.Text})
+		assert.False(t, note.CreatedAt.IsZero())
+		assert.Contains(t, statusBar(d), "✎ check this")
+	})
+
+	t.Run("tab widens the note to the hunk and then the file", func(t *testing.T) {
+		t.Parallel()
+
+		store, appended := noteStore()
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"),
+			bubbletea.WithAnnotations(store, "notes.jsonl")), 100, 10)
+
+		require.NoError(t, d.Press("c", "tab"))
+		assert.Contains(t, statusBar(d), "note on a.go hunk 1")
+		require.NoError(t, d.Press("tab"))
+		assert.Contains(t, statusBar(d), "note on a.go (tab: widen)")
+		d.Type("whole file")
+		require.NoError(t, d.Press("enter"))
+
+		require.Len(t, *appended, 1)
+		assert.Equal(t, 0, (*appended)[0].Hunk)
+		assert.Equal(t, 0, (*appended)[0].Line)
+	})
+
+	t.Run("esc discards the note", func(t *testing.T) {
+		t.Parallel()
+
+		store, appended := noteStore()
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"),
+			bubbletea.WithAnnotations(store, "notes.jsonl")), 100, 10)
+
+		require.NoError(t, d.Press("c"))
+		d.Type("never mind")
+		require.NoError(t, d.Press("esc"))
+
+		assert.Empty(t, *appended)
+		assert.NotContains(t, statusBar(d), "note on")
+	})
+
+	t.Run("does nothing without a store", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go")), 100, 10)
+		require.NoError(t, d.Press("c"))
+		assert.NotContains(t, statusBar(d), "note on")
+	})
+
+	t.Run("shows recorded notes for the file in view", func(t *testing.T) {
+		t.Parallel()
+
+		store, _ := noteStore(
+			diffview.Annotation{Path: "b.go", Text: "rename this file"},
+			diffview.Annotation{Path: "b.go", Hunk: 1, Line: 3, Text: "typo"},
+		)
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go"),
+			bubbletea.WithAnnotations(store, "notes.jsonl")), 100, 10)
+
+		assert.NotContains(t, statusBar(d), "✎")
+		require.NoError(t, d.Press("]"))
+		assert.Contains(t, statusBar(d), "✎ rename this file")
+		require.NoError(t, d.Press("j"))
+		assert.Contains(t, statusBar(d), "✎ rename this file (+1)")
+	})
+
+	t.Run("reports a note that couldn't be saved", func(t *testing.T) {
+		t.Parallel()
+
+		store := &mock.AnnotationStore{
+			LoadFn:   func(string) ([]diffview.Annotation, error) { return nil, nil },
+			AppendFn: func(string, diffview.Annotation) error { return errors.New("disk full") },
+		}
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"),
+			bubbletea.WithAnnotations(store, "notes.jsonl")), 100, 10)
+
+		require.NoError(t, d.Press("c"))
+		d.Type("x")
+		require.NoError(t, d.Press("enter"))
+		assert.Contains(t, statusBar(d), "note not saved: disk full")
+	})
+}
+
+func TestModel_Annotators(t *testing.T) {
+	t.Parallel()
+
+	lint := &mock.Annotator{
+		NameFn: func() string { return "lint" },
+		AnnotateFn: func(diff *diffview.Diff) []diffview.Annotation {
+			return []diffview.Annotation{{Path: "a.go", Line: 2, Text: "unused variable", Icon: "▲"}}
+		},
+	}
+
+	t.Run("marks annotated lines and shows notes in view", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"), bubbletea.WithAnnotators(lint)), 100, 10)
+
+		assert.Contains(t, d.Frame(), "▲+line 2 of a.go")
+		assert.Contains(t, d.Frame(), " +line 1 of a.go")
+		assert.NotContains(t, statusBar(d), "lint")
+		require.NoError(t, d.Press("j"))
+		assert.Contains(t, statusBar(d), "▲ lint: unused variable")
+	})
+
+	t.Run("i shows the notes in view in full and sums up findings", func(t *testing.T) {
+		t.Parallel()
+
+		store, _ := noteStore(diffview.Annotation{Path: "a.go", Text: "split this file"})
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"),
+			bubbletea.WithAnnotations(store, "notes.jsonl"), bubbletea.WithAnnotators(lint)), 100, 12)
+
+		require.NoError(t, d.Press("j", "i"))
+		lines := strings.Split(d.Frame(), "\n")
+		for i := range lines {
+			lines[i] = strings.TrimRight(lines[i], " ")
+		}
+		assert.Equal(t, []string{
+			"Notes here",
+			"  a.go",
+			"    ✎ split this file",
+			"  a.go:2",
+			"    ▲ lint: unused variable",
+			"",
+			"All findings (1)",
+			"  a.go:2  ▲ lint: unused variable",
+		}, lines[:8])
+		require.NoError(t, d.Press("esc"))
+		assert.Contains(t, d.Frame(), "+line 1 of a.go")
+	})
+
+	t.Run("a toggles annotator notes", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"), bubbletea.WithAnnotators(lint)), 100, 10)
+
+		require.NoError(t, d.Press("j", "a"))
+		assert.Contains(t, d.Frame(), " +line 2 of a.go")
+		assert.Contains(t, statusBar(d), "annotator notes hidden")
+		require.NoError(t, d.Press("j"))
+		assert.NotContains(t, statusBar(d), "lint")
+		require.NoError(t, d.Press("a"))
+		assert.Contains(t, d.Frame(), "▲+line 2 of a.go")
+		assert.Contains(t, statusBar(d), "annotator notes shown (1)")
+	})
+	t.Run("shows file note badges in the file header", func(t *testing.T) {
+		t.Parallel()
+
+		coverage := &mock.Annotator{
+			NameFn: func() string { return "coverage" },
+			AnnotateFn: func(diff *diffview.Diff) []diffview.Annotation {
+				return []diffview.Annotation{{Path: "a.go", Text: "1 of 2 changed lines covered", Badge: "50% covered"}}
+			},
+		}
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"), bubbletea.WithAnnotators(coverage)), 100, 10)
+
+		assert.Contains(t, d.Frame(), " 50% covered +10 -0 ──")
+		require.NoError(t, d.Press("a"))
+		assert.NotContains(t, d.Frame(), "50% covered")
+	})
+}
@@ -30,32 +30,6 @@ func extractLastLine(s string) string {
 	return ""
 }
 
-// mockTokenizer implements diffview.Tokenizer for testing.
-type mockTokenizer struct {
-	TokenizeFn      func(language, source string) []diffview.Token
-	TokenizeLinesFn func(language, source string) [][]diffview.Token
-}
-
-func (m *mockTokenizer) Tokenize(language, source string) []diffview.Token {
-	return m.TokenizeFn(language, source)
-}
-
-func (m *mockTokenizer) TokenizeLines(language, source string) [][]diffview.Token {
-	if m.TokenizeLinesFn != nil {
-		return m.TokenizeLinesFn(language, source)
-	}
-	return nil
-}
-
-// mockLanguageDetector implements diffview.LanguageDetector for testing.
-type mockLanguageDetector struct {
-	DetectFromPathFn func(path string) string
-}
-
-func (m *mockLanguageDetector) DetectFromPath(path string) string {
-	return m.DetectFromPathFn(path)
-}
-
 // mockWordDiffer implements diffview.WordDiffer for testing.
 type mockWordDiffer struct {
 	DiffFn func(old, new string) (oldSegs, newSegs []diffview.Segment)
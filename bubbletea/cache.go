@@ -0,0 +1,182 @@
+package bubbletea
+
+import (
+	"reflect"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fwojciec/diffstory"
+)
+
+// renderCache memoizes the costly parts of rendering a diff from one render
+// to the next, so that resizing, scrolling sideways or switching story
+// sections doesn't re-tokenize and re-style every hunk.
+//
+// Hunks are keyed by their lines rather than their place in the diff, so
+// the filtered diffs stories and eval cases render share entries with the
+// full one. Tokens and word diffs don't depend on the width and survive a
+// resize; rendered hunks are dropped when the width, theme or anything else
+// they're drawn with changes.
+type renderCache struct {
+	analysisParams analysisParams
+	analyses       map[hunkCacheKey]hunkAnalysis
+
+	hunkParams hunkParams
+	hunks      map[renderedHunkKey]cachedHunk
+}
+
+// analysisParams are the settings a hunk's analysis depends on besides the
+// hunk itself.
+type analysisParams struct {
+	tokenizer        diffview.Tokenizer
+	wordDiffer       diffview.WordDiffer
+	languageDetector diffview.LanguageDetector
+	tabWidth         int
+	symbols          bool
+}
+
+// hunkParams are the settings a hunk's rendered lines depend on besides the
+// hunk itself. Styles, line icons and moves hold maps and slices, so they're
+// compared by value rather than by ==.
+type hunkParams struct {
+	analysis analysisParams
+	width    int
+	wrap     bool
+	xOffset  int
+	renderer *lipgloss.Renderer
+	secrets  diffview.SecretDetector
+
+	styles    diffview.Styles
+	lineIcons map[lineKey]string
+	moves     []diffview.Move
+}
+
+// equal reports whether p and o render hunks alike.
+func (p hunkParams) equal(o hunkParams) bool {
+	return p.analysis == o.analysis && p.width == o.width && p.wrap == o.wrap &&
+		p.xOffset == o.xOffset && p.renderer == o.renderer && p.secrets == o.secrets &&
+		reflect.DeepEqual(p.styles, o.styles) && reflect.DeepEqual(p.lineIcons, o.lineIcons) &&
+		reflect.DeepEqual(p.moves, o.moves)
+}
+
+// maxCachedHunks bounds how many hunks the cache holds of each kind, so a
+// long session of changing diffs, such as an eval review, doesn't keep every
+// one it has shown.
+const maxCachedHunks = 4096
+
+// newRenderCache returns an empty render cache.
+func newRenderCache() *renderCache {
+	return &renderCache{}
+}
+
+// hunkCacheKey identifies a hunk by its lines, which filtered copies of a
+// diff share with the original, and the language it's highlighted as, which
+// a file's Language override can change without touching its lines.
+type hunkCacheKey struct {
+	path     string
+	language string
+	lines    *diffview.Line
+	n        int
+}
+
+// cacheKeyOf returns the cache key of the hunk of the file at path
+// highlighted as language, and false for a hunk without lines, which
+// there's nothing to cache for.
+func cacheKeyOf(path, language string, hunk diffview.Hunk) (hunkCacheKey, bool) {
+	if len(hunk.Lines) == 0 {
+		return hunkCacheKey{}, false
+	}
+	return hunkCacheKey{path: path, language: language, lines: &hunk.Lines[0], n: len(hunk.Lines)}, true
+}
+
+// hunkAnalysis is what rendering a hunk works out before styling it.
+type hunkAnalysis struct {
+	segments map[int][]diffview.Segment
+	tokens   [][]diffview.Token
+}
+
+// renderedHunkKey identifies a hunk's rendered lines. The gutter is as wide
+// as the largest line number in the whole diff, which differs between the
+// filtered diffs sharing hunks.
+type renderedHunkKey struct {
+	hunk        hunkCacheKey
+	gutterWidth int
+}
+
+// cachedHunk is a hunk's lines as rendered, with the rows renderDiffLayout
+// records for them counted from the first.
+type cachedHunk struct {
+	content    string
+	sourceRows []sourceRow
+	moveRows   []moveRow
+}
+
+// begin readies the cache for rendering with cfg, dropping whatever was
+// rendered with different settings.
+func (c *renderCache) begin(cfg renderConfig) {
+	analysis := analysisParams{
+		tokenizer:        cfg.tokenizer,
+		wordDiffer:       cfg.wordDiffer,
+		languageDetector: cfg.languageDetector,
+		tabWidth:         cfg.tabWidth,
+		symbols:          cfg.styles.Symbols,
+	}
+	if analysis != c.analysisParams || c.analyses == nil {
+		c.analysisParams = analysis
+		c.analyses = make(map[hunkCacheKey]hunkAnalysis)
+	}
+	hunk := hunkParams{
+		analysis:  analysis,
+		width:     cfg.width,
+		wrap:      cfg.wrap,
+		xOffset:   cfg.xOffset,
+		renderer:  cfg.renderer,
+		secrets:   cfg.secrets,
+		styles:    cfg.styles,
+		lineIcons: cfg.lineIcons,
+		moves:     cfg.moves,
+	}
+	if !hunk.equal(c.hunkParams) || c.hunks == nil {
+		c.hunkParams = hunk
+		c.hunks = make(map[renderedHunkKey]cachedHunk)
+	}
+}
+
+// analysis returns the hunk's cached analysis, if any.
+func (c *renderCache) analysis(key hunkCacheKey) (hunkAnalysis, bool) {
+	if c == nil {
+		return hunkAnalysis{}, false
+	}
+	a, ok := c.analyses[key]
+	return a, ok
+}
+
+// storeAnalysis caches the hunk's analysis.
+func (c *renderCache) storeAnalysis(key hunkCacheKey, a hunkAnalysis) {
+	if c == nil {
+		return
+	}
+	if len(c.analyses) >= maxCachedHunks {
+		c.analyses = make(map[hunkCacheKey]hunkAnalysis)
+	}
+	c.analyses[key] = a
+}
+
+// hunk returns the hunk's cached rendered lines, if any.
+func (c *renderCache) hunk(key renderedHunkKey) (cachedHunk, bool) {
+	if c == nil {
+		return cachedHunk{}, false
+	}
+	h, ok := c.hunks[key]
+	return h, ok
+}
+
+// storeHunk caches the hunk's rendered lines.
+func (c *renderCache) storeHunk(key renderedHunkKey, h cachedHunk) {
+	if c == nil {
+		return
+	}
+	if len(c.hunks) >= maxCachedHunks {
+		c.hunks = make(map[renderedHunkKey]cachedHunk)
+	}
+	c.hunks[key] = h
+}
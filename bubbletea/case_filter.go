@@ -0,0 +1,291 @@
+package bubbletea
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fwojciec/diffstory"
+)
+
+// judgmentFilter narrows the cases by how they're judged.
+type judgmentFilter int
+
+const (
+	judgmentAny      judgmentFilter = iota
+	judgmentFailed                  // judged fail
+	judgmentUnjudged                // not judged pass or fail yet
+)
+
+// caseOrder orders the cases the reviewer steps through.
+type caseOrder int
+
+const (
+	orderDataset  caseOrder = iota // as in the dataset
+	orderLargest                   // most changed lines first
+	orderSmallest                  // fewest changed lines first
+)
+
+// caseFilter picks and orders the cases the reviewer steps through. The
+// zero value shows every case in dataset order.
+type caseFilter struct {
+	judgment   judgmentFilter
+	repo       string // only cases from this repo, or "" for any
+	changeType string // only stories of this change type, or "" for any
+	order      caseOrder
+}
+
+// labels describe what the filter narrows and how it orders, such as
+// "failed" and "by size, largest first", leaving out what it doesn't.
+func (f caseFilter) labels() []string {
+	var parts []string
+	switch f.judgment {
+	case judgmentFailed:
+		parts = append(parts, "failed")
+	case judgmentUnjudged:
+		parts = append(parts, "unjudged")
+	}
+	if f.repo != "" {
+		parts = append(parts, "repo "+f.repo)
+	}
+	if f.changeType != "" {
+		parts = append(parts, f.changeType)
+	}
+	switch f.order {
+	case orderLargest:
+		parts = append(parts, "largest first")
+	case orderSmallest:
+		parts = append(parts, "smallest first")
+	}
+	return parts
+}
+
+// keeps reports whether the filter keeps c, judged j (nil if unjudged).
+func (f caseFilter) keeps(c diffview.EvalCase, j *diffview.Judgment) bool {
+	judged := j != nil && j.Judged
+	switch {
+	case f.judgment == judgmentFailed && (!judged || j.Pass):
+		return false
+	case f.judgment == judgmentUnjudged && judged:
+		return false
+	case f.repo != "" && c.Input.Repo != f.repo:
+		return false
+	case f.changeType != "" && (c.Story == nil || c.Story.ChangeType != f.changeType):
+		return false
+	}
+	return true
+}
+
+// caseSize returns the number of lines c's diff adds and deletes.
+func caseSize(c diffview.EvalCase) int {
+	total := 0
+	for _, file := range c.Input.Diff.Files {
+		added, deleted := file.Stats()
+		total += added + deleted
+	}
+	return total
+}
+
+// filterCases returns the indices in m.cases of the cases f keeps, in f's
+// order.
+func (m EvalModel) filterCases(f caseFilter) []int {
+	var kept []int
+	for i, c := range m.cases {
+		if f.keeps(c, m.judgments[c.CaseID()]) {
+			kept = append(kept, i)
+		}
+	}
+	switch f.order {
+	case orderLargest:
+		sort.SliceStable(kept, func(a, b int) bool { return caseSize(m.cases[kept[a]]) > caseSize(m.cases[kept[b]]) })
+	case orderSmallest:
+		sort.SliceStable(kept, func(a, b int) bool { return caseSize(m.cases[kept[a]]) < caseSize(m.cases[kept[b]]) })
+	}
+	return kept
+}
+
+// caseOptions returns the distinct non-empty values of field across the
+// cases, sorted, for cycling through in the filter overlay.
+func (m EvalModel) caseOptions(field func(diffview.EvalCase) string) []string {
+	var options []string
+	for _, c := range m.cases {
+		if v := field(c); v != "" && !slices.Contains(options, v) {
+			options = append(options, v)
+		}
+	}
+	sort.Strings(options)
+	return options
+}
+
+// caseRepo returns the repo c was collected from.
+func caseRepo(c diffview.EvalCase) string {
+	return c.Input.Repo
+}
+
+// caseChangeType returns the change type of c's story, or "" if it has none.
+func caseChangeType(c diffview.EvalCase) string {
+	if c.Story == nil {
+		return ""
+	}
+	return c.Story.ChangeType
+}
+
+// order returns the indices in m.cases of the cases the reviewer steps
+// through, in order: those the filter kept when applied, or every case.
+func (m EvalModel) order() []int {
+	if m.shown != nil {
+		return m.shown
+	}
+	all := make([]int, len(m.cases))
+	for i := range all {
+		all[i] = i
+	}
+	return all
+}
+
+// step returns the index in m.cases of the case delta steps from the
+// current one in order, or -1 past either end. From a case the filter left
+// out, such as one an undo went back to, it's the first case in order.
+func (m EvalModel) step(delta int) int {
+	order := m.order()
+	pos := slices.Index(order, m.currentIndex)
+	switch {
+	case pos < 0 && len(order) > 0:
+		return order[0]
+	case pos < 0 || pos+delta < 0 || pos+delta >= len(order):
+		return -1
+	}
+	return order[pos+delta]
+}
+
+// gotoCase makes the case at idx in m.cases the current one.
+func (m *EvalModel) gotoCase(idx int) {
+	m.currentIndex = idx
+	m.rebuildStoryMaps()
+	m.updateStoryModeForCase()
+	m.updateViewportContent()
+}
+
+// forgetShown updates the cases the filter kept for the case at idx in
+// m.cases being deleted. It clears the filter once no case is left.
+func (m *EvalModel) forgetShown(idx int) {
+	if m.shown == nil {
+		return
+	}
+	shown := make([]int, 0, len(m.shown))
+	for _, i := range m.shown {
+		switch {
+		case i < idx:
+			shown = append(shown, i)
+		case i > idx:
+			shown = append(shown, i-1)
+		}
+	}
+	m.shown = shown
+	if len(shown) == 0 {
+		m.shown, m.filter = nil, caseFilter{}
+	}
+}
+
+// casePosition describes where the current case is among those the
+// reviewer steps through, and the filter if one is applied.
+func (m EvalModel) casePosition() string {
+	if m.shown == nil {
+		return fmt.Sprintf("case %d/%d", m.currentIndex+1, len(m.cases))
+	}
+	pos := "-"
+	if i := slices.Index(m.shown, m.currentIndex); i >= 0 {
+		pos = fmt.Sprint(i + 1)
+	}
+	return fmt.Sprintf("case %s/%d of %d [%s]", pos, len(m.shown), len(m.cases), strings.Join(m.filter.labels(), ", "))
+}
+
+// openFilter shows the filter overlay, starting from the applied filter.
+func (m *EvalModel) openFilter() {
+	m.filterDraft = m.filter
+	m.mode = ModeFilter
+}
+
+// handleFilterKeys changes the draft filter in the overlay. Enter applies
+// it, keeping the current case if the filter does and otherwise going to
+// the first case it keeps, and esc leaves the filter as it was. The cases a
+// filter keeps are fixed when it's applied, so judging a case doesn't
+// make it disappear.
+func (m EvalModel) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.CancelFilter):
+		m.mode = ModeReview
+	case key.Matches(msg, m.keymap.ApplyFilter):
+		if m.filterDraft == (caseFilter{}) {
+			m.filter, m.shown = caseFilter{}, nil
+			m.mode = ModeReview
+			return m, nil
+		}
+		shown := m.filterCases(m.filterDraft)
+		if len(shown) == 0 {
+			// The overlay already says nothing matches
+			return m, nil
+		}
+		m.filter, m.shown = m.filterDraft, shown
+		m.mode = ModeReview
+		if !slices.Contains(shown, m.currentIndex) || m.filter.order != orderDataset {
+			m.gotoCase(shown[0])
+		}
+	case key.Matches(msg, m.keymap.ClearFilter):
+		m.filterDraft = caseFilter{}
+	case key.Matches(msg, m.keymap.FilterJudgment):
+		m.filterDraft.judgment = nextOption([]judgmentFilter{judgmentFailed, judgmentUnjudged}, m.filterDraft.judgment)
+	case key.Matches(msg, m.keymap.FilterRepo):
+		if repos := m.caseOptions(caseRepo); len(repos) > 0 {
+			m.filterDraft.repo = nextOption(repos, m.filterDraft.repo)
+		}
+	case key.Matches(msg, m.keymap.FilterChangeType):
+		if types := m.caseOptions(caseChangeType); len(types) > 0 {
+			m.filterDraft.changeType = nextOption(types, m.filterDraft.changeType)
+		}
+	case key.Matches(msg, m.keymap.SortCases):
+		m.filterDraft.order = nextOption([]caseOrder{orderLargest, orderSmallest}, m.filterDraft.order)
+	}
+	return m, nil
+}
+
+// renderFilterView renders the filter overlay: the draft filter's settings,
+// each with the key that changes it, and how many cases it keeps.
+func (m EvalModel) renderFilterView() string {
+	var s strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Bold(true)
+	keyStyle := lipgloss.NewStyle().Bold(true)
+	descStyle := lipgloss.NewStyle().Faint(true)
+
+	f := m.filterDraft
+	judgment := map[judgmentFilter]string{judgmentAny: "all", judgmentFailed: "failed", judgmentUnjudged: "unjudged"}[f.judgment]
+	order := map[caseOrder]string{orderDataset: "dataset order", orderLargest: "largest first", orderSmallest: "smallest first"}[f.order]
+	orAll := func(v string) string {
+		if v == "" {
+			return "all"
+		}
+		return v
+	}
+
+	s.WriteString(headerStyle.Render("FILTER CASES"))
+	s.WriteString("\n\n")
+	for _, row := range []struct{ key, name, value string }{
+		{"j", "judgment", judgment},
+		{"r", "repo", orAll(f.repo)},
+		{"t", "change type", orAll(f.changeType)},
+		{"s", "sort", order},
+	} {
+		s.WriteString(fmt.Sprintf("  %s  %s %s\n", keyStyle.Render(row.key), descStyle.Render(fmt.Sprintf("%-12s", row.name)), row.value))
+	}
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf("  %d of %d cases match\n", len(m.filterCases(f)), len(m.cases)))
+	s.WriteString("\n\n")
+	s.WriteString(descStyle.Render("enter apply │ x clear │ esc cancel"))
+
+	return s.String()
+}
@@ -0,0 +1,122 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalModel_Filter(t *testing.T) {
+	t.Parallel()
+
+	// filterCase returns a case from repo whose story has changeType and
+	// whose diff adds n lines.
+	filterCase := func(name, repo, changeType string, n int) diffview.EvalCase {
+		hunk := diffview.Hunk{NewStart: 1, NewCount: n}
+		for i := range n {
+			hunk.Lines = append(hunk.Lines, diffview.Line{Type: diffview.LineAdded, Content: "x\n", NewLineNum: i + 1})
+		}
+		return diffview.EvalCase{
+			Input: diffview.ClassificationInput{
+				Repo: repo, Branch: name, Commits: []diffview.CommitBrief{{Hash: name}},
+				Diff: diffview.Diff{Files: []diffview.FileDiff{{NewPath: name + ".go", Hunks: []diffview.Hunk{hunk}}}},
+			},
+			Story: &diffview.StoryClassification{ChangeType: changeType, Summary: "Summary of " + name},
+		}
+	}
+	cases := []diffview.EvalCase{
+		filterCase("small", "alpha", "bugfix", 1),
+		filterCase("large", "beta", "feature", 9),
+		filterCase("medium", "alpha", "feature", 4),
+	}
+	newDriver := func(judgments ...diffview.Judgment) *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(judgments)), 120, 30)
+	}
+
+	t.Run("steps through only the failed cases", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(
+			diffview.Judgment{CaseID: cases[0].CaseID(), Judged: true, Pass: true},
+			diffview.Judgment{CaseID: cases[2].CaseID(), Index: 2, Judged: true, Pass: false},
+		)
+
+		require.NoError(t, d.Press("/", "j"))
+		assert.Contains(t, d.Frame(), "1 of 3 cases match")
+		require.NoError(t, d.Press("enter"))
+		assert.Contains(t, statusBar(d), "case 1/1 of 3 [failed]")
+		assert.Contains(t, d.Frame(), "Summary of medium")
+
+		require.NoError(t, d.Press("n"))
+		assert.Contains(t, d.Frame(), "Summary of medium", "there's no next case")
+	})
+
+	t.Run("narrows by repo and sorts by size", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("/", "r", "s", "enter"))
+		assert.Contains(t, statusBar(d), "case 1/2 of 3 [repo alpha, largest first]")
+		assert.Contains(t, d.Frame(), "Summary of medium")
+
+		require.NoError(t, d.Press("n"))
+		assert.Contains(t, d.Frame(), "Summary of small")
+	})
+
+	t.Run("narrows by change type", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("/", "t", "t", "enter"))
+		assert.Contains(t, statusBar(d), "case 1/2 of 3 [feature]")
+		assert.Contains(t, d.Frame(), "Summary of large")
+	})
+
+	t.Run("keeps judged cases until the filter is applied again", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("/", "j", "j", "s", "enter"))
+		require.Contains(t, d.Frame(), "Summary of large")
+		require.NoError(t, d.Press("f"))
+		assert.Contains(t, statusBar(d), "case 1/3 of 3 [unjudged, largest first]")
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		assert.Equal(t, cases[1].CaseID(), judgments[0].CaseID)
+		assert.Equal(t, 1, judgments[0].Index, "the index is the case's place in the dataset")
+
+		require.NoError(t, d.Press("/", "enter"))
+		assert.Contains(t, statusBar(d), "case 1/2 of 3")
+		assert.Contains(t, d.Frame(), "Summary of medium")
+	})
+
+	t.Run("refuses a filter that matches nothing", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("/", "j", "enter"))
+		assert.Contains(t, d.Frame(), "0 of 3 cases match")
+	})
+
+	t.Run("esc leaves the filter as it was and x clears it", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("/", "r", "esc"))
+		assert.Contains(t, statusBar(d), "case 1/3")
+		assert.NotContains(t, statusBar(d), "repo alpha")
+
+		require.NoError(t, d.Press("/", "r", "enter", "/", "x", "enter"))
+		assert.Contains(t, statusBar(d), "case 1/3")
+		assert.NotContains(t, statusBar(d), "repo alpha")
+	})
+}
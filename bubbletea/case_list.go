@@ -0,0 +1,85 @@
+package bubbletea
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+)
+
+const (
+	// caseListWidth is the width of the case list pane, left of the story
+	// view.
+	caseListWidth = 36
+
+	// caseListMinWidth is the narrowest terminal that shows the case list,
+	// leaving the story view enough room beside it.
+	caseListMinWidth = 100
+)
+
+// caseListShown reports whether the case list sits left of the story view:
+// when it's toggled on, the story view is showing and the terminal is wide
+// enough.
+func (m EvalModel) caseListShown() bool {
+	return m.showCaseList && m.viewMode == ViewStory && m.width >= caseListMinWidth && len(m.cases) > 0
+}
+
+// paneWidth returns the width left for the story view's panes.
+func (m EvalModel) paneWidth() int {
+	if m.caseListShown() {
+		return m.width - caseListWidth - 1
+	}
+	return m.width
+}
+
+// judgmentGlyph marks how j judged a case: ✓ pass, ✗ fail, ● a critique
+// without a verdict or ○ nothing yet.
+func judgmentGlyph(j *diffview.Judgment) string {
+	switch {
+	case j == nil:
+		return "○"
+	case !j.Judged:
+		return "●"
+	case j.Pass:
+		return "✓"
+	}
+	return "✗"
+}
+
+// caseListRow summarizes c, judged j, in one row of the case list: its
+// judgment, ID, repo and change type.
+func caseListRow(c diffview.EvalCase, j *diffview.Judgment) string {
+	id := c.CaseID()
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	parts := []string{judgmentGlyph(j), id, c.Input.Repo}
+	if t := caseChangeType(c); t != "" {
+		parts = append(parts, t)
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderCaseList renders the case list in a width × height area: a header,
+// then the cases the reviewer steps through, in order, scrolled to keep the
+// current case, highlighted, near the middle.
+func (m EvalModel) renderCaseList(width, height int) string {
+	order := m.order()
+	rows := max(height-1, 0)
+	pos := max(slices.Index(order, m.currentIndex), 0)
+	start := min(max(pos-rows/2, 0), max(len(order)-rows, 0))
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("CASES")}
+	current := lipgloss.NewStyle().Reverse(true)
+	for _, idx := range order[start:min(start+rows, len(order))] {
+		c := m.cases[idx]
+		row := ansi.Truncate(caseListRow(c, m.judgments[c.CaseID()]), width, "…")
+		if idx == m.currentIndex {
+			row = current.Render(row + strings.Repeat(" ", max(width-ansi.StringWidth(row), 0)))
+		}
+		lines = append(lines, row)
+	}
+	return lipgloss.NewStyle().Width(width).Height(height).MaxHeight(height).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,64 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalModel_CaseList(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{ID: "first-case", Input: diffview.ClassificationInput{Repo: "alpha", Branch: "first"}, Story: &diffview.StoryClassification{ChangeType: "bugfix", Summary: "Summary one"}},
+		{ID: "second-case", Input: diffview.ClassificationInput{Repo: "beta", Branch: "second"}, Story: &diffview.StoryClassification{ChangeType: "feature", Summary: "Summary two"}},
+	}
+	newDriver := func(width int) *bubbletea.Driver {
+		m := bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments([]diffview.Judgment{
+			{CaseID: "second-case", Index: 1, Judged: true, Pass: false},
+		}))
+		return bubbletea.NewDriver(m, width, 20)
+	}
+
+	t.Run("lists every case with its judgment, repo and change type", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(140)
+		require.NoError(t, d.Press("b"))
+
+		frame := d.Frame()
+		assert.Contains(t, frame, "CASES")
+		assert.Contains(t, frame, "○ first-ca alpha bugfix")
+		assert.Contains(t, frame, "✗ second-c beta feature")
+		assert.Contains(t, frame, "Summary one")
+
+		require.NoError(t, d.Press("b"))
+		assert.NotContains(t, d.Frame(), "CASES")
+	})
+
+	t.Run("arrow keys load the next and previous case", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(140)
+		require.NoError(t, d.Press("b", "down"))
+		assert.Contains(t, d.Frame(), "Summary two")
+		assert.Contains(t, statusBar(d), "case 2/2")
+
+		require.NoError(t, d.Press("up"))
+		assert.Contains(t, d.Frame(), "Summary one")
+	})
+
+	t.Run("stays hidden on narrow terminals", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(80)
+		require.NoError(t, d.Press("b"))
+		assert.NotContains(t, d.Frame(), "CASES")
+
+		require.NoError(t, d.Press("down"))
+		assert.Contains(t, statusBar(d), "case 1/2", "arrows scroll as usual")
+	})
+}
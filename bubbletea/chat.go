@@ -0,0 +1,150 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fwojciec/diffstory"
+)
+
+// AskFunc answers question about diff, the part of story's diff on screen,
+// following on from the conversation in history.
+type AskFunc func(diff *diffview.Diff, story *diffview.StoryClassification, history []diffview.ChatTurn, question string) (string, error)
+
+// chatPanel is an overlay for asking questions about the section on screen.
+// The conversation is kept for as long as the program runs, and each
+// question is sent with the turns before it.
+type chatPanel struct {
+	active   bool
+	input    string
+	pending  string // question waiting for its answer
+	history  []diffview.ChatTurn
+	viewport viewport.Model // transcript, above the input line
+}
+
+// chatAnswerMsg carries the answer to a question from the chat panel.
+type chatAnswerMsg struct {
+	question string
+	answer   string
+	err      error
+}
+
+// chatScope names the part of the diff questions are asked about.
+func (m StoryModel) chatScope() string {
+	idx := m.visibleSectionIndex()
+	if m.story == nil || idx < 0 || idx >= len(m.story.Sections) {
+		return "the whole diff"
+	}
+	return fmt.Sprintf("section %d: %s", idx+1, m.story.Sections[idx].Title)
+}
+
+// openChat opens the chat panel over the code.
+func (m *StoryModel) openChat() {
+	m.chat.active = true
+	m.chat.viewport = viewport.New(m.width, max(m.viewport.Height-2, 1))
+	m.refreshChat()
+}
+
+// refreshChat fits the transcript to the screen and scrolls to its end.
+func (m *StoryModel) refreshChat() {
+	m.chat.viewport.Width = m.width
+	m.chat.viewport.Height = max(m.viewport.Height-2, 1)
+	m.chat.viewport.SetContent(m.chatTranscript())
+	m.chat.viewport.GotoBottom()
+}
+
+// updateChat handles a key press while the chat panel is open: enter asks
+// the question typed, esc closes the panel, arrows and page keys scroll
+// the transcript and other keys edit the question.
+func (m *StoryModel) updateChat(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.chat.active = false
+	case tea.KeyEnter:
+		question := strings.TrimSpace(m.chat.input)
+		if question == "" || m.chat.pending != "" {
+			return nil
+		}
+		m.chat.input = ""
+		m.chat.pending = question
+		m.refreshChat()
+		return m.ask(question)
+	case tea.KeyBackspace:
+		if m.chat.input != "" {
+			runes := []rune(m.chat.input)
+			m.chat.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.chat.input = ""
+	case tea.KeyRunes, tea.KeySpace:
+		m.chat.input += string(msg.Runes)
+	case tea.KeyUp, tea.KeyDown, tea.KeyPgUp, tea.KeyPgDown:
+		m.chat.viewport, _ = m.chat.viewport.Update(msg)
+	}
+	return nil
+}
+
+// ask returns a command that sends question, with the diff of the section
+// on screen and the conversation so far, to the configured AskFunc.
+func (m StoryModel) ask(question string) tea.Cmd {
+	if m.askFn == nil {
+		return nil
+	}
+	diff, _ := m.sectionDiffWithIndices(m.visibleSectionIndex())
+	story := m.story
+	history := append([]diffview.ChatTurn(nil), m.chat.history...)
+	askFn := m.askFn
+	return func() tea.Msg {
+		answer, err := askFn(diff, story, history, question)
+		return chatAnswerMsg{question: question, answer: answer, err: err}
+	}
+}
+
+// receiveAnswer adds an answer to the transcript. A failed question goes
+// back in the input line to try again.
+func (m *StoryModel) receiveAnswer(msg chatAnswerMsg) tea.Cmd {
+	m.chat.pending = ""
+	if msg.err != nil {
+		if m.chat.input == "" {
+			m.chat.input = msg.question
+		}
+		m.refreshChat()
+		return notifyErr("asking question", msg.err)
+	}
+	m.chat.history = append(m.chat.history, diffview.ChatTurn{Question: msg.question, Answer: msg.answer})
+	m.refreshChat()
+	return nil
+}
+
+// chatTranscript renders the conversation wrapped to the panel's width,
+// with the question waiting for an answer last.
+func (m StoryModel) chatTranscript() string {
+	questionStyle := m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)).Bold(true).Width(m.width).Padding(0, 1)
+	answerStyle := m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)).Width(m.width).Padding(0, 1)
+	dimStyle := m.newStyle().Foreground(lipgloss.Color(m.palette.Context)).Width(m.width).Padding(0, 1)
+
+	var parts []string
+	if len(m.chat.history) == 0 && m.chat.pending == "" {
+		parts = append(parts, dimStyle.Render("Ask about "+m.chatScope()+". enter asks, esc closes, ↑/↓ scroll."))
+	}
+	for _, turn := range m.chat.history {
+		parts = append(parts, questionStyle.Render("» "+turn.Question), answerStyle.Render(turn.Answer), "")
+	}
+	if m.chat.pending != "" {
+		parts = append(parts, questionStyle.Render("» "+m.chat.pending), dimStyle.Render("thinking…"))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// renderChat renders the chat panel: the transcript, a rule naming what
+// questions are about, and the input line.
+func (m StoryModel) renderChat() string {
+	label := " " + m.chatScope() + " "
+	rule := label + strings.Repeat("─", max(m.width-lipgloss.Width(label), 0))
+	rule = m.newStyle().Foreground(lipgloss.Color(m.palette.UIForeground)).MaxWidth(m.width).Render(rule)
+	input := m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)).MaxWidth(m.width).Render("> " + m.chat.input + "█")
+	return lipgloss.JoinVertical(lipgloss.Left, m.chat.viewport.View(), rule, input)
+}
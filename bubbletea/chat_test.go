@@ -0,0 +1,86 @@
+package bubbletea_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chatStory() *diffview.StoryClassification {
+	return &diffview.StoryClassification{
+		Summary: "Add an endpoint and its store",
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Store", Hunks: []diffview.HunkRef{{File: "store.go", HunkIndex: 0}}},
+		},
+	}
+}
+
+func TestStoryModel_ChatAsksAboutSectionOnScreen(t *testing.T) {
+	t.Parallel()
+
+	var asked []diffview.ChatTurn
+	var files []string
+	ask := func(diff *diffview.Diff, _ *diffview.StoryClassification, history []diffview.ChatTurn, question string) (string, error) {
+		asked = history
+		files = nil
+		for _, f := range diff.Files {
+			files = append(files, f.NewPath)
+		}
+		return "Answer to " + question, nil
+	}
+	m := bubbletea.NewStoryModel(multiFileDiff("api.go", "store.go"), chatStory(), bubbletea.WithStoryChat(ask))
+	d := bubbletea.NewDriver(m, 80, 20)
+
+	require.NoError(t, d.Press("s", "c"))
+	assert.Contains(t, d.Frame(), "section 2: Store")
+
+	// Keys type the question rather than acting on the viewer
+	d.Type("why q?")
+	require.NoError(t, d.Press("enter"))
+	assert.Equal(t, []string{"store.go"}, files)
+	assert.Empty(t, asked)
+	assert.Contains(t, d.Frame(), "» why q?")
+	assert.Contains(t, d.Frame(), "Answer to why q?")
+	assert.False(t, d.Done())
+
+	// Later questions carry the conversation, even after closing the panel
+	require.NoError(t, d.Press("esc"))
+	assert.NotContains(t, d.Frame(), "Answer to why q?")
+	require.NoError(t, d.Press("c"))
+	d.Type("and then")
+	require.NoError(t, d.Press("enter"))
+	assert.Equal(t, []diffview.ChatTurn{{Question: "why q?", Answer: "Answer to why q?"}}, asked)
+	assert.Contains(t, d.Frame(), "Answer to and then")
+}
+
+func TestStoryModel_ChatFailureKeepsQuestion(t *testing.T) {
+	t.Parallel()
+
+	ask := func(*diffview.Diff, *diffview.StoryClassification, []diffview.ChatTurn, string) (string, error) {
+		return "", errors.New("quota exceeded")
+	}
+	m := bubbletea.NewStoryModel(multiFileDiff("api.go", "store.go"), chatStory(), bubbletea.WithStoryChat(ask))
+	d := bubbletea.NewDriver(m, 80, 20)
+
+	require.NoError(t, d.Press("c"))
+	d.Type("what now")
+	require.NoError(t, d.Press("enter"))
+
+	frame := d.Frame()
+	assert.Contains(t, frame, "asking question: quota exceeded")
+	assert.Contains(t, frame, "> what now")
+	assert.NotContains(t, frame, "» what now")
+}
+
+func TestStoryModel_ChatNeedsAnAnswerer(t *testing.T) {
+	t.Parallel()
+
+	d := bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("api.go"), chatStory()), 80, 20)
+	require.NoError(t, d.Press("c"))
+	assert.NotContains(t, d.Frame(), "enter:ask")
+}
@@ -0,0 +1,221 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+)
+
+// CompareModel is a Bubble Tea model showing two classifications of the
+// same cases side by side, one case at a time, with the lines that differ
+// highlighted.
+type CompareModel struct {
+	pairs          []diffview.CasePair
+	labelA, labelB string
+	current        int
+
+	viewport      viewport.Model
+	width, height int
+	ready         bool
+	keymap        EvalKeyMap
+}
+
+// NewCompareModel returns a model comparing each pair's A and B
+// classifications, with the datasets they came from named by labelA and
+// labelB.
+func NewCompareModel(pairs []diffview.CasePair, labelA, labelB string) CompareModel {
+	return CompareModel{
+		pairs:  pairs,
+		labelA: labelA,
+		labelB: labelB,
+		keymap: DefaultEvalKeyMap(),
+	}
+}
+
+// Init implements tea.Model.
+func (m CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		// Reserve: case header (1), column headers (1), status bar (1)
+		height := max(msg.Height-3, 1)
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, height)
+			m.ready = true
+		} else {
+			m.viewport.Width, m.viewport.Height = msg.Width, height
+		}
+		m.updateContent()
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keymap.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keymap.NextCase):
+			if m.current < len(m.pairs)-1 {
+				m.current++
+				m.updateContent()
+			}
+		case key.Matches(msg, m.keymap.PrevCase):
+			if m.current > 0 {
+				m.current--
+				m.updateContent()
+			}
+		case key.Matches(msg, m.keymap.ScrollDown):
+			m.viewport.ScrollDown(1)
+		case key.Matches(msg, m.keymap.ScrollUp):
+			m.viewport.ScrollUp(1)
+		case key.Matches(msg, m.keymap.HalfPageDown):
+			m.viewport.HalfPageDown()
+		case key.Matches(msg, m.keymap.HalfPageUp):
+			m.viewport.HalfPageUp()
+		case key.Matches(msg, m.keymap.GotoTop):
+			m.viewport.GotoTop()
+		case key.Matches(msg, m.keymap.GotoBottom):
+			m.viewport.GotoBottom()
+		}
+	}
+	return m, nil
+}
+
+// columnWidth returns the width of each side, leaving room for the divider.
+func (m CompareModel) columnWidth() int {
+	return max((m.width-3)/2, 1)
+}
+
+// updateContent renders the current pair into the viewport.
+func (m *CompareModel) updateContent() {
+	if !m.ready || len(m.pairs) == 0 {
+		return
+	}
+	p := m.pairs[m.current]
+	m.viewport.SetContent(renderStoryChanges(diffview.CompareStories(p.A.Story, p.B.Story), m.width) +
+		renderSideBySide(
+			dataViewLines(p.A.Story, m.columnWidth()),
+			dataViewLines(p.B.Story, m.columnWidth()),
+			m.columnWidth(),
+		))
+	m.viewport.GotoTop()
+}
+
+// renderStoryChanges lists what changed between the classifications, ahead
+// of the side by side view, so the changes that matter stand out from
+// rewording. It returns "" if none of them changed.
+func renderStoryChanges(c diffview.StoryChanges, width int) string {
+	if c.Empty() {
+		return ""
+	}
+	var lines []string
+	if c.ChangeType {
+		lines = append(lines, "change type changed")
+	}
+	if c.Summary {
+		lines = append(lines, "summary changed")
+	}
+	for _, r := range c.Retitled {
+		lines = append(lines, fmt.Sprintf("section %d retitled: %q → %q", r.Index+1, r.From, r.To))
+	}
+	orNone := func(title string) string {
+		if title == "" {
+			return "no section"
+		}
+		return fmt.Sprintf("%q", title)
+	}
+	for _, mv := range c.Moved {
+		lines = append(lines, fmt.Sprintf("%s:H%d moved: %s → %s", mv.File, mv.HunkIndex, orNone(mv.From), orNone(mv.To)))
+	}
+
+	bold := lipgloss.NewStyle().Bold(true)
+	var s strings.Builder
+	s.WriteString(bold.Render("Changes") + "\n")
+	for _, line := range lines {
+		s.WriteString(bold.Render(ansi.Truncate("  "+line, width, "…")) + "\n")
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+// renderSideBySide lays before and after out in two columns of width,
+// aligning unchanged lines and highlighting the rest.
+func renderSideBySide(before, after []string, width int) string {
+	changed := lipgloss.NewStyle().Reverse(true)
+	cell := func(text string, highlight bool) string {
+		text = ansi.Truncate(text, width, "…")
+		text += strings.Repeat(" ", max(width-lipgloss.Width(text), 0))
+		if highlight {
+			return changed.Render(text)
+		}
+		return text
+	}
+
+	var s strings.Builder
+	ops := diffLines(before, after)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			s.WriteString(cell(ops[i].text, false) + " │ " + cell(ops[i].text, false) + "\n")
+			i++
+			continue
+		}
+		// Pair a run of removals with the additions that follow it
+		var removed, added []string
+		for ; i < len(ops) && ops[i].kind == '-'; i++ {
+			removed = append(removed, ops[i].text)
+		}
+		for ; i < len(ops) && ops[i].kind == '+'; i++ {
+			added = append(added, ops[i].text)
+		}
+		for k := range max(len(removed), len(added)) {
+			left, right := cell("", false), cell("", false)
+			if k < len(removed) {
+				left = cell(removed[k], true)
+			}
+			if k < len(added) {
+				right = cell(added[k], true)
+			}
+			s.WriteString(left + " │ " + right + "\n")
+		}
+	}
+	return s.String()
+}
+
+// View implements tea.Model.
+func (m CompareModel) View() string {
+	if !m.ready {
+		return "Loading..."
+	}
+	if len(m.pairs) == 0 {
+		return "No classifications differ."
+	}
+
+	p := m.pairs[m.current]
+	bold := lipgloss.NewStyle().Bold(true)
+	side := func(label string, c diffview.EvalCase) string {
+		if c.PromptVersion != "" {
+			label += " (prompt " + c.PromptVersion + ")"
+		}
+		return ansi.Truncate(label, m.columnWidth(), "…")
+	}
+	a := side("A: "+m.labelA, p.A)
+	b := side("B: "+m.labelB, p.B)
+
+	var s strings.Builder
+	s.WriteString(bold.Render(p.A.Input.Name()))
+	s.WriteString("\n")
+	s.WriteString(bold.Render(a + strings.Repeat(" ", max(m.columnWidth()-lipgloss.Width(a), 0)) + " │ " + b))
+	s.WriteString("\n")
+	s.WriteString(m.viewport.View())
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf("case %d/%d │ n/N case j/k scroll q quit", m.current+1, len(m.pairs)))
+	return s.String()
+}
@@ -0,0 +1,84 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareModel(t *testing.T) {
+	t.Parallel()
+
+	pair := func(branch, before, after string) diffview.CasePair {
+		input := diffview.ClassificationInput{Repo: "repo", Branch: branch}
+		return diffview.CasePair{
+			A: diffview.EvalCase{Input: input, Story: &diffview.StoryClassification{ChangeType: "bugfix", Summary: before}, PromptVersion: "v1"},
+			B: diffview.EvalCase{Input: input, Story: &diffview.StoryClassification{ChangeType: "bugfix", Summary: after}, PromptVersion: "terse@1a2b3c4d"},
+		}
+	}
+	pairs := []diffview.CasePair{
+		pair("one", "Fix the cache", "Invalidate the cache on write"),
+		pair("two", "Add retries", "Retry failed requests"),
+	}
+
+	t.Run("shows both classifications side by side", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewCompareModel(pairs, "a.jsonl", "b.jsonl"), 120, 20)
+		frame := d.Frame()
+		assert.Contains(t, frame, "repo/one")
+		assert.Contains(t, frame, "A: a.jsonl (prompt v1)")
+		assert.Contains(t, frame, "B: b.jsonl (prompt terse@1a2b3c4d)")
+		assert.Regexp(t, `change_type: bugfix\s+│ change_type: bugfix`, frame)
+		assert.Regexp(t, `summary:     Fix the cache\s+│ summary:     Invalidate the cache on write`, frame)
+		assert.Contains(t, frame, "case 1/2")
+	})
+
+	t.Run("steps through the cases", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewCompareModel(pairs, "a.jsonl", "b.jsonl"), 120, 20)
+		require.NoError(t, d.Press("n"))
+		assert.Contains(t, d.Frame(), "repo/two")
+		assert.Contains(t, d.Frame(), "Retry failed requests")
+		require.NoError(t, d.Press("n"))
+		assert.Contains(t, d.Frame(), "case 2/2")
+		require.NoError(t, d.Press("N"))
+		assert.Contains(t, d.Frame(), "repo/one")
+	})
+
+	t.Run("lists changed summaries, titles and moved hunks first", func(t *testing.T) {
+		t.Parallel()
+
+		input := diffview.ClassificationInput{Repo: "repo", Branch: "moved"}
+		section := func(title string, refs ...diffview.HunkRef) diffview.Section {
+			return diffview.Section{Title: title, Hunks: refs}
+		}
+		moved := diffview.CasePair{
+			A: diffview.EvalCase{Input: input, Story: &diffview.StoryClassification{Summary: "Fix", Sections: []diffview.Section{
+				section("The fix", diffview.HunkRef{File: "a.go"}, diffview.HunkRef{File: "a.go", HunkIndex: 1}),
+			}}},
+			B: diffview.EvalCase{Input: input, Story: &diffview.StoryClassification{Summary: "Fix it", Sections: []diffview.Section{
+				section("Core fix", diffview.HunkRef{File: "a.go"}),
+				section("Cleanup", diffview.HunkRef{File: "a.go", HunkIndex: 1}),
+			}}},
+		}
+
+		d := bubbletea.NewDriver(bubbletea.NewCompareModel([]diffview.CasePair{moved}, "a.jsonl", "b.jsonl"), 120, 30)
+		frame := d.Frame()
+		assert.Contains(t, frame, "summary changed")
+		assert.Contains(t, frame, `section 1 retitled: "The fix" → "Core fix"`)
+		assert.Contains(t, frame, `a.go:H1 moved: "The fix" → "Cleanup"`)
+	})
+
+	t.Run("quits on q", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewCompareModel(pairs, "a.jsonl", "b.jsonl"), 120, 20)
+		require.NoError(t, d.Press("q"))
+		assert.True(t, d.Done())
+	})
+}
@@ -0,0 +1,64 @@
+package bubbletea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// DiffComponent is the diff viewer as a part of another bubbletea program,
+// such as a pane of a larger TUI. It takes the same options as Model, but
+// is sized by its parent with SetSize rather than by the terminal, and
+// leaves q and ctrl+c to the parent rather than quitting.
+//
+// The parent passes it the messages its program receives, keys among them
+// while it has focus, and renders its View where it's placed:
+//
+//	diff := bubbletea.NewDiffComponent(d, bubbletea.WithTheme(theme))
+//	diff.SetSize(width/2, height)
+//	...
+//	model, cmd := diff.Update(msg)
+//	diff = model.(bubbletea.DiffComponent)
+type DiffComponent struct {
+	model Model
+}
+
+// NewDiffComponent returns a component showing diff, configured by opts.
+// It renders nothing useful until sized with SetSize.
+func NewDiffComponent(diff *diffview.Diff, opts ...ModelOption) DiffComponent {
+	m := NewModel(diff, opts...)
+	m.embedded = true
+	return DiffComponent{model: m}
+}
+
+// SetSize sets the width and height the component renders in, its status
+// bar included.
+func (c *DiffComponent) SetSize(width, height int) {
+	model, _ := c.model.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	c.model = model.(Model)
+}
+
+// Init implements tea.Model.
+func (c DiffComponent) Init() tea.Cmd {
+	return c.model.Init()
+}
+
+// Update implements tea.Model. Window size messages are ignored, since the
+// parent decides the component's size.
+func (c DiffComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		return c, nil
+	}
+	model, cmd := c.model.Update(msg)
+	c.model = model.(Model)
+	return c, cmd
+}
+
+// View implements tea.Model.
+func (c DiffComponent) View() string {
+	return c.model.View()
+}
+
+// Position returns where the view stands in the diff.
+func (c DiffComponent) Position() Position {
+	return c.model.Position()
+}
@@ -0,0 +1,141 @@
+package bubbletea_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffComponent_RendersAtSetSize(t *testing.T) {
+	t.Parallel()
+
+	c := bubbletea.NewDiffComponent(multiFileDiff("a.go", "b.go"))
+	c.SetSize(50, 8)
+
+	lines := strings.Split(ansi.Strip(c.View()), "\n")
+	assert.Len(t, lines, 8)
+	for _, line := range lines {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 50)
+	}
+}
+
+func TestDiffComponent_IgnoresWindowSize(t *testing.T) {
+	t.Parallel()
+
+	c := bubbletea.NewDiffComponent(multiFileDiff("a.go", "b.go"))
+	c.SetSize(50, 8)
+
+	// The parent's terminal resizing doesn't resize the component
+	model, cmd := c.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	assert.Nil(t, cmd)
+	assert.Len(t, strings.Split(ansi.Strip(model.View()), "\n"), 8)
+}
+
+func TestDiffComponent_LeavesQuittingToParent(t *testing.T) {
+	t.Parallel()
+
+	c := bubbletea.NewDiffComponent(multiFileDiff("a.go"))
+	c.SetSize(80, 10)
+
+	for _, k := range []tea.KeyMsg{typeText("q"), {Type: tea.KeyCtrlC}} {
+		_, cmd := c.Update(k)
+		assert.Nil(t, cmd)
+	}
+}
+
+func TestDiffComponent_Navigates(t *testing.T) {
+	t.Parallel()
+
+	var c tea.Model = bubbletea.NewDiffComponent(multiFileDiff("a.go", "b.go", "c.go"))
+	component := c.(bubbletea.DiffComponent)
+	component.SetSize(80, 10)
+
+	c = sendKeys(t, component, typeText("]"), typeText("]"))
+
+	pos := c.(bubbletea.DiffComponent).Position()
+	require.Equal(t, 3, pos.Files)
+	assert.Equal(t, 3, pos.File)
+	assert.Contains(t, ansi.Strip(c.View()), "line 1 of c.go")
+}
+
+func TestModel_InitialFocus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("opens at the hunk", func(t *testing.T) {
+		t.Parallel()
+
+		m := bubbletea.NewModel(multiFileDiff("a.go", "b.go", "c.go"),
+			bubbletea.WithInitialFocus(diffview.HunkRef{File: "b.go", HunkIndex: 0}))
+		d := bubbletea.NewDriver(m, 80, 8)
+
+		pos, _ := d.Position()
+		assert.Equal(t, 2, pos.File)
+		assert.Equal(t, 2, pos.Hunk)
+		assert.Contains(t, d.Frame(), "line 1 of b.go")
+	})
+
+	t.Run("opens at the file without such a hunk", func(t *testing.T) {
+		t.Parallel()
+
+		m := bubbletea.NewModel(multiFileDiff("a.go", "b.go", "c.go"),
+			bubbletea.WithInitialFocus(diffview.HunkRef{File: "c.go", HunkIndex: 5}))
+		d := bubbletea.NewDriver(m, 80, 8)
+
+		pos, _ := d.Position()
+		assert.Equal(t, 3, pos.File)
+	})
+
+	t.Run("stays at the top for a file not in the diff", func(t *testing.T) {
+		t.Parallel()
+
+		m := bubbletea.NewModel(multiFileDiff("a.go", "b.go"),
+			bubbletea.WithInitialFocus(diffview.HunkRef{File: "gone.go"}))
+		d := bubbletea.NewDriver(m, 80, 8)
+
+		pos, _ := d.Position()
+		assert.Equal(t, 0, pos.Line)
+	})
+}
+
+func TestModel_OnQuitReportsFinalState(t *testing.T) {
+	t.Parallel()
+
+	var got *bubbletea.QuitState
+	m := bubbletea.NewModel(multiFileDiff("a.go", "b.go", "c.go"),
+		bubbletea.WithOnQuit(func(s bubbletea.QuitState) { got = &s }))
+	d := bubbletea.NewDriver(m, 80, 8)
+
+	// Collapse the first hunk by clicking its header, then move to c.go
+	click(d, 5, 1)
+	require.NoError(t, d.Press("]", "]"))
+	require.Nil(t, got)
+	require.NoError(t, d.Press("q"))
+
+	require.NotNil(t, got)
+	assert.Equal(t, 3, got.Position.File)
+	assert.Equal(t, []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}, got.Collapsed)
+}
+
+func TestModel_StatusSegments(t *testing.T) {
+	t.Parallel()
+
+	approved := func(pos bubbletea.Position) string {
+		return fmt.Sprintf("approved %d/%d", pos.File-1, pos.Files)
+	}
+	hidden := func(bubbletea.Position) string { return "" }
+	m := bubbletea.NewModel(multiFileDiff("a.go", "b.go"), bubbletea.WithStatusSegments(approved, hidden))
+	d := bubbletea.NewDriver(m, 100, 8)
+
+	assert.Contains(t, statusBar(d), "approved 0/2 │ file 1/2")
+
+	require.NoError(t, d.Press("]"))
+	assert.Contains(t, statusBar(d), "approved 1/2 │ file 2/2")
+}
@@ -0,0 +1,92 @@
+package bubbletea
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fwojciec/diffstory"
+)
+
+// critiqueTemplate returns the critique outline offered when category is
+// chosen, for the reviewer to fill in, or "" for none chosen.
+func critiqueTemplate(category diffview.CritiqueCategory) string {
+	switch category {
+	case diffview.CategoryWrongNarrative:
+		return "The story says ... but the change actually ..."
+	case diffview.CategoryMissingHunk:
+		return "Hunk <file>:H<n> is in no section; it belongs in ..."
+	case diffview.CategoryBadGrouping:
+		return "Section ... mixes ... and ...; they should be ..."
+	case diffview.CategoryHallucination:
+		return "The story mentions ..., which the diff doesn't contain."
+	}
+	return ""
+}
+
+// applyCritiqueTemplate fills the critique with the chosen category's
+// template, unless the reviewer has already written something of their own.
+func (m *EvalModel) applyCritiqueTemplate() {
+	current := m.critiqueTextarea.Value()
+	if current != "" && !isCritiqueTemplate(current) {
+		return
+	}
+	m.critiqueTextarea.SetValue(critiqueTemplate(m.critiqueCategory))
+}
+
+// isCritiqueTemplate reports whether text is one of the templates, unedited.
+func isCritiqueTemplate(text string) bool {
+	for _, c := range diffview.CritiqueCategories() {
+		if text == critiqueTemplate(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOption returns the option after current, wrapping through the zero
+// value, which stands for none chosen.
+func nextOption[T comparable](options []T, current T) T {
+	var none T
+	if current == none {
+		return options[0]
+	}
+	for i, o := range options {
+		if o == current && i+1 < len(options) {
+			return options[i+1]
+		}
+	}
+	return none
+}
+
+// critiqueLabel describes j's category and severity, such as
+// "bad grouping, major", or returns "" if neither is set.
+func critiqueLabel(j diffview.Judgment) string {
+	var parts []string
+	if j.Category != "" {
+		parts = append(parts, j.Category.Label())
+	}
+	if j.Severity != "" {
+		parts = append(parts, string(j.Severity))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderChoices renders a labeled row of options with the chosen one
+// bracketed and bold, starting with "none".
+func renderChoices[T comparable](name string, options []T, chosen T, label func(T) string) string {
+	chosenStyle := lipgloss.NewStyle().Bold(true)
+	otherStyle := lipgloss.NewStyle().Faint(true)
+
+	var none T
+	render := func(text string, selected bool) string {
+		if selected {
+			return chosenStyle.Render("[" + text + "]")
+		}
+		return otherStyle.Render(text)
+	}
+	items := []string{render("none", chosen == none)}
+	for _, o := range options {
+		items = append(items, render(label(o), o == chosen))
+	}
+	return lipgloss.NewStyle().Bold(true).Render(name) + "  " + strings.Join(items, "  ")
+}
@@ -0,0 +1,178 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// debugPanel is an overlay explaining how the diff was parsed and rendered,
+// for working out why a diff looks wrong. The report is built when the
+// panel opens, so its render timings describe that moment.
+type debugPanel struct {
+	active   bool
+	viewport viewport.Model
+}
+
+// newDebugPanel returns an open panel reporting on cfg, sized width × height.
+func newDebugPanel(cfg renderConfig, width, height int) debugPanel {
+	return newTextPanel(debugReport(cfg), width, height)
+}
+
+// newTextPanel returns an open panel showing content, sized width × height.
+// The annotation details overlay reuses the debug panel this way.
+func newTextPanel(content string, width, height int) debugPanel {
+	vp := viewport.New(width, height)
+	vp.SetContent(content)
+	return debugPanel{active: true, viewport: vp}
+}
+
+// update handles a key press while the panel is open: esc, q or the debug
+// key close it and other keys scroll it.
+func (d *debugPanel) update(msg tea.KeyMsg, toggle key.Binding) {
+	if msg.Type == tea.KeyEsc || msg.String() == "q" || key.Matches(msg, toggle) {
+		d.active = false
+		return
+	}
+	d.viewport, _ = d.viewport.Update(msg)
+}
+
+// resize fits the panel to a new content area.
+func (d *debugPanel) resize(width, height int) {
+	d.viewport.Width = width
+	d.viewport.Height = height
+}
+
+// debugReport describes what the parser and renderer made of cfg's diff:
+// parse warnings, content a lenient parse skipped, files left out, files
+// without syntax highlighting, hunks where word diff didn't apply, and how
+// long each file took to render.
+func debugReport(cfg renderConfig) string {
+	var sb strings.Builder
+	section := func(title string, lines []string) {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(title + "\n")
+		if len(lines) == 0 {
+			lines = []string{"none"}
+		}
+		for _, line := range lines {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	diff := cfg.diff
+	if diff == nil {
+		diff = &diffview.Diff{}
+	}
+
+	section("Parse warnings", diff.Warnings)
+	var skipped []string
+	for _, s := range diff.Skipped {
+		skipped = append(skipped, s.String())
+	}
+	section("Skipped content", skipped)
+
+	var hidden, undetected, wordDiff []string
+	if cfg.wordDiffer == nil {
+		wordDiff = append(wordDiff, "word diff is off")
+	}
+	for _, file := range diff.Files {
+		path := file.Path()
+		if !file.Visible() {
+			reason := "mode change only"
+			if file.IsBinary {
+				reason = "binary"
+			}
+			hidden = append(hidden, path+": "+reason)
+			continue
+		}
+		if cfg.languageDetector != nil && diffview.DetectLanguage(cfg.languageDetector, file) == "" {
+			undetected = append(undetected, path)
+		}
+		if cfg.wordDiffer == nil {
+			continue
+		}
+		for i, hunk := range file.Hunks {
+			if reason := wordDiffSkipped(hunk.Lines, cfg.wordDiffer); reason != "" {
+				wordDiff = append(wordDiff, fmt.Sprintf("%s hunk %d: %s", path, i+1, reason))
+			}
+		}
+	}
+	section("Files not shown", hidden)
+	section("Language not detected", undetected)
+	section("Word diff skipped", wordDiff)
+	section("Render time", renderTimings(cfg, diff))
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// wordDiffSkipped explains which changed lines of a hunk got no word-level
+// highlighting, or returns "" if they all did. Hunks that only add or only
+// delete lines have nothing to compare and aren't reported.
+func wordDiffSkipped(lines []diffview.Line, wordDiffer diffview.WordDiffer) string {
+	var added, deleted bool
+	for _, line := range lines {
+		added = added || line.Type == diffview.LineAdded
+		deleted = deleted || line.Type == diffview.LineDeleted
+	}
+	if !added || !deleted {
+		return ""
+	}
+
+	pairs, unpaired := linePairs(lines)
+	segments := computeLinePairSegments(lines, wordDiffer)
+	dissimilar := 0
+	for _, pair := range pairs {
+		if segments[pair[0]] == nil {
+			dissimilar++
+		}
+	}
+
+	var reasons []string
+	if dissimilar > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d line pair(s) under 30%% unchanged", dissimilar))
+	}
+	if unpaired > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d line(s) with no counterpart", unpaired))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// renderTimings renders each shown file on its own and reports how long it
+// took, followed by the total.
+func renderTimings(cfg renderConfig, diff *diffview.Diff) []string {
+	var paths []string
+	var took []time.Duration
+	var total time.Duration
+	for _, file := range diff.Files {
+		if !file.Visible() {
+			continue
+		}
+		one := cfg
+		one.diff = &diffview.Diff{Files: []diffview.FileDiff{file}}
+		start := time.Now()
+		renderDiffLayout(one)
+		elapsed := time.Since(start)
+
+		paths = append(paths, file.Path())
+		took = append(took, elapsed)
+		total += elapsed
+	}
+
+	width := len("total")
+	for _, path := range paths {
+		width = max(width, len(path))
+	}
+	lines := make([]string, 0, len(paths)+1)
+	for i, path := range paths {
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, path, took[i].Round(time.Microsecond)))
+	}
+	return append(lines, fmt.Sprintf("%-*s  %s", width, "total", total.Round(time.Microsecond)))
+}
@@ -0,0 +1,90 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_DebugPanel(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("main.go", "notes.xyz")
+	diff.Warnings = []string{"normalized CRLF line endings to LF"}
+	diff.Files = append(diff.Files,
+		diffview.FileDiff{NewPath: "logo.png", Operation: diffview.FileModified, IsBinary: true},
+		diffview.FileDiff{
+			OldPath:   "util.go",
+			NewPath:   "util.go",
+			Operation: diffview.FileModified,
+			Hunks: []diffview.Hunk{{Lines: []diffview.Line{
+				{Type: diffview.LineDeleted, Content: "old\n"},
+				{Type: diffview.LineAdded, Content: "new\n"},
+				{Type: diffview.LineAdded, Content: "extra\n"},
+			}}},
+		},
+	)
+	detector := &mock.LanguageDetector{
+		DetectFromPathFn: func(path string) string {
+			if path == "notes.xyz" {
+				return ""
+			}
+			return "Go"
+		},
+		DetectFromContentFn: func(content string) string { return "" },
+	}
+	// Every pair is reported as entirely changed
+	wordDiffer := &mockWordDiffer{DiffFn: func(old, new string) ([]diffview.Segment, []diffview.Segment) {
+		return []diffview.Segment{{Text: old, Changed: true}}, []diffview.Segment{{Text: new, Changed: true}}
+	}}
+
+	var model tea.Model = bubbletea.NewModel(diff,
+		bubbletea.WithLanguageDetector(detector),
+		bubbletea.WithWordDiffer(wordDiffer))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	model = sendKeys(t, model, typeText("D"))
+	view := model.View()
+	assert.Contains(t, view, "normalized CRLF line endings to LF")
+	assert.Contains(t, view, "logo.png: binary")
+	assert.Contains(t, view, "notes.xyz")
+	assert.Contains(t, view, "util.go hunk 1: 1 line pair(s) under 30% unchanged, 1 line(s) with no counterpart")
+	assert.Contains(t, view, "Render time")
+	assert.NotContains(t, view, "line 1 of main.go")
+
+	// Keys other than the closing ones leave the panel open
+	model = sendKeys(t, model, typeText("j"))
+	assert.Contains(t, model.View(), "Render time")
+
+	model = sendKeys(t, model, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Contains(t, model.View(), "line 1 of main.go")
+}
+
+func TestStoryModel_DebugPanelCoversWholeDiff(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("a.go", "b.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Title: "First", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}},
+			{Title: "Second", Hunks: []diffview.HunkRef{{File: "b.go", HunkIndex: 0}}},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+
+	model = sendKeys(t, model, typeText("D"))
+	view := model.View()
+	assert.Contains(t, view, "Parse warnings")
+	assert.Contains(t, view, "word diff is off")
+	assert.Contains(t, view, "a.go")
+	assert.Contains(t, view, "b.go")
+
+	model = sendKeys(t, model, typeText("D"))
+	assert.NotContains(t, model.View(), "Parse warnings")
+}
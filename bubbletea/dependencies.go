@@ -0,0 +1,117 @@
+package bubbletea
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// summarizeDependencies shows the manifests and lockfiles of diff as the
+// dependency changes the summarizer finds in them, except those the reader
+// switched back to lines. Files it finds no changes in keep their lines,
+// so one it can't make sense of still reads.
+func (m *Model) summarizeDependencies(diff *diffview.Diff) *diffview.Diff {
+	m.summarized = nil
+	if m.summarizer == nil || diff == nil {
+		return diff
+	}
+	summarized := make(map[string]diffview.FileDiff)
+	out := *diff
+	out.Files = slices.Clone(diff.Files)
+	for i, file := range out.Files {
+		path := file.Path()
+		if m.expanded[path] {
+			continue
+		}
+		changes, ok := m.summarizer.SummarizeDependencies(file)
+		if !ok || len(changes) == 0 {
+			continue
+		}
+		summarized[path] = file
+		out.Files[i] = dependencyFile(file, changes)
+	}
+	if len(summarized) == 0 {
+		return diff
+	}
+	m.summarized = summarized
+	return &out
+}
+
+// toggleDependencies switches a dependency file between its dependency
+// changes and its line diff, reporting false for files that aren't shown
+// summarized and weren't before.
+func (m *Model) toggleDependencies(file diffview.FileDiff) (diffview.FileDiff, bool) {
+	path := file.Path()
+	if lines, ok := m.summarized[path]; ok {
+		m.summarized = maps.Clone(m.summarized)
+		delete(m.summarized, path)
+		m.expanded = maps.Clone(m.expanded)
+		if m.expanded == nil {
+			m.expanded = make(map[string]bool)
+		}
+		m.expanded[path] = true
+		return lines, true
+	}
+	if !m.expanded[path] {
+		return file, false
+	}
+	changes, _ := m.summarizer.SummarizeDependencies(file)
+	m.expanded = maps.Clone(m.expanded)
+	delete(m.expanded, path)
+	m.summarized = maps.Clone(m.summarized)
+	if m.summarized == nil {
+		m.summarized = make(map[string]diffview.FileDiff)
+	}
+	m.summarized[path] = file
+	return dependencyFile(file, changes), true
+}
+
+// dependencyFile returns file with its hunks replaced by one listing
+// changes: an added or removed line for each dependency added or removed,
+// and an unchanged line with an arrow between the versions for each
+// upgraded or downgraded, with a summary of them as its section.
+func dependencyFile(file diffview.FileDiff, changes []diffview.DependencyChange) diffview.FileDiff {
+	var hunk diffview.Hunk
+	counts := make(map[diffview.DependencyOp]int)
+	for _, c := range changes {
+		var line diffview.Line
+		switch c.Op {
+		case diffview.DependencyUpgraded:
+			line = diffview.Line{Type: diffview.LineContext, Content: fmt.Sprintf("↑ %s %s → %s", c.Name, c.Old, c.New)}
+		case diffview.DependencyDowngraded:
+			line = diffview.Line{Type: diffview.LineContext, Content: fmt.Sprintf("↓ %s %s → %s", c.Name, c.Old, c.New)}
+		case diffview.DependencyAdded:
+			line = diffview.Line{Type: diffview.LineAdded, Content: c.Name + " " + c.New}
+		case diffview.DependencyRemoved:
+			line = diffview.Line{Type: diffview.LineDeleted, Content: c.Name + " " + c.Old}
+		}
+		hunk.Lines = append(hunk.Lines, line)
+		counts[c.Op]++
+	}
+	var summary []string
+	for _, part := range []struct {
+		op   diffview.DependencyOp
+		verb string
+	}{
+		{diffview.DependencyUpgraded, "upgraded"},
+		{diffview.DependencyDowngraded, "downgraded"},
+		{diffview.DependencyAdded, "added"},
+		{diffview.DependencyRemoved, "removed"},
+	} {
+		if n := counts[part.op]; n > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", n, part.verb))
+		}
+	}
+	hunk.Section = strings.Join(summary, ", ")
+	file.Hunks = []diffview.Hunk{hunk}
+	return file
+}
+
+// formatDependencyHeader formats the header of a hunk from dependencyFile,
+// which has no line ranges to show.
+func formatDependencyHeader(hunk diffview.Hunk) string {
+	return "@@ dependencies @@ " + hunk.Section
+}
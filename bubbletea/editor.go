@@ -0,0 +1,108 @@
+package bubbletea
+
+import (
+	"fmt"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// EditorFunc returns the command that opens path at line in an editor.
+type EditorFunc func(path string, line int) *exec.Cmd
+
+// QuickfixLocation is a changed place in a file, for an editor's quickfix
+// list.
+type QuickfixLocation struct {
+	Path string
+	Line int    // line in the new version of the file
+	Text string // what changed there, such as the hunk's enclosing function
+}
+
+// QuickfixFunc returns the command that replaces the quickfix list of a
+// running editor, such as Neovim listening on a socket, with locations
+// under title.
+type QuickfixFunc func(title string, locations []QuickfixLocation) *exec.Cmd
+
+// editorClosedMsg reports that the editor exited and the program resumed.
+type editorClosedMsg struct{}
+
+// openInEditor returns a command that opens the source line shown at or
+// below row. An editor in the terminal suspends the program until it exits;
+// a remote one, already running elsewhere, is sent the line while the
+// program keeps running. Returns nil if no editor is configured or there is
+// no line to open.
+func openInEditor(open EditorFunc, remote bool, layout diffLayout, row int) tea.Cmd {
+	if open == nil {
+		return nil
+	}
+	src, ok := layout.sourceAt(row)
+	if !ok {
+		return nil
+	}
+	cmd := open(src.path, src.line)
+	if remote {
+		return func() tea.Msg {
+			return toastResult(fmt.Sprintf("opened %s:%d in editor", src.path, src.line), "opening in editor", cmd.Run())
+		}
+	}
+	return tea.ExecProcess(cmd, func(error) tea.Msg {
+		return editorClosedMsg{}
+	})
+}
+
+// sendQuickfix returns a command that sends the changed places of section
+// to the editor's quickfix list: for each of its hunks, the first line in
+// the new version the hunk adds or changes. Returns nil if there's no
+// quickfix command or no place to send.
+func sendQuickfix(quickfix QuickfixFunc, diff *diffview.Diff, section diffview.Section) tea.Cmd {
+	if quickfix == nil || diff == nil {
+		return nil
+	}
+	hunks := make(map[diffview.HunkRef]diffview.Hunk)
+	for _, file := range diff.Files {
+		if file.Operation == diffview.FileDeleted {
+			continue
+		}
+		for i, h := range file.Hunks {
+			hunks[diffview.HunkRef{File: file.Path(), HunkIndex: i}] = h
+		}
+	}
+	var locations []QuickfixLocation
+	for _, ref := range section.Hunks {
+		hunk, ok := hunks[diffview.HunkRef{File: ref.File, HunkIndex: ref.HunkIndex}]
+		if !ok {
+			continue
+		}
+		text := hunk.Section
+		if text == "" {
+			text = section.Title
+		}
+		locations = append(locations, QuickfixLocation{Path: ref.File, Line: changedLine(hunk), Text: text})
+	}
+	if len(locations) == 0 {
+		return nil
+	}
+	cmd := quickfix(section.Title, locations)
+	return func() tea.Msg {
+		return toastResult(fmt.Sprintf("sent %d location(s) to the quickfix list", len(locations)), "sending quickfix list", cmd.Run())
+	}
+}
+
+// changedLine returns the line in the new version where hunk's changes
+// start: its first added line, or for a hunk that only deletes, the line
+// after the deletion.
+func changedLine(hunk diffview.Hunk) int {
+	next := hunk.NewStart
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case diffview.LineAdded:
+			return line.NewLineNum
+		case diffview.LineDeleted:
+			return max(next, 1)
+		case diffview.LineContext:
+			next = line.NewLineNum + 1
+		}
+	}
+	return max(hunk.NewStart, 1)
+}
@@ -0,0 +1,243 @@
+package bubbletea_test
+
+import (
+	"os/exec"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// editorSpy records the file and line an EditorFunc was asked to open.
+type editorSpy struct {
+	path  string
+	line  int
+	calls int
+}
+
+func (s *editorSpy) open(path string, line int) *exec.Cmd {
+	s.path, s.line = path, line
+	s.calls++
+	return exec.Command("true")
+}
+
+func TestModel_OpenEditorAtTopVisibleLine(t *testing.T) {
+	t.Parallel()
+
+	spy := &editorSpy{}
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go", "b.go"), bubbletea.WithEditor(spy.open))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	// At the top, the file and hunk headers point at the hunk's first line
+	_, cmd := model.Update(typeText("e"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, "a.go", spy.path)
+	assert.Equal(t, 1, spy.line)
+
+	// Scrolled into the second file's hunk
+	model = sendKeys(t, model, typeText("]"), typeText("j"), typeText("j"), typeText("j"))
+	_, cmd = model.Update(typeText("e"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, "b.go", spy.path)
+	assert.Equal(t, 2, spy.line)
+}
+
+func TestModel_OpenEditorMapsDeletedLinesToNewFile(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/main.go",
+				NewPath:   "b/main.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 10, OldCount: 3, NewStart: 10, NewCount: 2,
+						Lines: []diffview.Line{
+							{Type: diffview.LineContext, Content: "keep\n", OldLineNum: 10, NewLineNum: 10},
+							{Type: diffview.LineDeleted, Content: "gone\n", OldLineNum: 11},
+							{Type: diffview.LineContext, Content: "after\n", OldLineNum: 12, NewLineNum: 11},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	spy := &editorSpy{}
+	var model tea.Model = bubbletea.NewModel(diff, bubbletea.WithEditor(spy.open))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 3})
+
+	// Rows: file header, hunk header, keep, gone; scroll so "gone" is on top
+	model = sendKeys(t, model, typeText("j"), typeText("j"), typeText("j"))
+	model.Update(typeText("e"))
+
+	assert.Equal(t, "main.go", spy.path)
+	assert.Equal(t, 11, spy.line)
+}
+
+func TestModel_OpenEditorWithoutEditorDoesNothing(t *testing.T) {
+	t.Parallel()
+
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go"))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("e"))
+
+	assert.Nil(t, cmd)
+}
+
+func TestModel_OpenEditorSkipsDeletedFiles(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/old.go",
+				NewPath:   "/dev/null",
+				Operation: diffview.FileDeleted,
+				Hunks: []diffview.Hunk{
+					{OldStart: 1, OldCount: 1, Lines: []diffview.Line{{Type: diffview.LineDeleted, Content: "x\n", OldLineNum: 1}}},
+				},
+			},
+		},
+	}
+
+	spy := &editorSpy{}
+	var model tea.Model = bubbletea.NewModel(diff, bubbletea.WithEditor(spy.open))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("e"))
+
+	assert.Nil(t, cmd)
+	assert.Zero(t, spy.calls)
+}
+
+func TestStoryModel_OpenEditorUsesO(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("core.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Core", Hunks: []diffview.HunkRef{{File: "core.go", HunkIndex: 0}}},
+		},
+	}
+
+	spy := &editorSpy{}
+	var model tea.Model = bubbletea.NewStoryModel(diff, story, bubbletea.WithStoryEditor(spy.open))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	model = sendKeys(t, model, typeText("j"), typeText("j"))
+	_, cmd := model.Update(typeText("o"))
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "core.go", spy.path)
+	assert.Equal(t, 1, spy.line)
+}
+
+func TestModel_RemoteEditorKeepsViewerRunning(t *testing.T) {
+	t.Parallel()
+	skipWithoutUnixCommands(t)
+
+	var gotPath string
+	var gotLine int
+	remote := func(path string, line int) *exec.Cmd {
+		gotPath, gotLine = path, line
+		return exec.Command("true")
+	}
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go"), bubbletea.WithRemoteEditor(remote))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("e"))
+
+	require.NotNil(t, cmd)
+	// The command is sent rather than run in the terminal, reporting back
+	assert.Equal(t, bubbletea.ToastMsg{Text: "opened a.go:1 in editor"}, cmd())
+	assert.Equal(t, "a.go", gotPath)
+	assert.Equal(t, 1, gotLine)
+}
+
+func TestModel_RemoteEditorReportsFailure(t *testing.T) {
+	t.Parallel()
+	skipWithoutUnixCommands(t)
+
+	remote := func(path string, line int) *exec.Cmd { return exec.Command("false") }
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go"), bubbletea.WithRemoteEditor(remote))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("e"))
+
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(bubbletea.ToastMsg)
+	require.True(t, ok)
+	assert.True(t, msg.Err)
+	assert.Contains(t, msg.Text, "opening in editor")
+}
+
+// quickfixSpy records what a QuickfixFunc was asked to send.
+type quickfixSpy struct {
+	title     string
+	locations []bubbletea.QuickfixLocation
+}
+
+func (s *quickfixSpy) send(title string, locations []bubbletea.QuickfixLocation) *exec.Cmd {
+	s.title, s.locations = title, locations
+	return exec.Command("true")
+}
+
+func TestStoryModel_QuickfixSendsSectionChanges(t *testing.T) {
+	t.Parallel()
+	skipWithoutUnixCommands(t)
+
+	diff := multiFileDiff("core.go", "util.go", "core_test.go")
+	diff.Files[1].Hunks[0] = diffview.Hunk{
+		OldStart: 5, OldCount: 3, NewStart: 5, NewCount: 2, Section: "func helper()",
+		Lines: []diffview.Line{
+			{Type: diffview.LineContext, Content: "a\n", OldLineNum: 5, NewLineNum: 5},
+			{Type: diffview.LineDeleted, Content: "b\n", OldLineNum: 6},
+			{Type: diffview.LineContext, Content: "c\n", OldLineNum: 7, NewLineNum: 6},
+		},
+	}
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Core change", Hunks: []diffview.HunkRef{
+				{File: "core.go", HunkIndex: 0},
+				{File: "util.go", HunkIndex: 0},
+				{File: "missing.go", HunkIndex: 0},
+			}},
+			{Role: "test", Title: "Tests", Hunks: []diffview.HunkRef{{File: "core_test.go", HunkIndex: 0}}},
+		},
+	}
+	spy := &quickfixSpy{}
+	var model tea.Model = bubbletea.NewStoryModel(diff, story, bubbletea.WithStoryQuickfix(spy.send))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("O"))
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, bubbletea.ToastMsg{Text: "sent 2 location(s) to the quickfix list"}, cmd())
+	assert.Equal(t, "Core change", spy.title)
+	assert.Equal(t, []bubbletea.QuickfixLocation{
+		{Path: "core.go", Line: 1, Text: "Core change"},
+		{Path: "util.go", Line: 6, Text: "func helper()"},
+	}, spy.locations)
+}
+
+func TestStoryModel_QuickfixWithoutCommandDoesNothing(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("core.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{{Role: "core", Title: "Core", Hunks: []diffview.HunkRef{{File: "core.go", HunkIndex: 0}}}},
+	}
+	var model tea.Model = bubbletea.NewStoryModel(diff, story)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("O"))
+
+	assert.Nil(t, cmd)
+}
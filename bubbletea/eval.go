@@ -2,6 +2,8 @@ package bubbletea
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -22,6 +24,9 @@ const (
 	ModeReview Mode = iota
 	ModeCritique
 	ModeHelp
+	ModeConfirmDelete
+	ModeEdit
+	ModeFilter
 )
 
 // ViewMode identifies which view is active: story or data.
@@ -33,18 +38,38 @@ const (
 	ViewData
 )
 
+// evalPane identifies a pane of the story view.
+type evalPane int
+
+// Panes of the story view.
+const (
+	paneDiff evalPane = iota
+	paneStory
+)
+
+// sideBySideMinWidth is the narrowest terminal on which the story pane can
+// sit left of the diff rather than above it.
+const sideBySideMinWidth = 120
+
 // EvalModel is the Bubble Tea model for evaluating diff stories.
 type EvalModel struct {
 	// Data
 	cases        []diffview.EvalCase
 	judgments    map[string]*diffview.Judgment
 	currentIndex int
+	filter       caseFilter // applied to the cases the reviewer steps through
+	filterDraft  caseFilter // being changed in the filter overlay
+	shown        []int      // indices of the cases the filter kept, in order, or nil for all
 
 	// UI Components
 	diffViewport     viewport.Model
 	storyViewport    viewport.Model
 	dataViewport     viewport.Model
 	critiqueTextarea textarea.Model
+	critiqueCategory diffview.CritiqueCategory // chosen in critique mode
+	critiqueSeverity diffview.Severity         // chosen in critique mode
+	editor           storyEditor               // gold label draft in edit mode
+	compareGold      bool                      // data view compares the model's story with the gold one
 
 	// State
 	mode     Mode
@@ -57,7 +82,18 @@ type EvalModel struct {
 	collapsedHunks map[hunkKey]bool   // hunk collapse state
 	hunkCategories map[hunkKey]string // hunk → category for styling
 	collapseText   map[hunkKey]string // hunk → collapse text
+	sectionNotes   map[hunkKey]string // hunk → its section's title and explanation
+	hideNotes      bool               // hide section annotations above hunks in raw mode
 	splitRatio     int                // percentage of height for metadata pane (0-100)
+	rawMode        bool               // open cases in raw mode even when they have sections
+
+	// Panes
+	focus      evalPane // pane the scroll keys act on
+	hideStory  bool     // story pane hidden, the diff taking its place
+	sideBySide bool     // story pane left of the diff, splitting the width, on wide terminals
+
+	// Case list
+	showCaseList bool // list of the cases left of the story view, on wide terminals
 
 	// Rendering
 	width, height    int
@@ -65,14 +101,41 @@ type EvalModel struct {
 	languageDetector diffview.LanguageDetector
 	tokenizer        diffview.Tokenizer
 	wordDiffer       diffview.WordDiffer
+	noWordDiff       bool // word-level highlighting turned off
+	tabWidth         int
+	cache            *renderCache
+	wrap             bool  // soft-wrap long diff lines instead of scrolling horizontally
+	xOffset          int   // diff content columns scrolled off to the left
+	diffFileRows     []int // row of each file header in the diff pane
+	finder           fileFinder
+	idle             idleLock
+	toast            toast
+	timer            reviewTimer // time each case is on screen
+	blurred          bool        // the terminal lost focus
 
 	// Persistence
 	store      diffview.JudgmentStore
 	outputPath string
+	writer     *judgmentWriter // orders saves to store, shared by copies of the model
+	saveErr    error           // why the latest save failed, until retried or dismissed
+	history    history         // judgment edits to undo and redo
+	prefs      diffview.PreferencesStore
+
+	// Deletion
+	tombstones     diffview.TombstoneStore
+	tombstonesPath string
+
+	// Gold labels
+	goldSaver diffview.EvalCaseSaver
+	goldPath  string
 
 	// Clipboard
 	clipboard diffview.Clipboard
 
+	// Read-only review of an earlier session's snapshot
+	readOnly      bool
+	readOnlyLabel string
+
 	// Keybindings
 	keymap EvalKeyMap
 }
@@ -85,6 +148,28 @@ func WithJudgmentStore(store diffview.JudgmentStore, outputPath string) EvalMode
 	return func(m *EvalModel) {
 		m.store = store
 		m.outputPath = outputPath
+		m.writer = nil
+		if store != nil && outputPath != "" {
+			m.writer = &judgmentWriter{store: store, path: outputPath}
+		}
+	}
+}
+
+// WithTombstoneStore sets the store for recording case deletions.
+// Deletion is disabled unless a tombstone store is configured.
+func WithTombstoneStore(store diffview.TombstoneStore, path string) EvalModelOption {
+	return func(m *EvalModel) {
+		m.tombstones = store
+		m.tombstonesPath = path
+	}
+}
+
+// WithGoldSaver sets where cases are recorded as their gold labels are
+// promoted. Promotion is disabled unless a gold saver is configured.
+func WithGoldSaver(s diffview.EvalCaseSaver, path string) EvalModelOption {
+	return func(m *EvalModel) {
+		m.goldSaver = s
+		m.goldPath = path
 	}
 }
 
@@ -126,6 +211,29 @@ func WithEvalWordDiffer(d diffview.WordDiffer) EvalModelOption {
 	}
 }
 
+// WithEvalTabWidth sets the tab stop interval for line content.
+func WithEvalTabWidth(n int) EvalModelOption {
+	return func(m *EvalModel) {
+		m.tabWidth = n
+	}
+}
+
+// WithEvalIdleTimeout blanks the screen after the given period without input,
+// until the next key press. Zero (the default) disables locking.
+func WithEvalIdleTimeout(d time.Duration) EvalModelOption {
+	return func(m *EvalModel) {
+		m.idle = newIdleLock(d)
+	}
+}
+
+// WithPreferencesStore restores the split ratio, default mode and word diff
+// setting from s, and saves them to it on quit.
+func WithPreferencesStore(s diffview.PreferencesStore) EvalModelOption {
+	return func(m *EvalModel) {
+		m.prefs = s
+	}
+}
+
 // WithClipboard sets the clipboard for copy operations.
 func WithClipboard(c diffview.Clipboard) EvalModelOption {
 	return func(m *EvalModel) {
@@ -133,26 +241,44 @@ func WithClipboard(c diffview.Clipboard) EvalModelOption {
 	}
 }
 
+// WithReadOnly shows the cases as a snapshot that can't be changed: judging,
+// critiquing and deleting are disabled. The label, such as the session the
+// snapshot comes from, is shown in the status bar.
+func WithReadOnly(label string) EvalModelOption {
+	return func(m *EvalModel) {
+		m.readOnly = true
+		m.readOnlyLabel = label
+	}
+}
+
 // NewEvalModel creates a new EvalModel with the given cases.
 func NewEvalModel(cases []diffview.EvalCase, opts ...EvalModelOption) EvalModel {
 	m := EvalModel{
+		cache:          newRenderCache(),
 		cases:          cases,
 		judgments:      make(map[string]*diffview.Judgment),
+		timer:          newReviewTimer(),
 		mode:           ModeReview,
 		keymap:         DefaultEvalKeyMap(),
 		styles:         defaultStyles(), // Use same defaults as viewer
 		collapsedHunks: make(map[hunkKey]bool),
 		hunkCategories: make(map[hunkKey]string),
 		collapseText:   make(map[hunkKey]string),
+		sectionNotes:   make(map[hunkKey]string),
 		splitRatio:     30, // 30% metadata, 70% diff by default
 	}
 
 	for _, opt := range opts {
 		opt(&m)
 	}
+	if m.prefs != nil {
+		if prefs, err := m.prefs.Load(); err == nil && prefs != nil {
+			m.restorePreferences(*prefs)
+		}
+	}
 
 	// Enable story mode by default if first case has sections
-	if len(cases) > 0 && cases[0].Story != nil && len(cases[0].Story.Sections) > 0 {
+	if !m.rawMode && len(cases) > 0 && cases[0].Story != nil && len(cases[0].Story.Sections) > 0 {
 		m.storyMode = true
 		m.rebuildStoryMaps()
 	}
@@ -162,119 +288,167 @@ func NewEvalModel(cases []diffview.EvalCase, opts ...EvalModelOption) EvalModel
 
 // Init implements tea.Model.
 func (m EvalModel) Init() tea.Cmd {
-	return nil
+	return m.idle.start()
 }
 
-// Update implements tea.Model.
+// Update implements tea.Model, timing the case left on screen once msg is
+// handled.
 func (m EvalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// Handlers with pointer receivers return the model by pointer
+	next, ok := model.(EvalModel)
+	if !ok {
+		next = *model.(*EvalModel)
+	}
+	next.timer.watch(next.watchedCase(), time.Now())
+	return next, cmd
+}
+
+func (m EvalModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.FocusMsg:
+		m.blurred = false
+		return m, nil
+
+	case tea.BlurMsg:
+		m.blurred = true
+		return m, nil
+
+	case idleCheckMsg:
+		return m, m.idle.check()
+
+	case ToastMsg:
+		return m, m.toast.show(msg)
+
+	case toastExpiredMsg:
+		m.toast.expire(msg)
+		return m, nil
+
+	case judgmentsSavedMsg:
+		return m, m.judgmentsSaved(msg)
+
+	case tea.MouseMsg:
+		if dismissed, cmd := m.idle.touch(); dismissed {
+			return m, cmd
+		}
+
 	case tea.KeyMsg:
+		// The key that dismisses the lock screen isn't acted on
+		if dismissed, cmd := m.idle.touch(); dismissed {
+			return m, cmd
+		}
 		switch m.mode {
 		case ModeReview:
+			if m.saveErr != nil {
+				return m.handleSaveErrorKeys(msg)
+			}
 			return m.handleReviewKeys(msg)
 		case ModeCritique:
 			return m.handleCritiqueKeys(msg)
 		case ModeHelp:
 			return m.handleHelpKeys(msg)
+		case ModeConfirmDelete:
+			return m.handleConfirmDeleteKeys(msg)
+		case ModeEdit:
+			return m.handleEditKeys(msg)
+		case ModeFilter:
+			return m.handleFilterKeys(msg)
 		}
 
 	case tea.WindowSizeMsg:
 		return m.handleWindowSize(msg)
 	}
 
-	// Update the diff viewport
+	// Update the focused viewport
 	var cmd tea.Cmd
-	m.diffViewport, cmd = m.diffViewport.Update(msg)
+	vp := m.focusedViewport()
+	*vp, cmd = vp.Update(msg)
 	return m, cmd
 }
 
 func (m EvalModel) handleReviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The finder overlay takes all keys while open
+	if m.finder.active {
+		if done, picked := m.finder.update(msg); done && picked >= 0 {
+			m.diffViewport.SetYOffset(m.diffFileRows[picked])
+		}
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, m.keymap.Quit):
+		m.savePreferences()
 		return m, tea.Quit
 
 	case key.Matches(msg, m.keymap.NextCase):
-		if m.currentIndex < len(m.cases)-1 {
-			m.currentIndex++
-			m.rebuildStoryMaps()
-			m.updateStoryModeForCase()
-			m.updateViewportContent()
+		if idx := m.step(1); idx >= 0 {
+			m.gotoCase(idx)
 		}
 		return m, nil
 
 	case key.Matches(msg, m.keymap.PrevCase):
-		if m.currentIndex > 0 {
-			m.currentIndex--
-			m.rebuildStoryMaps()
-			m.updateStoryModeForCase()
-			m.updateViewportContent()
+		if idx := m.step(-1); idx >= 0 {
+			m.gotoCase(idx)
 		}
 		return m, nil
 
 	case key.Matches(msg, m.keymap.NextUnjudged):
 		if idx := m.findNextUnjudged(); idx != -1 && idx != m.currentIndex {
-			m.currentIndex = idx
-			m.rebuildStoryMaps()
-			m.updateStoryModeForCase()
-			m.updateViewportContent()
+			m.gotoCase(idx)
 		}
 		return m, nil
 
 	case key.Matches(msg, m.keymap.PrevUnjudged):
 		if idx := m.findPrevUnjudged(); idx != -1 && idx != m.currentIndex {
-			m.currentIndex = idx
-			m.rebuildStoryMaps()
-			m.updateStoryModeForCase()
-			m.updateViewportContent()
+			m.gotoCase(idx)
 		}
 		return m, nil
 
-	case key.Matches(msg, m.keymap.ScrollDown):
-		if m.viewMode == ViewData {
-			m.dataViewport.ScrollDown(1)
-		} else {
-			m.diffViewport.ScrollDown(1)
+	case key.Matches(msg, m.keymap.Filter):
+		if len(m.cases) > 0 {
+			m.openFilter()
 		}
 		return m, nil
 
-	case key.Matches(msg, m.keymap.ScrollUp):
-		if m.viewMode == ViewData {
-			m.dataViewport.ScrollUp(1)
-		} else {
-			m.diffViewport.ScrollUp(1)
+	case key.Matches(msg, m.keymap.CaseListDown) && m.caseListShown():
+		if idx := m.step(1); idx >= 0 {
+			m.gotoCase(idx)
 		}
 		return m, nil
 
-	case key.Matches(msg, m.keymap.HalfPageUp):
-		if m.viewMode == ViewData {
-			m.dataViewport.HalfPageUp()
-		} else {
-			m.diffViewport.HalfPageUp()
+	case key.Matches(msg, m.keymap.CaseListUp) && m.caseListShown():
+		if idx := m.step(-1); idx >= 0 {
+			m.gotoCase(idx)
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keymap.ToggleCaseList):
+		m.showCaseList = !m.showCaseList
+		m.resizePanes()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.ScrollDown):
+		m.focusedViewport().ScrollDown(1)
+		return m, nil
+
+	case key.Matches(msg, m.keymap.ScrollUp):
+		m.focusedViewport().ScrollUp(1)
+		return m, nil
+
+	case key.Matches(msg, m.keymap.HalfPageUp):
+		m.focusedViewport().HalfPageUp()
+		return m, nil
+
 	case key.Matches(msg, m.keymap.HalfPageDown):
-		if m.viewMode == ViewData {
-			m.dataViewport.HalfPageDown()
-		} else {
-			m.diffViewport.HalfPageDown()
-		}
+		m.focusedViewport().HalfPageDown()
 		return m, nil
 
 	case key.Matches(msg, m.keymap.GotoTop):
-		if m.viewMode == ViewData {
-			m.dataViewport.GotoTop()
-		} else {
-			m.diffViewport.GotoTop()
-		}
+		m.focusedViewport().GotoTop()
 		return m, nil
 
 	case key.Matches(msg, m.keymap.GotoBottom):
-		if m.viewMode == ViewData {
-			m.dataViewport.GotoBottom()
-		} else {
-			m.diffViewport.GotoBottom()
-		}
+		m.focusedViewport().GotoBottom()
 		return m, nil
 
 	case key.Matches(msg, m.keymap.ToggleMode):
@@ -285,6 +459,23 @@ func (m EvalModel) handleReviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.toggleViewMode()
 		return m, nil
 
+	case key.Matches(msg, m.keymap.SwitchFocus):
+		m.switchFocus()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.ToggleStoryPane):
+		m.hideStory = !m.hideStory
+		if m.hideStory {
+			m.focus = paneDiff
+		}
+		m.resizePanes()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.ToggleSideBySide):
+		m.sideBySide = !m.sideBySide
+		m.resizePanes()
+		return m, nil
+
 	case key.Matches(msg, m.keymap.NextSection):
 		if m.storyMode {
 			m.gotoNextSection()
@@ -305,19 +496,87 @@ func (m EvalModel) handleReviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.adjustSplit(-10)
 		return m, nil
 
-	case key.Matches(msg, m.keymap.Pass):
-		m.recordJudgment(true)
+	case key.Matches(msg, m.keymap.ToggleNotes):
+		m.hideNotes = !m.hideNotes
+		m.refreshDiff()
 		return m, nil
 
-	case key.Matches(msg, m.keymap.Fail):
-		m.recordJudgment(false)
+	case key.Matches(msg, m.keymap.ToggleWordDiff):
+		m.noWordDiff = !m.noWordDiff
+		m.refreshDiff()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.ToggleWrap):
+		m.wrap = !m.wrap
+		m.xOffset = 0
+		m.refreshDiff()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.ScrollLeft):
+		m.scrollDiffHorizontal(-horizontalScrollStep)
 		return m, nil
 
+	case key.Matches(msg, m.keymap.ScrollRight):
+		m.scrollDiffHorizontal(horizontalScrollStep)
+		return m, nil
+
+	case key.Matches(msg, m.keymap.FindFile):
+		m.openFinder()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.Pass):
+		if m.readOnly {
+			return m, nil
+		}
+		return m, m.recordJudgment(true)
+
+	case key.Matches(msg, m.keymap.Fail):
+		if m.readOnly {
+			return m, nil
+		}
+		return m, m.recordJudgment(false)
+
+	case key.Matches(msg, m.keymap.Undo):
+		if m.readOnly {
+			return m, nil
+		}
+		return m, m.undo()
+
+	case key.Matches(msg, m.keymap.Redo):
+		if m.readOnly {
+			return m, nil
+		}
+		return m, m.redo()
+
 	case key.Matches(msg, m.keymap.Critique):
+		if m.readOnly {
+			return m, nil
+		}
 		return m.enterCritiqueMode()
 
+	case key.Matches(msg, m.keymap.EditStory):
+		if m.readOnly {
+			return m, nil
+		}
+		return m.enterEditMode()
+
+	case key.Matches(msg, m.keymap.PromoteGold):
+		if m.readOnly {
+			return m, nil
+		}
+		return m, m.promoteGold()
+
+	case key.Matches(msg, m.keymap.CompareGold):
+		m.toggleGoldComparison()
+		return m, nil
+
 	case key.Matches(msg, m.keymap.CopyCase):
-		m.copyCurrentCase()
+		return m, m.copyCurrentCase()
+
+	case key.Matches(msg, m.keymap.Delete):
+		if m.tombstones != nil && len(m.cases) > 0 && !m.readOnly {
+			m.mode = ModeConfirmDelete
+		}
 		return m, nil
 
 	case key.Matches(msg, m.keymap.Help):
@@ -332,6 +591,13 @@ func (m EvalModel) handleCritiqueKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keymap.ExitCritique):
 		return m.exitCritiqueMode()
+	case key.Matches(msg, m.keymap.CritiqueCategory):
+		m.critiqueCategory = nextOption(diffview.CritiqueCategories(), m.critiqueCategory)
+		m.applyCritiqueTemplate()
+		return m, nil
+	case key.Matches(msg, m.keymap.CritiqueSeverity):
+		m.critiqueSeverity = nextOption(diffview.Severities(), m.critiqueSeverity)
+		return m, nil
 	}
 
 	// Pass all other keys to textarea
@@ -340,12 +606,57 @@ func (m EvalModel) handleCritiqueKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m EvalModel) handleEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The title prompt takes all keys while open
+	if m.editor.retitling {
+		m.editor.updateTitle(msg)
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keymap.ExitEdit):
+		return m.exitEditMode()
+	case key.Matches(msg, m.keymap.ScrollDown):
+		m.editor.moveCursor(1)
+	case key.Matches(msg, m.keymap.ScrollUp):
+		m.editor.moveCursor(-1)
+	case key.Matches(msg, m.keymap.GotoTop):
+		m.editor.cursor = 0
+	case key.Matches(msg, m.keymap.GotoBottom):
+		m.editor.moveCursor(len(m.editor.rows()))
+	case key.Matches(msg, m.keymap.MoveHunkDown):
+		m.editor.moveHunk(1)
+	case key.Matches(msg, m.keymap.MoveHunkUp):
+		m.editor.moveHunk(-1)
+	case key.Matches(msg, m.keymap.CycleHunkCategory):
+		m.editor.cycleCategory()
+	case key.Matches(msg, m.keymap.RetitleSection):
+		m.editor.startRetitle()
+	case key.Matches(msg, m.keymap.AddSection):
+		m.editor.addSection()
+	case key.Matches(msg, m.keymap.ResetStory):
+		m.editor = newStoryEditor(*m.cases[m.currentIndex].Story)
+	}
+	return m, nil
+}
+
 func (m EvalModel) handleHelpKeys(_ tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Any key dismisses help
 	m.mode = ModeReview
 	return m, nil
 }
 
+func (m EvalModel) handleConfirmDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.ConfirmDelete):
+		m.mode = ModeReview
+		return m, m.deleteCurrentCase()
+	case key.Matches(msg, m.keymap.CancelDelete):
+		m.mode = ModeReview
+	}
+	return m, nil
+}
+
 func (m EvalModel) enterCritiqueMode() (tea.Model, tea.Cmd) {
 	if len(m.cases) == 0 {
 		return m, nil
@@ -356,11 +667,13 @@ func (m EvalModel) enterCritiqueMode() (tea.Model, tea.Cmd) {
 	ta.Placeholder = "Enter detailed critique..."
 	ta.ShowLineNumbers = false
 	ta.SetWidth(m.width - 4)
-	ta.SetHeight(m.height - 6)
+	ta.SetHeight(m.height - 9)
 
 	c := m.cases[m.currentIndex]
-	if j := m.judgments[c.Input.CaseID()]; j != nil && j.Critique != "" {
+	m.critiqueCategory, m.critiqueSeverity = "", ""
+	if j := m.judgments[c.CaseID()]; j != nil {
 		ta.SetValue(j.Critique)
+		m.critiqueCategory, m.critiqueSeverity = j.Category, j.Severity
 	}
 
 	ta.Focus()
@@ -372,10 +685,12 @@ func (m EvalModel) enterCritiqueMode() (tea.Model, tea.Cmd) {
 
 func (m EvalModel) exitCritiqueMode() (tea.Model, tea.Cmd) {
 	// Save critique to judgment
+	var cmd tea.Cmd
 	if len(m.cases) > 0 {
 		c := m.cases[m.currentIndex]
-		caseID := c.Input.CaseID()
+		caseID := c.CaseID()
 		critique := m.critiqueTextarea.Value()
+		before := copyJudgment(m.judgments[caseID])
 
 		// Get or create judgment
 		j := m.judgments[caseID]
@@ -387,82 +702,193 @@ func (m EvalModel) exitCritiqueMode() (tea.Model, tea.Cmd) {
 			}
 			m.judgments[caseID] = j
 		}
+		// Rewriting a grader's critique makes the judgment the reviewer's
+		if critique != j.Critique || m.critiqueCategory != j.Category || m.critiqueSeverity != j.Severity {
+			j.Judge = ""
+		}
 		j.Critique = critique
+		j.Category = m.critiqueCategory
+		j.Severity = m.critiqueSeverity
 		j.JudgedAt = time.Now()
 
-		m.persistJudgments()
+		m.recordEdit(caseID, before)
+		cmd = m.persistJudgments()
 	}
 
 	m.mode = ModeReview
-	return m, nil
+	return m, cmd
 }
 
-func (m *EvalModel) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
-	m.width = msg.Width
-	m.height = msg.Height
+// enterEditMode opens the gold label editor on the current case, starting
+// from its gold label if it has one.
+func (m EvalModel) enterEditMode() (tea.Model, tea.Cmd) {
+	if len(m.cases) == 0 || m.cases[m.currentIndex].Story == nil {
+		return m, nil
+	}
 
-	// Calculate panel heights using split ratio
-	// Reserve: DIFF header (1), STORY header (1), judgment bar (1), status bar (1) = 4
-	// Plus newlines after each viewport (2) = 6 total reserved
-	usableHeight := msg.Height - 6
-	if usableHeight < 2 {
-		usableHeight = 2 // Minimum height for tiny terminals
+	story := *m.cases[m.currentIndex].Story
+	if gold := m.goldStory(); gold != nil {
+		story = *gold
+	}
+	m.editor = newStoryEditor(story)
+	m.mode = ModeEdit
+	return m, nil
+}
+
+// exitEditMode saves the draft as the case's gold label, to be promoted. A
+// draft that matches the promoted gold label, or the model's classification
+// if there's none, clears the edits instead.
+func (m EvalModel) exitEditMode() (tea.Model, tea.Cmd) {
+	c := m.cases[m.currentIndex]
+	caseID := c.CaseID()
+	gold := m.editor.result()
+
+	unchanged := *c.Story
+	if c.Gold != nil {
+		unchanged = *c.Gold
+	}
+	j := m.judgments[caseID]
+	before := copyJudgment(j)
+	if reflect.DeepEqual(gold, newStoryEditor(unchanged).result()) {
+		m.mode = ModeReview
+		if j != nil && j.Gold != nil {
+			j.Gold = nil
+			m.recordEdit(caseID, before)
+			return m, m.persistJudgments()
+		}
+		return m, nil
 	}
-	metadataHeight := usableHeight * m.splitRatio / 100
-	diffHeight := usableHeight - metadataHeight
 
-	// Data view uses full height minus header (1), judgment bar (1), status bar (1) = 3
-	dataHeight := msg.Height - 3
-	if dataHeight < 1 {
-		dataHeight = 1
+	// Get or create judgment
+	if j == nil {
+		j = &diffview.Judgment{
+			CaseID:   caseID,
+			Index:    m.currentIndex,
+			JudgedAt: time.Now(),
+		}
+		m.judgments[caseID] = j
 	}
+	j.Gold = &gold
+	j.JudgedAt = time.Now()
+	m.recordEdit(caseID, before)
+
+	m.mode = ModeReview
+	return m, m.persistJudgments()
+}
+
+func (m *EvalModel) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	m.width = msg.Width
+	m.height = msg.Height
 
 	if !m.ready {
-		m.diffViewport = viewport.New(msg.Width, diffHeight)
-		m.storyViewport = viewport.New(msg.Width, metadataHeight)
-		m.dataViewport = viewport.New(msg.Width, dataHeight)
-		m.updateViewportContent()
+		m.diffViewport = viewport.New(0, 0)
+		m.storyViewport = viewport.New(0, 0)
+		m.dataViewport = viewport.New(0, 0)
 		m.ready = true
+		m.recalculateViewportSizes()
+		m.updateViewportContent()
 	} else {
-		m.diffViewport.Width = msg.Width
-		m.diffViewport.Height = diffHeight
-		m.storyViewport.Width = msg.Width
-		m.storyViewport.Height = metadataHeight
-		m.dataViewport.Width = msg.Width
-		m.dataViewport.Height = dataHeight
+		m.resizePanes()
 	}
 
 	return m, nil
 }
 
-func (m *EvalModel) updateViewportContent() {
-	if len(m.cases) == 0 {
-		m.diffViewport.SetContent("No cases loaded")
-		m.storyViewport.SetContent("")
-		return
-	}
-
+// renderDiffContent renders the current case's diff, filtered to the active
+// section in story mode.
+func (m EvalModel) renderDiffContent() string {
 	c := m.cases[m.currentIndex]
+	if c.Input.PathsOnly {
+		// Stats and hunk references survive, but there is no code to render
+		return "[Paths-only case: code content was removed on export, so the diff can't be shown]"
+	}
+	return renderDiff(m.diffConfig())
+}
 
+// diffConfig returns the render configuration for the current case's diff.
+func (m EvalModel) diffConfig() renderConfig {
 	// Use filtered diff in story mode, full diff otherwise
 	diffToRender, originalIndices := m.filteredDiffWithIndices()
 
-	// Render diff content using styled renderer
-	diffContent := renderDiff(renderConfig{
+	// Raw mode shows every hunk, so note which section each one belongs to
+	var annotations map[hunkKey]string
+	if !m.storyMode && !m.hideNotes {
+		annotations = m.sectionNotes
+	}
+
+	wordDiffer := m.wordDiffer
+	if m.noWordDiff {
+		wordDiffer = nil
+	}
+
+	return renderConfig{
 		diff:             diffToRender,
 		styles:           m.styles,
 		renderer:         nil, // Use default renderer
-		width:            m.width,
+		width:            m.diffViewport.Width,
 		languageDetector: m.languageDetector,
 		tokenizer:        m.tokenizer,
-		wordDiffer:       m.wordDiffer,
+		wordDiffer:       wordDiffer,
+		tabWidth:         m.tabWidth,
+		wrap:             m.wrap,
+		xOffset:          m.xOffset,
 		collapsedHunks:   m.collapsedHunks,
 		hunkCategories:   m.hunkCategories,
 		collapseText:     m.collapseText,
 		originalIndices:  originalIndices,
-	})
+		hunkAnnotations:  annotations,
+		cache:            m.cache,
+	}
+}
 
-	m.diffViewport.SetContent(diffContent)
+// refreshDiff re-renders the diff pane in place, keeping the scroll position.
+func (m *EvalModel) refreshDiff() {
+	if len(m.cases) == 0 {
+		return
+	}
+	m.diffFileRows = nil
+	if !m.cases[m.currentIndex].Input.PathsOnly {
+		m.diffFileRows = m.diffConfig().layout().Files
+	}
+	m.diffViewport.SetContent(m.renderDiffContent())
+}
+
+// openFinder opens the jump-to-file overlay over the diff pane, listing the
+// files currently shown there.
+func (m *EvalModel) openFinder() {
+	if m.viewMode != ViewStory || len(m.diffFileRows) == 0 {
+		return
+	}
+	diff, _ := m.filteredDiffWithIndices()
+	m.finder = newFileFinder(renderedFilePaths(diff))
+}
+
+// scrollDiffHorizontal scrolls diff line content by delta columns.
+// Has no effect while wrapping.
+func (m *EvalModel) scrollDiffHorizontal(delta int) {
+	if m.wrap || len(m.cases) == 0 {
+		return
+	}
+	diff, _ := m.filteredDiffWithIndices()
+	offset := clampXOffset(m.xOffset+delta, maxXOffset(diff, m.diffViewport.Width, m.tabWidth))
+	if offset == m.xOffset {
+		return
+	}
+	m.xOffset = offset
+	m.refreshDiff()
+}
+
+func (m *EvalModel) updateViewportContent() {
+	if len(m.cases) == 0 {
+		m.diffViewport.SetContent("No cases loaded")
+		m.storyViewport.SetContent("")
+		return
+	}
+
+	c := m.cases[m.currentIndex]
+
+	m.xOffset = 0
+	m.refreshDiff()
 	m.diffViewport.GotoTop()
 
 	// Render metadata content based on mode
@@ -491,8 +917,11 @@ func (m *EvalModel) updateViewportContent() {
 	}
 
 	// Add critique if present (full text, not truncated)
-	if j := m.judgments[c.Input.CaseID()]; j != nil && j.Critique != "" {
+	if j := m.judgments[c.CaseID()]; j != nil && (j.Critique != "" || critiqueLabel(*j) != "") {
 		metadataContent.WriteString("\n\nCRITIQUE:\n")
+		if label := critiqueLabel(*j); label != "" {
+			metadataContent.WriteString("[" + label + "]\n")
+		}
 		metadataContent.WriteString(j.Critique)
 	}
 
@@ -500,7 +929,9 @@ func (m *EvalModel) updateViewportContent() {
 	m.storyViewport.GotoTop()
 
 	// Update data viewport content
-	if c.Story != nil {
+	if m.comparingGold() {
+		m.dataViewport.SetContent(RenderStoryComparison(c.Story, m.goldStory(), m.width))
+	} else if c.Story != nil {
 		m.dataViewport.SetContent(RenderDataView(c.Story, m.width))
 	} else {
 		m.dataViewport.SetContent("[Not yet classified]")
@@ -508,18 +939,23 @@ func (m *EvalModel) updateViewportContent() {
 	m.dataViewport.GotoTop()
 }
 
-func (m *EvalModel) recordJudgment(pass bool) {
+func (m *EvalModel) recordJudgment(pass bool) tea.Cmd {
 	if len(m.cases) == 0 {
-		return
+		return nil
 	}
 
 	c := m.cases[m.currentIndex]
-	caseID := c.Input.CaseID()
+	caseID := c.CaseID()
 
-	// Preserve existing critique when toggling pass/fail
+	// Preserve existing critique and gold label when toggling pass/fail. The
+	// judgment is the reviewer's now, even if a grader made it.
 	var critique string
+	var category diffview.CritiqueCategory
+	var severity diffview.Severity
+	var gold *diffview.StoryClassification
 	if existing := m.judgments[caseID]; existing != nil {
-		critique = existing.Critique
+		critique, category, severity = existing.Critique, existing.Category, existing.Severity
+		gold = existing.Gold
 	}
 
 	j := &diffview.Judgment{
@@ -528,11 +964,16 @@ func (m *EvalModel) recordJudgment(pass bool) {
 		Judged:   true,
 		Pass:     pass,
 		Critique: critique,
+		Category: category,
+		Severity: severity,
 		JudgedAt: time.Now(),
+		Gold:     gold,
 	}
+	before := copyJudgment(m.judgments[caseID])
 	m.judgments[caseID] = j
+	m.recordEdit(caseID, before)
 
-	m.persistJudgments()
+	return m.persistJudgments()
 }
 
 // isUnjudged returns true if the case at the given index hasn't been judged.
@@ -540,37 +981,37 @@ func (m EvalModel) isUnjudged(idx int) bool {
 	if idx < 0 || idx >= len(m.cases) {
 		return false
 	}
-	j := m.judgments[m.cases[idx].Input.CaseID()]
+	j := m.judgments[m.cases[idx].CaseID()]
 	return j == nil || !j.Judged
 }
 
-// findNextUnjudged returns the index of the next unjudged case, wrapping around.
-// Returns -1 if no unjudged cases exist.
+// findNextUnjudged returns the index of the next unjudged case in order,
+// wrapping around. Returns -1 if no unjudged cases exist.
 func (m EvalModel) findNextUnjudged() int {
-	n := len(m.cases)
-	if n == 0 {
-		return -1
-	}
-	// Search from current+1 to end, then from start to current
-	for i := 1; i <= n; i++ {
-		idx := (m.currentIndex + i) % n
-		if m.isUnjudged(idx) {
-			return idx
-		}
-	}
-	return -1
+	return m.findUnjudged(1)
 }
 
-// findPrevUnjudged returns the index of the previous unjudged case, wrapping around.
-// Returns -1 if no unjudged cases exist.
+// findPrevUnjudged returns the index of the previous unjudged case in order,
+// wrapping around. Returns -1 if no unjudged cases exist.
 func (m EvalModel) findPrevUnjudged() int {
-	n := len(m.cases)
+	return m.findUnjudged(-1)
+}
+
+// findUnjudged searches the cases in order for an unjudged one, forwards
+// from the current case if dir is 1 and backwards if it's -1, wrapping
+// around.
+func (m EvalModel) findUnjudged(dir int) int {
+	order := m.order()
+	n := len(order)
 	if n == 0 {
 		return -1
 	}
-	// Search backwards from current-1 to start, then from end to current
+	pos := slices.Index(order, m.currentIndex)
+	if pos < 0 && dir < 0 {
+		pos = n
+	}
 	for i := 1; i <= n; i++ {
-		idx := (m.currentIndex - i + n) % n
+		idx := order[((pos+dir*i)%n+n)%n]
 		if m.isUnjudged(idx) {
 			return idx
 		}
@@ -578,32 +1019,74 @@ func (m EvalModel) findPrevUnjudged() int {
 	return -1
 }
 
-func (m *EvalModel) persistJudgments() {
-	if m.store == nil || m.outputPath == "" {
-		return
-	}
+// Cases returns the cases under review, without any deleted this session.
+func (m EvalModel) Cases() []diffview.EvalCase {
+	return m.cases
+}
+
+// Judgments returns every judgment recorded so far, ordered by case index.
+func (m EvalModel) Judgments() []diffview.Judgment {
+	now := time.Now()
 	judgments := make([]diffview.Judgment, 0, len(m.judgments))
 	for _, j := range m.judgments {
-		judgments = append(judgments, *j)
+		j := *j
+		j.ReviewTime += m.timer.elapsed(j.CaseID, now)
+		judgments = append(judgments, j)
 	}
 	// Sort by index for deterministic output
 	sort.Slice(judgments, func(i, k int) bool {
 		return judgments[i].Index < judgments[k].Index
 	})
-	// Best-effort save - errors are logged but don't block the UI
-	// TODO: Consider adding error display in status bar
-	_ = m.store.Save(m.outputPath, judgments)
+	return judgments
 }
 
-func (m *EvalModel) copyCurrentCase() {
-	if m.clipboard == nil || len(m.cases) == 0 {
-		return
+// deleteCurrentCase records a tombstone for the current case and removes it
+// from the session. The dataset file itself is left untouched; the case stays
+// on disk until the dataset is garbage-collected.
+func (m *EvalModel) deleteCurrentCase() tea.Cmd {
+	if m.tombstones == nil || len(m.cases) == 0 {
+		return nil
 	}
 
 	c := m.cases[m.currentIndex]
-	content := formatCaseForExport(c)
-	// Best-effort copy - errors are silently ignored in UI
-	_ = m.clipboard.Copy(content)
+	t := diffview.Tombstone{
+		CaseID:    c.CaseID(),
+		DeletedAt: time.Now(),
+	}
+	// Keep the case visible if the deletion couldn't be recorded
+	if err := m.tombstones.Append(m.tombstonesPath, t); err != nil {
+		return notifyErr("deleting case", err)
+	}
+
+	pos := slices.Index(m.order(), m.currentIndex)
+	cases := make([]diffview.EvalCase, 0, len(m.cases)-1)
+	cases = append(cases, m.cases[:m.currentIndex]...)
+	cases = append(cases, m.cases[m.currentIndex+1:]...)
+	m.cases = cases
+	m.forgetShown(m.currentIndex)
+	switch {
+	case m.shown != nil && pos >= 0:
+		// Move on to the next case the filter kept
+		m.currentIndex = m.shown[min(pos, len(m.shown)-1)]
+	case m.currentIndex >= len(m.cases) && m.currentIndex > 0:
+		m.currentIndex--
+	}
+
+	m.rebuildStoryMaps()
+	m.updateStoryModeForCase()
+	m.updateViewportContent()
+	return notify("case deleted")
+}
+
+func (m *EvalModel) copyCurrentCase() tea.Cmd {
+	if m.clipboard == nil || len(m.cases) == 0 {
+		return nil
+	}
+
+	clipboard, content := m.clipboard, formatCaseForExport(m.cases[m.currentIndex])
+	return func() tea.Msg {
+		return toastResult("case copied to clipboard", "copying case", clipboard.Copy(content))
+	}
 }
 
 // rebuildStoryMaps rebuilds the hunk maps from the current case's story.
@@ -613,6 +1096,7 @@ func (m *EvalModel) rebuildStoryMaps() {
 	m.collapsedHunks = make(map[hunkKey]bool)
 	m.hunkCategories = make(map[hunkKey]string)
 	m.collapseText = make(map[hunkKey]string)
+	m.sectionNotes = make(map[hunkKey]string)
 	m.activeSection = 0
 
 	if len(m.cases) == 0 {
@@ -626,9 +1110,11 @@ func (m *EvalModel) rebuildStoryMaps() {
 
 	// Build lookup maps from story classification
 	for _, section := range c.Story.Sections {
+		note := sectionNote(section)
 		for _, ref := range section.Hunks {
 			key := hunkKey{file: ref.File, hunkIndex: ref.HunkIndex}
 			m.hunkCategories[key] = ref.Category
+			m.sectionNotes[key] = note
 			if ref.CollapseText != "" {
 				m.collapseText[key] = ref.CollapseText
 			}
@@ -640,6 +1126,19 @@ func (m *EvalModel) rebuildStoryMaps() {
 	}
 }
 
+// sectionNote returns the annotation shown above a section's hunks in raw
+// mode: "[role] title — explanation".
+func sectionNote(section diffview.Section) string {
+	note := section.Title
+	if section.Role != "" {
+		note = "[" + section.Role + "] " + note
+	}
+	if section.Explanation != "" {
+		note += " — " + section.Explanation
+	}
+	return note
+}
+
 // toggleStoryMode toggles between story mode and raw mode.
 // Story mode is only available when the current case has sections.
 func (m *EvalModel) toggleStoryMode() {
@@ -655,6 +1154,7 @@ func (m *EvalModel) toggleStoryMode() {
 	}
 
 	m.storyMode = !m.storyMode
+	m.rawMode = !m.storyMode
 	if m.storyMode {
 		m.rebuildStoryMaps()
 	}
@@ -668,11 +1168,24 @@ func (m *EvalModel) toggleViewMode() {
 	} else {
 		m.viewMode = ViewStory
 	}
+	m.compareGold = false
+	m.updateViewportContent()
+}
+
+// toggleGoldComparison switches the data view between the model's story and
+// its differences from the gold one, if the case has a gold label.
+func (m *EvalModel) toggleGoldComparison() {
+	if m.goldStory() == nil {
+		return
+	}
+	m.compareGold = !m.compareGold || m.viewMode != ViewData
+	m.viewMode = ViewData
 	m.updateViewportContent()
 }
 
 // updateStoryModeForCase updates story mode based on the current case.
-// Enables story mode if the case has sections, disables if it doesn't.
+// Enables story mode if the case has sections, unless the reviewer last
+// switched to raw mode, and disables it if it doesn't.
 func (m *EvalModel) updateStoryModeForCase() {
 	if len(m.cases) == 0 {
 		m.storyMode = false
@@ -681,7 +1194,7 @@ func (m *EvalModel) updateStoryModeForCase() {
 
 	c := m.cases[m.currentIndex]
 	// Enable story mode if the case has sections
-	m.storyMode = c.Story != nil && len(c.Story.Sections) > 0
+	m.storyMode = !m.rawMode && c.Story != nil && len(c.Story.Sections) > 0
 }
 
 // gotoNextSection moves to the next section and marks the current one as reviewed.
@@ -725,42 +1238,113 @@ func (m *EvalModel) gotoPrevSection() {
 // adjustSplit adjusts the split ratio by the given delta (positive = more metadata).
 // Clamps the ratio between 10% and 90%.
 func (m *EvalModel) adjustSplit(delta int) {
-	m.splitRatio += delta
-	if m.splitRatio < 10 {
-		m.splitRatio = 10
+	m.splitRatio = clampSplit(m.splitRatio + delta)
+	m.recalculateViewportSizes()
+}
+
+// clampSplit limits a split ratio to between 10% and 90%.
+func clampSplit(ratio int) int {
+	return min(max(ratio, 10), 90)
+}
+
+// restorePreferences applies saved preferences. A zero split ratio, as
+// saved before there was one, keeps the default.
+func (m *EvalModel) restorePreferences(prefs diffview.Preferences) {
+	if prefs.SplitRatio != 0 {
+		m.splitRatio = clampSplit(prefs.SplitRatio)
 	}
-	if m.splitRatio > 90 {
-		m.splitRatio = 90
+	m.rawMode = prefs.RawMode
+	m.noWordDiff = prefs.NoWordDiff
+}
+
+// savePreferences records the split ratio, default mode and word diff
+// setting, if a preferences store is configured.
+func (m EvalModel) savePreferences() {
+	if m.prefs == nil {
+		return
 	}
-	m.recalculateViewportSizes()
+	// Best-effort save - errors are silently ignored in UI
+	_ = m.prefs.Save(diffview.Preferences{
+		SplitRatio: m.splitRatio,
+		RawMode:    m.rawMode,
+		NoWordDiff: m.noWordDiff,
+	})
 }
 
-// recalculateViewportSizes updates viewport dimensions based on current split ratio.
+// recalculateViewportSizes updates viewport dimensions based on current
+// split ratio and pane layout.
 func (m *EvalModel) recalculateViewportSizes() {
 	if !m.ready || m.height == 0 {
 		return
 	}
 	// Reserve: DIFF header (1), STORY header (1), judgment bar (1), status bar (1) = 4
 	// Plus newlines after each viewport (2) = 6 total reserved
-	usableHeight := m.height - 6
-	if usableHeight < 2 {
-		usableHeight = 2
-	}
-	metadataHeight := usableHeight * m.splitRatio / 100
-	diffHeight := usableHeight - metadataHeight
-
-	m.storyViewport.Width = m.width
-	m.storyViewport.Height = metadataHeight
-	m.diffViewport.Width = m.width
+	usableHeight := max(m.height-6, 2)
+	width := m.paneWidth()
+	storyWidth, storyHeight := width, usableHeight*m.splitRatio/100
+	diffWidth, diffHeight := width, usableHeight-storyHeight
+	switch {
+	case m.hideStory:
+		// The story pane's header and newline go to the diff
+		storyHeight = 0
+		diffHeight = usableHeight + 2
+	case m.storyBeside():
+		// Both panes share one header row, split by a separator column
+		storyWidth = width * m.splitRatio / 100
+		diffWidth = width - storyWidth - 1
+		storyHeight = usableHeight + 2
+		diffHeight = storyHeight
+	}
+
+	m.storyViewport.Width = storyWidth
+	m.storyViewport.Height = storyHeight
+	m.diffViewport.Width = diffWidth
 	m.diffViewport.Height = diffHeight
 
 	// Data view uses full height minus header (1), judgment bar (1), status bar (1) = 3
-	dataHeight := m.height - 3
-	if dataHeight < 1 {
-		dataHeight = 1
-	}
 	m.dataViewport.Width = m.width
-	m.dataViewport.Height = dataHeight
+	m.dataViewport.Height = max(m.height-3, 1)
+}
+
+// resizePanes lays out the panes again after the terminal or the layout
+// changes, rendering the diff to its new width.
+func (m *EvalModel) resizePanes() {
+	width := m.diffViewport.Width
+	m.recalculateViewportSizes()
+	if m.diffViewport.Width != width {
+		m.xOffset = 0
+		m.refreshDiff()
+	}
+}
+
+// storyBeside reports whether the story pane sits left of the diff: when
+// side by side is on, the pane is shown and the terminal is wide enough.
+func (m EvalModel) storyBeside() bool {
+	return m.sideBySide && !m.hideStory && m.width >= sideBySideMinWidth
+}
+
+// switchFocus moves the scroll keys to the other pane of the story view.
+func (m *EvalModel) switchFocus() {
+	if m.viewMode != ViewStory || m.hideStory {
+		return
+	}
+	if m.focus == paneDiff {
+		m.focus = paneStory
+	} else {
+		m.focus = paneDiff
+	}
+}
+
+// focusedViewport returns the viewport the scroll keys act on: the data
+// view's, or the focused pane's in the story view.
+func (m *EvalModel) focusedViewport() *viewport.Model {
+	switch {
+	case m.viewMode == ViewData:
+		return &m.dataViewport
+	case m.focus == paneStory:
+		return &m.storyViewport
+	}
+	return &m.diffViewport
 }
 
 // renderSectionHeader formats the section header for display in the diff panel.
@@ -805,7 +1389,7 @@ func (m *EvalModel) filteredDiffWithIndices() (*diffview.Diff, map[hunkKey]int)
 	originalIndices := make(map[hunkKey]int)
 	var filteredFiles []diffview.FileDiff
 	for _, file := range diff.Files {
-		path := filePath(file)
+		path := file.Path()
 		var filteredHunks []diffview.Hunk
 		for hunkIdx, hunk := range file.Hunks {
 			if activeHunks[hunkKey{file: path, hunkIndex: hunkIdx}] {
@@ -838,6 +1422,9 @@ func formatCaseForExport(c diffview.EvalCase) string {
 	if c.Input.Branch != "" {
 		sb.WriteString(fmt.Sprintf("Branch: %s\n", c.Input.Branch))
 	}
+	if len(c.Input.Reviewers) > 0 {
+		sb.WriteString(reviewersSummary(c.Input.Reviewers))
+	}
 	if len(c.Input.Commits) > 0 {
 		sb.WriteString("\nCommits:\n")
 		for _, commit := range c.Input.Commits {
@@ -846,7 +1433,7 @@ func formatCaseForExport(c diffview.EvalCase) string {
 	}
 	sb.WriteString("\n```diff\n")
 	for _, file := range c.Input.Diff.Files {
-		sb.WriteString(fmt.Sprintf("=== %s (%s) ===\n", formatFilePath(file), formatFileOp(file.Operation)))
+		sb.WriteString(fmt.Sprintf("=== %s (%s) ===\n", file.Path(), formatFileOp(file.Operation)))
 		for _, hunk := range file.Hunks {
 			sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n",
 				hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount))
@@ -869,6 +1456,31 @@ func formatCaseForExport(c diffview.EvalCase) string {
 		sb.WriteString(fmt.Sprintf("Narrative: %s\n", c.Story.Narrative))
 		sb.WriteString(fmt.Sprintf("Summary: %s\n\n", c.Story.Summary))
 
+		if i := c.Story.Impact; i != nil {
+			sb.WriteString(fmt.Sprintf("Impact: %s, %s bump\n", i.ConventionalType(), i.Bump))
+			if len(i.Breaking) > 0 {
+				sb.WriteString(fmt.Sprintf("  Breaking: %s\n", strings.Join(i.Breaking, ", ")))
+			}
+			if len(i.Added) > 0 {
+				sb.WriteString(fmt.Sprintf("  Added: %s\n", strings.Join(i.Added, ", ")))
+			}
+			sb.WriteString("\n")
+		}
+
+		if r := c.Story.Risk; r != nil {
+			sb.WriteString(fmt.Sprintf("Risk: %s\n", r.Level))
+			if len(r.Breaking) > 0 {
+				sb.WriteString(fmt.Sprintf("  Breaking: %s\n", strings.Join(r.Breaking, "; ")))
+			}
+			if len(r.Migration) > 0 {
+				sb.WriteString(fmt.Sprintf("  Migration: %s\n", strings.Join(r.Migration, "; ")))
+			}
+			if len(r.AffectedAPIs) > 0 {
+				sb.WriteString(fmt.Sprintf("  Affected APIs: %s\n", strings.Join(r.AffectedAPIs, ", ")))
+			}
+			sb.WriteString("\n")
+		}
+
 		if len(c.Story.Sections) > 0 {
 			sb.WriteString("Sections:\n")
 			for i, section := range c.Story.Sections {
@@ -898,13 +1510,6 @@ func formatCaseForExport(c diffview.EvalCase) string {
 	return sb.String()
 }
 
-func formatFilePath(file diffview.FileDiff) string {
-	if file.NewPath != "" {
-		return file.NewPath
-	}
-	return file.OldPath
-}
-
 func formatFileOp(op diffview.FileOp) string {
 	switch op {
 	case diffview.FileAdded:
@@ -939,37 +1544,74 @@ func (m EvalModel) View() string {
 		return "Loading..."
 	}
 
+	// Locked screen hides everything, including critique text
+	if m.idle.locked {
+		return renderLockScreen(m.width, m.height, lipgloss.NewStyle().Faint(true))
+	}
+
 	// Critique mode shows full-screen textarea
 	if m.mode == ModeCritique {
 		return m.renderCritiqueView()
 	}
 
+	// Edit mode shows the full-screen section editor
+	if m.mode == ModeEdit {
+		return m.renderEditView()
+	}
+
 	// Help mode shows keybinding overlay
 	if m.mode == ModeHelp {
 		return m.renderHelpView()
 	}
 
+	// Filter mode shows the filter overlay
+	if m.mode == ModeFilter {
+		return m.renderFilterView()
+	}
+
 	// Data view shows full-screen classification tree
 	if m.viewMode == ViewData {
 		return m.renderDataViewScreen()
 	}
 
-	var s strings.Builder
+	var s, panes strings.Builder
 
 	// Metadata panel (top) - section info in story mode, classification tree in raw mode
 	panelName := "STORY"
 	if m.storyMode {
 		panelName = "SECTION"
 	}
-	s.WriteString(m.renderPanelHeader(panelName))
-	s.WriteString("\n")
-	s.WriteString(m.storyViewport.View())
-	s.WriteString("\n")
+	storyPane := m.renderPanelHeader(panelName, m.focus == paneStory) + "\n" + m.storyViewport.View()
 
 	// Diff panel (bottom) - filtered hunks in story mode, full diff in raw mode
-	s.WriteString(m.renderPanelHeader("DIFF"))
-	s.WriteString("\n")
-	s.WriteString(m.diffViewport.View())
+	diffPane := m.renderPanelHeader("DIFF", m.focus == paneDiff) + "\n"
+	if m.finder.active {
+		diffPane += m.finder.view(m.diffViewport.Width, m.diffViewport.Height, evalFinderStyles())
+	} else {
+		diffPane += m.diffViewport.View()
+	}
+
+	switch {
+	case m.hideStory:
+		panes.WriteString(diffPane)
+	case m.storyBeside():
+		storyPane = lipgloss.NewStyle().Width(m.storyViewport.Width).Render(storyPane)
+		separator := strings.TrimSuffix(strings.Repeat("│\n", m.diffViewport.Height+1), "\n")
+		panes.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, storyPane, separator, diffPane))
+	default:
+		panes.WriteString(storyPane)
+		panes.WriteString("\n")
+		panes.WriteString(diffPane)
+	}
+
+	if m.caseListShown() {
+		// The list runs down beside the panes, above the judgment and status bars
+		height := lipgloss.Height(panes.String())
+		separator := strings.TrimSuffix(strings.Repeat("│\n", height), "\n")
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, m.renderCaseList(caseListWidth, height), separator, panes.String()))
+	} else {
+		s.WriteString(panes.String())
+	}
 	s.WriteString("\n")
 
 	// Judgment bar
@@ -982,15 +1624,48 @@ func (m EvalModel) View() string {
 	return s.String()
 }
 
+// evalFinderStyles returns the file finder styles, matching the reviewer's
+// plain bold and faint look.
+func evalFinderStyles() finderStyles {
+	return finderStyles{
+		prompt:   lipgloss.NewStyle().Bold(true),
+		item:     lipgloss.NewStyle(),
+		selected: lipgloss.NewStyle().Reverse(true),
+		match:    lipgloss.NewStyle().Bold(true).Underline(true),
+		count:    lipgloss.NewStyle().Faint(true),
+	}
+}
+
 func (m EvalModel) renderCritiqueView() string {
 	var s strings.Builder
 
 	header := lipgloss.NewStyle().Bold(true).Render("CRITIQUE")
 	s.WriteString(header)
 	s.WriteString("\n\n")
+	s.WriteString(renderChoices("Category", diffview.CritiqueCategories(), m.critiqueCategory, diffview.CritiqueCategory.Label))
+	s.WriteString("\n")
+	s.WriteString(renderChoices("Severity", diffview.Severities(), m.critiqueSeverity, func(s diffview.Severity) string { return string(s) }))
+	s.WriteString("\n\n")
 	s.WriteString(m.critiqueTextarea.View())
 	s.WriteString("\n\n")
-	s.WriteString(lipgloss.NewStyle().Faint(true).Render("[Esc] save and exit"))
+	s.WriteString(lipgloss.NewStyle().Faint(true).Render("[Tab] category  [Shift+Tab] severity  [Esc] save and exit"))
+
+	return s.String()
+}
+
+func (m EvalModel) renderEditView() string {
+	var s strings.Builder
+
+	header := lipgloss.NewStyle().Bold(true).Render("EDIT SECTIONS")
+	s.WriteString(header)
+	s.WriteString("\n\n")
+	s.WriteString(m.editor.view())
+	s.WriteString("\n")
+	hints := "[j/k] move  [J/K] move hunk to next/prev section  [t] category  [r] retitle  [o] add section  [x] reset  [Esc] save and exit"
+	if m.editor.retitling {
+		hints = "[Enter] set title  [Esc] cancel"
+	}
+	s.WriteString(lipgloss.NewStyle().Faint(true).Render(hints))
 
 	return s.String()
 }
@@ -1003,13 +1678,15 @@ func (m EvalModel) renderHelpView() string {
 	descStyle := lipgloss.NewStyle().Faint(true)
 
 	s.WriteString(headerStyle.Render("HELP"))
-	s.WriteString("\n\n")
+	s.WriteString("\n")
 
 	// Navigation
 	s.WriteString(headerStyle.Render("Navigation"))
 	s.WriteString("\n")
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("n/N"), descStyle.Render("next/previous case")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("u/U"), descStyle.Render("next/previous unjudged")))
+	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("/"), descStyle.Render("filter and sort cases")))
+	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("b"), descStyle.Render("case list (↑/↓ move through it)")))
 	s.WriteString("\n")
 
 	// Scrolling
@@ -1023,27 +1700,42 @@ func (m EvalModel) renderHelpView() string {
 	// View
 	s.WriteString(headerStyle.Render("View"))
 	s.WriteString("\n")
-	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Tab"), descStyle.Render("toggle story/data view")))
+	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("d"), descStyle.Render("toggle story/data view")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Tab"), descStyle.Render("switch focus between story and diff")))
+	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("s"), descStyle.Render("hide/show story pane")))
+	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("L"), descStyle.Render("story beside diff (wide terminals)")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("=/+/-"), descStyle.Render("resize split")))
 	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("m"), descStyle.Render("toggle story/raw mode")))
+	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("a"), descStyle.Render("toggle section notes (raw mode)")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("]/["), descStyle.Render("next/prev section (story mode)")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("w/W"), descStyle.Render("toggle line wrap/word highlighting")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("h/l"), descStyle.Render("scroll diff left/right")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("ctrl+p/:"), descStyle.Render("jump to file")))
+	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("v"), descStyle.Render("compare model and gold story")))
 	s.WriteString("\n")
 
-	// Judgment
-	s.WriteString(headerStyle.Render("Judgment"))
-	s.WriteString("\n")
-	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("p"), descStyle.Render("mark pass")))
-	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("f"), descStyle.Render("mark fail")))
-	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("c"), descStyle.Render("enter critique")))
-	s.WriteString("\n")
+	// Judgment (not available in a read-only snapshot)
+	if !m.readOnly {
+		s.WriteString(headerStyle.Render("Judgment"))
+		s.WriteString("\n")
+		s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("p/f"), descStyle.Render("mark pass/fail")))
+		s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("c"), descStyle.Render("enter critique")))
+		s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("e"), descStyle.Render("edit sections (gold label)")))
+		s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("P"), descStyle.Render("promote edits to gold")))
+		s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("ctrl+z/y"), descStyle.Render("undo/redo judgment change")))
+		s.WriteString("\n")
+	}
 
 	// Other
 	s.WriteString(headerStyle.Render("Other"))
 	s.WriteString("\n")
 	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("y"), descStyle.Render("copy case to clipboard")))
+	if !m.readOnly {
+		s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("D"), descStyle.Render("delete case from dataset")))
+	}
 	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("?"), descStyle.Render("toggle help")))
 	s.WriteString(fmt.Sprintf("  %s    %s\n", keyStyle.Render("q"), descStyle.Render("quit")))
-	s.WriteString("\n\n")
+	s.WriteString("\n")
 
 	s.WriteString(descStyle.Render("Press any key to close"))
 
@@ -1054,7 +1746,11 @@ func (m EvalModel) renderDataViewScreen() string {
 	var s strings.Builder
 
 	// Header
-	header := lipgloss.NewStyle().Bold(true).Render("DATA")
+	name := "DATA"
+	if m.comparingGold() {
+		name = "DATA: model → gold"
+	}
+	header := lipgloss.NewStyle().Bold(true).Render(name)
 	s.WriteString(header)
 	s.WriteString("\n")
 
@@ -1072,8 +1768,13 @@ func (m EvalModel) renderDataViewScreen() string {
 	return s.String()
 }
 
-func (m EvalModel) renderPanelHeader(name string) string {
+// renderPanelHeader renders the name of a pane of the story view, marking
+// the focused one while both are shown.
+func (m EvalModel) renderPanelHeader(name string, focused bool) string {
 	style := lipgloss.NewStyle().Bold(true)
+	if focused && !m.hideStory {
+		name = "▸ " + name
+	}
 	return style.Render(name)
 }
 
@@ -1083,7 +1784,7 @@ func (m EvalModel) renderJudgmentBar() string {
 	}
 
 	c := m.cases[m.currentIndex]
-	j := m.judgments[c.Input.CaseID()]
+	j := m.judgments[c.CaseID()]
 
 	passMarker := "○"
 	failMarker := "○"
@@ -1103,9 +1804,23 @@ func (m EvalModel) renderJudgmentBar() string {
 				critique = critique[:27] + "..."
 			}
 		}
+		if label := critiqueLabel(*j); label != "" {
+			critique = "[" + label + "] " + critique
+		}
+	}
+	var judge string
+	if j != nil && j.Judge != "" {
+		judge = "  (auto: " + j.Judge + ")"
+	}
+	var gold string
+	switch {
+	case m.unpromoted():
+		gold = "    Gold: edited"
+	case c.Gold != nil:
+		gold = "    Gold: promoted"
 	}
 
-	return fmt.Sprintf("%s Pass  %s Fail    Critique: %s", passMarker, failMarker, critique)
+	return fmt.Sprintf("%s Pass  %s Fail%s    Critique: %s%s", passMarker, failMarker, judge, critique, gold)
 }
 
 // RenderDataView formats the classification as a structured tree for data view.
@@ -1154,13 +1869,26 @@ func (m EvalModel) renderStatusBar() string {
 		return "No cases"
 	}
 
+	if m.mode == ModeConfirmDelete {
+		name := m.cases[m.currentIndex].Input.Name()
+		return fmt.Sprintf("Delete %s from dataset? y confirm │ n cancel", name)
+	}
+	if m.saveErr != nil && m.mode == ModeReview {
+		return fmt.Sprintf("Judgments not saved: %v. r retry │ esc dismiss", m.saveErr)
+	}
+
 	// View mode indicator: [story] or [data]
 	viewIndicator := "[story]"
-	if m.viewMode == ViewData {
+	if m.comparingGold() {
+		viewIndicator = "[gold diff]"
+	} else if m.viewMode == ViewData {
 		viewIndicator = "[data]"
 	}
 
 	var parts []string
+	if m.readOnly {
+		parts = append(parts, "read-only "+m.readOnlyLabel)
+	}
 	parts = append(parts, viewIndicator)
 
 	// Section info (story view only)
@@ -1172,11 +1900,11 @@ func (m EvalModel) renderStatusBar() string {
 	}
 
 	// Case position
-	parts = append(parts, fmt.Sprintf("case %d/%d", m.currentIndex+1, len(m.cases)))
+	parts = append(parts, m.casePosition())
 
 	// Current case judgment state
 	currentCase := m.cases[m.currentIndex]
-	j, ok := m.judgments[currentCase.Input.CaseID()]
+	j, ok := m.judgments[currentCase.CaseID()]
 	var judgmentState string
 	if !ok {
 		judgmentState = "○ unset"
@@ -1187,14 +1915,29 @@ func (m EvalModel) renderStatusBar() string {
 	} else {
 		judgmentState = "✗ fail"
 	}
+	if ok && j.Judge != "" {
+		judgmentState += " (auto)"
+	}
 	parts = append(parts, judgmentState)
 
-	// Contextual key hints
-	var hints string
+	// Flag a story that doesn't fit its diff, such as one referencing hunks
+	// that don't exist
+	if currentCase.Story != nil {
+		if issues := diffview.ValidateStory(&currentCase.Input.Diff, currentCase.Story); len(issues) > 0 {
+			parts = append(parts, fmt.Sprintf("⚠ %d story issue(s)", len(issues)))
+		}
+	}
+
+	// Contextual key hints, or the latest toast
+	hints := "n/N case"
 	if m.viewMode == ViewStory && m.storyMode {
-		hints = "n/N case ]/[ section p/f judge"
-	} else {
-		hints = "n/N case p/f judge"
+		hints += " ]/[ section"
+	}
+	if !m.readOnly {
+		hints += " p/f judge"
+	}
+	if t := m.toast.render(lipgloss.NewStyle(), lipgloss.NewStyle().Bold(true)); t != "" {
+		hints = t
 	}
 	parts = append(parts, hints)
 
@@ -9,6 +9,12 @@ type EvalKeyMap struct {
 	PrevCase     key.Binding
 	NextUnjudged key.Binding
 	PrevUnjudged key.Binding
+	Filter       key.Binding // opens the filter overlay
+
+	// Case list
+	ToggleCaseList key.Binding
+	CaseListDown   key.Binding // while the list shows, in place of scrolling
+	CaseListUp     key.Binding
 
 	// Scrolling
 	ScrollDown   key.Binding
@@ -22,21 +28,68 @@ type EvalKeyMap struct {
 	NextSection   key.Binding
 	PrevSection   key.Binding
 	ToggleMode    key.Binding
-	ToggleView    key.Binding // Tab: toggle story/data view
+	ToggleView    key.Binding // d: toggle story/data view
+	ToggleNotes   key.Binding // raw mode: toggle section annotations above hunks
 	IncreaseSplit key.Binding
 	DecreaseSplit key.Binding
 
+	// Panes
+	SwitchFocus      key.Binding // Tab: scroll keys act on the other pane
+	ToggleStoryPane  key.Binding
+	ToggleSideBySide key.Binding // story pane left of the diff on wide terminals
+
+	// Long lines
+	ToggleWrap     key.Binding
+	ToggleWordDiff key.Binding
+	ScrollLeft     key.Binding
+	ScrollRight    key.Binding
+	FindFile       key.Binding // ctrl+p or ':': fuzzy jump to a file in the diff pane
+
 	// Judgment
 	Pass     key.Binding
 	Fail     key.Binding
 	Critique key.Binding
+	Undo     key.Binding // reverts the last judgment change: pass/fail, critique or gold label
+	Redo     key.Binding
 
 	// Critique mode
-	ExitCritique key.Binding
+	ExitCritique     key.Binding
+	CritiqueCategory key.Binding
+	CritiqueSeverity key.Binding
+
+	// Gold label editing
+	EditStory         key.Binding
+	MoveHunkDown      key.Binding // to the next section
+	MoveHunkUp        key.Binding // to the previous section
+	CycleHunkCategory key.Binding
+	RetitleSection    key.Binding
+	AddSection        key.Binding
+	ResetStory        key.Binding // back to the model's classification
+	ExitEdit          key.Binding
+	PromoteGold       key.Binding
+	CompareGold       key.Binding
 
 	// Export
 	CopyCase key.Binding
 
+	// Deletion
+	Delete        key.Binding
+	ConfirmDelete key.Binding
+	CancelDelete  key.Binding
+
+	// Filter overlay
+	FilterJudgment   key.Binding // all, failed or unjudged
+	FilterRepo       key.Binding
+	FilterChangeType key.Binding
+	SortCases        key.Binding // dataset order or by size
+	ClearFilter      key.Binding
+	ApplyFilter      key.Binding
+	CancelFilter     key.Binding
+
+	// Failed judgment saves
+	RetrySave        key.Binding
+	DismissSaveError key.Binding // judgments stay unsaved until the next save
+
 	// General
 	Quit key.Binding
 	Help key.Binding
@@ -61,6 +114,22 @@ func DefaultEvalKeyMap() EvalKeyMap {
 			key.WithKeys("U"),
 			key.WithHelp("U", "previous unjudged"),
 		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter and sort cases"),
+		),
+		ToggleCaseList: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "toggle case list"),
+		),
+		CaseListDown: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next case in list"),
+		),
+		CaseListUp: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous case in list"),
+		),
 		ScrollDown: key.NewBinding(
 			key.WithKeys("j", "down"),
 			key.WithHelp("j", "scroll down"),
@@ -98,8 +167,12 @@ func DefaultEvalKeyMap() EvalKeyMap {
 			key.WithHelp("m", "toggle story/raw mode"),
 		),
 		ToggleView: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("tab", "toggle story/data view"),
+			key.WithKeys("d"),
+			key.WithHelp("d", "toggle story/data view"),
+		),
+		ToggleNotes: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "toggle hunk annotations"),
 		),
 		IncreaseSplit: key.NewBinding(
 			key.WithKeys("+", "="),
@@ -109,6 +182,38 @@ func DefaultEvalKeyMap() EvalKeyMap {
 			key.WithKeys("-"),
 			key.WithHelp("-", "decrease metadata pane"),
 		),
+		SwitchFocus: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch focus between story and diff"),
+		),
+		ToggleStoryPane: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "hide/show story pane"),
+		),
+		ToggleSideBySide: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "toggle story beside diff"),
+		),
+		ToggleWrap: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "toggle line wrap"),
+		),
+		ToggleWordDiff: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "toggle word-level highlighting"),
+		),
+		ScrollLeft: key.NewBinding(
+			key.WithKeys("h", "left"),
+			key.WithHelp("h", "scroll diff left"),
+		),
+		ScrollRight: key.NewBinding(
+			key.WithKeys("l", "right"),
+			key.WithHelp("l", "scroll diff right"),
+		),
+		FindFile: key.NewBinding(
+			key.WithKeys("ctrl+p", ":"),
+			key.WithHelp("ctrl+p", "jump to file"),
+		),
 		Pass: key.NewBinding(
 			key.WithKeys("p"),
 			key.WithHelp("p", "mark pass"),
@@ -121,14 +226,118 @@ func DefaultEvalKeyMap() EvalKeyMap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "enter critique"),
 		),
+		Undo: key.NewBinding(
+			key.WithKeys("ctrl+z"),
+			key.WithHelp("ctrl+z", "undo judgment change"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "redo judgment change"),
+		),
 		ExitCritique: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "exit critique mode"),
 		),
+		CritiqueCategory: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "cycle error category"),
+		),
+		CritiqueSeverity: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "cycle severity"),
+		),
+		EditStory: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit sections"),
+		),
+		MoveHunkDown: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "move hunk to next section"),
+		),
+		MoveHunkUp: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "move hunk to previous section"),
+		),
+		CycleHunkCategory: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "cycle hunk category"),
+		),
+		RetitleSection: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "retitle section"),
+		),
+		AddSection: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "add section"),
+		),
+		ResetStory: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "reset to model output"),
+		),
+		ExitEdit: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "save and exit"),
+		),
+		PromoteGold: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "promote edits to gold"),
+		),
+		CompareGold: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "compare model and gold story"),
+		),
 		CopyCase: key.NewBinding(
 			key.WithKeys("y"),
 			key.WithHelp("y", "copy case to clipboard"),
 		),
+		Delete: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "delete case"),
+		),
+		ConfirmDelete: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "confirm delete"),
+		),
+		CancelDelete: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n", "cancel delete"),
+		),
+		FilterJudgment: key.NewBinding(
+			key.WithKeys("j"),
+			key.WithHelp("j", "cycle judgment filter"),
+		),
+		FilterRepo: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "cycle repo filter"),
+		),
+		FilterChangeType: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "cycle change type filter"),
+		),
+		SortCases: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "cycle sort order"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "clear filter"),
+		),
+		ApplyFilter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply filter"),
+		),
+		CancelFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		RetrySave: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "retry saving judgments"),
+		),
+		DismissSaveError: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "dismiss save error"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
@@ -2,7 +2,9 @@ package bubbletea_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 
@@ -10,7 +12,9 @@ import (
 	"github.com/charmbracelet/x/exp/teatest"
 	"github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEvalModel_Init(t *testing.T) {
@@ -160,8 +164,8 @@ func TestEvalModel_NavigationBetweenCases(t *testing.T) {
 
 	// Tests navigation between cases: forward with n, backward with N.
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "first", Commits: []diffview.CommitBrief{{Hash: "first"}}}, Story: &diffview.StoryClassification{Summary: "First summary"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "second", Commits: []diffview.CommitBrief{{Hash: "second"}}}, Story: &diffview.StoryClassification{Summary: "Second summary"}},
+		{ID: "first", Input: diffview.ClassificationInput{Repo: "repo", Branch: "first", Commits: []diffview.CommitBrief{{Hash: "first"}}}, Story: &diffview.StoryClassification{Summary: "First summary"}},
+		{ID: "second", Input: diffview.ClassificationInput{Repo: "repo", Branch: "second", Commits: []diffview.CommitBrief{{Hash: "second"}}}, Story: &diffview.StoryClassification{Summary: "Second summary"}},
 	}
 
 	m := bubbletea.NewEvalModel(cases)
@@ -197,6 +201,7 @@ func TestEvalModel_PassJudgment(t *testing.T) {
 
 	cases := []diffview.EvalCase{
 		{
+			ID:    "branch",
 			Input: diffview.ClassificationInput{Repo: "repo", Branch: "branch", Commits: []diffview.CommitBrief{{Hash: "abc123"}}},
 			Story: &diffview.StoryClassification{Summary: "Test story"},
 		},
@@ -229,6 +234,7 @@ func TestEvalModel_FailJudgment(t *testing.T) {
 
 	cases := []diffview.EvalCase{
 		{
+			ID:    "branch",
 			Input: diffview.ClassificationInput{Repo: "repo", Branch: "branch", Commits: []diffview.CommitBrief{{Hash: "abc123"}}},
 			Story: &diffview.StoryClassification{Summary: "Test story"},
 		},
@@ -260,8 +266,8 @@ func TestEvalModel_JudgmentUpdatesStatus(t *testing.T) {
 	t.Parallel()
 
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "branch-a", Commits: []diffview.CommitBrief{{Hash: "abc"}}}, Story: &diffview.StoryClassification{Summary: "Case A"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "branch-b", Commits: []diffview.CommitBrief{{Hash: "def"}}}, Story: &diffview.StoryClassification{Summary: "Case B"}},
+		{ID: "branch-a", Input: diffview.ClassificationInput{Repo: "repo", Branch: "branch-a", Commits: []diffview.CommitBrief{{Hash: "abc"}}}, Story: &diffview.StoryClassification{Summary: "Case A"}},
+		{ID: "branch-b", Input: diffview.ClassificationInput{Repo: "repo", Branch: "branch-b", Commits: []diffview.CommitBrief{{Hash: "def"}}}, Story: &diffview.StoryClassification{Summary: "Case B"}},
 	}
 
 	m := bubbletea.NewEvalModel(cases)
@@ -304,8 +310,8 @@ func TestEvalModel_StatusBarShowsCurrentCaseJudgment(t *testing.T) {
 	t.Parallel()
 
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+		{ID: "case2", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
 	}
 
 	m := bubbletea.NewEvalModel(cases)
@@ -346,16 +352,42 @@ func TestEvalModel_StatusBarShowsCurrentCaseJudgment(t *testing.T) {
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
 
+func TestEvalModel_StatusBarFlagsStoryIssues(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Summary: "Case 1",
+		Sections: []diffview.Section{
+			{Title: "Core", Hunks: []diffview.HunkRef{{File: "missing.go", Category: "core"}}},
+		},
+	}
+	cases := []diffview.EvalCase{
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: story},
+	}
+
+	m := bubbletea.NewEvalModel(cases)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(120, 40),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("⚠ 1 story issue(s)"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
 func TestEvalModel_StatusBarShowsPendingForCritiqueOnly(t *testing.T) {
 	t.Parallel()
 
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
 	}
 
 	// Pre-load with a critique-only judgment (has critique but no pass/fail yet)
 	judgments := []diffview.Judgment{
-		{CaseID: "repo/case1", Critique: "Some critique text"},
+		{CaseID: "case1", Critique: "Some critique text"},
 	}
 
 	m := bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(judgments))
@@ -383,16 +415,16 @@ func TestEvalModel_JumpToNextUnjudged(t *testing.T) {
 	t.Parallel()
 
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case3", Commits: []diffview.CommitBrief{{Hash: "case3"}}}, Story: &diffview.StoryClassification{Summary: "Case 3"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case4", Commits: []diffview.CommitBrief{{Hash: "case4"}}}, Story: &diffview.StoryClassification{Summary: "Case 4"}},
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+		{ID: "case2", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
+		{ID: "case3", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case3", Commits: []diffview.CommitBrief{{Hash: "case3"}}}, Story: &diffview.StoryClassification{Summary: "Case 3"}},
+		{ID: "case4", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case4", Commits: []diffview.CommitBrief{{Hash: "case4"}}}, Story: &diffview.StoryClassification{Summary: "Case 4"}},
 	}
 
 	// Pre-load judgments for cases 1 and 3 (indices 0 and 2)
 	judgments := []diffview.Judgment{
-		{CaseID: "repo/case1", Judged: true, Pass: true},
-		{CaseID: "repo/case3", Judged: true, Pass: false},
+		{CaseID: "case1", Judged: true, Pass: true},
+		{CaseID: "case3", Judged: true, Pass: false},
 	}
 
 	m := bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(judgments))
@@ -434,16 +466,16 @@ func TestEvalModel_JumpToPrevUnjudged(t *testing.T) {
 	t.Parallel()
 
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case3", Commits: []diffview.CommitBrief{{Hash: "case3"}}}, Story: &diffview.StoryClassification{Summary: "Case 3"}},
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case4", Commits: []diffview.CommitBrief{{Hash: "case4"}}}, Story: &diffview.StoryClassification{Summary: "Case 4"}},
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+		{ID: "case2", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
+		{ID: "case3", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case3", Commits: []diffview.CommitBrief{{Hash: "case3"}}}, Story: &diffview.StoryClassification{Summary: "Case 3"}},
+		{ID: "case4", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case4", Commits: []diffview.CommitBrief{{Hash: "case4"}}}, Story: &diffview.StoryClassification{Summary: "Case 4"}},
 	}
 
 	// Pre-load judgments for cases 1 and 3 (indices 0 and 2)
 	judgments := []diffview.Judgment{
-		{CaseID: "repo/case1", Judged: true, Pass: true},
-		{CaseID: "repo/case3", Judged: true, Pass: false},
+		{CaseID: "case1", Judged: true, Pass: true},
+		{CaseID: "case3", Judged: true, Pass: false},
 	}
 
 	m := bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(judgments))
@@ -487,12 +519,12 @@ func TestEvalModel_StoryPanelShowsFullCritique(t *testing.T) {
 	longCritique := "This is a very long critique that should be displayed in full in the story panel without any truncation. It contains multiple sentences and detailed feedback about the classification quality."
 
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Test Story"}},
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Test Story"}},
 	}
 
 	// Pre-load with a judgment that has a long critique
 	judgments := []diffview.Judgment{
-		{CaseID: "repo/case1", Judged: true, Pass: false, Critique: longCritique},
+		{CaseID: "case1", Judged: true, Pass: false, Critique: longCritique},
 	}
 
 	m := bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(judgments))
@@ -514,12 +546,12 @@ func TestEvalModel_JudgmentBarWithCritiqueOnly(t *testing.T) {
 	t.Parallel()
 
 	cases := []diffview.EvalCase{
-		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
 	}
 
 	// Case has critique but Judged is false (not explicitly passed/failed)
 	judgments := []diffview.Judgment{
-		{CaseID: "repo/case1", Judged: false, Critique: "Some critique text"},
+		{CaseID: "case1", Judged: false, Critique: "Some critique text"},
 	}
 
 	m := bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(judgments))
@@ -594,9 +626,10 @@ func TestEvalModel_CopyCaseToClipboard(t *testing.T) {
 	cases := []diffview.EvalCase{
 		{
 			Input: diffview.ClassificationInput{
-				Repo:    "test-repo",
-				Branch:  "feature-branch",
-				Commits: []diffview.CommitBrief{{Hash: "abc123", Message: "Add new feature"}},
+				Repo:      "test-repo",
+				Branch:    "feature-branch",
+				Commits:   []diffview.CommitBrief{{Hash: "abc123", Message: "Add new feature"}},
+				Reviewers: []diffview.Reviewer{{Name: "Ann"}},
 				Diff: diffview.Diff{
 					Files: []diffview.FileDiff{
 						{
@@ -624,6 +657,8 @@ func TestEvalModel_CopyCaseToClipboard(t *testing.T) {
 				ChangeType: "feature",
 				Narrative:  "core-periphery",
 				Summary:    "Added a new feature to the codebase",
+				Risk:       &diffview.Risk{Level: "medium", Breaking: []string{"Config key renamed"}},
+				Impact:     &diffview.Impact{CommitType: "feat", Bump: "minor", Added: []string{"main.Feature"}},
 				Sections: []diffview.Section{
 					{
 						Role:        "core",
@@ -672,6 +707,9 @@ func TestEvalModel_CopyCaseToClipboard(t *testing.T) {
 	assert.Contains(t, content, "Change Type: feature")
 	assert.Contains(t, content, "Narrative: core-periphery")
 	assert.Contains(t, content, "Added a new feature to the codebase")
+	assert.Contains(t, content, "Impact: feat, minor bump\n  Added: main.Feature\n")
+	assert.Contains(t, content, "Risk: medium\n  Breaking: Config key renamed\n")
+	assert.Contains(t, content, "Likely reviewers: Ann\n")
 	assert.Contains(t, content, "## Your Task")
 
 	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
@@ -697,6 +735,179 @@ func (m *mockClipboard) Content() string {
 	return m.content
 }
 
+// mockTombstoneStore captures appended tombstones for testing.
+type mockTombstoneStore struct {
+	mu         sync.Mutex
+	tombstones []diffview.Tombstone
+}
+
+func (m *mockTombstoneStore) Load(_ string) ([]diffview.Tombstone, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tombstones, nil
+}
+
+func (m *mockTombstoneStore) Append(_ string, t diffview.Tombstone) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tombstones = append(m.tombstones, t)
+	return nil
+}
+
+func (m *mockTombstoneStore) Tombstones() []diffview.Tombstone {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]diffview.Tombstone(nil), m.tombstones...)
+}
+
+func deletionTestCases() []diffview.EvalCase {
+	return []diffview.EvalCase{
+		{
+			ID:    "first",
+			Input: diffview.ClassificationInput{Repo: "repo", Branch: "first"},
+			Story: &diffview.StoryClassification{Summary: "First case summary"},
+		},
+		{
+			ID:    "second",
+			Input: diffview.ClassificationInput{Repo: "repo", Branch: "second"},
+			Story: &diffview.StoryClassification{Summary: "Second case summary"},
+		},
+	}
+}
+
+func TestEvalModel_DeleteCaseWithConfirmation(t *testing.T) {
+	t.Parallel()
+
+	store := &mockTombstoneStore{}
+	m := bubbletea.NewEvalModel(deletionTestCases(), bubbletea.WithTombstoneStore(store, "tombstones.jsonl"))
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(100, 40),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("First case"))
+	})
+
+	// 'D' asks for confirmation before deleting
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Delete repo/first from dataset?"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+
+	// Deleted case is removed and the next case takes its place
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Second case")) && bytes.Contains(out, []byte("case 1/1"))
+	})
+
+	tombstones := store.Tombstones()
+	if assert.Len(t, tombstones, 1) {
+		assert.Equal(t, "first", tombstones[0].CaseID)
+		assert.False(t, tombstones[0].DeletedAt.IsZero())
+	}
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestEvalModel_DeleteCaseReportsFailedTombstone(t *testing.T) {
+	t.Parallel()
+
+	store := &mock.TombstoneStore{AppendFn: func(string, diffview.Tombstone) error { return errors.New("disk full") }}
+	d := bubbletea.NewDriver(bubbletea.NewEvalModel(deletionTestCases(), bubbletea.WithTombstoneStore(store, "tombstones.jsonl")), 100, 40)
+	require.NoError(t, d.Press("D", "y"))
+
+	// The case stays, as it would in the next session
+	frame := d.Frame()
+	assert.Contains(t, frame, "deleting case: disk full")
+	assert.Contains(t, frame, "case 1/2")
+	assert.Len(t, d.Model().(bubbletea.EvalModel).Cases(), 2)
+}
+
+func TestEvalModel_DeleteCaseCancelled(t *testing.T) {
+	t.Parallel()
+
+	store := &mockTombstoneStore{}
+	m := bubbletea.NewEvalModel(deletionTestCases(), bubbletea.WithTombstoneStore(store, "tombstones.jsonl"))
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(100, 40),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("First case"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Delete repo/first from dataset?"))
+	})
+
+	// 'n' cancels and returns to review mode
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("case 1/2"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+
+	assert.Empty(t, store.Tombstones())
+}
+
+func TestEvalModel_DeleteDisabledWithoutTombstoneStore(t *testing.T) {
+	t.Parallel()
+
+	m := bubbletea.NewEvalModel(deletionTestCases())
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+
+	assert.NotContains(t, updated.View(), "from dataset?")
+	assert.Contains(t, updated.View(), "case 1/2")
+}
+
+func TestEvalModel_ReadOnlyIgnoresChanges(t *testing.T) {
+	t.Parallel()
+
+	judgments := []diffview.Judgment{{CaseID: "first", Judged: true, Pass: false, Critique: "too vague"}}
+	store := &mockTombstoneStore{}
+	m := bubbletea.NewEvalModel(deletionTestCases(),
+		bubbletea.WithExistingJudgments(judgments),
+		bubbletea.WithTombstoneStore(store, "tombstones.jsonl"),
+		bubbletea.WithReadOnly("session 2"),
+	)
+	var updated tea.Model = m
+	updated, _ = updated.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	for _, r := range "pcD" {
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	view := updated.View()
+	assert.Contains(t, view, "read-only session 2")
+	assert.Contains(t, view, "✗ fail")
+	assert.NotContains(t, view, "from dataset?")
+	assert.NotContains(t, view, "p/f judge")
+	assert.Equal(t, judgments, updated.(bubbletea.EvalModel).Judgments())
+	assert.Empty(t, store.Tombstones())
+}
+
+func TestEvalModel_JudgmentsAndCases(t *testing.T) {
+	t.Parallel()
+
+	m := bubbletea.NewEvalModel(deletionTestCases())
+	var updated tea.Model = m
+	updated, _ = updated.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+
+	em := updated.(bubbletea.EvalModel)
+	assert.Len(t, em.Cases(), 2)
+	judgments := em.Judgments()
+	require.Len(t, judgments, 1)
+	assert.Equal(t, "second", judgments[0].CaseID)
+	assert.True(t, judgments[0].Pass)
+}
+
 func TestEvalModel_JKScrollsDiffViewport(t *testing.T) {
 	t.Parallel()
 
@@ -769,6 +980,7 @@ func TestEvalModel_HelpOverlayShowsOnQuestionMark(t *testing.T) {
 
 	cases := []diffview.EvalCase{
 		{
+			ID:    "branch",
 			Input: diffview.ClassificationInput{Repo: "repo", Branch: "branch", Commits: []diffview.CommitBrief{{Hash: "abc"}}},
 			Story: &diffview.StoryClassification{Summary: "Test story"},
 		},
@@ -935,6 +1147,71 @@ func TestEvalModel_ToggleModeWithM(t *testing.T) {
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
 
+func TestEvalModel_RawModeAnnotatesHunksWithSection(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Repo:    "test-repo",
+				Branch:  "test-branch",
+				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
+				Diff: diffview.Diff{
+					Files: []diffview.FileDiff{
+						{
+							NewPath: "main.go",
+							Hunks: []diffview.Hunk{
+								{
+									Lines: []diffview.Line{
+										{Type: diffview.LineAdded, Content: "added line"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Story: &diffview.StoryClassification{
+				ChangeType: "feature",
+				Summary:    "Added new feature",
+				Sections: []diffview.Section{
+					{
+						Role:        "core",
+						Title:       "Main implementation",
+						Explanation: "Core logic for the feature",
+						Hunks:       []diffview.HunkRef{{File: "main.go", HunkIndex: 0}},
+					},
+				},
+			},
+		},
+	}
+
+	m := bubbletea.NewEvalModel(cases)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(100, 40),
+	)
+
+	// Story mode already shows the section, so hunks aren't annotated
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("section 1/1")) &&
+			!bytes.Contains(out, []byte("»"))
+	})
+
+	// Raw mode annotates each hunk with its section
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("» [core] Main implementation — Core logic for the feature"))
+	})
+
+	// 'a' hides the annotations
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	final := tm.FinalModel(t).View()
+	assert.NotContains(t, final, "»")
+	assert.Contains(t, final, "+added line")
+}
+
 func TestEvalModel_StoryModeShowsOnlyCurrentSectionHunks(t *testing.T) {
 	t.Parallel()
 
@@ -1454,10 +1731,10 @@ func TestEvalModel_FilteredDiffUsesOriginalHunkIndices(t *testing.T) {
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
 
-func TestEvalModel_TabTogglesViewMode(t *testing.T) {
+func TestEvalModel_DTogglesViewMode(t *testing.T) {
 	t.Parallel()
 
-	// Tab should toggle between story view and data view
+	// d should toggle between story view and data view
 	// Story view shows the split pane (metadata + diff)
 	// Data view shows the full classification tree
 
@@ -1504,8 +1781,8 @@ func TestEvalModel_TabTogglesViewMode(t *testing.T) {
 		return bytes.Contains(out, []byte("[story]"))
 	})
 
-	// Press Tab to switch to data view
-	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	// Press d to switch to data view
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
 
 	// Should now show [data] in footer and the classification tree
 	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
@@ -1513,8 +1790,8 @@ func TestEvalModel_TabTogglesViewMode(t *testing.T) {
 			bytes.Contains(out, []byte("change_type: feature"))
 	})
 
-	// Press Tab again to return to story view
-	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	// Press d again to return to story view
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
 
 	// Should show [story] again
 	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
@@ -1575,7 +1852,7 @@ func TestEvalModel_DataViewShowsFullClassificationTree(t *testing.T) {
 	})
 
 	// Switch to data view
-	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
 
 	// Should show classification tree elements
 	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
@@ -1707,7 +1984,7 @@ func TestEvalModel_DataViewScrolling(t *testing.T) {
 	})
 
 	// Switch to data view
-	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
 
 	// Should show data view with early sections visible
 	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
@@ -1739,3 +2016,570 @@ func TestEvalModel_DataViewScrolling(t *testing.T) {
 	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
+
+func TestEvalModel_PathsOnlyCaseBlocksDiff(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Repo:      "repo",
+				Branch:    "shared",
+				PathsOnly: true,
+				Diff: diffview.Diff{
+					Files: []diffview.FileDiff{
+						{
+							NewPath:   "main.go",
+							Operation: diffview.FileModified,
+							Hunks: []diffview.Hunk{
+								{NewStart: 1, NewCount: 1, Lines: []diffview.Line{{Type: diffview.LineAdded, NewLineNum: 1}}},
+							},
+						},
+					},
+				},
+			},
+			Story: &diffview.StoryClassification{Summary: "Shared summary"},
+		},
+	}
+
+	m := bubbletea.NewEvalModel(cases)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(100, 40),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Shared summary")) &&
+			bytes.Contains(out, []byte("Paths-only case"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestEvalModel_FindFileJumpsInRawMode(t *testing.T) {
+	t.Parallel()
+
+	fileWithLines := func(path string) diffview.FileDiff {
+		var lines []diffview.Line
+		for i := 1; i <= 10; i++ {
+			lines = append(lines, diffview.Line{Type: diffview.LineAdded, Content: fmt.Sprintf("%s line %d", path, i)})
+		}
+		return diffview.FileDiff{NewPath: path, Hunks: []diffview.Hunk{{Lines: lines}}}
+	}
+	cases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Repo:    "test-repo",
+				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
+				Diff: diffview.Diff{
+					Files: []diffview.FileDiff{fileWithLines("api.go"), fileWithLines("db.go")},
+				},
+			},
+			Story: &diffview.StoryClassification{
+				Sections: []diffview.Section{
+					{Role: "core", Title: "API", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+					{Role: "supporting", Title: "Storage", Hunks: []diffview.HunkRef{{File: "db.go", HunkIndex: 0}}},
+				},
+			},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewEvalModel(cases)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("db")})
+	assert.Contains(t, model.View(), "> db")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// The diff pane starts at db.go's header, past api.go's annotated hunk
+	view := model.View()
+	_, diffPane, found := strings.Cut(view, "DIFF")
+	require.True(t, found)
+	lines := strings.Split(diffPane, "\n")
+	require.Greater(t, len(lines), 1)
+	assert.Contains(t, lines[1], "db.go")
+	assert.NotContains(t, diffPane, "api.go line")
+}
+
+func TestEvalModel_StructuredCritique(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+	}
+
+	t.Run("records category and severity with the template as critique", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("c"))
+		frame := d.Frame()
+		assert.Contains(t, frame, "Category  [none]  wrong narrative  missing hunk")
+		assert.Contains(t, frame, "Severity  [none]  minor  major  critical")
+
+		require.NoError(t, d.Press("tab", "tab"))
+		frame = d.Frame()
+		assert.Contains(t, frame, "[missing hunk]")
+		assert.Contains(t, frame, "Hunk <file>:H<n> is in no section")
+
+		require.NoError(t, d.Press("shift+tab", "shift+tab", "esc"))
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		assert.Equal(t, diffview.CategoryMissingHunk, judgments[0].Category)
+		assert.Equal(t, diffview.SeverityMajor, judgments[0].Severity)
+		assert.Contains(t, judgments[0].Critique, "is in no section")
+		assert.Contains(t, d.Frame(), "Critique: [missing hunk, major]")
+	})
+
+	t.Run("keeps text the reviewer wrote when the category changes", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("c"))
+		d.Type("own words")
+		require.NoError(t, d.Press("tab", "esc"))
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		assert.Equal(t, "own words", judgments[0].Critique)
+		assert.Equal(t, diffview.CategoryWrongNarrative, judgments[0].Category)
+	})
+
+	t.Run("wraps back to none after the last option", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("c", "tab", "tab", "tab", "tab", "tab", "esc"))
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		assert.Empty(t, judgments[0].Category)
+		assert.Empty(t, judgments[0].Critique)
+	})
+}
+
+func TestEvalModel_EditGoldLabel(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Summary: "Fix the cache",
+		Sections: []diffview.Section{
+			{Role: "fix", Title: "The fix", Hunks: []diffview.HunkRef{
+				{File: "cache.go", HunkIndex: 0, Category: "core"},
+				{File: "cache_test.go", HunkIndex: 0, Category: "core"},
+			}},
+			{Role: "test", Title: "Tests", Hunks: []diffview.HunkRef{
+				{File: "store_test.go", HunkIndex: 1, Category: "core"},
+			}},
+		},
+	}
+	cases := []diffview.EvalCase{
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: story},
+	}
+
+	t.Run("saves moved hunks, titles and categories as the gold label", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("e"))
+		assert.Contains(t, d.Frame(), "EDIT SECTIONS")
+
+		// Move cache_test.go's hunk into the tests section and mark it noise
+		require.NoError(t, d.Press("j", "j", "J", "t", "t", "t"))
+		require.NoError(t, d.Press("k", "k", "r", "ctrl+u"))
+		d.Type("Cover the fix")
+		require.NoError(t, d.Press("enter", "esc"))
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		gold := judgments[0].Gold
+		require.NotNil(t, gold)
+		require.Len(t, gold.Sections, 2)
+		assert.Equal(t, []diffview.HunkRef{{File: "cache.go", HunkIndex: 0, Category: "core"}}, gold.Sections[0].Hunks)
+		assert.Equal(t, "Cover the fix", gold.Sections[1].Title)
+		assert.Equal(t, []diffview.HunkRef{
+			{File: "store_test.go", HunkIndex: 1, Category: "core"},
+			{File: "cache_test.go", HunkIndex: 0, Category: "noise"},
+		}, gold.Sections[1].Hunks)
+		assert.Len(t, story.Sections[0].Hunks, 2, "model output is left unchanged")
+		assert.Contains(t, d.Frame(), "Gold: edited")
+	})
+
+	t.Run("keeps the gold label when the case is judged", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("e", "r", "ctrl+u"))
+		d.Type("Renamed")
+		require.NoError(t, d.Press("enter", "esc", "f"))
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		assert.True(t, judgments[0].Judged)
+		require.NotNil(t, judgments[0].Gold)
+		assert.Equal(t, "Renamed", judgments[0].Gold.Sections[0].Title)
+	})
+
+	t.Run("drops sections left empty", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("e", "G", "K", "esc"))
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		require.Len(t, judgments[0].Gold.Sections, 1)
+		assert.Len(t, judgments[0].Gold.Sections[0].Hunks, 3)
+	})
+
+	t.Run("adds a section to move hunks into", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("e", "j", "o"))
+		d.Type("Cleanup")
+		require.NoError(t, d.Press("enter", "k", "J", "esc"))
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		gold := judgments[0].Gold
+		require.Len(t, gold.Sections, 3)
+		assert.Equal(t, "Cleanup", gold.Sections[1].Title)
+		assert.Equal(t, []diffview.HunkRef{{File: "cache_test.go", HunkIndex: 0, Category: "core"}}, gold.Sections[1].Hunks)
+		assert.Equal(t, "Tests", gold.Sections[2].Title)
+	})
+
+	t.Run("records nothing when the story is left as the model wrote it", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("e", "j", "t", "x", "esc"))
+
+		assert.Empty(t, d.Model().(bubbletea.EvalModel).Judgments())
+	})
+
+	t.Run("is unavailable read-only", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithReadOnly("snapshot")), 120, 40)
+		require.NoError(t, d.Press("e"))
+		assert.NotContains(t, d.Frame(), "EDIT SECTIONS")
+	})
+}
+
+func TestEvalModel_PromoteGold(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Summary: "Fix the cache",
+		Sections: []diffview.Section{
+			{Role: "fix", Title: "The fix", Hunks: []diffview.HunkRef{{File: "cache.go", HunkIndex: 0, Category: "core"}}},
+		},
+	}
+	cases := []diffview.EvalCase{
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: story},
+	}
+	retitled := []string{"e", "r", "ctrl+u"}
+
+	t.Run("saves the case with the edits as its gold label", func(t *testing.T) {
+		t.Parallel()
+
+		var saved []diffview.EvalCase
+		saver := &mock.EvalCaseSaver{SaveFn: func(path string, c diffview.EvalCase) error {
+			assert.Equal(t, "gold.jsonl", path)
+			saved = append(saved, c)
+			return nil
+		}}
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithGoldSaver(saver, "gold.jsonl")), 120, 40)
+		require.NoError(t, d.Press(retitled...))
+		d.Type("Invalidate on write")
+		require.NoError(t, d.Press("enter", "esc"))
+		assert.Contains(t, d.Frame(), "Gold: edited")
+
+		require.NoError(t, d.Press("P"))
+		require.Len(t, saved, 1)
+		require.NotNil(t, saved[0].Gold)
+		assert.Equal(t, "Invalidate on write", saved[0].Gold.Sections[0].Title)
+		assert.Equal(t, story, saved[0].Story)
+		assert.Contains(t, d.Frame(), "Gold: promoted")
+
+		// The edits moved from the judgment to the case
+		model := d.Model().(bubbletea.EvalModel)
+		require.NotNil(t, model.Cases()[0].Gold)
+		assert.Equal(t, "Invalidate on write", model.Cases()[0].Gold.Sections[0].Title)
+		require.Len(t, model.Judgments(), 1)
+		assert.Nil(t, model.Judgments()[0].Gold)
+
+		// Nothing new to promote
+		require.NoError(t, d.Press("P"))
+		assert.Len(t, saved, 1)
+	})
+
+	t.Run("edits a promoted gold label back to it without anything to promote", func(t *testing.T) {
+		t.Parallel()
+
+		gold := *story
+		gold.Sections = []diffview.Section{
+			{Role: "fix", Title: "Invalidate on write", Hunks: story.Sections[0].Hunks},
+		}
+		promoted := []diffview.EvalCase{{Input: cases[0].Input, Story: story, Gold: &gold}}
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(promoted), 120, 40)
+
+		require.NoError(t, d.Press("e", "esc"))
+		assert.Empty(t, d.Model().(bubbletea.EvalModel).Judgments())
+		assert.Contains(t, d.Frame(), "Gold: promoted")
+	})
+
+	t.Run("keeps the edits unpromoted if the case couldn't be saved", func(t *testing.T) {
+		t.Parallel()
+
+		saver := &mock.EvalCaseSaver{SaveFn: func(string, diffview.EvalCase) error { return errors.New("disk full") }}
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithGoldSaver(saver, "gold.jsonl")), 120, 40)
+		require.NoError(t, d.Press(retitled...))
+		d.Type("Other")
+		require.NoError(t, d.Press("enter", "esc", "P"))
+
+		model := d.Model().(bubbletea.EvalModel)
+		assert.Nil(t, model.Cases()[0].Gold)
+		require.Len(t, model.Judgments(), 1)
+		assert.NotNil(t, model.Judgments()[0].Gold)
+		assert.Contains(t, d.Frame(), "Gold: edited")
+	})
+
+	t.Run("compares the model's story with the gold one", func(t *testing.T) {
+		t.Parallel()
+
+		gold := *story
+		gold.Sections = []diffview.Section{
+			{Role: "fix", Title: "Invalidate on write", Hunks: story.Sections[0].Hunks},
+		}
+		promoted := []diffview.EvalCase{{Input: cases[0].Input, Story: story, Gold: &gold}}
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(promoted), 120, 40)
+		assert.Contains(t, d.Frame(), "Gold: promoted")
+
+		require.NoError(t, d.Press("v"))
+		frame := d.Frame()
+		assert.Contains(t, frame, "DATA: model → gold")
+		assert.Contains(t, frame, "[gold diff]")
+		assert.Contains(t, frame, "- ")
+		assert.Contains(t, frame, "The fix")
+		assert.Contains(t, frame, "+ ")
+		assert.Contains(t, frame, "Invalidate on write")
+
+		require.NoError(t, d.Press("v"))
+		assert.NotContains(t, d.Frame(), "model → gold")
+	})
+
+	t.Run("has nothing to compare without a gold label", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 40)
+		require.NoError(t, d.Press("v"))
+		assert.NotContains(t, d.Frame(), "model → gold")
+	})
+}
+
+func TestRenderStoryComparison(t *testing.T) {
+	t.Parallel()
+
+	model := &diffview.StoryClassification{Summary: "Fix", Sections: []diffview.Section{
+		{Role: "fix", Title: "Old title", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0, Category: "core"}}},
+	}}
+	gold := &diffview.StoryClassification{Summary: "Fix", Sections: []diffview.Section{
+		{Role: "fix", Title: "New title", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0, Category: "core"}}},
+	}}
+
+	var removed, added []string
+	for _, line := range strings.Split(bubbletea.RenderStoryComparison(model, gold, 80), "\n") {
+		switch {
+		case strings.HasPrefix(line, "- "):
+			removed = append(removed, line)
+		case strings.HasPrefix(line, "+ "):
+			added = append(added, line)
+		}
+	}
+	require.Len(t, removed, 1)
+	require.Len(t, added, 1)
+	assert.Contains(t, removed[0], "Old title")
+	assert.Contains(t, added[0], "New title")
+}
+
+func TestEvalModel_AutoJudgments(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{ID: "case1", Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+	}
+	auto := []diffview.Judgment{{CaseID: "case1", Judged: true, Pass: false, Critique: "Mixed sections", Judge: "grader"}}
+
+	t.Run("marks judgments made by a grader", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(auto)), 120, 40)
+		frame := d.Frame()
+		assert.Contains(t, frame, "● Fail  (auto: grader)")
+		assert.Contains(t, frame, "✗ fail (auto)")
+	})
+
+	t.Run("judging the case makes the judgment the reviewer's", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(auto)), 120, 40)
+		require.NoError(t, d.Press("p"))
+
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		assert.Empty(t, judgments[0].Judge)
+		assert.Equal(t, "Mixed sections", judgments[0].Critique)
+		assert.NotContains(t, d.Frame(), "(auto")
+	})
+
+	t.Run("reading the critique leaves it the grader's", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments(auto)), 120, 40)
+		require.NoError(t, d.Press("c", "esc"))
+		assert.Equal(t, "grader", d.Model().(bubbletea.EvalModel).Judgments()[0].Judge)
+
+		require.NoError(t, d.Press("c"))
+		d.Type(" and missing tests")
+		require.NoError(t, d.Press("esc"))
+		assert.Empty(t, d.Model().(bubbletea.EvalModel).Judgments()[0].Judge)
+	})
+}
+
+func TestEvalModel_Panes(t *testing.T) {
+	t.Parallel()
+
+	var lines []diffview.Line
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, diffview.Line{Type: diffview.LineAdded, Content: fmt.Sprintf("diff line %d", i)})
+	}
+	var explanation []string
+	for i := 1; i <= 30; i++ {
+		explanation = append(explanation, fmt.Sprintf("story line %d", i))
+	}
+	cases := []diffview.EvalCase{{
+		Input: diffview.ClassificationInput{
+			Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}},
+			Diff: diffview.Diff{Files: []diffview.FileDiff{{
+				NewPath: "a.go", Operation: diffview.FileAdded,
+				Hunks: []diffview.Hunk{{NewStart: 1, NewCount: 30, Lines: lines}},
+			}}},
+		},
+		Story: &diffview.StoryClassification{Summary: strings.Join(explanation, "\n")},
+	}}
+	newDriver := func(width int) *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewEvalModel(cases), width, 30)
+	}
+
+	t.Run("tab moves the scroll keys to the story pane", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(100)
+		assert.Contains(t, d.Frame(), "▸ DIFF")
+
+		require.NoError(t, d.Press("tab", "G"))
+		frame := d.Frame()
+		assert.Contains(t, frame, "▸ STORY")
+		assert.Contains(t, frame, "story line 30")
+		assert.Contains(t, frame, "diff line 1", "the diff stays put")
+
+		require.NoError(t, d.Press("tab", "G"))
+		assert.Contains(t, d.Frame(), "diff line 30")
+	})
+
+	t.Run("s hides the story pane and gives its rows to the diff", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(100)
+		require.NoError(t, d.Press("tab", "s"))
+		frame := d.Frame()
+		assert.NotContains(t, frame, "STORY")
+		assert.Contains(t, frame, "DIFF")
+		assert.NotContains(t, frame, "▸", "the diff is the only pane")
+		assert.Contains(t, frame, "diff line 24")
+
+		require.NoError(t, d.Press("G"))
+		assert.Contains(t, d.Frame(), "diff line 30", "keys act on the diff")
+
+		require.NoError(t, d.Press("s"))
+		assert.Contains(t, d.Frame(), "STORY")
+	})
+
+	t.Run("L puts the story beside the diff on wide terminals", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(140)
+		require.NoError(t, d.Press("L"))
+		lines := strings.Split(d.Frame(), "\n")
+		assert.Contains(t, lines[0], "STORY")
+		assert.Contains(t, lines[0], "DIFF")
+		assert.Contains(t, lines[1], "│")
+
+		narrow := newDriver(100)
+		require.NoError(t, narrow.Press("L"))
+		assert.NotContains(t, strings.Split(narrow.Frame(), "\n")[0], "DIFF")
+	})
+}
+
+func TestEvalModel_Preferences(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{{
+		Input: diffview.ClassificationInput{
+			Repo:    "test-repo",
+			Commits: []diffview.CommitBrief{{Hash: "abc123"}},
+			Diff: diffview.Diff{Files: []diffview.FileDiff{{
+				NewPath: "main.go",
+				Hunks:   []diffview.Hunk{{Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "added line"}}}},
+			}}},
+		},
+		Story: &diffview.StoryClassification{Sections: []diffview.Section{
+			{Role: "core", Title: "Main", Hunks: []diffview.HunkRef{{File: "main.go", HunkIndex: 0}}},
+		}},
+	}}
+
+	t.Run("restores the saved mode", func(t *testing.T) {
+		t.Parallel()
+
+		store := &mock.PreferencesStore{
+			LoadFn: func() (*diffview.Preferences, error) {
+				return &diffview.Preferences{RawMode: true}, nil
+			},
+		}
+		var model tea.Model = bubbletea.NewEvalModel(cases, bubbletea.WithPreferencesStore(store))
+		model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+		assert.NotContains(t, model.View(), "section 1/1")
+	})
+
+	t.Run("saves changes on quit", func(t *testing.T) {
+		t.Parallel()
+
+		var saved *diffview.Preferences
+		store := &mock.PreferencesStore{
+			LoadFn: func() (*diffview.Preferences, error) {
+				return &diffview.Preferences{SplitRatio: 50}, nil
+			},
+			SaveFn: func(prefs diffview.Preferences) error {
+				saved = &prefs
+				return nil
+			},
+		}
+		var model tea.Model = bubbletea.NewEvalModel(cases, bubbletea.WithPreferencesStore(store))
+		model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+		for _, r := range "+mW" {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		assert.Nil(t, saved, "nothing is saved until quitting")
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+		require.NotNil(t, cmd)
+		require.NotNil(t, saved)
+		assert.Equal(t, diffview.Preferences{SplitRatio: 60, RawMode: true, NoWordDiff: true}, *saved)
+	})
+}
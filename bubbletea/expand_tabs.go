@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fwojciec/diffstory"
 )
 
 // ExpandTabs converts tab characters to the appropriate number of spaces
@@ -11,6 +12,11 @@ import (
 // the column position where the string begins, which affects how the first
 // tab is expanded.
 func ExpandTabs(s string, startCol int) string {
+	return expandTabsWidth(s, startCol, tabWidth)
+}
+
+// expandTabsWidth is ExpandTabs with a configurable tab stop interval.
+func expandTabsWidth(s string, startCol, width int) string {
 	if !strings.Contains(s, "\t") {
 		return s
 	}
@@ -19,7 +25,7 @@ func ExpandTabs(s string, startCol int) string {
 	col := startCol
 	for _, r := range s {
 		if r == '\t' {
-			nextStop := ((col / tabWidth) + 1) * tabWidth
+			nextStop := ((col / width) + 1) * width
 			spaces := nextStop - col
 			sb.WriteString(strings.Repeat(" ", spaces))
 			col = nextStop
@@ -30,3 +36,30 @@ func ExpandTabs(s string, startCol int) string {
 	}
 	return sb.String()
 }
+
+// expandLineTabs returns lines with tabs in their content expanded to spaces
+// at the given tab width. Tab stops are measured from the start of the
+// content, as in an editor, so the diff prefix doesn't shift indentation.
+// Expanding before tokenizing and word diffing keeps every later width
+// calculation and token offset in terms of the expanded text. Lines without
+// tabs are shared with the input.
+func expandLineTabs(lines []diffview.Line, width int) []diffview.Line {
+	if width <= 0 {
+		width = tabWidth
+	}
+	var expanded []diffview.Line
+	for i, line := range lines {
+		if !strings.Contains(line.Content, "\t") {
+			continue
+		}
+		if expanded == nil {
+			expanded = make([]diffview.Line, len(lines))
+			copy(expanded, lines)
+		}
+		expanded[i].Content = expandTabsWidth(line.Content, 0, width)
+	}
+	if expanded == nil {
+		return lines
+	}
+	return expanded
+}
@@ -0,0 +1,22 @@
+package bubbletea
+
+import (
+	"fmt"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// ParseFileGroups parses a file grouping setting such as a flag or
+// environment value: "package" groups files by directory, "top" by
+// top-level directory, and "none" or an empty string leaves them ungrouped.
+func ParseFileGroups(s string) (diffview.GroupBy, error) {
+	switch s {
+	case "", "none":
+		return diffview.GroupNone, nil
+	case "package":
+		return diffview.GroupPackage, nil
+	case "top":
+		return diffview.GroupTopLevel, nil
+	}
+	return diffview.GroupNone, fmt.Errorf("invalid grouping %q: must be none, package or top", s)
+}
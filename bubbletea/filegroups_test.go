@@ -0,0 +1,29 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFileGroups(t *testing.T) {
+	t.Parallel()
+
+	for s, want := range map[string]diffview.GroupBy{
+		"":        diffview.GroupNone,
+		"none":    diffview.GroupNone,
+		"package": diffview.GroupPackage,
+		"top":     diffview.GroupTopLevel,
+	} {
+		by, err := bubbletea.ParseFileGroups(s)
+
+		require.NoError(t, err, s)
+		assert.Equal(t, want, by, s)
+	}
+
+	_, err := bubbletea.ParseFileGroups("dir")
+	assert.EqualError(t, err, `invalid grouping "dir": must be none, package or top`)
+}
@@ -0,0 +1,209 @@
+package bubbletea
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fuzzy match scoring, loosely following fzf: every matched character scores,
+// with bonuses for runs of consecutive matches, matches at word boundaries,
+// and matches in the file name rather than its directory.
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 8
+	bonusBoundary    = 8
+	bonusBasename    = 4
+	penaltyGap       = 1
+)
+
+// fuzzyMatch reports whether every rune of pattern appears in text in order,
+// ignoring case, and returns a score (higher is better) and the byte offsets
+// of the matched runes in text.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+	want := []rune(strings.ToLower(pattern))
+	base := strings.LastIndex(text, "/") + 1
+
+	p := 0
+	prevMatch := -2
+	prevRune := rune(0)
+	runeIdx := 0
+	for i, r := range text {
+		if p < len(want) && unicode.ToLower(r) == want[p] {
+			score += scoreMatch
+			if prevMatch == runeIdx-1 {
+				score += bonusConsecutive
+			} else if prevMatch >= 0 {
+				score -= penaltyGap * (runeIdx - prevMatch - 1)
+			}
+			if i == 0 || strings.ContainsRune("/._- ", prevRune) {
+				score += bonusBoundary
+			}
+			if i >= base {
+				score += bonusBasename
+			}
+			positions = append(positions, i)
+			prevMatch = runeIdx
+			p++
+		}
+		prevRune = r
+		runeIdx++
+	}
+	if p < len(want) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// fileMatch is a file path matching the finder query.
+type fileMatch struct {
+	index     int   // position in the finder's file list
+	score     int   // fuzzyMatch score
+	positions []int // byte offsets of matched characters, for highlighting
+}
+
+// fileFinder is a fuzzy "jump to file" overlay. Typing narrows the list of
+// changed files; enter picks the selected file and esc cancels.
+type fileFinder struct {
+	active   bool
+	files    []string
+	query    string
+	matches  []fileMatch
+	selected int
+}
+
+// newFileFinder returns an active finder listing files in diff order.
+func newFileFinder(files []string) fileFinder {
+	f := fileFinder{active: true, files: files}
+	f.filter()
+	return f
+}
+
+// filter recomputes matches for the current query, best first. Ties keep
+// shorter paths first, then diff order.
+func (f *fileFinder) filter() {
+	var matches []fileMatch
+	for i, path := range f.files {
+		if score, positions, ok := fuzzyMatch(f.query, path); ok {
+			matches = append(matches, fileMatch{index: i, score: score, positions: positions})
+		}
+	}
+	f.matches = matches
+	if f.query != "" {
+		sort.SliceStable(f.matches, func(a, b int) bool {
+			ma, mb := f.matches[a], f.matches[b]
+			if ma.score != mb.score {
+				return ma.score > mb.score
+			}
+			return len(f.files[ma.index]) < len(f.files[mb.index])
+		})
+	}
+	f.selected = 0
+}
+
+// update handles a key press while the finder is open. When the finder
+// closes, done is true and picked is the chosen file's index, or -1 if the
+// finder was cancelled or nothing matched.
+func (f *fileFinder) update(msg tea.KeyMsg) (done bool, picked int) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		f.active = false
+		return true, -1
+	case tea.KeyEnter:
+		f.active = false
+		if len(f.matches) == 0 {
+			return true, -1
+		}
+		return true, f.matches[f.selected].index
+	case tea.KeyUp, tea.KeyCtrlP:
+		if f.selected > 0 {
+			f.selected--
+		}
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyTab:
+		if f.selected < len(f.matches)-1 {
+			f.selected++
+		}
+	case tea.KeyBackspace:
+		if f.query != "" {
+			runes := []rune(f.query)
+			f.query = string(runes[:len(runes)-1])
+			f.filter()
+		}
+	case tea.KeyCtrlU:
+		f.query = ""
+		f.filter()
+	case tea.KeyRunes, tea.KeySpace:
+		f.query += string(msg.Runes)
+		f.filter()
+	}
+	return false, -1
+}
+
+// finderStyles holds the styles used to draw the finder overlay.
+type finderStyles struct {
+	prompt   lipgloss.Style // query line
+	item     lipgloss.Style // unselected file
+	selected lipgloss.Style // file under the cursor
+	match    lipgloss.Style // matched characters
+	count    lipgloss.Style // "N/M" match count
+}
+
+// view renders the finder in a width × height area: a query prompt followed
+// by as many matches as fit, scrolled to keep the selection visible.
+func (f fileFinder) view(width, height int, st finderStyles) string {
+	var sb strings.Builder
+	prompt := st.prompt.Render("> "+f.query) + " " +
+		st.count.Render(fmt.Sprintf("%d/%d", len(f.matches), len(f.files)))
+	sb.WriteString(prompt)
+
+	rows := max(height-1, 0)
+	start := 0
+	if f.selected >= rows {
+		start = f.selected - rows + 1
+	}
+	for i := start; i < len(f.matches) && i < start+rows; i++ {
+		m := f.matches[i]
+		style := st.item
+		marker := "  "
+		if i == f.selected {
+			style = st.selected
+			marker = "▌ "
+		}
+		sb.WriteString("\n")
+		sb.WriteString(style.Render(marker))
+		sb.WriteString(highlightMatches(f.files[m.index], m.positions, style, st.match.Inherit(style)))
+		// The selection bar spans the full width
+		if pad := width - lipgloss.Width(marker) - lipgloss.Width(f.files[m.index]); pad > 0 && i == f.selected {
+			sb.WriteString(style.Render(strings.Repeat(" ", pad)))
+		}
+	}
+	return lipgloss.NewStyle().Height(height).MaxHeight(height).MaxWidth(width).Render(sb.String())
+}
+
+// highlightMatches renders path with the runes at positions in matchStyle
+// and the rest in style.
+func highlightMatches(path string, positions []int, style, matchStyle lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(path)
+	}
+	var sb strings.Builder
+	next := 0
+	last := 0
+	for i, r := range path {
+		if next < len(positions) && positions[next] == i {
+			sb.WriteString(style.Render(path[last:i]))
+			sb.WriteString(matchStyle.Render(string(r)))
+			next++
+			last = i + len(string(r))
+		}
+	}
+	sb.WriteString(style.Render(path[last:]))
+	return sb.String()
+}
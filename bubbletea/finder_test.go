@@ -0,0 +1,103 @@
+package bubbletea_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// multiFileDiff returns a diff with one ten-line hunk per path, so each file
+// is taller than a small test terminal.
+func multiFileDiff(paths ...string) *diffview.Diff {
+	diff := &diffview.Diff{}
+	for _, path := range paths {
+		var lines []diffview.Line
+		for i := 1; i <= 10; i++ {
+			lines = append(lines, diffview.Line{
+				Type:       diffview.LineAdded,
+				Content:    fmt.Sprintf("line %d of %s\n", i, path),
+				NewLineNum: i,
+			})
+		}
+		diff.Files = append(diff.Files, diffview.FileDiff{
+			NewPath:   path,
+			Operation: diffview.FileAdded,
+			Hunks:     []diffview.Hunk{{NewStart: 1, NewCount: 10, Lines: lines}},
+		})
+	}
+	return diff
+}
+
+func sendKeys(t *testing.T, model tea.Model, keys ...tea.KeyMsg) tea.Model {
+	t.Helper()
+	for _, k := range keys {
+		model, _ = model.Update(k)
+	}
+	return model
+}
+
+func typeText(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModel_FindFileJumpsToFuzzyMatch(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("cmd/main.go", "internal/server/handler.go", "internal/store/store.go")
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	model = sendKeys(t, model, tea.KeyMsg{Type: tea.KeyCtrlP}, typeText("hndlr"))
+
+	// The overlay lists only the matching file
+	view := model.View()
+	assert.Contains(t, view, "> hndlr")
+	assert.Contains(t, view, "1/3")
+	assert.Contains(t, view, "internal/server/handler.go")
+	assert.NotContains(t, view, "cmd/main.go")
+
+	model = sendKeys(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	// The picked file's header is now at the top of the viewport
+	firstLine := strings.SplitN(model.View(), "\n", 2)[0]
+	assert.Contains(t, firstLine, "internal/server/handler.go")
+}
+
+func TestModel_FindFileEscapeKeepsPosition(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("a.go", "b.go")
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	model = sendKeys(t, model, typeText(":"), typeText("b"), tea.KeyMsg{Type: tea.KeyEsc})
+
+	firstLine := strings.SplitN(model.View(), "\n", 2)[0]
+	assert.Contains(t, firstLine, "a.go")
+
+	// Keys act on the viewer again once the finder closes
+	model = sendKeys(t, model, typeText("]"))
+	firstLine = strings.SplitN(model.View(), "\n", 2)[0]
+	assert.Contains(t, firstLine, "b.go")
+}
+
+func TestModel_FindFilePrefersBasenameMatches(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("store/cache/util.go", "pkg/cache.go")
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	model = sendKeys(t, model, tea.KeyMsg{Type: tea.KeyCtrlP}, typeText("cache"), tea.KeyMsg{Type: tea.KeyEnter})
+
+	firstLine := strings.SplitN(model.View(), "\n", 2)[0]
+	assert.Contains(t, firstLine, "pkg/cache.go")
+}
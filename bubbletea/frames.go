@@ -0,0 +1,22 @@
+package bubbletea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// StoryFrames renders the story as the viewer shows it on a width by height
+// screen: the intro slide, then each section in turn. Sections taller than
+// the screen are cut off where the viewer would need scrolling.
+func StoryFrames(diff *diffview.Diff, story *diffview.StoryClassification, width, height int, opts ...StoryModelOption) []string {
+	opts = append(opts, WithIntroSlide())
+	model, _ := NewStoryModel(diff, story, opts...).Update(tea.WindowSizeMsg{Width: width, Height: height})
+	m := model.(StoryModel)
+
+	frames := []string{m.View()}
+	for range m.totalSections() - 1 {
+		m.gotoNextSection()
+		frames = append(frames, m.View())
+	}
+	return frames
+}
@@ -0,0 +1,43 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoryFrames(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				NewPath:   "b/a.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{OldStart: 1, OldCount: 1, NewStart: 1, NewCount: 1, Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "FIRST_CODE"}}},
+					{OldStart: 9, OldCount: 1, NewStart: 9, NewCount: 1, Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "SECOND_CODE"}}},
+				},
+			},
+		},
+	}
+	story := &diffview.StoryClassification{
+		Summary: "Walkthrough summary",
+		Sections: []diffview.Section{
+			{Role: "core", Title: "First", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}},
+			{Role: "test", Title: "Second", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 1}}},
+		},
+	}
+
+	frames := bubbletea.StoryFrames(diff, story, 80, 24)
+
+	require.Len(t, frames, 3)
+	assert.Contains(t, ansi.Strip(frames[0]), "Walkthrough summary")
+	assert.Contains(t, ansi.Strip(frames[1]), "FIRST_CODE")
+	assert.NotContains(t, ansi.Strip(frames[1]), "SECOND_CODE")
+	assert.Contains(t, ansi.Strip(frames[2]), "SECOND_CODE")
+}
@@ -0,0 +1,122 @@
+package bubbletea
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// goldStory returns the current case's gold classification: the reviewer's
+// unpromoted edits if there are any, else the promoted gold label.
+func (m EvalModel) goldStory() *diffview.StoryClassification {
+	if len(m.cases) == 0 {
+		return nil
+	}
+	c := m.cases[m.currentIndex]
+	if j := m.judgments[c.CaseID()]; j != nil && j.Gold != nil {
+		return j.Gold
+	}
+	return c.Gold
+}
+
+// unpromoted reports whether the current case has edits not yet promoted
+// to its gold label.
+func (m EvalModel) unpromoted() bool {
+	if len(m.cases) == 0 {
+		return false
+	}
+	j := m.judgments[m.cases[m.currentIndex].CaseID()]
+	return j != nil && j.Gold != nil
+}
+
+// promoteGold moves the reviewer's edits from the current case's judgment
+// to its gold label and records the case in the gold log. The case is left
+// as it was if the log couldn't be written.
+func (m *EvalModel) promoteGold() tea.Cmd {
+	if m.goldSaver == nil || !m.unpromoted() {
+		return nil
+	}
+	c := m.cases[m.currentIndex]
+	caseID := c.CaseID()
+	c.Gold = m.judgments[caseID].Gold
+	if err := m.goldSaver.Save(m.goldPath, c); err != nil {
+		return notifyErr("promoting gold label", err)
+	}
+	// Copy rather than update in place: the slices are shared with the
+	// caller and the edit history
+	cases := append([]diffview.EvalCase(nil), m.cases...)
+	cases[m.currentIndex] = c
+	m.cases = cases
+	j := copyJudgment(m.judgments[caseID])
+	j.Gold = nil
+	m.judgments[caseID] = j
+	m.updateViewportContent()
+	return tea.Batch(m.persistJudgments(), notify("gold label promoted"))
+}
+
+// comparingGold reports whether the data view shows the model's
+// classification against the gold one.
+func (m EvalModel) comparingGold() bool {
+	return m.compareGold && m.viewMode == ViewData && m.goldStory() != nil
+}
+
+// RenderStoryComparison shows how gold differs from the model's story as a
+// line diff of their data views: removed lines start with "- ", added lines
+// with "+ " and unchanged ones with two spaces.
+func RenderStoryComparison(model, gold *diffview.StoryClassification, width int) string {
+	var s strings.Builder
+	for _, op := range diffLines(dataViewLines(model, width), dataViewLines(gold, width)) {
+		s.WriteString(string(op.kind) + " " + op.text + "\n")
+	}
+	return s.String()
+}
+
+// dataViewLines returns the lines of story's data view.
+func dataViewLines(story *diffview.StoryClassification, width int) []string {
+	return strings.Split(strings.TrimRight(RenderDataView(story, width), "\n"), "\n")
+}
+
+// lineOp is a line of a line diff: kept (' '), removed ('-') or added ('+').
+type lineOp struct {
+	kind byte
+	text string
+}
+
+// diffLines returns a shortest line diff turning before into after, with
+// removals ahead of the additions that replace them.
+func diffLines(before, after []string) []lineOp {
+	// lcs[i][j] is the length of the longest common subsequence of
+	// before[i:] and after[j:]
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []lineOp
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			ops = append(ops, lineOp{' ', before[i]})
+			i++
+			j++
+		case i < len(before) && (j == len(after) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, lineOp{'-', before[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', after[j]})
+			j++
+		}
+	}
+	return ops
+}
@@ -0,0 +1,75 @@
+package bubbletea
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// groupHunks returns grouper's groups for diff, or none without a grouper.
+func groupHunks(diff *diffview.Diff, grouper diffview.HunkGrouper) []diffview.HunkGroup {
+	if diff == nil || grouper == nil {
+		return nil
+	}
+	return grouper.GroupHunks(diff)
+}
+
+// renderedHunks returns the positions of a group's hunks among the rendered
+// hunks, from 1 as in currentHunkPosition.
+func renderedHunks(diff *diffview.Diff, group diffview.HunkGroup) []int {
+	type span struct{ first, count int }
+	files := make(map[string]span) // rendered hunks of each file, by ref path
+	n := 1
+	for _, file := range diff.Files {
+		if !file.Visible() {
+			continue
+		}
+		path := file.NewPath
+		if path == "" {
+			path = file.OldPath
+		}
+		files[path] = span{first: n, count: len(file.Hunks)}
+		n += len(file.Hunks)
+	}
+	var hunks []int
+	for _, ref := range group.Hunks {
+		if f, ok := files[ref.File]; ok && ref.HunkIndex >= 0 && ref.HunkIndex < f.count {
+			hunks = append(hunks, f.first+ref.HunkIndex)
+		}
+	}
+	return hunks
+}
+
+// gotoRelated scrolls to the next hunk, or previous for a negative delta,
+// that touches the same symbol as the current hunk, wrapping around. It
+// keeps to the group it last moved through while the current hunk is in
+// it, so repeated presses visit one symbol's hunks.
+func (m *Model) gotoRelated(delta int) {
+	current, _ := m.currentHunkPosition()
+	if current == 0 {
+		return
+	}
+	order := make([]int, 0, len(m.groups))
+	if m.group < len(m.groups) {
+		order = append(order, m.group)
+	}
+	for i := range m.groups {
+		if i != m.group {
+			order = append(order, i)
+		}
+	}
+	for _, i := range order {
+		hunks := renderedHunks(m.diff, m.groups[i])
+		at := slices.Index(hunks, current)
+		if at < 0 || len(hunks) < 2 {
+			continue
+		}
+		next := (at + delta + len(hunks)) % len(hunks)
+		m.group = i
+		m.viewport.SetYOffset(m.layout.Hunks[hunks[next]-1])
+		m.notice = fmt.Sprintf("%s: hunk %d of %d", m.groups[i].Symbol, next+1, len(hunks))
+		return
+	}
+	m.notice = "no other hunks touch this hunk's symbols"
+}
@@ -0,0 +1,51 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModel_RelatedHunks(t *testing.T) {
+	t.Parallel()
+
+	grouper := &mock.HunkGrouper{
+		GroupHunksFn: func(diff *diffview.Diff) []diffview.HunkGroup {
+			return []diffview.HunkGroup{
+				{Symbol: "Start", Hunks: []diffview.HunkRef{{File: "a.go"}, {File: "c.go"}, {File: "d.go"}}},
+			}
+		},
+	}
+	newDriver := func() *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go", "c.go", "d.go"), bubbletea.WithHunkGrouper(grouper)), 80, 8)
+	}
+
+	t.Run("s and S move between hunks touching the same symbol", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("s"))
+		assert.Contains(t, d.Frame(), "line 1 of c.go")
+		assert.Contains(t, statusBar(d), "Start: hunk 2 of 3")
+		require.NoError(t, d.Press("s", "s"))
+		assert.Contains(t, d.Frame(), "line 1 of a.go", "wraps around")
+		require.NoError(t, d.Press("S"))
+		assert.Contains(t, d.Frame(), "line 1 of d.go")
+		assert.Contains(t, statusBar(d), "Start: hunk 3 of 3")
+	})
+
+	t.Run("says when no other hunk touches the current hunk's symbols", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("n", "s"))
+		assert.Contains(t, d.Frame(), "line 1 of b.go")
+		assert.Contains(t, statusBar(d), "no other hunks touch this hunk's symbols")
+	})
+}
@@ -0,0 +1,212 @@
+package bubbletea
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// defaultCommandTimeout is how long a Driver waits for a command to return
+// its message. Commands still running after it, such as timers, are dropped.
+const defaultCommandTimeout = 50 * time.Millisecond
+
+// Position is where a model's view stands in its diff, as its status bar
+// shows it. Indices are 1-based, with zero totals when the diff has nothing
+// of that kind.
+type Position struct {
+	Line     int // row of the rendered diff at the top of the view, from 0
+	File     int
+	Files    int
+	Hunk     int
+	Hunks    int
+	Commit   int
+	Commits  int
+	Group    int
+	Groups   int
+	Section  int
+	Sections int
+}
+
+// Driver runs a model without a terminal, for scripts and integration
+// tests. Messages are handled synchronously: each key or message is passed
+// to the model and the commands it returns are run, and their messages
+// handled, before the call returns.
+type Driver struct {
+	model   tea.Model
+	timeout time.Duration
+	done    bool
+}
+
+// DriverOption configures a Driver.
+type DriverOption func(*Driver)
+
+// WithCommandTimeout sets how long the driver waits for each command to
+// return a message before dropping it. Defaults to 50ms, which skips timers
+// such as the idle timeout while still running quick work like loading a
+// file.
+func WithCommandTimeout(d time.Duration) DriverOption {
+	return func(drv *Driver) {
+		drv.timeout = d
+	}
+}
+
+// NewDriver starts m as if in a terminal of the given size.
+func NewDriver(m tea.Model, width, height int, opts ...DriverOption) *Driver {
+	d := &Driver{model: m, timeout: defaultCommandTimeout}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.run(m.Init())
+	d.Send(tea.WindowSizeMsg{Width: width, Height: height})
+	return d
+}
+
+// Send passes msg to the model and runs the commands it returns. Messages
+// after the model quits are ignored.
+func (d *Driver) Send(msg tea.Msg) {
+	if d.done {
+		return
+	}
+	var cmd tea.Cmd
+	d.model, cmd = d.model.Update(msg)
+	d.run(cmd)
+}
+
+// Press sends each key in turn, named as in key bindings: "j", "G",
+// "ctrl+d", "enter", "esc", "alt+x" or "space".
+func (d *Driver) Press(keys ...string) error {
+	for _, k := range keys {
+		msg, err := parseKey(k)
+		if err != nil {
+			return err
+		}
+		d.Send(msg)
+	}
+	return nil
+}
+
+// Type sends text as typed, one key per character.
+func (d *Driver) Type(text string) {
+	for _, r := range text {
+		d.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// Frame returns the current screen as plain text, without colors or other
+// styling.
+func (d *Driver) Frame() string {
+	return ansi.Strip(d.model.View())
+}
+
+// Model returns the model as it is now.
+func (d *Driver) Model() tea.Model {
+	return d.model
+}
+
+// Done reports whether the model has quit.
+func (d *Driver) Done() bool {
+	return d.done
+}
+
+// Position returns where the model's view stands, for models that track
+// one, such as Model and StoryModel.
+func (d *Driver) Position() (Position, bool) {
+	p, ok := d.model.(interface{ Position() Position })
+	if !ok {
+		return Position{}, false
+	}
+	return p.Position(), true
+}
+
+// run runs cmd and handles the messages it produces, in order.
+func (d *Driver) run(cmd tea.Cmd) {
+	if cmd == nil || d.done {
+		return
+	}
+	msg, ok := d.exec(cmd)
+	if !ok {
+		return
+	}
+	switch msg := msg.(type) {
+	case nil:
+	case tea.QuitMsg:
+		d.done = true
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			d.run(cmd)
+		}
+	default:
+		// tea.Sequence's message type is unexported, but is a list of commands
+		if cmds, ok := sequence(msg); ok {
+			for _, cmd := range cmds {
+				d.run(cmd)
+			}
+			return
+		}
+		d.Send(msg)
+	}
+}
+
+// exec runs cmd, reporting false if it takes longer than the timeout.
+func (d *Driver) exec(cmd tea.Cmd) (tea.Msg, bool) {
+	result := make(chan tea.Msg, 1)
+	go func() {
+		result <- cmd()
+	}()
+	select {
+	case msg := <-result:
+		return msg, true
+	case <-time.After(d.timeout):
+		return nil, false
+	}
+}
+
+// sequence returns the commands in msg if it is a list of them.
+func sequence(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem() != reflect.TypeOf(tea.Cmd(nil)) {
+		return nil, false
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
+
+// keyType returns the key type named name, as tea.KeyMsg.String names it,
+// and false if no key type has that name.
+func keyType(name string) (tea.KeyType, bool) {
+	if name == "space" {
+		return tea.KeySpace, true
+	}
+	for k := tea.KeyType(-128); k < 128; k++ {
+		if k != tea.KeyRunes && k.String() == name {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// parseKey turns a key name into the message a terminal would send.
+func parseKey(name string) (tea.KeyMsg, error) {
+	var msg tea.KeyMsg
+	rest := name
+	if after, ok := strings.CutPrefix(name, "alt+"); ok && after != "" {
+		msg.Alt, rest = true, after
+	}
+	if k, ok := keyType(rest); ok {
+		msg.Type = k
+		return msg, nil
+	}
+	if utf8.RuneCountInString(rest) == 1 {
+		msg.Type, msg.Runes = tea.KeyRunes, []rune(rest)
+		return msg, nil
+	}
+	return tea.KeyMsg{}, fmt.Errorf("unknown key %q", name)
+}
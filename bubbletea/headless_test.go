@@ -0,0 +1,108 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_ModelNavigation(t *testing.T) {
+	t.Parallel()
+
+	d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go", "c.go")), 80, 10)
+
+	pos, ok := d.Position()
+	require.True(t, ok)
+	assert.Equal(t, 1, pos.File)
+	assert.Equal(t, 3, pos.Files)
+	assert.Contains(t, d.Frame(), "a.go")
+	assert.NotContains(t, d.Frame(), "\x1b[")
+
+	require.NoError(t, d.Press("]", "]"))
+	pos, _ = d.Position()
+	assert.Equal(t, 3, pos.File)
+	assert.Contains(t, d.Frame(), "line 1 of c.go")
+
+	require.NoError(t, d.Press("g", "g"))
+	pos, _ = d.Position()
+	assert.Equal(t, bubbletea.Position{Line: 0, File: 1, Files: 3, Hunk: 1, Hunks: 3}, pos)
+}
+
+func TestDriver_StoryPosition(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go", "store.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Add the endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Persist requests", Hunks: []diffview.HunkRef{{File: "store.go", HunkIndex: 0}}},
+		},
+	}
+	d := bubbletea.NewDriver(bubbletea.NewStoryModel(diff, story), 80, 20)
+
+	require.NoError(t, d.Press("s"))
+	pos, ok := d.Position()
+	require.True(t, ok)
+	assert.Equal(t, 2, pos.Section)
+	assert.Equal(t, 2, pos.Sections)
+	assert.Contains(t, d.Frame(), "Persist requests")
+}
+
+func TestDriver_Quit(t *testing.T) {
+	t.Parallel()
+
+	d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go")), 80, 10)
+	require.NoError(t, d.Press("q"))
+	assert.True(t, d.Done())
+
+	// Keys after quitting are ignored
+	require.NoError(t, d.Press("G"))
+	pos, _ := d.Position()
+	assert.Equal(t, 0, pos.Line)
+}
+
+func TestDriver_RunsBatchedAndSequencedCommands(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	m := recordingModel{got: &got}
+	d := bubbletea.NewDriver(m, 80, 10)
+	d.Send(tea.Sequence(
+		func() tea.Msg { return "first" },
+		tea.Batch(func() tea.Msg { return "second" }),
+		func() tea.Msg { return "third" },
+	))
+	assert.Equal(t, []string{"first", "second", "third"}, got)
+}
+
+func TestDriver_PressRejectsUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go")), 80, 10)
+	require.NoError(t, d.Press("ctrl+d", "enter", "esc", "space", "alt+x"))
+	require.EqualError(t, d.Press("hyper+x"), `unknown key "hyper+x"`)
+}
+
+// recordingModel records the string messages it receives and, for a
+// command, returns it to be run.
+type recordingModel struct {
+	got *[]string
+}
+
+func (m recordingModel) Init() tea.Cmd { return nil }
+
+func (m recordingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case string:
+		*m.got = append(*m.got, msg)
+	case tea.Cmd:
+		return m, msg
+	}
+	return m, nil
+}
+
+func (m recordingModel) View() string { return "" }
@@ -0,0 +1,93 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// idleLockMessage is shown in place of content while the screen is locked.
+const idleLockMessage = "Screen locked after inactivity · press any key to resume"
+
+// ParseIdleTimeout parses an idle timeout setting such as a flag or
+// environment value. A bare number is minutes; otherwise the value is a Go
+// duration like "90s" or "15m". An empty string or zero disables locking.
+func ParseIdleTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid idle timeout %q: must not be negative", s)
+		}
+		return time.Duration(n) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid idle timeout %q: use minutes (e.g. 10) or a duration (e.g. 90s)", s)
+	}
+	return d, nil
+}
+
+// idleCheckMsg asks the model to check whether the idle timeout has passed.
+type idleCheckMsg struct{}
+
+// idleLock blanks the screen after a period without input, so proprietary
+// code isn't left visible on an unattended screen. A zero timeout disables it.
+type idleLock struct {
+	timeout      time.Duration
+	lastActivity time.Time
+	locked       bool
+}
+
+// newIdleLock returns an idle lock whose countdown starts now.
+func newIdleLock(timeout time.Duration) idleLock {
+	return idleLock{timeout: timeout, lastActivity: time.Now()}
+}
+
+// start returns the command that schedules the first idle check.
+func (l idleLock) start() tea.Cmd {
+	if l.timeout <= 0 {
+		return nil
+	}
+	return checkIdleAfter(l.timeout)
+}
+
+// touch records a key press or click. If the screen was locked it unlocks
+// and reports true, meaning the input only dismissed the lock screen and
+// should be discarded.
+func (l *idleLock) touch() (dismissed bool, cmd tea.Cmd) {
+	l.lastActivity = time.Now()
+	if !l.locked {
+		return false, nil
+	}
+	l.locked = false
+	return true, checkIdleAfter(l.timeout)
+}
+
+// check locks the screen if the timeout has passed since the last input, or
+// schedules another check for when it would.
+func (l *idleLock) check() tea.Cmd {
+	if l.timeout <= 0 || l.locked {
+		return nil
+	}
+	idle := time.Since(l.lastActivity)
+	if idle >= l.timeout {
+		l.locked = true
+		return nil
+	}
+	return checkIdleAfter(l.timeout - idle)
+}
+
+func checkIdleAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return idleCheckMsg{} })
+}
+
+// renderLockScreen returns a blank screen of the given size with the lock
+// message centered.
+func renderLockScreen(width, height int, style lipgloss.Style) string {
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, style.Render(idleLockMessage))
+}
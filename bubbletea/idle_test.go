@@ -0,0 +1,113 @@
+package bubbletea_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty disables locking", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := bubbletea.ParseIdleTimeout("")
+
+		require.NoError(t, err)
+		assert.Zero(t, d)
+	})
+
+	t.Run("bare number is minutes", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := bubbletea.ParseIdleTimeout("10")
+
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Minute, d)
+	})
+
+	t.Run("accepts durations", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := bubbletea.ParseIdleTimeout("90s")
+
+		require.NoError(t, err)
+		assert.Equal(t, 90*time.Second, d)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		t.Parallel()
+
+		for _, s := range []string{"soon", "-5", "-1m"} {
+			_, err := bubbletea.ParseIdleTimeout(s)
+			assert.Error(t, err, "input %q", s)
+		}
+	})
+}
+
+func TestModel_IdleTimeoutBlanksContentUntilKeyPress(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/secret.go",
+				NewPath:   "b/secret.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineAdded, Content: "proprietary code\n", NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	m := bubbletea.NewModel(diff, bubbletea.WithIdleTimeout(100*time.Millisecond))
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(80, 24),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("proprietary code"))
+	}, teatest.WithDuration(2*time.Second))
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Screen locked after inactivity"))
+	}, teatest.WithDuration(2*time.Second))
+
+	// The key that dismisses the lock isn't acted on, so q doesn't quit
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("proprietary code"))
+	}, teatest.WithDuration(2*time.Second))
+
+	// The screen may lock again before the next key arrives; a second q
+	// quits either way
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(2*time.Second))
+}
+
+func TestModel_NoIdleLockByDefault(t *testing.T) {
+	t.Parallel()
+
+	m := bubbletea.NewModel(&diffview.Diff{})
+
+	assert.Nil(t, m.Init())
+}
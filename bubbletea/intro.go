@@ -1,6 +1,9 @@
 package bubbletea
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
 	diffview "github.com/fwojciec/diffstory"
 )
@@ -204,3 +207,46 @@ func extractRoles(sections []diffview.Section) []string {
 	}
 	return roles
 }
+
+// riskSummary describes risk for the intro slide: its level, then what
+// breaks, the migration steps and the public APIs affected, where any.
+func riskSummary(risk *diffview.Risk) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Risk: %s\n", risk.Level)
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "  %s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "    - %s\n", item)
+		}
+	}
+	list("Breaking", risk.Breaking)
+	list("Migration", risk.Migration)
+	list("Affected APIs", risk.AffectedAPIs)
+	return b.String()
+}
+
+// impactSummary describes impact for the intro slide: the commit type and
+// version bump, then the exported identifiers breaking or added, where any.
+func impactSummary(impact *diffview.Impact) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Impact: %s, %s bump\n", impact.ConventionalType(), impact.Bump)
+	if len(impact.Breaking) > 0 {
+		fmt.Fprintf(&b, "  Breaking: %s\n", strings.Join(impact.Breaking, ", "))
+	}
+	if len(impact.Added) > 0 {
+		fmt.Fprintf(&b, "  Added: %s\n", strings.Join(impact.Added, ", "))
+	}
+	return b.String()
+}
+
+// reviewersSummary names the suggested reviewers in one line.
+func reviewersSummary(reviewers []diffview.Reviewer) string {
+	names := make([]string, len(reviewers))
+	for i, r := range reviewers {
+		names[i] = r.String()
+	}
+	return "Likely reviewers: " + strings.Join(names, ", ") + "\n"
+}
@@ -405,3 +405,63 @@ func TestNarrativeDiagram_CorePeriphery_FivePeripheral_FallsBackToRightOnly(t *t
 		}
 	}
 }
+
+func TestStoryModel_IntroSlideShowsRisk(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Summary: "Rename the config key",
+		Risk: &diffview.Risk{
+			Level:     "high",
+			Breaking:  []string{"Old config files no longer load"},
+			Migration: []string{"Rename timeout to timeout_ms"},
+		},
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Rename", Hunks: []diffview.HunkRef{{File: "config.go", HunkIndex: 0}}},
+		},
+	}
+	d := bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("config.go"), story, bubbletea.WithIntroSlide()), 80, 30)
+
+	frame := d.Frame()
+	assert.Contains(t, frame, "Risk: high")
+	assert.Contains(t, frame, "Breaking:")
+	assert.Contains(t, frame, "- Old config files no longer load")
+	assert.Contains(t, frame, "- Rename timeout to timeout_ms")
+	assert.NotContains(t, frame, "Affected APIs")
+}
+
+func TestStoryModel_IntroSlideShowsLikelyReviewers(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Summary:  "Tune the cache",
+		Sections: []diffview.Section{{Role: "core", Title: "Cache", Hunks: []diffview.HunkRef{{File: "cache.go", HunkIndex: 0}}}},
+	}
+	input := diffview.ClassificationInput{Reviewers: []diffview.Reviewer{{Name: "@org/perf", Owner: true}, {Name: "Ann"}}}
+	m := bubbletea.NewStoryModel(multiFileDiff("cache.go"), story, bubbletea.WithIntroSlide(), bubbletea.WithStoryInput(input))
+	d := bubbletea.NewDriver(m, 80, 24)
+
+	assert.Contains(t, d.Frame(), "Likely reviewers: @org/perf (owner), Ann")
+}
+
+func TestStoryModel_IntroSlideShowsImpact(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Summary: "Drop the legacy login",
+		Impact: &diffview.Impact{
+			CommitType: "feat",
+			Bump:       "major",
+			Breaking:   []string{"auth.LegacyLogin"},
+		},
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Remove", Hunks: []diffview.HunkRef{{File: "auth.go", HunkIndex: 0}}},
+		},
+	}
+	d := bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("auth.go"), story, bubbletea.WithIntroSlide()), 80, 30)
+
+	frame := d.Frame()
+	assert.Contains(t, frame, "Impact: feat!, major bump")
+	assert.Contains(t, frame, "Breaking: auth.LegacyLogin")
+	assert.NotContains(t, frame, "Added:")
+}
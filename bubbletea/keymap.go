@@ -14,6 +14,24 @@ type KeyMap struct {
 	PrevHunk     key.Binding
 	NextFile     key.Binding
 	PrevFile     key.Binding
+	NextCommit   key.Binding
+	PrevCommit   key.Binding
+	NextRelated  key.Binding
+	PrevRelated  key.Binding
+	FollowMove   key.Binding
+	Structure    key.Binding
+	ToggleWrap   key.Binding
+	ScrollLeft   key.Binding
+	ScrollRight  key.Binding
+	FindFile     key.Binding
+	OpenEditor   key.Binding
+	PaneFile     key.Binding
+	PaneBlame    key.Binding
+	PaneLog      key.Binding
+	Annotate     key.Binding
+	Details      key.Binding
+	ToggleNotes  key.Binding
+	Debug        key.Binding
 	Quit         key.Binding
 }
 
@@ -60,6 +78,78 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("["),
 			key.WithHelp("[", "previous file"),
 		),
+		NextCommit: key.NewBinding(
+			key.WithKeys("}"),
+			key.WithHelp("}", "next commit or file group"),
+		),
+		PrevCommit: key.NewBinding(
+			key.WithKeys("{"),
+			key.WithHelp("{", "previous commit or file group"),
+		),
+		NextRelated: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "next hunk on the same symbol"),
+		),
+		PrevRelated: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "previous hunk on the same symbol"),
+		),
+		FollowMove: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "jump to the other side of a move"),
+		),
+		Structure: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "toggle key-level diff of config file or lockfile summary"),
+		),
+		ToggleWrap: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "toggle line wrap"),
+		),
+		ScrollLeft: key.NewBinding(
+			key.WithKeys("h", "left"),
+			key.WithHelp("h/←", "scroll left"),
+		),
+		ScrollRight: key.NewBinding(
+			key.WithKeys("l", "right"),
+			key.WithHelp("l/→", "scroll right"),
+		),
+		FindFile: key.NewBinding(
+			key.WithKeys("ctrl+p", ":"),
+			key.WithHelp("ctrl+p/:", "jump to file"),
+		),
+		OpenEditor: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "open in editor"),
+		),
+		PaneFile: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "show file in a pane"),
+		),
+		PaneBlame: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "show blame in a pane"),
+		),
+		PaneLog: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "show file history in a pane"),
+		),
+		Annotate: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "add review note"),
+		),
+		Details: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "show notes here"),
+		),
+		ToggleNotes: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "show/hide annotator notes"),
+		),
+		Debug: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "debug info"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
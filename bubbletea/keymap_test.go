@@ -90,6 +90,50 @@ func TestDefaultKeyMap_HasExpectedBindings(t *testing.T) {
 		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}}
 		assert.True(t, key.Matches(msg, km.PrevFile), "[ should match PrevFile binding")
 	})
+
+	t.Run("ToggleWrap binding", func(t *testing.T) {
+		t.Parallel()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}}
+		assert.True(t, key.Matches(msg, km.ToggleWrap), "w should match ToggleWrap binding")
+	})
+
+	t.Run("ScrollLeft and ScrollRight bindings", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}}, km.ScrollLeft), "h should match ScrollLeft binding")
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyLeft}, km.ScrollLeft), "arrow left should match ScrollLeft binding")
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}}, km.ScrollRight), "l should match ScrollRight binding")
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRight}, km.ScrollRight), "arrow right should match ScrollRight binding")
+	})
+
+	t.Run("FindFile binding", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyCtrlP}, km.FindFile), "ctrl+p should match FindFile binding")
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}}, km.FindFile), ": should match FindFile binding")
+	})
+
+	t.Run("OpenEditor binding", func(t *testing.T) {
+		t.Parallel()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}}
+		assert.True(t, key.Matches(msg, km.OpenEditor), "e should match OpenEditor binding")
+	})
+
+	t.Run("commit bindings", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'}'}}, km.NextCommit), "} should match NextCommit binding")
+		assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'{'}}, km.PrevCommit), "{ should match PrevCommit binding")
+	})
+
+	t.Run("Annotate binding", func(t *testing.T) {
+		t.Parallel()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}}
+		assert.True(t, key.Matches(msg, km.Annotate), "c should match Annotate binding")
+	})
+
+	t.Run("Debug binding", func(t *testing.T) {
+		t.Parallel()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}}
+		assert.True(t, key.Matches(msg, km.Debug), "D should match Debug binding")
+	})
 }
 
 func TestKeyMap_HelpText(t *testing.T) {
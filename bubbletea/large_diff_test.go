@@ -5,6 +5,7 @@
 package bubbletea_test
 
 import (
+	"iter"
 	"strings"
 	"testing"
 	"time"
@@ -167,14 +168,14 @@ func TestLargeDiff_SkipsTokenizationForLongLines(t *testing.T) {
 // Used to verify that tokenization is correctly skipped for large files.
 type panicOnCallTokenizer struct{}
 
-func (p *panicOnCallTokenizer) Tokenize(language, source string) []diffview.Token {
-	panic("Tokenize should not be called for large files")
-}
-
 func (p *panicOnCallTokenizer) TokenizeLines(language, source string) [][]diffview.Token {
 	panic("TokenizeLines should not be called for large files")
 }
 
+func (p *panicOnCallTokenizer) StreamLines(language, source string) iter.Seq[[]diffview.Token] {
+	panic("StreamLines should not be called for large files")
+}
+
 // alwaysGoDetector returns "Go" for any path, ensuring tokenization would be attempted.
 type alwaysGoDetector struct{}
 
@@ -182,6 +183,10 @@ func (d *alwaysGoDetector) DetectFromPath(path string) string {
 	return "Go"
 }
 
+func (d *alwaysGoDetector) DetectFromContent(content string) string {
+	return "Go"
+}
+
 // benchResult prevents compiler from optimizing away benchmark results.
 var benchResult any
 
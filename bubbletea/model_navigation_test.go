@@ -592,3 +592,64 @@ func TestModel_SkipsFilesWithNoHunks(t *testing.T) {
 	assert.Equal(t, 1, hunkPositions[0], "first hunk at line 1")
 	assert.Equal(t, 4, hunkPositions[1], "second hunk at line 4")
 }
+
+func TestModel_CommitNavigation(t *testing.T) {
+	t.Parallel()
+
+	// Three commits: the second, a merge, changed no files
+	diff := multiFileDiff("a.go", "b.go")
+	diff.Commits = []diffview.Commit{
+		{Hash: "1111111aaaa", Author: "Ada <ada@example.com>", Message: "First change", FirstFile: 0},
+		{Hash: "2222222bbbb", Author: "Bob <bob@example.com>", Message: "Merge branch", FirstFile: 1},
+		{Hash: "3333333cccc", Author: "Ada <ada@example.com>", Message: "Second change\n\nDetails.", FirstFile: 1},
+	}
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 10})
+
+	assert.Contains(t, topLine(model), "1111111 First change · Ada")
+	assert.Contains(t, model.View(), "commit 1/3")
+
+	model = sendKeys(t, model, typeText("}"))
+	assert.Contains(t, topLine(model), "2222222 Merge branch · Bob")
+
+	// The merge header sits right above the next commit's
+	model = sendKeys(t, model, typeText("}"))
+	assert.Contains(t, topLine(model), "3333333 Second change · Ada")
+	assert.Contains(t, model.View(), "commit 3/3")
+	assert.NotContains(t, model.View(), "Details.")
+
+	model = sendKeys(t, model, typeText("{"), typeText("{"))
+	assert.Contains(t, topLine(model), "1111111")
+}
+
+func TestModel_FileGroupNavigation(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("pkg/server/server.go", "pkg/server/routes.go", "pkg/client/client.go")
+
+	var model tea.Model = bubbletea.NewModel(diff, bubbletea.WithFileGroups(diffview.GroupPackage))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 6})
+
+	assert.Contains(t, topLine(model), "══ pkg/server · 2 files +20 -0")
+	assert.Contains(t, model.View(), "group 1/2")
+
+	model = sendKeys(t, model, typeText("}"))
+	assert.Contains(t, topLine(model), "══ pkg/client · 1 file +10 -0")
+	assert.Contains(t, model.View(), "group 2/2")
+
+	model = sendKeys(t, model, typeText("{"))
+	assert.Contains(t, topLine(model), "pkg/server")
+}
+
+func TestModel_FileGroupsOffByDefault(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("pkg/server/server.go", "pkg/client/client.go")
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 10})
+
+	assert.NotContains(t, model.View(), "group 1/")
+	assert.NotContains(t, model.View(), "══")
+}
@@ -2,15 +2,20 @@ package bubbletea_test
 
 import (
 	"bytes"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/teatest"
 	diffview "github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/bubbletea"
 	dv "github.com/fwojciec/diffstory/lipgloss"
+	"github.com/fwojciec/diffstory/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestModel_RendersFileHeaders(t *testing.T) {
@@ -91,6 +96,25 @@ func TestModel_RendersHunkHeaders(t *testing.T) {
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
 
+func TestModel_ResolvesHunkSymbols(t *testing.T) {
+	t.Parallel()
+
+	resolver := &mock.SymbolResolver{
+		ResolveSymbolsFn: func(diff *diffview.Diff) {
+			for i := range diff.Files {
+				diff.Files[i].Hunks[0].Section = "func (s *Server) Start"
+			}
+		},
+	}
+	d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go"), bubbletea.WithSymbolResolver(resolver)), 80, 10)
+
+	assert.Contains(t, d.Frame(), "@@ func (s *Server) Start")
+
+	d.Send(bubbletea.ReloadMsg{Diff: multiFileDiff("b.go")})
+	assert.Contains(t, d.Frame(), "b.go")
+	assert.Contains(t, d.Frame(), "@@ func (s *Server) Start", "reloaded diffs are resolved too")
+}
+
 func TestModel_RendersLinePrefixes(t *testing.T) {
 	t.Parallel()
 
@@ -529,6 +553,56 @@ func TestModel_GutterUsesEmptySpaceForMissingLineNumbers(t *testing.T) {
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
 
+func TestModel_FlagsLikelySecretsInGutterAndStatusBar(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/config.go",
+				NewPath:   "b/config.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 2,
+						Lines: []diffview.Line{
+							{Type: diffview.LineContext, Content: "SECRET context", OldLineNum: 1, NewLineNum: 1},
+							{Type: diffview.LineAdded, Content: "SECRET added", OldLineNum: 0, NewLineNum: 2},
+						},
+					},
+				},
+			},
+		},
+	}
+	detector := &mock.SecretDetector{
+		DetectSecretFn: func(content string) string {
+			if strings.HasPrefix(content, "SECRET") {
+				return "aws-access-key"
+			}
+			return ""
+		},
+	}
+
+	m := bubbletea.NewModel(diff, bubbletea.WithSecretDetector(detector))
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(100, 24),
+	)
+
+	// Only added lines are flagged, with the badge in the empty old line
+	// number column
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("   ⚠    2  +SECRET added")) &&
+			bytes.Contains(out, []byte("   1    1   SECRET context")) &&
+			bytes.Contains(out, []byte("⚠ 1 possible secret"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
 func TestModel_GutterHasColoredBackgroundForAddedLines(t *testing.T) {
 	t.Parallel()
 
@@ -802,7 +876,7 @@ func TestModel_AppliesSyntaxHighlighting(t *testing.T) {
 	theme := dv.TestTheme()
 
 	// Create a mock tokenizer that returns tokens with keyword style
-	tokenizer := &mockTokenizer{
+	tokenizer := &mock.Tokenizer{
 		TokenizeLinesFn: func(language, source string) [][]diffview.Token {
 			if language != "Go" {
 				return nil
@@ -829,7 +903,7 @@ func TestModel_AppliesSyntaxHighlighting(t *testing.T) {
 	}
 
 	// Create a mock detector that returns "Go" for .go files
-	detector := &mockLanguageDetector{
+	detector := &mock.LanguageDetector{
 		DetectFromPathFn: func(path string) string {
 			if len(path) >= 3 && path[len(path)-3:] == ".go" {
 				return "Go"
@@ -1025,3 +1099,411 @@ func TestModel_ShowsEmptyFileDeletion(t *testing.T) {
 	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
+
+func TestModel_RendersCombinedDiffWithOursTheirsColors(t *testing.T) {
+	t.Parallel()
+
+	ctx, add := diffview.LineContext, diffview.LineAdded
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "file.txt",
+				NewPath:   "file.txt",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 3,
+						Parents:  []diffview.HunkRange{{Start: 1, Count: 1}, {Start: 1, Count: 1}},
+						Lines: []diffview.Line{
+							{Type: diffview.LineContext, Content: "same", Markers: []diffview.LineType{ctx, ctx}, OldLineNum: 1, NewLineNum: 1},
+							{Type: diffview.LineAdded, Content: "ours", Markers: []diffview.LineType{ctx, add}, OldLineNum: 2, NewLineNum: 2},
+							{Type: diffview.LineAdded, Content: "theirs", Markers: []diffview.LineType{add, ctx}, NewLineNum: 3},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// TestTheme blends UIAccent #0000ff at 20% -> RGB(0, 0, 51) for ours
+	// and Modified #ffff00 at 20% -> RGB(51, 51, 0) for theirs
+	m := bubbletea.NewModel(diff,
+		bubbletea.WithTheme(dv.TestTheme()),
+		bubbletea.WithRenderer(trueColorRenderer()),
+	)
+	assert.True(t, m.ConflictMode())
+
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(80, 24),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("@@@ -1,1 -1,1 +1,3 @@@")) &&
+			bytes.Contains(out, []byte(" +ours")) &&
+			bytes.Contains(out, []byte("+ theirs")) &&
+			bytes.Contains(out, []byte("48;2;0;0;51")) &&
+			bytes.Contains(out, []byte("48;2;51;51;0")) &&
+			bytes.Contains(out, []byte("merge"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestModel_RegularDiffIsNotConflictMode(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				NewPath: "file.txt",
+				Hunks: []diffview.Hunk{
+					{Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "x"}}},
+				},
+			},
+		},
+	}
+
+	m := bubbletea.NewModel(diff)
+
+	assert.False(t, m.ConflictMode())
+}
+
+func TestModel_EscapesControlBytesAndShowsEncoding(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "legacy.txt",
+				NewPath:   "legacy.txt",
+				Operation: diffview.FileModified,
+				Encoding:  "latin-1",
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineAdded, Content: "\x1b[31mred\x07\n", NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	m := bubbletea.NewModel(diff)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(80, 24),
+	)
+
+	// Raw escape sequences would recolor the terminal; they appear as control pictures instead
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("legacy.txt (latin-1)")) &&
+			bytes.Contains(out, []byte("+␛[31mred␇"))
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestModel_HidesCarriageReturnLineEndings(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "win.txt",
+				NewPath:   "win.txt",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineAdded, Content: "crlf\r\n", NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	m := bubbletea.NewModel(diff)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(80, 24),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("+crlf")) && !bytes.Contains(out, []byte("␍"))
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestModel_NotesLineEndingsInFileHeader(t *testing.T) {
+	t.Parallel()
+
+	file := func(path string, lines ...diffview.Line) diffview.FileDiff {
+		return diffview.FileDiff{
+			OldPath:   path,
+			NewPath:   path,
+			Operation: diffview.FileModified,
+			Hunks:     []diffview.Hunk{{OldStart: 1, OldCount: 1, NewStart: 1, NewCount: 1, Lines: lines}},
+		}
+	}
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		file("win.txt",
+			diffview.Line{Type: diffview.LineDeleted, Content: "a\r\n", OldLineNum: 1},
+			diffview.Line{Type: diffview.LineAdded, Content: "b\r\n", NewLineNum: 1}),
+		file("converted.txt",
+			diffview.Line{Type: diffview.LineDeleted, Content: "a\n", OldLineNum: 1},
+			diffview.Line{Type: diffview.LineAdded, Content: "a\r\n", NewLineNum: 1}),
+		file("unix.txt",
+			diffview.Line{Type: diffview.LineDeleted, Content: "a\n", OldLineNum: 1},
+			diffview.Line{Type: diffview.LineAdded, Content: "b\n", NewLineNum: 1}),
+	}}
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	view := model.View()
+
+	assert.Contains(t, view, "win.txt (CRLF)")
+	assert.Contains(t, view, "converted.txt (LF→CRLF)")
+	assert.Contains(t, view, "unix.txt ─")
+}
+
+func TestModel_MarksLinesWithoutNewline(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{{
+		OldPath:   "main.go",
+		NewPath:   "main.go",
+		Operation: diffview.FileModified,
+		Hunks: []diffview.Hunk{{
+			OldStart: 1, OldCount: 1, NewStart: 1, NewCount: 2,
+			Lines: []diffview.Line{
+				{Type: diffview.LineDeleted, Content: "}", OldLineNum: 1, NoNewline: true},
+				{Type: diffview.LineAdded, Content: "}\n", NewLineNum: 1},
+				{Type: diffview.LineAdded, Content: "// end", NewLineNum: 2, NoNewline: true},
+			},
+		}},
+	}}}
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	view := ansi.Strip(model.View())
+
+	assert.Contains(t, view, "¶-}")
+	assert.Contains(t, view, " +}")
+	assert.Contains(t, view, "¶+// end")
+}
+
+func TestModel_ExpandsTabsAtConfiguredWidth(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "main.go",
+				NewPath:   "main.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineAdded, Content: "\treturn\tnil\n", NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	m := bubbletea.NewModel(diff, bubbletea.WithTabWidth(4))
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(80, 24),
+	)
+
+	// Tab stops are measured from the start of the content, not the prefix
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("+    return  nil"))
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestModel_HighlightsAsFileLanguage(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				NewPath:   "b/BUILD",
+				Operation: diffview.FileAdded,
+				Language:  "Python",
+				Hunks: []diffview.Hunk{
+					{
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineAdded, Content: "load()", NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var languages []string
+	tokenizer := &mock.Tokenizer{
+		TokenizeLinesFn: func(language, source string) [][]diffview.Token {
+			languages = append(languages, language)
+			return nil
+		},
+	}
+	// The path alone gives no language
+	detector := &mock.LanguageDetector{
+		DetectFromPathFn: func(path string) string { return "" },
+	}
+
+	m := bubbletea.NewModel(diff,
+		bubbletea.WithLanguageDetector(detector),
+		bubbletea.WithTokenizer(tokenizer),
+	)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.View()
+
+	if assert.NotEmpty(t, languages) {
+		assert.Equal(t, "Python", languages[0])
+	}
+}
+
+func TestModel_TokenizesEachSideOfAHunkAsItsFileHasIt(t *testing.T) {
+	t.Parallel()
+
+	// The change closes a block comment early, so the new side's last line
+	// is code while the old side's is still inside the comment
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/main.go",
+				NewPath:   "b/main.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1, OldCount: 3, NewStart: 1, NewCount: 3,
+						Lines: []diffview.Line{
+							{Type: diffview.LineContext, Content: "/* start", OldLineNum: 1, NewLineNum: 1},
+							{Type: diffview.LineDeleted, Content: "still comment", OldLineNum: 2},
+							{Type: diffview.LineAdded, Content: "*/", NewLineNum: 2},
+							{Type: diffview.LineContext, Content: "x := 1", OldLineNum: 3, NewLineNum: 3},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var sources []string
+	tokenizer := &mock.Tokenizer{
+		TokenizeLinesFn: func(language, source string) [][]diffview.Token {
+			sources = append(sources, source)
+			return nil
+		},
+	}
+	detector := &mock.LanguageDetector{
+		DetectFromPathFn: func(path string) string { return "Go" },
+	}
+
+	m := bubbletea.NewModel(diff,
+		bubbletea.WithLanguageDetector(detector),
+		bubbletea.WithTokenizer(tokenizer),
+	)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.View()
+
+	assert.Contains(t, sources, "/* start\nstill comment\nx := 1")
+	assert.Contains(t, sources, "/* start\n*/\nx := 1")
+}
+
+func TestModel_ReusesTokensAcrossResizes(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("a.go", "b.go")
+	calls := 0
+	tokenizer := &mock.Tokenizer{
+		TokenizeLinesFn: func(language, source string) [][]diffview.Token {
+			calls++
+			return nil
+		},
+	}
+	detector := &mock.LanguageDetector{
+		DetectFromPathFn: func(path string) string { return "Go" },
+	}
+
+	var m tea.Model = bubbletea.NewModel(diff,
+		bubbletea.WithLanguageDetector(detector),
+		bubbletea.WithTokenizer(tokenizer),
+	)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	m.View()
+	require.NotZero(t, calls)
+	tokenized := calls
+
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 60, Height: 10})
+	narrow := m.View()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	wide := m.View()
+
+	assert.Equal(t, tokenized, calls, "tokenized again after resizing")
+	// Hunks are still drawn at each width
+	assert.NotEqual(t, narrow, wide)
+	assert.Contains(t, wide, "line 1 of a.go")
+}
+
+func TestModel_TokenizesAgainWhenAFilesLanguageChanges(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("a.txt")
+	var languages []string
+	tokenizer := &mock.Tokenizer{
+		TokenizeLinesFn: func(language, source string) [][]diffview.Token {
+			languages = append(languages, language)
+			return nil
+		},
+	}
+	detector := &mock.LanguageDetector{
+		DetectFromPathFn: func(path string) string { return "Text" },
+	}
+
+	var m tea.Model = bubbletea.NewModel(diff,
+		bubbletea.WithLanguageDetector(detector),
+		bubbletea.WithTokenizer(tokenizer),
+	)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	m.View()
+	require.NotEmpty(t, languages)
+
+	// The override shares the hunks' lines with the diff rendered before
+	overridden := *diff
+	overridden.Files = slices.Clone(diff.Files)
+	overridden.Files[0].Language = "Go"
+	languages = nil
+	m, _ = m.Update(bubbletea.ReloadMsg{Diff: &overridden})
+	m.View()
+
+	assert.Contains(t, languages, "Go")
+}
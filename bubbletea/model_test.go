@@ -3,6 +3,7 @@ package bubbletea_test
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	diffview "github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/bubbletea"
 	dv "github.com/fwojciec/diffstory/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -232,3 +234,147 @@ func TestViewer_ContextAlreadyCancelled(t *testing.T) {
 	err := viewer.View(ctx, diff)
 	require.ErrorIs(t, err, context.Canceled, "viewer should return context.Canceled for pre-cancelled context")
 }
+
+func TestViewer_QuitIfOneScreenPrintsDiffThatFits(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	viewer := bubbletea.NewViewer(dv.TestTheme(),
+		bubbletea.WithViewerQuitIfOneScreen(&out, 80, 24),
+	)
+
+	// No program runs, so no terminal is needed
+	err := viewer.View(context.Background(), multiFileDiff("a.go"))
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "line 10 of a.go")
+}
+
+func TestViewer_QuitIfOneScreenLeavesARowForThePrompt(t *testing.T) {
+	t.Parallel()
+
+	// A file takes 12 rows: its header, the hunk header and 10 lines
+	t.Run("prints a diff one row shorter than the screen", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		viewer := bubbletea.NewViewer(dv.TestTheme(),
+			bubbletea.WithViewerQuitIfOneScreen(&out, 80, 13),
+		)
+
+		err := viewer.View(context.Background(), multiFileDiff("a.go"))
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "line 10 of a.go")
+	})
+
+	t.Run("pages a diff as tall as the screen", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var printed, in, out bytes.Buffer
+		viewer := bubbletea.NewViewer(dv.TestTheme(),
+			bubbletea.WithViewerQuitIfOneScreen(&printed, 80, 12),
+			bubbletea.WithProgramOptions(
+				tea.WithInput(&in),
+				tea.WithOutput(&out),
+			),
+		)
+
+		err := viewer.View(ctx, multiFileDiff("a.go"))
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, printed.String())
+	})
+}
+
+func TestViewer_PrintWritesDiffWithoutTerminal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		viewer := bubbletea.NewViewer(dv.TestTheme(),
+			bubbletea.WithViewerPrint(&out, 40, termenv.Ascii),
+		)
+
+		// Every file is printed, however long the diff
+		err := viewer.View(context.Background(), multiFileDiff("a.go", "b.go", "c.go"))
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "line 10 of c.go")
+		assert.NotContains(t, out.String(), "\x1b[")
+	})
+
+	t.Run("colored", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		viewer := bubbletea.NewViewer(dv.TestTheme(),
+			bubbletea.WithViewerPrint(&out, 40, termenv.TrueColor),
+		)
+
+		err := viewer.View(context.Background(), multiFileDiff("a.go"))
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "\x1b[")
+	})
+
+	t.Run("256 colors", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		viewer := bubbletea.NewViewer(dv.ForProfile(dv.TestTheme(), termenv.ANSI256),
+			bubbletea.WithViewerPrint(&out, 40, termenv.ANSI256),
+		)
+
+		err := viewer.View(context.Background(), multiFileDiff("a.go"))
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "\x1b[")
+		assert.NotContains(t, out.String(), "38;2;", "true color escape")
+	})
+
+	t.Run("wraps long lines", func(t *testing.T) {
+		t.Parallel()
+
+		diff := multiFileDiff("a.go")
+		diff.Files[0].Hunks[0].Lines[0].Content = strings.Repeat("x", 60) + "END\n"
+
+		var out bytes.Buffer
+		viewer := bubbletea.NewViewer(dv.TestTheme(),
+			bubbletea.WithViewerPrint(&out, 40, termenv.Ascii),
+		)
+
+		err := viewer.View(context.Background(), diff)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "END")
+	})
+}
+
+func TestViewer_QuitIfOneScreenPagesDiffThatDoesNot(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var printed, in, out bytes.Buffer
+	viewer := bubbletea.NewViewer(dv.TestTheme(),
+		bubbletea.WithViewerQuitIfOneScreen(&printed, 80, 20),
+		bubbletea.WithProgramOptions(
+			tea.WithInput(&in),
+			tea.WithOutput(&out),
+		),
+	)
+
+	// Two files take 24 rows, more than the screen; the viewer starts and
+	// exits on the cancelled context
+	err := viewer.View(ctx, multiFileDiff("a.go", "b.go"))
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, printed.String())
+}
@@ -439,3 +439,54 @@ func TestModel_WordDiffHighlighting_NoWordDiffer(t *testing.T) {
 	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
+
+func TestModel_SymbolsTheme(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/test.go",
+				NewPath:   "b/test.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineDeleted, Content: "hello world", OldLineNum: 1},
+							{Type: diffview.LineAdded, Content: "hello universe", NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+	wordDiffer := &mockWordDiffer{
+		DiffFn: func(old, new string) (oldSegs, newSegs []diffview.Segment) {
+			return []diffview.Segment{{Text: "hello "}, {Text: "world", Changed: true}},
+				[]diffview.Segment{{Text: "hello "}, {Text: "universe", Changed: true}}
+		},
+	}
+
+	var model tea.Model = bubbletea.NewModel(diff,
+		bubbletea.WithTheme(dv.SymbolsTheme()),
+		bubbletea.WithRenderer(trueColorRenderer()),
+		bubbletea.WithWordDiffer(wordDiffer),
+	)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	view := model.View()
+
+	// Changes are marked beside the gutter, and nothing is colored
+	assert.Contains(t, view, "«")
+	assert.Contains(t, view, "»")
+	assert.NotContains(t, view, "38;2;")
+	assert.NotContains(t, view, "48;2;")
+
+	// Changed words are underlined, and added ones bold too
+	assert.Regexp(t, `\x1b\[[0-9;]*4mw`, view)
+	assert.Regexp(t, `\x1b\[1;[0-9;]*4mu`, view)
+	assert.NotRegexp(t, `\x1b\[[0-9;]*4mh`, view, "unchanged words aren't underlined")
+}
@@ -0,0 +1,184 @@
+package bubbletea_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	dv "github.com/fwojciec/diffstory/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// longLineDiff returns a diff with one added line much wider than a 60-column
+// terminal, followed by a second hunk.
+func longLineDiff(content string) *diffview.Diff {
+	return &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/data.txt",
+				NewPath:   "b/data.txt",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 0,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineAdded, Content: content + "\n", NewLineNum: 1},
+						},
+					},
+					{
+						OldStart: 10,
+						OldCount: 1,
+						NewStart: 11,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineContext, Content: "short\n", OldLineNum: 10, NewLineNum: 11},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestModel_WrapToggleShowsLongLineOnContinuationRows(t *testing.T) {
+	t.Parallel()
+
+	diff := longLineDiff("start" + strings.Repeat("x", 100) + "END")
+
+	m := bubbletea.NewModel(diff)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(60, 24),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("+start"))
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("↪")) && bytes.Contains(out, []byte("xEND"))
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestModel_HorizontalScrollKeepsPrefixInPlace(t *testing.T) {
+	t.Parallel()
+
+	diff := longLineDiff("start" + strings.Repeat("x", 100) + "END")
+
+	m := bubbletea.NewModel(diff)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(60, 24),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("+start"))
+	}, teatest.WithDuration(2*time.Second))
+
+	for range 10 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	}
+
+	// Scrolled content follows the diff prefix directly; the start of the line is gone
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("+xxxx")) && bytes.Contains(out, []byte("xEND"))
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestModel_WrapKeepsWordDiffHighlightAcrossRows(t *testing.T) {
+	t.Parallel()
+
+	oldContent := "value = " + strings.Repeat("a", 30)
+	newContent := "value = " + strings.Repeat("b", 80)
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/test.go",
+				NewPath:   "b/test.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 1,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineDeleted, Content: oldContent, OldLineNum: 1},
+							{Type: diffview.LineAdded, Content: newContent, NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	wordDiffer := &mockWordDiffer{
+		DiffFn: func(old, new string) (oldSegs, newSegs []diffview.Segment) {
+			oldSegs = []diffview.Segment{{Text: "value = "}, {Text: old[8:], Changed: true}}
+			newSegs = []diffview.Segment{{Text: "value = "}, {Text: new[8:], Changed: true}}
+			return oldSegs, newSegs
+		},
+	}
+
+	m := bubbletea.NewModel(diff,
+		bubbletea.WithTheme(dv.TestTheme()),
+		bubbletea.WithRenderer(trueColorRenderer()),
+		bubbletea.WithWordDiffer(wordDiffer),
+	)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(60, 24),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("+value"))
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+
+	// The changed word continues on the next row with the added highlight background
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		for _, line := range bytes.Split(out, []byte("\n")) {
+			if bytes.Contains(line, []byte("↪")) && bytes.Contains(line, []byte("48;2;0;89;0")) &&
+				bytes.Contains(line, []byte("bbbb")) {
+				return true
+			}
+		}
+		return false
+	}, teatest.WithDuration(2*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestModel_WrapShiftsHunkPositions(t *testing.T) {
+	t.Parallel()
+
+	diff := longLineDiff(strings.Repeat("x", 100))
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 60, Height: 24})
+	assert.Equal(t, []int{1, 3}, model.(bubbletea.Model).HunkPositions())
+
+	// Gutter takes 11 columns and the prefix 1, leaving 48 per row: 100 columns need 3 rows
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	require.IsType(t, bubbletea.Model{}, model)
+	assert.Equal(t, []int{1, 5}, model.(bubbletea.Model).HunkPositions())
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	assert.Equal(t, []int{1, 3}, model.(bubbletea.Model).HunkPositions())
+}
@@ -0,0 +1,268 @@
+package bubbletea
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+)
+
+// selection is the range of content rows dragged over with the left mouse
+// button, highlighted until the next key.
+type selection struct {
+	active   bool
+	dragging bool
+	anchor   int // row the drag started on
+	end      int // row the drag is on
+}
+
+// rows returns the first and last row of the selection.
+func (s selection) rows() (first, last int) {
+	return min(s.anchor, s.end), max(s.anchor, s.end)
+}
+
+// contains reports whether row is selected.
+func (s selection) contains(row int) bool {
+	first, last := s.rows()
+	return s.active && row >= first && row <= last
+}
+
+// mouseEvent is what a left-button press, drag or release over a viewer
+// amounts to.
+type mouseEvent int
+
+const (
+	mouseIgnored   mouseEvent = iota // not a left-button event; the wheel and the like
+	mouseDragging                    // the selection changed
+	mouseClick                       // released where pressed, on a content row
+	mouseSelected                    // released after dragging over several rows
+	mouseStatusBar                   // pressed on the status bar
+)
+
+// trackMouse updates sel for msg, a mouse event over a view whose content
+// fills vp with the status bar below it, and returns what msg amounts to
+// and the content row it's on.
+func trackMouse(sel *selection, vp viewport.Model, msg tea.MouseMsg) (mouseEvent, int) {
+	row := vp.YOffset + min(max(msg.Y, 0), vp.Height-1)
+	switch {
+	case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+		if msg.Y >= vp.Height {
+			return mouseStatusBar, 0
+		}
+		*sel = selection{active: true, dragging: true, anchor: row, end: row}
+		return mouseDragging, row
+	case msg.Action == tea.MouseActionMotion && sel.dragging:
+		sel.end = row
+		return mouseDragging, row
+	case msg.Action == tea.MouseActionRelease && sel.dragging:
+		sel.dragging = false
+		if sel.anchor == sel.end {
+			*sel = selection{}
+			return mouseClick, row
+		}
+		return mouseSelected, row
+	}
+	return mouseIgnored, 0
+}
+
+// highlightSelection draws the rows of view, the visible part of content
+// starting at row top, that sel covers in style, in place of their colors.
+func highlightSelection(view string, top int, sel selection, style lipgloss.Style) string {
+	if !sel.active {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if sel.contains(top + i) {
+			lines[i] = style.Render(ansi.Strip(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statusSegment returns the index of the segment of the status bar bar
+// that column x falls in, among those starting with labels, or -1. A
+// segment runs from its label to the next separator.
+func statusSegment(bar string, x int, labels ...string) int {
+	plain := ansi.Strip(bar)
+	for i, label := range labels {
+		start := strings.Index(plain, label)
+		if start < 0 {
+			continue
+		}
+		end := len(plain)
+		if sep := strings.Index(plain[start:], " │ "); sep >= 0 {
+			end = start + sep
+		}
+		left := ansi.StringWidth(plain[:start])
+		if x >= left && x < left+ansi.StringWidth(plain[start:end]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextRow returns the first of positions below row, or row when there's
+// none.
+func nextRow(positions []int, row int) int {
+	for _, pos := range positions {
+		if pos > row {
+			return pos
+		}
+	}
+	return row
+}
+
+// fileKeys returns the keys of the hunks of each file cfg shows, in order.
+func (cfg renderConfig) fileKeys() [][]hunkKey {
+	if cfg.diff == nil {
+		return nil
+	}
+	var files [][]hunkKey
+	for _, file := range cfg.diff.Files {
+		if !file.Visible() {
+			continue
+		}
+		keys := make([]hunkKey, len(file.Hunks))
+		for i := range file.Hunks {
+			keys[i] = cfg.keyOf(file.Path(), i)
+		}
+		files = append(files, keys)
+	}
+	return files
+}
+
+// clickCollapse toggles collapsed for what a click on row hits, given the
+// layout and hunk keys of each file on screen: a file's header collapses
+// its hunks, or expands them if all are collapsed, and a hunk's header
+// collapses or expands the hunk. It returns whether it changed anything,
+// and otherwise the header row of the hunk the click is in, or -1.
+func clickCollapse(collapsed map[hunkKey]bool, layout diffview.Layout, files [][]hunkKey, row int) (bool, int) {
+	if row >= layout.Rows || slices.Contains(layout.Commits, row) || slices.Contains(layout.Groups, row) {
+		return false, -1
+	}
+	file, hunk := layout.At(row)
+	keys := slices.Concat(files...)
+	switch {
+	case hunk >= 0 && row == layout.Hunks[hunk] && hunk < len(keys):
+		collapsed[keys[hunk]] = !collapsed[keys[hunk]]
+		return true, -1
+	case hunk >= 0:
+		return false, layout.Hunks[hunk]
+	case file >= 0 && file < len(files) && len(files[file]) > 0:
+		fold := slices.ContainsFunc(files[file], func(key hunkKey) bool { return !collapsed[key] })
+		for _, key := range files[file] {
+			collapsed[key] = fold
+		}
+		return true, -1
+	}
+	return false, -1
+}
+
+// handleMouse acts on left-button clicks and drags, reporting whether it
+// did: clicking a header collapses or expands it, clicking a line scrolls
+// its hunk to the top, clicking the status bar's file or hunk position
+// jumps to the next one, and dragging selects rows.
+func (m *Model) handleMouse(msg tea.MouseMsg) bool {
+	if m.finder.active || m.debug.active || m.details.active || m.noteEditor.active {
+		return false
+	}
+	event, row := trackMouse(&m.selection, m.viewport, msg)
+	switch event {
+	case mouseIgnored:
+		return false
+	case mouseStatusBar:
+		switch statusSegment(m.statusBarView(), msg.X, "file ", "hunk ") {
+		case 0:
+			m.viewport.SetYOffset(nextRow(m.layout.Files, m.viewport.YOffset))
+		case 1:
+			m.viewport.SetYOffset(nextRow(m.layout.Hunks, m.viewport.YOffset))
+		}
+	case mouseClick:
+		if m.collapsed == nil {
+			m.collapsed = make(map[hunkKey]bool)
+		}
+		changed, hunk := clickCollapse(m.collapsed, m.layout, m.diffConfig().fileKeys(), row)
+		switch {
+		case changed:
+			m.updatePositions()
+			m.viewport.SetContent(m.renderContent())
+		case hunk >= 0:
+			m.viewport.SetYOffset(hunk)
+		}
+	case mouseSelected:
+		first, last := m.selection.rows()
+		m.notice = fmt.Sprintf("%d lines selected", last-first+1)
+	}
+	return true
+}
+
+// handleMouse is Model.handleMouse for the story view, where clicking the
+// status bar's section jumps to the next section.
+func (m *StoryModel) handleMouse(msg tea.MouseMsg) bool {
+	if m.debug.active || m.pendingPatch != nil {
+		return false
+	}
+	// Nor is the presenter's header above it
+	vp := m.viewport
+	if header := m.presenterHeaderHeight(); header > 0 {
+		if msg.Y < header {
+			return true
+		}
+		msg.Y -= header
+		vp.Height = max(vp.Height-header, 1)
+	}
+	// The sidebar isn't part of the content
+	if msg.X >= vp.Width && msg.Y < vp.Height && msg.Action == tea.MouseActionPress {
+		return true
+	}
+	event, row := trackMouse(&m.selection, vp, msg)
+	positions := m.positions()
+	switch event {
+	case mouseIgnored:
+		return false
+	case mouseStatusBar:
+		switch statusSegment(m.statusBarView(), msg.X, "file ", "hunk ", "section ") {
+		case 0:
+			m.viewport.SetYOffset(nextRow(positions.Files, m.viewport.YOffset))
+		case 1:
+			m.viewport.SetYOffset(nextRow(positions.Hunks, m.viewport.YOffset))
+		case 2:
+			m.gotoNextSection()
+		}
+	case mouseClick:
+		if m.onIntro() {
+			return true
+		}
+		changed, hunk := clickCollapse(m.collapsedHunks, positions, m.fileKeys(), row)
+		switch {
+		case changed:
+			m.setContent()
+		case hunk >= 0:
+			m.viewport.SetYOffset(hunk)
+		}
+	case mouseSelected:
+		first, last := m.selection.rows()
+		m.notice = fmt.Sprintf("%d lines selected", last-first+1)
+	}
+	return true
+}
+
+// fileKeys returns the keys of the hunks of each file on screen, in order.
+func (m StoryModel) fileKeys() [][]hunkKey {
+	if !m.allSections {
+		return m.diffConfig().fileKeys()
+	}
+	var files [][]hunkKey
+	if m.story != nil {
+		for idx := range m.story.Sections {
+			files = append(files, m.sectionConfig(idx).fileKeys()...)
+		}
+	}
+	return files
+}
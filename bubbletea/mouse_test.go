@@ -0,0 +1,144 @@
+package bubbletea_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// click presses and releases the left mouse button at x, y.
+func click(d *bubbletea.Driver, x, y int) {
+	d.Send(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	d.Send(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+}
+
+// statusColumn returns the column of label in the status bar.
+func statusColumn(t *testing.T, d *bubbletea.Driver, label string) int {
+	t.Helper()
+	bar := statusBar(d)
+	idx := strings.Index(bar, label)
+	require.GreaterOrEqual(t, idx, 0, "%q in %q", label, bar)
+	return ansi.StringWidth(bar[:idx])
+}
+
+func TestModel_Mouse(t *testing.T) {
+	t.Parallel()
+
+	newDriver := func() *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go")), 80, 8)
+	}
+
+	t.Run("clicking a hunk header collapses and expands the hunk", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		click(d, 5, 1)
+		assert.Contains(t, d.Frame(), "▸ collapsed")
+		assert.NotContains(t, d.Frame(), "line 1 of a.go")
+		assert.Contains(t, d.Frame(), "b.go", "the next file moves up")
+
+		click(d, 5, 1)
+		assert.Contains(t, d.Frame(), "line 1 of a.go")
+	})
+
+	t.Run("clicking a file header collapses its hunks", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		click(d, 5, 0)
+		assert.Contains(t, d.Frame(), "▸ collapsed")
+		assert.NotContains(t, d.Frame(), "line 1 of a.go")
+
+		click(d, 5, 0)
+		assert.Contains(t, d.Frame(), "line 1 of a.go")
+	})
+
+	t.Run("clicking a line scrolls its hunk to the top", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		click(d, 5, 4)
+		pos, ok := d.Position()
+		require.True(t, ok)
+		assert.Equal(t, 1, pos.Line)
+	})
+
+	t.Run("clicking the file position jumps to the next file", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		click(d, statusColumn(t, d, "file ")+2, 7)
+		pos, ok := d.Position()
+		require.True(t, ok)
+		assert.Equal(t, 2, pos.File)
+		assert.Contains(t, statusBar(d), "file 2/2")
+	})
+
+	t.Run("dragging selects lines until the next key", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		d.Send(tea.MouseMsg{X: 5, Y: 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+		d.Send(tea.MouseMsg{X: 5, Y: 4, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+		d.Send(tea.MouseMsg{X: 5, Y: 4, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+		assert.Contains(t, statusBar(d), "3 lines selected")
+		assert.Contains(t, d.Frame(), "line 1 of a.go", "selecting doesn't collapse or scroll")
+
+		require.NoError(t, d.Press("j"))
+		assert.NotContains(t, statusBar(d), "selected")
+	})
+}
+
+func TestStoryModel_Mouse(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "First", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}},
+			{Role: "core", Title: "Second", Hunks: []diffview.HunkRef{{File: "b.go", HunkIndex: 0}}},
+		},
+	}
+	newDriver := func() *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("a.go", "b.go"), story), 120, 8)
+	}
+
+	t.Run("clicking the section position jumps to the next section", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		click(d, statusColumn(t, d, "section ")+2, 7)
+		assert.Contains(t, statusBar(d), "section 2/2: Second")
+		assert.Contains(t, d.Frame(), "line 1 of b.go")
+	})
+
+	t.Run("clicking a hunk header collapses the hunk", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+		lines := strings.Split(d.Frame(), "\n")
+		header := -1
+		for i, line := range lines {
+			if strings.Contains(line, "@@") {
+				header = i
+				break
+			}
+		}
+		require.GreaterOrEqual(t, header, 0)
+
+		click(d, 5, header)
+		assert.Contains(t, d.Frame(), "▸ collapsed")
+		assert.NotContains(t, d.Frame(), "line 1 of a.go")
+	})
+}
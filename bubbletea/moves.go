@@ -0,0 +1,41 @@
+package bubbletea
+
+import (
+	"fmt"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// detectMoves returns detector's moves in diff, or none without a detector.
+func detectMoves(diff *diffview.Diff, detector diffview.MoveDetector) []diffview.Move {
+	if diff == nil || detector == nil {
+		return nil
+	}
+	return detector.DetectMoves(diff)
+}
+
+// followMove scrolls to the other side of the first move in view that
+// isn't above the top row, saying where the lines went or came from.
+func (m *Model) followMove() {
+	_, layout := renderDiffLayout(m.diffConfig())
+	top, bottom := m.viewport.YOffset, m.viewport.YOffset+m.viewport.Height
+	for _, side := range layout.moveRows {
+		if side.end < top || side.start >= bottom {
+			continue
+		}
+		for _, other := range layout.moveRows {
+			if other.move != side.move || other.deleted == side.deleted {
+				continue
+			}
+			move := m.moves[side.move]
+			m.viewport.SetYOffset(other.start)
+			if side.deleted {
+				m.notice = fmt.Sprintf("moved to %s:%d", move.To.Path, move.To.Start)
+			} else {
+				m.notice = fmt.Sprintf("moved from %s:%d", move.From.Path, move.From.Start)
+			}
+			return
+		}
+	}
+	m.notice = "no moved lines in view"
+}
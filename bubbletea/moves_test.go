@@ -0,0 +1,80 @@
+package bubbletea_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	dv "github.com/fwojciec/diffstory/lipgloss"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModel_Moves(t *testing.T) {
+	t.Parallel()
+
+	block := []string{"func validate(token string) error {", "\treturn check(token)", "}"}
+	var deleted, added []diffview.Line
+	for i, content := range block {
+		deleted = append(deleted, diffview.Line{Type: diffview.LineDeleted, Content: content + "\n", OldLineNum: i + 1})
+		added = append(added, diffview.Line{Type: diffview.LineAdded, Content: content + "\n", NewLineNum: i + 3})
+	}
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{OldPath: "auth.go", NewPath: "auth.go", Operation: diffview.FileModified, Hunks: []diffview.Hunk{{OldStart: 1, OldCount: 3, Lines: deleted}}},
+		{OldPath: "token.go", NewPath: "token.go", Operation: diffview.FileModified, Hunks: []diffview.Hunk{{NewStart: 3, NewCount: 3, Lines: added}}},
+	}}
+	detector := &mock.MoveDetector{
+		DetectMovesFn: func(*diffview.Diff) []diffview.Move {
+			return []diffview.Move{{
+				From: diffview.MovedLines{Path: "auth.go", Start: 1, Count: 3},
+				To:   diffview.MovedLines{Path: "token.go", Start: 3, Count: 3},
+			}}
+		},
+	}
+
+	t.Run("styles both sides of a move", func(t *testing.T) {
+		t.Parallel()
+
+		var model tea.Model = bubbletea.NewModel(diff,
+			bubbletea.WithTheme(dv.TestTheme()),
+			bubbletea.WithRenderer(trueColorRenderer()),
+			bubbletea.WithMoveDetector(detector),
+		)
+		model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+		// TestTheme blends Function #0000ff into the background at 15%
+		lines := strings.Split(model.View(), "\n")
+		require.Greater(t, len(lines), 9)
+		for _, row := range []int{2, 3, 4, 7, 8, 9} {
+			assert.Contains(t, lines[row], "48;2;0;0;38", "row %d", row)
+		}
+		assert.NotContains(t, lines[1], "48;2;0;0;38")
+	})
+
+	t.Run("m jumps between the sides of a move", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewModel(diff, bubbletea.WithMoveDetector(detector)), 80, 7)
+		require.NotContains(t, d.Frame(), "+func validate")
+
+		require.NoError(t, d.Press("m"))
+		assert.Contains(t, d.Frame(), "+func validate")
+		assert.Contains(t, statusBar(d), "moved to token.go:3")
+
+		require.NoError(t, d.Press("m"))
+		assert.Contains(t, strings.Split(d.Frame(), "\n")[0], "-func validate")
+		assert.Contains(t, statusBar(d), "moved from auth.go:1")
+	})
+
+	t.Run("says when no moved lines are in view", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewModel(diff), 80, 7)
+
+		require.NoError(t, d.Press("m"))
+		assert.Contains(t, statusBar(d), "no moved lines in view")
+	})
+}
@@ -0,0 +1,70 @@
+package bubbletea
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SpeakFunc reads text aloud, such as through a text-to-speech command,
+// stopping whatever it read before.
+type SpeakFunc func(text string) error
+
+// narrationMaxLines is the most lines of narration the panel shows; longer
+// narrations are cut off.
+const narrationMaxLines = 6
+
+// narration returns what the presenter says over the view: the section on
+// screen's narration, or on the intro slide the story's summary.
+func (m StoryModel) narration() string {
+	if m.story == nil {
+		return ""
+	}
+	if m.onIntro() {
+		return m.story.Summary
+	}
+	idx := m.visibleSectionIndex()
+	if idx < 0 || idx >= len(m.story.Sections) {
+		return ""
+	}
+	return m.story.Sections[idx].Narration
+}
+
+// toggleNarration shows or hides the narration panel, reading the narration
+// aloud as it opens.
+func (m *StoryModel) toggleNarration() tea.Cmd {
+	m.narrating = !m.narrating
+	return m.speakNarration()
+}
+
+// speakNarration returns a command that reads the narration on screen
+// aloud, or nil if the panel is closed, there is nothing to read or no
+// speaker is configured.
+func (m StoryModel) speakNarration() tea.Cmd {
+	text := m.narration()
+	if !m.narrating || m.speak == nil || text == "" {
+		return nil
+	}
+	speak := m.speak
+	return func() tea.Msg {
+		if err := speak(text); err != nil {
+			return ToastMsg{Text: "speaking narration: " + err.Error(), Err: true}
+		}
+		return nil
+	}
+}
+
+// renderNarration renders the narration panel: a rule across the screen
+// above the narration, wrapped to the screen's width.
+func (m StoryModel) renderNarration() string {
+	rule := m.newStyle().Foreground(lipgloss.Color(m.palette.UIForeground)).Render(strings.Repeat("─", m.width))
+	text := m.narration()
+	style := m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground))
+	if text == "" {
+		text = "No narration for this section"
+		style = m.newStyle().Foreground(lipgloss.Color(m.palette.Context))
+	}
+	body := style.Width(m.width).Padding(0, 1).MaxHeight(narrationMaxLines).Render(text)
+	return lipgloss.JoinVertical(lipgloss.Left, rule, body)
+}
@@ -0,0 +1,88 @@
+package bubbletea_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spySpeaker records what it was asked to read aloud.
+type spySpeaker struct {
+	mu     sync.Mutex
+	spoken []string
+	err    error
+}
+
+func (s *spySpeaker) speak(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spoken = append(s.spoken, text)
+	return s.err
+}
+
+func (s *spySpeaker) Spoken() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spoken
+}
+
+func narratedStory() *diffview.StoryClassification {
+	return &diffview.StoryClassification{
+		Summary: "Add an endpoint and its store",
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Endpoint", Narration: "First we add the endpoint.", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Store", Hunks: []diffview.HunkRef{{File: "store.go", HunkIndex: 0}}},
+		},
+	}
+}
+
+func TestStoryModel_NarrationPanelShowsSectionNarration(t *testing.T) {
+	t.Parallel()
+
+	d := bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("api.go", "store.go"), narratedStory()), 80, 20)
+	assert.NotContains(t, d.Frame(), "First we add the endpoint.")
+
+	require.NoError(t, d.Press("v"))
+	frame := d.Frame()
+	assert.Contains(t, frame, "First we add the endpoint.")
+	assert.Len(t, strings.Split(frame, "\n"), 20, "the panel takes rows from the code")
+
+	require.NoError(t, d.Press("s"))
+	assert.Contains(t, d.Frame(), "No narration for this section")
+
+	require.NoError(t, d.Press("v"))
+	assert.NotContains(t, d.Frame(), "No narration for this section")
+}
+
+func TestStoryModel_NarrationSpokenAsSectionsChange(t *testing.T) {
+	t.Parallel()
+
+	spy := &spySpeaker{}
+	m := bubbletea.NewStoryModel(multiFileDiff("api.go", "store.go"), narratedStory(),
+		bubbletea.WithIntroSlide(),
+		bubbletea.WithStorySpeaker(spy.speak),
+	)
+	d := bubbletea.NewDriver(m, 80, 20)
+
+	// Nothing is read until the panel opens, and sections without
+	// narration are skipped
+	require.NoError(t, d.Press("s", "S", "v", "s", "s"))
+	assert.Equal(t, []string{"Add an endpoint and its store", "First we add the endpoint."}, spy.Spoken())
+}
+
+func TestStoryModel_NarrationSpeakerErrorShowsToast(t *testing.T) {
+	t.Parallel()
+
+	spy := &spySpeaker{err: errors.New("say not found")}
+	m := bubbletea.NewStoryModel(multiFileDiff("api.go", "store.go"), narratedStory(), bubbletea.WithStorySpeaker(spy.speak))
+	d := bubbletea.NewDriver(m, 80, 20)
+
+	require.NoError(t, d.Press("v"))
+	assert.Contains(t, d.Frame(), "speaking narration: say not found")
+}
@@ -0,0 +1,51 @@
+package bubbletea
+
+import (
+	"fmt"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PaneView is what a pane beside the viewer shows of a file.
+type PaneView int
+
+// Views of a file a pane can show.
+const (
+	PaneFile  PaneView = iota // The whole file, from the line
+	PaneBlame                 // Who last changed each line, from the line
+	PaneLog                   // The commits that changed the file
+)
+
+// String names the view, as toasts show it.
+func (v PaneView) String() string {
+	switch v {
+	case PaneBlame:
+		return "blame"
+	case PaneLog:
+		return "log"
+	default:
+		return "file"
+	}
+}
+
+// PaneFunc returns the command that opens view of path, at line, in a pane
+// beside the viewer, such as a tmux split, leaving the viewer on screen.
+type PaneFunc func(view PaneView, path string, line int) *exec.Cmd
+
+// openInPane returns a command that opens view of the source line shown at
+// or below row in a pane beside the viewer, which keeps running. Returns nil
+// if no pane is configured or there is no line to open.
+func openInPane(pane PaneFunc, view PaneView, layout diffLayout, row int) tea.Cmd {
+	if pane == nil {
+		return nil
+	}
+	src, ok := layout.sourceAt(row)
+	if !ok {
+		return nil
+	}
+	cmd := pane(view, src.path, src.line)
+	return func() tea.Msg {
+		return toastResult(fmt.Sprintf("opened %s of %s in a pane", view, src.path), "opening pane", cmd.Run())
+	}
+}
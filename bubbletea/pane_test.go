@@ -0,0 +1,120 @@
+package bubbletea_test
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paneSpy records what a PaneFunc was asked to open.
+type paneSpy struct {
+	view bubbletea.PaneView
+	path string
+	line int
+}
+
+func (s *paneSpy) open(view bubbletea.PaneView, path string, line int) *exec.Cmd {
+	s.view, s.path, s.line = view, path, line
+	return exec.Command("true")
+}
+
+// skipWithoutUnixCommands skips tests that run the true and false commands,
+// which a stock Windows install doesn't have.
+func skipWithoutUnixCommands(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("no true or false commands on Windows")
+	}
+}
+
+func TestModel_PaneKeysOpenViewsOfTopVisibleLine(t *testing.T) {
+	t.Parallel()
+	skipWithoutUnixCommands(t)
+
+	tests := []struct {
+		key  string
+		view bubbletea.PaneView
+	}{
+		{"p", bubbletea.PaneFile},
+		{"b", bubbletea.PaneBlame},
+		{"L", bubbletea.PaneLog},
+	}
+	for _, tt := range tests {
+		t.Run(tt.view.String(), func(t *testing.T) {
+			t.Parallel()
+
+			spy := &paneSpy{}
+			var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go", "b.go"), bubbletea.WithPane(spy.open))
+			model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+			model = sendKeys(t, model, typeText("]"), typeText("j"), typeText("j"), typeText("j"))
+
+			_, cmd := model.Update(typeText(tt.key))
+
+			require.NotNil(t, cmd)
+			assert.Equal(t, tt.view, spy.view)
+			assert.Equal(t, "b.go", spy.path)
+			assert.Equal(t, 2, spy.line)
+			msg, ok := cmd().(bubbletea.ToastMsg)
+			require.True(t, ok)
+			assert.False(t, msg.Err)
+			assert.Equal(t, "opened "+tt.view.String()+" of b.go in a pane", msg.Text)
+		})
+	}
+}
+
+func TestModel_PaneWithoutCommandDoesNothing(t *testing.T) {
+	t.Parallel()
+
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go"))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("p"))
+
+	assert.Nil(t, cmd)
+}
+
+func TestModel_PaneReportsFailure(t *testing.T) {
+	t.Parallel()
+	skipWithoutUnixCommands(t)
+
+	pane := func(bubbletea.PaneView, string, int) *exec.Cmd { return exec.Command("false") }
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go"), bubbletea.WithPane(pane))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	_, cmd := model.Update(typeText("b"))
+
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(bubbletea.ToastMsg)
+	require.True(t, ok)
+	assert.True(t, msg.Err)
+	assert.Contains(t, msg.Text, "opening pane")
+}
+
+func TestStoryModel_PaneOpensBlame(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("core.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Core", Hunks: []diffview.HunkRef{{File: "core.go", HunkIndex: 0}}},
+		},
+	}
+
+	spy := &paneSpy{}
+	var model tea.Model = bubbletea.NewStoryModel(diff, story, bubbletea.WithStoryPane(spy.open))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	model = sendKeys(t, model, typeText("j"), typeText("j"))
+	_, cmd := model.Update(typeText("b"))
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, bubbletea.PaneBlame, spy.view)
+	assert.Equal(t, "core.go", spy.path)
+	assert.Equal(t, 1, spy.line)
+}
@@ -0,0 +1,55 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// PatchFunc applies diff to the working tree, or reverts it there if
+// reverse is set, such as with git apply.
+type PatchFunc func(diff *diffview.Diff, reverse bool) error
+
+// patchRequest is a section's hunks waiting for the reader to confirm
+// applying or reverting them.
+type patchRequest struct {
+	section int // index into story.Sections
+	reverse bool
+}
+
+// verb names what the request does to the working tree.
+func (r patchRequest) verb() string {
+	if r.reverse {
+		return "revert"
+	}
+	return "apply"
+}
+
+// patchDoneMsg reports the outcome of a patch request.
+type patchDoneMsg struct {
+	request patchRequest
+	err     error
+}
+
+// runPatch returns a command that applies or reverts diff with patch.
+func runPatch(patch PatchFunc, diff *diffview.Diff, req patchRequest) tea.Cmd {
+	return func() tea.Msg {
+		return patchDoneMsg{request: req, err: patch(diff, req.reverse)}
+	}
+}
+
+// notice describes the outcome for the status bar, keeping only the first
+// line of an error.
+func (msg patchDoneMsg) notice() string {
+	if msg.err != nil {
+		first, _, _ := strings.Cut(msg.err.Error(), "\n")
+		return fmt.Sprintf("%s failed: %s", msg.request.verb(), first)
+	}
+	past := "applied"
+	if msg.request.reverse {
+		past = "reverted"
+	}
+	return fmt.Sprintf("%s section %d in worktree", past, msg.request.section+1)
+}
@@ -0,0 +1,27 @@
+package bubbletea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// PermalinkFunc returns the web URL for line of path at the reviewed commit,
+// such as a GitHub blob link.
+type PermalinkFunc func(path string, line int) string
+
+// copyPermalink copies the web URL of the source line shown at or below row,
+// returning a toast of the outcome. Does nothing if links or the clipboard
+// aren't configured.
+func copyPermalink(link PermalinkFunc, clipboard diffview.Clipboard, layout diffLayout, row int) tea.Cmd {
+	if link == nil || clipboard == nil {
+		return nil
+	}
+	src, ok := layout.sourceAt(row)
+	if !ok {
+		return nil
+	}
+	url := link(src.path, src.line)
+	return func() tea.Msg {
+		return toastResult("link copied to clipboard", "copying link", clipboard.Copy(url))
+	}
+}
@@ -0,0 +1,117 @@
+package bubbletea
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// judgmentWriter saves snapshots of the judgments to a store from commands
+// that may run concurrently, skipping any snapshot older than one already
+// saved so an earlier write never overwrites a later one.
+type judgmentWriter struct {
+	store diffview.JudgmentStore
+	path  string
+
+	mu      sync.Mutex
+	taken   int // snapshots handed out
+	written int // newest snapshot saved
+}
+
+// judgmentsSavedMsg reports the outcome of saving a snapshot of the
+// judgments.
+type judgmentsSavedMsg struct {
+	snapshot int
+	err      error
+}
+
+// next numbers a new snapshot.
+func (w *judgmentWriter) next() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.taken++
+	return w.taken
+}
+
+// write saves judgments as the given snapshot, unless a newer one is
+// already saved.
+func (w *judgmentWriter) write(snapshot int, judgments []diffview.Judgment) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if snapshot < w.written {
+		return nil
+	}
+	if err := w.store.Save(w.path, judgments); err != nil {
+		return err
+	}
+	w.written = snapshot
+	return nil
+}
+
+// stale reports whether a newer snapshot than the given one is saved, so
+// the given one's outcome no longer matters.
+func (w *judgmentWriter) stale(snapshot int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return snapshot < w.written
+}
+
+// save returns a command that saves judgments.
+func (w *judgmentWriter) save(judgments []diffview.Judgment) tea.Cmd {
+	snapshot := w.next()
+	return func() tea.Msg {
+		return judgmentsSavedMsg{snapshot: snapshot, err: w.write(snapshot, judgments)}
+	}
+}
+
+// persistJudgments returns a command that saves the judgments, reporting
+// the outcome with a judgmentsSavedMsg.
+func (m *EvalModel) persistJudgments() tea.Cmd {
+	if m.writer == nil {
+		return nil
+	}
+	return m.writer.save(m.Judgments())
+}
+
+// judgmentsSaved shows the outcome of a save: a toast if it succeeded, or a
+// prompt to retry if it failed and nothing newer has been saved since.
+func (m *EvalModel) judgmentsSaved(msg judgmentsSavedMsg) tea.Cmd {
+	if m.writer.stale(msg.snapshot) {
+		return nil
+	}
+	m.saveErr = msg.err
+	if msg.err != nil {
+		return nil
+	}
+	return notify("judgment saved")
+}
+
+// handleSaveErrorKeys handles the prompt to retry a failed save, which
+// holds every key but quitting until the reviewer retries or dismisses it.
+func (m EvalModel) handleSaveErrorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.RetrySave):
+		m.saveErr = nil
+		return m, m.persistJudgments()
+	case key.Matches(msg, m.keymap.DismissSaveError):
+		m.saveErr = nil
+		return m, func() tea.Msg {
+			return ToastMsg{Text: "judgments not saved; the next judgment or quitting saves them", Err: true}
+		}
+	case key.Matches(msg, m.keymap.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// SaveJudgments saves the judgments to the judgment store, if there is one,
+// before returning. Programs call it after the reviewer quits, so a save
+// still running or one that failed doesn't lose their work.
+func (m EvalModel) SaveJudgments() error {
+	if m.writer == nil {
+		return nil
+	}
+	return m.writer.write(m.writer.next(), m.Judgments())
+}
@@ -0,0 +1,78 @@
+package bubbletea_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalModel_SaveErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+	}
+
+	// failingStore fails saves until fixed, keeping the judgments of the
+	// last save that succeeded.
+	type failingStore struct {
+		mu    sync.Mutex
+		fail  bool
+		saved []diffview.Judgment
+	}
+	newDriver := func(s *failingStore) *bubbletea.Driver {
+		store := &mock.JudgmentStore{SaveFn: func(_ string, judgments []diffview.Judgment) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.fail {
+				return errors.New("disk full")
+			}
+			s.saved = judgments
+			return nil
+		}}
+		return bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithJudgmentStore(store, "judgments.jsonl")), 120, 20)
+	}
+
+	t.Run("prompts to retry a failed save", func(t *testing.T) {
+		t.Parallel()
+
+		s := &failingStore{fail: true}
+		d := newDriver(s)
+
+		require.NoError(t, d.Press("p"))
+		assert.Contains(t, statusBar(d), "Judgments not saved: disk full. r retry │ esc dismiss")
+
+		require.NoError(t, d.Press("f"))
+		assert.Contains(t, d.Frame(), "● Pass  ○ Fail", "the prompt holds other keys")
+
+		s.mu.Lock()
+		s.fail = false
+		s.mu.Unlock()
+		require.NoError(t, d.Press("r"))
+		assert.Contains(t, statusBar(d), "judgment saved")
+		require.Len(t, s.saved, 1)
+		assert.True(t, s.saved[0].Pass)
+	})
+
+	t.Run("dismissing keeps the judgments for the next save", func(t *testing.T) {
+		t.Parallel()
+
+		s := &failingStore{fail: true}
+		d := newDriver(s)
+
+		require.NoError(t, d.Press("p", "esc"))
+		assert.Contains(t, statusBar(d), "judgments not saved")
+
+		s.mu.Lock()
+		s.fail = false
+		s.mu.Unlock()
+		require.NoError(t, d.Model().(bubbletea.EvalModel).SaveJudgments())
+		require.Len(t, s.saved, 1)
+	})
+}
@@ -0,0 +1,49 @@
+package bubbletea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Picker is a Bubble Tea model for choosing one of several items, such as
+// review sessions or saved cases. Typing fuzzy-filters the list; enter
+// picks the selected item and esc cancels.
+type Picker struct {
+	finder        fileFinder
+	width, height int
+	picked        int
+}
+
+// NewPicker returns a picker listing labels, one per item.
+func NewPicker(labels []string) Picker {
+	return Picker{finder: newFileFinder(labels), picked: -1}
+}
+
+// Picked returns the index in labels of the chosen item, or -1 if none was
+// chosen.
+func (p Picker) Picked() int {
+	return p.picked
+}
+
+// Init implements tea.Model.
+func (p Picker) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (p Picker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if done, picked := p.finder.update(msg); done {
+			p.picked = picked
+			return p, tea.Quit
+		}
+	}
+	return p, nil
+}
+
+// View implements tea.Model.
+func (p Picker) View() string {
+	return p.finder.view(p.width, p.height, evalFinderStyles())
+}
@@ -0,0 +1,39 @@
+package bubbletea_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPicker(t *testing.T) {
+	t.Parallel()
+
+	labels := []string{"#2  2025-01-16 09:00", "#1  2025-01-15 10:30"}
+
+	t.Run("picks the filtered item with enter", func(t *testing.T) {
+		t.Parallel()
+
+		var m tea.Model = bubbletea.NewPicker(labels)
+		m, _ = m.Update(tea.WindowSizeMsg{Width: 60, Height: 10})
+		assert.Contains(t, m.View(), "2025-01-16")
+
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("#1")})
+		m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.Equal(t, 1, m.(bubbletea.Picker).Picked())
+		assert.NotNil(t, cmd)
+	})
+
+	t.Run("picks nothing on esc", func(t *testing.T) {
+		t.Parallel()
+
+		var m tea.Model = bubbletea.NewPicker(labels)
+		m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.Equal(t, -1, m.(bubbletea.Picker).Picked())
+		assert.NotNil(t, cmd)
+	})
+}
@@ -0,0 +1,312 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// advanceMsg moves a presentation on to the next section, unless the
+// section changed, or presenting stopped or paused, since it was scheduled.
+type advanceMsg struct{ id int }
+
+// laser brackets lines of a file on screen, for pointing at code while
+// talking it through. Rows are in the content, not the screen.
+type laser struct {
+	prompting bool   // reading the range from the keyboard
+	input     string // range typed so far, such as "12-18"
+	path      string // file whose lines are asked for: the one at the top of the view
+	active    bool
+	from, to  int // rows bracketed
+}
+
+// ParseAutoAdvance parses how long a presentation stays on each section
+// before moving on by itself: seconds, such as "30", or a duration, such as
+// "1m30s". Empty means it doesn't.
+func ParseAutoAdvance(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid auto-advance %q: must not be negative", s)
+		}
+		return time.Duration(n) * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid auto-advance %q: use seconds (e.g. 30) or a duration (e.g. 1m30s)", s)
+	}
+	return d, nil
+}
+
+// togglePresenter switches presenter mode, where sections open with a
+// large header and the status bar shows only the section, and returns the
+// command that advances the presentation if it auto-advances.
+func (m *StoryModel) togglePresenter() tea.Cmd {
+	m.presenting = !m.presenting
+	m.paused = false
+	m.laser = laser{}
+	m.advanceID++
+	return m.advanceCmd()
+}
+
+// togglePause stops auto-advancing the presentation, or starts it again
+// with the whole interval on the section on screen.
+func (m *StoryModel) togglePause() tea.Cmd {
+	m.paused = !m.paused
+	m.advanceID++
+	return m.advanceCmd()
+}
+
+// sectionChanged clears the laser, which pointed into the last section,
+// restarts the auto-advance interval, and reads the new section's narration
+// aloud.
+func (m *StoryModel) sectionChanged() tea.Cmd {
+	m.laser = laser{}
+	m.advanceID++
+	return tea.Batch(m.speakNarration(), m.advanceCmd())
+}
+
+// advanceCmd returns the command that moves the presentation on after the
+// auto-advance interval, or nil when it doesn't auto-advance: outside
+// presenter mode, while paused and on the last section.
+func (m StoryModel) advanceCmd() tea.Cmd {
+	if !m.presenting || m.paused || m.autoAdvance <= 0 {
+		return nil
+	}
+	if current, total, _ := m.currentSection(); current >= total {
+		return nil
+	}
+	id := m.advanceID
+	return tea.Tick(m.autoAdvance, func(time.Time) tea.Msg { return advanceMsg{id: id} })
+}
+
+// advance moves the presentation on to the next section if msg is still
+// due.
+func (m *StoryModel) advance(msg advanceMsg) tea.Cmd {
+	if msg.id != m.advanceID || !m.presenting || m.paused {
+		return nil
+	}
+	m.gotoNextSection()
+	return m.sectionChanged()
+}
+
+// startLaser asks for the lines to bracket of the file at the top of the
+// view, or clears the lines bracketed.
+func (m *StoryModel) startLaser() {
+	if m.laser.active {
+		m.laser = laser{}
+		return
+	}
+	src, ok := m.contentLayout().sourceAt(m.viewport.YOffset)
+	if m.onIntro() || !ok {
+		m.notice = "no lines to highlight"
+		return
+	}
+	m.laser = laser{prompting: true, path: src.path}
+}
+
+// updateLaser reads the line range typed at the prompt: digits and a dash,
+// then enter to bracket the lines or esc to give up.
+func (m *StoryModel) updateLaser(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.laser = laser{}
+	case tea.KeyEnter:
+		m.fireLaser()
+	case tea.KeyBackspace:
+		if m.laser.input != "" {
+			_, size := utf8.DecodeLastRuneInString(m.laser.input)
+			m.laser.input = m.laser.input[:len(m.laser.input)-size]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if r >= '0' && r <= '9' || r == '-' {
+				m.laser.input += string(r)
+			}
+		}
+	}
+}
+
+// fireLaser brackets the rows showing the lines typed at the prompt,
+// saying why when it can't.
+func (m *StoryModel) fireLaser() {
+	path := m.laser.path
+	from, to, err := parseLineRange(m.laser.input)
+	m.laser = laser{}
+	if err != nil {
+		m.notice = err.Error()
+		return
+	}
+	first, last, ok := m.contentLayout().rowsOf(path, from, to)
+	if !ok {
+		m.notice = fmt.Sprintf("lines %d-%d of %s aren't on screen", from, to, path)
+		return
+	}
+	m.laser = laser{active: true, path: path, from: first, to: last}
+	if first < m.viewport.YOffset || last >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(first)
+	}
+}
+
+// parseLineRange parses a line number, such as "12", or a range of them,
+// such as "12-18".
+func parseLineRange(s string) (from, to int, err error) {
+	first, last, isRange := strings.Cut(s, "-")
+	from, err = strconv.Atoi(first)
+	if err != nil || from < 1 {
+		return 0, 0, fmt.Errorf("invalid line range %q: want a line, such as 12, or lines, such as 12-18", s)
+	}
+	if !isRange {
+		return from, from, nil
+	}
+	to, err = strconv.Atoi(last)
+	if err != nil || to < from {
+		return 0, 0, fmt.Errorf("invalid line range %q: want a line, such as 12, or lines, such as 12-18", s)
+	}
+	return from, to, nil
+}
+
+// rowsOf returns the first and last rows showing lines from through to of
+// the file at path, counting the rows a wrapped line continues on. Reports
+// false if none of them were rendered.
+func (l diffLayout) rowsOf(path string, from, to int) (first, last int, ok bool) {
+	for i, src := range l.sourceRows {
+		if src.path != path || src.line < from || src.line > to {
+			continue
+		}
+		if !ok {
+			first, ok = src.row, true
+		}
+		last = src.row
+		if i+1 < len(l.sourceRows) && l.sourceRows[i+1].path == path {
+			last = max(last, l.sourceRows[i+1].row-1)
+		}
+	}
+	return first, last, ok
+}
+
+// laserPrompt returns the status bar's prompt for the lines to highlight.
+func (m StoryModel) laserPrompt() string {
+	return fmt.Sprintf("highlight lines of %s: %s▏", m.laser.path, m.laser.input)
+}
+
+// renderLaser brackets the rows of view, the viewport from row top, that
+// the laser points at.
+func (m StoryModel) renderLaser(view string, top int) string {
+	if !m.laser.active {
+		return view
+	}
+	sel := selection{active: true, anchor: m.laser.from, end: m.laser.to}
+	style := m.newStyle().Reverse(true).Bold(true).Foreground(lipgloss.Color(m.palette.Foreground))
+	return highlightSelection(view, top, sel, style)
+}
+
+// presenterHeader renders the large header that opens the section on
+// screen in presenter mode: its title in spaced capitals in a heavy frame,
+// with its position and role below. Returns "" on the intro slide, which
+// is a slide already, and without sections.
+func (m StoryModel) presenterHeader() string {
+	idx := m.visibleSectionIndex()
+	if m.story == nil || idx < 0 || idx >= len(m.story.Sections) {
+		return ""
+	}
+	section := m.story.Sections[idx]
+	inner := max(m.width-4, 1) // frame and padding
+
+	title := spaceLetters(strings.ToUpper(section.Title))
+	if lipgloss.Width(title) > inner {
+		title = section.Title
+	}
+	current, total, _ := m.currentSection()
+	caption := fmt.Sprintf("%d of %d", current, total)
+	if section.Role != "" {
+		caption += " · " + section.Role
+	}
+
+	titleStyle := sectionBannerStyle(section, m.styles, m.renderer)
+	captionStyle := m.newStyle().Foreground(lipgloss.Color(m.palette.Context))
+	frame := m.newStyle().
+		Border(lipgloss.ThickBorder()).
+		BorderForeground(lipgloss.Color(m.palette.UIForeground)).
+		Padding(0, 1).
+		Width(max(m.width-2, 1))
+	return frame.Render(titleStyle.Render(title) + "\n" + captionStyle.Render(caption))
+}
+
+// presenterHeaderHeight returns the rows the presenter's header takes from
+// the top of the code, or 0 when there's none.
+func (m StoryModel) presenterHeaderHeight() int {
+	if !m.presenting {
+		return 0
+	}
+	if header := m.presenterHeader(); header != "" {
+		return lipgloss.Height(header)
+	}
+	return 0
+}
+
+// spaceLetters spaces out the letters of s, and words by three spaces, so
+// it reads larger on screen.
+func spaceLetters(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = strings.Join(strings.Split(w, ""), " ")
+	}
+	return strings.Join(words, "   ")
+}
+
+// presenterStatusBar renders the status bar of presenter mode: the section
+// and whether the presentation advances by itself, leaving out positions
+// and key hints. The laser's prompt, notices and toasts still show.
+func (m StoryModel) presenterStatusBar() string {
+	barStyle := m.newStyle().
+		Background(lipgloss.Color(m.palette.UIBackground)).
+		Foreground(lipgloss.Color(m.palette.Foreground))
+	sepStyle := m.newStyle().
+		Background(lipgloss.Color(m.palette.UIBackground)).
+		Foreground(lipgloss.Color(m.palette.UIForeground))
+	sep := sepStyle.Render(" │ ")
+
+	var parts []string
+	if current, total, title := m.currentSection(); total > 0 {
+		parts = append(parts, barStyle.Render(fmt.Sprintf("%d/%d %s", current, total, title)))
+	}
+	if m.autoAdvance > 0 {
+		state := "▶ auto " + m.autoAdvance.String()
+		if m.paused {
+			state = "⏸ paused"
+		}
+		parts = append(parts, barStyle.Render(state))
+	}
+	switch {
+	case m.laser.prompting:
+		parts = append(parts, barStyle.Render(m.laserPrompt()))
+	case m.notice != "":
+		parts = append(parts, barStyle.Render(m.notice))
+	case m.toast.text != "":
+		parts = append(parts, m.toast.render(barStyle, m.errorStyle()))
+	}
+	content := strings.Join(parts, sep) + barStyle.Render("  ")
+
+	if w := lipgloss.Width(content); m.width > w {
+		content = barStyle.Render(strings.Repeat(" ", m.width-w)) + content
+	}
+	return content
+}
+
+// presenterKey handles the keys of presenter mode, reporting false for
+// other keys: space pauses and resumes auto-advancing.
+func (m *StoryModel) presenterKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if !m.presenting || m.autoAdvance <= 0 || !key.Matches(msg, m.keymap.PauseAdvance) {
+		return nil, false
+	}
+	return m.togglePause(), true
+}
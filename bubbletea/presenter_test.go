@@ -0,0 +1,141 @@
+package bubbletea_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// presenterStory has a section for each of the files of
+// multiFileDiff("a.go", "b.go").
+func presenterStory() *diffview.StoryClassification {
+	return &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "First", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}},
+			{Role: "test", Title: "Second", Hunks: []diffview.HunkRef{{File: "b.go", HunkIndex: 0}}},
+		},
+	}
+}
+
+// bracketed is how the highlight starts a line: bold, in reverse video.
+const bracketed = "\x1b[1;7"
+
+// viewLine returns the line of the model's styled view containing text.
+func viewLine(t *testing.T, d *bubbletea.Driver, text string) string {
+	t.Helper()
+	for _, line := range strings.Split(d.Model().View(), "\n") {
+		if strings.Contains(line, text) {
+			return line
+		}
+	}
+	t.Fatalf("no line contains %q", text)
+	return ""
+}
+
+func TestStoryModel_Presenter(t *testing.T) {
+	t.Parallel()
+
+	newDriver := func(opts ...bubbletea.StoryModelOption) *bubbletea.Driver {
+		opts = append(opts, bubbletea.WithStoryRenderer(trueColorRenderer()))
+		return bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("a.go", "b.go"), presenterStory(), opts...), 100, 16)
+	}
+
+	t.Run("P opens each section with a large header and quiets the status bar", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("P"))
+		frame := d.Frame()
+		assert.Contains(t, frame, "F I R S T")
+		assert.Contains(t, frame, "1 of 2 · core")
+		assert.Contains(t, statusBar(d), "1/2 First")
+		assert.NotContains(t, statusBar(d), "hunk ")
+
+		require.NoError(t, d.Press("s"))
+		assert.Contains(t, d.Frame(), "S E C O N D")
+
+		require.NoError(t, d.Press("P"))
+		assert.NotContains(t, d.Frame(), "S E C O N D")
+		assert.Contains(t, statusBar(d), "hunk ")
+	})
+
+	t.Run("auto-advance moves through the sections to the last", func(t *testing.T) {
+		t.Parallel()
+
+		m := bubbletea.NewStoryModel(multiFileDiff("a.go", "b.go"), presenterStory(),
+			bubbletea.WithStoryPresenter(), bubbletea.WithStoryAutoAdvance(time.Millisecond))
+		d := bubbletea.NewDriver(m, 100, 16, bubbletea.WithCommandTimeout(time.Second))
+
+		pos, _ := d.Position()
+		assert.Equal(t, 2, pos.Section)
+		assert.Contains(t, d.Frame(), "line 1 of b.go")
+	})
+
+	t.Run("space pauses and resumes auto-advance", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(bubbletea.WithStoryPresenter(), bubbletea.WithStoryAutoAdvance(time.Hour))
+		assert.Contains(t, statusBar(d), "▶ auto 1h0m0s")
+
+		require.NoError(t, d.Press("space"))
+		assert.Contains(t, statusBar(d), "⏸ paused")
+
+		require.NoError(t, d.Press("space"))
+		assert.Contains(t, statusBar(d), "▶ auto")
+	})
+
+	t.Run("H brackets the lines typed until esc", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("H"))
+		assert.Contains(t, statusBar(d), "highlight lines of a.go: ")
+		d.Type("3-4")
+		require.NoError(t, d.Press("enter"))
+
+		assert.NotContains(t, viewLine(t, d, "line 2 of a.go"), bracketed)
+		assert.Contains(t, viewLine(t, d, "line 3 of a.go"), bracketed)
+		assert.Contains(t, viewLine(t, d, "line 4 of a.go"), bracketed)
+		assert.NotContains(t, viewLine(t, d, "line 5 of a.go"), bracketed)
+
+		require.NoError(t, d.Press("esc"))
+		assert.NotContains(t, viewLine(t, d, "line 3 of a.go"), bracketed)
+	})
+
+	t.Run("a new section clears the highlight", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("s", "H"))
+		d.Type("3")
+		require.NoError(t, d.Press("enter"))
+		require.Contains(t, viewLine(t, d, "line 3 of b.go"), bracketed)
+
+		require.NoError(t, d.Press("S", "s"))
+		assert.NotContains(t, viewLine(t, d, "line 3 of b.go"), bracketed)
+	})
+
+	t.Run("says when the lines typed can't be highlighted", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("H"))
+		d.Type("9-2")
+		require.NoError(t, d.Press("enter"))
+		assert.Contains(t, statusBar(d), `invalid line range "9-2"`)
+
+		require.NoError(t, d.Press("H"))
+		d.Type("40")
+		require.NoError(t, d.Press("enter"))
+		assert.Contains(t, statusBar(d), "lines 40-40 of a.go aren't on screen")
+	})
+}
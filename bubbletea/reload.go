@@ -0,0 +1,34 @@
+package bubbletea
+
+import "github.com/fwojciec/diffstory"
+
+// ReloadMsg replaces the diff a Model or StoryModel shows, such as after the
+// working tree changes, keeping the reader's place where the new diff allows.
+// StoryModel also takes the new story; Model ignores it.
+type ReloadMsg struct {
+	Diff  *diffview.Diff
+	Story *diffview.StoryClassification
+}
+
+// reanchor returns the scroll offset that keeps the source line at the top
+// of the old layout at the top of the new one. If the new diff no longer
+// shows that file, the offset is kept as is.
+func reanchor(offset int, old, new diffLayout) int {
+	top, ok := old.sourceAt(offset)
+	if !ok {
+		return offset
+	}
+	above := top.row - offset // header rows between the top of the view and the line
+	for _, src := range new.sourceRows {
+		if src.path == top.path && src.line >= top.line {
+			return max(src.row-above, 0)
+		}
+	}
+	// The line is past the end of its file now: keep to the file's last line
+	for i := len(new.sourceRows) - 1; i >= 0; i-- {
+		if src := new.sourceRows[i]; src.path == top.path {
+			return max(src.row-above, 0)
+		}
+	}
+	return offset
+}
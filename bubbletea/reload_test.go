@@ -0,0 +1,76 @@
+package bubbletea_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_ReloadKeepsTopLineInView(t *testing.T) {
+	t.Parallel()
+
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("b.go", "c.go"))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+
+	// Rows: b.go header, hunk header, ten lines, c.go header, hunk header, ...
+	for range 15 {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	}
+	assert.Contains(t, topLine(model), "line 2 of c.go")
+
+	// A new file above pushes c.go down; the view follows it
+	model, _ = model.Update(bubbletea.ReloadMsg{Diff: multiFileDiff("a.go", "b.go", "c.go")})
+	view := model.View()
+	assert.Contains(t, strings.Split(view, "\n")[0], "line 2 of c.go")
+	assert.Contains(t, view, "file 3/3")
+}
+
+func TestModel_ReloadWithoutTopFileKeepsOffset(t *testing.T) {
+	t.Parallel()
+
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go", "b.go"))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+	for range 3 {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	}
+
+	model, _ = model.Update(bubbletea.ReloadMsg{Diff: multiFileDiff("c.go", "d.go")})
+	assert.Contains(t, topLine(model), "line 2 of c.go")
+}
+
+func TestStoryModel_ReloadKeepsSectionAndCollapseState(t *testing.T) {
+	t.Parallel()
+
+	story := func(noise string) *diffview.StoryClassification {
+		return &diffview.StoryClassification{
+			Sections: []diffview.Section{
+				{Title: "Core", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}},
+				{Title: "Generated", Hunks: []diffview.HunkRef{
+					{File: "gen.go", HunkIndex: 0, Category: "noise"},
+					{File: noise, HunkIndex: 0, Category: "noise"},
+				}},
+			},
+		}
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(multiFileDiff("a.go", "gen.go", "mock.go"), story("mock.go"))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	assert.Contains(t, model.View(), "line 1 of gen.go")
+
+	// gen.go stays expanded; the new noise hunk starts collapsed
+	model, _ = model.Update(bubbletea.ReloadMsg{
+		Diff:  multiFileDiff("a.go", "gen.go", "stub.go"),
+		Story: story("stub.go"),
+	})
+	view := model.View()
+	assert.Contains(t, view, "section 2/2: Generated")
+	assert.Contains(t, view, "line 1 of gen.go")
+	assert.Contains(t, view, "stub.go")
+	assert.NotContains(t, view, "line 1 of stub.go")
+}
@@ -3,8 +3,10 @@ package bubbletea
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/fwojciec/diffstory"
 )
 
@@ -14,6 +16,12 @@ type hunkKey struct {
 	hunkIndex int
 }
 
+// lineKey identifies a line in the new version of a file.
+type lineKey struct {
+	file string
+	line int
+}
+
 // renderConfig holds all rendering parameters for renderDiff.
 type renderConfig struct {
 	diff             *diffview.Diff
@@ -23,12 +31,54 @@ type renderConfig struct {
 	languageDetector diffview.LanguageDetector
 	tokenizer        diffview.Tokenizer
 	wordDiffer       diffview.WordDiffer
+	tabWidth         int  // Tab stop interval for line content (0 = default)
+	wrap             bool // Soft-wrap long lines onto continuation rows
+	xOffset          int  // Content columns scrolled off to the left (ignored when wrapping)
+
+	// secrets flags added lines that look like credentials with a badge
+	// in the gutter (optional)
+	secrets diffview.SecretDetector
+
+	// lineIcons holds an icon drawn between the gutter and the line for
+	// each annotated line (optional). See annotationIcons.
+	lineIcons map[lineKey]string
+
+	// moves gives moved lines the moved style on both sides (optional)
+	moves []diffview.Move
+
+	// structured holds the paths of files whose hunk lists key changes
+	// rather than lines (optional). See structureFile.
+	structured map[string]bool
+
+	// summarized holds the paths of files whose hunk lists dependency
+	// changes rather than lines (optional). See dependencyFile.
+	summarized map[string]bool
+
+	// fileBadges holds a label drawn in each annotated file's header,
+	// before its change statistics (optional). See annotationBadges.
+	fileBadges map[string]string
 
 	// Story-aware rendering options (optional)
 	collapsedHunks  map[hunkKey]bool   // Which hunks are collapsed
 	hunkCategories  map[hunkKey]string // Category for each hunk (for styling)
 	collapseText    map[hunkKey]string // Summary text for collapsed hunks
 	originalIndices map[hunkKey]int    // Maps (file, filtered position) -> original hunk index
+
+	// hunkAnnotations holds a one-line note rendered dimmed above each hunk,
+	// such as the story section it belongs to. Annotation rows aren't counted
+	// by computePositions, so only views without hunk navigation set this.
+	hunkAnnotations map[hunkKey]string
+
+	// sectionBanners holds the story section whose banner is drawn before
+	// each hunk that starts one, keyed like the other story maps. Banners go
+	// above the file header when a section starts a file. See sectionStarts.
+	sectionBanners map[hunkKey]diffview.Section
+
+	// fileGroups are the file groups whose header is drawn above each
+	// group's first file, below any commit header (optional)
+	fileGroups []diffview.FileGroup
+	// cache keeps tokens and rendered hunks from earlier renders (optional)
+	cache *renderCache
 }
 
 // minGutterWidth is the minimum width of each line number column in the gutter.
@@ -38,16 +88,154 @@ const minGutterWidth = 4
 // If renderer is nil, the default lipgloss renderer is used.
 // Width is the terminal width for full-width backgrounds.
 func renderDiff(cfg renderConfig) string {
+	content, _ := renderDiffLayout(cfg)
+	return content
+}
+
+// diffLayout records what landed on which row of rendered diff output.
+// Unlike computePositions, the rows account for everything the render adds,
+// such as annotations and collapsed hunks.
+type diffLayout struct {
+	sourceRows []sourceRow // first row of each hunk and content line, in order
+	moveRows   []moveRow   // rows of each side of each move, in order
+}
+
+// moveRow is where one side of a move was rendered.
+type moveRow struct {
+	move       int  // index into renderConfig.moves
+	deleted    bool // the From side rather than the To side
+	start, end int  // rows of its first and last lines
+}
+
+// shift returns the layout with every row moved down by n, for output that
+// follows n rows of other content.
+func (l diffLayout) shift(n int) diffLayout {
+	out := diffLayout{
+		sourceRows: make([]sourceRow, len(l.sourceRows)),
+		moveRows:   make([]moveRow, len(l.moveRows)),
+	}
+	for i, src := range l.sourceRows {
+		src.row += n
+		out.sourceRows[i] = src
+	}
+	for i, mv := range l.moveRows {
+		mv.start += n
+		mv.end += n
+		out.moveRows[i] = mv
+	}
+	return out
+}
+
+// rendered returns content, rendered from row on, as a cached hunk, with
+// the source and move rows recorded for it from sourceStart and moveStart.
+func (l diffLayout) rendered(content string, row, sourceStart, moveStart int) cachedHunk {
+	h := cachedHunk{content: content}
+	for _, src := range l.sourceRows[sourceStart:] {
+		src.row -= row
+		h.sourceRows = append(h.sourceRows, src)
+	}
+	for _, mv := range l.moveRows[moveStart:] {
+		mv.start -= row
+		mv.end -= row
+		h.moveRows = append(h.moveRows, mv)
+	}
+	return h
+}
+
+// appendRendered adds the rows of a cached hunk written from row on.
+func (l *diffLayout) appendRendered(h cachedHunk, row int) {
+	for _, src := range h.sourceRows {
+		src.row += row
+		l.sourceRows = append(l.sourceRows, src)
+	}
+	for _, mv := range h.moveRows {
+		mv.start += row
+		mv.end += row
+		l.moveRows = append(l.moveRows, mv)
+	}
+}
+
+// append adds other's rows after l's.
+func (l *diffLayout) append(other diffLayout) {
+	l.sourceRows = append(l.sourceRows, other.sourceRows...)
+}
+
+// sourceRow ties a row of rendered output to a line of a changed file.
+type sourceRow struct {
+	row  int
+	path string
+	line int // line number in the new version of the file
+}
+
+// sourceAt returns the first source line shown at or below row, or the last
+// one if row is past them all. Reports false if the diff has no lines that
+// exist in the new version, such as when every file was deleted.
+func (l diffLayout) sourceAt(row int) (sourceRow, bool) {
+	if len(l.sourceRows) == 0 {
+		return sourceRow{}, false
+	}
+	for _, src := range l.sourceRows {
+		if src.row >= row {
+			return src, true
+		}
+	}
+	return l.sourceRows[len(l.sourceRows)-1], true
+}
+
+// keyOf returns the key of the file at path's hunk at hunkIdx. When
+// rendering a filtered diff, originalIndices maps the filtered position to
+// the original hunk index for correct lookup in category/collapse maps.
+func (cfg renderConfig) keyOf(path string, hunkIdx int) hunkKey {
+	if idx, ok := cfg.originalIndices[hunkKey{file: path, hunkIndex: hunkIdx}]; ok {
+		return hunkKey{file: path, hunkIndex: idx}
+	}
+	return hunkKey{file: path, hunkIndex: hunkIdx}
+}
+
+// layout returns the rows renderDiffLayout puts each commit header, file
+// and hunk on, without rendering.
+func (cfg renderConfig) layout() diffview.Layout {
+	if cfg.diff == nil {
+		return diffview.Layout{}
+	}
+	key := func(file, hunk int) hunkKey {
+		return cfg.keyOf(cfg.diff.Files[file].Path(), hunk)
+	}
+	opts := diffview.LayoutOptions{
+		Banner: func(file, hunk int) bool {
+			_, ok := cfg.sectionBanners[key(file, hunk)]
+			return ok
+		},
+		Note: func(file, hunk int) bool {
+			return cfg.hunkAnnotations[key(file, hunk)] != ""
+		},
+		Collapsed: func(file, hunk int) bool {
+			return cfg.collapsedHunks[key(file, hunk)]
+		},
+		Groups: cfg.fileGroups,
+	}
+	if cfg.wrap {
+		opts.LineRows = wrappedRows(cfg.diff, cfg.width, cfg.tabWidth)
+	}
+	return diffview.NewLayout(cfg.diff, opts)
+}
+
+// renderDiffLayout renders the diff like renderDiff and also returns its
+// layout.
+func renderDiffLayout(cfg renderConfig) (string, diffLayout) {
 	diff := cfg.diff
 	styles := cfg.styles
 	renderer := cfg.renderer
 	width := cfg.width
 	if diff == nil {
-		return ""
+		return "", diffLayout{}
 	}
 
 	// Calculate dynamic gutter width based on max line number in the diff
 	gutterWidth := calculateGutterWidth(diff)
+	if cfg.cache != nil {
+		cfg.cache.begin(cfg)
+	}
 
 	// Create lipgloss styles from color pairs
 	fileHeaderStyle := styleFromColorPair(styles.FileHeader, renderer)
@@ -60,34 +248,96 @@ func renderDiff(cfg renderConfig) string {
 	deletedGutterStyle := styleFromColorPair(styles.DeletedGutter, renderer)
 	addedHighlightStyle := styleFromColorPair(styles.AddedHighlight, renderer)
 	deletedHighlightStyle := styleFromColorPair(styles.DeletedHighlight, renderer)
+	if styles.Symbols {
+		addedStyle = addedStyle.Bold(true)
+		addedHighlightStyle = addedHighlightStyle.Bold(true).Underline(true)
+		deletedHighlightStyle = deletedHighlightStyle.Underline(true)
+	}
+	oursStyle := styleFromColorPair(styles.Ours, renderer)
+	theirsStyle := styleFromColorPair(styles.Theirs, renderer)
+	movedStyle := styleFromColorPair(styles.Moved, renderer)
+	moved := movedLines(cfg.moves)
 
 	// Create dimmed style for non-core categories
 	dimmedStyle := createDimmedStyle(styles, renderer)
 
 	var sb strings.Builder
-	for _, file := range diff.Files {
+	var layout diffLayout
+	rows, counted := 0, 0
+	currentRow := func() int {
+		rows += strings.Count(sb.String()[counted:], "\n")
+		counted = sb.Len()
+		return rows
+	}
+
+	// Commit headers go above each commit's first file, even if that file
+	// isn't shown. Commits without files get a header all the same.
+	commitStyle := styleFromColorPair(styles.SectionBanner, renderer).Bold(true)
+	nextCommit := 0
+	writeCommits := func(fileIdx int) {
+		for ; nextCommit < len(diff.Commits) && diff.Commits[nextCommit].FirstFile <= fileIdx; nextCommit++ {
+			sb.WriteString(commitStyle.Render(formatCommitHeader(diff.Commits[nextCommit], width)))
+			sb.WriteString("\n")
+		}
+	}
+
+	groupStyle := styleFromColorPair(styles.SectionBanner, renderer)
+	nextGroup := 0
+	for fileIdx, file := range diff.Files {
+		writeCommits(fileIdx)
+		if nextGroup < len(cfg.fileGroups) && cfg.fileGroups[nextGroup].FirstFile == fileIdx {
+			sb.WriteString(groupStyle.Render(formatGroupHeader(cfg.fileGroups[nextGroup], width)))
+			sb.WriteString("\n")
+			nextGroup++
+		}
+
 		// Skip files that shouldn't be rendered (binary files, mode-only changes)
-		if !shouldRenderFile(file) {
+		if !file.Visible() {
 			continue
 		}
 
-		// Detect language for syntax highlighting
-		path := filePath(file)
-		var language string
-		if cfg.languageDetector != nil {
-			language = cfg.languageDetector.DetectFromPath(path)
+		path := file.Path()
+		keyFor := func(hunkIdx int) hunkKey {
+			return cfg.keyOf(path, hunkIdx)
+		}
+		writeBanner := func(hunkIdx int) {
+			if section, ok := cfg.sectionBanners[keyFor(hunkIdx)]; ok {
+				sb.WriteString(sectionBannerStyle(section, styles, renderer).Render(formatSectionBanner(section, width)))
+				sb.WriteString("\n")
+			}
+		}
+		if len(file.Hunks) > 0 {
+			writeBanner(0)
+		}
+
+		// Deleted files have no lines left to point at
+		markSource := func(line int) {
+			if file.Operation != diffview.FileDeleted {
+				layout.sourceRows = append(layout.sourceRows, sourceRow{row: currentRow(), path: file.Path(), line: line})
+			}
 		}
 
+		// Detect language for syntax highlighting
+		language := diffview.DetectLanguage(cfg.languageDetector, file)
+
 		// Render enhanced file header with box-drawing and change statistics
 		// Format: ── filename ─────────────────── +N -M ──
 		added, deleted := file.Stats()
 		stats := fmt.Sprintf("+%d -%d", added, deleted)
 
 		// Build header: "── " + path + " " + fill + " " + stats + " ──"
+		// Transcoded files note their source encoding after the path, and
+		// files not ending lines in LF their line endings
 		prefix := "── "
 		suffix := " ──"
 		middle := prefix + path + " "
+		if note := fileHeaderNote(file); note != "" {
+			middle += "(" + note + ") "
+		}
 		end := " " + stats + suffix
+		if badge := cfg.fileBadges[file.Path()]; badge != "" {
+			end = " " + badge + end
+		}
 
 		// Calculate fill width
 		fillWidth := width - lipgloss.Width(middle) - lipgloss.Width(end)
@@ -109,20 +359,21 @@ func renderDiff(cfg renderConfig) string {
 		}
 
 		for hunkIdx, hunk := range file.Hunks {
-			// When rendering a filtered diff, originalIndices maps the filtered
-			// position to the original hunk index for correct lookup in category/collapse maps.
-			origIdx := hunkIdx
-			if cfg.originalIndices != nil {
-				if idx, ok := cfg.originalIndices[hunkKey{file: path, hunkIndex: hunkIdx}]; ok {
-					origIdx = idx
-				}
+			key := keyFor(hunkIdx)
+			if hunkIdx > 0 {
+				writeBanner(hunkIdx)
+			}
+
+			if note := cfg.hunkAnnotations[key]; note != "" {
+				sb.WriteString(dimmedStyle.Render(formatAnnotation(note, width)))
+				sb.WriteString("\n")
 			}
-			key := hunkKey{file: path, hunkIndex: origIdx}
 
 			// Check if this hunk is collapsed
 			if cfg.collapsedHunks != nil && cfg.collapsedHunks[key] {
 				// Dim collapsed hunks based on category (refactoring/systematic/noise)
 				// Once unfolded, hunks get full styling - dimming is just a "skip this" hint
+				markSource(hunk.NewStart)
 				collapseStyle := hunkHeaderStyle
 				if cfg.hunkCategories != nil {
 					category := cfg.hunkCategories[key]
@@ -145,19 +396,63 @@ func renderDiff(cfg renderConfig) string {
 			currentLineNumStyle := lineNumStyle
 
 			// Render hunk header with styling
+			markSource(hunk.NewStart)
 			header := formatHunkHeader(hunk)
+			if cfg.structured[path] {
+				header = formatStructureHeader(hunk)
+			}
+			if cfg.summarized[path] {
+				header = formatDependencyHeader(hunk)
+			}
 			sb.WriteString(currentHunkHeaderStyle.Render(header))
 			sb.WriteString("\n")
 
-			// Compute word diff segments for paired lines (delete followed by add)
-			lineSegments := computeLinePairSegments(hunk.Lines, cfg.wordDiffer)
-
-			// Pre-tokenize all lines in the hunk together for proper multi-line construct handling
-			// (e.g., /* */ comments, JSDoc). This gives each line correct context-aware tokens.
-			hunkTokens := tokenizeHunkLines(hunk.Lines, language, cfg.tokenizer)
+			// Hunks rendered before with the same settings are reused whole
+			cacheKey, cacheable := cacheKeyOf(path, language, hunk)
+			renderedKey := renderedHunkKey{hunk: cacheKey, gutterWidth: gutterWidth}
+			if cacheable {
+				if cached, ok := cfg.cache.hunk(renderedKey); ok {
+					layout.appendRendered(cached, currentRow())
+					sb.WriteString(cached.content)
+					continue
+				}
+			}
+			hunkStart, hunkRow := sb.Len(), currentRow()
+			sourceStart, moveStart := len(layout.sourceRows), len(layout.moveRows)
+
+			// Control bytes would move the cursor or break alignment, and raw
+			// tabs would be measured against the terminal's own tab stops
+			hunk.Lines = expandLineTabs(escapeLines(hunk.Lines), cfg.tabWidth)
+
+			analysis, analyzed := cfg.cache.analysis(cacheKey)
+			if !analyzed {
+				// Compute word diff segments for paired lines (delete followed by add)
+				analysis.segments = computeLinePairSegments(hunk.Lines, cfg.wordDiffer)
+
+				// Pre-tokenize all lines in the hunk together for proper multi-line construct handling
+				// (e.g., /* */ comments, JSDoc). This gives each line correct context-aware tokens.
+				// Without color, syntax highlighting has nothing to show
+				if !styles.Symbols {
+					analysis.tokens = tokenizeHunkLines(hunk.Lines, language, cfg.tokenizer)
+				}
+				if cacheable {
+					cfg.cache.storeAnalysis(cacheKey, analysis)
+				}
+			}
+			lineSegments, hunkTokens := analysis.segments, analysis.tokens
 
-			// Render lines with gutter and prefixes
+			// Render lines with gutter and prefixes. Deleted lines point at
+			// the new line that took their place.
+			newLine := hunk.NewStart
 			for i, line := range hunk.Lines {
+				if line.Type != diffview.LineDeleted && line.NewLineNum > 0 {
+					newLine = line.NewLineNum
+				}
+				markSource(newLine)
+				if line.Type != diffview.LineDeleted {
+					newLine++
+				}
+
 				// Line number gutter with diff-aware styling
 				var gutterStyle lipgloss.Style
 				var lineStyle lipgloss.Style
@@ -175,23 +470,81 @@ func renderDiff(cfg renderConfig) string {
 					gutterStyle = currentLineNumStyle
 					lineStyle = currentContextStyle
 				}
-				sb.WriteString(formatGutter(line.OldLineNum, line.NewLineNum, gutterWidth, gutterStyle))
 
-				// Add padding space between gutter and code prefix, styled with code line's background
-				sb.WriteString(lineStyle.Render(" "))
+				// Merge lines from a single parent get the ours/theirs colors
+				colors := lineColors(line, styles)
+				switch line.Origin() {
+				case diffview.OriginOurs:
+					gutterStyle = oursStyle
+					lineStyle = oursStyle
+				case diffview.OriginTheirs:
+					gutterStyle = theirsStyle
+					lineStyle = theirsStyle
+				}
+				mv, isMoved := moved[movedKeyFor(path, line)]
+				if isMoved {
+					gutterStyle = movedStyle
+					lineStyle = movedStyle
+					colors = styles.Moved
+					if mv.first {
+						layout.moveRows = append(layout.moveRows, moveRow{move: mv.move, deleted: line.Type == diffview.LineDeleted, start: currentRow()})
+					}
+					if last := len(layout.moveRows) - 1; last >= 0 && layout.moveRows[last].move == mv.move {
+						layout.moveRows[last].end = currentRow()
+					}
+				}
+				if line.Type == diffview.LineAdded && cfg.secrets != nil && cfg.secrets.DetectSecret(line.Content) != "" {
+					sb.WriteString(formatSecretGutter(line.NewLineNum, gutterWidth, gutterStyle))
+				} else {
+					sb.WriteString(formatGutter(line.OldLineNum, line.NewLineNum, gutterWidth, gutterStyle))
+				}
+
+				// Add padding space between gutter and code prefix, styled with code line's background.
+				// Annotated lines show their annotation's icon there instead,
+				// and a file's last line without a newline a dim ¶
+				padding, paddingStyle := " ", lineStyle
+				if styles.Symbols {
+					padding = symbolsMarker(line)
+				}
+				if line.NoNewline && padding == " " {
+					padding = noNewlineMarker
+					paddingStyle = lineStyle.Foreground(lipgloss.Color(styles.LineNumber.Foreground))
+				}
+				if icon := cfg.lineIcons[lineKey{file: path, line: line.NewLineNum}]; icon != "" && line.Type != diffview.LineDeleted {
+					padding, paddingStyle = icon, lineStyle
+				}
+				sb.WriteString(paddingStyle.Render(padding))
 
 				// Get prefix and content
-				prefix := linePrefixFor(line.Type)
+				prefix := linePrefix(line)
 				lineContent := strings.TrimSuffix(line.Content, "\n")
 				fullLine := prefix + lineContent
 
-				// Check if this line has word-level diff segments
+				// Long lines either wrap onto continuation rows or scroll
+				// horizontally; padding covers every row so line backgrounds
+				// reach the right edge
+				padWidth := width
+				rows := 1
+				avail := 0
+				if cfg.wrap && width > 0 {
+					avail = wrapWidth(width, gutterWidth, len(prefix))
+					rows = rowCount(DisplayWidth(lineContent), avail)
+					padWidth = len(prefix) + rows*avail
+				} else if cfg.xOffset > 0 {
+					padWidth = width + cfg.xOffset
+				}
+
+				// Check if this line has word-level diff segments; moved
+				// lines are unchanged, so have none to show
 				segments := lineSegments[i]
+				if isMoved {
+					segments = nil
+				}
 
 				var styledLine string
 				if segments != nil {
 					// Render with word-level highlighting
-					styledLine = renderLineWithSegments(prefix, segments, lineStyle, highlightStyle, width)
+					styledLine = renderLineWithSegments(prefix, segments, lineStyle, highlightStyle, padWidth)
 				} else {
 					// Use pre-computed tokens from hunk-level tokenization
 					var tokens []diffview.Token
@@ -201,34 +554,74 @@ func renderDiff(cfg renderConfig) string {
 
 					if tokens != nil {
 						// Render with syntax highlighting (prefix + tokens)
-						var colors diffview.ColorPair
-						switch line.Type {
-						case diffview.LineAdded:
-							colors = styles.Added
-						case diffview.LineDeleted:
-							colors = styles.Deleted
-						default:
-							colors = styles.Context
-						}
-						styledLine = renderLineWithTokens(prefix, tokens, colors, renderer, width)
+						styledLine = renderLineWithTokens(prefix, tokens, colors, renderer, padWidth)
 					} else {
 						// Plain rendering - entire line including prefix
 						switch line.Type {
-						case diffview.LineAdded:
-							styledLine = currentAddedStyle.Render(padLine(fullLine, width))
-						case diffview.LineDeleted:
-							styledLine = currentDeletedStyle.Render(padLine(fullLine, width))
+						case diffview.LineAdded, diffview.LineDeleted:
+							styledLine = lineStyle.Render(padLine(fullLine, padWidth))
 						default:
-							styledLine = currentContextStyle.Render(fullLine)
+							styledLine = lineStyle.Render(fullLine)
 						}
 					}
 				}
-				sb.WriteString(styledLine)
+				switch {
+				case rows > 1:
+					// Continuation rows keep the gutter's width and color but
+					// leave line numbers blank
+					for r, row := range wrapLine(styledLine, len(prefix), avail, rows) {
+						if r > 0 {
+							sb.WriteString("\n")
+							sb.WriteString(formatGutter(0, 0, gutterWidth, gutterStyle))
+							sb.WriteString(lineStyle.Render(" " + padLine(continuationMarker, len(prefix))))
+						}
+						sb.WriteString(row)
+					}
+				case cfg.xOffset > 0 && !cfg.wrap:
+					sb.WriteString(scrollLine(styledLine, len(prefix), cfg.xOffset))
+				default:
+					sb.WriteString(styledLine)
+				}
 				sb.WriteString("\n")
 			}
+			if cacheable {
+				cfg.cache.storeHunk(renderedKey, layout.rendered(sb.String()[hunkStart:], hunkRow, sourceStart, moveStart))
+			}
 		}
 	}
-	return sb.String()
+	writeCommits(len(diff.Files))
+	return sb.String(), layout
+}
+
+// noNewlineMarker marks the last line of a side of a file that ends without
+// a newline, where git's diff says "\ No newline at end of file".
+const noNewlineMarker = "¶"
+
+// fileHeaderNote returns what a file's header notes after its path: the
+// encoding it was transcoded from, and line endings other than LF, such as
+// "UTF-16LE, CRLF" or "LF→CRLF" for a file converted to CRLF.
+func fileHeaderNote(file diffview.FileDiff) string {
+	var notes []string
+	if file.Encoding != "" {
+		notes = append(notes, file.Encoding)
+	}
+	old, new := file.LineEndings()
+	// An added or deleted file has lines on one side only
+	if old == "" {
+		old = new
+	}
+	if new == "" {
+		new = old
+	}
+	switch {
+	case old != new:
+		notes = append(notes, string(old)+"→"+string(new))
+	case old == diffview.LineEndingMixed:
+		notes = append(notes, "mixed line endings")
+	case old == diffview.LineEndingCRLF:
+		notes = append(notes, string(old))
+	}
+	return strings.Join(notes, ", ")
 }
 
 // createDimmedStyle creates a dimmed style for non-core hunks.
@@ -277,22 +670,151 @@ func renderCollapsedHunk(hunk diffview.Hunk, key hunkKey, cfg renderConfig, head
 	return headerStyle.Render(rangeStr + " " + summary)
 }
 
+// formatAnnotation formats a hunk annotation as a single line no wider than
+// width, truncating with an ellipsis. A width of 0 means no limit.
+func formatAnnotation(note string, width int) string {
+	line := "» " + strings.Join(strings.Fields(note), " ")
+	if width > 0 {
+		line = ansi.Truncate(line, width, "…")
+	}
+	return line
+}
+
+// formatSectionBanner returns a rule naming a story section, filled to width:
+// "━━ core · Title ━━━━━━━━". Long titles are truncated to fit.
+func formatSectionBanner(section diffview.Section, width int) string {
+	const prefix, minFill = "━━ ", 3
+	label := strings.Join(strings.Fields(section.Title), " ")
+	switch {
+	case section.Role != "" && label != "":
+		label = section.Role + " · " + label
+	case section.Role != "":
+		label = section.Role
+	}
+	if width > 0 {
+		label = ansi.Truncate(label, max(width-lipgloss.Width(prefix)-1-minFill, 1), "…")
+	}
+	fill := max(width-lipgloss.Width(prefix+label)-1, minFill)
+	return prefix + label + " " + strings.Repeat("━", fill)
+}
+
+// formatCommitHeader returns a rule introducing a commit, filled to width:
+// "━━ 1a2b3c4 Subject · Author ━━━━━━━━". Long subjects are truncated to fit.
+func formatCommitHeader(commit diffview.Commit, width int) string {
+	const prefix, minFill = "━━ ", 3
+	hash := commit.Hash
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	label := hash + " " + strings.Join(strings.Fields(commit.Subject()), " ")
+	// Show the author's name without the email address
+	if author, _, _ := strings.Cut(commit.Author, " <"); author != "" {
+		label += " · " + author
+	}
+	if width > 0 {
+		label = ansi.Truncate(label, max(width-lipgloss.Width(prefix)-1-minFill, 1), "…")
+	}
+	fill := max(width-lipgloss.Width(prefix+label)-1, minFill)
+	return prefix + label + " " + strings.Repeat("━", fill)
+}
+
+// formatGroupHeader returns the header drawn above a file group, such as
+// "══ pkg/server · 3 files +40 -12 ═════", filled out to width. The double
+// rule sets it apart from the file headers below it.
+func formatGroupHeader(group diffview.FileGroup, width int) string {
+	const prefix, minFill = "══ ", 3
+	files := "files"
+	if group.Files == 1 {
+		files = "file"
+	}
+	label := fmt.Sprintf("%s · %d %s +%d -%d", group.Name, group.Files, files, group.Added, group.Deleted)
+	if width > 0 {
+		label = ansi.Truncate(label, max(width-lipgloss.Width(prefix)-1-minFill, 1), "…")
+	}
+	fill := max(width-lipgloss.Width(prefix+label)-1, minFill)
+	return prefix + label + " " + strings.Repeat("═", fill)
+}
+
+// sectionBannerStyle returns the banner style for a section's role.
+func sectionBannerStyle(section diffview.Section, styles diffview.Styles, renderer *lipgloss.Renderer) lipgloss.Style {
+	colors, ok := styles.SectionRoles[section.Role]
+	if !ok {
+		colors = styles.SectionBanner
+	}
+	return styleFromColorPair(colors, renderer).Bold(true)
+}
+
+// sectionStarts maps the first hunk of each section, in diff order, to its
+// section, for rendering banners where sections begin. Keys use the original
+// hunk indices of diff.
+func sectionStarts(diff *diffview.Diff, sections []diffview.Section) map[hunkKey]diffview.Section {
+	if diff == nil {
+		return nil
+	}
+	order := make(map[hunkKey]int)
+	for _, file := range diff.Files {
+		path := file.Path()
+		for i := range file.Hunks {
+			order[hunkKey{file: path, hunkIndex: i}] = len(order)
+		}
+	}
+
+	starts := make(map[hunkKey]diffview.Section, len(sections))
+	for _, section := range sections {
+		first, firstPos := hunkKey{}, -1
+		for _, ref := range section.Hunks {
+			key := hunkKey{file: ref.File, hunkIndex: ref.HunkIndex}
+			if pos, ok := order[key]; ok && (firstPos < 0 || pos < firstPos) {
+				first, firstPos = key, pos
+			}
+		}
+		if firstPos >= 0 {
+			starts[first] = section
+		}
+	}
+	return starts
+}
+
 // computeLinePairSegments identifies paired delete/add lines and computes word-level diff segments.
 // Returns a map from line index to segments. Lines without word-level diffs have nil segments.
 // Only applies word-level highlighting when there's meaningful shared content (>30% unchanged).
-//
-// Handles both simple pairs (one delete followed by one add) and runs of consecutive
-// deletes followed by consecutive adds (pairs them 1:1 in order).
 func computeLinePairSegments(lines []diffview.Line, wordDiffer diffview.WordDiffer) map[int][]diffview.Segment {
 	if wordDiffer == nil {
 		return nil
 	}
 
 	result := make(map[int][]diffview.Segment)
+	pairs, _ := linePairs(lines)
+	for _, pair := range pairs {
+		delIdx, addIdx := pair[0], pair[1]
+		oldContent := strings.TrimSuffix(lines[delIdx].Content, "\n")
+		newContent := strings.TrimSuffix(lines[addIdx].Content, "\n")
+		oldSegs, newSegs := wordDiffer.Diff(oldContent, newContent)
+
+		// Only use word-level highlighting if there's meaningful shared content.
+		if hasSignificantUnchangedContent(oldSegs) && hasSignificantUnchangedContent(newSegs) {
+			result[delIdx] = oldSegs
+			result[addIdx] = newSegs
+		}
+	}
+
+	return result
+}
 
-	// Find runs of consecutive deleted lines followed by runs of added lines
+// linePairs returns the indices of deleted and added lines that word diff
+// compares, and how many changed lines were left without a partner.
+//
+// Handles both simple pairs (one delete followed by one add) and runs of consecutive
+// deletes followed by consecutive adds (pairs them 1:1 in order).
+func linePairs(lines []diffview.Line) (pairs [][2]int, unpaired int) {
 	for i := 0; i < len(lines); i++ {
-		if lines[i].Type != diffview.LineDeleted {
+		switch lines[i].Type {
+		case diffview.LineAdded:
+			// Adds not preceded by deletes have nothing to compare against
+			unpaired++
+			continue
+		case diffview.LineDeleted:
+		default:
 			continue
 		}
 
@@ -303,13 +825,7 @@ func computeLinePairSegments(lines []diffview.Line, wordDiffer diffview.WordDiff
 			deleteEnd++
 		}
 
-		// Check if immediately followed by added lines
-		if deleteEnd >= len(lines) || lines[deleteEnd].Type != diffview.LineAdded {
-			i = deleteEnd - 1 // Skip to end of delete run
-			continue
-		}
-
-		// Count consecutive adds
+		// Count consecutive adds that immediately follow
 		addStart := deleteEnd
 		addEnd := addStart
 		for addEnd < len(lines) && lines[addEnd].Type == diffview.LineAdded {
@@ -319,30 +835,16 @@ func computeLinePairSegments(lines []diffview.Line, wordDiffer diffview.WordDiff
 		// Pair up deletes and adds 1:1
 		deleteCount := deleteEnd - deleteStart
 		addCount := addEnd - addStart
-		pairCount := deleteCount
-		if addCount < pairCount {
-			pairCount = addCount
-		}
-
+		pairCount := min(deleteCount, addCount)
 		for j := 0; j < pairCount; j++ {
-			delIdx := deleteStart + j
-			addIdx := addStart + j
-
-			oldContent := strings.TrimSuffix(lines[delIdx].Content, "\n")
-			newContent := strings.TrimSuffix(lines[addIdx].Content, "\n")
-			oldSegs, newSegs := wordDiffer.Diff(oldContent, newContent)
-
-			// Only use word-level highlighting if there's meaningful shared content.
-			if hasSignificantUnchangedContent(oldSegs) && hasSignificantUnchangedContent(newSegs) {
-				result[delIdx] = oldSegs
-				result[addIdx] = newSegs
-			}
+			pairs = append(pairs, [2]int{deleteStart + j, addStart + j})
 		}
+		unpaired += deleteCount + addCount - 2*pairCount
 
-		i = addEnd - 1 // Skip to end of add run
+		i = max(addEnd, deleteEnd) - 1 // Skip to end of the run
 	}
 
-	return result
+	return pairs, unpaired
 }
 
 // hasSignificantUnchangedContent checks if segments have enough unchanged content
@@ -503,6 +1005,35 @@ func formatGutter(oldLineNum, newLineNum, width int, style lipgloss.Style) strin
 	return style.Render(gutter)
 }
 
+// secretBadge marks added lines that look like credentials, in the gutter's
+// old line number column, which added lines leave empty.
+const secretBadge = "⚠"
+
+// formatSecretGutter formats the gutter of an added line flagged as a
+// likely secret, with the badge in place of the old line number.
+func formatSecretGutter(newLineNum, width int, style lipgloss.Style) string {
+	gutter := fmt.Sprintf("%*s %s ", width, secretBadge, formatLineNum(newLineNum, width))
+	return style.Render(gutter)
+}
+
+// countSecrets returns how many added lines in diff detector flags.
+func countSecrets(diff *diffview.Diff, detector diffview.SecretDetector) int {
+	if diff == nil || detector == nil {
+		return 0
+	}
+	n := 0
+	for _, file := range diff.Files {
+		for _, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				if line.Type == diffview.LineAdded && detector.DetectSecret(line.Content) != "" {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
 // formatLineNum formats a line number for the gutter.
 // Returns right-aligned number or empty space for zero (missing) line numbers.
 func formatLineNum(num, width int) string {
@@ -531,14 +1062,37 @@ func styleFromColorPair(cp diffview.ColorPair, renderer *lipgloss.Renderer) lipg
 }
 
 // formatHunkHeader formats a hunk header in standard diff format.
+// Combined diff hunks use the "@@@ -A,B -C,D +E,F @@@" form.
 func formatHunkHeader(hunk diffview.Hunk) string {
 	header := fmt.Sprintf("@@ -%d,%d +%d,%d @@", hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount)
+	if hunk.IsCombined() {
+		marks := strings.Repeat("@", len(hunk.Parents)+1)
+		var sb strings.Builder
+		sb.WriteString(marks)
+		for _, p := range hunk.Parents {
+			fmt.Fprintf(&sb, " -%d,%d", p.Start, p.Count)
+		}
+		fmt.Fprintf(&sb, " +%d,%d %s", hunk.NewStart, hunk.NewCount, marks)
+		header = sb.String()
+	}
 	if hunk.Section != "" {
 		header += " " + hunk.Section
 	}
 	return header
 }
 
+// symbolsMarker returns the marker drawn beside the gutter of a line when
+// changes are shown without color: » for added lines, « for deleted ones.
+func symbolsMarker(line diffview.Line) string {
+	switch line.Type {
+	case diffview.LineAdded:
+		return "»"
+	case diffview.LineDeleted:
+		return "«"
+	}
+	return " "
+}
+
 // linePrefixFor returns the appropriate prefix for a line type.
 func linePrefixFor(lineType diffview.LineType) string {
 	switch lineType {
@@ -551,6 +1105,145 @@ func linePrefixFor(lineType diffview.LineType) string {
 	}
 }
 
+// movedKey identifies a deleted line by its old line number, or another
+// line by its new one.
+type movedKey struct {
+	file    string
+	deleted bool
+	line    int
+}
+
+func movedKeyFor(path string, line diffview.Line) movedKey {
+	if line.Type == diffview.LineDeleted {
+		return movedKey{file: path, deleted: true, line: line.OldLineNum}
+	}
+	return movedKey{file: path, line: line.NewLineNum}
+}
+
+// movedLine is a line on one side of a move.
+type movedLine struct {
+	move  int  // index into moves
+	first bool // first line of its side
+}
+
+// movedLines indexes the lines on both sides of moves.
+func movedLines(moves []diffview.Move) map[movedKey]movedLine {
+	lines := make(map[movedKey]movedLine)
+	for i, m := range moves {
+		for n := 0; n < m.From.Count; n++ {
+			lines[movedKey{file: m.From.Path, deleted: true, line: m.From.Start + n}] = movedLine{move: i, first: n == 0}
+		}
+		for n := 0; n < m.To.Count; n++ {
+			lines[movedKey{file: m.To.Path, line: m.To.Start + n}] = movedLine{move: i, first: n == 0}
+		}
+	}
+	return lines
+}
+
+// linePrefix returns the diff prefix for a line. Combined diff lines have one
+// marker column per parent (e.g., " +" for a line taken from the first parent).
+func linePrefix(line diffview.Line) string {
+	if len(line.Markers) == 0 {
+		return linePrefixFor(line.Type)
+	}
+	var sb strings.Builder
+	for _, marker := range line.Markers {
+		sb.WriteString(linePrefixFor(marker))
+	}
+	return sb.String()
+}
+
+// lineColors returns the color pair used for syntax-highlighted rendering of a line.
+func lineColors(line diffview.Line, styles diffview.Styles) diffview.ColorPair {
+	switch line.Origin() {
+	case diffview.OriginOurs:
+		return styles.Ours
+	case diffview.OriginTheirs:
+		return styles.Theirs
+	}
+	switch line.Type {
+	case diffview.LineAdded:
+		return styles.Added
+	case diffview.LineDeleted:
+		return styles.Deleted
+	default:
+		return styles.Context
+	}
+}
+
+// escapeLines returns lines with control characters and invalid UTF-8 in
+// their content replaced by printable stand-ins. Lines that need no escaping
+// are shared with the input.
+func escapeLines(lines []diffview.Line) []diffview.Line {
+	var escaped []diffview.Line
+	for i, line := range lines {
+		content := escapeControl(line.Content)
+		if content == line.Content {
+			continue
+		}
+		if escaped == nil {
+			escaped = make([]diffview.Line, len(lines))
+			copy(escaped, lines)
+		}
+		escaped[i].Content = content
+	}
+	if escaped == nil {
+		return lines
+	}
+	return escaped
+}
+
+// escapeControl replaces C0 control characters (other than tab and the
+// trailing newline) with their Unicode control pictures, DEL with ␡, and
+// C1 controls and invalid UTF-8 bytes with U+FFFD. Each replacement is one
+// cell wide, so column alignment is preserved. A carriage return ending the
+// line is dropped so files with CRLF line endings render like LF files.
+func escapeControl(content string) string {
+	body, hasNewline := strings.CutSuffix(content, "\n")
+	if trimmed, ok := strings.CutSuffix(body, "\r"); ok {
+		body = trimmed
+		content = body
+		if hasNewline {
+			content += "\n"
+		}
+	}
+	clean := true
+	for _, r := range body {
+		if needsEscape(r) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return content
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(content))
+	for _, r := range body {
+		switch {
+		case r == utf8.RuneError, r >= 0x80 && r < 0xa0:
+			sb.WriteRune(utf8.RuneError)
+		case r == 0x7f:
+			sb.WriteRune('␡')
+		case r < 0x20 && r != '\t':
+			sb.WriteRune(0x2400 + r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if hasNewline {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// needsEscape reports whether r is a control character or decoding error
+// that escapeControl replaces.
+func needsEscape(r rune) bool {
+	return (r < 0x20 && r != '\t') || (r >= 0x7f && r < 0xa0) || r == utf8.RuneError
+}
+
 // padLine pads a line with spaces to the specified display width.
 // Uses DisplayWidth() to correctly handle tabs and multi-byte Unicode characters.
 // If the line is already wider, it is returned unchanged.
@@ -562,44 +1255,22 @@ func padLine(line string, width int) string {
 	return line + strings.Repeat(" ", width-lineWidth)
 }
 
-// shouldRenderFile returns true if the file should be rendered in the diff view.
-// Binary files are skipped, but empty text files (new or deleted) are shown.
-func shouldRenderFile(file diffview.FileDiff) bool {
-	// Always skip binary files
-	if file.IsBinary {
+// hasCombinedHunks reports whether any hunk in the diff comes from a combined
+// (merge) diff.
+func hasCombinedHunks(diff *diffview.Diff) bool {
+	if diff == nil {
 		return false
 	}
-	// Render files with hunks
-	if len(file.Hunks) > 0 {
-		return true
-	}
-	// Render empty new/deleted files
-	if file.Operation == diffview.FileAdded || file.Operation == diffview.FileDeleted {
-		return true
-	}
-	// Render renames/copies (even without content changes)
-	if file.Operation == diffview.FileRenamed || file.Operation == diffview.FileCopied {
-		return true
+	for _, file := range diff.Files {
+		for _, hunk := range file.Hunks {
+			if hunk.IsCombined() {
+				return true
+			}
+		}
 	}
-	// Skip mode-only changes without hunks (or add logic to show them later)
 	return false
 }
 
-// filePath returns the display path for a file in the diff.
-// Uses NewPath for most operations, OldPath for deleted files.
-func filePath(file diffview.FileDiff) string {
-	var path string
-	if file.Operation == diffview.FileDeleted {
-		path = file.OldPath
-	} else {
-		path = file.NewPath
-	}
-	// Strip "a/" or "b/" prefix if present
-	path = strings.TrimPrefix(path, "a/")
-	path = strings.TrimPrefix(path, "b/")
-	return path
-}
-
 // digitWidth returns the number of digits needed to display n.
 func digitWidth(n int) int {
 	if n <= 0 {
@@ -613,43 +1284,19 @@ func digitWidth(n int) int {
 	return width
 }
 
-// computePositions calculates the line numbers where each hunk and file starts.
-// This is independent of terminal width and can be computed eagerly.
-func computePositions(diff *diffview.Diff) (hunkPositions, filePositions []int) {
+// renderedFilePaths returns the display paths of the files renderDiff shows,
+// in order, so that index i corresponds to filePositions[i].
+func renderedFilePaths(diff *diffview.Diff) []string {
 	if diff == nil {
-		return nil, nil
+		return nil
 	}
-
-	lineNum := 0
+	var paths []string
 	for _, file := range diff.Files {
-		// Skip files that shouldn't be rendered (binary files, mode-only changes)
-		if !shouldRenderFile(file) {
-			continue
-		}
-
-		// Track file position at the header line
-		filePositions = append(filePositions, lineNum)
-
-		// Enhanced file header (single line: ── file ─── +N -M ──)
-		lineNum++
-
-		if len(file.Hunks) == 0 {
-			// Empty file: one line for "(empty)" indicator
-			lineNum++
-		} else {
-			for _, hunk := range file.Hunks {
-				// Track hunk position at the header line
-				hunkPositions = append(hunkPositions, lineNum)
-
-				// Hunk header
-				lineNum++
-
-				// Content lines
-				lineNum += len(hunk.Lines)
-			}
+		if file.Visible() {
+			paths = append(paths, file.Path())
 		}
 	}
-	return hunkPositions, filePositions
+	return paths
 }
 
 // maxHunkSizeForTokenization is the maximum total size (in bytes) of hunk content
@@ -662,9 +1309,12 @@ const maxHunkSizeForTokenization = 16 * 1024 // 16KB
 // hunk-level tokenization. Lines longer than this are likely data, not code.
 const maxLineLength = 1000
 
-// tokenizeHunkLines tokenizes all lines in a hunk together with full context,
-// returning per-line tokens. This correctly handles multi-line constructs like
-// /* */ comments and JSDoc that span multiple lines.
+// tokenizeHunkLines tokenizes a hunk's lines with full context, returning
+// per-line tokens. The old side (context and deleted lines) and the new side
+// (context and added lines) are each tokenized as the contiguous text they
+// are in their file, so multi-line constructs like /* */ comments, JSDoc and
+// raw strings are colored as the file has them, even where a change opens or
+// closes one.
 // Returns nil if tokenizer is nil, language is empty, language is unsupported,
 // or hunk content exceeds size limits (likely not normal code).
 func tokenizeHunkLines(lines []diffview.Line, language string, tokenizer diffview.Tokenizer) [][]diffview.Token {
@@ -685,16 +1335,51 @@ func tokenizeHunkLines(lines []diffview.Line, language string, tokenizer diffvie
 		}
 	}
 
-	// Build the full hunk content by joining all line contents
-	var sb strings.Builder
-	sb.Grow(totalSize) // Pre-allocate to avoid reallocations
+	oldTokens := tokenizeSide(lines, diffview.LineAdded, language, tokenizer)
+	newTokens := tokenizeSide(lines, diffview.LineDeleted, language, tokenizer)
+	if oldTokens == nil && newTokens == nil {
+		return nil
+	}
+
+	// Each line takes its tokens from its side; context lines, on both,
+	// from the new one
+	tokens := make([][]diffview.Token, len(lines))
+	oldIdx, newIdx := 0, 0
 	for i, line := range lines {
-		content := strings.TrimSuffix(line.Content, "\n")
-		sb.WriteString(content)
-		if i < len(lines)-1 {
-			sb.WriteString("\n")
+		if line.Type != diffview.LineAdded {
+			if line.Type == diffview.LineDeleted && oldIdx < len(oldTokens) {
+				tokens[i] = oldTokens[oldIdx]
+			}
+			oldIdx++
+		}
+		if line.Type != diffview.LineDeleted {
+			if newIdx < len(newTokens) {
+				tokens[i] = newTokens[newIdx]
+			}
+			newIdx++
 		}
 	}
+	return tokens
+}
 
+// tokenizeSide tokenizes the lines of one side of a hunk, those not of type
+// skip, joined as they are in the file, returning tokens per line of that
+// side.
+func tokenizeSide(lines []diffview.Line, skip diffview.LineType, language string, tokenizer diffview.Tokenizer) [][]diffview.Token {
+	var sb strings.Builder
+	first := true
+	for _, line := range lines {
+		if line.Type == skip {
+			continue
+		}
+		if !first {
+			sb.WriteString("\n")
+		}
+		first = false
+		sb.WriteString(strings.TrimSuffix(line.Content, "\n"))
+	}
+	if first {
+		return nil
+	}
 	return tokenizer.TokenizeLines(language, sb.String())
 }
@@ -0,0 +1,130 @@
+// Render benchmarks measure diffs shaped like real code review: many Go
+// files, each with hunks of context around paired changes, highlighted and
+// word-diffed as the viewer does. The budget tests in render_budget_test.go
+// fail when the render or parse path slows down well past what these
+// benchmarks measure.
+package bubbletea_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/chroma"
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/fwojciec/diffstory/lipgloss"
+	"github.com/fwojciec/diffstory/worddiff"
+	"github.com/stretchr/testify/require"
+)
+
+// representativeDiff returns the text of a unified diff of about lines
+// lines across Go files of 500 lines each. Every hunk has six lines of
+// context either side of four lines changed.
+func representativeDiff(lines int) string {
+	const linesPerFile, linesPerHunk = 500, 20
+	var sb strings.Builder
+	for file := 0; file*linesPerFile < lines; file++ {
+		path := fmt.Sprintf("pkg/service%d/handler.go", file)
+		fmt.Fprintf(&sb, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+		for hunk := 0; hunk < linesPerFile/linesPerHunk; hunk++ {
+			start := hunk*linesPerHunk*2 + 1
+			fmt.Fprintf(&sb, "@@ -%d,16 +%d,16 @@ func handle%d(ctx context.Context) error {\n", start, start, hunk)
+			for i := 0; i < 6; i++ {
+				fmt.Fprintf(&sb, " \tif err := step%d(ctx, \"input\"); err != nil { // keep going\n", i)
+			}
+			for i := 0; i < 4; i++ {
+				fmt.Fprintf(&sb, "-\tresult%d := compute(ctx, %d, \"old value\")\n", i, i)
+			}
+			for i := 0; i < 4; i++ {
+				fmt.Fprintf(&sb, "+\tresult%d := computeWithRetry(ctx, %d, \"new value\", 3)\n", i, i)
+			}
+			for i := 0; i < 6; i++ {
+				fmt.Fprintf(&sb, " \t\treturn fmt.Errorf(\"step %d: %%w\", err)\n", i)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// parseRepresentative parses representativeDiff(lines).
+func parseRepresentative(tb testing.TB, lines int) *diffview.Diff {
+	tb.Helper()
+	diff, err := gitdiff.NewParser().Parse(strings.NewReader(representativeDiff(lines)))
+	require.NoError(tb, err)
+	return diff
+}
+
+// highlightedModel returns a viewer model for diff that highlights and
+// word-diffs as the diffview command does.
+func highlightedModel(tb testing.TB, diff *diffview.Diff) bubbletea.Model {
+	tb.Helper()
+	theme := lipgloss.DefaultTheme()
+	tokenizer, err := chroma.NewTokenizer(chroma.StyleFromPalette(theme.Palette()))
+	require.NoError(tb, err)
+	return bubbletea.NewModel(diff,
+		bubbletea.WithTheme(theme),
+		bubbletea.WithLanguageDetector(chroma.NewDetector()),
+		bubbletea.WithTokenizer(tokenizer),
+		bubbletea.WithWordDiffer(worddiff.NewDiffer()),
+	)
+}
+
+// render sizes m, which renders the whole diff, and returns its view.
+func render(m tea.Model, width int) (tea.Model, string) {
+	m, _ = m.Update(tea.WindowSizeMsg{Width: width, Height: 40})
+	return m, m.View()
+}
+
+func BenchmarkRender(b *testing.B) {
+	for _, lines := range []int{10_000, 100_000} {
+		b.Run(fmt.Sprintf("%dk lines", lines/1000), func(b *testing.B) {
+			diff := parseRepresentative(b, lines)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var result string
+			for i := 0; i < b.N; i++ {
+				_, result = render(highlightedModel(b, diff), 120)
+			}
+			benchResult = result
+		})
+	}
+}
+
+func BenchmarkRender_Resize(b *testing.B) {
+	diff := parseRepresentative(b, 10_000)
+	m, _ := render(highlightedModel(b, diff), 120)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	// Resizes between two widths reuse tokens and word diffs
+	var result string
+	for i := 0; i < b.N; i++ {
+		m, result = render(m, 100+20*(i%2))
+	}
+	benchResult = result
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, lines := range []int{10_000, 100_000} {
+		b.Run(fmt.Sprintf("%dk lines", lines/1000), func(b *testing.B) {
+			text := representativeDiff(lines)
+			b.ReportAllocs()
+			b.SetBytes(int64(len(text)))
+			b.ResetTimer()
+
+			var result *diffview.Diff
+			for i := 0; i < b.N; i++ {
+				diff, err := gitdiff.NewParser().Parse(strings.NewReader(text))
+				if err != nil {
+					b.Fatal(err)
+				}
+				result = diff
+			}
+			benchResult = result
+		})
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !race
+
+// The race detector slows rendering and parsing several times over, so the
+// budgets only hold without it.
+
+package bubbletea_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/stretchr/testify/require"
+)
+
+// Budgets for the representative diffs, five to ten times what they take
+// on a CI machine (2s to render 10k lines, most of it chroma lexing, and
+// 0.1s to parse 100k) so that only real regressions, not slow or busy
+// machines, fail them.
+const (
+	renderBudget10k = 10 * time.Second
+	parseBudget100k = time.Second
+)
+
+func TestRender_WithinBudget(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+	diff := parseRepresentative(t, 10_000)
+	m := highlightedModel(t, diff)
+
+	start := time.Now()
+	_, view := render(m, 120)
+	elapsed := time.Since(start)
+
+	require.NotEmpty(t, view)
+	require.Less(t, elapsed, renderBudget10k, "rendering 10k lines took %v", elapsed)
+}
+
+func TestParse_WithinBudget(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+	text := representativeDiff(100_000)
+
+	start := time.Now()
+	diff, err := gitdiff.NewParser().Parse(strings.NewReader(text))
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 200)
+	require.Less(t, elapsed, parseBudget100k, "parsing 100k lines took %v", elapsed)
+}
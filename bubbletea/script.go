@@ -0,0 +1,145 @@
+package bubbletea
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Default terminal size a script starts in, until it resizes.
+const (
+	defaultScriptWidth  = 80
+	defaultScriptHeight = 24
+)
+
+// Script is a command file of keys to drive a model through without a
+// terminal and screens to record, for docs screenshots and end-to-end tests
+// whose output must be the same every run:
+//
+//	# The second file, scrolled a little
+//	resize 100 30
+//	press ] j j
+//	snapshot second file
+//
+// Each line is a command and its arguments. Blank lines and lines starting
+// with # are skipped.
+//
+//	press KEY...     send keys, named as Driver.Press takes them
+//	type TEXT        type the rest of the line
+//	resize W H       resize the terminal, 80×24 to start with
+//	snapshot [NAME]  record the screen, under NAME
+type Script struct {
+	steps []scriptStep
+}
+
+// scriptStep is a command of a script.
+type scriptStep struct {
+	line int    // in the command file, for errors
+	cmd  string // press, type, resize or snapshot
+	args []string
+	text string // the rest of the line, for type and snapshot
+}
+
+// ParseScript reads a script from r, checking its commands and keys.
+func ParseScript(r io.Reader) (*Script, error) {
+	var s Script
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cmd, text, _ := strings.Cut(line, " ")
+		step := scriptStep{line: n, cmd: cmd, args: strings.Fields(text), text: strings.TrimSpace(text)}
+		switch cmd {
+		case "press":
+			if len(step.args) == 0 {
+				return nil, fmt.Errorf("line %d: press needs at least one key", n)
+			}
+			for _, k := range step.args {
+				if _, err := parseKey(k); err != nil {
+					return nil, fmt.Errorf("line %d: %w", n, err)
+				}
+			}
+		case "type":
+			if step.text == "" {
+				return nil, fmt.Errorf("line %d: type needs text", n)
+			}
+		case "resize":
+			if _, _, err := step.size(); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+		case "snapshot":
+		default:
+			return nil, fmt.Errorf("line %d: unknown command %q (want press, type, resize or snapshot)", n, cmd)
+		}
+		s.steps = append(s.steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	return &s, nil
+}
+
+// size returns the width and height of a resize step.
+func (s scriptStep) size() (width, height int, err error) {
+	if len(s.args) != 2 {
+		return 0, 0, fmt.Errorf("resize needs a width and height")
+	}
+	width, werr := strconv.Atoi(s.args[0])
+	height, herr := strconv.Atoi(s.args[1])
+	if werr != nil || herr != nil || width < 1 || height < 1 {
+		return 0, 0, fmt.Errorf("invalid size %s×%s: want positive numbers", s.args[0], s.args[1])
+	}
+	return width, height, nil
+}
+
+// Run drives d through the script, writing each screen it snapshots to w
+// as plain text under a "=== NAME ===" line. A script without snapshots
+// records the screen it ends on. Keys after the model quits are ignored.
+func (s *Script) Run(d *Driver, w io.Writer) error {
+	snapshots := 0
+	for _, step := range s.steps {
+		switch step.cmd {
+		case "press":
+			if err := d.Press(step.args...); err != nil {
+				return fmt.Errorf("line %d: %w", step.line, err)
+			}
+		case "type":
+			d.Type(step.text)
+		case "resize":
+			width, height, _ := step.size()
+			d.Send(tea.WindowSizeMsg{Width: width, Height: height})
+		case "snapshot":
+			snapshots++
+			name := step.text
+			if name == "" {
+				name = "snapshot " + strconv.Itoa(snapshots)
+			}
+			if err := writeSnapshot(w, name, d.Frame()); err != nil {
+				return err
+			}
+		}
+	}
+	if snapshots == 0 {
+		return writeSnapshot(w, "end", d.Frame())
+	}
+	return nil
+}
+
+// writeSnapshot writes frame to w under name, without the spaces that pad
+// its lines, which would only make recordings harder to compare.
+func writeSnapshot(w io.Writer, name, frame string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "=== %s ===\n", name)
+	for line := range strings.Lines(frame) {
+		sb.WriteString(strings.TrimRight(line, " \n"))
+		sb.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
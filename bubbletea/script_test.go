@@ -0,0 +1,113 @@
+package bubbletea_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory/bubbletea"
+	dv "github.com/fwojciec/diffstory/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScript(t *testing.T) {
+	t.Parallel()
+
+	t.Run("skips blank lines and comments", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := bubbletea.ParseScript(strings.NewReader("# demo\n\npress ] j ctrl+d\ntype hello world\nresize 100 30\nsnapshot\n"))
+
+		require.NoError(t, err)
+	})
+
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{"unknown command", "press j\nscroll 3\n", `line 2: unknown command "scroll"`},
+		{"unknown key", "press j ctrl+nope\n", `line 1: unknown key "ctrl+nope"`},
+		{"press without keys", "press\n", "line 1: press needs at least one key"},
+		{"type without text", "type\n", "line 1: type needs text"},
+		{"resize without height", "resize 80\n", "line 1: resize needs a width and height"},
+		{"resize to nothing", "resize 0 24\n", "line 1: invalid size 0×24"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := bubbletea.ParseScript(strings.NewReader(tt.script))
+
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestScript_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records each snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		script, err := bubbletea.ParseScript(strings.NewReader("snapshot first file\npress ]\nresize 60 8\nsnapshot\n"))
+		require.NoError(t, err)
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go")), 80, 10)
+
+		var out bytes.Buffer
+		require.NoError(t, script.Run(d, &out))
+
+		first, second, ok := strings.Cut(out.String(), "=== snapshot 2 ===\n")
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(first, "=== first file ===\n"))
+		assert.Contains(t, first, "line 1 of a.go")
+		assert.Contains(t, second, "line 1 of b.go")
+		assert.Equal(t, 8, strings.Count(second, "\n"))
+		assert.NotContains(t, out.String(), " \n")
+	})
+
+	t.Run("records the last screen without snapshots", func(t *testing.T) {
+		t.Parallel()
+
+		script, err := bubbletea.ParseScript(strings.NewReader("press ]\n"))
+		require.NoError(t, err)
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go")), 80, 10)
+
+		var out bytes.Buffer
+		require.NoError(t, script.Run(d, &out))
+
+		assert.True(t, strings.HasPrefix(out.String(), "=== end ===\n"))
+		assert.Contains(t, out.String(), "line 1 of b.go")
+	})
+
+	t.Run("is the same every run", func(t *testing.T) {
+		t.Parallel()
+
+		record := func() string {
+			script, err := bubbletea.ParseScript(strings.NewReader("press j j ctrl+d\nsnapshot\npress G\nsnapshot\n"))
+			require.NoError(t, err)
+			var out bytes.Buffer
+			d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go", "b.go", "c.go")), 80, 12)
+			require.NoError(t, script.Run(d, &out))
+			return out.String()
+		}
+
+		assert.Equal(t, record(), record())
+	})
+}
+
+func TestViewer_ScriptRecordsWithoutTerminal(t *testing.T) {
+	t.Parallel()
+
+	script, err := bubbletea.ParseScript(strings.NewReader("resize 80 10\npress ] ]\nsnapshot\n"))
+	require.NoError(t, err)
+	var out bytes.Buffer
+	viewer := bubbletea.NewViewer(dv.TestTheme(), bubbletea.WithViewerScript(script, &out))
+
+	err = viewer.View(context.Background(), multiFileDiff("a.go", "b.go", "c.go"))
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "line 1 of c.go")
+}
@@ -0,0 +1,127 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxScrollStep bounds configurable scroll steps; larger jumps are what the
+// page keys are for.
+const maxScrollStep = 10
+
+// Smooth page jumps move the view over a few frames instead of all at once.
+const (
+	scrollFrames        = 4
+	scrollFrameInterval = 15 * time.Millisecond
+)
+
+// Scrolling configures how far the scrolling keys move the view.
+type Scrolling struct {
+	Step     int  // rows j/k move; 0 means 1
+	FullPage bool // ctrl+d/ctrl+u move a full page instead of half
+	Smooth   bool // animate page jumps over a few frames
+}
+
+// ParseScrolling parses scroll settings such as flag or environment values:
+// a step of 1 to 10 rows, a page size of "half" or "full", and a boolean
+// enabling smooth page jumps. Empty strings select the defaults of one row,
+// half pages and no animation.
+func ParseScrolling(step, page, smooth string) (Scrolling, error) {
+	var s Scrolling
+	if step != "" {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 || n > maxScrollStep {
+			return Scrolling{}, fmt.Errorf("invalid scroll step %q: must be between 1 and %d", step, maxScrollStep)
+		}
+		s.Step = n
+	}
+	switch page {
+	case "", "half":
+	case "full":
+		s.FullPage = true
+	default:
+		return Scrolling{}, fmt.Errorf("invalid page scroll %q: use half or full", page)
+	}
+	if smooth != "" {
+		on, err := strconv.ParseBool(smooth)
+		if err != nil {
+			return Scrolling{}, fmt.Errorf("invalid smooth scroll %q: use true or false", smooth)
+		}
+		s.Smooth = on
+	}
+	return s, nil
+}
+
+// scrollFrameMsg advances a smooth page jump by one frame.
+type scrollFrameMsg struct{}
+
+// scroller moves a viewport according to the scroll settings, animating
+// page jumps when smooth scrolling is on.
+type scroller struct {
+	Scrolling
+	target    int // offset a page jump in progress is heading for
+	remaining int // frames left in the jump; 0 when none is in progress
+}
+
+// lines scrolls n steps, down for positive n and up for negative.
+func (s *scroller) lines(vp *viewport.Model, n int) {
+	s.settle(vp)
+	rows := n * max(s.Step, 1)
+	if rows > 0 {
+		vp.ScrollDown(rows)
+	} else {
+		vp.ScrollUp(-rows)
+	}
+}
+
+// page jumps one page, down for positive dir and up for negative. The
+// returned command drives the animation when smooth scrolling is on.
+func (s *scroller) page(vp *viewport.Model, dir int) tea.Cmd {
+	s.settle(vp)
+	rows := max(vp.Height/2, 1)
+	if s.FullPage {
+		rows = max(vp.Height, 1)
+	}
+	target := max(0, min(vp.YOffset+dir*rows, vp.TotalLineCount()-vp.Height))
+	if !s.Smooth || target == vp.YOffset {
+		vp.SetYOffset(target)
+		return nil
+	}
+	s.target = target
+	s.remaining = scrollFrames
+	return s.frame(vp)
+}
+
+// frame moves the view one frame closer to the page jump target, easing
+// out so most of the distance is covered first.
+func (s *scroller) frame(vp *viewport.Model) tea.Cmd {
+	if s.remaining == 0 {
+		return nil
+	}
+	distance := s.target - vp.YOffset
+	step := distance / 2
+	if s.remaining == 1 || step == 0 {
+		step = distance
+	}
+	vp.SetYOffset(vp.YOffset + step)
+	s.remaining--
+	if vp.YOffset == s.target {
+		s.remaining = 0
+		return nil
+	}
+	return tea.Tick(scrollFrameInterval, func(time.Time) tea.Msg { return scrollFrameMsg{} })
+}
+
+// settle completes any page jump in progress, so other input starts from
+// where the jump was heading.
+func (s *scroller) settle(vp *viewport.Model) {
+	if s.remaining == 0 {
+		return
+	}
+	vp.SetYOffset(s.target)
+	s.remaining = 0
+}
@@ -0,0 +1,120 @@
+package bubbletea_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScrolling(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty selects defaults", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := bubbletea.ParseScrolling("", "", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, bubbletea.Scrolling{}, s)
+	})
+
+	t.Run("accepts settings", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := bubbletea.ParseScrolling("3", "full", "true")
+
+		require.NoError(t, err)
+		assert.Equal(t, bubbletea.Scrolling{Step: 3, FullPage: true, Smooth: true}, s)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		t.Parallel()
+
+		for _, in := range [][3]string{
+			{"0", "", ""},
+			{"11", "", ""},
+			{"fast", "", ""},
+			{"", "quarter", ""},
+			{"", "", "sometimes"},
+		} {
+			_, err := bubbletea.ParseScrolling(in[0], in[1], in[2])
+			assert.Error(t, err, "input %q", in)
+		}
+	})
+}
+
+// topLine returns the first line of the view.
+func topLine(m tea.Model) string {
+	return strings.SplitN(m.View(), "\n", 2)[0]
+}
+
+func TestModel_ScrollStepMovesSeveralRows(t *testing.T) {
+	t.Parallel()
+
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go"),
+		bubbletea.WithScrolling(bubbletea.Scrolling{Step: 3}))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 6})
+
+	// Rows: file header, hunk header, line 1, line 2, ...
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	assert.Contains(t, topLine(model), "line 2 of a.go")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	assert.Contains(t, topLine(model), "a.go")
+	assert.NotContains(t, topLine(model), "line")
+}
+
+func TestModel_FullPageScroll(t *testing.T) {
+	t.Parallel()
+
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("a.go"),
+		bubbletea.WithScrolling(bubbletea.Scrolling{FullPage: true}))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 6})
+
+	// The viewport is five rows tall, so a page starts on line 4
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	assert.Contains(t, topLine(model), "line 4 of a.go")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	assert.NotContains(t, topLine(model), "line")
+}
+
+func TestStoryModel_SmoothPageScrollAnimates(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("a.go", "b.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Title: "Both", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}, {File: "b.go", HunkIndex: 0}}},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story,
+		bubbletea.WithStoryScrolling(bubbletea.Scrolling{FullPage: true, Smooth: true}))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 11})
+
+	// Rows: banner, file header, hunk header, line 1, ... so a ten-row page
+	// ends on line 8, partway there after the first frame
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	require.NotNil(t, cmd)
+	assert.Contains(t, topLine(model), "line 3 of a.go")
+
+	frames := 0
+	for cmd != nil {
+		model, cmd = model.Update(cmd())
+		frames++
+	}
+	assert.Contains(t, topLine(model), "line 8 of a.go")
+	assert.LessOrEqual(t, frames, 3)
+
+	// A key during the animation finishes the jump first
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	assert.Contains(t, topLine(model), "a.go")
+	assert.NotContains(t, topLine(model), "line")
+}
@@ -0,0 +1,69 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+)
+
+// skippedBannerHeight returns the rows of the banner above the diff, which
+// shows when a lenient parse left content out.
+func (m Model) skippedBannerHeight() int {
+	if m.diff == nil || len(m.diff.Skipped) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// skippedBannerView renders the banner warning that content was left out
+// of the diff and naming the files it belonged to, or returns "" if none
+// was. The debug panel lists each skipped section.
+func (m Model) skippedBannerView() string {
+	if m.skippedBannerHeight() == 0 {
+		return ""
+	}
+	style := m.newStyle().
+		Background(lipgloss.Color(m.palette.UIBackground)).
+		Foreground(lipgloss.Color(m.palette.Modified))
+	text := formatSkippedBanner(m.diff.Skipped)
+	if m.width > 0 {
+		text = ansi.Truncate(text, m.width, "…")
+		if pad := m.width - lipgloss.Width(text); pad > 0 {
+			text += strings.Repeat(" ", pad)
+		}
+	}
+	return style.Render(text)
+}
+
+// formatSkippedBanner summarizes skipped sections in a line, such as
+// "⚠ skipped 2 malformed section(s), 9 line(s), of main.go, util.go".
+func formatSkippedBanner(skipped []diffview.SkippedSection) string {
+	var names []string
+	seen := make(map[string]bool)
+	lines := 0
+	for _, s := range skipped {
+		lines += s.Lines
+		name := s.Path
+		if name == "" {
+			name = "a garbled file"
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return fmt.Sprintf("⚠ skipped %d malformed section(s), %d line(s), of %s · D: details",
+		len(skipped), lines, strings.Join(names, ", "))
+}
+
+// frame stacks the skipped content banner, if any, body and the status
+// bar into the whole view.
+func (m Model) frame(body string) string {
+	if banner := m.skippedBannerView(); banner != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, banner, body, m.statusBarView())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, body, m.statusBarView())
+}
@@ -0,0 +1,62 @@
+package bubbletea_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_ShowsBannerForSkippedContent(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("main.go")
+	diff.Skipped = []diffview.SkippedSection{
+		{Path: "main.go", Header: "@@ -10,2 +10,x @@", Lines: 4, Reason: "invalid fragment header"},
+		{Header: "diff --git a/file.go", Lines: 3, Reason: "no file header"},
+	}
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+
+	view := model.View()
+	rows := strings.Split(view, "\n")
+	assert.Len(t, rows, 8)
+	assert.Contains(t, rows[0], "skipped 2 malformed section(s), 7 line(s), of main.go, a garbled file")
+	assert.Contains(t, rows[1], "main.go")
+
+	// The debug panel lists each section
+	model = sendKeys(t, model, typeText("D"))
+	view = model.View()
+	assert.Contains(t, view, `main.go: skipped 4 line(s) from "@@ -10,2 +10,x @@": invalid fragment header`)
+	assert.Contains(t, view, `skipped 3 line(s) from "diff --git a/file.go": no file header`)
+}
+
+func TestModel_NoBannerWithoutSkippedContent(t *testing.T) {
+	t.Parallel()
+
+	var model tea.Model = bubbletea.NewModel(multiFileDiff("main.go"))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+
+	view := model.View()
+	assert.Len(t, strings.Split(view, "\n"), 8)
+	assert.NotContains(t, view, "malformed")
+}
+
+func TestModel_ReloadHidesBannerOnceContentParses(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("main.go")
+	diff.Skipped = []diffview.SkippedSection{{Path: "main.go", Header: "@@ -1 +1,x @@", Lines: 3, Reason: "invalid fragment header"}}
+
+	var model tea.Model = bubbletea.NewModel(diff)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+	model, _ = model.Update(bubbletea.ReloadMsg{Diff: multiFileDiff("main.go")})
+
+	view := model.View()
+	assert.Len(t, strings.Split(view, "\n"), 8)
+	assert.NotContains(t, view, "malformed")
+}
@@ -2,7 +2,9 @@ package bubbletea
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -27,16 +29,60 @@ type StoryModel struct {
 	activeSection int  // 0 = intro (if showIntro) or first code section
 	showIntro     bool // whether intro slide is enabled
 
+	// All-sections mode renders every section in order instead of one at a time
+	allSections bool
+	allLayout   diffLayout // layout of the all-sections content
+	sectionRows []int      // banner row of each section in the all-sections content
+
 	// Syntax highlighting
 	languageDetector diffview.LanguageDetector
 	tokenizer        diffview.Tokenizer
 	wordDiffer       diffview.WordDiffer
+	tabWidth         int
+	cache            *renderCache // tokens and hunks from earlier renders
+
+	// Long lines
+	wrap    bool // soft-wrap long lines instead of scrolling horizontally
+	xOffset int  // content columns scrolled off to the left
 
 	// Case saving
 	input         *diffview.ClassificationInput // optional: full input for constructing EvalCase
 	caseSaver     diffview.EvalCaseSaver
 	caseSaverPath string
 
+	// Opening lines in an editor, a pane beside the viewer or on the web
+	editor       EditorFunc
+	editorRemote bool // editor runs apart from the terminal, such as Neovim on a socket
+	quickfix     QuickfixFunc
+	pane         PaneFunc
+	permalink    PermalinkFunc
+	clipboard    diffview.Clipboard
+
+	// Applying or reverting a section's hunks in the working tree
+	patch        PatchFunc
+	pendingPatch *patchRequest // awaiting confirmation
+	notice       string        // outcome of the last patch, until the next key
+	toast        toast         // outcome of the last save or copy, until it times out
+
+	// Narrating each section, optionally aloud
+	narrating bool // narration panel shown below the code
+	speak     SpeakFunc
+
+	// Asking questions about the section on screen
+	askFn AskFunc
+	chat  chatPanel
+
+	// Presenting on a call
+	presenting  bool          // large section headers and a quiet status bar
+	autoAdvance time.Duration // time on each section while presenting; 0 to advance by hand
+	paused      bool          // auto-advance stopped for now
+	advanceID   int           // counts auto-advance intervals started, so a stale one is ignored
+	laser       laser         // lines bracketed to point at
+
+	// Resuming where the reader left off
+	stateStore    diffview.ViewStateStore
+	restoreOffset int // scroll offset to apply once the viewport exists
+
 	// UI state
 	viewport   viewport.Model
 	keymap     StoryKeyMap
@@ -46,6 +92,12 @@ type StoryModel struct {
 	width      int
 	ready      bool
 	pendingKey string
+	scroll     scroller
+	idle       idleLock
+	debug      debugPanel
+
+	// Clicking and dragging
+	selection selection // rows dragged over with the mouse
 }
 
 // StoryModelOption configures a StoryModel.
@@ -57,10 +109,25 @@ type storyModelConfig struct {
 	languageDetector diffview.LanguageDetector
 	tokenizer        diffview.Tokenizer
 	wordDiffer       diffview.WordDiffer
+	tabWidth         int
+	idleTimeout      time.Duration
 	showIntro        bool
 	input            *diffview.ClassificationInput
 	caseSaver        diffview.EvalCaseSaver
 	caseSaverPath    string
+	editor           EditorFunc
+	editorRemote     bool
+	quickfix         QuickfixFunc
+	pane             PaneFunc
+	permalink        PermalinkFunc
+	clipboard        diffview.Clipboard
+	stateStore       diffview.ViewStateStore
+	scrolling        Scrolling
+	patch            PatchFunc
+	speak            SpeakFunc
+	ask              AskFunc
+	presenting       bool
+	autoAdvance      time.Duration
 }
 
 // WithStoryRenderer sets a custom lipgloss renderer for the model.
@@ -98,6 +165,123 @@ func WithStoryWordDiffer(d diffview.WordDiffer) StoryModelOption {
 	}
 }
 
+// WithStoryTabWidth sets the tab stop interval for line content.
+func WithStoryTabWidth(n int) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.tabWidth = n
+	}
+}
+
+// WithStoryIdleTimeout blanks the diff after the given period without input,
+// until the next key press. Zero (the default) disables locking.
+func WithStoryIdleTimeout(d time.Duration) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.idleTimeout = d
+	}
+}
+
+// WithStoryEditor sets the command used to open the line at the top of the
+// view in an editor.
+func WithStoryEditor(e EditorFunc) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.editor = e
+	}
+}
+
+// WithStoryRemoteEditor sets the command used to send the line at the top
+// of the view to an editor already running apart from the viewer, such as
+// Neovim listening on a socket.
+func WithStoryRemoteEditor(e EditorFunc) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.editor = e
+		cfg.editorRemote = true
+	}
+}
+
+// WithStoryQuickfix sets the command that sends the changed places of the
+// section on screen to a running editor's quickfix list. Without one the
+// key does nothing.
+func WithStoryQuickfix(q QuickfixFunc) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.quickfix = q
+	}
+}
+
+// WithStoryPane sets the command used to open the file, blame or log of the
+// line at the top of the view in a pane beside the viewer. Without one the
+// keys do nothing.
+func WithStoryPane(p PaneFunc) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.pane = p
+	}
+}
+
+// WithStoryPermalinks sets how web links to lines are built and the
+// clipboard they're copied to.
+func WithStoryPermalinks(link PermalinkFunc, c diffview.Clipboard) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.permalink = link
+		cfg.clipboard = c
+	}
+}
+
+// WithStoryScrolling sets how far the scrolling keys move the view.
+func WithStoryScrolling(s Scrolling) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.scrolling = s
+	}
+}
+
+// WithStoryStateStore restores the reader's place in the diff from s and
+// saves it there on quit.
+func WithStoryStateStore(s diffview.ViewStateStore) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.stateStore = s
+	}
+}
+
+// WithStoryPatcher enables applying and reverting the hunks of the section
+// in view in the working tree, through p.
+func WithStoryPatcher(p PatchFunc) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.patch = p
+	}
+}
+
+// WithStorySpeaker sets how the narration panel reads each section's
+// narration aloud as it comes on screen.
+func WithStorySpeaker(s SpeakFunc) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.speak = s
+	}
+}
+
+// WithStoryChat enables the chat panel, answering questions about the
+// section on screen with ask.
+func WithStoryChat(ask AskFunc) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.ask = ask
+	}
+}
+
+// WithStoryPresenter starts the viewer in presenter mode, for walking
+// through the story on a call: each section opens with a large header and
+// the status bar shows only the section. P leaves it.
+func WithStoryPresenter() StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.presenting = true
+	}
+}
+
+// WithStoryAutoAdvance moves on to the next section after d in presenter
+// mode, stopping at the last. Space pauses and resumes it. Zero (the
+// default) leaves advancing to the presenter.
+func WithStoryAutoAdvance(d time.Duration) StoryModelOption {
+	return func(cfg *storyModelConfig) {
+		cfg.autoAdvance = d
+	}
+}
+
 // WithIntroSlide enables the intro slide, starting the viewer at an overview
 // rather than jumping directly into code.
 func WithIntroSlide() StoryModelOption {
@@ -138,62 +322,196 @@ func NewStoryModel(diff *diffview.Diff, story *diffview.StoryClassification, opt
 		palette = defaultPalette()
 	}
 
-	// Build lookup maps from story classification
-	hunkToSection := make(map[hunkKey]int)
-	hunkCategories := make(map[hunkKey]string)
-	collapseText := make(map[hunkKey]string)
-	collapsedHunks := make(map[hunkKey]bool)
-	llmCollapsedHunks := make(map[hunkKey]bool)
-
-	if story != nil {
-		for sectionIdx, section := range story.Sections {
-			for _, ref := range section.Hunks {
-				key := hunkKey{file: ref.File, hunkIndex: ref.HunkIndex}
-				hunkToSection[key] = sectionIdx
-				hunkCategories[key] = ref.Category
-				if ref.CollapseText != "" {
-					collapseText[key] = ref.CollapseText
-				}
-				// Collapse if explicitly marked or noise category
-				if ref.Collapsed || ref.Category == "noise" {
-					collapsedHunks[key] = true
-					llmCollapsedHunks[key] = true // Track original LLM decision
-				}
+	m := StoryModel{
+		cache:            newRenderCache(),
+		showIntro:        cfg.showIntro,
+		languageDetector: cfg.languageDetector,
+		tokenizer:        cfg.tokenizer,
+		wordDiffer:       cfg.wordDiffer,
+		tabWidth:         cfg.tabWidth,
+		input:            cfg.input,
+		caseSaver:        cfg.caseSaver,
+		caseSaverPath:    cfg.caseSaverPath,
+		editor:           cfg.editor,
+		editorRemote:     cfg.editorRemote,
+		quickfix:         cfg.quickfix,
+		pane:             cfg.pane,
+		permalink:        cfg.permalink,
+		clipboard:        cfg.clipboard,
+		patch:            cfg.patch,
+		speak:            cfg.speak,
+		askFn:            cfg.ask,
+		presenting:       cfg.presenting,
+		autoAdvance:      cfg.autoAdvance,
+		keymap:           DefaultStoryKeyMap(),
+		styles:           styles,
+		palette:          palette,
+		renderer:         cfg.renderer,
+		idle:             newIdleLock(cfg.idleTimeout),
+		stateStore:       cfg.stateStore,
+		scroll:           scroller{Scrolling: cfg.scrolling},
+	}
+	m.setStory(diff, story)
+
+	// Best-effort restore - a missing or unreadable state starts fresh
+	if m.stateStore != nil {
+		if state, err := m.stateStore.Load(diff); err == nil && state != nil {
+			m.restoreState(*state)
+		}
+	}
+	return m
+}
+
+// setStory sets the diff and story and builds the lookup maps for the
+// story's hunks, collapsing the ones the classifier marked as noise. A hunk
+// in several sections, as in the section on exported API changes, belongs
+// to the first.
+func (m *StoryModel) setStory(diff *diffview.Diff, story *diffview.StoryClassification) {
+	m.diff = diff
+	m.story = story
+	m.hunkToSection = make(map[hunkKey]int)
+	m.hunkCategories = make(map[hunkKey]string)
+	m.collapseText = make(map[hunkKey]string)
+	m.collapsedHunks = make(map[hunkKey]bool)
+	m.llmCollapsedHunks = make(map[hunkKey]bool)
+
+	if story == nil {
+		return
+	}
+	for sectionIdx, section := range story.Sections {
+		for _, ref := range section.Hunks {
+			key := hunkKey{file: ref.File, hunkIndex: ref.HunkIndex}
+			if _, ok := m.hunkToSection[key]; ok {
+				continue
+			}
+			m.hunkToSection[key] = sectionIdx
+			m.hunkCategories[key] = ref.Category
+			if ref.CollapseText != "" {
+				m.collapseText[key] = ref.CollapseText
 			}
+			// Collapse if explicitly marked or noise category
+			if ref.Collapsed || ref.Category == "noise" {
+				m.collapsedHunks[key] = true
+				m.llmCollapsedHunks[key] = true // Track original LLM decision
+			}
+		}
+	}
+}
+
+// reload swaps in a new diff and story. Hunks in both keep their collapse
+// state, and the view stays on the same section and source line.
+func (m *StoryModel) reload(diff *diffview.Diff, story *diffview.StoryClassification) {
+	var old diffLayout
+	if m.ready && !m.onIntro() {
+		old = m.contentLayout()
+	}
+	prevCollapsed, prevKnown := m.collapsedHunks, m.hunkToSection
+
+	m.setStory(diff, story)
+	for key := range m.hunkToSection {
+		if _, ok := prevKnown[key]; ok {
+			m.collapsedHunks[key] = prevCollapsed[key]
 		}
 	}
+	m.activeSection = min(m.activeSection, max(m.totalSections()-1, 0))
+	m.allSections = m.allSections && story != nil && len(story.Sections) > 0
+	m.xOffset = clampXOffset(m.xOffset, maxXOffset(m.visibleDiff(), m.contentWidth(), m.tabWidth))
+	if !m.ready {
+		return
+	}
 
-	return StoryModel{
-		diff:              diff,
-		story:             story,
-		hunkToSection:     hunkToSection,
-		hunkCategories:    hunkCategories,
-		collapseText:      collapseText,
-		collapsedHunks:    collapsedHunks,
-		llmCollapsedHunks: llmCollapsedHunks,
-		showIntro:         cfg.showIntro,
-		languageDetector:  cfg.languageDetector,
-		tokenizer:         cfg.tokenizer,
-		wordDiffer:        cfg.wordDiffer,
-		input:             cfg.input,
-		caseSaver:         cfg.caseSaver,
-		caseSaverPath:     cfg.caseSaverPath,
-		keymap:            DefaultStoryKeyMap(),
-		styles:            styles,
-		palette:           palette,
-		renderer:          cfg.renderer,
+	offset := m.viewport.YOffset
+	m.setContent()
+	if !m.onIntro() {
+		offset = reanchor(offset, old, m.contentLayout())
 	}
+	m.viewport.SetYOffset(offset)
 }
 
 // Init implements tea.Model.
 func (m StoryModel) Init() tea.Cmd {
-	return nil
+	return m.idle.start()
 }
 
 // Update implements tea.Model.
 func (m StoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case idleCheckMsg:
+		return m, m.idle.check()
+	case scrollFrameMsg:
+		return m, m.scroll.frame(&m.viewport)
+	case ReloadMsg:
+		m.reload(msg.Diff, msg.Story)
+		return m, nil
+	case patchDoneMsg:
+		m.notice = msg.notice()
+		return m, nil
+	case chatAnswerMsg:
+		return m, m.receiveAnswer(msg)
+	case advanceMsg:
+		return m, m.advance(msg)
+	case ToastMsg:
+		return m, m.toast.show(msg)
+	case toastExpiredMsg:
+		m.toast.expire(msg)
+		return m, nil
+	case editorClosedMsg:
+		// Time spent in the editor counts as activity
+		_, cmd := m.idle.touch()
+		return m, cmd
+	case tea.MouseMsg:
+		if dismissed, cmd := m.idle.touch(); dismissed {
+			return m, cmd
+		}
+		if m.handleMouse(msg) {
+			return m, nil
+		}
 	case tea.KeyMsg:
+		// The key that dismisses the lock screen isn't acted on
+		if dismissed, cmd := m.idle.touch(); dismissed {
+			return m, cmd
+		}
+
+		m.scroll.settle(&m.viewport)
+
+		// The debug panel takes all keys while open
+		if m.debug.active {
+			m.debug.update(msg, m.keymap.Debug)
+			return m, nil
+		}
+		// So does the chat panel, where keys type the question
+		if m.chat.active {
+			return m, m.updateChat(msg)
+		}
+		// And the laser's prompt, where keys type the lines to highlight
+		if m.laser.prompting {
+			m.updateLaser(msg)
+			return m, nil
+		}
+
+		// An apply or revert waits for a yes or no
+		if m.pendingPatch != nil {
+			req := *m.pendingPatch
+			switch {
+			case key.Matches(msg, m.keymap.ConfirmPatch):
+				m.pendingPatch = nil
+				diff, _ := m.sectionDiffWithIndices(req.section)
+				return m, runPatch(m.patch, diff, req)
+			case key.Matches(msg, m.keymap.CancelPatch):
+				m.pendingPatch = nil
+			}
+			return m, nil
+		}
+		m.notice = ""
+		m.selection = selection{}
+		if m.laser.active && msg.Type == tea.KeyEsc {
+			m.laser = laser{}
+			return m, nil
+		}
+		if cmd, ok := m.presenterKey(msg); ok {
+			return m, cmd
+		}
+
 		// Handle multi-key sequences (gg for go to top)
 		if m.pendingKey == "g" && key.Matches(msg, m.keymap.GotoTop) {
 			m.viewport.GotoTop()
@@ -212,51 +530,125 @@ func (m StoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch {
 		case key.Matches(msg, m.keymap.Quit):
+			m.saveState()
 			return m, tea.Quit
 		case key.Matches(msg, m.keymap.GotoBottom):
 			m.viewport.GotoBottom()
 			return m, nil
 		case key.Matches(msg, m.keymap.HalfPageUp):
-			m.viewport.HalfPageUp()
-			return m, nil
+			return m, m.scroll.page(&m.viewport, -1)
 		case key.Matches(msg, m.keymap.HalfPageDown):
-			m.viewport.HalfPageDown()
-			return m, nil
+			return m, m.scroll.page(&m.viewport, 1)
 		case key.Matches(msg, m.keymap.Up):
-			m.viewport.ScrollUp(1)
+			m.scroll.lines(&m.viewport, -1)
 			return m, nil
 		case key.Matches(msg, m.keymap.Down):
-			m.viewport.ScrollDown(1)
+			m.scroll.lines(&m.viewport, 1)
 			return m, nil
 		case key.Matches(msg, m.keymap.NextSection):
 			m.gotoNextSection()
-			return m, nil
+			return m, m.sectionChanged()
 		case key.Matches(msg, m.keymap.PrevSection):
 			m.gotoPrevSection()
+			return m, m.sectionChanged()
+		case key.Matches(msg, m.keymap.TogglePresenter):
+			return m, m.togglePresenter()
+		case key.Matches(msg, m.keymap.Highlight):
+			m.startLaser()
+			return m, nil
+		case key.Matches(msg, m.keymap.ToggleNarration):
+			return m, m.toggleNarration()
+		case key.Matches(msg, m.keymap.Chat):
+			if m.askFn != nil {
+				m.openChat()
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.ToggleAllSections):
+			m.toggleAllSections()
 			return m, nil
 		case key.Matches(msg, m.keymap.ToggleCollapseAll):
 			m.toggleAllCollapse()
 			return m, nil
+		case key.Matches(msg, m.keymap.ToggleWrap):
+			m.toggleWrap()
+			return m, nil
+		case key.Matches(msg, m.keymap.ScrollLeft):
+			m.scrollHorizontal(-horizontalScrollStep)
+			return m, nil
+		case key.Matches(msg, m.keymap.ScrollRight):
+			m.scrollHorizontal(horizontalScrollStep)
+			return m, nil
 		case key.Matches(msg, m.keymap.SaveCase):
-			m.saveCurrentCase()
+			return m, m.saveCurrentCase()
+		case key.Matches(msg, m.keymap.ApplySection):
+			m.requestPatch(false)
+			return m, nil
+		case key.Matches(msg, m.keymap.RevertSection):
+			m.requestPatch(true)
+			return m, nil
+		case key.Matches(msg, m.keymap.OpenEditor):
+			if m.onIntro() {
+				return m, nil
+			}
+			return m, openInEditor(m.editor, m.editorRemote, m.contentLayout(), m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.Quickfix):
+			idx := m.visibleSectionIndex()
+			if m.story == nil || idx < 0 || idx >= len(m.story.Sections) {
+				return m, nil
+			}
+			return m, sendQuickfix(m.quickfix, m.diff, m.story.Sections[idx])
+		case key.Matches(msg, m.keymap.PaneFile):
+			if m.onIntro() {
+				return m, nil
+			}
+			return m, openInPane(m.pane, PaneFile, m.contentLayout(), m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.PaneBlame):
+			if m.onIntro() {
+				return m, nil
+			}
+			return m, openInPane(m.pane, PaneBlame, m.contentLayout(), m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.PaneLog):
+			if m.onIntro() {
+				return m, nil
+			}
+			return m, openInPane(m.pane, PaneLog, m.contentLayout(), m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.CopyLink):
+			if m.onIntro() {
+				return m, nil
+			}
+			return m, copyPermalink(m.permalink, m.clipboard, m.contentLayout(), m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.Debug):
+			// Report on the whole diff, not just the section in view
+			m.debug = newDebugPanel(m.sectionConfig(-1), m.viewport.Width, m.viewport.Height)
 			return m, nil
 		}
 	case tea.WindowSizeMsg:
 		statusBarHeight := 1
+		started := false
 		widthChanged := m.width != msg.Width
 		m.width = msg.Width
 
 		if !m.ready {
-			m.viewport = viewport.New(msg.Width, msg.Height-statusBarHeight)
-			m.viewport.SetContent(m.renderContent())
+			m.viewport = viewport.New(m.contentWidth(), msg.Height-statusBarHeight)
+			m.setContent()
+			m.viewport.SetYOffset(m.restoreOffset)
 			m.ready = true
+			started = true
 		} else if widthChanged {
-			m.viewport.Width = msg.Width
 			m.viewport.Height = msg.Height - statusBarHeight
-			m.viewport.SetContent(m.renderContent())
+			m.xOffset = clampXOffset(m.xOffset, maxXOffset(m.visibleDiff(), m.contentWidth(), m.tabWidth))
+			m.setContent()
 		} else {
 			m.viewport.Height = msg.Height - statusBarHeight
 		}
+		m.debug.resize(m.viewport.Width, m.viewport.Height)
+		if m.chat.active {
+			m.refreshChat()
+		}
+		// A presentation starts advancing once it's on screen
+		if started {
+			return m, m.advanceCmd()
+		}
 	}
 
 	var cmd tea.Cmd
@@ -269,15 +661,93 @@ func (m StoryModel) View() string {
 	if !m.ready {
 		return "Loading..."
 	}
-	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), m.statusBarView())
+	if m.idle.locked {
+		return renderLockScreen(m.width, m.viewport.Height+1, m.newStyle().Foreground(lipgloss.Color(m.palette.Context)))
+	}
+	if m.debug.active {
+		return lipgloss.JoinVertical(lipgloss.Left, m.debug.viewport.View(), m.statusBarView())
+	}
+	if m.chat.active {
+		return lipgloss.JoinVertical(lipgloss.Left, m.renderChat(), m.statusBarView())
+	}
+	// The narration panel takes its rows from the bottom of the code, and
+	// the presenter's header from the top
+	var narration, header string
+	if m.narrating {
+		narration = m.renderNarration()
+		m.viewport.Height = max(m.viewport.Height-lipgloss.Height(narration), 1)
+	}
+	if m.presenting {
+		if header = m.presenterHeader(); header != "" {
+			m.viewport.Height = max(m.viewport.Height-lipgloss.Height(header), 1)
+		}
+	}
+	content := highlightSelection(m.viewport.View(), m.viewport.YOffset, m.selection, m.newStyle().Reverse(true))
+	content = m.renderLaser(content, m.viewport.YOffset)
+	if m.hasSidebar() {
+		separator := m.newStyle().Foreground(lipgloss.Color(m.palette.UIForeground)).
+			Render(strings.TrimSuffix(strings.Repeat("│\n", m.viewport.Height), "\n"))
+		content = lipgloss.JoinHorizontal(lipgloss.Top, content, separator, m.renderSidebar())
+	}
+	if header != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, header, content)
+	}
+	if m.narrating {
+		return lipgloss.JoinVertical(lipgloss.Left, content, narration, m.statusBarView())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, content, m.statusBarView())
+}
+
+// sidebarMinWidth is the narrowest terminal that shows the section on
+// screen in a sidebar right of the code, and sidebarWidth the sidebar's
+// width there, not counting the separator.
+const (
+	sidebarMinWidth = 160
+	sidebarWidth    = 48
+)
+
+// hasSidebar reports whether the section on screen is explained in a
+// sidebar: on wide terminals, past the intro slide.
+func (m StoryModel) hasSidebar() bool {
+	return m.width >= sidebarMinWidth && m.story != nil && len(m.story.Sections) > 0 && !m.onIntro()
+}
+
+// contentWidth returns the width of the code, which leaves room for the
+// sidebar when there is one.
+func (m StoryModel) contentWidth() int {
+	if m.hasSidebar() {
+		return m.width - sidebarWidth - 1
+	}
+	return m.width
+}
+
+// renderSidebar renders the title, role and explanation of the section on
+// screen, wrapped to the sidebar and cut to the viewport's height.
+func (m StoryModel) renderSidebar() string {
+	style := m.newStyle().Width(sidebarWidth).Padding(0, 1)
+	idx := m.visibleSectionIndex()
+	if idx < 0 || idx >= len(m.story.Sections) {
+		return style.Height(m.viewport.Height).Render("")
+	}
+	section := m.story.Sections[idx]
+
+	var parts []string
+	parts = append(parts, m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)).Bold(true).Render(section.Title))
+	if section.Role != "" {
+		parts = append(parts, m.newStyle().Foreground(lipgloss.Color(m.palette.Context)).Render(section.Role))
+	}
+	if section.Explanation != "" {
+		parts = append(parts, "", m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)).Render(section.Explanation))
+	}
+	return style.Height(m.viewport.Height).MaxHeight(m.viewport.Height).Render(strings.Join(parts, "\n"))
 }
 
 // onIntro returns true if the viewer is on the intro slide.
 func (m StoryModel) onIntro() bool {
-	return m.showIntro && m.activeSection == 0
+	return m.showIntro && m.activeSection == 0 && !m.allSections
 }
 
-// codeSectionIndex returns the index into story.Sections for the current view.
+// codeSectionIndex returns the index into story.Sections for the active section.
 // Returns -1 if on the intro slide.
 func (m StoryModel) codeSectionIndex() int {
 	if m.showIntro {
@@ -298,25 +768,105 @@ func (m StoryModel) totalSections() int {
 	return total
 }
 
+// visibleSectionIndex returns the index into story.Sections of the section
+// on screen: the active section, or in all-sections mode the one scrolled to.
+// Returns -1 if on the intro slide.
+func (m StoryModel) visibleSectionIndex() int {
+	if m.allSections {
+		current, _ := m.currentPosition(m.sectionRows)
+		return current - 1
+	}
+	return m.codeSectionIndex()
+}
+
+// setContent renders the content for the current mode into the viewport,
+// narrowing it to make room for the sidebar when there is one.
+func (m *StoryModel) setContent() {
+	m.viewport.Width = m.contentWidth()
+	if m.allSections {
+		content, layout, sectionRows := m.renderAllSections()
+		m.allLayout = layout
+		m.sectionRows = sectionRows
+		m.viewport.SetContent(content)
+		return
+	}
+	m.viewport.SetContent(m.renderContent())
+}
+
 // renderContent renders the diff content with story-aware configuration.
 func (m StoryModel) renderContent() string {
 	if m.onIntro() {
 		return m.renderIntro()
 	}
-	diff, originalIndices := m.filteredDiffWithIndices()
-	return renderDiff(renderConfig{
+	return renderDiff(m.diffConfig())
+}
+
+// renderAllSections renders every section in story order, each opened by
+// its banner, and returns the content, its layout and the row each section
+// starts on. Sections are rendered separately so a file split across
+// sections appears under each of them.
+func (m StoryModel) renderAllSections() (string, diffLayout, []int) {
+	if m.story == nil {
+		return "", diffLayout{}, nil
+	}
+	var sb strings.Builder
+	var layout diffLayout
+	sectionRows := make([]int, len(m.story.Sections))
+	row := 0
+	for idx := range m.story.Sections {
+		sectionRows[idx] = row
+		content, sectionLayout := renderDiffLayout(m.sectionConfig(idx))
+		layout.append(sectionLayout.shift(row))
+		sb.WriteString(content)
+		row += strings.Count(content, "\n")
+	}
+	return sb.String(), layout, sectionRows
+}
+
+// contentLayout returns the layout of the diff content on screen.
+func (m StoryModel) contentLayout() diffLayout {
+	if m.allSections {
+		return m.allLayout
+	}
+	_, layout := renderDiffLayout(m.diffConfig())
+	return layout
+}
+
+// diffConfig returns the render configuration for the current section.
+func (m StoryModel) diffConfig() renderConfig {
+	return m.sectionConfig(m.codeSectionIndex())
+}
+
+// sectionConfig returns the render configuration for the section at idx.
+func (m StoryModel) sectionConfig(idx int) renderConfig {
+	diff, originalIndices := m.sectionDiffWithIndices(idx)
+	return renderConfig{
 		diff:             diff,
 		styles:           m.styles,
 		renderer:         m.renderer,
-		width:            m.width,
+		width:            m.contentWidth(),
 		languageDetector: m.languageDetector,
 		tokenizer:        m.tokenizer,
 		wordDiffer:       m.wordDiffer,
+		tabWidth:         m.tabWidth,
+		wrap:             m.wrap,
+		xOffset:          m.xOffset,
 		collapsedHunks:   m.collapsedHunks,
 		hunkCategories:   m.hunkCategories,
 		collapseText:     m.collapseText,
 		originalIndices:  originalIndices,
-	})
+		sectionBanners:   m.sectionBanners(idx),
+		cache:            m.cache,
+	}
+}
+
+// sectionBanners returns the banner that opens the diff of the section at
+// idx, so the section shows in the content and not just the status bar.
+func (m StoryModel) sectionBanners(idx int) map[hunkKey]diffview.Section {
+	if m.story == nil || idx < 0 || idx >= len(m.story.Sections) {
+		return nil
+	}
+	return sectionStarts(m.diff, m.story.Sections[idx:idx+1])
 }
 
 // renderIntro renders the intro slide content.
@@ -348,6 +898,24 @@ func (m StoryModel) renderIntro() string {
 		}
 	}
 
+	// Commit type and version bump, when analyzed
+	if m.story != nil && m.story.Impact != nil {
+		b.WriteString("\n")
+		b.WriteString(impactSummary(m.story.Impact))
+	}
+
+	// Risk, when the classifier assessed it
+	if m.story != nil && m.story.Risk != nil {
+		b.WriteString("\n")
+		b.WriteString(riskSummary(m.story.Risk))
+	}
+
+	// Who should review it, when suggested
+	if m.input != nil && len(m.input.Reviewers) > 0 {
+		b.WriteString("\n")
+		b.WriteString(reviewersSummary(m.input.Reviewers))
+	}
+
 	// Section list
 	if hasSections {
 		b.WriteString("\nSections:\n")
@@ -366,7 +934,7 @@ func (m StoryModel) renderIntro() string {
 	}
 
 	// Navigation hint
-	b.WriteString("\n\n[s] next section\n")
+	b.WriteString("\n\n[s] next section  [a] all sections\n")
 
 	return b.String()
 }
@@ -375,10 +943,14 @@ func (m StoryModel) renderIntro() string {
 // along with a mapping from (file, filtered position) to original hunk index.
 // If there are no sections or the active section is invalid, returns the full diff with nil indices.
 func (m StoryModel) filteredDiffWithIndices() (*diffview.Diff, map[hunkKey]int) {
+	return m.sectionDiffWithIndices(m.codeSectionIndex())
+}
+
+// sectionDiffWithIndices is filteredDiffWithIndices for the section at idx.
+func (m StoryModel) sectionDiffWithIndices(idx int) (*diffview.Diff, map[hunkKey]int) {
 	if m.diff == nil || m.story == nil || len(m.story.Sections) == 0 {
 		return m.diff, nil
 	}
-	idx := m.codeSectionIndex()
 	if idx < 0 || idx >= len(m.story.Sections) {
 		return m.diff, nil
 	}
@@ -395,7 +967,7 @@ func (m StoryModel) filteredDiffWithIndices() (*diffview.Diff, map[hunkKey]int)
 	originalIndices := make(map[hunkKey]int)
 	var filteredFiles []diffview.FileDiff
 	for _, file := range m.diff.Files {
-		path := filePath(file)
+		path := file.Path()
 		var filteredHunks []diffview.Hunk
 		for hunkIdx, hunk := range file.Hunks {
 			if activeHunks[hunkKey{file: path, hunkIndex: hunkIdx}] {
@@ -423,80 +995,70 @@ func (m StoryModel) filteredDiff() *diffview.Diff {
 	return diff
 }
 
-// computePositions calculates line positions for the current section's filtered diff.
-// Returns hunk positions (in display order) and HunkRefs (for looking up original indices).
-func (m StoryModel) computePositions() (hunkPositions []int, hunkRefs []diffview.HunkRef, filePositions []int) {
-	filtered := m.filteredDiff()
-	if filtered == nil {
-		return nil, nil, nil
+// visibleDiff returns the diff whose hunks are on screen.
+func (m StoryModel) visibleDiff() *diffview.Diff {
+	if m.allSections {
+		return m.diff
 	}
+	return m.filteredDiff()
+}
 
-	// Build map from hunkKey to HunkRef for O(1) lookup
-	// This handles the case where section's HunkRefs may not be in file order
-	refMap := make(map[hunkKey]diffview.HunkRef)
-	idx := m.codeSectionIndex()
-	if m.story != nil && idx >= 0 && idx < len(m.story.Sections) {
-		for _, ref := range m.story.Sections[idx].Hunks {
-			refMap[hunkKey{file: ref.File, hunkIndex: ref.HunkIndex}] = ref
+// positions returns the rows of each file and hunk of the content on
+// screen: the current section's, or every section's in all-sections mode.
+func (m StoryModel) positions() diffview.Layout {
+	if !m.allSections {
+		return m.diffConfig().layout()
+	}
+	var layout diffview.Layout
+	if m.story != nil {
+		for idx := range m.story.Sections {
+			layout.Append(m.sectionConfig(idx).layout())
 		}
 	}
+	return layout
+}
 
-	lineNum := 0
-	for _, file := range filtered.Files {
-		if !shouldRenderFile(file) {
-			continue
-		}
+// toggleWrap switches between soft-wrapped lines and horizontal scrolling,
+// keeping the current hunk in view.
+func (m *StoryModel) toggleWrap() {
+	hunk, _ := m.currentPosition(m.positions().Hunks)
+	atTop := m.viewport.AtTop()
 
-		path := filePath(file)
-		filePositions = append(filePositions, lineNum)
-		lineNum++ // file header
+	m.wrap = !m.wrap
+	m.xOffset = 0
+	m.setContent()
 
-		if len(file.Hunks) == 0 {
-			lineNum++ // "(empty)" line
-		} else {
-			// Track which original hunk index we're at for this file
-			// filteredDiff() preserves order, so we scan through original indices
-			origIdx := 0
-			for _, hunk := range file.Hunks {
-				hunkPositions = append(hunkPositions, lineNum)
-
-				// When no sections exist, refMap is empty - create synthetic ref
-				if len(refMap) == 0 {
-					hunkRefs = append(hunkRefs, diffview.HunkRef{
-						File:      path,
-						HunkIndex: origIdx,
-					})
-					origIdx++
-				} else {
-					// Find original index by scanning until we hit one in the section
-					for {
-						key := hunkKey{file: path, hunkIndex: origIdx}
-						if ref, ok := refMap[key]; ok {
-							hunkRefs = append(hunkRefs, ref)
-							origIdx++
-							break
-						}
-						origIdx++
-					}
-				}
-
-				// Use the just-appended ref for collapse check
-				lastRef := hunkRefs[len(hunkRefs)-1]
-				key := hunkKey{file: lastRef.File, hunkIndex: lastRef.HunkIndex}
-				if m.collapsedHunks[key] {
-					lineNum++ // collapsed: single line
-				} else {
-					lineNum++                  // header
-					lineNum += len(hunk.Lines) // content
-				}
-			}
-		}
+	if !atTop && hunk > 0 {
+		m.viewport.SetYOffset(m.positions().Hunks[hunk-1])
+	}
+}
+
+// scrollHorizontal scrolls line content by delta columns. The line number
+// gutter and diff prefix stay in place. Has no effect while wrapping.
+func (m *StoryModel) scrollHorizontal(delta int) {
+	if m.wrap || m.onIntro() {
+		return
 	}
-	return hunkPositions, hunkRefs, filePositions
+	offset := clampXOffset(m.xOffset+delta, maxXOffset(m.visibleDiff(), m.contentWidth(), m.tabWidth))
+	if offset == m.xOffset {
+		return
+	}
+	m.xOffset = offset
+	m.setContent()
 }
 
-// gotoNextSection switches to the next section.
+// gotoNextSection switches to the next section, or in all-sections mode
+// scrolls to its banner.
 func (m *StoryModel) gotoNextSection() {
+	if m.allSections {
+		for _, row := range m.sectionRows {
+			if row > m.viewport.YOffset {
+				m.viewport.SetYOffset(row)
+				return
+			}
+		}
+		return
+	}
 	total := m.totalSections()
 	if total == 0 {
 		return
@@ -504,7 +1066,8 @@ func (m *StoryModel) gotoNextSection() {
 	// Move to next section if possible
 	if m.activeSection < total-1 {
 		m.activeSection++
-		m.viewport.SetContent(m.renderContent())
+		m.xOffset = 0
+		m.setContent()
 		m.viewport.GotoTop()
 	}
 }
@@ -515,7 +1078,7 @@ func (m *StoryModel) toggleAllCollapse() {
 	if m.story == nil || len(m.story.Sections) == 0 {
 		return
 	}
-	idx := m.codeSectionIndex()
+	idx := m.visibleSectionIndex()
 	if idx < 0 || idx >= len(m.story.Sections) {
 		return // On intro slide or invalid section
 	}
@@ -554,11 +1117,45 @@ func (m *StoryModel) toggleAllCollapse() {
 	}
 
 	// Re-render content
-	m.viewport.SetContent(m.renderContent())
+	m.setContent()
+}
+
+// toggleAllSections switches between showing one section at a time and
+// scrolling through all of them, keeping the current section in view.
+func (m *StoryModel) toggleAllSections() {
+	if m.story == nil || len(m.story.Sections) == 0 {
+		return
+	}
+	m.xOffset = 0
+	if m.allSections {
+		idx := max(m.visibleSectionIndex(), 0)
+		m.allSections = false
+		m.activeSection = idx
+		if m.showIntro {
+			m.activeSection++
+		}
+		m.setContent()
+		m.viewport.GotoTop()
+		return
+	}
+	idx := max(m.codeSectionIndex(), 0)
+	m.allSections = true
+	m.setContent()
+	m.viewport.SetYOffset(m.sectionRows[idx])
 }
 
-// gotoPrevSection switches to the previous section.
+// gotoPrevSection switches to the previous section, or in all-sections mode
+// scrolls to the banner above the top of the screen.
 func (m *StoryModel) gotoPrevSection() {
+	if m.allSections {
+		for i := len(m.sectionRows) - 1; i >= 0; i-- {
+			if m.sectionRows[i] < m.viewport.YOffset {
+				m.viewport.SetYOffset(m.sectionRows[i])
+				return
+			}
+		}
+		return
+	}
 	total := m.totalSections()
 	if total == 0 {
 		return
@@ -566,22 +1163,83 @@ func (m *StoryModel) gotoPrevSection() {
 	// Move to previous section if possible
 	if m.activeSection > 0 {
 		m.activeSection--
-		m.viewport.SetContent(m.renderContent())
+		m.xOffset = 0
+		m.setContent()
 		m.viewport.GotoTop()
 	}
 }
 
-func (m *StoryModel) saveCurrentCase() {
-	if m.caseSaver == nil || m.caseSaverPath == "" || m.input == nil || m.story == nil {
+// restoreState applies a saved view state. The scroll offset is applied when
+// the viewport is created.
+func (m *StoryModel) restoreState(state diffview.ViewState) {
+	if state.ActiveSection >= 0 && state.ActiveSection < m.totalSections() {
+		m.activeSection = state.ActiveSection
+	}
+	m.allSections = state.AllSections && m.story != nil && len(m.story.Sections) > 0
+	m.wrap = state.Wrap
+	m.collapsedHunks = make(map[hunkKey]bool, len(state.Collapsed))
+	for _, ref := range state.Collapsed {
+		m.collapsedHunks[hunkKey{file: ref.File, hunkIndex: ref.HunkIndex}] = true
+	}
+	m.restoreOffset = state.Offset
+}
+
+// saveState records the reader's place in the diff so the next session can
+// resume there.
+func (m StoryModel) saveState() {
+	if m.stateStore == nil {
 		return
 	}
 
+	state := diffview.ViewState{
+		Offset:        m.viewport.YOffset,
+		ActiveSection: m.activeSection,
+		AllSections:   m.allSections,
+		Wrap:          m.wrap,
+	}
+	for key, collapsed := range m.collapsedHunks {
+		if collapsed {
+			state.Collapsed = append(state.Collapsed, diffview.HunkRef{File: key.file, HunkIndex: key.hunkIndex})
+		}
+	}
+	sort.Slice(state.Collapsed, func(i, j int) bool {
+		a, b := state.Collapsed[i], state.Collapsed[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.HunkIndex < b.HunkIndex
+	})
+
+	// Best-effort save - errors are silently ignored in UI
+	_ = m.stateStore.Save(m.diff, state)
+}
+
+func (m *StoryModel) saveCurrentCase() tea.Cmd {
+	if m.caseSaver == nil || m.caseSaverPath == "" || m.input == nil || m.story == nil {
+		return nil
+	}
+
+	saver, path := m.caseSaver, m.caseSaverPath
 	evalCase := diffview.EvalCase{
 		Input: *m.input,
 		Story: m.story,
 	}
-	// Best-effort save - errors are silently ignored in UI
-	_ = m.caseSaver.Save(m.caseSaverPath, evalCase)
+	return func() tea.Msg {
+		return toastResult("case saved to "+path, "saving case", saver.Save(path, evalCase))
+	}
+}
+
+// requestPatch asks to confirm applying, or reverting, the hunks of the
+// section in view. Does nothing without a patcher or on the intro slide.
+func (m *StoryModel) requestPatch(reverse bool) {
+	if m.patch == nil {
+		return
+	}
+	idx := m.visibleSectionIndex()
+	if idx < 0 || m.story == nil || idx >= len(m.story.Sections) {
+		return
+	}
+	m.pendingPatch = &patchRequest{section: idx, reverse: reverse}
 }
 
 // newStyle creates a new lipgloss style using the model's renderer.
@@ -592,8 +1250,19 @@ func (m StoryModel) newStyle() lipgloss.Style {
 	return lipgloss.NewStyle()
 }
 
+// errorStyle returns the status bar style for error toasts.
+func (m StoryModel) errorStyle() lipgloss.Style {
+	return m.newStyle().
+		Background(lipgloss.Color(m.palette.UIBackground)).
+		Foreground(lipgloss.Color(m.palette.Deleted)).
+		Bold(true)
+}
+
 // statusBarView renders the status bar with position info.
 func (m StoryModel) statusBarView() string {
+	if m.presenting {
+		return m.presenterStatusBar()
+	}
 	barStyle := m.newStyle().
 		Background(lipgloss.Color(m.palette.UIBackground)).
 		Foreground(lipgloss.Color(m.palette.Foreground))
@@ -607,9 +1276,9 @@ func (m StoryModel) statusBarView() string {
 		Foreground(lipgloss.Color(m.palette.UIForeground))
 
 	// Format position info
-	hunkPositions, _, filePositions := m.computePositions()
-	fileIdx, fileTotal := m.currentPosition(filePositions)
-	hunkIdx, hunkTotal := m.currentPosition(hunkPositions)
+	positions := m.positions()
+	fileIdx, fileTotal := m.currentPosition(positions.Files)
+	hunkIdx, hunkTotal := m.currentPosition(positions.Hunks)
 	sectionIdx, sectionTotal, sectionTitle := m.currentSection()
 
 	fileWidth := digitWidth(fileTotal)
@@ -631,9 +1300,22 @@ func (m StoryModel) statusBarView() string {
 		content += barStyle.Render(sectionPos) + sep
 	}
 
-	content += barStyle.Render(scrollPos) + sep +
-		dimStyle.Render("j/k:scroll  s/S:section  z:toggle noise  e:save  q:quit") +
-		barStyle.Render("  ")
+	hints := dimStyle.Render("j/k:scroll  s/S:section  z:toggle noise  w:wrap  e:save  q:quit")
+	if m.chat.active {
+		hints = dimStyle.Render("enter:ask  ↑/↓:scroll  esc:close")
+	}
+	switch {
+	case m.pendingPatch != nil:
+		prompt := fmt.Sprintf("%s section %d in worktree? y confirm  n cancel", m.pendingPatch.verb(), m.pendingPatch.section+1)
+		hints = barStyle.Render(strings.ToUpper(prompt[:1]) + prompt[1:])
+	case m.laser.prompting:
+		hints = barStyle.Render(m.laserPrompt())
+	case m.notice != "":
+		hints = barStyle.Render(m.notice)
+	case m.toast.text != "":
+		hints = m.toast.render(barStyle, m.errorStyle())
+	}
+	content += barStyle.Render(scrollPos) + sep + hints + barStyle.Render("  ")
 
 	// Right-align by padding left side with background
 	contentWidth := lipgloss.Width(content)
@@ -645,25 +1327,20 @@ func (m StoryModel) statusBarView() string {
 	return content
 }
 
+// Position returns where the view stands in the diff. Sections are numbered
+// as in the status bar, where the intro slide comes first.
+func (m StoryModel) Position() Position {
+	positions := m.positions()
+	pos := Position{Line: m.viewport.YOffset}
+	pos.File, pos.Files = m.currentPosition(positions.Files)
+	pos.Hunk, pos.Hunks = m.currentPosition(positions.Hunks)
+	pos.Section, pos.Sections, _ = m.currentSection()
+	return pos
+}
+
 // currentPosition returns the current position (1-based) and total count.
 func (m StoryModel) currentPosition(positions []int) (current, total int) {
-	total = len(positions)
-	if total == 0 {
-		return 0, 0
-	}
-
-	currentLine := m.viewport.YOffset
-	current = 1
-
-	for i, pos := range positions {
-		if pos <= currentLine {
-			current = i + 1
-		} else {
-			break
-		}
-	}
-
-	return current, total
+	return diffview.Position(positions, m.viewport.YOffset)
 }
 
 // scrollPosition returns a string indicating the scroll position.
@@ -686,6 +1363,11 @@ func (m StoryModel) currentSection() (current, total int, title string) {
 		return 0, 0, ""
 	}
 
+	if m.allSections {
+		idx := max(m.visibleSectionIndex(), 0)
+		return idx + 1, len(m.story.Sections), m.story.Sections[idx].Title
+	}
+
 	current = m.activeSection + 1 // Convert 0-based to 1-based
 
 	if m.onIntro() {
@@ -0,0 +1,208 @@
+package bubbletea
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fwojciec/diffstory"
+)
+
+// editorRow is a line in the story editor: a section header, or one of the
+// section's hunks when hunk is not -1.
+type editorRow struct {
+	section int
+	hunk    int
+}
+
+// storyEditor edits a copy of a story classification: hunks can be moved
+// between sections and recategorized, and sections retitled or added.
+type storyEditor struct {
+	story  diffview.StoryClassification
+	cursor int // index into rows()
+
+	retitling bool
+	title     string
+}
+
+// newStoryEditor returns an editor working on a copy of story.
+func newStoryEditor(story diffview.StoryClassification) storyEditor {
+	return storyEditor{story: copyStory(story)}
+}
+
+// copyStory returns a copy of story that shares no slices with it.
+func copyStory(story diffview.StoryClassification) diffview.StoryClassification {
+	sections := make([]diffview.Section, len(story.Sections))
+	for i, s := range story.Sections {
+		s.Hunks = append([]diffview.HunkRef(nil), s.Hunks...)
+		sections[i] = s
+	}
+	story.Sections = sections
+	return story
+}
+
+// rows returns the editor's lines: each section header followed by its hunks.
+func (e storyEditor) rows() []editorRow {
+	var rows []editorRow
+	for i, s := range e.story.Sections {
+		rows = append(rows, editorRow{section: i, hunk: -1})
+		for j := range s.Hunks {
+			rows = append(rows, editorRow{section: i, hunk: j})
+		}
+	}
+	return rows
+}
+
+// current returns the row under the cursor.
+func (e storyEditor) current() (editorRow, bool) {
+	rows := e.rows()
+	if e.cursor < 0 || e.cursor >= len(rows) {
+		return editorRow{}, false
+	}
+	return rows[e.cursor], true
+}
+
+// moveCursor moves the cursor by delta rows, stopping at either end.
+func (e *storyEditor) moveCursor(delta int) {
+	e.cursor = max(0, min(e.cursor+delta, len(e.rows())-1))
+}
+
+// moveHunk moves the hunk under the cursor to the end of the next section,
+// or the previous one if delta is negative, keeping the cursor on it.
+func (e *storyEditor) moveHunk(delta int) {
+	row, ok := e.current()
+	if !ok || row.hunk < 0 {
+		return
+	}
+	to := row.section + delta
+	if to < 0 || to >= len(e.story.Sections) {
+		return
+	}
+	from := &e.story.Sections[row.section]
+	h := from.Hunks[row.hunk]
+	from.Hunks = append(from.Hunks[:row.hunk], from.Hunks[row.hunk+1:]...)
+	e.story.Sections[to].Hunks = append(e.story.Sections[to].Hunks, h)
+
+	for i, r := range e.rows() {
+		if r.section == to && r.hunk == len(e.story.Sections[to].Hunks)-1 {
+			e.cursor = i
+		}
+	}
+}
+
+// cycleCategory gives the hunk under the cursor the next category, in the
+// order of diffview.HunkCategories.
+func (e *storyEditor) cycleCategory() {
+	row, ok := e.current()
+	if !ok || row.hunk < 0 {
+		return
+	}
+	h := &e.story.Sections[row.section].Hunks[row.hunk]
+	categories := diffview.HunkCategories()
+	next := categories[0]
+	for i, c := range categories {
+		if c == h.Category && i+1 < len(categories) {
+			next = categories[i+1]
+		}
+	}
+	h.Category = next
+}
+
+// addSection adds an empty section after the one under the cursor and
+// starts retitling it.
+func (e *storyEditor) addSection() {
+	at := len(e.story.Sections)
+	if row, ok := e.current(); ok {
+		at = row.section + 1
+	}
+	sections := append([]diffview.Section(nil), e.story.Sections[:at]...)
+	sections = append(sections, diffview.Section{Role: "supporting"})
+	e.story.Sections = append(sections, e.story.Sections[at:]...)
+
+	for i, r := range e.rows() {
+		if r.section == at && r.hunk < 0 {
+			e.cursor = i
+		}
+	}
+	e.startRetitle()
+}
+
+// startRetitle opens the title prompt for the section under the cursor.
+func (e *storyEditor) startRetitle() {
+	row, ok := e.current()
+	if !ok {
+		return
+	}
+	e.retitling = true
+	e.title = e.story.Sections[row.section].Title
+}
+
+// updateTitle handles a key press while the title prompt is open. Enter sets
+// the title; esc leaves it unchanged.
+func (e *storyEditor) updateTitle(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		e.retitling = false
+	case tea.KeyEnter:
+		e.retitling = false
+		if row, ok := e.current(); ok {
+			e.story.Sections[row.section].Title = e.title
+		}
+	case tea.KeyBackspace:
+		if e.title != "" {
+			runes := []rune(e.title)
+			e.title = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		e.title = ""
+	case tea.KeyRunes, tea.KeySpace:
+		e.title += string(msg.Runes)
+	}
+}
+
+// result returns the edited classification without its empty sections.
+func (e storyEditor) result() diffview.StoryClassification {
+	story := copyStory(e.story)
+	sections := story.Sections[:0]
+	for _, s := range story.Sections {
+		if len(s.Hunks) > 0 {
+			sections = append(sections, s)
+		}
+	}
+	story.Sections = sections
+	return story
+}
+
+// view renders the sections and their hunks, with the cursor row reversed.
+func (e storyEditor) view() string {
+	cursorStyle := lipgloss.NewStyle().Reverse(true)
+	sectionStyle := lipgloss.NewStyle().Bold(true)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	cur, _ := e.current()
+	var s strings.Builder
+	for i, r := range e.rows() {
+		section := e.story.Sections[r.section]
+		var line string
+		if r.hunk < 0 {
+			title := section.Title
+			if e.retitling && r.section == cur.section {
+				title = e.title + "█"
+			} else if title == "" {
+				title = faint.Render("(untitled)")
+			}
+			line = sectionStyle.Render(fmt.Sprintf("%d. ", r.section+1)) + title +
+				faint.Render(" ["+section.Role+"]")
+		} else {
+			h := section.Hunks[r.hunk]
+			line = fmt.Sprintf("   %s:H%d  %s", h.File, h.HunkIndex, faint.Render(h.Category))
+		}
+		if i == e.cursor {
+			line = cursorStyle.Render(line)
+		}
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+	return s.String()
+}
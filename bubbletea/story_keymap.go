@@ -20,14 +20,48 @@ type StoryKeyMap struct {
 	Quit         key.Binding
 
 	// Section navigation (story-specific)
-	NextSection key.Binding
-	PrevSection key.Binding
+	NextSection       key.Binding
+	PrevSection       key.Binding
+	ToggleAllSections key.Binding
 
 	// Hunk collapsing (story-specific)
 	ToggleCollapseAll key.Binding
 
+	// Long lines
+	ToggleWrap  key.Binding
+	ScrollLeft  key.Binding
+	ScrollRight key.Binding
+
+	// OpenEditor uses o because e saves the case
+	OpenEditor key.Binding
+	Quickfix   key.Binding
+	CopyLink   key.Binding
+
+	// Panes beside the viewer
+	PaneFile  key.Binding
+	PaneBlame key.Binding
+	PaneLog   key.Binding
+
+	// Narration and questions
+	ToggleNarration key.Binding
+	Chat            key.Binding
+
+	// Presenting on a call
+	TogglePresenter key.Binding
+	PauseAdvance    key.Binding
+	Highlight       key.Binding
+
 	// Export
 	SaveCase key.Binding
+
+	// Working tree
+	ApplySection  key.Binding
+	RevertSection key.Binding
+	ConfirmPatch  key.Binding
+	CancelPatch   key.Binding
+
+	// Diagnostics
+	Debug key.Binding
 }
 
 // DefaultStoryKeyMap returns the default key bindings for story mode.
@@ -85,13 +119,93 @@ func DefaultStoryKeyMap() StoryKeyMap {
 			key.WithKeys("S"),
 			key.WithHelp("S", "previous section"),
 		),
+		ToggleAllSections: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "toggle all sections"),
+		),
 		ToggleCollapseAll: key.NewBinding(
 			key.WithKeys("z"),
 			key.WithHelp("z", "toggle LLM-collapsed"),
 		),
+		ToggleWrap: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "toggle line wrap"),
+		),
+		ScrollLeft: key.NewBinding(
+			key.WithKeys("h", "left"),
+			key.WithHelp("h/←", "scroll left"),
+		),
+		ScrollRight: key.NewBinding(
+			key.WithKeys("l", "right"),
+			key.WithHelp("l/→", "scroll right"),
+		),
+		OpenEditor: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open in editor"),
+		),
+		Quickfix: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "send section to editor's quickfix list"),
+		),
+		CopyLink: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy web link to line"),
+		),
+		PaneFile: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "show file in a pane"),
+		),
+		PaneBlame: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "show blame in a pane"),
+		),
+		PaneLog: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "show file history in a pane"),
+		),
+		ToggleNarration: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "toggle narration"),
+		),
+		Chat: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "ask about this section"),
+		),
+		TogglePresenter: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "toggle presenter mode"),
+		),
+		PauseAdvance: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "pause auto-advance"),
+		),
+		Highlight: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "highlight lines"),
+		),
 		SaveCase: key.NewBinding(
 			key.WithKeys("e"),
 			key.WithHelp("e", "save case to eval dataset"),
 		),
+		ApplySection: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "apply section to worktree"),
+		),
+		RevertSection: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "revert section in worktree"),
+		),
+		ConfirmPatch: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "confirm"),
+		),
+		CancelPatch: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n", "cancel"),
+		),
+		Debug: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "debug info"),
+		),
 	}
 }
@@ -2,7 +2,10 @@ package bubbletea_test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"testing"
 
@@ -12,7 +15,10 @@ import (
 	"github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/bubbletea"
 	dv "github.com/fwojciec/diffstory/lipgloss"
+	"github.com/fwojciec/diffstory/mock"
 	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStoryModel_BasicRendering(t *testing.T) {
@@ -806,32 +812,6 @@ func storyTrueColorRenderer() *lipgloss.Renderer {
 	return r
 }
 
-// storyMockTokenizer implements diffview.Tokenizer for testing.
-type storyMockTokenizer struct {
-	TokenizeFn      func(language, source string) []diffview.Token
-	TokenizeLinesFn func(language, source string) [][]diffview.Token
-}
-
-func (m *storyMockTokenizer) Tokenize(language, source string) []diffview.Token {
-	return m.TokenizeFn(language, source)
-}
-
-func (m *storyMockTokenizer) TokenizeLines(language, source string) [][]diffview.Token {
-	if m.TokenizeLinesFn != nil {
-		return m.TokenizeLinesFn(language, source)
-	}
-	return nil
-}
-
-// storyMockLanguageDetector implements diffview.LanguageDetector for testing.
-type storyMockLanguageDetector struct {
-	DetectFromPathFn func(path string) string
-}
-
-func (m *storyMockLanguageDetector) DetectFromPath(path string) string {
-	return m.DetectFromPathFn(path)
-}
-
 func TestStoryModel_ExpandedHunksGetFullStyling(t *testing.T) {
 	t.Parallel()
 
@@ -880,7 +860,7 @@ func TestStoryModel_ExpandedHunksGetFullStyling(t *testing.T) {
 	theme := dv.TestTheme()
 
 	// Mock tokenizer that returns magenta-colored keywords
-	tokenizer := &storyMockTokenizer{
+	tokenizer := &mock.Tokenizer{
 		TokenizeLinesFn: func(language, source string) [][]diffview.Token {
 			if language != "Go" {
 				return nil
@@ -907,7 +887,7 @@ func TestStoryModel_ExpandedHunksGetFullStyling(t *testing.T) {
 	}
 
 	// Mock detector that returns "Go" for .go files
-	detector := &storyMockLanguageDetector{
+	detector := &mock.LanguageDetector{
 		DetectFromPathFn: func(path string) string {
 			if len(path) >= 3 && path[len(path)-3:] == ".go" {
 				return "Go"
@@ -1461,3 +1441,420 @@ func TestStoryModel_IntroSlide_UnknownNarrativeFallback(t *testing.T) {
 	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
 	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
 }
+
+func TestStoryModel_WrapToggle(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "a/main.go",
+				NewPath:   "b/main.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 1,
+						OldCount: 0,
+						NewStart: 1,
+						NewCount: 1,
+						Lines: []diffview.Line{
+							{Type: diffview.LineAdded, Content: "var s = \"" + strings.Repeat("x", 100) + "TAIL\"", NewLineNum: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{
+				Role:  "core",
+				Title: "Main Changes",
+				Hunks: []diffview.HunkRef{{File: "main.go", HunkIndex: 0, Category: "core"}},
+			},
+		},
+	}
+
+	m := bubbletea.NewStoryModel(diff, story)
+	tm := teatest.NewTestModel(t, m,
+		teatest.WithInitialTermSize(60, 24),
+	)
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("+var s"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("↪")) && bytes.Contains(out, []byte("TAIL"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(0))
+}
+
+func TestStoryModel_SectionBannerOpensSection(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go", "store.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Add the endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Persist requests", Hunks: []diffview.HunkRef{{File: "store.go", HunkIndex: 0}}},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story,
+		bubbletea.WithStoryTheme(dv.TestTheme()),
+		bubbletea.WithStoryRenderer(trueColorRenderer()),
+	)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+
+	// The banner spans the width above the first file header, in the role's color
+	lines := strings.Split(model.View(), "\n")
+	require.Greater(t, len(lines), 1)
+	assert.Contains(t, lines[0], "━━ core · Add the endpoint ━━")
+	assert.Contains(t, lines[0], "38;2;0;0;255") // test palette UIAccent
+	assert.Equal(t, 60, lipgloss.Width(strings.TrimRight(lines[0], " ")))
+	assert.Contains(t, lines[1], "api.go")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	lines = strings.Split(model.View(), "\n")
+	assert.Contains(t, lines[0], "━━ supporting · Persist requests ━━")
+	assert.Contains(t, lines[0], "38;2;255;255;0") // test palette Modified
+}
+
+func TestStoryModel_SectionBannerTruncatesLongTitles(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{
+				Role:  "unusual",
+				Title: "A section title much too long to fit on one narrow terminal row",
+				Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}},
+			},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 40, Height: 20})
+
+	banner := strings.Split(model.View(), "\n")[0]
+	assert.Contains(t, banner, "━━ unusual · A section")
+	assert.Contains(t, banner, "… ━━━")
+	assert.Equal(t, 40, lipgloss.Width(strings.TrimRight(banner, " ")))
+}
+
+func TestStoryModel_SectionBannerCountsTowardPositions(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("a.go", "b.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Both", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}, {File: "b.go", HunkIndex: 0}}},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+
+	// Rows: banner, a.go header, hunk header, ten lines, b.go header
+	for range 12 {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	}
+	view := model.View()
+	assert.Contains(t, strings.Split(view, "\n")[0], "line 10 of a.go")
+	assert.Contains(t, view, "file 1/2")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	view = model.View()
+	assert.Contains(t, strings.Split(view, "\n")[0], "b.go")
+	assert.Contains(t, view, "file 2/2")
+}
+
+func TestStoryModel_CopyLinkToTopVisibleLine(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "API", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+		},
+	}
+	clip := &mockClipboard{}
+	link := func(path string, line int) string {
+		return fmt.Sprintf("https://example.com/%s#L%d", path, line)
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story, bubbletea.WithStoryPermalinks(link, clip))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+
+	// Rows: banner, file header, hunk header, line 1, line 2, ...
+	for range 4 {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	}
+	// The copy runs as a command, which reports it
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	require.NotNil(t, cmd)
+	assert.Equal(t, bubbletea.ToastMsg{Text: "link copied to clipboard"}, cmd())
+
+	assert.Equal(t, "https://example.com/api.go#L2", clip.Content())
+}
+
+func TestStoryModel_AllSectionsScrollsThroughSections(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go", "store.go", "util.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Add the endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Persist requests", Hunks: []diffview.HunkRef{{File: "store.go", HunkIndex: 0}}},
+			{Role: "cleanup", Title: "Tidy helpers", Hunks: []diffview.HunkRef{{File: "util.go", HunkIndex: 0}}},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story, bubbletea.WithIntroSlide())
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	require.Contains(t, model.View(), "section 3/4: Persist requests")
+
+	// Toggling keeps the current section on screen, with the whole diff around it
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	view := model.View()
+	assert.Contains(t, strings.Split(view, "\n")[0], "━━ supporting · Persist requests")
+	assert.Contains(t, view, "section 2/3: Persist requests")
+	assert.Contains(t, view, "file 2/3")
+
+	// Scrolling up crosses into the previous section
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	view = model.View()
+	assert.Contains(t, strings.Split(view, "\n")[0], "line 10 of api.go")
+	assert.Contains(t, view, "section 1/3: Add the endpoint")
+
+	// s and S jump between banners
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	assert.Contains(t, strings.Split(model.View(), "\n")[0], "━━ supporting · Persist requests")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	assert.Contains(t, strings.Split(model.View(), "\n")[0], "━━ core · Add the endpoint")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	assert.Contains(t, strings.Split(model.View(), "\n")[0], "━━ core · Add the endpoint")
+
+	// Toggling back pages the section that was on screen
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	view = model.View()
+	assert.Contains(t, view, "section 2/4: Add the endpoint")
+	assert.NotContains(t, view, "store.go")
+}
+
+func TestStoryModel_ResumesSavedViewState(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go", "store.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Add the endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Persist requests", Hunks: []diffview.HunkRef{{File: "store.go", HunkIndex: 0, Category: "noise"}}},
+		},
+	}
+	var saved *diffview.ViewState
+	store := &mock.ViewStateStore{
+		LoadFn: func(d *diffview.Diff) (*diffview.ViewState, error) {
+			assert.Same(t, diff, d)
+			return &diffview.ViewState{Offset: 4, ActiveSection: 1}, nil
+		},
+		SaveFn: func(d *diffview.Diff, state diffview.ViewState) error {
+			assert.Same(t, diff, d)
+			saved = &state
+			return nil
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story, bubbletea.WithStoryStateStore(store))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+
+	// The saved state expands the noise hunk the classifier collapsed
+	// Rows: banner, file header, hunk header, line 1, line 2, ...
+	view := model.View()
+	assert.Contains(t, view, "section 2/2: Persist requests")
+	assert.Contains(t, strings.Split(view, "\n")[0], "line 2 of store.go")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	require.NotNil(t, saved)
+	assert.Equal(t, diffview.ViewState{Offset: 5, ActiveSection: 1}, *saved)
+}
+
+func TestStoryModel_SavesCollapsedHunksOnQuit(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go", "gen.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "API", Hunks: []diffview.HunkRef{
+				{File: "api.go", HunkIndex: 0},
+				{File: "gen.go", HunkIndex: 0, Category: "noise"},
+			}},
+		},
+	}
+	var saved *diffview.ViewState
+	store := &mock.ViewStateStore{
+		LoadFn: func(*diffview.Diff) (*diffview.ViewState, error) { return nil, nil },
+		SaveFn: func(_ *diffview.Diff, state diffview.ViewState) error {
+			saved = &state
+			return nil
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story, bubbletea.WithStoryStateStore(store))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	require.NotNil(t, saved)
+	assert.Equal(t, []diffview.HunkRef{{File: "gen.go", HunkIndex: 0}}, saved.Collapsed)
+}
+
+func TestStoryModel_RevertSectionInWorktree(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go", "store.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Add the endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Persist requests", Hunks: []diffview.HunkRef{{File: "store.go", HunkIndex: 0}}},
+		},
+	}
+
+	var patched *diffview.Diff
+	var reversed bool
+	calls := 0
+	var model tea.Model = bubbletea.NewStoryModel(diff, story,
+		bubbletea.WithStoryPatcher(func(d *diffview.Diff, reverse bool) error {
+			calls++
+			patched, reversed = d, reverse
+			return nil
+		}),
+	)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	// Cancelling leaves the worktree alone
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	assert.Contains(t, model.View(), "Apply section 2 in worktree?")
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	assert.Nil(t, cmd)
+	assert.NotContains(t, model.View(), "in worktree?")
+
+	// Confirming reverts only the section's hunks
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	assert.Contains(t, model.View(), "Revert section 2 in worktree?")
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	require.NotNil(t, cmd)
+	model, _ = model.Update(cmd())
+
+	assert.Equal(t, 1, calls)
+	assert.True(t, reversed)
+	require.Len(t, patched.Files, 1)
+	assert.Equal(t, "store.go", patched.Files[0].NewPath)
+	assert.Contains(t, model.View(), "reverted section 2 in worktree")
+}
+
+func TestStoryModel_PatchFailureShowsError(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("api.go")
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Add the endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+		},
+	}
+
+	var model tea.Model = bubbletea.NewStoryModel(diff, story,
+		bubbletea.WithStoryPatcher(func(*diffview.Diff, bool) error {
+			return errors.New("error: patch failed: api.go:1\nerror: api.go: patch does not apply")
+		}),
+	)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	require.NotNil(t, cmd)
+	model, _ = model.Update(cmd())
+
+	view := model.View()
+	assert.Contains(t, view, "apply failed: error: patch failed: api.go:1")
+	assert.NotContains(t, view, "does not apply")
+}
+
+func TestStoryModel_Sidebar(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Token refresh", Explanation: "Refreshes expired tokens before retrying the request.", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}},
+			{Role: "supporting", Title: "Tests", Explanation: "Covers the retry.", Hunks: []diffview.HunkRef{{File: "b.go", HunkIndex: 0}}},
+		},
+	}
+	newDriver := func(width int, opts ...bubbletea.StoryModelOption) *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("a.go", "b.go"), story, opts...), width, 16)
+	}
+
+	t.Run("explains the section beside the code on wide terminals", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(180)
+		lines := strings.Split(d.Frame(), "\n")
+		assert.Contains(t, lines[0], "│ Token refresh")
+		assert.Contains(t, lines[1], "core")
+		assert.Contains(t, d.Frame(), "Refreshes expired tokens")
+		assert.Contains(t, d.Frame(), "line 1 of a.go")
+
+		require.NoError(t, d.Press("s"))
+		assert.Contains(t, strings.Split(d.Frame(), "\n")[0], "│ Tests")
+	})
+
+	t.Run("keeps the code full width on narrow terminals", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(120)
+		assert.NotContains(t, d.Frame(), "Refreshes expired tokens")
+		assert.Contains(t, statusBar(d), "section 1/2: Token refresh")
+	})
+
+	t.Run("reflows when the terminal narrows", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(180)
+		d.Send(tea.WindowSizeMsg{Width: 120, Height: 16})
+		assert.NotContains(t, d.Frame(), "Refreshes expired tokens")
+	})
+
+	t.Run("leaves the intro slide full width", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(180, bubbletea.WithIntroSlide())
+		assert.NotContains(t, d.Frame(), "Refreshes expired tokens")
+	})
+}
+
+func TestStoryModel_ShowsHunksSharedBetweenSections(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Title: "Add the endpoint", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0, Category: "core"}}},
+			{Role: "api", Title: "Exported API changes", Hunks: []diffview.HunkRef{{File: "api.go", HunkIndex: 0}}},
+		},
+	}
+	d := bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("api.go"), story), 120, 20)
+
+	assert.Contains(t, d.Frame(), "line 1 of api.go")
+
+	require.NoError(t, d.Press("s"))
+	assert.Contains(t, d.Frame(), "section 2/2: Exported API changes")
+	assert.Contains(t, d.Frame(), "line 1 of api.go")
+}
@@ -0,0 +1,159 @@
+package bubbletea
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// toggleStructure switches the file in view between its line diff and the
+// key-level changes the structural differ finds in it, or for a manifest or
+// lockfile, the dependency changes, saying why when it can't.
+func (m *Model) toggleStructure() {
+	current, _ := m.currentFilePosition()
+	if current == 0 || m.structurer == nil && m.summarizer == nil {
+		m.notice = "no structural diff available"
+		return
+	}
+	idx := renderedFileIndex(m.diff, current)
+	file := m.diff.Files[idx]
+	path := file.Path()
+	if lines, ok := m.toggleDependencies(file); ok {
+		m.showFile(current, idx, lines)
+		return
+	}
+	if m.structurer == nil {
+		m.notice = "no structural diff available"
+		return
+	}
+
+	structured := maps.Clone(m.structured)
+	if structured == nil {
+		structured = make(map[string]diffview.FileDiff)
+	}
+	if lines, ok := structured[path]; ok {
+		delete(structured, path)
+		file = lines
+	} else {
+		changes, err := m.structurer.DiffStructure(file)
+		if err != nil {
+			m.notice = err.Error()
+			return
+		}
+		structured[path] = file
+		file = structureFile(file, changes)
+	}
+	m.structured = structured
+	m.showFile(current, idx, file)
+}
+
+// showFile replaces the file at idx in the diff shown, the current'th
+// rendered file, with file, keeping it in view.
+func (m *Model) showFile(current, idx int, file diffview.FileDiff) {
+	diff := *m.diff
+	diff.Files = slices.Clone(diff.Files)
+	diff.Files[idx] = file
+	m.diff = &diff
+	m.updatePositions()
+	m.viewport.SetContent(m.renderContent())
+	m.viewport.SetYOffset(m.layout.Files[current-1])
+}
+
+// showStructure shows the files of a reloaded diff that were shown
+// structurally that way again, dropping any that are gone or no longer
+// parse.
+func (m *Model) showStructure(diff *diffview.Diff) *diffview.Diff {
+	if len(m.structured) == 0 || diff == nil {
+		return diff
+	}
+	structured := make(map[string]diffview.FileDiff)
+	out := *diff
+	out.Files = slices.Clone(diff.Files)
+	for i, file := range out.Files {
+		path := file.Path()
+		if _, ok := m.structured[path]; !ok {
+			continue
+		}
+		changes, err := m.structurer.DiffStructure(file)
+		if err != nil {
+			continue
+		}
+		structured[path] = file
+		out.Files[i] = structureFile(file, changes)
+	}
+	m.structured = structured
+	return &out
+}
+
+// renderedFileIndex returns the index in diff.Files of the nth rendered
+// file, counting from 1 as currentFilePosition does.
+func renderedFileIndex(diff *diffview.Diff, n int) int {
+	for i, file := range diff.Files {
+		if file.Visible() {
+			if n--; n == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// structureFile returns file with its hunks replaced by one listing
+// changes, a removed line and an added line for each changed key, and a
+// summary of them as its section.
+func structureFile(file diffview.FileDiff, changes []diffview.KeyChange) diffview.FileDiff {
+	var hunk diffview.Hunk
+	var changed, added, removed int
+	for _, c := range changes {
+		if c.Op != diffview.KeyAdded {
+			hunk.Lines = append(hunk.Lines, diffview.Line{Type: diffview.LineDeleted, Content: c.Key + ": " + c.Old})
+		}
+		if c.Op != diffview.KeyRemoved {
+			hunk.Lines = append(hunk.Lines, diffview.Line{Type: diffview.LineAdded, Content: c.Key + ": " + c.New})
+		}
+		switch c.Op {
+		case diffview.KeyChanged:
+			changed++
+		case diffview.KeyAdded:
+			added++
+		case diffview.KeyRemoved:
+			removed++
+		}
+	}
+	var summary []string
+	for _, part := range []struct {
+		n    int
+		verb string
+	}{{changed, "changed"}, {added, "added"}, {removed, "removed"}} {
+		if part.n > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", part.n, part.verb))
+		}
+	}
+	hunk.Section = "no key changes"
+	if len(summary) > 0 {
+		hunk.Section = strings.Join(summary, ", ")
+	}
+	file.Hunks = []diffview.Hunk{hunk}
+	return file
+}
+
+// formatStructureHeader formats the header of a hunk from structureFile,
+// which has no line ranges to show.
+func formatStructureHeader(hunk diffview.Hunk) string {
+	return "@@ keys @@ " + hunk.Section
+}
+
+// structuredPaths returns the paths of the files in structured.
+func structuredPaths(structured map[string]diffview.FileDiff) map[string]bool {
+	if len(structured) == 0 {
+		return nil
+	}
+	paths := make(map[string]bool, len(structured))
+	for path := range structured {
+		paths[path] = true
+	}
+	return paths
+}
@@ -0,0 +1,151 @@
+package bubbletea_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModel_Structure(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("config.json", "main.go")
+	differ := &mock.StructuralDiffer{
+		DiffStructureFn: func(file diffview.FileDiff) ([]diffview.KeyChange, error) {
+			if file.NewPath != "config.json" {
+				return nil, errors.New(file.NewPath + ": not a JSON, YAML or TOML file")
+			}
+			return []diffview.KeyChange{
+				{Op: diffview.KeyChanged, Key: "port", Old: "8080", New: "9090"},
+				{Op: diffview.KeyAdded, Key: "tls", New: "true"},
+			}, nil
+		},
+	}
+	newDriver := func() *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewModel(diff, bubbletea.WithStructuralDiffer(differ)), 80, 8)
+	}
+
+	t.Run("t switches the file in view between lines and keys", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("t"))
+		frame := d.Frame()
+		assert.Contains(t, frame, "@@ keys @@ 1 changed, 1 added")
+		assert.Contains(t, frame, "-port: 8080")
+		assert.Contains(t, frame, "+port: 9090")
+		assert.Contains(t, frame, "+tls: true")
+		assert.NotContains(t, frame, "line 1 of config.json")
+		assert.Contains(t, frame, "@@ -0,0 +1,10 @@", "other files keep their lines")
+
+		require.NoError(t, d.Press("t"))
+		assert.Contains(t, d.Frame(), "line 1 of config.json")
+		assert.NotContains(t, d.Frame(), "@@ keys @@")
+	})
+
+	t.Run("says why a file can't be shown as keys", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("]", "t"))
+		assert.Contains(t, statusBar(d), "main.go: not a JSON, YAML or TOML file")
+		assert.Contains(t, d.Frame(), "line 1 of main.go")
+	})
+}
+
+func TestModel_DependencySummary(t *testing.T) {
+	t.Parallel()
+
+	diff := multiFileDiff("go.mod", "main.go")
+	summarizer := &mock.DependencySummarizer{
+		SummarizeDependenciesFn: func(file diffview.FileDiff) ([]diffview.DependencyChange, bool) {
+			if file.NewPath != "go.mod" {
+				return nil, false
+			}
+			return []diffview.DependencyChange{
+				{Op: diffview.DependencyUpgraded, Name: "github.com/stretchr/testify", Old: "v1.8.4", New: "v1.9.0"},
+				{Op: diffview.DependencyAdded, Name: "gopkg.in/yaml.v3", New: "v3.0.1"},
+			}, true
+		},
+	}
+	newDriver := func() *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewModel(diff, bubbletea.WithDependencySummarizer(summarizer)), 80, 8)
+	}
+
+	t.Run("shows lockfiles as their dependency changes", func(t *testing.T) {
+		t.Parallel()
+
+		frame := newDriver().Frame()
+		assert.Contains(t, frame, "@@ dependencies @@ 1 upgraded, 1 added")
+		assert.Contains(t, frame, "↑ github.com/stretchr/testify v1.8.4 → v1.9.0")
+		assert.Contains(t, frame, "+gopkg.in/yaml.v3 v3.0.1")
+		assert.NotContains(t, frame, "line 1 of go.mod")
+	})
+
+	t.Run("t switches a lockfile between its summary and lines", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+
+		require.NoError(t, d.Press("t"))
+		assert.Contains(t, d.Frame(), "line 1 of go.mod")
+		assert.NotContains(t, d.Frame(), "@@ dependencies @@")
+
+		require.NoError(t, d.Press("t"))
+		assert.Contains(t, d.Frame(), "@@ dependencies @@ 1 upgraded, 1 added")
+	})
+
+	t.Run("keeps the lines of files it finds no changes in", func(t *testing.T) {
+		t.Parallel()
+
+		empty := &mock.DependencySummarizer{
+			SummarizeDependenciesFn: func(diffview.FileDiff) ([]diffview.DependencyChange, bool) {
+				return nil, true
+			},
+		}
+		d := bubbletea.NewDriver(bubbletea.NewModel(diff, bubbletea.WithDependencySummarizer(empty)), 80, 8)
+
+		assert.Contains(t, d.Frame(), "line 1 of go.mod")
+	})
+
+	t.Run("annotators see lockfiles as they're shown", func(t *testing.T) {
+		t.Parallel()
+
+		// Badges go.mod with the number of added lines the annotator saw
+		counter := &mock.Annotator{
+			NameFn: func() string { return "counter" },
+			AnnotateFn: func(diff *diffview.Diff) []diffview.Annotation {
+				var added int
+				for _, file := range diff.Files {
+					if file.NewPath != "go.mod" {
+						continue
+					}
+					for _, hunk := range file.Hunks {
+						for _, line := range hunk.Lines {
+							if line.Type == diffview.LineAdded {
+								added++
+							}
+						}
+					}
+				}
+				return []diffview.Annotation{{Path: "go.mod", Text: "added lines", Badge: fmt.Sprintf("%d seen", added)}}
+			},
+		}
+		d := bubbletea.NewDriver(bubbletea.NewModel(diff,
+			bubbletea.WithDependencySummarizer(summarizer), bubbletea.WithAnnotators(counter)), 80, 8)
+
+		assert.Contains(t, d.Frame(), "1 seen")
+		assert.NotContains(t, d.Frame(), "10 seen")
+
+		d.Send(bubbletea.ReloadMsg{Diff: multiFileDiff("go.mod", "main.go")})
+		assert.Contains(t, d.Frame(), "1 seen", "reloaded diffs are summarized first too")
+	})
+}
@@ -0,0 +1,48 @@
+package bubbletea
+
+import "time"
+
+// reviewTimer measures how long each case is on screen during a session,
+// counting only while the reviewer is watching: the terminal has focus and
+// the screen isn't locked.
+type reviewTimer struct {
+	spent   map[string]time.Duration // time per case, up to since
+	watched string                   // case on screen, or "" if nobody is watching
+	since   time.Time                // when watching the case started
+}
+
+// newReviewTimer returns a timer that hasn't measured anything yet.
+func newReviewTimer() reviewTimer {
+	return reviewTimer{spent: make(map[string]time.Duration)}
+}
+
+// watch records that caseID is what the reviewer is watching from now on,
+// or that they're watching nothing if it's "".
+func (t *reviewTimer) watch(caseID string, now time.Time) {
+	if caseID == t.watched {
+		return
+	}
+	if t.watched != "" {
+		t.spent[t.watched] += now.Sub(t.since)
+	}
+	t.watched, t.since = caseID, now
+}
+
+// elapsed returns the time spent on caseID this session, as of now.
+func (t reviewTimer) elapsed(caseID string, now time.Time) time.Duration {
+	d := t.spent[caseID]
+	if caseID != "" && caseID == t.watched {
+		d += now.Sub(t.since)
+	}
+	return d
+}
+
+// watchedCase returns the ID of the case the reviewer is watching, or "" if
+// there's none, the terminal lost focus or the screen is locked. Browsing a
+// read-only snapshot isn't reviewing, so it isn't timed.
+func (m EvalModel) watchedCase() string {
+	if len(m.cases) == 0 || m.readOnly || m.blurred || m.idle.locked {
+		return ""
+	}
+	return m.cases[m.currentIndex].CaseID()
+}
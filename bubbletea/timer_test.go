@@ -0,0 +1,64 @@
+package bubbletea_test
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalModel_ReviewTime(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
+	}
+	reviewTime := func(d *bubbletea.Driver) time.Duration {
+		judgments := d.Model().(bubbletea.EvalModel).Judgments()
+		require.Len(t, judgments, 1)
+		return judgments[0].ReviewTime
+	}
+
+	t.Run("adds the time the case was on screen to its judgment", func(t *testing.T) {
+		t.Parallel()
+
+		m := bubbletea.NewEvalModel(cases, bubbletea.WithExistingJudgments([]diffview.Judgment{
+			{CaseID: cases[0].CaseID(), Judged: true, ReviewTime: time.Minute},
+		}))
+		d := bubbletea.NewDriver(m, 120, 20)
+		time.Sleep(10 * time.Millisecond)
+
+		assert.Greater(t, reviewTime(d), time.Minute+10*time.Millisecond)
+	})
+
+	t.Run("stops while another case is on screen", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 20)
+		require.NoError(t, d.Press("p", "n"))
+		spent := reviewTime(d)
+		time.Sleep(10 * time.Millisecond)
+
+		assert.Equal(t, spent, reviewTime(d))
+	})
+
+	t.Run("stops while the terminal doesn't have focus", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 20)
+		require.NoError(t, d.Press("p"))
+		d.Send(tea.BlurMsg{})
+		spent := reviewTime(d)
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, spent, reviewTime(d))
+
+		d.Send(tea.FocusMsg{})
+		time.Sleep(10 * time.Millisecond)
+		assert.Greater(t, reviewTime(d), spent)
+	})
+}
@@ -0,0 +1,86 @@
+package bubbletea
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultToastDuration is how long a toast shows unless it says otherwise.
+const defaultToastDuration = 3 * time.Second
+
+// ToastMsg shows a message in the status bar of the viewer, story or eval
+// model until it times out, such as the outcome of saving or copying.
+// Programs can send their own, for example to report a failed reload.
+type ToastMsg struct {
+	Text     string
+	Err      bool          // Styled as an error
+	Duration time.Duration // How long it shows; 0 means three seconds
+}
+
+// toastExpiredMsg clears the toast it was scheduled for, unless another
+// has replaced it since.
+type toastExpiredMsg struct{ id int }
+
+// toast is the message a model is showing in its status bar, if any.
+type toast struct {
+	text string
+	err  bool
+	id   int // counts toasts shown, so an old one's expiry leaves a newer alone
+}
+
+// show replaces the toast with msg and returns the command that clears it.
+func (t *toast) show(msg ToastMsg) tea.Cmd {
+	t.id++
+	t.text, t.err = msg.Text, msg.Err
+	d := msg.Duration
+	if d <= 0 {
+		d = defaultToastDuration
+	}
+	id := t.id
+	return tea.Tick(d, func(time.Time) tea.Msg { return toastExpiredMsg{id: id} })
+}
+
+// expire clears the toast msg was scheduled for if it's still showing.
+func (t *toast) expire(msg toastExpiredMsg) {
+	if msg.id == t.id {
+		t.text, t.err = "", false
+	}
+}
+
+// render renders the toast in style, or errStyle for an error, or returns ""
+// if there's none.
+func (t toast) render(style, errStyle lipgloss.Style) string {
+	switch {
+	case t.text == "":
+		return ""
+	case t.err:
+		return errStyle.Render(t.text)
+	}
+	return style.Render(t.text)
+}
+
+// notify returns a command that shows text as a toast.
+func notify(text string) tea.Cmd {
+	return func() tea.Msg { return ToastMsg{Text: text} }
+}
+
+// toastResult returns a toast of done if err is nil, or else of err
+// prefixed with what failed, for commands that do their work before
+// reporting it.
+func toastResult(done, what string, err error) ToastMsg {
+	if err != nil {
+		return ToastMsg{Text: what + ": " + err.Error(), Err: true}
+	}
+	return ToastMsg{Text: done}
+}
+
+// notifyErr returns a command that shows err as an error toast, prefixed
+// with what failed, or nil if err is nil.
+func notifyErr(what string, err error) tea.Cmd {
+	if err == nil {
+		return nil
+	}
+	return func() tea.Msg { return ToastMsg{Text: what + ": " + err.Error(), Err: true} }
+}
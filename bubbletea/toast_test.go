@@ -0,0 +1,97 @@
+package bubbletea_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModel_Toast(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shows a toast in the status bar until it times out", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go")), 100, 10)
+
+		d.Send(bubbletea.ToastMsg{Text: "reloaded", Duration: time.Hour})
+		assert.Contains(t, statusBar(d), "reloaded")
+
+		require.NoError(t, d.Press("j"))
+		assert.Contains(t, statusBar(d), "reloaded", "keys don't dismiss toasts")
+	})
+
+	t.Run("clears a toast once its time is up", func(t *testing.T) {
+		t.Parallel()
+
+		d := bubbletea.NewDriver(bubbletea.NewModel(multiFileDiff("a.go")), 100, 10,
+			bubbletea.WithCommandTimeout(time.Second))
+
+		d.Send(bubbletea.ToastMsg{Text: "reloaded", Duration: time.Millisecond})
+		assert.NotContains(t, statusBar(d), "reloaded")
+	})
+}
+
+func TestStoryModel_Toast(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{{Title: "Only", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0}}}},
+	}
+	newDriver := func(err error) *bubbletea.Driver {
+		saver := &mock.EvalCaseSaver{SaveFn: func(string, diffview.EvalCase) error { return err }}
+		return bubbletea.NewDriver(bubbletea.NewStoryModel(multiFileDiff("a.go"), story,
+			bubbletea.WithStoryInput(diffview.ClassificationInput{Repo: "repo"}),
+			bubbletea.WithStoryCaseSaver(saver, "cases.jsonl")), 140, 10)
+	}
+
+	t.Run("says where a saved case went", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(nil)
+		require.NoError(t, d.Press("e"))
+		assert.Contains(t, statusBar(d), "case saved to cases.jsonl")
+	})
+
+	t.Run("says why a case wasn't saved", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver(errors.New("disk full"))
+		require.NoError(t, d.Press("e"))
+		assert.Contains(t, statusBar(d), "saving case: disk full")
+	})
+}
+
+func TestEvalModel_Toast(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+	}
+
+	t.Run("confirms a copied case", func(t *testing.T) {
+		t.Parallel()
+
+		clipboard := &mock.Clipboard{CopyFn: func(string) error { return nil }}
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithClipboard(clipboard)), 120, 20)
+
+		require.NoError(t, d.Press("y"))
+		assert.Contains(t, statusBar(d), "case copied to clipboard")
+	})
+
+	t.Run("confirms a saved judgment", func(t *testing.T) {
+		t.Parallel()
+
+		store := &mock.JudgmentStore{SaveFn: func(string, []diffview.Judgment) error { return nil }}
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithJudgmentStore(store, "judgments.jsonl")), 120, 20)
+
+		require.NoError(t, d.Press("p"))
+		assert.Contains(t, statusBar(d), "judgment saved")
+	})
+}
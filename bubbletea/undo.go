@@ -0,0 +1,108 @@
+package bubbletea
+
+import (
+	"reflect"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fwojciec/diffstory"
+)
+
+// judgmentEdit is one change to a case's judgment, kept so it can be undone
+// and redone.
+type judgmentEdit struct {
+	caseID string
+	before *diffview.Judgment // nil if the case had no judgment
+	after  *diffview.Judgment
+}
+
+// history holds the judgment edits made this session, most recent last,
+// and those undone since, most recently undone last. Making a new edit
+// forgets the undone ones.
+type history struct {
+	done   []judgmentEdit
+	undone []judgmentEdit
+}
+
+// copyJudgment returns a copy of j, or nil if j is nil, that changes to j
+// don't reach.
+func copyJudgment(j *diffview.Judgment) *diffview.Judgment {
+	if j == nil {
+		return nil
+	}
+	c := *j
+	if j.Gold != nil {
+		gold := copyStory(*j.Gold)
+		c.Gold = &gold
+	}
+	return &c
+}
+
+// sameJudgment reports whether a and b say the same about a case, ignoring
+// when they were made.
+func sameJudgment(a, b *diffview.Judgment) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	x, y := *a, *b
+	x.JudgedAt = y.JudgedAt
+	return reflect.DeepEqual(x, y)
+}
+
+// recordEdit records that the judgment of caseID changed from before, a
+// copy taken beforehand, to what it is now, unless nothing changed.
+func (m *EvalModel) recordEdit(caseID string, before *diffview.Judgment) {
+	after := copyJudgment(m.judgments[caseID])
+	if sameJudgment(before, after) {
+		return
+	}
+	// Clip so copies of the model sharing the slices don't overwrite each
+	// other's edits
+	m.history.done = append(slices.Clip(m.history.done), judgmentEdit{caseID: caseID, before: before, after: after})
+	m.history.undone = nil
+}
+
+// undo reverts the most recent judgment edit, going to its case, and
+// returns the command that saves the judgments.
+func (m *EvalModel) undo() tea.Cmd {
+	n := len(m.history.done)
+	if n == 0 {
+		return notify("nothing to undo")
+	}
+	edit := m.history.done[n-1]
+	m.history.done = m.history.done[:n-1]
+	m.history.undone = append(slices.Clip(m.history.undone), edit)
+	return m.restoreJudgment(edit.caseID, edit.before)
+}
+
+// redo reapplies the most recently undone judgment edit, going to its case,
+// and returns the command that saves the judgments.
+func (m *EvalModel) redo() tea.Cmd {
+	n := len(m.history.undone)
+	if n == 0 {
+		return notify("nothing to redo")
+	}
+	edit := m.history.undone[n-1]
+	m.history.undone = m.history.undone[:n-1]
+	m.history.done = append(slices.Clip(m.history.done), edit)
+	return m.restoreJudgment(edit.caseID, edit.after)
+}
+
+// restoreJudgment sets the judgment of caseID to j, or removes it if j is
+// nil, and goes to the case unless it was deleted. Only the result is
+// saved, not the history.
+func (m *EvalModel) restoreJudgment(caseID string, j *diffview.Judgment) tea.Cmd {
+	if j == nil {
+		delete(m.judgments, caseID)
+	} else {
+		m.judgments[caseID] = copyJudgment(j)
+	}
+
+	idx := slices.IndexFunc(m.cases, func(c diffview.EvalCase) bool { return c.CaseID() == caseID })
+	if idx >= 0 && idx != m.currentIndex {
+		m.gotoCase(idx)
+	} else {
+		m.updateViewportContent()
+	}
+	return m.persistJudgments()
+}
@@ -0,0 +1,99 @@
+package bubbletea_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalModel_Undo(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case1", Commits: []diffview.CommitBrief{{Hash: "case1"}}}, Story: &diffview.StoryClassification{Summary: "Case 1"}},
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "case2", Commits: []diffview.CommitBrief{{Hash: "case2"}}}, Story: &diffview.StoryClassification{Summary: "Case 2"}},
+	}
+	newDriver := func() *bubbletea.Driver {
+		return bubbletea.NewDriver(bubbletea.NewEvalModel(cases), 120, 20)
+	}
+	judgments := func(d *bubbletea.Driver) []diffview.Judgment {
+		return d.Model().(bubbletea.EvalModel).Judgments()
+	}
+
+	t.Run("undoes and redoes a pass/fail toggle", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+		require.NoError(t, d.Press("p", "f"))
+
+		require.NoError(t, d.Press("ctrl+z"))
+		assert.Contains(t, d.Frame(), "● Pass  ○ Fail")
+
+		require.NoError(t, d.Press("ctrl+z"))
+		assert.Empty(t, judgments(d), "undoing the first judgment removes it")
+
+		require.NoError(t, d.Press("ctrl+y", "ctrl+y"))
+		assert.Contains(t, d.Frame(), "○ Pass  ● Fail")
+	})
+
+	t.Run("undoes a critique edit", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+		require.NoError(t, d.Press("f", "c"))
+		d.Type("wrong order")
+		require.NoError(t, d.Press("esc"))
+		require.Equal(t, "wrong order", judgments(d)[0].Critique)
+
+		require.NoError(t, d.Press("ctrl+z"))
+		got := judgments(d)
+		require.Len(t, got, 1)
+		assert.Empty(t, got[0].Critique)
+		assert.False(t, got[0].Pass)
+	})
+
+	t.Run("goes to the case the undone change was on", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+		require.NoError(t, d.Press("p", "n"))
+		require.Contains(t, d.Frame(), "Case 2")
+
+		require.NoError(t, d.Press("ctrl+z"))
+		assert.Contains(t, d.Frame(), "Case 1")
+		assert.Empty(t, judgments(d))
+	})
+
+	t.Run("a new change forgets undone ones", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDriver()
+		require.NoError(t, d.Press("p", "ctrl+z", "f", "ctrl+y"))
+		assert.Contains(t, d.Frame(), "○ Pass  ● Fail")
+		assert.Contains(t, statusBar(d), "nothing to redo")
+	})
+
+	t.Run("saves the state after undoing", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var saved []diffview.Judgment
+		store := &mock.JudgmentStore{SaveFn: func(_ string, judgments []diffview.Judgment) error {
+			mu.Lock()
+			defer mu.Unlock()
+			saved = judgments
+			return nil
+		}}
+		d := bubbletea.NewDriver(bubbletea.NewEvalModel(cases, bubbletea.WithJudgmentStore(store, "judgments.jsonl")), 120, 20)
+
+		require.NoError(t, d.Press("p", "ctrl+z"))
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Empty(t, saved)
+	})
+}
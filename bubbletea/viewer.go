@@ -2,15 +2,21 @@
 package bubbletea
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/fwojciec/diffstory"
+	"github.com/muesli/termenv"
 )
 
 // Compile-time interface verification.
@@ -25,15 +31,67 @@ type Model struct {
 	languageDetector diffview.LanguageDetector
 	tokenizer        diffview.Tokenizer
 	wordDiffer       diffview.WordDiffer
+	tabWidth         int // tab stop interval for line content (0 = default)
+	fileGroupBy      diffview.GroupBy
+	cache            *renderCache // tokens and hunks from earlier renders
 	viewport         viewport.Model
 	ready            bool
 	keymap           KeyMap
 	pendingKey       string
-	hunkPositions    []int // line numbers where each hunk starts
-	filePositions    []int // line numbers where each file starts
-	width            int   // terminal width for rendering
+	layout           diffview.Layout // rows of each commit header, file and hunk
+	width            int             // terminal width for rendering
+	conflict         bool            // diff contains combined (merge conflict) hunks
+	secrets          diffview.SecretDetector
+	secretCount      int // added lines secrets flags, shown in the status bar
+	annotators       []diffview.Annotator
+	symbols          diffview.SymbolResolver
+	grouper          diffview.HunkGrouper
+	groups           []diffview.HunkGroup // hunks touching the same symbols
+	group            int                  // index into groups that s/S last moved through
+	moveDetector     diffview.MoveDetector
+	moves            []diffview.Move       // blocks of lines diff moves
+	findings         []diffview.Annotation // annotators' notes on diff
+	hideFindings     bool                  // annotators' notes toggled off
+	details          debugPanel            // notes at the top of the view, in full
+	wrap             bool                  // soft-wrap long lines instead of scrolling horizontally
+	xOffset          int                   // content columns scrolled off to the left
+	structurer       diffview.StructuralDiffer
+	structured       map[string]diffview.FileDiff // line diffs of the files shown as key changes, by path
+	summarizer       diffview.DependencySummarizer
+	summarized       map[string]diffview.FileDiff // line diffs of the files shown as dependency changes, by path
+	expanded         map[string]bool              // dependency files switched back to their lines
+	collapsed        map[hunkKey]bool             // hunks collapsed by clicking their headers
+	selection        selection                    // rows dragged over with the mouse
+	finder           fileFinder
+	debug            debugPanel
+	editor           EditorFunc
+	editorRemote     bool // editor runs apart from the terminal, such as Neovim on a socket
+	pane             PaneFunc
+	scroll           scroller
+	idle             idleLock
+	notes            []diffview.Annotation
+	noteStore        diffview.AnnotationStore
+	notesPath        string
+	noteEditor       noteEditor
+	toast            toast
+	notice           string // why the last note wasn't saved, until the next key
+	embedded         bool   // part of another program, which decides when to quit
+	focus            *diffview.HunkRef
+	onQuit           func(QuitState)
+	statusSegments   []StatusSegment
 }
 
+// QuitState is where a Model stood when the reader quit.
+type QuitState struct {
+	Position  Position
+	Collapsed []diffview.HunkRef // hunks collapsed by clicking their headers, in order
+}
+
+// StatusSegment returns text for a segment of the status bar given where the
+// view stands, such as a review tool's count of files approved. An empty
+// string leaves the segment out.
+type StatusSegment func(pos Position) string
+
 // ModelOption configures a Model.
 type ModelOption func(*modelConfig)
 
@@ -43,6 +101,25 @@ type modelConfig struct {
 	languageDetector diffview.LanguageDetector
 	tokenizer        diffview.Tokenizer
 	wordDiffer       diffview.WordDiffer
+	tabWidth         int
+	fileGroupBy      diffview.GroupBy
+	idleTimeout      time.Duration
+	editor           EditorFunc
+	editorRemote     bool
+	pane             PaneFunc
+	scrolling        Scrolling
+	noteStore        diffview.AnnotationStore
+	notesPath        string
+	secrets          diffview.SecretDetector
+	annotators       []diffview.Annotator
+	symbols          diffview.SymbolResolver
+	grouper          diffview.HunkGrouper
+	moveDetector     diffview.MoveDetector
+	structurer       diffview.StructuralDiffer
+	summarizer       diffview.DependencySummarizer
+	focus            *diffview.HunkRef
+	onQuit           func(QuitState)
+	statusSegments   []StatusSegment
 }
 
 // WithRenderer sets a custom lipgloss renderer for the model.
@@ -82,6 +159,158 @@ func WithWordDiffer(d diffview.WordDiffer) ModelOption {
 	}
 }
 
+// WithTabWidth sets the tab stop interval used when expanding tabs in
+// line content. Defaults to 8.
+func WithTabWidth(n int) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.tabWidth = n
+	}
+}
+
+// WithFileGroups groups files by directory or package under headers with
+// their combined stats, which } and { move between. Diffs of several
+// commits are grouped by commit instead.
+func WithFileGroups(by diffview.GroupBy) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.fileGroupBy = by
+	}
+}
+
+// WithIdleTimeout blanks the diff after the given period without input,
+// until the next key press. Zero (the default) disables locking.
+func WithIdleTimeout(d time.Duration) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.idleTimeout = d
+	}
+}
+
+// WithEditor sets the command used to open the line at the top of the view
+// in an editor. Without one the key does nothing.
+func WithEditor(e EditorFunc) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.editor = e
+	}
+}
+
+// WithRemoteEditor sets the command used to send the line at the top of the
+// view to an editor already running apart from the viewer, such as Neovim
+// listening on a socket. The viewer keeps running while it's sent.
+func WithRemoteEditor(e EditorFunc) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.editor = e
+		cfg.editorRemote = true
+	}
+}
+
+// WithPane sets the command used to open the file, blame or log of the
+// line at the top of the view in a pane beside the viewer. Without one the
+// keys do nothing.
+func WithPane(p PaneFunc) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.pane = p
+	}
+}
+
+// WithScrolling sets how far the scrolling keys move the view. Defaults to
+// one row per step and half-page jumps without animation.
+func WithScrolling(s Scrolling) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.scrolling = s
+	}
+}
+
+// WithAnnotations enables writing review notes on the diff, recorded by
+// store at path. Notes already recorded there are shown alongside new ones.
+func WithAnnotations(store diffview.AnnotationStore, path string) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.noteStore = store
+		cfg.notesPath = path
+	}
+}
+
+// WithSecretDetector flags added lines that look like credentials with a
+// badge in the gutter, and counts them in the status bar.
+func WithSecretDetector(d diffview.SecretDetector) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.secrets = d
+	}
+}
+
+// WithAnnotators runs annotators over the diff, and again on each reload,
+// marking the lines they annotate with their icons and showing their notes
+// in the status bar alongside review notes.
+func WithAnnotators(annotators ...diffview.Annotator) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.annotators = append(cfg.annotators, annotators...)
+	}
+}
+
+// WithSymbolResolver names the declaration enclosing each hunk in its
+// header, resolving the diff's symbols now and on each reload.
+func WithSymbolResolver(r diffview.SymbolResolver) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.symbols = r
+	}
+}
+
+// WithHunkGrouper groups the hunks that touch the same symbols, now and on
+// each reload, for s and S to move between.
+func WithHunkGrouper(g diffview.HunkGrouper) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.grouper = g
+	}
+}
+
+// WithMoveDetector shows the lines diff moves, now and on each reload, in
+// the moved style on both sides, for m to jump between.
+func WithMoveDetector(d diffview.MoveDetector) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.moveDetector = d
+	}
+}
+
+// WithStructuralDiffer lets t switch the file in view between its line
+// diff and the keys d finds it changes.
+func WithStructuralDiffer(d diffview.StructuralDiffer) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.structurer = d
+	}
+}
+
+// WithInitialFocus opens the view at the header of the hunk ref names, or
+// of its file if the file has no such hunk. Files the diff doesn't show are
+// ignored.
+func WithInitialFocus(ref diffview.HunkRef) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.focus = &ref
+	}
+}
+
+// WithOnQuit sets a function called with where the view stood when the
+// reader quits, for programs that pick up from there.
+func WithOnQuit(fn func(QuitState)) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.onQuit = fn
+	}
+}
+
+// WithStatusSegments adds segments to the status bar, left of the file and
+// hunk positions, in order.
+func WithStatusSegments(segments ...StatusSegment) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.statusSegments = append(cfg.statusSegments, segments...)
+	}
+}
+
+// WithDependencySummarizer shows the manifests and lockfiles s reads as
+// the dependencies they add, remove, upgrade and downgrade; t switches one
+// back to its lines.
+func WithDependencySummarizer(s diffview.DependencySummarizer) ModelOption {
+	return func(cfg *modelConfig) {
+		cfg.summarizer = s
+	}
+}
+
 // NewModel creates a new Model with the given diff.
 // Use WithTheme to set a custom theme, otherwise uses hardcoded defaults.
 func NewModel(diff *diffview.Diff, opts ...ModelOption) Model {
@@ -100,21 +329,57 @@ func NewModel(diff *diffview.Diff, opts ...ModelOption) Model {
 		palette = defaultPalette()
 	}
 
-	// Compute positions eagerly - they don't depend on terminal width
-	hunkPositions, filePositions := computePositions(diff)
+	if cfg.symbols != nil && diff != nil {
+		cfg.symbols.ResolveSymbols(diff)
+	}
 
-	return Model{
-		diff:             diff,
+	var notes []diffview.Annotation
+	if cfg.noteStore != nil {
+		// Best-effort: unreadable notes leave only this session's to show
+		notes, _ = cfg.noteStore.Load(cfg.notesPath)
+	}
+
+	m := Model{
+		cache:            newRenderCache(),
 		styles:           styles,
 		palette:          palette,
 		renderer:         cfg.renderer,
 		languageDetector: cfg.languageDetector,
 		tokenizer:        cfg.tokenizer,
 		wordDiffer:       cfg.wordDiffer,
+		tabWidth:         cfg.tabWidth,
+		fileGroupBy:      cfg.fileGroupBy,
 		keymap:           DefaultKeyMap(),
-		hunkPositions:    hunkPositions,
-		filePositions:    filePositions,
+		secrets:          cfg.secrets,
+		annotators:       cfg.annotators,
+		symbols:          cfg.symbols,
+		grouper:          cfg.grouper,
+		moveDetector:     cfg.moveDetector,
+		structurer:       cfg.structurer,
+		summarizer:       cfg.summarizer,
+		focus:            cfg.focus,
+		onQuit:           cfg.onQuit,
+		statusSegments:   cfg.statusSegments,
+		editor:           cfg.editor,
+		editorRemote:     cfg.editorRemote,
+		pane:             cfg.pane,
+		scroll:           scroller{Scrolling: cfg.scrolling},
+		idle:             newIdleLock(cfg.idleTimeout),
+		notes:            notes,
+		noteStore:        cfg.noteStore,
+		notesPath:        cfg.notesPath,
 	}
+	// Everything worked out from the diff sees lockfiles as they're shown
+	m.diff = m.summarizeDependencies(diff)
+	m.conflict = hasCombinedHunks(m.diff)
+	m.secretCount = countSecrets(m.diff, m.secrets)
+	m.groups = groupHunks(m.diff, m.grouper)
+	m.moves = detectMoves(m.diff, m.moveDetector)
+	m.findings = runAnnotators(m.diff, m.annotators)
+	// Compute positions eagerly - they don't depend on terminal width
+	// until lines are wrapped
+	m.updatePositions()
+	return m
 }
 
 // defaultStyles returns the default dark theme styles (GitHub-inspired).
@@ -161,6 +426,32 @@ func defaultStyles() diffview.Styles {
 			Foreground: "#e6edf3", // Same as code line foreground (neutral)
 			Background: "#5f2728", // Same as gutter (35% blend)
 		},
+		Ours: diffview.ColorPair{
+			Foreground: "#e6edf3", // Normal text (neutral)
+			Background: "#1c2e45", // Blue background (20% blend of #58a6ff with #0d1117)
+		},
+		Theirs: diffview.ColorPair{
+			Foreground: "#e6edf3", // Normal text (neutral)
+			Background: "#342c19", // Yellow background (20% blend of #d29922 with #0d1117)
+		},
+		Moved: diffview.ColorPair{
+			Foreground: "#e6edf3", // Normal text (neutral)
+			Background: "#2a2739", // Purple background (15% blend of #d2a8ff with #0d1117)
+		},
+		SectionBanner: diffview.ColorPair{
+			Foreground: "#e6edf3", // Normal text
+			Background: "#161b22", // Elevated surface (UIBackground)
+		},
+		SectionRoles: map[string]diffview.ColorPair{
+			"problem":    {Foreground: "#f85149", Background: "#161b22"}, // Deleted red
+			"fix":        {Foreground: "#3fb950", Background: "#161b22"}, // Added green
+			"core":       {Foreground: "#58a6ff", Background: "#161b22"}, // UIAccent blue
+			"test":       {Foreground: "#d2a8ff", Background: "#161b22"}, // Function purple
+			"interface":  {Foreground: "#ffa657", Background: "#161b22"}, // Type orange
+			"pattern":    {Foreground: "#ff7b72", Background: "#161b22"}, // Keyword red
+			"supporting": {Foreground: "#d29922", Background: "#161b22"}, // Modified yellow
+			"cleanup":    {Foreground: "#8b949e", Background: "#161b22"}, // Comment muted
+		},
 	}
 }
 
@@ -197,7 +488,7 @@ func defaultPalette() diffview.Palette {
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
-	return nil
+	return m.idle.start()
 }
 
 // newStyle creates a new lipgloss style using the model's renderer.
@@ -209,10 +500,77 @@ func (m Model) newStyle() lipgloss.Style {
 	return lipgloss.NewStyle()
 }
 
+// errorStyle returns the status bar style for error toasts.
+func (m Model) errorStyle() lipgloss.Style {
+	return m.newStyle().
+		Background(lipgloss.Color(m.palette.UIBackground)).
+		Foreground(lipgloss.Color(m.palette.Deleted)).
+		Bold(true)
+}
+
 // Update implements tea.Model.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case idleCheckMsg:
+		return m, m.idle.check()
+	case scrollFrameMsg:
+		return m, m.scroll.frame(&m.viewport)
+	case ReloadMsg:
+		m.reload(msg.Diff)
+		return m, nil
+	case ToastMsg:
+		return m, m.toast.show(msg)
+	case toastExpiredMsg:
+		m.toast.expire(msg)
+		return m, nil
+	case editorClosedMsg:
+		// Time spent in the editor counts as activity
+		_, cmd := m.idle.touch()
+		return m, cmd
+	case tea.MouseMsg:
+		if dismissed, cmd := m.idle.touch(); dismissed {
+			return m, cmd
+		}
+		// Rows count from the top of the viewport, below any banner
+		if msg.Y -= m.skippedBannerHeight(); msg.Y < 0 {
+			return m, nil
+		}
+		if m.handleMouse(msg) {
+			return m, nil
+		}
 	case tea.KeyMsg:
+		// The key that dismisses the lock screen isn't acted on
+		if dismissed, cmd := m.idle.touch(); dismissed {
+			return m, cmd
+		}
+
+		m.scroll.settle(&m.viewport)
+
+		// The finder overlay takes all keys while open
+		if m.finder.active {
+			if done, picked := m.finder.update(msg); done && picked >= 0 {
+				m.viewport.SetYOffset(m.layout.Files[picked])
+			}
+			return m, nil
+		}
+		if m.debug.active {
+			m.debug.update(msg, m.keymap.Debug)
+			return m, nil
+		}
+		if m.details.active {
+			m.details.update(msg, m.keymap.Details)
+			return m, nil
+		}
+		// The note prompt takes all keys while open
+		if m.noteEditor.active {
+			if done, save := m.noteEditor.update(msg); done && save {
+				m.addNote()
+			}
+			return m, nil
+		}
+		m.notice = ""
+		m.selection = selection{}
+
 		// Handle multi-key sequences (gg for go to top)
 		if m.pendingKey == "g" && key.Matches(msg, m.keymap.GotoTop) {
 			m.viewport.GotoTop()
@@ -231,54 +589,123 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch {
 		case key.Matches(msg, m.keymap.Quit):
+			if m.embedded {
+				return m, nil
+			}
+			if m.onQuit != nil {
+				m.onQuit(m.quitState())
+			}
 			return m, tea.Quit
 		case key.Matches(msg, m.keymap.GotoBottom):
 			m.viewport.GotoBottom()
 			return m, nil
 		case key.Matches(msg, m.keymap.HalfPageUp):
-			m.viewport.HalfPageUp()
-			return m, nil
+			return m, m.scroll.page(&m.viewport, -1)
 		case key.Matches(msg, m.keymap.HalfPageDown):
-			m.viewport.HalfPageDown()
-			return m, nil
+			return m, m.scroll.page(&m.viewport, 1)
 		case key.Matches(msg, m.keymap.Up):
-			m.viewport.ScrollUp(1)
+			m.scroll.lines(&m.viewport, -1)
 			return m, nil
 		case key.Matches(msg, m.keymap.Down):
-			m.viewport.ScrollDown(1)
+			m.scroll.lines(&m.viewport, 1)
 			return m, nil
 		case key.Matches(msg, m.keymap.NextHunk):
-			m.gotoNextPosition(m.hunkPositions)
+			m.gotoNextPosition(m.layout.Hunks)
 			return m, nil
 		case key.Matches(msg, m.keymap.PrevHunk):
-			m.gotoPrevPosition(m.hunkPositions)
+			m.gotoPrevPosition(m.layout.Hunks)
 			return m, nil
 		case key.Matches(msg, m.keymap.NextFile):
-			m.gotoNextPosition(m.filePositions)
+			m.gotoNextPosition(m.layout.Files)
 			return m, nil
 		case key.Matches(msg, m.keymap.PrevFile):
-			m.gotoPrevPosition(m.filePositions)
+			m.gotoPrevPosition(m.layout.Files)
+			return m, nil
+		case key.Matches(msg, m.keymap.NextCommit):
+			m.gotoNextPosition(m.commitOrGroupPositions())
+			return m, nil
+		case key.Matches(msg, m.keymap.PrevCommit):
+			m.gotoPrevPosition(m.commitOrGroupPositions())
+			return m, nil
+		case key.Matches(msg, m.keymap.NextRelated):
+			m.gotoRelated(1)
+			return m, nil
+		case key.Matches(msg, m.keymap.PrevRelated):
+			m.gotoRelated(-1)
+			return m, nil
+		case key.Matches(msg, m.keymap.FollowMove):
+			m.followMove()
+			return m, nil
+		case key.Matches(msg, m.keymap.Structure):
+			m.toggleStructure()
+			return m, nil
+		case key.Matches(msg, m.keymap.ToggleWrap):
+			m.toggleWrap()
+			return m, nil
+		case key.Matches(msg, m.keymap.ScrollLeft):
+			m.scrollHorizontal(-horizontalScrollStep)
+			return m, nil
+		case key.Matches(msg, m.keymap.ScrollRight):
+			m.scrollHorizontal(horizontalScrollStep)
+			return m, nil
+		case key.Matches(msg, m.keymap.FindFile):
+			m.finder = newFileFinder(renderedFilePaths(m.diff))
+			return m, nil
+		case key.Matches(msg, m.keymap.OpenEditor):
+			_, layout := renderDiffLayout(m.diffConfig())
+			return m, openInEditor(m.editor, m.editorRemote, layout, m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.PaneFile):
+			_, layout := renderDiffLayout(m.diffConfig())
+			return m, openInPane(m.pane, PaneFile, layout, m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.PaneBlame):
+			_, layout := renderDiffLayout(m.diffConfig())
+			return m, openInPane(m.pane, PaneBlame, layout, m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.PaneLog):
+			_, layout := renderDiffLayout(m.diffConfig())
+			return m, openInPane(m.pane, PaneLog, layout, m.viewport.YOffset)
+		case key.Matches(msg, m.keymap.Annotate):
+			m.startNote()
+			return m, nil
+		case key.Matches(msg, m.keymap.Details):
+			m.details = newTextPanel(annotationDetails(m.notesInView(), m.visibleFindings()), m.viewport.Width, m.viewport.Height)
+			return m, nil
+		case key.Matches(msg, m.keymap.ToggleNotes):
+			m.toggleFindings()
+			return m, nil
+		case key.Matches(msg, m.keymap.Debug):
+			m.debug = newDebugPanel(m.diffConfig(), m.viewport.Width, m.viewport.Height)
 			return m, nil
 		}
 	case tea.WindowSizeMsg:
-		statusBarHeight := 1
+		// The status bar, and the skipped content banner if any
+		chromeHeight := 1 + m.skippedBannerHeight()
 		widthChanged := m.width != msg.Width
 		m.width = msg.Width
 
 		if !m.ready {
 			// First render - create viewport and render content
-			m.viewport = viewport.New(msg.Width, msg.Height-statusBarHeight)
+			m.viewport = viewport.New(msg.Width, msg.Height-chromeHeight)
+			m.updatePositions()
 			m.viewport.SetContent(m.renderContent())
+			if m.focus != nil {
+				if row, ok := m.focusRow(*m.focus); ok {
+					m.viewport.SetYOffset(row)
+				}
+			}
 			m.ready = true
 		} else if widthChanged {
 			// Width changed - re-render content
 			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - statusBarHeight
+			m.viewport.Height = msg.Height - chromeHeight
+			m.xOffset = clampXOffset(m.xOffset, maxXOffset(m.diff, m.width, m.tabWidth))
+			m.updatePositions()
 			m.viewport.SetContent(m.renderContent())
 		} else {
 			// Only height changed
-			m.viewport.Height = msg.Height - statusBarHeight
+			m.viewport.Height = msg.Height - chromeHeight
 		}
+		m.debug.resize(m.viewport.Width, m.viewport.Height)
+		m.details.resize(m.viewport.Width, m.viewport.Height)
 	}
 
 	var cmd tea.Cmd
@@ -291,12 +718,41 @@ func (m Model) View() string {
 	if !m.ready {
 		return "Loading..."
 	}
-	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), m.statusBarView())
+	if m.idle.locked {
+		height := m.viewport.Height + 1 + m.skippedBannerHeight()
+		return renderLockScreen(m.width, height, m.newStyle().Foreground(lipgloss.Color(m.palette.Context)))
+	}
+	if m.finder.active {
+		return m.frame(m.finder.view(m.width, m.viewport.Height, m.finderStyles()))
+	}
+	if m.debug.active {
+		return m.frame(m.debug.viewport.View())
+	}
+	if m.details.active {
+		return m.frame(m.details.viewport.View())
+	}
+	return m.frame(highlightSelection(m.viewport.View(), m.viewport.YOffset, m.selection, m.newStyle().Reverse(true)))
+}
+
+// finderStyles returns the file finder styles for the model's palette.
+func (m Model) finderStyles() finderStyles {
+	return finderStyles{
+		prompt:   m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)).Bold(true),
+		item:     m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)),
+		selected: m.newStyle().Foreground(lipgloss.Color(m.palette.Foreground)).Background(lipgloss.Color(m.palette.UIBackground)),
+		match:    m.newStyle().Foreground(lipgloss.Color(m.palette.UIAccent)).Bold(true),
+		count:    m.newStyle().Foreground(lipgloss.Color(m.palette.Context)),
+	}
 }
 
 // renderContent renders the diff content with current model configuration.
 func (m Model) renderContent() string {
-	return renderDiff(renderConfig{
+	return renderDiff(m.diffConfig())
+}
+
+// diffConfig returns the render configuration for the current model state.
+func (m Model) diffConfig() renderConfig {
+	return renderConfig{
 		diff:             m.diff,
 		styles:           m.styles,
 		renderer:         m.renderer,
@@ -304,7 +760,165 @@ func (m Model) renderContent() string {
 		languageDetector: m.languageDetector,
 		tokenizer:        m.tokenizer,
 		wordDiffer:       m.wordDiffer,
-	})
+		tabWidth:         m.tabWidth,
+		wrap:             m.wrap,
+		xOffset:          m.xOffset,
+		secrets:          m.secrets,
+		lineIcons:        annotationIcons(m.visibleFindings()),
+		fileBadges:       annotationBadges(m.visibleFindings()),
+		moves:            m.moves,
+		structured:       structuredPaths(m.structured),
+		summarized:       structuredPaths(m.summarized),
+		collapsedHunks:   m.collapsed,
+		fileGroups:       diffview.GroupFiles(m.diff, m.fileGroupBy),
+		cache:            m.cache,
+	}
+}
+
+// reload swaps in a new diff, keeping the same source line at the top of the
+// view. An open file finder lists the new diff's files.
+func (m *Model) reload(diff *diffview.Diff) {
+	var old diffLayout
+	if m.ready {
+		_, old = renderDiffLayout(m.diffConfig())
+	}
+
+	if m.symbols != nil {
+		m.symbols.ResolveSymbols(diff)
+	}
+	bannerHeight := m.skippedBannerHeight()
+	m.diff = m.showStructure(m.summarizeDependencies(diff))
+	if m.ready {
+		// The viewport gives up or takes back the banner's rows
+		m.viewport.Height += bannerHeight - m.skippedBannerHeight()
+		m.debug.resize(m.viewport.Width, m.viewport.Height)
+		m.details.resize(m.viewport.Width, m.viewport.Height)
+	}
+	m.groups = groupHunks(m.diff, m.grouper)
+	m.moves = detectMoves(m.diff, m.moveDetector)
+	m.conflict = hasCombinedHunks(m.diff)
+	m.secretCount = countSecrets(m.diff, m.secrets)
+	m.findings = runAnnotators(m.diff, m.annotators)
+	m.xOffset = clampXOffset(m.xOffset, maxXOffset(m.diff, m.width, m.tabWidth))
+	m.updatePositions()
+	if m.finder.active {
+		m.finder.files = renderedFilePaths(diff)
+		m.finder.filter()
+	}
+	if !m.ready {
+		return
+	}
+
+	content, layout := renderDiffLayout(m.diffConfig())
+	offset := m.viewport.YOffset
+	m.viewport.SetContent(content)
+	m.viewport.SetYOffset(reanchor(offset, old, layout))
+}
+
+// updatePositions recomputes hunk and file positions. Wrapped lines take
+// several rows, so positions depend on the terminal width while wrapping.
+func (m *Model) updatePositions() {
+	m.layout = m.diffConfig().layout()
+}
+
+// toggleWrap switches between soft-wrapped lines and horizontal scrolling,
+// keeping the current hunk in view.
+func (m *Model) toggleWrap() {
+	hunk, _ := m.currentHunkPosition()
+	atTop := m.viewport.AtTop()
+
+	m.wrap = !m.wrap
+	m.xOffset = 0
+	m.updatePositions()
+	m.viewport.SetContent(m.renderContent())
+
+	if !atTop && hunk > 0 {
+		m.viewport.SetYOffset(m.layout.Hunks[hunk-1])
+	}
+}
+
+// scrollHorizontal scrolls line content by delta columns. The line number
+// gutter and diff prefix stay in place. Has no effect while wrapping.
+func (m *Model) scrollHorizontal(delta int) {
+	if m.wrap {
+		return
+	}
+	offset := clampXOffset(m.xOffset+delta, maxXOffset(m.diff, m.width, m.tabWidth))
+	if offset == m.xOffset {
+		return
+	}
+	m.xOffset = offset
+	m.viewport.SetContent(m.renderContent())
+}
+
+// startNote opens the note prompt for the line at the top of the view. Does
+// nothing unless notes are enabled.
+func (m *Model) startNote() {
+	if m.noteStore == nil {
+		return
+	}
+	_, layout := renderDiffLayout(m.diffConfig())
+	src, ok := layout.sourceAt(m.viewport.YOffset)
+	if !ok {
+		return
+	}
+	m.noteEditor = newNoteEditor(noteTargets(m.diff, src))
+}
+
+// addNote records the note written in the prompt.
+func (m *Model) addNote() {
+	note := m.noteEditor.target()
+	note.Text = m.noteEditor.text
+	note.CreatedAt = time.Now()
+	m.notes = append(m.notes, note)
+	if err := m.noteStore.Append(m.notesPath, note); err != nil {
+		m.notice = "note not saved: " + err.Error()
+	}
+}
+
+// notesInView returns the notes on the file at the top of the view and on
+// the hunk there.
+func (m Model) notesInView() []diffview.Annotation {
+	if len(m.notes) == 0 && len(m.findings) == 0 {
+		return nil
+	}
+	fileIdx, _ := m.currentFilePosition()
+	files := renderedFilePaths(m.diff)
+	if fileIdx < 1 || fileIdx > len(files) {
+		return nil
+	}
+	path := files[fileIdx-1]
+	hunkIdx, _ := m.currentHunkPosition()
+	// Above a file's first hunk, the current hunk is the previous file's
+	hunkPath, hunk, ok := renderedHunk(m.diff, hunkIdx)
+	if !ok || hunkPath != path || m.viewport.YOffset < m.layout.Hunks[hunkIdx-1] {
+		hunk = 0
+	}
+	return append(notesOn(m.notes, path, hunk), notesOn(m.visibleFindings(), path, hunk)...)
+}
+
+// visibleFindings returns the annotators' notes, or none while they're
+// toggled off.
+func (m Model) visibleFindings() []diffview.Annotation {
+	if m.hideFindings {
+		return nil
+	}
+	return m.findings
+}
+
+// toggleFindings shows or hides the annotators' notes and their marks.
+func (m *Model) toggleFindings() {
+	if len(m.annotators) == 0 {
+		return
+	}
+	m.hideFindings = !m.hideFindings
+	if m.ready {
+		m.viewport.SetContent(m.renderContent())
+	}
+	m.notice = fmt.Sprintf("annotator notes shown (%d)", len(m.findings))
+	if m.hideFindings {
+		m.notice = "annotator notes hidden"
+	}
 }
 
 // statusBarView renders the status bar with position info.
@@ -322,6 +936,22 @@ func (m Model) statusBarView() string {
 		Background(lipgloss.Color(m.palette.UIBackground)).
 		Foreground(lipgloss.Color(m.palette.UIForeground))
 
+	// The note prompt takes the whole bar, keeping the end of the text in view
+	if m.noteEditor.active {
+		label := "note on " + m.noteEditor.target().Location()
+		if len(m.noteEditor.targets) > 1 {
+			label += " (tab: widen)"
+		}
+		prompt := label + ": " + m.noteEditor.text + "█"
+		if over := lipgloss.Width(prompt) - m.width; over > 0 && m.width > 0 {
+			prompt = ansi.TruncateLeft(prompt, over+1, "…")
+		}
+		if pad := m.width - lipgloss.Width(prompt); pad > 0 {
+			prompt += strings.Repeat(" ", pad)
+		}
+		return barStyle.Render(prompt)
+	}
+
 	// Format position info with fixed widths
 	fileIdx, fileTotal := m.currentFilePosition()
 	hunkIdx, hunkTotal := m.currentHunkPosition()
@@ -331,22 +961,78 @@ func (m Model) statusBarView() string {
 	hunkWidth := digitWidth(hunkTotal)
 
 	filePos := fmt.Sprintf("file %*d/%-*d", fileWidth, fileIdx, fileWidth, fileTotal)
+
 	hunkPos := fmt.Sprintf("hunk %*d/%-*d", hunkWidth, hunkIdx, hunkWidth, hunkTotal)
 	scrollPos := m.scrollPosition()
 
 	// Build status bar with separators
 	sep := sepStyle.Render(" │ ")
-	content := barStyle.Render(filePos) + sep +
+	content := ""
+	if m.conflict {
+		// Legend for merge conflict view: colored swatches for each side
+		oursStyle := m.newStyle().
+			Background(lipgloss.Color(m.styles.Ours.Background)).
+			Foreground(lipgloss.Color(m.styles.Ours.Foreground))
+		theirsStyle := m.newStyle().
+			Background(lipgloss.Color(m.styles.Theirs.Background)).
+			Foreground(lipgloss.Color(m.styles.Theirs.Foreground))
+		content += barStyle.Render("merge ") + oursStyle.Render(" ours ") +
+			barStyle.Render(" ") + theirsStyle.Render(" theirs ") + sep
+	}
+	if m.secretCount > 0 {
+		warnStyle := m.newStyle().
+			Background(lipgloss.Color(m.palette.UIBackground)).
+			Foreground(lipgloss.Color(m.palette.Modified))
+		label := "possible secrets"
+		if m.secretCount == 1 {
+			label = "possible secret"
+		}
+		content += warnStyle.Render(fmt.Sprintf("%s %d %s", secretBadge, m.secretCount, label)) + sep
+	}
+	if commitIdx, commitTotal := m.currentCommitPosition(); commitTotal > 0 {
+		commitWidth := digitWidth(commitTotal)
+		content += barStyle.Render(fmt.Sprintf("commit %*d/%-*d", commitWidth, commitIdx, commitWidth, commitTotal)) + sep
+	}
+	if len(m.statusSegments) > 0 {
+		pos := m.Position()
+		for _, segment := range m.statusSegments {
+			if text := segment(pos); text != "" {
+				content += barStyle.Render(text) + sep
+			}
+		}
+	}
+	if groupIdx, groupTotal := m.currentGroupPosition(); groupTotal > 0 {
+		groupWidth := digitWidth(groupTotal)
+		content += barStyle.Render(fmt.Sprintf("group %*d/%-*d", groupWidth, groupIdx, groupWidth, groupTotal)) + sep
+	}
+	hints := dimStyle.Render("j/k:scroll  n/N:hunk  ]/[:file  w:wrap  q:quit")
+	if notes := m.notesInView(); len(notes) > 0 {
+		text := noteLabel(notes[0])
+		if len(notes) > 1 {
+			text += fmt.Sprintf(" (+%d)", len(notes)-1)
+		}
+		hints = barStyle.Render(text)
+	}
+	if t := m.toast.render(barStyle, m.errorStyle()); t != "" {
+		hints = t
+	}
+	if m.notice != "" {
+		hints = barStyle.Render(m.notice)
+	}
+	content += barStyle.Render(filePos) + sep +
 		barStyle.Render(hunkPos) + sep +
 		barStyle.Render(scrollPos) + sep +
-		dimStyle.Render("j/k:scroll  n/N:hunk  ]/[:file  q:quit") +
+		hints +
 		barStyle.Render("  ") // Right padding
 
-	// Right-align by padding left side with background
+	// Right-align by padding left side with background, or cut what doesn't
+	// fit, such as the hints in a narrow component
 	contentWidth := lipgloss.Width(content)
 	if m.width > contentWidth {
 		padding := barStyle.Render(strings.Repeat(" ", m.width-contentWidth))
 		content = padding + content
+	} else if m.width > 0 && contentWidth > m.width {
+		content = ansi.Truncate(content, m.width, "")
 	}
 
 	return content
@@ -367,56 +1053,93 @@ func (m Model) scrollPosition() string {
 
 // currentFilePosition returns the current file index (1-based) and total file count.
 func (m Model) currentFilePosition() (current, total int) {
-	total = len(m.filePositions)
-	if total == 0 {
-		return 0, 0
-	}
+	return diffview.Position(m.layout.Files, m.viewport.YOffset)
+}
 
-	currentLine := m.viewport.YOffset
-	current = 1 // Default to first file
+// currentCommitPosition returns the current commit index (1-based) and total
+// commit count, which is zero unless the diff spans several commits.
+func (m Model) currentCommitPosition() (current, total int) {
+	return diffview.Position(m.layout.Commits, m.viewport.YOffset)
+}
 
-	// Find which file we're currently in
-	for i, pos := range m.filePositions {
-		if pos <= currentLine {
-			current = i + 1 // 1-based index
-		} else {
-			break
-		}
-	}
+// currentGroupPosition returns the current file group index (1-based) and
+// total group count, which is zero unless files are grouped.
+func (m Model) currentGroupPosition() (current, total int) {
+	return diffview.Position(m.layout.Groups, m.viewport.YOffset)
+}
 
-	return current, total
+// commitOrGroupPositions returns the rows } and { move between: commit
+// headers, or file group headers for a diff of a single commit.
+func (m Model) commitOrGroupPositions() []int {
+	if len(m.layout.Commits) > 0 {
+		return m.layout.Commits
+	}
+	return m.layout.Groups
 }
 
 // currentHunkPosition returns the current hunk index (1-based) and total hunk count.
 func (m Model) currentHunkPosition() (current, total int) {
-	total = len(m.hunkPositions)
-	if total == 0 {
-		return 0, 0
-	}
-
-	currentLine := m.viewport.YOffset
-	current = 1 // Default to first hunk
-
-	// Find which hunk we're currently in
-	for i, pos := range m.hunkPositions {
-		if pos <= currentLine {
-			current = i + 1 // 1-based index
-		} else {
-			break
-		}
-	}
+	return diffview.Position(m.layout.Hunks, m.viewport.YOffset)
+}
 
-	return current, total
+// ConflictMode reports whether the model is showing a merge conflict
+// (combined diff), with lines colored by the side they came from.
+func (m Model) ConflictMode() bool {
+	return m.conflict
 }
 
 // HunkPositions returns the line numbers where each hunk starts.
 func (m Model) HunkPositions() []int {
-	return m.hunkPositions
+	return m.layout.Hunks
 }
 
 // FilePositions returns the line numbers where each file starts.
 func (m Model) FilePositions() []int {
-	return m.filePositions
+	return m.layout.Files
+}
+
+// Position returns where the view stands in the diff.
+func (m Model) Position() Position {
+	pos := Position{Line: m.viewport.YOffset}
+	pos.File, pos.Files = m.currentFilePosition()
+	pos.Hunk, pos.Hunks = m.currentHunkPosition()
+	pos.Commit, pos.Commits = m.currentCommitPosition()
+	pos.Group, pos.Groups = m.currentGroupPosition()
+	return pos
+}
+
+// quitState returns where the view stands, for the quit callback.
+func (m Model) quitState() QuitState {
+	state := QuitState{Position: m.Position()}
+	for k, collapsed := range m.collapsed {
+		if collapsed {
+			state.Collapsed = append(state.Collapsed, diffview.HunkRef{File: k.file, HunkIndex: k.hunkIndex})
+		}
+	}
+	slices.SortFunc(state.Collapsed, func(a, b diffview.HunkRef) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.HunkIndex, b.HunkIndex))
+	})
+	return state
+}
+
+// focusRow returns the row of the header of the hunk ref names, or of its
+// file if the file has no such hunk. Reports false if the diff doesn't
+// show the file.
+func (m Model) focusRow(ref diffview.HunkRef) (int, bool) {
+	cfg := m.diffConfig()
+	want := cfg.keyOf(ref.File, ref.HunkIndex)
+	paths := renderedFilePaths(m.diff)
+	hunks := 0
+	for i, keys := range cfg.fileKeys() {
+		if paths[i] == ref.File {
+			if j := slices.Index(keys, want); j >= 0 {
+				return m.layout.Hunks[hunks+j], true
+			}
+			return m.layout.Files[i], true
+		}
+		hunks += len(keys)
+	}
+	return 0, false
 }
 
 // gotoNextPosition scrolls to the next position.
@@ -478,9 +1201,54 @@ type Viewer struct {
 	languageDetector diffview.LanguageDetector
 	tokenizer        diffview.Tokenizer
 	wordDiffer       diffview.WordDiffer
+	tabWidth         int
+	fileGroupBy      diffview.GroupBy
+	idleTimeout      time.Duration
+	editor           EditorFunc
+	editorRemote     bool
+	pane             PaneFunc
+	scrolling        Scrolling
+	noAltScreen      bool
+	oneScreen        *oneScreen
+	print            *printTarget
+	script           *scriptTarget
+	reloads          <-chan *diffview.Diff
+	noteStore        diffview.AnnotationStore
+	notesPath        string
+	secrets          diffview.SecretDetector
+	annotators       []diffview.Annotator
+	symbols          diffview.SymbolResolver
+	grouper          diffview.HunkGrouper
+	moveDetector     diffview.MoveDetector
+	structurer       diffview.StructuralDiffer
+	summarizer       diffview.DependencySummarizer
+	focus            *diffview.HunkRef
+	onQuit           func(QuitState)
+	statusSegments   []StatusSegment
 	programOpts      []tea.ProgramOption
 }
 
+// oneScreen is where to print diffs that fit on a single screen instead of
+// opening the viewer.
+type oneScreen struct {
+	out           io.Writer
+	width, height int
+}
+
+// printTarget is where to print diffs instead of opening the viewer.
+type printTarget struct {
+	out     io.Writer
+	width   int
+	profile termenv.Profile
+}
+
+// scriptTarget is the script to drive the viewer through instead of a
+// terminal, and where to record its screens.
+type scriptTarget struct {
+	script *Script
+	out    io.Writer
+}
+
 // ViewerOption configures a Viewer.
 type ViewerOption func(*Viewer)
 
@@ -513,6 +1281,199 @@ func WithViewerWordDiffer(d diffview.WordDiffer) ViewerOption {
 	}
 }
 
+// WithViewerTabWidth sets the tab stop interval for line content.
+func WithViewerTabWidth(n int) ViewerOption {
+	return func(v *Viewer) {
+		v.tabWidth = n
+	}
+}
+
+// WithViewerFileGroups groups files by directory or package.
+func WithViewerFileGroups(by diffview.GroupBy) ViewerOption {
+	return func(v *Viewer) {
+		v.fileGroupBy = by
+	}
+}
+
+// WithViewerIdleTimeout blanks the diff after the given period without input.
+func WithViewerIdleTimeout(d time.Duration) ViewerOption {
+	return func(v *Viewer) {
+		v.idleTimeout = d
+	}
+}
+
+// WithViewerEditor sets the command used to open the line at the top of the
+// view in an editor.
+func WithViewerEditor(e EditorFunc) ViewerOption {
+	return func(v *Viewer) {
+		v.editor = e
+	}
+}
+
+// WithViewerRemoteEditor sets the command used to send the line at the top
+// of the view to an editor already running apart from the viewer.
+func WithViewerRemoteEditor(e EditorFunc) ViewerOption {
+	return func(v *Viewer) {
+		v.editor = e
+		v.editorRemote = true
+	}
+}
+
+// WithViewerPane sets the command used to open the file, blame or log of
+// the line at the top of the view in a pane beside the viewer.
+func WithViewerPane(p PaneFunc) ViewerOption {
+	return func(v *Viewer) {
+		v.pane = p
+	}
+}
+
+// WithViewerScrolling sets how far the scrolling keys move the view.
+func WithViewerScrolling(s Scrolling) ViewerOption {
+	return func(v *Viewer) {
+		v.scrolling = s
+	}
+}
+
+// WithViewerNoAltScreen runs the viewer in the main screen instead of the
+// alternate screen, so the last view stays in the scrollback after quitting.
+func WithViewerNoAltScreen() ViewerOption {
+	return func(v *Viewer) {
+		v.noAltScreen = true
+	}
+}
+
+// WithViewerQuitIfOneScreen prints diffs that fit in a width × height
+// terminal to out and returns, like less -F, instead of opening the viewer.
+func WithViewerQuitIfOneScreen(out io.Writer, width, height int) ViewerOption {
+	return func(v *Viewer) {
+		v.oneScreen = &oneScreen{out: out, width: width, height: height}
+	}
+}
+
+// WithViewerScript drives the viewer through script without a terminal,
+// writing the screens it snapshots to out, and returns instead of opening
+// the viewer.
+func WithViewerScript(script *Script, out io.Writer) ViewerOption {
+	return func(v *Viewer) {
+		v.script = &scriptTarget{script: script, out: out}
+	}
+}
+
+// WithViewerPrint prints the whole diff to out and returns instead of opening
+// the viewer, for CI logs, less -R, or use as git's core.pager. Long lines
+// wrap at width since there is no scrolling sideways. Colors are written as
+// ANSI escapes for profile, and not at all for termenv.Ascii.
+func WithViewerPrint(out io.Writer, width int, profile termenv.Profile) ViewerOption {
+	return func(v *Viewer) {
+		v.print = &printTarget{out: out, width: width, profile: profile}
+	}
+}
+
+// SetColorProfile sets the colors the viewers write to the terminal with,
+// in place of what the terminal reports. Without colors, bold and
+// underline are still written, for themes that mark changes with them.
+func SetColorProfile(profile termenv.Profile) {
+	if profile == termenv.Ascii {
+		profile = termenv.ANSI
+	}
+	lipgloss.SetColorProfile(profile)
+}
+
+// WithViewerReloads replaces the diff on screen with each diff received from
+// reloads, such as when watching the working tree for changes.
+func WithViewerReloads(reloads <-chan *diffview.Diff) ViewerOption {
+	return func(v *Viewer) {
+		v.reloads = reloads
+	}
+}
+
+// WithViewerAnnotations enables writing review notes on the diff, recorded
+// by store at path.
+func WithViewerAnnotations(store diffview.AnnotationStore, path string) ViewerOption {
+	return func(v *Viewer) {
+		v.noteStore = store
+		v.notesPath = path
+	}
+}
+
+// WithViewerSecretDetector flags added lines that look like credentials.
+func WithViewerSecretDetector(d diffview.SecretDetector) ViewerOption {
+	return func(v *Viewer) {
+		v.secrets = d
+	}
+}
+
+// WithViewerAnnotators runs annotators over the diff, showing their notes
+// alongside it.
+func WithViewerAnnotators(annotators ...diffview.Annotator) ViewerOption {
+	return func(v *Viewer) {
+		v.annotators = append(v.annotators, annotators...)
+	}
+}
+
+// WithViewerSymbolResolver names the declaration enclosing each hunk in
+// its header.
+func WithViewerSymbolResolver(r diffview.SymbolResolver) ViewerOption {
+	return func(v *Viewer) {
+		v.symbols = r
+	}
+}
+
+// WithViewerHunkGrouper lets s and S move between hunks that touch the
+// same symbols.
+func WithViewerHunkGrouper(g diffview.HunkGrouper) ViewerOption {
+	return func(v *Viewer) {
+		v.grouper = g
+	}
+}
+
+// WithViewerMoveDetector shows moved lines distinctly from other deleted
+// and added lines, with m jumping between a move's two sides.
+func WithViewerMoveDetector(d diffview.MoveDetector) ViewerOption {
+	return func(v *Viewer) {
+		v.moveDetector = d
+	}
+}
+
+// WithViewerStructuralDiffer lets t show the file in view as the keys it
+// changes, for config files.
+func WithViewerStructuralDiffer(d diffview.StructuralDiffer) ViewerOption {
+	return func(v *Viewer) {
+		v.structurer = d
+	}
+}
+
+// WithViewerInitialFocus opens the view at the hunk ref names.
+func WithViewerInitialFocus(ref diffview.HunkRef) ViewerOption {
+	return func(v *Viewer) {
+		v.focus = &ref
+	}
+}
+
+// WithViewerOnQuit sets a function called with where the view stood when
+// the reader quits.
+func WithViewerOnQuit(fn func(QuitState)) ViewerOption {
+	return func(v *Viewer) {
+		v.onQuit = fn
+	}
+}
+
+// WithViewerStatusSegments adds segments to the status bar, left of the
+// file and hunk positions.
+func WithViewerStatusSegments(segments ...StatusSegment) ViewerOption {
+	return func(v *Viewer) {
+		v.statusSegments = append(v.statusSegments, segments...)
+	}
+}
+
+// WithViewerDependencySummarizer shows manifests and lockfiles as the
+// dependency changes s finds in them.
+func WithViewerDependencySummarizer(s diffview.DependencySummarizer) ViewerOption {
+	return func(v *Viewer) {
+		v.summarizer = s
+	}
+}
+
 // NewViewer creates a new Viewer with the given theme.
 func NewViewer(theme diffview.Theme, opts ...ViewerOption) *Viewer {
 	v := &Viewer{theme: theme}
@@ -524,19 +1485,100 @@ func NewViewer(theme diffview.Theme, opts ...ViewerOption) *Viewer {
 
 // View displays the diff and blocks until the user exits.
 func (v *Viewer) View(ctx context.Context, diff *diffview.Diff) error {
+	if v.print != nil {
+		return v.printDiff(diff)
+	}
+	editorOption := WithEditor(v.editor)
+	if v.editorRemote {
+		editorOption = WithRemoteEditor(v.editor)
+	}
 	m := NewModel(diff,
 		WithTheme(v.theme),
 		WithLanguageDetector(v.languageDetector),
 		WithTokenizer(v.tokenizer),
 		WithWordDiffer(v.wordDiffer),
+		WithTabWidth(v.tabWidth),
+		WithFileGroups(v.fileGroupBy),
+		WithIdleTimeout(v.idleTimeout),
+		editorOption,
+		WithPane(v.pane),
+		WithScrolling(v.scrolling),
+		WithAnnotations(v.noteStore, v.notesPath),
+		WithSecretDetector(v.secrets),
+		WithAnnotators(v.annotators...),
+		WithSymbolResolver(v.symbols),
+		WithHunkGrouper(v.grouper),
+		WithMoveDetector(v.moveDetector),
+		WithStructuralDiffer(v.structurer),
+		WithDependencySummarizer(v.summarizer),
+		WithOnQuit(v.onQuit),
+		WithStatusSegments(v.statusSegments...),
 	)
+	if v.focus != nil {
+		m.focus = v.focus
+	}
+	if v.script != nil {
+		return v.script.script.Run(NewDriver(m, defaultScriptWidth, defaultScriptHeight), v.script.out)
+	}
+	if v.oneScreen != nil {
+		m.width = v.oneScreen.width
+		// The shell prompt takes the row after the diff
+		if content := m.renderContent(); strings.Count(content, "\n") <= v.oneScreen.height-1 {
+			_, err := io.WriteString(v.oneScreen.out, content)
+			return err
+		}
+	}
 	opts := []tea.ProgramOption{
-		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 		tea.WithContext(ctx),
 	}
+	if !v.noAltScreen {
+		opts = append(opts, tea.WithAltScreen())
+	}
 	opts = append(opts, v.programOpts...)
 	p := tea.NewProgram(m, opts...)
+	if v.reloads != nil {
+		go forwardReloads(ctx, p, v.reloads)
+	}
 	_, err := p.Run()
 	return err
 }
+
+// printDiff renders diff to the print target.
+func (v *Viewer) printDiff(diff *diffview.Diff) error {
+	renderer := lipgloss.NewRenderer(v.print.out)
+	renderer.SetColorProfile(v.print.profile)
+	m := NewModel(diff,
+		WithRenderer(renderer),
+		WithTheme(v.theme),
+		WithLanguageDetector(v.languageDetector),
+		WithTokenizer(v.tokenizer),
+		WithWordDiffer(v.wordDiffer),
+		WithTabWidth(v.tabWidth),
+		WithFileGroups(v.fileGroupBy),
+		WithSecretDetector(v.secrets),
+		WithAnnotators(v.annotators...),
+		WithSymbolResolver(v.symbols),
+		WithMoveDetector(v.moveDetector),
+	)
+	m.width = v.print.width
+	m.wrap = true
+	_, err := io.WriteString(v.print.out, m.renderContent())
+	return err
+}
+
+// forwardReloads sends each diff from reloads to the program until ctx is
+// done or reloads is closed.
+func forwardReloads(ctx context.Context, p *tea.Program, reloads <-chan *diffview.Diff) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case diff, ok := <-reloads:
+			if !ok {
+				return
+			}
+			p.Send(ReloadMsg{Diff: diff})
+		}
+	}
+}
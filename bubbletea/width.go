@@ -1,10 +1,31 @@
 package bubbletea
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // tabWidth is the standard terminal tab stop interval.
 const tabWidth = 8
 
+// maxTabWidth bounds configurable tab widths to keep lines readable.
+const maxTabWidth = 16
+
+// ParseTabWidth parses a tab width setting such as a flag or environment
+// value. An empty string selects the default of 8 columns.
+func ParseTabWidth(s string) (int, error) {
+	if s == "" {
+		return tabWidth, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > maxTabWidth {
+		return 0, fmt.Errorf("invalid tab width %q: must be between 1 and %d", s, maxTabWidth)
+	}
+	return n, nil
+}
+
 // DisplayWidth calculates the display width of a string, correctly handling
 // tab characters which expand to the next 8-column boundary.
 // This fixes the issue where lipgloss.Width returns 0 for tabs.
@@ -5,6 +5,7 @@ import (
 
 	"github.com/fwojciec/diffstory/bubbletea"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDisplayWidth(t *testing.T) {
@@ -75,3 +76,34 @@ func TestDisplayWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTabWidth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults to 8 when unset", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := bubbletea.ParseTabWidth("")
+
+		require.NoError(t, err)
+		assert.Equal(t, 8, n)
+	})
+
+	t.Run("accepts configured width", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := bubbletea.ParseTabWidth("4")
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+	})
+
+	t.Run("rejects invalid widths", func(t *testing.T) {
+		t.Parallel()
+
+		for _, s := range []string{"0", "-2", "17", "four"} {
+			_, err := bubbletea.ParseTabWidth(s)
+			assert.Error(t, err, "input %q", s)
+		}
+	})
+}
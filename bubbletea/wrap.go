@@ -0,0 +1,123 @@
+package bubbletea
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fwojciec/diffstory"
+)
+
+// continuationMarker fills the prefix column of soft-wrapped continuation rows.
+const continuationMarker = "↪"
+
+// horizontalScrollStep is the number of columns h/l scroll line content.
+const horizontalScrollStep = 8
+
+// minWrapWidth keeps wrapped rows readable on very narrow terminals; lines
+// wider than the terminal at this width are cut off by the viewport.
+const minWrapWidth = 10
+
+// gutterColumns returns the display width of the line number gutter plus the
+// padding space that separates it from the line prefix.
+func gutterColumns(gutterWidth int) int {
+	return 2*gutterWidth + 3
+}
+
+// wrapWidth returns the number of content columns available on each row for
+// a line with the given prefix width.
+func wrapWidth(width, gutterWidth, prefixWidth int) int {
+	return max(width-gutterColumns(gutterWidth)-prefixWidth, minWrapWidth)
+}
+
+// contentWidth returns the display width of a line's content as rendered,
+// after control bytes are escaped and tabs expanded.
+func contentWidth(line diffview.Line, tabStop int) int {
+	if tabStop <= 0 {
+		tabStop = tabWidth
+	}
+	content := strings.TrimSuffix(escapeControl(line.Content), "\n")
+	return lipgloss.Width(expandTabsWidth(content, 0, tabStop))
+}
+
+// rowCount returns the number of rows needed to show content of the given
+// width in rows of avail columns. Empty lines still take one row.
+func rowCount(contentWidth, avail int) int {
+	if contentWidth <= avail {
+		return 1
+	}
+	return (contentWidth + avail - 1) / avail
+}
+
+// wrappedRows returns a function reporting how many display rows each line
+// of diff occupies when soft-wrapped at width.
+func wrappedRows(diff *diffview.Diff, width, tabStop int) func(diffview.Line) int {
+	gutterWidth := calculateGutterWidth(diff)
+	return func(line diffview.Line) int {
+		avail := wrapWidth(width, gutterWidth, len(linePrefix(line)))
+		return rowCount(contentWidth(line, tabStop), avail)
+	}
+}
+
+// countRows returns the number of display rows lines occupy. A nil rows
+// function means one row per line.
+func countRows(lines []diffview.Line, rows func(diffview.Line) int) int {
+	if rows == nil {
+		return len(lines)
+	}
+	n := 0
+	for _, line := range lines {
+		n += rows(line)
+	}
+	return n
+}
+
+// maxXOffset returns how far line content can scroll left before the widest
+// line's end reaches the right edge of the terminal.
+func maxXOffset(diff *diffview.Diff, width, tabStop int) int {
+	if diff == nil {
+		return 0
+	}
+	gutterWidth := calculateGutterWidth(diff)
+	widest := 0
+	for _, file := range diff.Files {
+		if !file.Visible() {
+			continue
+		}
+		for _, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				visible := width - gutterColumns(gutterWidth) - len(linePrefix(line))
+				widest = max(widest, contentWidth(line, tabStop)-visible)
+			}
+		}
+	}
+	return widest
+}
+
+// clampXOffset limits a horizontal scroll offset to [0, limit].
+func clampXOffset(offset, limit int) int {
+	return max(0, min(offset, limit))
+}
+
+// scrollLine drops the first xOffset columns of content from a styled line,
+// keeping the diff prefix in place. Styles carry over the cut, so word-diff
+// highlights stay intact.
+func scrollLine(styled string, prefixWidth, xOffset int) string {
+	return ansi.Cut(styled, 0, prefixWidth) + ansi.TruncateLeft(styled, prefixWidth+xOffset, "")
+}
+
+// wrapLine splits the content of a styled line into rows of avail columns.
+// The first row keeps the diff prefix; later rows hold content only. Styles
+// carry over each cut, so word-diff highlights continue across rows.
+func wrapLine(styled string, prefixWidth, avail, rows int) []string {
+	out := make([]string, rows)
+	for i := range rows {
+		start := prefixWidth + i*avail
+		row := ansi.Cut(styled, start, start+avail)
+		if i == 0 {
+			row = ansi.Cut(styled, 0, prefixWidth) + row
+		}
+		out[i] = row
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package chroma
 
 import (
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/alecthomas/chroma/v2/lexers"
@@ -11,12 +12,43 @@ import (
 // Compile-time interface verification.
 var _ diffview.LanguageDetector = (*Detector)(nil)
 
-// Detector detects programming languages from file paths using chroma.
-type Detector struct{}
+// Detector detects programming languages from file paths and contents
+// using chroma.
+type Detector struct {
+	// modelines match vim ("vim: set ft=python:") and emacs ("-*- mode:
+	// ruby -*-") modelines, capturing the language they name.
+	modelines []*regexp.Regexp
+
+	// heuristics are lines that give away a file's language, checked in
+	// order.
+	heuristics []contentHeuristic
+}
+
+// contentHeuristic is a line that gives away a file's language.
+type contentHeuristic struct {
+	pattern  *regexp.Regexp
+	language string
+}
 
 // NewDetector creates a new chroma-based language detector.
 func NewDetector() *Detector {
-	return &Detector{}
+	return &Detector{
+		modelines: []*regexp.Regexp{
+			regexp.MustCompile(`(?m)\b(?:vi|vim|ex):.*\b(?:ft|filetype|syntax)=([\w+-]+)`),
+			regexp.MustCompile(`-\*-\s*(?:[^:;]*;\s*)*mode:\s*([\w+-]+)`),
+			regexp.MustCompile(`-\*-\s*([\w+-]+)\s*-\*-`),
+		},
+		heuristics: []contentHeuristic{
+			{regexp.MustCompile(`^<\?php`), "PHP"},
+			{regexp.MustCompile(`^<\?xml\b`), "XML"},
+			{regexp.MustCompile(`(?i)^\s*<!doctype html|^\s*<html\b`), "HTML"},
+			{regexp.MustCompile(`(?m)^package [a-z_][a-z0-9_]*\s*$`), "Go"},
+			{regexp.MustCompile(`(?m)^package [\w.]+;\s*$`), "Java"},
+			{regexp.MustCompile(`(?m)^#include\s*[<"]`), "C++"},
+			{regexp.MustCompile(`(?m)^(?:async )?def \w+\(.*\):\s*$|^from [\w.]+ import \w`), "Python"},
+			{regexp.MustCompile(`(?m)^FROM \S+`), "Docker"},
+		},
+	}
 }
 
 // DetectFromPath returns the language name for the given path,
@@ -37,3 +69,72 @@ func (d *Detector) DetectFromPath(path string) string {
 
 	return lexer.Config().Name
 }
+
+// DetectFromContent returns the language of a file from its opening
+// lines, or an empty string if the language cannot be determined. It
+// reads a shebang, then a vim or emacs modeline, then looks for telltale
+// lines of a few common languages.
+func (d *Detector) DetectFromContent(content string) string {
+	first, _, _ := strings.Cut(content, "\n")
+	if strings.HasPrefix(first, "#!") {
+		if lang := interpreterLanguage(first); lang != "" {
+			return lang
+		}
+	}
+	for _, re := range d.modelines {
+		if m := re.FindStringSubmatch(content); m != nil {
+			if lexer := lexers.Get(m[1]); lexer != nil {
+				return lexer.Config().Name
+			}
+		}
+	}
+	for _, h := range d.heuristics {
+		if h.pattern.MatchString(content) {
+			return h.language
+		}
+	}
+	return ""
+}
+
+// interpreterAlias returns the language of interpreters chroma doesn't
+// know by name, or "" for any other.
+func interpreterAlias(name string) string {
+	switch name {
+	case "node", "nodejs", "bun":
+		return "JavaScript"
+	case "deno", "ts-node", "tsx":
+		return "TypeScript"
+	}
+	return ""
+}
+
+// interpreterLanguage returns the language of the interpreter a shebang
+// line runs, as in "#!/bin/sh" or "#!/usr/bin/env -S python3 -u".
+func interpreterLanguage(shebang string) string {
+	fields := strings.Fields(strings.TrimPrefix(shebang, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	name := filepath.Base(fields[0])
+	if name == "env" {
+		name = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				name = filepath.Base(f)
+				break
+			}
+		}
+	}
+	// python3.12 runs Python
+	name = strings.TrimRight(name, "0123456789.")
+	if name == "" {
+		return ""
+	}
+	if lang := interpreterAlias(name); lang != "" {
+		return lang
+	}
+	if lexer := lexers.Get(name); lexer != nil {
+		return lexer.Config().Name
+	}
+	return ""
+}
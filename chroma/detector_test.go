@@ -77,3 +77,31 @@ func TestDetector_DetectFromPath(t *testing.T) {
 		assert.Equal(t, "Go", lang)
 	})
 }
+
+func TestDetector_DetectFromContent(t *testing.T) {
+	t.Parallel()
+
+	detector := chroma.NewDetector()
+
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"shebang", "#!/bin/sh\necho hi\n", "Bash"},
+		{"env shebang", "#!/usr/bin/env python3\nprint()\n", "Python"},
+		{"env shebang with flags", "#!/usr/bin/env -S python3.12 -u\n", "Python"},
+		{"interpreter chroma doesn't name", "#!/usr/bin/env node\n", "JavaScript"},
+		{"vim modeline", "# vim: set ft=ruby:\nputs 1\n", "Ruby"},
+		{"emacs modeline", "# -*- mode: perl -*-\nprint 1;\n", "Perl"},
+		{"go package clause", "// Package x does things.\npackage x\n\nfunc f() {}\n", "Go"},
+		{"php", "<?php\necho 1;\n", "PHP"},
+		{"python def", "import os\n\ndef main():\n    pass\n", "Python"},
+		{"unknown shebang", "#!/opt/bin/frobnicate\n", ""},
+		{"plain text", "hello world\n", ""},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, detector.DetectFromContent(tc.content), tc.name)
+	}
+}
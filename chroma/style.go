@@ -1,7 +1,13 @@
 package chroma
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
 	chromalib "github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/styles"
 	"github.com/fwojciec/diffstory"
 )
 
@@ -59,3 +65,46 @@ func StyleFromPalette(p diffview.Palette) StyleFunc {
 		}
 	}
 }
+
+// StyleFromChroma returns a function that maps chroma token types to
+// diffview styles from a chroma style, such as one of an editor's themes.
+// The style's plain text color and backgrounds are left out, so diff lines
+// keep their own.
+func StyleFromChroma(s *chromalib.Style) StyleFunc {
+	text := s.Get(chromalib.Text).Colour
+	return func(tt chromalib.TokenType) diffview.Style {
+		entry := s.Get(tt)
+		var style diffview.Style
+		if entry.Colour.IsSet() && entry.Colour != text {
+			style.Foreground = entry.Colour.String()
+		}
+		style.Bold = entry.Bold == chromalib.Yes
+		return style
+	}
+}
+
+// SyntaxStyle returns the style function for a syntax theme: the name of
+// one of chroma's styles, such as "dracula", or the path of a style file
+// in chroma's XML format to give every token's color. An empty name
+// derives token colors from p.
+func SyntaxStyle(name string, p diffview.Palette) (StyleFunc, error) {
+	if name == "" {
+		return StyleFromPalette(p), nil
+	}
+	if s, ok := styles.Registry[name]; ok {
+		return StyleFromChroma(s), nil
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unknown syntax theme %q: must be a chroma style such as dracula or monokai, or a style file", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s, err := chromalib.NewXMLStyle(f)
+	if err != nil {
+		return nil, fmt.Errorf("syntax theme %s: %w", name, err)
+	}
+	return StyleFromChroma(s), nil
+}
@@ -1,12 +1,15 @@
 package chroma_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	chromalib "github.com/alecthomas/chroma/v2"
 	"github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/chroma"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStyleFromPalette(t *testing.T) {
@@ -91,3 +94,44 @@ func TestStyleFromPalette(t *testing.T) {
 		assert.False(t, style.Bold)
 	})
 }
+
+func TestSyntaxStyle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty name derives colors from the palette", func(t *testing.T) {
+		t.Parallel()
+		styleFunc, err := chroma.SyntaxStyle("", diffview.Palette{String: "#00ff00"})
+		require.NoError(t, err)
+		assert.Equal(t, "#00ff00", styleFunc(chromalib.String).Foreground)
+	})
+
+	t.Run("chroma style by name", func(t *testing.T) {
+		t.Parallel()
+		styleFunc, err := chroma.SyntaxStyle("dracula", diffview.Palette{})
+		require.NoError(t, err)
+		assert.Equal(t, "#ff79c6", styleFunc(chromalib.Keyword).Foreground)
+		// Plain text keeps the diff line's color
+		assert.Empty(t, styleFunc(chromalib.Name).Foreground)
+	})
+
+	t.Run("style file", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "mine.xml")
+		require.NoError(t, os.WriteFile(path, []byte(`<style name="mine">
+  <entry type="Text" style="#eeeeee"/>
+  <entry type="Keyword" style="bold #123456"/>
+</style>`), 0o644))
+
+		styleFunc, err := chroma.SyntaxStyle(path, diffview.Palette{})
+		require.NoError(t, err)
+		style := styleFunc(chromalib.KeywordDeclaration)
+		assert.Equal(t, "#123456", style.Foreground)
+		assert.True(t, style.Bold)
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		t.Parallel()
+		_, err := chroma.SyntaxStyle("no-such-theme", diffview.Palette{})
+		assert.ErrorContains(t, err, `unknown syntax theme "no-such-theme"`)
+	})
+}
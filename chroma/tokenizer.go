@@ -3,6 +3,7 @@ package chroma
 
 import (
 	"errors"
+	"iter"
 	"strings"
 
 	chromalib "github.com/alecthomas/chroma/v2"
@@ -30,113 +31,67 @@ func NewTokenizer(styleFunc StyleFunc) (*Tokenizer, error) {
 	return &Tokenizer{styleFunc: styleFunc}, nil
 }
 
-// Tokenize splits source code into syntax-highlighted tokens for the given language.
-// Returns nil if the language is not supported or an error occurs.
-// Returns an empty slice for empty source (valid input, no tokens).
-func (t *Tokenizer) Tokenize(language, source string) []diffview.Token {
-	if source == "" {
-		return []diffview.Token{}
-	}
-
-	lexer := lexers.Get(language)
-	if lexer == nil {
-		return nil
-	}
-
-	// Coalesce for better performance with consecutive tokens of the same type
-	lexer = chromalib.Coalesce(lexer)
-
-	iterator, err := lexer.Tokenise(nil, source)
-	if err != nil {
-		return nil
-	}
-
-	var tokens []diffview.Token
-	for token := iterator(); token != chromalib.EOF; token = iterator() {
-		style := t.styleFunc(token.Type)
-		tokens = append(tokens, diffview.Token{
-			Text:  token.Value,
-			Style: style,
-		})
-	}
-
-	return tokens
-}
-
 // TokenizeLines tokenizes source code with full context, then splits tokens by line.
 // This correctly handles multi-line constructs like /* */ comments and JSDoc.
-// Returns nil if the language is not supported or an error occurs.
+// Returns nil if the language is not supported.
 // Returns an empty slice for empty source.
 func (t *Tokenizer) TokenizeLines(language, source string) [][]diffview.Token {
 	if source == "" {
 		return [][]diffview.Token{}
 	}
-
-	lexer := lexers.Get(language)
-	if lexer == nil {
-		return nil
-	}
-
-	// Coalesce for better performance with consecutive tokens of the same type
-	lexer = chromalib.Coalesce(lexer)
-
-	iterator, err := lexer.Tokenise(nil, source)
-	if err != nil {
+	if lexers.Get(language) == nil {
 		return nil
 	}
-
-	// Collect all tokens first (with full context)
-	var allTokens []diffview.Token
-	for token := iterator(); token != chromalib.EOF; token = iterator() {
-		style := t.styleFunc(token.Type)
-		allTokens = append(allTokens, diffview.Token{
-			Text:  token.Value,
-			Style: style,
-		})
+	lines := [][]diffview.Token{}
+	for line := range t.StreamLines(language, source) {
+		lines = append(lines, line)
 	}
-
-	// Split tokens by newlines
-	return splitTokensByLine(allTokens)
+	return lines
 }
 
-// splitTokensByLine splits a flat list of tokens into per-line token slices.
-// Handles tokens that span multiple lines by splitting them at newline boundaries.
-func splitTokensByLine(tokens []diffview.Token) [][]diffview.Token {
-	if len(tokens) == 0 {
-		return [][]diffview.Token{}
-	}
-
-	var result [][]diffview.Token
-	var currentLine []diffview.Token
+// StreamLines tokenizes source code as TokenizeLines does, yielding each
+// line's tokens as soon as the lexer reaches its end, so that a caller
+// needing only the first lines stops lexing there. Tokens spanning lines
+// are split at each newline. Yields nothing if the language is not
+// supported or an error occurs.
+func (t *Tokenizer) StreamLines(language, source string) iter.Seq[[]diffview.Token] {
+	return func(yield func([]diffview.Token) bool) {
+		if source == "" {
+			return
+		}
+		lexer := lexers.Get(language)
+		if lexer == nil {
+			return
+		}
 
-	for _, tok := range tokens {
-		// Token without newlines goes directly to current line
-		if !strings.Contains(tok.Text, "\n") {
-			currentLine = append(currentLine, tok)
-			continue
+		// Coalesce for better performance with consecutive tokens of the same type
+		iterator, err := chromalib.Coalesce(lexer).Tokenise(nil, source)
+		if err != nil {
+			return
 		}
 
-		// Split the token at newline boundaries
-		parts := strings.Split(tok.Text, "\n")
-		for i, part := range parts {
-			if part != "" {
-				currentLine = append(currentLine, diffview.Token{
-					Text:  part,
-					Style: tok.Style,
-				})
-			}
-			// If this isn't the last part, we hit a newline - finalize the line
-			if i < len(parts)-1 {
-				result = append(result, currentLine)
-				currentLine = nil
+		var line []diffview.Token
+		for token := iterator(); token != chromalib.EOF; token = iterator() {
+			style := t.styleFunc(token.Type)
+			text := token.Value
+			for {
+				part, rest, found := strings.Cut(text, "\n")
+				if part != "" {
+					line = append(line, diffview.Token{Text: part, Style: style})
+				}
+				if !found {
+					break
+				}
+				if !yield(line) {
+					return
+				}
+				line, text = nil, rest
 			}
 		}
-	}
 
-	// Don't forget the last line if it has content
-	if len(currentLine) > 0 {
-		result = append(result, currentLine)
+		// The last line, unless source ends with a newline
+		if len(line) > 0 {
+			yield(line)
+		}
 	}
-
-	return result
 }
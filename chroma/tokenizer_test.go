@@ -1,6 +1,7 @@
 package chroma_test
 
 import (
+	"slices"
 	"testing"
 
 	chromalib "github.com/alecthomas/chroma/v2"
@@ -16,7 +17,7 @@ func testStyleFunc() func(chromalib.TokenType) diffview.Style {
 	return chroma.StyleFromPalette(lipgloss.TestTheme().Palette())
 }
 
-func TestTokenizer_Tokenize(t *testing.T) {
+func TestTokenizer_TokenizeLines_SingleLine(t *testing.T) {
 	t.Parallel()
 
 	t.Run("tokenizes Go code", func(t *testing.T) {
@@ -24,8 +25,10 @@ func TestTokenizer_Tokenize(t *testing.T) {
 
 		tokenizer, err := chroma.NewTokenizer(testStyleFunc())
 		require.NoError(t, err)
-		tokens := tokenizer.Tokenize("go", `package main`)
+		lines := tokenizer.TokenizeLines("go", `package main`)
 
+		require.Len(t, lines, 1)
+		tokens := lines[0]
 		require.NotEmpty(t, tokens, "expected tokens for valid Go code")
 
 		// Reconstruct the source from tokens
@@ -51,9 +54,9 @@ func TestTokenizer_Tokenize(t *testing.T) {
 
 		tokenizer, err := chroma.NewTokenizer(testStyleFunc())
 		require.NoError(t, err)
-		tokens := tokenizer.Tokenize("nonexistent-language-xyz", "some code")
+		lines := tokenizer.TokenizeLines("nonexistent-language-xyz", "some code")
 
-		assert.Nil(t, tokens)
+		assert.Nil(t, lines)
 	})
 
 	t.Run("handles empty source", func(t *testing.T) {
@@ -61,9 +64,9 @@ func TestTokenizer_Tokenize(t *testing.T) {
 
 		tokenizer, err := chroma.NewTokenizer(testStyleFunc())
 		require.NoError(t, err)
-		tokens := tokenizer.Tokenize("go", "")
+		lines := tokenizer.TokenizeLines("go", "")
 
-		assert.Empty(t, tokens)
+		assert.Empty(t, lines)
 	})
 
 	t.Run("styles function names", func(t *testing.T) {
@@ -72,8 +75,10 @@ func TestTokenizer_Tokenize(t *testing.T) {
 		tokenizer, err := chroma.NewTokenizer(testStyleFunc())
 		require.NoError(t, err)
 		// Code with a function definition
-		tokens := tokenizer.Tokenize("go", `func foo() {}`)
+		lines := tokenizer.TokenizeLines("go", `func foo() {}`)
 
+		require.Len(t, lines, 1)
+		tokens := lines[0]
 		require.NotEmpty(t, tokens)
 
 		var fooStyle diffview.Style
@@ -94,8 +99,10 @@ func TestTokenizer_Tokenize(t *testing.T) {
 		palette := lipgloss.TestTheme().Palette()
 		tokenizer, err := chroma.NewTokenizer(chroma.StyleFromPalette(palette))
 		require.NoError(t, err)
-		tokens := tokenizer.Tokenize("go", `package main`)
+		lines := tokenizer.TokenizeLines("go", `package main`)
 
+		require.Len(t, lines, 1)
+		tokens := lines[0]
 		require.NotEmpty(t, tokens)
 
 		// Find the "package" keyword and verify it uses the palette's keyword color
@@ -251,3 +258,45 @@ func TestTokenizer_TokenizeLines(t *testing.T) {
 		}
 	})
 }
+
+func TestTokenizer_StreamLines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("yields the lines TokenizeLines returns", func(t *testing.T) {
+		t.Parallel()
+
+		tokenizer, err := chroma.NewTokenizer(testStyleFunc())
+		require.NoError(t, err)
+		source := "/* start\nstill comment */\nfunc foo() {}\n"
+
+		streamed := slices.Collect(tokenizer.StreamLines("go", source))
+
+		assert.Len(t, streamed, 3)
+		assert.Equal(t, tokenizer.TokenizeLines("go", source), streamed)
+	})
+
+	t.Run("stops lexing when the caller stops", func(t *testing.T) {
+		t.Parallel()
+
+		tokenizer, err := chroma.NewTokenizer(testStyleFunc())
+		require.NoError(t, err)
+
+		var first []diffview.Token
+		for line := range tokenizer.StreamLines("go", "package main\n\nfunc main() {}\n") {
+			first = line
+			break
+		}
+
+		require.NotEmpty(t, first)
+		assert.Equal(t, "package", first[0].Text)
+	})
+
+	t.Run("yields nothing for unsupported language", func(t *testing.T) {
+		t.Parallel()
+
+		tokenizer, err := chroma.NewTokenizer(testStyleFunc())
+		require.NoError(t, err)
+
+		assert.Empty(t, slices.Collect(tokenizer.StreamLines("nonexistent-language-xyz", "some code")))
+	})
+}
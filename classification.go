@@ -1,6 +1,12 @@
 package diffview
 
-import "context"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
 
 // CommitBrief captures essential commit metadata for PR context.
 type CommitBrief struct {
@@ -18,6 +24,79 @@ type ClassificationInput struct {
 	PRDescription string        `json:"pr_description,omitempty"`
 	Commits       []CommitBrief `json:"commits"`
 	Diff          Diff          `json:"diff"`
+	Groups        []HunkGroup   `json:"groups,omitempty"`      // Hunks touching the same symbols, as hints
+	Reviewers     []Reviewer    `json:"reviewers,omitempty"`   // Who would likely review the change
+	APIChanges    []APIChange   `json:"api_changes,omitempty"` // Exported Go API the diff changes
+
+	// PathsOnly marks an input whose code content was stripped for sharing
+	// (see StripContent). The diff keeps only paths and hunk structure.
+	PathsOnly bool `json:"paths_only,omitempty"`
+}
+
+// Reviewer is someone suggested to review a change: an owner of changed
+// files named in CODEOWNERS, or an author who often changed them before.
+type Reviewer struct {
+	Name    string   `json:"name"`              // CODEOWNERS owner such as @org/team, or a commit author
+	Owner   bool     `json:"owner,omitempty"`   // Named in CODEOWNERS rather than found in history
+	Files   []string `json:"files"`             // Changed files they own or have changed most
+	Commits int      `json:"commits,omitempty"` // Their past commits to those files
+}
+
+// String returns the reviewer's name, marking CODEOWNERS owners.
+func (r Reviewer) String() string {
+	if r.Owner {
+		return r.Name + " (owner)"
+	}
+	return r.Name
+}
+
+// ReviewerSuggester suggests who should review a diff, most relevant first.
+type ReviewerSuggester interface {
+	SuggestReviewers(ctx context.Context, diff *Diff) ([]Reviewer, error)
+}
+
+// StripContent returns a copy of the input with code content removed, for
+// sharing classifier behavior analyses outside the org. File paths, file
+// operations, hunk ranges, and line types and numbers are kept so stats and
+// hunk references still resolve; line text, hunk section headings, extended
+// headers, PR text, and commit messages are dropped.
+func (c ClassificationInput) StripContent() ClassificationInput {
+	stripped := ClassificationInput{
+		Repo:      c.Repo,
+		Branch:    c.Branch,
+		Diff:      stripDiff(c.Diff),
+		PathsOnly: true,
+	}
+	for _, commit := range c.Commits {
+		brief := CommitBrief{Hash: commit.Hash}
+		if commit.Diff != nil {
+			d := stripDiff(*commit.Diff)
+			brief.Diff = &d
+		}
+		stripped.Commits = append(stripped.Commits, brief)
+	}
+	return stripped
+}
+
+func stripDiff(d Diff) Diff {
+	files := make([]FileDiff, len(d.Files))
+	for i, f := range d.Files {
+		f.Extended = nil
+		hunks := make([]Hunk, len(f.Hunks))
+		for j, h := range f.Hunks {
+			h.Section = ""
+			lines := make([]Line, len(h.Lines))
+			for k, l := range h.Lines {
+				l.Content = ""
+				lines[k] = l
+			}
+			h.Lines = lines
+			hunks[j] = h
+		}
+		f.Hunks = hunks
+		files[i] = f
+	}
+	return Diff{Files: files}
 }
 
 // FirstCommitMessage returns the message of the first commit, or empty if none.
@@ -38,12 +117,32 @@ func (c ClassificationInput) FirstCommitHash() string {
 	return c.Commits[0].Hash
 }
 
-// CaseID returns a unique identifier for this case using repo/branch format.
-// This uniquely identifies a PR-level case for judgment linking.
-func (c ClassificationInput) CaseID() string {
+// Name returns the case's repo/branch, which reviewers know it by. Names
+// aren't unique: branches are reused, and commit-level cases have none.
+// Before content IDs, names were case IDs.
+func (c ClassificationInput) Name() string {
 	return c.Repo + "/" + c.Branch
 }
 
+// ContentID returns an ID derived from what the input changes: its repo,
+// commits, and the files and hunk ranges of its diff. Collecting the same
+// change twice gives the same ID, and stripping content (see StripContent)
+// keeps it.
+func (c ClassificationInput) ContentID() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "repo %q\n", c.Repo)
+	for _, commit := range c.Commits {
+		fmt.Fprintf(h, "commit %q\n", commit.Hash)
+	}
+	for _, f := range c.Diff.Files {
+		fmt.Fprintf(h, "file %q %q %d\n", f.OldPath, f.NewPath, f.Operation)
+		for _, hunk := range f.Hunks {
+			fmt.Fprintf(h, "hunk %d %d %d %d\n", hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
 // StoryClassification is the LLM's structured output for a diff.
 type StoryClassification struct {
 	ChangeType string    `json:"change_type"`         // bugfix, feature, refactor, chore, docs
@@ -51,14 +150,43 @@ type StoryClassification struct {
 	Summary    string    `json:"summary"`             // One sentence describing the change
 	Sections   []Section `json:"sections"`            // Ordered sections grouping related hunks
 	Evolution  string    `json:"evolution,omitempty"` // How changes evolved across commits
+	Risk       *Risk     `json:"risk,omitempty"`      // What the change may break for its users
+	Impact     *Impact   `json:"impact,omitempty"`    // Commit type and version bump, if analyzed
+}
+
+// Risk is what a change may break for the code and people depending on it.
+type Risk struct {
+	Level        string   `json:"level"`                   // low, medium, high
+	Breaking     []string `json:"breaking,omitempty"`      // Changes that break existing callers, data or config
+	Migration    []string `json:"migration,omitempty"`     // Steps users need to take to upgrade
+	AffectedAPIs []string `json:"affected_apis,omitempty"` // Public APIs whose signature or behavior changes
+}
+
+// Impact is the conventional commit type of a change and the semantic
+// version bump it calls for.
+type Impact struct {
+	CommitType string   `json:"commit_type"`        // feat, fix, refactor, docs, chore
+	Bump       string   `json:"bump"`               // major, minor, patch
+	Breaking   []string `json:"breaking,omitempty"` // Exported identifiers removed or changed
+	Added      []string `json:"added,omitempty"`    // Exported identifiers added
+}
+
+// ConventionalType returns the commit type as a conventional commit header
+// writes it, marked with "!" for a breaking change, such as "feat!".
+func (i Impact) ConventionalType() string {
+	if i.Bump == "major" {
+		return i.CommitType + "!"
+	}
+	return i.CommitType
 }
 
 // Section groups related hunks with a narrative role.
 type Section struct {
-	Role        string    `json:"role"`        // problem, fix, test, core, supporting, etc.
-	Title       string    `json:"title"`       // Human-readable section title
-	Hunks       []HunkRef `json:"hunks"`       // References to hunks in this section
-	Explanation string    `json:"explanation"` // Why this section matters
+	Role        string    `json:"role"`                // problem, fix, test, core, supporting, etc.
+	Title       string    `json:"title"`               // Human-readable section title
+	Hunks       []HunkRef `json:"hunks"`               // References to hunks in this section
+	Explanation string    `json:"explanation"`         // Why this section matters
+	Narration   string    `json:"narration,omitempty"` // What a presenter would say over it, if narrated
 }
 
 // HunkRef references a specific hunk with classification metadata.
@@ -74,3 +202,97 @@ type HunkRef struct {
 type StoryClassifier interface {
 	Classify(ctx context.Context, input ClassificationInput) (*StoryClassification, error)
 }
+
+// StoryNarrator writes what a presenter would say over each section of a
+// story, in the order of its sections, for walkthroughs read aloud.
+type StoryNarrator interface {
+	Narrate(ctx context.Context, input ClassificationInput, story *StoryClassification) ([]string, error)
+}
+
+// ChatTurn is a question a reader asked about a diff and the answer it got.
+type ChatTurn struct {
+	Question string
+	Answer   string
+}
+
+// DiffAnswerer answers a reader's questions about the part of a diff they
+// are looking at, given the story it belongs to and the conversation so far.
+type DiffAnswerer interface {
+	Answer(ctx context.Context, diff *Diff, story *StoryClassification, history []ChatTurn, question string) (string, error)
+}
+
+// PullRequest is a merged pull request as its hosting service records it.
+type PullRequest struct {
+	Number      int
+	Title       string
+	Body        string
+	Branch      string // Head branch the pull request merged
+	MergeCommit string // Hash of the commit the pull request landed as
+}
+
+// PullRequestFinder looks up pull requests in a hosted repository.
+type PullRequestFinder interface {
+	// PullRequest returns the merged pull request with the given number,
+	// or nil if there's no such pull request or it wasn't merged.
+	PullRequest(ctx context.Context, number int) (*PullRequest, error)
+}
+
+// HighEntropyRule names redactions of strings that match no rule but look
+// random enough to be secrets.
+const HighEntropyRule = "high-entropy"
+
+// Redaction records one piece of content scrubbed from a ClassificationInput.
+type Redaction struct {
+	Rule     string // Name of the rule that matched, or HighEntropyRule
+	Location string // Where it was: "path:line", "pr title", "pr description" or "commit <hash>"
+}
+
+// RedactionReport lists what a Redactor scrubbed from an input.
+type RedactionReport struct {
+	Redactions []Redaction
+}
+
+// HighEntropy returns how many redactions were high-entropy strings.
+func (r RedactionReport) HighEntropy() int {
+	n := 0
+	for _, red := range r.Redactions {
+		if red.Rule == HighEntropyRule {
+			n++
+		}
+	}
+	return n
+}
+
+// String summarizes the report as counts per rule in order of first
+// appearance, such as "email ×2, aws-access-key ×1".
+func (r RedactionReport) String() string {
+	var rules []string
+	counts := make(map[string]int)
+	for _, red := range r.Redactions {
+		if counts[red.Rule] == 0 {
+			rules = append(rules, red.Rule)
+		}
+		counts[red.Rule]++
+	}
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = fmt.Sprintf("%s ×%d", rule, counts[rule])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Redactor scrubs sensitive content, such as credentials and email
+// addresses, from classification input before it's sent to a classifier.
+type Redactor interface {
+	// Redact returns a copy of input with sensitive content replaced, and a
+	// report of what was replaced. The diff keeps its files and hunks.
+	Redact(input ClassificationInput) (ClassificationInput, RedactionReport)
+}
+
+// SecretDetector flags content that looks like a credential, without
+// changing it.
+type SecretDetector interface {
+	// DetectSecret returns the name of the rule content matches, or "" if
+	// it looks safe.
+	DetectSecret(content string) string
+}
@@ -82,3 +82,80 @@ func TestStoryClassification_JSONOmitsEmptyEvolution(t *testing.T) {
 		assert.Contains(t, string(data), "Changes evolved")
 	})
 }
+
+func TestClassificationInput_StripContent(t *testing.T) {
+	t.Parallel()
+
+	diff := diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				OldPath:   "secret.go",
+				NewPath:   "secret.go",
+				Operation: diffview.FileModified,
+				Extended:  []string{"index abc123..def456 100644"},
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 10, OldCount: 1, NewStart: 10, NewCount: 2,
+						Section: "func leakKey()",
+						Lines: []diffview.Line{
+							{Type: diffview.LineDeleted, Content: "key := \"hunter2\"\n", OldLineNum: 10},
+							{Type: diffview.LineAdded, Content: "key := os.Getenv(\"KEY\")\n", NewLineNum: 10},
+							{Type: diffview.LineAdded, Content: "_ = key\n", NewLineNum: 11},
+						},
+					},
+				},
+			},
+		},
+	}
+	input := diffview.ClassificationInput{
+		Repo:          "repo",
+		Branch:        "fix-key",
+		PRTitle:       "Stop hardcoding hunter2",
+		PRDescription: "The key was hunter2",
+		Commits:       []diffview.CommitBrief{{Hash: "abc123", Message: "Remove hunter2", Diff: &diff}},
+		Diff:          diff,
+	}
+
+	stripped := input.StripContent()
+
+	t.Run("marks input as paths-only and keeps identity", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, stripped.PathsOnly)
+		assert.Equal(t, input.ContentID(), stripped.ContentID())
+		assert.Equal(t, "abc123", stripped.FirstCommitHash())
+	})
+
+	t.Run("keeps paths, hunk ranges, and stats", func(t *testing.T) {
+		t.Parallel()
+
+		require.Len(t, stripped.Diff.Files, 1)
+		file := stripped.Diff.Files[0]
+		assert.Equal(t, "secret.go", file.NewPath)
+		require.Len(t, file.Hunks, 1)
+		assert.Equal(t, 10, file.Hunks[0].NewStart)
+		assert.Equal(t, 2, file.Hunks[0].NewCount)
+		added, deleted := file.Stats()
+		assert.Equal(t, 2, added)
+		assert.Equal(t, 1, deleted)
+	})
+
+	t.Run("removes all code and prose content", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(stripped)
+		require.NoError(t, err)
+
+		assert.NotContains(t, string(data), "hunter2")
+		assert.NotContains(t, string(data), "leakKey")
+		assert.NotContains(t, string(data), "index abc123")
+		assert.Contains(t, string(data), `"paths_only":true`)
+	})
+
+	t.Run("does not modify the original", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "func leakKey()", input.Diff.Files[0].Hunks[0].Section)
+		assert.Equal(t, "key := \"hunter2\"\n", input.Commits[0].Diff.Files[0].Hunks[0].Lines[0].Content)
+	})
+}
@@ -2,14 +2,20 @@
 package clipboard
 
 import (
+	"bytes"
 	"os/exec"
+	"runtime"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/fwojciec/diffstory"
 )
 
-// Ensure PBCopy implements the Clipboard interface.
-var _ diffview.Clipboard = (*PBCopy)(nil)
+// Ensure clipboard backends implement the Clipboard interface.
+var (
+	_ diffview.Clipboard = (*PBCopy)(nil)
+	_ diffview.Clipboard = (*Command)(nil)
+)
 
 // PBCopy implements Clipboard using macOS pbcopy command.
 type PBCopy struct{}
@@ -25,3 +31,67 @@ func (p *PBCopy) Copy(content string) error {
 	cmd.Stdin = strings.NewReader(content)
 	return cmd.Run()
 }
+
+// Command implements Clipboard by piping content to an external command.
+type Command struct {
+	Name string
+	Args []string
+	// UTF16 encodes content as UTF-16LE with a byte order mark, which
+	// Windows clip.exe needs to preserve non-ASCII text.
+	UTF16 bool
+}
+
+// Copy writes content to the clipboard command's stdin.
+func (c *Command) Copy(content string) error {
+	cmd := exec.Command(c.Name, c.Args...)
+	if c.UTF16 {
+		cmd.Stdin = bytes.NewReader(EncodeUTF16LE(content))
+	} else {
+		cmd.Stdin = strings.NewReader(content)
+	}
+	return cmd.Run()
+}
+
+// ForPlatform returns the clipboard backend for goos, using lookPath to
+// check which commands are installed. Returns nil if no backend is found.
+func ForPlatform(goos string, lookPath func(file string) (string, error)) *Command {
+	var candidates []Command
+	switch goos {
+	case "darwin":
+		candidates = []Command{{Name: "pbcopy"}}
+	case "windows":
+		candidates = []Command{{Name: "clip.exe", UTF16: true}}
+	default:
+		candidates = []Command{
+			{Name: "wl-copy"},
+			{Name: "xclip", Args: []string{"-selection", "clipboard"}},
+			{Name: "xsel", Args: []string{"--clipboard", "--input"}},
+		}
+	}
+	for _, c := range candidates {
+		if _, err := lookPath(c.Name); err == nil {
+			return &c
+		}
+	}
+	return nil
+}
+
+// NewSystem returns the clipboard backend for the current platform,
+// or nil if no clipboard command is available.
+func NewSystem() diffview.Clipboard {
+	if c := ForPlatform(runtime.GOOS, exec.LookPath); c != nil {
+		return c
+	}
+	return nil
+}
+
+// EncodeUTF16LE encodes s as UTF-16LE prefixed with a byte order mark.
+func EncodeUTF16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 0, 2+2*len(units))
+	out = append(out, 0xff, 0xfe)
+	for _, u := range units {
+		out = append(out, byte(u), byte(u>>8))
+	}
+	return out
+}
@@ -32,3 +32,59 @@ func TestPBCopy_Copy(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, testContent, string(out))
 }
+
+func TestForPlatform(t *testing.T) {
+	t.Parallel()
+
+	available := func(names ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			for _, n := range names {
+				if n == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", exec.ErrNotFound
+		}
+	}
+
+	t.Run("uses pbcopy on macOS", func(t *testing.T) {
+		t.Parallel()
+
+		c := clipboard.ForPlatform("darwin", available("pbcopy"))
+
+		require.NotNil(t, c)
+		assert.Equal(t, "pbcopy", c.Name)
+	})
+
+	t.Run("uses clip.exe with UTF-16 on Windows", func(t *testing.T) {
+		t.Parallel()
+
+		c := clipboard.ForPlatform("windows", available("clip.exe"))
+
+		require.NotNil(t, c)
+		assert.Equal(t, "clip.exe", c.Name)
+		assert.True(t, c.UTF16)
+	})
+
+	t.Run("prefers first available Linux backend", func(t *testing.T) {
+		t.Parallel()
+
+		c := clipboard.ForPlatform("linux", available("xsel", "xclip"))
+
+		require.NotNil(t, c)
+		assert.Equal(t, "xclip", c.Name)
+		assert.Equal(t, []string{"-selection", "clipboard"}, c.Args)
+	})
+
+	t.Run("returns nil when no backend is installed", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, clipboard.ForPlatform("linux", available()))
+	})
+}
+
+func TestEncodeUTF16LE(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []byte{0xff, 0xfe, 'h', 0, 0xe9, 0, '\n', 0}, clipboard.EncodeUTF16LE("hé\n"))
+}
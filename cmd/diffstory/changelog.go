@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gitdiff"
+)
+
+// ChangelogEntry is one commit's line in a changelog.
+type ChangelogEntry struct {
+	Hash       string
+	ChangeType string
+	Summary    string
+}
+
+// ChangelogApp classifies each commit in a range for a changelog.
+type ChangelogApp struct {
+	GitRunner  diffview.GitRunner
+	RepoPath   string
+	Range      string
+	Classifier diffview.StoryClassifier
+}
+
+// Run classifies each commit in the range on its own diff and returns a
+// changelog entry per commit, newest first. Commits without changes, such as
+// merges, are left out.
+func (a *ChangelogApp) Run(ctx context.Context) ([]ChangelogEntry, error) {
+	base, head, err := ParseRange(a.Range)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := a.GitRunner.CommitsInRange(ctx, a.RepoPath, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := gitdiff.NewParser()
+	var entries []ChangelogEntry
+	for _, c := range commits {
+		diffStr, err := a.GitRunner.Diff(ctx, a.RepoPath, c.Hash+"^!")
+		if err != nil {
+			return nil, err
+		}
+		diff, err := parser.Parse(strings.NewReader(diffStr))
+		if err != nil {
+			return nil, err
+		}
+		if len(diff.Files) == 0 {
+			continue
+		}
+		story, err := a.Classifier.Classify(ctx, diffview.ClassificationInput{
+			Commits: []diffview.CommitBrief{c},
+			Diff:    *diff,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("classifying commit %s: %w", shortHash(c.Hash), err)
+		}
+		entry := ChangelogEntry{Hash: c.Hash, ChangeType: story.ChangeType, Summary: story.Summary}
+		if entry.Summary == "" {
+			entry.Summary = c.Message
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, ErrNoChanges
+	}
+	return entries, nil
+}
+
+// WriteChangelog writes entries as a conventional-changelog release section
+// headed title, grouped by change type. Each entry links its commit through
+// commitURL, or names it in plain text if commitURL is nil.
+func WriteChangelog(w io.Writer, title string, entries []ChangelogEntry, commitURL func(hash string) string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s\n", title)
+
+	grouped := make(map[string][]ChangelogEntry)
+	for _, e := range entries {
+		grouped[e.ChangeType] = append(grouped[e.ChangeType], e)
+	}
+	writeGroup := func(heading string, group []ChangelogEntry) {
+		if len(group) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n", heading)
+		for _, e := range group {
+			ref := shortHash(e.Hash)
+			if commitURL != nil {
+				ref = fmt.Sprintf("[%s](%s)", ref, commitURL(e.Hash))
+			}
+			fmt.Fprintf(&sb, "* %s (%s)\n", e.Summary, ref)
+		}
+	}
+
+	// The headings for each change type, in the order they're written. Any
+	// other change type goes under "Other Changes".
+	groups := [...]struct {
+		changeType string
+		heading    string
+	}{
+		{"feature", "Features"},
+		{"bugfix", "Bug Fixes"},
+		{"refactor", "Refactoring"},
+		{"docs", "Documentation"},
+		{"chore", "Chores"},
+	}
+	var other []ChangelogEntry
+	known := make(map[string]bool)
+	for _, g := range groups {
+		writeGroup(g.heading, grouped[g.changeType])
+		known[g.changeType] = true
+	}
+	for _, e := range entries {
+		if !known[e.ChangeType] {
+			other = append(other, e)
+		}
+	}
+	writeGroup("Other Changes", other)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// AddToChangelog returns the changelog existing with release added above
+// earlier releases, below the document's title if it has one. An empty
+// changelog gets a "# Changelog" title.
+func AddToChangelog(existing, release string) string {
+	if strings.TrimSpace(existing) == "" {
+		return "# Changelog\n\n" + release
+	}
+	if !strings.HasPrefix(existing, "# ") {
+		return release + "\n" + existing
+	}
+	title, rest, _ := strings.Cut(existing, "\n")
+	rest = strings.TrimLeft(rest, "\n")
+	if rest == "" {
+		return title + "\n\n" + release
+	}
+	return title + "\n\n" + release + "\n" + rest
+}
+
+// shortHash abbreviates a commit hash the way git log --oneline does.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
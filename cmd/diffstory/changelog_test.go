@@ -0,0 +1,135 @@
+package main_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	main "github.com/fwojciec/diffstory/cmd/diffstory"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogApp_Run_ClassifiesEachCommit(t *testing.T) {
+	t.Parallel()
+
+	diffs := map[string]string{
+		"aaaaaaa1^!": "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n",
+		"bbbbbbb2^!": "",
+		"ccccccc3^!": "diff --git a/c.go b/c.go\n--- a/c.go\n+++ b/c.go\n@@ -1 +1 @@\n-old\n+new\n",
+	}
+	app := &main.ChangelogApp{
+		GitRunner: &mock.GitRunner{
+			CommitsInRangeFn: func(_ context.Context, _, base, head string) ([]diffview.CommitBrief, error) {
+				assert.Equal(t, "v1.0.0", base)
+				assert.Equal(t, "HEAD", head)
+				return []diffview.CommitBrief{
+					{Hash: "aaaaaaa1", Message: "Add a"},
+					{Hash: "bbbbbbb2", Message: "Merge branch"},
+					{Hash: "ccccccc3", Message: "Fix c"},
+				}, nil
+			},
+			DiffFn: func(_ context.Context, _, rangeSpec string) (string, error) {
+				return diffs[rangeSpec], nil
+			},
+		},
+		RepoPath: "/repo",
+		Range:    "v1.0.0..HEAD",
+		Classifier: &mock.StoryClassifier{
+			ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				require.Len(t, input.Commits, 1)
+				require.Len(t, input.Diff.Files, 1)
+				if input.Commits[0].Hash == "aaaaaaa1" {
+					return &diffview.StoryClassification{ChangeType: "feature", Summary: "Add a to the pipeline"}, nil
+				}
+				return &diffview.StoryClassification{ChangeType: "bugfix"}, nil
+			},
+		},
+	}
+
+	entries, err := app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []main.ChangelogEntry{
+		{Hash: "aaaaaaa1", ChangeType: "feature", Summary: "Add a to the pipeline"},
+		{Hash: "ccccccc3", ChangeType: "bugfix", Summary: "Fix c"},
+	}, entries, "merge without changes is left out; missing summary falls back to the subject")
+}
+
+func TestChangelogApp_Run_RejectsInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	app := &main.ChangelogApp{GitRunner: &mock.GitRunner{}, Range: "HEAD"}
+
+	_, err := app.Run(context.Background())
+	assert.ErrorIs(t, err, main.ErrInvalidRange)
+}
+
+func TestWriteChangelog_GroupsByChangeType(t *testing.T) {
+	t.Parallel()
+
+	entries := []main.ChangelogEntry{
+		{Hash: "1111111aaaa", ChangeType: "bugfix", Summary: "Fix crash on empty diff"},
+		{Hash: "2222222bbbb", ChangeType: "feature", Summary: "Add changelog mode"},
+		{Hash: "3333333cccc", ChangeType: "perf", Summary: "Speed up parsing"},
+		{Hash: "4444444dddd", ChangeType: "feature", Summary: "Add slides export"},
+	}
+
+	var buf bytes.Buffer
+	err := main.WriteChangelog(&buf, "v1.0.0..HEAD (2026-10-15)", entries, func(hash string) string {
+		return "https://github.com/o/r/commit/" + hash
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `## v1.0.0..HEAD (2026-10-15)
+
+### Features
+
+* Add changelog mode ([2222222](https://github.com/o/r/commit/2222222bbbb))
+* Add slides export ([4444444](https://github.com/o/r/commit/4444444dddd))
+
+### Bug Fixes
+
+* Fix crash on empty diff ([1111111](https://github.com/o/r/commit/1111111aaaa))
+
+### Other Changes
+
+* Speed up parsing ([3333333](https://github.com/o/r/commit/3333333cccc))
+`, buf.String())
+}
+
+func TestWriteChangelog_WithoutCommitURLs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := main.WriteChangelog(&buf, "HEAD~1..HEAD", []main.ChangelogEntry{
+		{Hash: "1111111aaaa", ChangeType: "docs", Summary: "Document changelog mode"},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "* Document changelog mode (1111111)\n")
+}
+
+func TestAddToChangelog(t *testing.T) {
+	t.Parallel()
+
+	const release = "## v2\n\n### Features\n\n* New\n"
+
+	t.Run("creates a titled changelog", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "# Changelog\n\n"+release, main.AddToChangelog("", release))
+	})
+
+	t.Run("adds above earlier releases below the title", func(t *testing.T) {
+		t.Parallel()
+		existing := "# Changelog\n\n## v1\n\n* Old\n"
+		assert.Equal(t, "# Changelog\n\n"+release+"\n## v1\n\n* Old\n", main.AddToChangelog(existing, release))
+	})
+
+	t.Run("adds at the top without a title", func(t *testing.T) {
+		t.Parallel()
+		existing := "## v1\n\n* Old\n"
+		assert.Equal(t, release+"\n"+existing, main.AddToChangelog(existing, release))
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fwojciec/diffstory"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPolicyFile is the policy diffstory check reads, at the top of the
+// repository, when not given one.
+const DefaultPolicyFile = ".diffstory-policy.yaml"
+
+// ErrPolicyViolated is returned when a story breaks its policy, failing
+// the hook or CI step running diffstory check.
+var ErrPolicyViolated = errors.New("story check failed")
+
+// DefaultPolicy is the policy of repositories without a policy file: core
+// changes come with tests and at most half of a change is noise.
+func DefaultPolicy() diffview.Policy {
+	maxNoise := 0.5
+	return diffview.Policy{RequireTests: true, MaxNoiseRatio: &maxNoise}
+}
+
+// ParsePolicy reads a policy from YAML, rejecting unknown rules so a typo
+// doesn't turn one off.
+func ParsePolicy(r io.Reader) (diffview.Policy, error) {
+	var p diffview.Policy
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && err != io.EOF {
+		return diffview.Policy{}, fmt.Errorf("invalid policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return diffview.Policy{}, fmt.Errorf("invalid policy: %w", err)
+	}
+	return p, nil
+}
+
+// LoadPolicy reads a policy from the YAML file at path.
+func LoadPolicy(path string) (diffview.Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return diffview.Policy{}, err
+	}
+	defer f.Close()
+
+	p, err := ParsePolicy(f)
+	if err != nil {
+		return diffview.Policy{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// WriteCheckReport writes the policy violations to w, one per line, or that
+// the story passed, and returns ErrPolicyViolated if there are any.
+func WriteCheckReport(w io.Writer, violations []diffview.PolicyViolation) error {
+	if len(violations) == 0 {
+		_, err := fmt.Fprintln(w, "✓ story passes the policy")
+		return err
+	}
+	for _, v := range violations {
+		if _, err := fmt.Fprintf(w, "✗ %s\n", v); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %d violation(s)", ErrPolicyViolated, len(violations))
+}
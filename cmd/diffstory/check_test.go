@@ -0,0 +1,121 @@
+package main_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	main "github.com/fwojciec/diffstory/cmd/diffstory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := main.ParsePolicy(strings.NewReader(`
+require_tests: true
+max_noise_ratio: 0.3
+max_risk: medium
+forbid_breaking: true
+required_roles: [core]
+`))
+
+	require.NoError(t, err)
+	require.NotNil(t, policy.MaxNoiseRatio)
+	assert.InDelta(t, 0.3, *policy.MaxNoiseRatio, 1e-9)
+	policy.MaxNoiseRatio = nil
+	assert.Equal(t, diffview.Policy{
+		RequireTests:   true,
+		MaxRisk:        "medium",
+		ForbidBreaking: true,
+		RequiredRoles:  []string{"core"},
+	}, policy)
+}
+
+func TestParsePolicy_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unknown rule", "require_test: true\n", "field require_test not found"},
+		{"bad risk", "max_risk: severe\n", `unknown max_risk "severe"`},
+		{"bad ratio", "max_noise_ratio: 2\n", "max_noise_ratio 2 is out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := main.ParsePolicy(strings.NewReader(tt.input))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestParsePolicy_Empty(t *testing.T) {
+	t.Parallel()
+
+	policy, err := main.ParsePolicy(strings.NewReader(""))
+
+	require.NoError(t, err)
+	assert.Equal(t, diffview.Policy{}, policy)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, main.DefaultPolicyFile)
+	require.NoError(t, os.WriteFile(path, []byte("max_risk: low\nbogus: 1\n"), 0o644))
+
+	_, err := main.LoadPolicy(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path)
+
+	_, err = main.LoadPolicy(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestWriteCheckReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("violations", func(t *testing.T) {
+		t.Parallel()
+
+		var sb strings.Builder
+		err := main.WriteCheckReport(&sb, []diffview.PolicyViolation{
+			{Rule: "require_tests", Message: "no test section"},
+			{Rule: "max_risk", Message: "too risky"},
+		})
+
+		assert.ErrorIs(t, err, main.ErrPolicyViolated)
+		assert.EqualError(t, err, "story check failed: 2 violation(s)")
+		assert.Equal(t, "✗ require_tests: no test section\n✗ max_risk: too risky\n", sb.String())
+	})
+
+	t.Run("pass", func(t *testing.T) {
+		t.Parallel()
+
+		var sb strings.Builder
+		err := main.WriteCheckReport(&sb, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "✓ story passes the policy\n", sb.String())
+	})
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := main.DefaultPolicy()
+
+	assert.True(t, policy.RequireTests)
+	require.NoError(t, policy.Validate())
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/fwojciec/diffstory/github"
+	"github.com/fwojciec/diffstory/lint"
+	"github.com/fwojciec/diffstory/sarif"
+	"github.com/fwojciec/diffstory/todo"
+)
+
+// ciFindingLevel returns the level of the notes of the annotator named
+// source in CI output: notices for reminders, such as todos, and warnings
+// for problems.
+func ciFindingLevel(source string) diffview.FindingLevel {
+	if source == "todo" {
+		return diffview.FindingNotice
+	}
+	return diffview.FindingWarning
+}
+
+// CIApp gathers findings on a change for a CI check: its annotators'
+// notes and the risk the classifier sees in it.
+type CIApp struct {
+	GitRunner  diffview.GitRunner
+	RepoPath   string
+	BaseBranch string                   // Base branch, diffed against HEAD when Range is empty
+	Range      string                   // Raw commit range, such as "origin/main...HEAD"
+	Classifier diffview.StoryClassifier // Assesses the change's risk (optional)
+	Annotators []diffview.Annotator
+}
+
+// Run returns the findings on the change: each annotator's notes in turn,
+// then the risk, if the classifier assessed it.
+func (a *CIApp) Run(ctx context.Context) ([]diffview.Finding, error) {
+	var diffStr string
+	var err error
+	if a.Range != "" {
+		diffStr, err = a.GitRunner.Diff(ctx, a.RepoPath, a.Range)
+	} else {
+		diffStr, err = a.GitRunner.DiffRange(ctx, a.RepoPath, a.BaseBranch, "HEAD")
+	}
+	if err != nil {
+		return nil, err
+	}
+	diff, err := gitdiff.NewParser().Parse(strings.NewReader(diffStr))
+	if err != nil {
+		return nil, err
+	}
+	if len(diff.Files) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	var findings []diffview.Finding
+	for _, annotator := range a.Annotators {
+		for _, note := range annotator.Annotate(diff) {
+			if note.Source == "" {
+				note.Source = annotator.Name()
+			}
+			findings = append(findings, diffview.AnnotationFinding(diff, note, ciFindingLevel(note.Source)))
+		}
+	}
+	if a.Classifier != nil {
+		story, err := a.Classifier.Classify(ctx, diffview.ClassificationInput{Diff: *diff})
+		if err != nil {
+			return nil, fmt.Errorf("classifying change: %w", err)
+		}
+		if story.Risk != nil {
+			findings = append(findings, diffview.RiskFinding(story.Risk))
+		}
+	}
+	return findings, nil
+}
+
+// WriteFindings writes findings to w in format: "github" for GitHub Actions
+// workflow commands or "sarif" for a SARIF log.
+func WriteFindings(w io.Writer, format string, findings []diffview.Finding) error {
+	switch format {
+	case "github":
+		return github.WriteWorkflowCommands(w, findings)
+	case "sarif":
+		return sarif.Write(w, sarif.Tool{
+			Name:           "diffstory",
+			InformationURI: "https://github.com/fwojciec/diffstory",
+		}, findings)
+	default:
+		return fmt.Errorf("unknown format %q (available: github, sarif)", format)
+	}
+}
+
+// ciAnnotators returns the annotators named in a comma-separated list of
+// todo, vet and staticcheck, with the linters in custom, each
+// "name=command", run together in dir.
+func ciAnnotators(list string, custom []string, dir string) ([]diffview.Annotator, error) {
+	var annotators []diffview.Annotator
+	var linters []lint.Linter
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+		case "todo":
+			annotators = append(annotators, todo.NewAnnotator())
+		case lint.Vet().Name, lint.Staticcheck().Name:
+			linter, _ := lint.ParseLinter(name)
+			linters = append(linters, linter)
+		default:
+			return nil, fmt.Errorf("unknown annotator %q (available: todo, vet, staticcheck)", name)
+		}
+	}
+	for _, spec := range custom {
+		linter, err := lint.ParseLinter(spec)
+		if err != nil {
+			return nil, err
+		}
+		linters = append(linters, linter)
+	}
+	if len(linters) > 0 {
+		annotators = append(annotators, lint.NewAnnotator(dir, linters))
+	}
+	return annotators, nil
+}
@@ -0,0 +1,122 @@
+package main_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	main "github.com/fwojciec/diffstory/cmd/diffstory"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIApp_Run(t *testing.T) {
+	t.Parallel()
+
+	app := &main.CIApp{
+		GitRunner: &mock.GitRunner{
+			DiffFn: func(_ context.Context, _, rangeSpec string) (string, error) {
+				assert.Equal(t, "origin/main...HEAD", rangeSpec)
+				return "diff --git a/api.go b/api.go\n--- a/api.go\n+++ b/api.go\n@@ -1 +1,2 @@\n-old\n+new\n+// TODO: tidy\n", nil
+			},
+		},
+		RepoPath: "/repo",
+		Range:    "origin/main...HEAD",
+		Classifier: &mock.StoryClassifier{
+			ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				require.Len(t, input.Diff.Files, 1)
+				return &diffview.StoryClassification{Risk: &diffview.Risk{Level: "medium", Breaking: []string{"Renames Parse"}}}, nil
+			},
+		},
+		Annotators: []diffview.Annotator{
+			&mock.Annotator{
+				NameFn: func() string { return "todo" },
+				AnnotateFn: func(diff *diffview.Diff) []diffview.Annotation {
+					return []diffview.Annotation{{Path: "api.go", Hunk: 1, Line: 2, Text: "TODO: tidy"}}
+				},
+			},
+			&mock.Annotator{
+				NameFn: func() string { return "lint" },
+				AnnotateFn: func(diff *diffview.Diff) []diffview.Annotation {
+					return []diffview.Annotation{{Path: "api.go", Hunk: 1, Text: "unused result", Source: "vet"}}
+				},
+			},
+		},
+	}
+
+	findings, err := app.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []diffview.Finding{
+		{Rule: "todo", Level: diffview.FindingNotice, Message: "TODO: tidy", Path: "api.go", StartLine: 2, EndLine: 2},
+		{Rule: "vet", Level: diffview.FindingWarning, Message: "unused result", Path: "api.go", StartLine: 1, EndLine: 2},
+		{Rule: "risk", Level: diffview.FindingWarning, Title: "medium risk", Message: "Risk: medium\nBreaking:\n- Renames Parse"},
+	}, findings)
+}
+
+func TestCIApp_Run_WithoutClassifier(t *testing.T) {
+	t.Parallel()
+
+	app := &main.CIApp{
+		GitRunner: &mock.GitRunner{
+			DiffRangeFn: func(_ context.Context, _, base, head string) (string, error) {
+				assert.Equal(t, "main", base)
+				assert.Equal(t, "HEAD", head)
+				return "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n", nil
+			},
+		},
+		BaseBranch: "main",
+	}
+
+	findings, err := app.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestCIApp_Run_EmptyDiff(t *testing.T) {
+	t.Parallel()
+
+	app := &main.CIApp{
+		GitRunner: &mock.GitRunner{
+			DiffFn: func(_ context.Context, _, _ string) (string, error) { return "", nil },
+		},
+		Range: "a..b",
+	}
+
+	_, err := app.Run(context.Background())
+
+	assert.ErrorIs(t, err, main.ErrNoChanges)
+}
+
+func TestWriteFindings(t *testing.T) {
+	t.Parallel()
+
+	findings := []diffview.Finding{{Rule: "vet", Level: diffview.FindingWarning, Message: "oops", Path: "a.go", StartLine: 3, EndLine: 3}}
+
+	t.Run("github", func(t *testing.T) {
+		t.Parallel()
+
+		var sb strings.Builder
+		require.NoError(t, main.WriteFindings(&sb, "github", findings))
+		assert.Equal(t, "::warning file=a.go,line=3,title=vet::oops\n", sb.String())
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		t.Parallel()
+
+		var sb strings.Builder
+		require.NoError(t, main.WriteFindings(&sb, "sarif", findings))
+		assert.Contains(t, sb.String(), `"ruleId": "vet"`)
+		assert.Contains(t, sb.String(), `"name": "diffstory"`)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		err := main.WriteFindings(&strings.Builder{}, "junit", findings)
+		assert.EqualError(t, err, `unknown format "junit" (available: github, sarif)`)
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// commitBodyWidth is the column commit message bodies wrap at, as git log
+// and most editors expect.
+const commitBodyWidth = 72
+
+// CommitMessage suggests a commit message for story: its summary as the
+// subject, then a bullet per section giving the section's title and
+// explanation.
+func CommitMessage(story *diffview.StoryClassification) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(story.Summary), "\n")
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSuffix(subject, "."))
+	sb.WriteString("\n")
+	if len(story.Sections) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, section := range story.Sections {
+		bullet := section.Title
+		if section.Explanation != "" {
+			bullet += ": " + section.Explanation
+		}
+		sb.WriteString(wrapText(bullet, "- ", "  ", commitBodyWidth))
+	}
+	return sb.String()
+}
+
+// AddCommitMessage returns the commit message file existing with msg
+// written above git's comment lines. A message already in the file, as
+// from git commit -m or an amend, is kept as it is.
+func AddCommitMessage(existing, msg string) string {
+	for _, line := range strings.Split(existing, "\n") {
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "#") {
+			return existing
+		}
+	}
+	comments := strings.TrimLeft(existing, "\n")
+	if comments == "" {
+		return msg
+	}
+	return msg + "\n" + comments
+}
+
+// wrapText word-wraps text to width, starting the first line with first
+// and the rest with indent.
+func wrapText(text, first, indent string, width int) string {
+	var sb strings.Builder
+	line := first
+	lineEmpty := true
+	for _, word := range strings.Fields(text) {
+		if !lineEmpty && len(line)+1+len(word) > width {
+			sb.WriteString(line + "\n")
+			line, lineEmpty = indent, true
+		}
+		if !lineEmpty {
+			line += " "
+		}
+		line += word
+		lineEmpty = false
+	}
+	sb.WriteString(line + "\n")
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	main "github.com/fwojciec/diffstory/cmd/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitMessage(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		ChangeType: "bugfix",
+		Summary:    "Stop the parser from dropping the last hunk of a file.",
+		Sections: []diffview.Section{
+			{Role: "fix", Title: "Flush the pending hunk", Explanation: "The parser only emitted a hunk when the next one started, so a file's last hunk was lost at end of input."},
+			{Role: "test", Title: "Cover files ending mid-hunk"},
+		},
+	}
+
+	assert.Equal(t, `Stop the parser from dropping the last hunk of a file
+
+- Flush the pending hunk: The parser only emitted a hunk when the next
+  one started, so a file's last hunk was lost at end of input.
+- Cover files ending mid-hunk
+`, main.CommitMessage(story))
+}
+
+func TestCommitMessage_WithoutSections(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{Summary: "Bump dependencies"}
+
+	assert.Equal(t, "Bump dependencies\n", main.CommitMessage(story))
+}
+
+func TestAddCommitMessage(t *testing.T) {
+	t.Parallel()
+
+	const msg = "Fix parser\n\n- Flush the pending hunk\n"
+
+	t.Run("writes above git's comments", func(t *testing.T) {
+		t.Parallel()
+		existing := "\n# Please enter the commit message for your changes.\n"
+		assert.Equal(t, msg+"\n# Please enter the commit message for your changes.\n",
+			main.AddCommitMessage(existing, msg))
+	})
+
+	t.Run("writes an empty file", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, msg, main.AddCommitMessage("", msg))
+	})
+
+	t.Run("keeps a message already given", func(t *testing.T) {
+		t.Parallel()
+		existing := "Fix typo\n# Please enter the commit message for your changes.\n"
+		assert.Equal(t, existing, main.AddCommitMessage(existing, msg))
+	})
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Exported casts show each frame for castFrameSeconds on a terminal of
+// castWidth by castHeight.
+const (
+	castFrameSeconds = 5
+	castWidth        = 120
+	castHeight       = 36
+)
+
+// WriteSlides writes story as a Marp-compatible Markdown slide deck: an
+// intro slide with the summary, impact and likely reviewers, a slide on its
+// risk if assessed, then a slide per section with its explanation and the
+// section's hunks from diff. Narration becomes the presenter notes.
+func WriteSlides(w io.Writer, diff *diffview.Diff, story *diffview.StoryClassification, reviewers []diffview.Reviewer) error {
+	var sb strings.Builder
+	sb.WriteString("---\nmarp: true\npaginate: true\n---\n\n")
+
+	fmt.Fprintf(&sb, "# %s\n\n", story.Summary)
+	if story.ChangeType != "" {
+		fmt.Fprintf(&sb, "**%s**", story.ChangeType)
+		if story.Narrative != "" {
+			fmt.Fprintf(&sb, " · %s", story.Narrative)
+		}
+		sb.WriteString("\n\n")
+	}
+	for i, section := range story.Sections {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, section.Title)
+	}
+	if impact := story.Impact; impact != nil {
+		fmt.Fprintf(&sb, "\nImpact: `%s`, %s version bump\n", impact.ConventionalType(), impact.Bump)
+		if len(impact.Breaking) > 0 {
+			fmt.Fprintf(&sb, "\nBreaking: `%s`\n", strings.Join(impact.Breaking, "`, `"))
+		}
+		if len(impact.Added) > 0 {
+			fmt.Fprintf(&sb, "\nAdded: `%s`\n", strings.Join(impact.Added, "`, `"))
+		}
+	}
+	if len(reviewers) > 0 {
+		names := make([]string, len(reviewers))
+		for i, r := range reviewers {
+			names[i] = r.String()
+		}
+		fmt.Fprintf(&sb, "\nLikely reviewers: %s\n", strings.Join(names, ", "))
+	}
+
+	if story.Risk != nil {
+		sb.WriteString("\n---\n\n")
+		writeRisk(&sb, story.Risk)
+	}
+
+	hunks := hunksByRef(diff)
+	for i, section := range story.Sections {
+		fmt.Fprintf(&sb, "\n---\n\n## %d. %s\n\n", i+1, section.Title)
+		if section.Role != "" {
+			fmt.Fprintf(&sb, "*%s*\n\n", section.Role)
+		}
+		if section.Explanation != "" {
+			fmt.Fprintf(&sb, "%s\n\n", section.Explanation)
+		}
+		for _, ref := range section.Hunks {
+			hunk, ok := hunks[diffview.HunkRef{File: ref.File, HunkIndex: ref.HunkIndex}]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&sb, "`%s`\n\n", ref.File)
+			writeHunkBlock(&sb, hunk)
+		}
+		if section.Narration != "" {
+			// Marp shows comments as presenter notes
+			fmt.Fprintf(&sb, "<!-- %s -->\n", strings.ReplaceAll(section.Narration, "-->", "-- >"))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeRisk writes a slide on risk: its level, then lists of what breaks,
+// the migration steps and the public APIs affected.
+func writeRisk(sb *strings.Builder, risk *diffview.Risk) {
+	fmt.Fprintf(sb, "## Risk: %s\n", risk.Level)
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(sb, "\n**%s**\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(sb, "- %s\n", item)
+		}
+	}
+	list("Breaking changes", risk.Breaking)
+	list("Migration", risk.Migration)
+	list("Affected APIs", risk.AffectedAPIs)
+}
+
+// hunksByRef indexes the hunks of diff by the file and hunk index that
+// story sections refer to them by.
+func hunksByRef(diff *diffview.Diff) map[diffview.HunkRef]diffview.Hunk {
+	hunks := make(map[diffview.HunkRef]diffview.Hunk)
+	for _, file := range diff.Files {
+		for i, h := range file.Hunks {
+			hunks[diffview.HunkRef{File: file.Path(), HunkIndex: i}] = h
+		}
+	}
+	return hunks
+}
+
+// writeHunkBlock writes hunk as a fenced diff code block, with a fence
+// longer than any run of backticks in the code.
+func writeHunkBlock(sb *strings.Builder, hunk diffview.Hunk) {
+	var body strings.Builder
+	fmt.Fprintf(&body, "@@ -%d,%d +%d,%d @@", hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount)
+	if hunk.Section != "" {
+		fmt.Fprintf(&body, " %s", hunk.Section)
+	}
+	body.WriteString("\n")
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case diffview.LineAdded:
+			body.WriteString("+")
+		case diffview.LineDeleted:
+			body.WriteString("-")
+		default:
+			body.WriteString(" ")
+		}
+		body.WriteString(line.Content)
+		body.WriteString("\n")
+	}
+
+	fence := "```"
+	for strings.Contains(body.String(), fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(sb, "%sdiff\n%s%s\n\n", fence, body.String(), fence)
+}
+
+// WriteCast writes frames as an asciicast v2 recording for a width by
+// height terminal, showing each frame for castFrameSeconds, so it can be
+// played back with asciinema.
+func WriteCast(w io.Writer, frames []string, width, height int, title string) error {
+	enc := json.NewEncoder(w)
+	header := struct {
+		Version int    `json:"version"`
+		Width   int    `json:"width"`
+		Height  int    `json:"height"`
+		Title   string `json:"title,omitempty"`
+	}{2, width, height, title}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+	for i, frame := range frames {
+		// Terminals need a carriage return to start each line at the left.
+		data := "\x1b[H\x1b[2J" + strings.ReplaceAll(frame, "\n", "\r\n")
+		if err := enc.Encode([]any{i * castFrameSeconds, "o", data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	main "github.com/fwojciec/diffstory/cmd/diffstory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSlides(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				NewPath:   "b/auth.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{
+						OldStart: 10, OldCount: 2, NewStart: 10, NewCount: 2, Section: "func Login()",
+						Lines: []diffview.Line{
+							{Type: diffview.LineContext, Content: "\tuser := lookup()"},
+							{Type: diffview.LineDeleted, Content: "\treturn nil"},
+							{Type: diffview.LineAdded, Content: "\treturn check(user)"},
+						},
+					},
+				},
+			},
+		},
+	}
+	story := &diffview.StoryClassification{
+		ChangeType: "bugfix",
+		Narrative:  "cause-effect",
+		Summary:    "Check credentials on login",
+		Risk: &diffview.Risk{
+			Level:        "high",
+			Breaking:     []string{"Login rejects unknown users"},
+			AffectedAPIs: []string{"Login"},
+		},
+		Impact: &diffview.Impact{CommitType: "fix", Bump: "major", Breaking: []string{"auth.Login"}},
+		Sections: []diffview.Section{
+			{
+				Role:        "fix",
+				Title:       "Validate the user",
+				Explanation: "Login now checks the user it finds.",
+				Narration:   "Here login starts checking the user.",
+				Hunks:       []diffview.HunkRef{{File: "auth.go", HunkIndex: 0}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	reviewers := []diffview.Reviewer{{Name: "@org/auth", Owner: true}, {Name: "Ann"}}
+	require.NoError(t, main.WriteSlides(&buf, diff, story, reviewers))
+
+	slides := strings.Split(buf.String(), "\n---\n")
+	require.Len(t, slides, 4)
+	assert.Contains(t, slides[0], "marp: true")
+	assert.Contains(t, slides[1], "# Check credentials on login")
+	assert.Contains(t, slides[1], "**bugfix** · cause-effect")
+	assert.Contains(t, slides[1], "1. Validate the user")
+	assert.Contains(t, slides[1], "Impact: `fix!`, major version bump")
+	assert.Contains(t, slides[1], "Breaking: `auth.Login`")
+	assert.Contains(t, slides[1], "Likely reviewers: @org/auth (owner), Ann")
+	assert.Equal(t, "\n## Risk: high\n\n**Breaking changes**\n\n- Login rejects unknown users\n\n**Affected APIs**\n\n- Login\n", slides[2])
+	assert.Contains(t, slides[3], "## 1. Validate the user")
+	assert.Contains(t, slides[3], "Login now checks the user it finds.")
+	assert.Contains(t, slides[3], "```diff\n@@ -10,2 +10,2 @@ func Login()\n \tuser := lookup()\n-\treturn nil\n+\treturn check(user)\n```")
+	assert.Contains(t, slides[3], "<!-- Here login starts checking the user. -->")
+}
+
+func TestWriteSlides_LengthensFenceAroundBackticks(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				NewPath: "README.md",
+				Hunks: []diffview.Hunk{
+					{Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "```go"}}},
+				},
+			},
+		},
+	}
+	story := &diffview.StoryClassification{
+		Summary:  "Document usage",
+		Sections: []diffview.Section{{Title: "Docs", Hunks: []diffview.HunkRef{{File: "README.md"}}}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, main.WriteSlides(&buf, diff, story, nil))
+
+	assert.Contains(t, buf.String(), "````diff\n")
+	assert.Contains(t, buf.String(), "+```go\n````")
+}
+
+func TestWriteCast(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, main.WriteCast(&buf, []string{"intro\nslide", "section"}, 80, 24, "Title"))
+
+	scanner := bufio.NewScanner(&buf)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.Len(t, lines, 3)
+
+	var header map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, map[string]any{"version": 2.0, "width": 80.0, "height": 24.0, "title": "Title"}, header)
+
+	var first, second []any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &second))
+	assert.Equal(t, []any{0.0, "o", "\x1b[H\x1b[2Jintro\r\nslide"}, first)
+	assert.Equal(t, []any{5.0, "o", "\x1b[H\x1b[2Jsection"}, second)
+}
@@ -5,10 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -17,13 +22,22 @@ import (
 	"github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/bubbletea"
 	"github.com/fwojciec/diffstory/chroma"
+	"github.com/fwojciec/diffstory/clipboard"
+	"github.com/fwojciec/diffstory/editor"
 	"github.com/fwojciec/diffstory/fs"
 	"github.com/fwojciec/diffstory/gemini"
 	"github.com/fwojciec/diffstory/git"
 	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/fwojciec/diffstory/goapi"
+	diffhttp "github.com/fwojciec/diffstory/http"
 	"github.com/fwojciec/diffstory/jsonl"
 	"github.com/fwojciec/diffstory/lipgloss"
+	"github.com/fwojciec/diffstory/owners"
+	"github.com/fwojciec/diffstory/symbols"
+	"github.com/fwojciec/diffstory/tmux"
+	"github.com/fwojciec/diffstory/watch"
 	"github.com/fwojciec/diffstory/worddiff"
+	"github.com/muesli/termenv"
 )
 
 // ErrNoChanges is returned when the diff contains no changes to analyze.
@@ -64,6 +78,10 @@ type App struct {
 	BaseBranch string                   // Base branch (auto-detected if empty)
 	Range      string                   // Raw commit range (e.g., "main...feature"), overrides BaseBranch
 	Classifier diffview.StoryClassifier // Classifier for story generation
+	Symbols    diffview.SymbolResolver  // Names the declaration enclosing each hunk (optional)
+	Grouper    diffview.HunkGrouper     // Hints at hunks touching the same symbols (optional)
+	Narrator   diffview.StoryNarrator   // Narrates each section for walkthroughs (optional)
+	API        diffview.APIDiffer       // Finds exported Go API changes, shown in a section of their own (optional)
 }
 
 // Run parses the diff input and classifies it.
@@ -90,16 +108,41 @@ func (a *App) Run(ctx context.Context) (*diffview.Diff, *diffview.StoryClassific
 	if len(diff.Files) == 0 {
 		return nil, nil, ErrNoChanges
 	}
+	if a.Symbols != nil {
+		a.Symbols.ResolveSymbols(diff)
+	}
 
 	// Build classification input with the parsed diff
 	classInput := diffview.ClassificationInput{
 		Diff: *diff,
 	}
+	if a.Grouper != nil {
+		classInput.Groups = a.Grouper.GroupHunks(diff)
+	}
+	if a.API != nil {
+		classInput.APIChanges = a.API.DiffAPI(diff)
+	}
 
 	classification, err := a.Classifier.Classify(ctx, classInput)
 	if err != nil {
 		return nil, nil, err
 	}
+	if a.API != nil {
+		if len(classInput.APIChanges) > 0 {
+			classification.Sections = append(classification.Sections, goapi.Section(classInput.APIChanges))
+		}
+		impact := diffview.InferImpact(classification, classInput.APIChanges)
+		classification.Impact = &impact
+	}
+	if a.Narrator != nil {
+		narrations, err := a.Narrator.Narrate(ctx, classInput, classification)
+		if err != nil {
+			return nil, nil, fmt.Errorf("narrating story: %w", err)
+		}
+		for i := range min(len(narrations), len(classification.Sections)) {
+			classification.Sections[i].Narration = narrations[i]
+		}
+	}
 
 	return diff, classification, nil
 }
@@ -175,13 +218,70 @@ func main() {
 	}
 }
 
+// takeColorFlag removes a --color=WHEN or --color WHEN option from
+// os.Args, wherever it is, and returns when to use colors: auto, always or
+// never. Without the option, $DIFFVIEW_COLOR is used.
+func takeColorFlag() (lipgloss.ColorMode, error) {
+	value := os.Getenv("DIFFVIEW_COLOR")
+	args := []string{os.Args[0]}
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		switch {
+		case strings.HasPrefix(arg, "--color="):
+			value = strings.TrimPrefix(arg, "--color=")
+		case arg == "--color" && i+1 < len(os.Args):
+			i++
+			value = os.Args[i]
+		default:
+			args = append(args, arg)
+		}
+	}
+	os.Args = args
+	return lipgloss.ParseColorMode(value)
+}
+
 func usage() {
-	fmt.Fprintf(os.Stderr, `Usage: diffstory [range | command]
+	fmt.Fprintf(os.Stderr, `Usage: diffstory [--watch] [--narrate] [--present] [--color=WHEN] [range | command]
 
 Modes:
   (default)              Analyze current branch diff vs auto-detected base
   <range>                Analyze diff for specific commit range
-  replay <file> [index]  Replay a saved eval case from JSONL file
+  replay [--present] <file> [index]
+                         Replay a saved eval case from JSONL file
+  export --slides [--cast] <file> [index]
+                         Write a saved case's story as a Marp slide deck,
+                         or with --cast as an asciinema recording
+  changelog [--write] <range>
+                         Write release notes for the range's commits, grouped
+                         by change type, or with --write add them to
+                         CHANGELOG.md
+  suggest-commit [--write [file]]
+                         Suggest a commit message for the staged changes, or
+                         with --write put it in file (default
+                         .git/COMMIT_EDITMSG)
+  ci [--format github|sarif] [--annotate LIST] [--linter name=command] [range]
+                         Write annotator findings (todo, vet, staticcheck)
+                         and, with GEMINI_API_KEY, the change's risk as
+                         GitHub Actions workflow commands (default) or SARIF
+  check [--policy FILE] [range]
+                         Fail when the change's story breaks the policy in
+                         FILE (default .diffstory-policy.yaml, or core
+                         changes need tests and at most half may be noise),
+                         for pre-push hooks and CI
+  serve [--addr ADDR] [--max-concurrent N]
+                         Serve POST /parse and POST /classify, each taking a
+                         raw diff, over HTTP (default localhost:8080, 4 at
+                         once); clients send a key from DIFFSTORY_API_KEYS
+                         as a bearer token or X-API-Key header
+
+Options:
+  --watch                Re-analyze and reload when new commits change the diff
+  --narrate              Write a spoken narration of each section, shown with v
+  --present              Start in presenter mode, for walking through the
+                         story on a call (P toggles it, H highlights lines)
+  --color=WHEN           When to use colors: auto, always or never; 256- and
+                         16-color terminals get the theme's nearest colors
+                         (default auto, or $DIFFVIEW_COLOR)
 
 Range examples:
   main...feature         Three-dot: changes on feature since diverging from main
@@ -191,8 +291,50 @@ Examples:
   diffstory                      # Analyze current branch vs base
   diffstory main...feature       # Analyze specific branch comparison
   diffstory HEAD~3..HEAD         # Analyze last 3 commits
-  diffstory replay cases.jsonl   # Replay first case
+  diffstory --watch              # Follow the branch as you commit
+  diffstory replay cases.jsonl   # Choose a case to replay from a list
   diffstory replay cases.jsonl 2 # Replay third case (0-indexed)
+  diffstory replay --present cases.jsonl 2
+  diffstory export --slides cases.jsonl 2 > walkthrough.md
+  diffstory export --slides --cast cases.jsonl 2 > walkthrough.cast
+  diffstory changelog v1.2.0..HEAD
+  diffstory changelog --write v1.2.0..HEAD
+  diffstory suggest-commit       # Message for what's staged
+  diffstory ci --annotate todo,vet origin/main...HEAD
+  diffstory check --policy .diffstory-policy.yaml
+  diffstory ci --format sarif origin/main...HEAD > diffstory.sarif
+
+Environment:
+  GEMINI_API_KEY         API key for classification
+  DIFFSTORY_API_KEYS     Comma-separated keys serve accepts from clients
+  DIFFSTORY_FALLBACK_MODEL
+                         Gemini model to classify with when the default fails
+  DIFFVIEW_TAB_WIDTH     Tab stop width for diff content (default 8)
+  DIFFVIEW_IDLE_TIMEOUT  Blank the screen after this many idle minutes, or a
+                         duration like 90s (default off)
+  DIFFVIEW_EDITOR        Command that o opens the current line with, e.g.
+                         "code -g {file}:{line}" (default $VISUAL, $EDITOR, vi)
+  DIFFVIEW_NVIM_SERVER   Address of a running Neovim (nvim --listen) for o to
+                         open lines in and O to send quickfix lists to
+                         (default $NVIM, set inside Neovim's terminal)
+  DIFFVIEW_TMUX_PANE     Where p, b and L open the file, its blame or its log
+                         when running in tmux: right, below or window
+                         (default right)
+  DIFFVIEW_SPEAK         Text-to-speech command that reads narration aloud,
+                         e.g. "say" or "espeak" (default off)
+  DIFFVIEW_AUTO_ADVANCE  In presenter mode, move to the next section after
+                         this many seconds, or a duration like 1m (default
+                         off; space pauses)
+  DIFFVIEW_SCROLL_STEP   Rows j/k scroll, 1 to 10 (default 1)
+  DIFFVIEW_PAGE_SCROLL   How far ctrl+d/ctrl+u move: half or full (default half)
+  DIFFVIEW_SMOOTH_SCROLL Animate page jumps: true or false (default false)
+  DIFFVIEW_THEME         Colors: default, colorblind, high-contrast, or symbols
+                         to mark changes without color (default default)
+  DIFFVIEW_SYNTAX_THEME  Syntax colors: a chroma style like dracula, or a
+                         chroma XML style file (default from DIFFVIEW_THEME)
+  DIFFVIEW_COLOR         When to use colors: auto, always or never (default auto)
+  XDG_STATE_HOME         Where your place in each diff is kept, to resume on
+                         reopening (default ~/.local/state)
 `)
 }
 
@@ -200,24 +342,78 @@ func run() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	// Check for subcommand or range argument
+	colorMode, err := takeColorFlag()
+	if err != nil {
+		return err
+	}
+	profile := colorMode.Profile(os.Stdout)
+	bubbletea.SetColorProfile(profile)
+
+	// Check for subcommand, options and range argument
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		return runReplay(ctx, profile)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		return runExport(ctx)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "changelog" {
+		return runChangelog(ctx)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "suggest-commit" {
+		return runSuggestCommit(ctx)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		return runServe(ctx)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "ci" {
+		return runCI(ctx)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		return runCheck(ctx)
+	}
 	var rangeArg string
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "replay":
-			return runReplay(ctx)
+	var watchMode, narrate, present bool
+	for _, arg := range os.Args[1:] {
+		switch arg {
 		case "-h", "--help", "help":
 			usage()
 			return nil
+		case "--watch":
+			watchMode = true
+		case "--narrate":
+			narrate = true
+		case "--present":
+			present = true
 		default:
 			// Validate as commit range - provides helpful error for malformed ranges
-			if _, _, err := ParseRange(os.Args[1]); err != nil {
-				return fmt.Errorf("unknown argument %q (use --help for usage)", os.Args[1])
+			if _, _, err := ParseRange(arg); err != nil || rangeArg != "" {
+				return fmt.Errorf("unknown argument %q (use --help for usage)", arg)
 			}
-			rangeArg = os.Args[1]
+			rangeArg = arg
 		}
 	}
 
+	tabWidth, err := bubbletea.ParseTabWidth(os.Getenv("DIFFVIEW_TAB_WIDTH"))
+	if err != nil {
+		return err
+	}
+	idleTimeout, err := bubbletea.ParseIdleTimeout(os.Getenv("DIFFVIEW_IDLE_TIMEOUT"))
+	if err != nil {
+		return err
+	}
+	scrolling, err := scrollingFromEnv()
+	if err != nil {
+		return err
+	}
+	presenter, err := presenterOptions(present)
+	if err != nil {
+		return err
+	}
+	pane, err := tmux.FromEnv(os.Getenv)
+	if err != nil {
+		return err
+	}
+
 	// Check for API key
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
@@ -256,16 +452,30 @@ func run() error {
 	}
 	defer client.Close()
 
-	geminiClassifier := gemini.NewClassifier(client, gemini.DefaultModel,
-		gemini.WithValidationRetry(2)) // Retry once if LLM returns invalid hunk references
-	classifier := fs.NewClassifier(geminiClassifier, fs.DefaultCacheDir())
+	classifier, err := newClassifier(client)
+	if err != nil {
+		return err
+	}
 
+	// Hunks are resolved against the files at the head of the range
+	headRef := "HEAD"
+	if rangeArg != "" {
+		_, headRef, _ = ParseRange(rangeArg)
+	}
 	app := &App{
 		GitRunner:  gitRunner,
 		RepoPath:   cwd,
 		BaseBranch: baseBranch,
 		Range:      rangeArg,
 		Classifier: classifier,
+		Symbols: symbols.NewResolver(func(path string) ([]byte, error) {
+			return gitRunner.FileAt(ctx, cwd, headRef, path)
+		}),
+		Grouper: symbols.NewGrouper(),
+		API:     apiDiffer(ctx, gitRunner, cwd, rangeArg, baseBranch, headRef),
+	}
+	if narrate {
+		app.Narrator = gemini.NewNarrator(client, gemini.DefaultModel)
 	}
 
 	// Show spinner while processing (only if stderr is a terminal)
@@ -304,16 +514,28 @@ func run() error {
 
 	// Build ClassificationInput for case saving
 	classInput := diffview.ClassificationInput{
-		Repo:    filepath.Base(cwd),
-		Branch:  branchName,
-		Commits: commits,
-		Diff:    *diff,
+		Repo:      filepath.Base(cwd),
+		Branch:    branchName,
+		Commits:   commits,
+		Diff:      *diff,
+		Reviewers: suggestReviewers(ctx, gitRunner, cwd, rangeArg, baseBranch, headRef, diff),
+	}
+	if app.API != nil {
+		classInput.APIChanges = app.API.DiffAPI(diff)
 	}
 
 	// Set up syntax highlighting
-	theme := lipgloss.DefaultTheme()
+	theme, err := lipgloss.ThemeByName(os.Getenv("DIFFVIEW_THEME"))
+	if err != nil {
+		return err
+	}
+	theme = lipgloss.ForProfile(theme, profile)
+	syntaxStyle, err := chroma.SyntaxStyle(os.Getenv("DIFFVIEW_SYNTAX_THEME"), theme.Palette())
+	if err != nil {
+		return err
+	}
 	detector := chroma.NewDetector()
-	tokenizer, err := chroma.NewTokenizer(chroma.StyleFromPalette(theme.Palette()))
+	tokenizer, err := chroma.NewTokenizer(syntaxStyle)
 	if err != nil {
 		return fmt.Errorf("failed to set up syntax highlighting: %w", err)
 	}
@@ -322,66 +544,151 @@ func run() error {
 	curatedPath := filepath.Join(cwd, "eval-curated.jsonl")
 
 	// Launch StoryModel TUI
-	m := bubbletea.NewStoryModel(diff, classification,
+	opts := []bubbletea.StoryModelOption{
 		bubbletea.WithStoryTheme(theme),
 		bubbletea.WithStoryLanguageDetector(detector),
 		bubbletea.WithStoryTokenizer(tokenizer),
 		bubbletea.WithStoryWordDiffer(worddiff.NewDiffer()),
+		bubbletea.WithStoryTabWidth(tabWidth),
+		bubbletea.WithStoryIdleTimeout(idleTimeout),
+		bubbletea.WithStoryScrolling(scrolling),
+		storyEditorOption(),
+		bubbletea.WithStoryQuickfix(quickfixFunc()),
+		bubbletea.WithStoryPane(paneFunc(pane, cwd)),
+		bubbletea.WithStoryPermalinks(permalinkFunc(ctx, gitRunner, cwd, headRef), clipboard.NewSystem()),
+		bubbletea.WithStoryPatcher(patchFunc(ctx, gitRunner, cwd)),
+		bubbletea.WithStorySpeaker(speakFunc()),
+		bubbletea.WithStoryChat(askFunc(ctx, gemini.NewAnswerer(client, gemini.DefaultModel))),
 		bubbletea.WithIntroSlide(),
 		bubbletea.WithStoryInput(classInput),
 		bubbletea.WithStoryCaseSaver(jsonl.NewSaver(), curatedPath),
-	)
+		bubbletea.WithStoryStateStore(fs.NewStateStore(fs.DefaultStateDir())),
+	}
+	m := bubbletea.NewStoryModel(diff, classification, append(opts, presenter...)...)
 	p := tea.NewProgram(m,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 		tea.WithContext(ctx),
 	)
 
+	if watchMode {
+		go watchForChanges(ctx, p, app, diff)
+	}
+
 	_, err = p.Run()
 	return err
 }
 
-func runReplay(ctx context.Context) error {
-	// Parse replay arguments: replay <file> [index]
-	if len(os.Args) < 3 {
-		return fmt.Errorf("replay requires a file path: diffstory replay <file.jsonl> [index]")
+// watchForChanges re-analyzes the diff whenever the repository changes and
+// sends the result to the program. Changes that leave the diff as it was,
+// such as edits not yet committed, don't reload.
+func watchForChanges(ctx context.Context, p *tea.Program, app *App, shown *diffview.Diff) {
+	root, err := git.NewRunner().TopLevel(ctx, app.RepoPath)
+	if err != nil {
+		return
 	}
+	// Best-effort: if watching fails, the view just stops updating
+	_ = watch.Watch(ctx, root, watch.DefaultDebounce, func() {
+		// A failed analysis, such as with no changes left, waits for the next change
+		diff, classification, err := app.Run(ctx)
+		if err != nil || reflect.DeepEqual(diff, shown) {
+			return
+		}
+		shown = diff
+		p.Send(bubbletea.ReloadMsg{Diff: diff, Story: classification})
+	})
+}
 
-	filePath := os.Args[2]
+func runReplay(ctx context.Context, profile termenv.Profile) error {
+	// Parse replay arguments: replay [--present] <file> [index]
+	var args []string
+	var present bool
+	for _, arg := range os.Args[2:] {
+		if arg == "--present" {
+			present = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("replay requires a file path: diffstory replay [--present] <file.jsonl> [index]")
+	}
+
+	filePath := args[0]
 	index := 0
-	if len(os.Args) > 3 {
-		if _, err := fmt.Sscanf(os.Args[3], "%d", &index); err != nil {
-			return fmt.Errorf("invalid index %q: must be a non-negative integer", os.Args[3])
+	var pick func(labels []string) (int, error)
+	if len(args) > 1 {
+		if _, err := fmt.Sscanf(args[1], "%d", &index); err != nil {
+			return fmt.Errorf("invalid index %q: must be a non-negative integer", args[1])
 		}
+	} else {
+		pick = pickCase(ctx)
+	}
+
+	tabWidth, err := bubbletea.ParseTabWidth(os.Getenv("DIFFVIEW_TAB_WIDTH"))
+	if err != nil {
+		return err
+	}
+	idleTimeout, err := bubbletea.ParseIdleTimeout(os.Getenv("DIFFVIEW_IDLE_TIMEOUT"))
+	if err != nil {
+		return err
+	}
+	scrolling, err := scrollingFromEnv()
+	if err != nil {
+		return err
+	}
+	presenter, err := presenterOptions(present)
+	if err != nil {
+		return err
 	}
 
 	app := &ReplayApp{
 		Loader:   jsonl.NewLoader(),
 		FilePath: filePath,
 		Index:    index,
+		Pick:     pick,
 	}
 
 	diff, story, err := app.Run()
+	if errors.Is(err, ErrNoCaseChosen) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
 	// Set up syntax highlighting
-	theme := lipgloss.DefaultTheme()
+	theme, err := lipgloss.ThemeByName(os.Getenv("DIFFVIEW_THEME"))
+	if err != nil {
+		return err
+	}
+	theme = lipgloss.ForProfile(theme, profile)
+	syntaxStyle, err := chroma.SyntaxStyle(os.Getenv("DIFFVIEW_SYNTAX_THEME"), theme.Palette())
+	if err != nil {
+		return err
+	}
 	detector := chroma.NewDetector()
-	tokenizer, err := chroma.NewTokenizer(chroma.StyleFromPalette(theme.Palette()))
+	tokenizer, err := chroma.NewTokenizer(syntaxStyle)
 	if err != nil {
 		return fmt.Errorf("failed to set up syntax highlighting: %w", err)
 	}
 
 	// Launch StoryModel TUI (without case saving - this is replay mode)
-	m := bubbletea.NewStoryModel(diff, story,
+	opts := []bubbletea.StoryModelOption{
 		bubbletea.WithStoryTheme(theme),
 		bubbletea.WithStoryLanguageDetector(detector),
 		bubbletea.WithStoryTokenizer(tokenizer),
 		bubbletea.WithStoryWordDiffer(worddiff.NewDiffer()),
+		bubbletea.WithStoryTabWidth(tabWidth),
+		bubbletea.WithStoryIdleTimeout(idleTimeout),
+		bubbletea.WithStoryScrolling(scrolling),
+		storyEditorOption(),
+		bubbletea.WithStoryQuickfix(quickfixFunc()),
+		bubbletea.WithStorySpeaker(speakFunc()),
 		bubbletea.WithIntroSlide(),
-	)
+		bubbletea.WithStoryStateStore(fs.NewStateStore(fs.DefaultStateDir())),
+	}
+	m := bubbletea.NewStoryModel(diff, story, append(opts, presenter...)...)
 	p := tea.NewProgram(m,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
@@ -391,3 +698,736 @@ func runReplay(ctx context.Context) error {
 	_, err = p.Run()
 	return err
 }
+
+// runExport writes the story of a saved eval case to stdout as slides.
+func runExport(ctx context.Context) error {
+	// Parse export arguments: export --slides [--cast] <file> [index]
+	var slides, cast bool
+	var args []string
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--slides":
+			slides = true
+		case "--cast":
+			cast = true
+		default:
+			args = append(args, arg)
+		}
+	}
+	if !slides || len(args) == 0 {
+		return fmt.Errorf("export requires --slides and a file path: diffstory export --slides [--cast] <file.jsonl> [index]")
+	}
+
+	index := 0
+	var pick func(labels []string) (int, error)
+	if len(args) > 1 {
+		if _, err := fmt.Sscanf(args[1], "%d", &index); err != nil {
+			return fmt.Errorf("invalid index %q: must be a non-negative integer", args[1])
+		}
+	} else {
+		// Stdout holds the export, so the picker draws on stderr.
+		pick = pickCase(ctx, tea.WithOutput(os.Stderr))
+	}
+
+	app := &ReplayApp{
+		Loader:   jsonl.NewLoader(),
+		FilePath: args[0],
+		Index:    index,
+		Pick:     pick,
+	}
+	evalCase, err := app.Load()
+	if errors.Is(err, ErrNoCaseChosen) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	diff, story := &evalCase.Input.Diff, evalCase.Story
+	if story == nil {
+		return fmt.Errorf("case has no story to export")
+	}
+
+	if !cast {
+		return WriteSlides(os.Stdout, diff, story, evalCase.Input.Reviewers)
+	}
+
+	theme := lipgloss.DefaultTheme()
+	tokenizer, err := chroma.NewTokenizer(chroma.StyleFromPalette(theme.Palette()))
+	if err != nil {
+		return fmt.Errorf("failed to set up syntax highlighting: %w", err)
+	}
+	frames := bubbletea.StoryFrames(diff, story, castWidth, castHeight,
+		bubbletea.WithStoryTheme(theme),
+		bubbletea.WithStoryLanguageDetector(chroma.NewDetector()),
+		bubbletea.WithStoryTokenizer(tokenizer),
+		bubbletea.WithStoryWordDiffer(worddiff.NewDiffer()),
+	)
+	return WriteCast(os.Stdout, frames, castWidth, castHeight, story.Summary)
+}
+
+func runChangelog(ctx context.Context) error {
+	// Parse changelog arguments: changelog [--write] <range>
+	var write bool
+	var rangeArg string
+	for _, arg := range os.Args[2:] {
+		switch {
+		case arg == "--write":
+			write = true
+		case rangeArg == "":
+			rangeArg = arg
+		default:
+			return fmt.Errorf("unknown argument %q (use --help for usage)", arg)
+		}
+	}
+	if rangeArg == "" {
+		return fmt.Errorf("changelog requires a commit range: diffstory changelog [--write] <range>")
+	}
+	if _, _, err := ParseRange(rangeArg); err != nil {
+		return err
+	}
+
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable required")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	client, err := gemini.NewClient(ctx, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	defer client.Close()
+	classifier, err := newClassifier(client)
+	if err != nil {
+		return err
+	}
+
+	gitRunner := git.NewRunner()
+	app := &ChangelogApp{
+		GitRunner:  gitRunner,
+		RepoPath:   cwd,
+		Range:      rangeArg,
+		Classifier: classifier,
+	}
+
+	var spin *spinner
+	if isTerminal(os.Stderr) {
+		spin = newSpinner(os.Stderr, "Classifying commits...")
+		spin.Start()
+	}
+	entries, err := app.Run(ctx)
+	if spin != nil {
+		spin.Stop()
+	}
+	if err != nil {
+		return err
+	}
+
+	var commitURL func(hash string) string
+	if rawURL, err := gitRunner.RemoteURL(ctx, cwd, "origin"); err == nil {
+		if remote, err := git.ParseRemote(rawURL); err == nil {
+			commitURL = remote.CommitURL
+		}
+	}
+	title := fmt.Sprintf("%s (%s)", rangeArg, time.Now().Format(time.DateOnly))
+	var release strings.Builder
+	if err := WriteChangelog(&release, title, entries, commitURL); err != nil {
+		return err
+	}
+	if !write {
+		_, err := io.WriteString(os.Stdout, release.String())
+		return err
+	}
+
+	path := filepath.Join(cwd, "CHANGELOG.md")
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read changelog: %w", err)
+	}
+	return os.WriteFile(path, []byte(AddToChangelog(string(existing), release.String())), 0o644)
+}
+
+func runSuggestCommit(ctx context.Context) error {
+	// Parse suggest-commit arguments: suggest-commit [--write [file]]
+	var write bool
+	var msgPath string
+	for _, arg := range os.Args[2:] {
+		switch {
+		case arg == "--write" && !write:
+			write = true
+		case write && msgPath == "":
+			msgPath = arg
+		default:
+			return fmt.Errorf("unknown argument %q (use --help for usage)", arg)
+		}
+	}
+
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable required")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	client, err := gemini.NewClient(ctx, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	defer client.Close()
+
+	classifier, err := newClassifier(client)
+	if err != nil {
+		return err
+	}
+
+	gitRunner := git.NewRunner()
+	app := &App{
+		GitRunner: gitRunner,
+		RepoPath:  cwd,
+		// The range is passed to git diff as is, so --cached selects the index
+		Range:      "--cached",
+		Classifier: classifier,
+		Symbols: symbols.NewResolver(func(path string) ([]byte, error) {
+			// An empty ref reads the staged version from the index
+			return gitRunner.FileAt(ctx, cwd, "", path)
+		}),
+		Grouper: symbols.NewGrouper(),
+		API: goapi.NewDiffer(
+			func(path string) ([]byte, error) { return gitRunner.FileAt(ctx, cwd, "HEAD", path) },
+			func(path string) ([]byte, error) { return gitRunner.FileAt(ctx, cwd, "", path) },
+		),
+	}
+
+	var spin *spinner
+	if isTerminal(os.Stderr) {
+		spin = newSpinner(os.Stderr, "Classifying staged changes...")
+		spin.Start()
+	}
+	_, story, err := app.Run(ctx)
+	if spin != nil {
+		spin.Stop()
+	}
+	if err != nil {
+		return err
+	}
+
+	msg := CommitMessage(story)
+	if !write {
+		_, err := io.WriteString(os.Stdout, msg)
+		return err
+	}
+	if msgPath == "" {
+		if msgPath, err = gitRunner.GitPath(ctx, cwd, "COMMIT_EDITMSG"); err != nil {
+			return err
+		}
+	}
+	existing, err := os.ReadFile(msgPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read commit message: %w", err)
+	}
+	return os.WriteFile(msgPath, []byte(AddCommitMessage(string(existing), msg)), 0o644)
+}
+
+// runCheck classifies the change and fails if its story breaks the policy,
+// for pre-push hooks and CI.
+func runCheck(ctx context.Context) error {
+	// Parse check arguments: check [--policy FILE] [range]
+	var policyPath, rangeArg string
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--policy" && i+1 < len(args):
+			i++
+			policyPath = args[i]
+		case strings.HasPrefix(arg, "--policy="):
+			policyPath = strings.TrimPrefix(arg, "--policy=")
+		case rangeArg == "" && !strings.HasPrefix(arg, "-"):
+			if _, _, err := ParseRange(arg); err != nil {
+				return err
+			}
+			rangeArg = arg
+		default:
+			return fmt.Errorf("unknown argument %q (use --help for usage)", arg)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	gitRunner := git.NewRunner()
+	root, err := gitRunner.TopLevel(ctx, cwd)
+	if err != nil {
+		return err
+	}
+	policyFile := policyPath
+	if policyFile == "" {
+		policyFile = filepath.Join(root, DefaultPolicyFile)
+	}
+	policy, err := LoadPolicy(policyFile)
+	if errors.Is(err, os.ErrNotExist) && policyPath == "" {
+		policy, err = DefaultPolicy(), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable required")
+	}
+	var baseBranch string
+	headRef := "HEAD"
+	if rangeArg == "" {
+		if baseBranch, err = gitRunner.DefaultBranch(ctx, root); err != nil {
+			return fmt.Errorf("failed to detect base branch, pass a range such as origin/main...HEAD: %w", err)
+		}
+	} else {
+		_, headRef, _ = ParseRange(rangeArg)
+	}
+	client, err := gemini.NewClient(ctx, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	defer client.Close()
+
+	classifier, err := newClassifier(client)
+	if err != nil {
+		return err
+	}
+
+	app := &App{
+		GitRunner:  gitRunner,
+		RepoPath:   root,
+		BaseBranch: baseBranch,
+		Range:      rangeArg,
+		Classifier: classifier,
+		Symbols: symbols.NewResolver(func(path string) ([]byte, error) {
+			return gitRunner.FileAt(ctx, root, headRef, path)
+		}),
+		Grouper: symbols.NewGrouper(),
+		API:     apiDiffer(ctx, gitRunner, root, rangeArg, baseBranch, headRef),
+	}
+	var spin *spinner
+	if isTerminal(os.Stderr) {
+		spin = newSpinner(os.Stderr, "Classifying changes...")
+		spin.Start()
+	}
+	_, story, err := app.Run(ctx)
+	if spin != nil {
+		spin.Stop()
+	}
+	if errors.Is(err, ErrNoChanges) {
+		// Nothing to push, such as a branch with no commits of its own
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return WriteCheckReport(os.Stderr, policy.Check(story))
+}
+
+// runCI writes the findings on the change, from the annotators asked for
+// and, with GEMINI_API_KEY set, the classifier's risk assessment, for a CI
+// pipeline to attach to the pull request.
+func runCI(ctx context.Context) error {
+	// Parse ci arguments: ci [--format FORMAT] [--annotate LIST] [--linter name=command]... [range]
+	format := "github"
+	var annotate, rangeArg string
+	var linters []string
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--format", "--annotate", "--linter":
+		default:
+			if rangeArg != "" || strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown argument %q (use --help for usage)", arg)
+			}
+			if _, _, err := ParseRange(arg); err != nil {
+				return err
+			}
+			rangeArg = arg
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return fmt.Errorf("%s requires a value (use --help for usage)", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "--format":
+			format = value
+		case "--annotate":
+			annotate = value
+		case "--linter":
+			linters = append(linters, value)
+		}
+	}
+	if format != "github" && format != "sarif" {
+		return fmt.Errorf("unknown format %q (available: github, sarif)", format)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	gitRunner := git.NewRunner()
+	// Annotators read the files the diff's paths name, from the top of the repository
+	root, err := gitRunner.TopLevel(ctx, cwd)
+	if err != nil {
+		return err
+	}
+	annotators, err := ciAnnotators(annotate, linters, root)
+	if err != nil {
+		return err
+	}
+	app := &CIApp{
+		GitRunner:  gitRunner,
+		RepoPath:   root,
+		Range:      rangeArg,
+		Annotators: annotators,
+	}
+	if rangeArg == "" {
+		if app.BaseBranch, err = gitRunner.DefaultBranch(ctx, root); err != nil {
+			return fmt.Errorf("failed to detect base branch, pass a range such as origin/main...HEAD: %w", err)
+		}
+	}
+	if apiKey := os.Getenv("GEMINI_API_KEY"); apiKey != "" {
+		client, err := gemini.NewClient(ctx, apiKey)
+		if err != nil {
+			return fmt.Errorf("failed to create Gemini client: %w", err)
+		}
+		defer client.Close()
+		if app.Classifier, err = newClassifier(client); err != nil {
+			return err
+		}
+	} else if len(annotators) == 0 {
+		return fmt.Errorf("nothing to report: set GEMINI_API_KEY for the risk assessment, or choose annotators with --annotate")
+	}
+
+	findings, err := app.Run(ctx)
+	if errors.Is(err, ErrNoChanges) {
+		// A change without a diff, such as a merge, has nothing to flag
+		findings, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	return WriteFindings(os.Stdout, format, findings)
+}
+
+// runServe serves the parser and classifier over HTTP until interrupted.
+func runServe(ctx context.Context) error {
+	// Parse serve arguments: serve [--addr ADDR] [--max-concurrent N]
+	addr := "localhost:8080"
+	maxConcurrent := diffhttp.DefaultMaxConcurrent
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "--addr" && name != "--max-concurrent" {
+			return fmt.Errorf("unknown argument %q (use --help for usage)", arg)
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return fmt.Errorf("%s requires a value (use --help for usage)", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "--addr":
+			addr = value
+		case "--max-concurrent":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid --max-concurrent %q: expected a positive number", value)
+			}
+			maxConcurrent = n
+		}
+	}
+
+	var keys []string
+	for _, key := range strings.Split(os.Getenv("DIFFSTORY_API_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("DIFFSTORY_API_KEYS environment variable required: comma-separated keys clients authenticate with")
+	}
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable required")
+	}
+	client, err := gemini.NewClient(ctx, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	defer client.Close()
+
+	// Each request is logged, for operators to follow
+	classifier, err := newClassifier(client, diffview.LogClassifier(os.Stderr))
+	if err != nil {
+		return err
+	}
+
+	handler := diffhttp.NewServer(
+		gitdiff.NewParser(gitdiff.WithLenient()),
+		classifier,
+		diffhttp.WithAPIKeys(keys...),
+		diffhttp.WithMaxConcurrent(maxConcurrent),
+	)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		// Requests in flight get to finish; the error is that they didn't
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	fmt.Fprintf(os.Stderr, "Serving POST /parse and POST /classify on %s\n", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// pickCase returns a ReplayApp.Pick that lets the reviewer choose a case
+// from a fuzzy-filtered list.
+func pickCase(ctx context.Context, opts ...tea.ProgramOption) func(labels []string) (int, error) {
+	return func(labels []string) (int, error) {
+		p := tea.NewProgram(bubbletea.NewPicker(labels),
+			append([]tea.ProgramOption{tea.WithAltScreen(), tea.WithContext(ctx)}, opts...)...,
+		)
+		final, err := p.Run()
+		if err != nil {
+			return -1, err
+		}
+		return final.(bubbletea.Picker).Picked(), nil
+	}
+}
+
+// scrollingFromEnv reads the scroll settings from the environment.
+func scrollingFromEnv() (bubbletea.Scrolling, error) {
+	return bubbletea.ParseScrolling(
+		os.Getenv("DIFFVIEW_SCROLL_STEP"),
+		os.Getenv("DIFFVIEW_PAGE_SCROLL"),
+		os.Getenv("DIFFVIEW_SMOOTH_SCROLL"),
+	)
+}
+
+// presenterOptions returns the options that start the story in presenter
+// mode when present is set, moving on to the next section by itself after
+// $DIFFVIEW_AUTO_ADVANCE while presenting.
+func presenterOptions(present bool) ([]bubbletea.StoryModelOption, error) {
+	advance, err := bubbletea.ParseAutoAdvance(os.Getenv("DIFFVIEW_AUTO_ADVANCE"))
+	if err != nil {
+		return nil, err
+	}
+	opts := []bubbletea.StoryModelOption{bubbletea.WithStoryAutoAdvance(advance)}
+	if present {
+		opts = append(opts, bubbletea.WithStoryPresenter())
+	}
+	return opts, nil
+}
+
+// newClassifier returns the classifier the commands share: Gemini, retried
+// when it returns invalid hunk references, behind the file cache and the
+// given middlewares. With DIFFSTORY_FALLBACK_MODEL set, that Gemini model
+// classifies whatever the default one fails to.
+func newClassifier(client *gemini.Client, middlewares ...diffview.ClassifierMiddleware) (diffview.StoryClassifier, error) {
+	middlewares = append([]diffview.ClassifierMiddleware{fs.Cache(fs.DefaultCacheDir())}, middlewares...)
+	if model := os.Getenv("DIFFSTORY_FALLBACK_MODEL"); model != "" {
+		fallback, err := gemini.NewClassifier(client, model, gemini.WithValidationRetry(2))
+		if err != nil {
+			return nil, err
+		}
+		middlewares = append(middlewares, diffview.FallbackClassifier(fallback))
+	}
+	classifier, err := gemini.NewClassifier(client, gemini.DefaultModel, gemini.WithValidationRetry(2))
+	if err != nil {
+		return nil, err
+	}
+	return diffview.Chain(middlewares...)(classifier), nil
+}
+
+// editorFunc returns the command for opening lines in the user's editor, or
+// nil if none is configured.
+func editorFunc() bubbletea.EditorFunc {
+	if e := editor.NewSystem(); e != nil {
+		return e.Command
+	}
+	return nil
+}
+
+// storyEditorOption returns the option for opening lines in an editor: the
+// running Neovim, if there is one, or else the user's editor.
+func storyEditorOption() bubbletea.StoryModelOption {
+	if nv := editor.NewSystemNeovim(); nv != nil {
+		return bubbletea.WithStoryRemoteEditor(nv.Command)
+	}
+	return bubbletea.WithStoryEditor(editorFunc())
+}
+
+// quickfixFunc returns the command for sending a section's changes to the
+// running Neovim's quickfix list, or nil if there's no running Neovim.
+func quickfixFunc() bubbletea.QuickfixFunc {
+	nv := editor.NewSystemNeovim()
+	if nv == nil {
+		return nil
+	}
+	return func(title string, locations []bubbletea.QuickfixLocation) *exec.Cmd {
+		locs := make([]editor.Location, len(locations))
+		for i, l := range locations {
+			locs[i] = editor.Location{Path: l.Path, Line: l.Line, Text: l.Text}
+		}
+		return nv.QuickfixCommand(title, locs)
+	}
+}
+
+// paneFunc returns the command for opening views of a file in a tmux pane
+// started in dir, or nil outside tmux.
+func paneFunc(pane *tmux.Pane, dir string) bubbletea.PaneFunc {
+	if pane == nil {
+		return nil
+	}
+	pane.Dir = dir
+	return func(view bubbletea.PaneView, path string, line int) *exec.Cmd {
+		switch view {
+		case bubbletea.PaneBlame:
+			return pane.Command(tmux.BlameCommand(path, line))
+		case bubbletea.PaneLog:
+			return pane.Command(tmux.LogCommand(path))
+		default:
+			return pane.Command(tmux.FileCommand(path, line))
+		}
+	}
+}
+
+// speakFunc returns a speaker reading narration through the text-to-speech
+// command in DIFFVIEW_SPEAK, such as say or espeak, which reads the text from
+// stdin. Each narration stops the one before. Returns nil if none is set.
+func speakFunc() bubbletea.SpeakFunc {
+	args := strings.Fields(os.Getenv("DIFFVIEW_SPEAK"))
+	if len(args) == 0 {
+		return nil
+	}
+	var mu sync.Mutex
+	var speaking *exec.Cmd
+	return func(text string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if speaking != nil {
+			_ = speaking.Process.Kill()
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Start(); err != nil {
+			speaking = nil
+			return err
+		}
+		speaking = cmd
+		go func() { _ = cmd.Wait() }()
+		return nil
+	}
+}
+
+// suggestReviewers suggests reviewers for diff from CODEOWNERS as of headRef
+// and from history of the base branch, or of the range's base, leaving out
+// the change's own authors. Suggestions are best-effort: on failure there
+// are none.
+func suggestReviewers(ctx context.Context, runner *git.Runner, repoPath, rangeArg, baseBranch, headRef string, diff *diffview.Diff) []diffview.Reviewer {
+	base, change := baseBranch, baseBranch+"..HEAD"
+	if rangeArg != "" {
+		base, _, _ = ParseRange(rangeArg)
+		change = rangeArg
+	}
+	rules, err := owners.LoadCodeOwners(func(path string) ([]byte, error) {
+		return runner.FileAt(ctx, repoPath, headRef, path)
+	})
+	if err != nil {
+		return nil
+	}
+	var authors []string
+	commits, _ := runner.History(ctx, repoPath, diffview.HistoryQuery{Ref: change})
+	for _, c := range commits {
+		authors = append(authors, c.Author)
+	}
+	reviewers, _ := owners.NewSuggester(runner, repoPath, base, rules, authors...).SuggestReviewers(ctx, diff)
+	return reviewers
+}
+
+// apiDiffer returns a differ comparing the exported API of the files the
+// diff changes as of headRef with how they were before: at the merge base in
+// branch mode and for three-dot ranges, or at the base of a two-dot range.
+// Returns nil if the merge base can't be found.
+func apiDiffer(ctx context.Context, runner *git.Runner, repoPath, rangeArg, baseBranch, headRef string) diffview.APIDiffer {
+	base, head := baseBranch, "HEAD"
+	if rangeArg != "" {
+		base, head, _ = ParseRange(rangeArg)
+	}
+	oldRef := base
+	if rangeArg == "" || strings.Contains(rangeArg, "...") {
+		var err error
+		if oldRef, err = runner.MergeBase(ctx, repoPath, base, head); err != nil {
+			return nil
+		}
+	}
+	return goapi.NewDiffer(
+		func(path string) ([]byte, error) { return runner.FileAt(ctx, repoPath, oldRef, path) },
+		func(path string) ([]byte, error) { return runner.FileAt(ctx, repoPath, headRef, path) },
+	)
+}
+
+// askFunc returns a function answering the chat panel's questions with
+// answerer.
+func askFunc(ctx context.Context, answerer diffview.DiffAnswerer) bubbletea.AskFunc {
+	return func(diff *diffview.Diff, story *diffview.StoryClassification, history []diffview.ChatTurn, question string) (string, error) {
+		return answerer.Answer(ctx, diff, story, history, question)
+	}
+}
+
+// permalinkFunc returns a function building web links to lines as of headRef
+// on origin's hosting service, or nil if origin isn't a hosted repository.
+func permalinkFunc(ctx context.Context, runner *git.Runner, repoPath, headRef string) bubbletea.PermalinkFunc {
+	rawURL, err := runner.RemoteURL(ctx, repoPath, "origin")
+	if err != nil {
+		return nil
+	}
+	remote, err := git.ParseRemote(rawURL)
+	if err != nil {
+		return nil
+	}
+	commit, err := runner.ResolveCommit(ctx, repoPath, headRef)
+	if err != nil {
+		return nil
+	}
+	return func(path string, line int) string {
+		return remote.Permalink(commit, path, line)
+	}
+}
+
+// patchFunc returns a PatchFunc that applies diffs to the working tree of
+// the repository at repoPath with git apply, or nil outside a repository.
+func patchFunc(ctx context.Context, runner *git.Runner, repoPath string) bubbletea.PatchFunc {
+	root, err := runner.TopLevel(ctx, repoPath)
+	if err != nil {
+		return nil
+	}
+	return func(diff *diffview.Diff, reverse bool) error {
+		return runner.Apply(ctx, root, gitdiff.Format(diff), reverse)
+	}
+}
@@ -53,6 +53,40 @@ new file mode 100644
 	assert.Equal(t, "feature.go", diff.Files[0].NewPath)
 }
 
+func TestApp_Run_ResolvesAndGroupsSymbolsBeforeClassifying(t *testing.T) {
+	t.Parallel()
+
+	app := &main.App{
+		GitRunner: &mock.GitRunner{
+			DiffRangeFn: func(_ context.Context, _, _, _ string) (string, error) {
+				return "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@ func old\n-x\n+y\n", nil
+			},
+		},
+		BaseBranch: "main",
+		Symbols: &mock.SymbolResolver{
+			ResolveSymbolsFn: func(diff *diffview.Diff) {
+				diff.Files[0].Hunks[0].Section = "func resolved"
+			},
+		},
+		Grouper: &mock.HunkGrouper{
+			GroupHunksFn: func(diff *diffview.Diff) []diffview.HunkGroup {
+				assert.Equal(t, "func resolved", diff.Files[0].Hunks[0].Section, "groups use resolved sections")
+				return []diffview.HunkGroup{{Symbol: "resolved"}}
+			},
+		},
+		Classifier: &mock.StoryClassifier{
+			ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				assert.Equal(t, "func resolved", input.Diff.Files[0].Hunks[0].Section)
+				assert.Equal(t, []diffview.HunkGroup{{Symbol: "resolved"}}, input.Groups)
+				return &diffview.StoryClassification{}, nil
+			},
+		},
+	}
+
+	_, _, err := app.Run(context.Background())
+	require.NoError(t, err)
+}
+
 func TestApp_Run_GitError(t *testing.T) {
 	t.Parallel()
 
@@ -133,6 +167,96 @@ new file mode 100644
 	assert.Contains(t, err.Error(), "API error")
 }
 
+func TestApp_Run_NarratesSections(t *testing.T) {
+	t.Parallel()
+
+	diffFromGit := `diff --git a/hello.go b/hello.go
+new file mode 100644
+--- /dev/null
++++ b/hello.go
+@@ -0,0 +1 @@
++package main
+`
+
+	app := &main.App{
+		GitRunner: &mock.GitRunner{
+			DiffRangeFn: func(_ context.Context, _, _, _ string) (string, error) {
+				return diffFromGit, nil
+			},
+		},
+		RepoPath:   "/repo",
+		BaseBranch: "main",
+		Classifier: &mock.StoryClassifier{
+			ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				return &diffview.StoryClassification{Sections: []diffview.Section{{Title: "Add main"}}}, nil
+			},
+		},
+		Narrator: &mock.StoryNarrator{
+			NarrateFn: func(_ context.Context, input diffview.ClassificationInput, story *diffview.StoryClassification) ([]string, error) {
+				assert.Len(t, input.Diff.Files, 1)
+				assert.Equal(t, "Add main", story.Sections[0].Title)
+				return []string{"We start a new program."}, nil
+			},
+		},
+	}
+
+	_, story, err := app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "We start a new program.", story.Sections[0].Narration)
+}
+
+func TestApp_Run_ReportsAPIChanges(t *testing.T) {
+	t.Parallel()
+
+	diffFromGit := `diff --git a/hello.go b/hello.go
+new file mode 100644
+--- /dev/null
++++ b/hello.go
+@@ -0,0 +1,3 @@
++package hello
++
++func Hello() {}
+`
+	changes := []diffview.APIChange{{
+		Symbol: "hello.Hello",
+		Kind:   "added",
+		New:    "func()",
+		Hunks:  []diffview.HunkRef{{File: "hello.go", HunkIndex: 0}},
+	}}
+
+	app := &main.App{
+		GitRunner: &mock.GitRunner{
+			DiffRangeFn: func(_ context.Context, _, _, _ string) (string, error) {
+				return diffFromGit, nil
+			},
+		},
+		RepoPath:   "/repo",
+		BaseBranch: "main",
+		Classifier: &mock.StoryClassifier{
+			ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				assert.Equal(t, changes, input.APIChanges)
+				return &diffview.StoryClassification{
+					ChangeType: "feature",
+					Sections:   []diffview.Section{{Title: "Say hello", Hunks: []diffview.HunkRef{{File: "hello.go", HunkIndex: 0}}}},
+				}, nil
+			},
+		},
+		API: &mock.APIDiffer{
+			DiffAPIFn: func(diff *diffview.Diff) []diffview.APIChange {
+				assert.Len(t, diff.Files, 1)
+				return changes
+			},
+		},
+	}
+
+	_, story, err := app.Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, story.Sections, 2)
+	assert.Equal(t, "api", story.Sections[1].Role)
+	assert.Equal(t, "Adds hello.Hello.", story.Sections[1].Explanation)
+	assert.Equal(t, &diffview.Impact{CommitType: "feat", Bump: "minor", Added: []string{"hello.Hello"}}, story.Impact)
+}
+
 func TestApp_Run_PassesDiffToClassifier(t *testing.T) {
 	t.Parallel()
 
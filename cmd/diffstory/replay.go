@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/fwojciec/diffstory"
 )
@@ -9,24 +10,76 @@ import (
 // ErrIndexOutOfBounds is returned when the requested case index is invalid.
 var ErrIndexOutOfBounds = errors.New("case index out of bounds")
 
+// ErrPathsOnly is returned when the requested case was exported without code
+// content and so has no diff to display.
+var ErrPathsOnly = errors.New("case is a paths-only export with code content removed; it can be used in reports but not opened in the diff viewer")
+
+// ErrNoCaseChosen is returned when the reviewer closes the case picker
+// without choosing a case.
+var ErrNoCaseChosen = errors.New("no case chosen")
+
 // ReplayApp loads a saved eval case for replay in the TUI.
 type ReplayApp struct {
 	Loader   diffview.EvalCaseLoader // Loader for JSONL files
 	FilePath string                  // Path to JSONL file
 	Index    int                     // Case index (0-based)
+
+	// Pick, if set, chooses the case in place of Index, given a label for
+	// each case as CaseLabel describes it. It returns -1 if none was chosen.
+	Pick func(labels []string) (int, error)
 }
 
 // Run loads the specified case and returns its diff and story.
 func (a *ReplayApp) Run() (*diffview.Diff, *diffview.StoryClassification, error) {
-	cases, err := a.Loader.Load(a.FilePath)
+	evalCase, err := a.Load()
 	if err != nil {
 		return nil, nil, err
 	}
+	return &evalCase.Input.Diff, evalCase.Story, nil
+}
 
-	if a.Index < 0 || a.Index >= len(cases) {
-		return nil, nil, ErrIndexOutOfBounds
+// Load loads the specified case.
+func (a *ReplayApp) Load() (diffview.EvalCase, error) {
+	cases, err := a.Loader.Load(a.FilePath)
+	if err != nil {
+		return diffview.EvalCase{}, err
 	}
 
-	evalCase := cases[a.Index]
-	return &evalCase.Input.Diff, evalCase.Story, nil
+	index := a.Index
+	if a.Pick != nil && len(cases) > 0 {
+		labels := make([]string, len(cases))
+		for i, c := range cases {
+			labels[i] = CaseLabel(c)
+		}
+		if index, err = a.Pick(labels); err != nil {
+			return diffview.EvalCase{}, err
+		}
+		if index < 0 {
+			return diffview.EvalCase{}, ErrNoCaseChosen
+		}
+	}
+	if index < 0 || index >= len(cases) {
+		return diffview.EvalCase{}, ErrIndexOutOfBounds
+	}
+
+	evalCase := cases[index]
+	if evalCase.Input.PathsOnly {
+		return diffview.EvalCase{}, ErrPathsOnly
+	}
+	return evalCase, nil
+}
+
+// CaseLabel describes c in one line for choosing it from a list: its repo
+// and branch, its story's summary and how many lines it changes.
+func CaseLabel(c diffview.EvalCase) string {
+	summary := "(not classified)"
+	if c.Story != nil {
+		summary = c.Story.Summary
+	}
+	lines := 0
+	for _, f := range c.Input.Diff.Files {
+		added, deleted := f.Stats()
+		lines += added + deleted
+	}
+	return fmt.Sprintf("%s  %s  (%d lines)", c.Input.Name(), summary, lines)
 }
@@ -158,3 +158,85 @@ func TestReplayApp_Run_CaseWithNilStory(t *testing.T) {
 	assert.Nil(t, story) // Story can be nil
 	assert.Len(t, diff.Files, 1)
 }
+
+func TestReplayApp_Run_PathsOnlyCase(t *testing.T) {
+	t.Parallel()
+
+	app := &main.ReplayApp{
+		Loader: &mock.EvalCaseLoader{
+			LoadFn: func(path string) ([]diffview.EvalCase, error) {
+				return []diffview.EvalCase{
+					{Input: diffview.ClassificationInput{Repo: "test", PathsOnly: true}},
+				}, nil
+			},
+		},
+		FilePath: "shared.jsonl",
+		Index:    0,
+	}
+
+	_, _, err := app.Run()
+	require.ErrorIs(t, err, main.ErrPathsOnly)
+}
+
+func TestReplayApp_Run_PicksCase(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "first"}, Story: &diffview.StoryClassification{Summary: "First"}},
+		{Input: diffview.ClassificationInput{Repo: "repo", Branch: "second"}, Story: &diffview.StoryClassification{Summary: "Second"}},
+	}
+	app := &main.ReplayApp{
+		Loader: &mock.EvalCaseLoader{
+			LoadFn: func(path string) ([]diffview.EvalCase, error) {
+				return cases, nil
+			},
+		},
+		FilePath: "cases.jsonl",
+		Pick: func(labels []string) (int, error) {
+			assert.Equal(t, []string{"repo/first  First  (0 lines)", "repo/second  Second  (0 lines)"}, labels)
+			return 1, nil
+		},
+	}
+
+	_, story, err := app.Run()
+	require.NoError(t, err)
+	assert.Equal(t, "Second", story.Summary)
+}
+
+func TestReplayApp_Run_NoCaseChosen(t *testing.T) {
+	t.Parallel()
+
+	app := &main.ReplayApp{
+		Loader: &mock.EvalCaseLoader{
+			LoadFn: func(path string) ([]diffview.EvalCase, error) {
+				return []diffview.EvalCase{{Input: diffview.ClassificationInput{Repo: "repo", Branch: "first"}}}, nil
+			},
+		},
+		FilePath: "cases.jsonl",
+		Pick:     func([]string) (int, error) { return -1, nil },
+	}
+
+	_, _, err := app.Run()
+	assert.ErrorIs(t, err, main.ErrNoCaseChosen)
+}
+
+func TestCaseLabel(t *testing.T) {
+	t.Parallel()
+
+	c := diffview.EvalCase{
+		Input: diffview.ClassificationInput{
+			Repo:   "repo",
+			Branch: "feature",
+			Diff: diffview.Diff{Files: []diffview.FileDiff{{
+				NewPath: "a.go",
+				Hunks: []diffview.Hunk{{Lines: []diffview.Line{
+					{Type: diffview.LineAdded, Content: "new\n"},
+					{Type: diffview.LineDeleted, Content: "old\n"},
+					{Type: diffview.LineContext, Content: "same\n"},
+				}}},
+			}}},
+		},
+	}
+
+	assert.Equal(t, "repo/feature  (not classified)  (2 lines)", main.CaseLabel(c))
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/bubbletea"
+	"github.com/fwojciec/diffstory/chroma"
+	"github.com/fwojciec/diffstory/fs"
+	"github.com/fwojciec/diffstory/lipgloss"
+)
+
+// Config holds settings read from a config file. Each is the raw value as
+// for the flag of the same name; empty values leave the default in place.
+type Config struct {
+	TabWidth     string
+	IdleTimeout  string
+	ScrollStep   string
+	PageScroll   string
+	SmoothScroll string
+	Group        string
+	Theme        string
+	SyntaxTheme  string
+	Color        string
+}
+
+// ConfigError describes a problem on one line of a config file.
+type ConfigError struct {
+	File string
+	Line int
+	Key  string // empty if the line couldn't be split into a key and value
+	Err  error
+}
+
+// Error implements error, in the file:line form editors can jump to.
+func (e *ConfigError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %s: %v", e.File, e.Line, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// configKey is a setting the config file accepts.
+type configKey struct {
+	name  string
+	field func(*Config) *string
+	check func(string) error
+}
+
+// configKeys returns the accepted settings in the order config init writes
+// them.
+func configKeys() []configKey {
+	return []configKey{
+		{"tab-width", func(c *Config) *string { return &c.TabWidth }, func(v string) error {
+			_, err := bubbletea.ParseTabWidth(v)
+			return err
+		}},
+		{"idle-timeout", func(c *Config) *string { return &c.IdleTimeout }, func(v string) error {
+			_, err := bubbletea.ParseIdleTimeout(v)
+			return err
+		}},
+		{"scroll-step", func(c *Config) *string { return &c.ScrollStep }, func(v string) error {
+			_, err := bubbletea.ParseScrolling(v, "", "")
+			return err
+		}},
+		{"page-scroll", func(c *Config) *string { return &c.PageScroll }, func(v string) error {
+			_, err := bubbletea.ParseScrolling("", v, "")
+			return err
+		}},
+		{"smooth-scroll", func(c *Config) *string { return &c.SmoothScroll }, func(v string) error {
+			_, err := bubbletea.ParseScrolling("", "", v)
+			return err
+		}},
+		{"group", func(c *Config) *string { return &c.Group }, func(v string) error {
+			_, err := bubbletea.ParseFileGroups(v)
+			return err
+		}},
+		{"theme", func(c *Config) *string { return &c.Theme }, func(v string) error {
+			_, err := lipgloss.ThemeByName(v)
+			return err
+		}},
+		{"syntax-theme", func(c *Config) *string { return &c.SyntaxTheme }, func(v string) error {
+			_, err := chroma.SyntaxStyle(v, diffview.Palette{})
+			return err
+		}},
+		{"color", func(c *Config) *string { return &c.Color }, func(v string) error {
+			_, err := lipgloss.ParseColorMode(v)
+			return err
+		}},
+	}
+}
+
+// ParseConfig parses a config file of "key = value" lines, where blank
+// lines and lines starting with # are ignored. Every invalid line is
+// reported, each as a *ConfigError naming file and the line.
+func ParseConfig(file string, r io.Reader) (Config, error) {
+	var cfg Config
+	var errs []error
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			errs = append(errs, &ConfigError{File: file, Line: n, Err: fmt.Errorf(`expected "key = value", got %q`, line)})
+			continue
+		}
+		name = strings.TrimSpace(name)
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		key, ok := lookupConfigKey(name)
+		if !ok {
+			errs = append(errs, &ConfigError{File: file, Line: n, Key: name,
+				Err: fmt.Errorf("unknown setting; expected one of %s", strings.Join(configKeyNames(), ", "))})
+			continue
+		}
+		if first, ok := seen[name]; ok {
+			errs = append(errs, &ConfigError{File: file, Line: n, Key: name, Err: fmt.Errorf("already set on line %d", first)})
+			continue
+		}
+		seen[name] = n
+		if err := key.check(value); err != nil {
+			errs = append(errs, &ConfigError{File: file, Line: n, Key: name, Err: err})
+			continue
+		}
+		*key.field(&cfg) = value
+	}
+	if err := scanner.Err(); err != nil {
+		return Config{}, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+func lookupConfigKey(name string) (configKey, bool) {
+	for _, key := range configKeys() {
+		if key.name == name {
+			return key, true
+		}
+	}
+	return configKey{}, false
+}
+
+func configKeyNames() []string {
+	keys := configKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.name
+	}
+	return names
+}
+
+// DefaultConfig is the config file config init writes: every setting,
+// commented out at its default, so uncommenting a line is a no-op.
+const DefaultConfig = `# diffview configuration. Flags and DIFFVIEW_* environment variables
+# override these settings. Check changes with: diffview config validate
+
+# Tab stop width for diff content, 1 to 16.
+# tab-width = 8
+
+# Blank the screen after this long without input, in minutes or as a
+# duration like 90s. 0 disables the lock.
+# idle-timeout = 0
+
+# Rows j/k scroll, 1 to 10.
+# scroll-step = 1
+
+# How far ctrl+d/ctrl+u move: half or full.
+# page-scroll = half
+
+# Animate page jumps: true or false.
+# smooth-scroll = false
+
+# Group files under headers by directory: none, package (each directory)
+# or top (each top-level directory). } and { move between groups.
+# group = none
+
+# Colors: default, colorblind (blue and orange rather than green and red),
+# high-contrast, or symbols to mark changes with symbols, bold and
+# underline instead of color.
+# theme = default
+
+# Syntax colors, to match your editor: a chroma style such as dracula,
+# monokai or solarized-dark, or the path of a style file in chroma's XML
+# format. By default they come from the theme.
+# syntax-theme =
+
+# When to use colors: auto (when the terminal shows them and $NO_COLOR is
+# unset), always or never. 256- and 16-color terminals get the theme's
+# nearest colors, and never marks changes as the symbols theme does.
+# color = auto
+`
+
+// configPath returns the config file to use: $DIFFVIEW_CONFIG if set, else
+// diffview.conf in the config directory.
+func configPath() string {
+	if path := os.Getenv("DIFFVIEW_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(fs.DefaultConfigDir(), "diffview.conf")
+}
+
+// loadConfig reads the config file at path. A missing file is an empty
+// config.
+func loadConfig(path string) (Config, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to open config: %w", err)
+	}
+	defer f.Close()
+	return ParseConfig(path, f)
+}
+
+// runConfig handles the config subcommands: validate checks the config file
+// and init writes a commented default one.
+func runConfig(args []string) error {
+	usage := errors.New("usage: diffview config validate [FILE] | diffview config init [-force]")
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "validate":
+		if len(args) > 2 {
+			return usage
+		}
+		path := configPath()
+		if len(args) == 2 {
+			path = args[1]
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("no config to validate: %w", err)
+		}
+		if _, err := loadConfig(path); err != nil {
+			return err
+		}
+		fmt.Printf("%s: ok\n", path)
+		return nil
+	case "init":
+		force := len(args) == 2 && (args[1] == "-force" || args[1] == "--force")
+		if len(args) > 2 || (len(args) == 2 && !force) {
+			return usage
+		}
+		path := configPath()
+		if _, err := os.Stat(path); err == nil && !force {
+			return fmt.Errorf("%s already exists; use -force to overwrite it", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(DefaultConfig), 0o644); err != nil {
+			return fmt.Errorf("failed to write config: %w", err)
+		}
+		fmt.Printf("wrote %s\n", path)
+		return nil
+	default:
+		return usage
+	}
+}
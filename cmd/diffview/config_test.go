@@ -0,0 +1,65 @@
+package main_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	main "github.com/fwojciec/diffstory/cmd/diffview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads settings", func(t *testing.T) {
+		t.Parallel()
+
+		input := "# comment\n" +
+			"\n" +
+			"tab-width = 4\n" +
+			"page-scroll=full\n" +
+			`smooth-scroll = "true"` + "\n"
+
+		cfg, err := main.ParseConfig("diffview.conf", strings.NewReader(input))
+
+		require.NoError(t, err)
+		assert.Equal(t, main.Config{TabWidth: "4", PageScroll: "full", SmoothScroll: "true"}, cfg)
+	})
+
+	t.Run("default config is valid and changes nothing", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := main.ParseConfig("diffview.conf", strings.NewReader(main.DefaultConfig))
+
+		require.NoError(t, err)
+		assert.Equal(t, main.Config{}, cfg)
+	})
+
+	t.Run("reports every invalid line", func(t *testing.T) {
+		t.Parallel()
+
+		input := "tab-width = 40\n" +
+			"colour = blue\n" +
+			"scroll-step\n" +
+			"page-scroll = quarter\n" +
+			"tab-width = 4\n"
+
+		_, err := main.ParseConfig("diffview.conf", strings.NewReader(input))
+
+		require.Error(t, err)
+		lines := strings.Split(err.Error(), "\n")
+		require.Len(t, lines, 5)
+		assert.True(t, strings.HasPrefix(lines[0], "diffview.conf:1: tab-width: "), lines[0])
+		assert.Equal(t, "diffview.conf:2: colour: unknown setting; expected one of tab-width, idle-timeout, scroll-step, page-scroll, smooth-scroll, group, theme, syntax-theme, color", lines[1])
+		assert.Equal(t, `diffview.conf:3: expected "key = value", got "scroll-step"`, lines[2])
+		assert.Equal(t, `diffview.conf:4: page-scroll: invalid page scroll "quarter": use half or full`, lines[3])
+		assert.Equal(t, "diffview.conf:5: tab-width: already set on line 1", lines[4])
+
+		var cfgErr *main.ConfigError
+		require.True(t, errors.As(err, &cfgErr))
+		assert.Equal(t, 1, cfgErr.Line)
+		assert.Equal(t, "tab-width", cfgErr.Key)
+	})
+}
@@ -3,17 +3,39 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"runtime/pprof"
+	"strconv"
+	"strings"
 	"syscall"
 
+	"github.com/charmbracelet/x/term"
 	"github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/bubbletea"
 	"github.com/fwojciec/diffstory/chroma"
+	"github.com/fwojciec/diffstory/configdiff"
+	"github.com/fwojciec/diffstory/cover"
+	"github.com/fwojciec/diffstory/depdiff"
+	"github.com/fwojciec/diffstory/dirdiff"
+	"github.com/fwojciec/diffstory/editor"
+	"github.com/fwojciec/diffstory/git"
 	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/fwojciec/diffstory/lint"
 	"github.com/fwojciec/diffstory/lipgloss"
+	"github.com/fwojciec/diffstory/moves"
+	"github.com/fwojciec/diffstory/redact"
+	"github.com/fwojciec/diffstory/symbols"
+	"github.com/fwojciec/diffstory/tmux"
+	"github.com/fwojciec/diffstory/todo"
+	"github.com/fwojciec/diffstory/watch"
 	"github.com/fwojciec/diffstory/worddiff"
 )
 
@@ -39,43 +61,737 @@ func (a *App) Run(ctx context.Context) error {
 	return a.Viewer.View(ctx, diff)
 }
 
+// ExternalDiff describes one changed file as git passes it to an external
+// diff program, set with diff.external or GIT_EXTERNAL_DIFF: the path
+// followed by the old file, hash and mode and then the new ones.
+type ExternalDiff struct {
+	Path    string
+	OldFile string
+	NewFile string
+	OldMode fs.FileMode // 0 if the file is new or the mode is unknown
+	NewMode fs.FileMode // 0 if the file is deleted or the mode is unknown
+}
+
+// ParseExternalDiffArgs parses the seven arguments git passes an external
+// diff program. Hashes are ignored, and modes git leaves out as "." are 0.
+func ParseExternalDiffArgs(args []string) (ExternalDiff, error) {
+	if len(args) != 7 {
+		return ExternalDiff{}, fmt.Errorf("expected 7 arguments from git, got %d", len(args))
+	}
+	oldMode, err := parseExternalMode(args[3])
+	if err != nil {
+		return ExternalDiff{}, err
+	}
+	newMode, err := parseExternalMode(args[6])
+	if err != nil {
+		return ExternalDiff{}, err
+	}
+	return ExternalDiff{
+		Path:    args[0],
+		OldFile: args[1],
+		NewFile: args[4],
+		OldMode: oldMode,
+		NewMode: newMode,
+	}, nil
+}
+
+// parseExternalMode parses an octal file mode such as "100644".
+func parseExternalMode(s string) (fs.FileMode, error) {
+	if s == "." {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file mode %q from git", s)
+	}
+	return fs.FileMode(mode), nil
+}
+
+// Label names the files in diff, a diff between the external diff's
+// temporary files, after the path in the repository, and notes a mode
+// change if there is one.
+func (e ExternalDiff) Label(diff *diffview.Diff) {
+	for i := range diff.Files {
+		f := &diff.Files[i]
+		if f.OldPath != "" {
+			f.OldPath = e.Path
+		}
+		if f.NewPath != "" {
+			f.NewPath = e.Path
+		}
+		if e.OldMode != 0 && e.NewMode != 0 && e.OldMode != e.NewMode {
+			f.OldMode, f.NewMode = e.OldMode, e.NewMode
+		}
+	}
+}
+
+// subcommands maps each subcommand name to its implementation.
+var subcommands = map[string]func(args []string) error{
+	"init-git": runInitGit,
+	"config":   runConfig,
+	"notes":    runNotes,
+	"parse":    runParse,
+}
+
+// setting returns the environment variable env if set, else the value from
+// the config file.
+func setting(env, fromConfig string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return fromConfig
+}
+
 func main() {
-	// Check if stdin is a pipe (not a terminal)
+	os.Exit(run())
+}
+
+// run runs diffview and returns its exit status, so that deferred cleanup
+// such as finishing the CPU profile runs before the process exits.
+func run() int {
+	// Subcommands come before any flags
+	if len(os.Args) > 1 {
+		if sub, ok := subcommands[os.Args[1]]; ok {
+			if err := sub(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	cfg, err := loadConfig(configPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	tabWidthFlag := flag.String("tab-width", setting("DIFFVIEW_TAB_WIDTH", cfg.TabWidth), "Tab stop width for diff content (default 8, or $DIFFVIEW_TAB_WIDTH)")
+	idleTimeoutFlag := flag.String("idle-timeout", setting("DIFFVIEW_IDLE_TIMEOUT", cfg.IdleTimeout), "Blank the screen after this long without input, in minutes or as a duration like 90s (default off, or $DIFFVIEW_IDLE_TIMEOUT)")
+	scrollStepFlag := flag.String("scroll-step", setting("DIFFVIEW_SCROLL_STEP", cfg.ScrollStep), "Rows j/k scroll, 1 to 10 (default 1, or $DIFFVIEW_SCROLL_STEP)")
+	pageScrollFlag := flag.String("page-scroll", setting("DIFFVIEW_PAGE_SCROLL", cfg.PageScroll), "How far ctrl+d/ctrl+u move: half or full (default half, or $DIFFVIEW_PAGE_SCROLL)")
+	smoothScrollFlag := flag.String("smooth-scroll", setting("DIFFVIEW_SMOOTH_SCROLL", cfg.SmoothScroll), "Animate page jumps (default false, or $DIFFVIEW_SMOOTH_SCROLL)")
+	groupFlag := flag.String("group", setting("DIFFVIEW_GROUP", cfg.Group), "Group files under headers with combined stats, moved between with } and {: none, package or top (default none, or $DIFFVIEW_GROUP)")
+	themeFlag := flag.String("theme", setting("DIFFVIEW_THEME", cfg.Theme), "Colors: default, colorblind, high-contrast, or symbols to mark changes without color (default default, or $DIFFVIEW_THEME)")
+	syntaxThemeFlag := flag.String("syntax-theme", setting("DIFFVIEW_SYNTAX_THEME", cfg.SyntaxTheme), "Syntax colors: a chroma style like dracula or monokai, or a chroma XML style file, to match your editor (default from -theme, or $DIFFVIEW_SYNTAX_THEME)")
+	colorFlag := flag.String("color", setting("DIFFVIEW_COLOR", cfg.Color), "When to use colors: auto, always or never; 256- and 16-color terminals get the theme's nearest colors (default auto, or $DIFFVIEW_COLOR)")
+	quitIfOneScreen := flag.Bool("quit-if-one-screen", false, "Print the diff and exit if it fits on one screen, like less -F")
+	noAltScreen := flag.Bool("no-alt-screen", false, "Keep the viewer in the main screen, so the diff stays in scrollback after quitting")
+	watchFlag := flag.Bool("watch", false, "Run git diff with the remaining arguments instead of reading stdin, and reload as the working tree changes")
+	notesFlag := flag.String("notes", "", "Record review notes to this JSONL file; c adds a note on the line, hunk or file in view")
+	annotateFlag := flag.String("annotate", "", "Comma-separated annotators to run over the diff, marking the lines they note (available: todo, vet, staticcheck)")
+	var linterFlags []string
+	flag.Func("linter", "Run `name=command` over the changed files as an annotator; {packages} and {files} expand to what changed (repeatable)", func(spec string) error {
+		linterFlags = append(linterFlags, spec)
+		return nil
+	})
+	coverFlag := flag.String("coverprofile", "", "Mark added lines covered or not by this go test -coverprofile output, with each file's changed-line coverage in its header")
+	cpuProfile := flag.String("cpuprofile", "", "Write a CPU profile of the session to this file, for go tool pprof")
+	noTUI := flag.Bool("no-tui", false, "Print the styled diff to stdout instead of opening the viewer, e.g. for CI logs, less -R or core.pager (colors off with -color never or $NO_COLOR)")
+	scriptFlag := flag.String("script", "", "Drive the viewer through the keys in this command file instead of a terminal, recording the screens it snapshots, e.g. for docs screenshots or end-to-end tests")
+	recordFlag := flag.String("record", "", "Write the screens a -script snapshots to this file (default stdout)")
+	flag.Parse()
+	if *cpuProfile != "" {
+		stop, err := startCPUProfile(*cpuProfile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer stop()
+	}
+	if *noTUI && *watchFlag {
+		fmt.Fprintln(os.Stderr, "-watch can't be used with -no-tui")
+		return 1
+	}
+	if *scriptFlag != "" && (*noTUI || *watchFlag) {
+		fmt.Fprintln(os.Stderr, "-script can't be used with -no-tui or -watch")
+		return 1
+	}
+	if *recordFlag != "" && *scriptFlag == "" {
+		fmt.Fprintln(os.Stderr, "-record needs -script")
+		return 1
+	}
+	tabWidth, err := bubbletea.ParseTabWidth(*tabWidthFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	idleTimeout, err := bubbletea.ParseIdleTimeout(*idleTimeoutFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	scrolling, err := bubbletea.ParseScrolling(*scrollStepFlag, *pageScrollFlag, *smoothScrollFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fileGroups, err := bubbletea.ParseFileGroups(*groupFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	theme, err := lipgloss.ThemeByName(*themeFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	colorMode, err := lipgloss.ParseColorMode(*colorFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// Printed diffs are colored for pipes too, such as into less -R, unless
+	// colors are off
+	if *noTUI && colorMode == lipgloss.ColorAuto && os.Getenv("NO_COLOR") == "" {
+		colorMode = lipgloss.ColorAlways
+	}
+	profile := colorMode.Profile(os.Stdout)
+	theme = lipgloss.ForProfile(theme, profile)
+	bubbletea.SetColorProfile(profile)
+	syntaxStyle, err := chroma.SyntaxStyle(*syntaxThemeFlag, theme.Palette())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// Git runs external diff programs with the file count in the environment
+	external := os.Getenv("GIT_DIFF_PATH_TOTAL") != "" && flag.NArg() == 7
+	// Two paths are compared directly, without git
+	compare := !*watchFlag && flag.NArg() == 2
+
+	// Check if stdin is a pipe (not a terminal); watch mode and external diffs
+	// get their diff from git, and comparisons from the files
 	stat, err := os.Stdin.Stat()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error checking stdin:", err)
-		os.Exit(1)
+		return 1
 	}
-	if (stat.Mode() & os.ModeCharDevice) != 0 {
-		fmt.Fprintln(os.Stderr, "Usage: git diff | diffview")
-		os.Exit(1)
+	if (stat.Mode()&os.ModeCharDevice) != 0 && !*watchFlag && !external && !compare {
+		fmt.Fprintln(os.Stderr, "Usage: git diff | diffview [-tab-width N] [-idle-timeout MINUTES] [-group package|top] [-theme NAME] [-syntax-theme NAME] [-color WHEN] [-quit-if-one-screen] [-no-alt-screen] [-no-tui] [-script FILE [-record FILE]]")
+		fmt.Fprintln(os.Stderr, "       diffview -watch [git diff args]")
+		fmt.Fprintln(os.Stderr, "       diffview OLD NEW  (compare two files or directories)")
+		fmt.Fprintln(os.Stderr, "       diffview init-git [-local] [pager|external|difftool]")
+		fmt.Fprintln(os.Stderr, "       diffview config validate [FILE] | diffview config init [-force]")
+		fmt.Fprintln(os.Stderr, "       diffview notes FILE  (print review notes as Markdown)")
+		return 1
 	}
 
 	// Set up context with signal handling for graceful shutdown
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Annotators and symbol resolution read the files the diff's paths name
+	dir := repoRoot(ctx)
+	annotators, err := ParseAnnotators(*annotateFlag, linterFlags, dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *coverFlag != "" {
+		annotator, err := coverAnnotator(*coverFlag, dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		annotators = append(annotators, annotator)
+	}
+
+	pane, err := tmux.FromEnv(os.Getenv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
 	// Set up syntax highlighting
-	theme := lipgloss.DefaultTheme()
 	detector := chroma.NewDetector()
-	tokenizer, err := chroma.NewTokenizer(chroma.StyleFromPalette(theme.Palette()))
+	tokenizer, err := chroma.NewTokenizer(syntaxStyle)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error setting up syntax highlighting:", err)
-		os.Exit(1)
+		return 1
+	}
+
+	viewerOpts := []bubbletea.ViewerOption{
+		bubbletea.WithViewerLanguageDetector(detector),
+		bubbletea.WithViewerTokenizer(tokenizer),
+		bubbletea.WithViewerWordDiffer(worddiff.NewDiffer()),
+		bubbletea.WithViewerTabWidth(tabWidth),
+		bubbletea.WithViewerIdleTimeout(idleTimeout),
+		viewerEditorOption(),
+		bubbletea.WithViewerPane(paneFunc(pane, dir)),
+		bubbletea.WithViewerScrolling(scrolling),
+		bubbletea.WithViewerFileGroups(fileGroups),
+		bubbletea.WithViewerSecretDetector(redact.NewSecretDetector()),
+		bubbletea.WithViewerAnnotators(annotators...),
+		bubbletea.WithViewerHunkGrouper(symbols.NewGrouper()),
+		bubbletea.WithViewerMoveDetector(moves.NewDetector()),
+		bubbletea.WithViewerDependencySummarizer(depdiff.NewSummarizer()),
+	}
+	if !compare {
+		viewerOpts = append(viewerOpts,
+			bubbletea.WithViewerSymbolResolver(symbols.NewResolver(symbols.DirSource(dir))),
+			bubbletea.WithViewerStructuralDiffer(configdiff.NewDiffer(symbols.DirSource(dir))),
+		)
+	}
+	if *notesFlag != "" {
+		viewerOpts = append(viewerOpts, bubbletea.WithViewerAnnotations(jsonl.NewAnnotationStore(), *notesFlag))
+	}
+	if *noAltScreen {
+		viewerOpts = append(viewerOpts, bubbletea.WithViewerNoAltScreen())
+	}
+	if *watchFlag {
+		if err := runWatch(ctx, theme, viewerOpts, flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	if *noTUI {
+		viewerOpts = append(viewerOpts, bubbletea.WithViewerPrint(os.Stdout, printWidth(), profile))
+	}
+	if *scriptFlag != "" {
+		script, err := loadScript(*scriptFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		record := os.Stdout
+		if *recordFlag != "" {
+			record, err = os.Create(*recordFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "failed to create recording:", err)
+				return 1
+			}
+			defer record.Close()
+		}
+		viewerOpts = append(viewerOpts, bubbletea.WithViewerScript(script, record))
+	}
+	// Without a terminal size there is no screen to fit, so always page
+	if *quitIfOneScreen {
+		if width, height, err := term.GetSize(os.Stdout.Fd()); err == nil {
+			viewerOpts = append(viewerOpts, bubbletea.WithViewerQuitIfOneScreen(os.Stdout, width, height))
+		}
 	}
 
+	if external {
+		if err := runExternal(ctx, theme, viewerOpts, flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if compare {
+		if err := runCompare(ctx, theme, viewerOpts, flag.Arg(0), flag.Arg(1)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	// A malformed hunk is skipped, and a banner says so, rather than
+	// failing the whole diff
 	app := &App{
 		Stdin:  os.Stdin,
-		Parser: gitdiff.NewParser(),
-		Viewer: bubbletea.NewViewer(theme,
-			bubbletea.WithViewerLanguageDetector(detector),
-			bubbletea.WithViewerTokenizer(tokenizer),
-			bubbletea.WithViewerWordDiffer(worddiff.NewDiffer()),
-		),
+		Parser: gitdiff.NewParser(gitdiff.WithLenient()),
+		Viewer: bubbletea.NewViewer(theme, viewerOpts...),
 	}
 
 	if err := app.Run(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return 1
+	}
+	return 0
+}
+
+// loadScript reads the command file at path that -script drives the viewer
+// through.
+func loadScript(path string) (*bubbletea.Script, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open script: %w", err)
+	}
+	defer f.Close()
+	script, err := bubbletea.ParseScript(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return script, nil
+}
+
+// runWatch shows the diff that git diff with args produces, reloading it
+// whenever the working tree changes. Unlike reading stdin, an empty diff is
+// fine: changes may be about to come.
+func runWatch(ctx context.Context, theme diffview.Theme, opts []bubbletea.ViewerOption, args []string) error {
+	runner := git.NewRunner()
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	root, err := runner.TopLevel(ctx, cwd)
+	if err != nil {
+		return err
+	}
+
+	parser := gitdiff.NewParser(gitdiff.WithLenient())
+	load := func() (*diffview.Diff, error) {
+		out, err := runner.WorktreeDiff(ctx, cwd, args...)
+		if err != nil {
+			return nil, err
+		}
+		return parser.Parse(strings.NewReader(out))
+	}
+	diff, err := load()
+	if err != nil {
+		return err
+	}
+
+	reloads := make(chan *diffview.Diff)
+	go func() {
+		// Best-effort: if watching fails, the diff just stops updating
+		_ = watch.Watch(ctx, root, watch.DefaultDebounce, func() {
+			// A failed reload, such as mid-rebase, is retried on the next change
+			d, err := load()
+			if err != nil {
+				return
+			}
+			select {
+			case reloads <- d:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	opts = append(opts, bubbletea.WithViewerReloads(reloads))
+	return bubbletea.NewViewer(theme, opts...).View(ctx, diff)
+}
+
+// runExternal shows the change to the single file git passes as an external
+// diff program. A file whose content didn't change, such as after a mode
+// change, has nothing to show.
+func runExternal(ctx context.Context, theme diffview.Theme, opts []bubbletea.ViewerOption, args []string) error {
+	ext, err := ParseExternalDiffArgs(args)
+	if err != nil {
+		return err
+	}
+	runner := git.NewRunner()
+	oldFile, newFile := ext.OldFile, ext.NewFile
+	if command := textConvFor(ctx, runner, ext.Path); command != "" {
+		tmp, err := os.MkdirTemp("", "diffview-textconv")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		if oldFile, err = convertExternalFile(ctx, runner, command, oldFile, filepath.Join(tmp, "old")); err != nil {
+			return err
+		}
+		if newFile, err = convertExternalFile(ctx, runner, command, newFile, filepath.Join(tmp, "new")); err != nil {
+			return err
+		}
+	}
+	out, err := runner.DiffFiles(ctx, oldFile, newFile)
+	if err != nil {
+		return err
+	}
+	diff, err := gitdiff.NewParser().Parse(strings.NewReader(out))
+	if err != nil {
+		return err
+	}
+	if len(diff.Files) == 0 {
+		return nil
+	}
+	ext.Label(diff)
+	return bubbletea.NewViewer(theme, opts...).View(ctx, diff)
+}
+
+// runCompare shows the differences between two files or directory trees,
+// compared without git.
+func runCompare(ctx context.Context, theme diffview.Theme, opts []bubbletea.ViewerOption, oldPath, newPath string) error {
+	diff, err := dirdiff.Compare(oldPath, newPath)
+	if err != nil {
+		return err
+	}
+	if len(diff.Files) == 0 {
+		return ErrNoChanges
+	}
+	return bubbletea.NewViewer(theme, opts...).View(ctx, diff)
+}
+
+// runNotes prints the review notes recorded with -notes as a Markdown
+// summary, ready to paste into a pull request.
+func runNotes(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: diffview notes FILE")
+	}
+	notes, err := jsonl.NewAnnotationStore().Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+	if len(notes) == 0 {
+		return fmt.Errorf("no notes in %s", args[0])
+	}
+	fmt.Print(diffview.ReviewSummary(notes))
+	return nil
+}
+
+// runParse converts the diff on stdin to the JSON model of diffview.Diff on
+// stdout, for tools downstream of diffview. Sections a lenient parse left
+// out are listed under "skipped".
+func runParse(args []string) error {
+	fset := flag.NewFlagSet("parse", flag.ContinueOnError)
+	asJSON := fset.Bool("json", false, "Write the parsed diff as JSON")
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "Usage: diffview parse --json < DIFF")
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if !*asJSON || fset.NArg() > 0 {
+		fset.Usage()
+		return errors.New("usage: diffview parse --json < DIFF")
+	}
+	return ParseJSON(os.Stdin, os.Stdout)
+}
+
+// ParseJSON parses the diff in r and writes it to w as JSON.
+func ParseJSON(r io.Reader, w io.Writer) error {
+	diff, err := gitdiff.NewParser(gitdiff.WithLenient()).Parse(r)
+	if err != nil {
+		return fmt.Errorf("failed to parse diff: %w", err)
+	}
+	return diffview.EncodeDiff(w, diff)
+}
+
+// textConvFor returns the textconv command .gitattributes selects for path,
+// which git doesn't apply to the files it hands external diff programs.
+// Outside a repository, such as for git difftool --no-index, there is none.
+func textConvFor(ctx context.Context, runner *git.Runner, path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	root, err := runner.TopLevel(ctx, cwd)
+	if err != nil {
+		return ""
+	}
+	// Best-effort: without the command the raw files are diffed
+	command, _ := runner.TextConv(ctx, root, path)
+	return command
+}
+
+// convertExternalFile writes the text form of file to dst and returns dst,
+// or returns file as is if it stands for a missing side of the diff.
+func convertExternalFile(ctx context.Context, runner *git.Runner, command, file, dst string) (string, error) {
+	if file == os.DevNull {
+		return file, nil
+	}
+	text, err := runner.ConvertText(ctx, command, file)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(dst, []byte(text), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write converted file: %w", err)
+	}
+	return dst, nil
+}
+
+// gitIntegrations maps each init-git mode to the git config it sets.
+var gitIntegrations = map[string][][2]string{
+	// Page the output of git diff and git show through the viewer
+	"pager": {
+		{"pager.diff", "diffview"},
+		{"pager.show", "diffview"},
+	},
+	// Have git diff open the viewer for each changed file
+	"external": {
+		{"diff.external", "diffview"},
+	},
+	// Make diffview the tool git difftool opens, one file at a time
+	"difftool": {
+		{"diff.tool", "diffview"},
+		{"difftool.diffview.cmd", `diffview "$MERGED" "$LOCAL" . . "$REMOTE" . .`},
+		{"difftool.prompt", "false"},
+	},
+}
+
+// runInitGit configures git to use diffview, globally unless -local is given.
+func runInitGit(args []string) error {
+	fset := flag.NewFlagSet("init-git", flag.ContinueOnError)
+	local := fset.Bool("local", false, "Configure the current repository instead of the global git config")
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "Usage: diffview init-git [-local] [pager|external|difftool]")
+		fmt.Fprintln(fset.Output(), "  pager     page git diff and git show through diffview (default)")
+		fmt.Fprintln(fset.Output(), "  external  set diffview as diff.external, opening it for each changed file")
+		fmt.Fprintln(fset.Output(), "  difftool  set diffview as the tool for git difftool")
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	mode := "pager"
+	if fset.NArg() > 1 {
+		fset.Usage()
+		return errors.New("init-git takes at most one mode")
+	}
+	if fset.NArg() == 1 {
+		mode = fset.Arg(0)
+	}
+	settings, ok := gitIntegrations[mode]
+	if !ok {
+		return fmt.Errorf("unknown init-git mode %q: use pager, external or difftool", mode)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	runner := git.NewRunner()
+	for _, kv := range settings {
+		if err := runner.SetConfig(context.Background(), cwd, !*local, kv[0], kv[1]); err != nil {
+			return err
+		}
+		fmt.Printf("git config %s %q\n", kv[0], kv[1])
+	}
+	return nil
+}
+
+// defaultPrintWidth is the width printed output wraps at when neither the
+// terminal nor $COLUMNS says otherwise.
+const defaultPrintWidth = 80
+
+// builtinAnnotators maps each annotator -annotate accepts, besides the
+// linters lint.ParseLinter knows, to its constructor.
+var builtinAnnotators = map[string]func() diffview.Annotator{
+	"todo": func() diffview.Annotator { return todo.NewAnnotator() },
+}
+
+// ParseAnnotators returns the annotators named in a comma-separated list,
+// with the named linters and those in custom, each "name=command", run
+// together in dir.
+func ParseAnnotators(value string, custom []string, dir string) ([]diffview.Annotator, error) {
+	var annotators []diffview.Annotator
+	var linters []lint.Linter
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if newAnnotator, ok := builtinAnnotators[name]; ok {
+			annotators = append(annotators, newAnnotator())
+			continue
+		}
+		if name != lint.Vet().Name && name != lint.Staticcheck().Name {
+			return nil, fmt.Errorf("unknown annotator %q (available: todo, vet, staticcheck)", name)
+		}
+		linter, _ := lint.ParseLinter(name)
+		linters = append(linters, linter)
+	}
+	for _, spec := range custom {
+		linter, err := lint.ParseLinter(spec)
+		if err != nil {
+			return nil, err
+		}
+		linters = append(linters, linter)
+	}
+	if len(linters) > 0 {
+		annotators = append(annotators, lint.NewAnnotator(dir, linters))
+	}
+	return annotators, nil
+}
+
+// coverAnnotator loads the coverage profile at path for the module whose
+// go.mod is in dir.
+func coverAnnotator(path, dir string) (diffview.Annotator, error) {
+	profile, err := cover.LoadProfile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading coverage profile: %w", err)
+	}
+	module, err := cover.ModulePath(dir)
+	if err != nil {
+		return nil, fmt.Errorf("finding module for coverage profile: %w", err)
+	}
+	return cover.NewAnnotator(profile, module), nil
+}
+
+// repoRoot returns the directory the diff's paths are relative to: the top
+// of the repository, or the current directory outside one.
+func repoRoot(ctx context.Context) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	if root, err := git.NewRunner().TopLevel(ctx, cwd); err == nil {
+		return root
+	}
+	return cwd
+}
+
+// startCPUProfile starts writing a CPU profile to path, returning the
+// function that finishes it.
+func startCPUProfile(path string) (stop func(), err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		f.Close()
+	}, nil
+}
+
+// printWidth returns the width for printed output: the terminal's when
+// stdout is one, else $COLUMNS, as set by many pagers and CI systems.
+func printWidth() int {
+	if width, _, err := term.GetSize(os.Stdout.Fd()); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return defaultPrintWidth
+}
+
+// editorFunc returns the command for opening lines in the user's editor, or
+// nil if none is configured. Set DIFFVIEW_EDITOR to a template like
+// "code -g {file}:{line}" to override $VISUAL and $EDITOR.
+func editorFunc() bubbletea.EditorFunc {
+	if e := editor.NewSystem(); e != nil {
+		return e.Command
+	}
+	return nil
+}
+
+// paneFunc returns the command for opening views of a file in a tmux pane
+// started in dir, or nil outside tmux. Set DIFFVIEW_TMUX_PANE to below or
+// window to open it somewhere other than to the right.
+func paneFunc(pane *tmux.Pane, dir string) bubbletea.PaneFunc {
+	if pane == nil {
+		return nil
+	}
+	pane.Dir = dir
+	return func(view bubbletea.PaneView, path string, line int) *exec.Cmd {
+		switch view {
+		case bubbletea.PaneBlame:
+			return pane.Command(tmux.BlameCommand(path, line))
+		case bubbletea.PaneLog:
+			return pane.Command(tmux.LogCommand(path))
+		default:
+			return pane.Command(tmux.FileCommand(path, line))
+		}
+	}
+}
+
+// viewerEditorOption returns the option for opening lines in an editor: the
+// Neovim running at $DIFFVIEW_NVIM_SERVER or $NVIM, if there is one, or else
+// the user's editor.
+func viewerEditorOption() bubbletea.ViewerOption {
+	if nv := editor.NewSystemNeovim(); nv != nil {
+		return bubbletea.WithViewerRemoteEditor(nv.Command)
 	}
+	return bubbletea.WithViewerEditor(editorFunc())
 }
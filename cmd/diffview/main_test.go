@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"io/fs"
 	"strings"
 	"testing"
 
@@ -117,3 +118,105 @@ func TestApp_Run_EmptyDiff(t *testing.T) {
 	require.ErrorIs(t, err, main.ErrNoChanges)
 	assert.False(t, viewerCalled, "viewer should not be called for empty diff")
 }
+
+func TestParseExternalDiffArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("modified file", func(t *testing.T) {
+		t.Parallel()
+
+		ext, err := main.ParseExternalDiffArgs([]string{
+			"src/main.go", "/tmp/old_main.go", "abc123", "100644", "src/main.go", "0000000", "100755",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, main.ExternalDiff{
+			Path:    "src/main.go",
+			OldFile: "/tmp/old_main.go",
+			NewFile: "src/main.go",
+			OldMode: 0o100644,
+			NewMode: 0o100755,
+		}, ext)
+	})
+
+	t.Run("added file has no old mode", func(t *testing.T) {
+		t.Parallel()
+
+		ext, err := main.ParseExternalDiffArgs([]string{
+			"new.go", "/dev/null", ".", ".", "new.go", "def456", "100644",
+		})
+
+		require.NoError(t, err)
+		assert.Zero(t, ext.OldMode)
+		assert.Equal(t, "/dev/null", ext.OldFile)
+	})
+
+	t.Run("rejects other arguments", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := main.ParseExternalDiffArgs([]string{"a.go", "b.go"})
+		assert.Error(t, err)
+
+		_, err = main.ParseExternalDiffArgs([]string{"a.go", "/tmp/a", "abc", "rw-r--r--", "a.go", "def", "100644"})
+		assert.Error(t, err)
+	})
+}
+
+func TestExternalDiff_Label(t *testing.T) {
+	t.Parallel()
+
+	ext := main.ExternalDiff{Path: "src/main.go", OldMode: 0o100644, NewMode: 0o100755}
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{OldPath: "/tmp/old_main.go", NewPath: "src/main.go", Operation: diffview.FileModified},
+	}}
+
+	ext.Label(diff)
+
+	assert.Equal(t, "src/main.go", diff.Files[0].OldPath)
+	assert.Equal(t, "src/main.go", diff.Files[0].NewPath)
+	assert.Equal(t, fs.FileMode(0o100755), diff.Files[0].NewMode)
+
+	// Added files keep their empty old path
+	added := &diffview.Diff{Files: []diffview.FileDiff{{NewPath: "tmp/x", Operation: diffview.FileAdded}}}
+	main.ExternalDiff{Path: "new.go"}.Label(added)
+	assert.Empty(t, added.Files[0].OldPath)
+	assert.Equal(t, "new.go", added.Files[0].NewPath)
+}
+
+func TestParseAnnotators(t *testing.T) {
+	t.Parallel()
+
+	annotators, err := main.ParseAnnotators("todo, vet,staticcheck", []string{"golangci=golangci-lint run {packages}"}, "/repo")
+	require.NoError(t, err)
+	require.Len(t, annotators, 2)
+	assert.Equal(t, "todo", annotators[0].Name())
+	assert.Equal(t, "lint", annotators[1].Name(), "linters run as one annotator")
+
+	annotators, err = main.ParseAnnotators("", nil, "")
+	require.NoError(t, err)
+	assert.Empty(t, annotators)
+
+	_, err = main.ParseAnnotators("coverage", nil, "")
+	assert.ErrorContains(t, err, `unknown annotator "coverage"`)
+
+	_, err = main.ParseAnnotators("", []string{"eslint"}, "")
+	assert.Error(t, err)
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Parallel()
+
+	input := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-old\n+new\n"
+	var out strings.Builder
+
+	err := main.ParseJSON(strings.NewReader(input), &out)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `"schema_version": 1`)
+	diff, err := diffview.DecodeDiff(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, "main.go", diff.Files[0].NewPath)
+	require.Len(t, diff.Files[0].Hunks, 1)
+	assert.Equal(t, "new\n", diff.Files[0].Hunks[0].Lines[1].Content)
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -10,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -19,13 +19,17 @@ import (
 	"github.com/fwojciec/diffstory/bubbletea"
 	"github.com/fwojciec/diffstory/chroma"
 	"github.com/fwojciec/diffstory/clipboard"
+	"github.com/fwojciec/diffstory/evalpipeline"
+	"github.com/fwojciec/diffstory/fs"
 	"github.com/fwojciec/diffstory/gemini"
 	"github.com/fwojciec/diffstory/git"
 	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/fwojciec/diffstory/github"
 	"github.com/fwojciec/diffstory/jsonl"
 	"github.com/fwojciec/diffstory/lipgloss"
+	"github.com/fwojciec/diffstory/redact"
 	"github.com/fwojciec/diffstory/worddiff"
-	"golang.org/x/sync/errgroup"
+	"github.com/muesli/termenv"
 )
 
 // ErrNoCases is returned when the input file contains no cases.
@@ -34,11 +38,67 @@ var ErrNoCases = errors.New("no cases to review")
 // judgmentsPath returns the path for the judgments file given an input path.
 // foo.jsonl -> foo-judgments.jsonl
 func judgmentsPath(inputPath string) string {
+	return companionPath(inputPath, "judgments")
+}
+
+// tombstonesPath returns the path for the tombstones file given an input path.
+// foo.jsonl -> foo-tombstones.jsonl
+func tombstonesPath(inputPath string) string {
+	return companionPath(inputPath, "tombstones")
+}
+
+// goldPath returns the path for the log of cases promoted with gold labels
+// given an input path. foo.jsonl -> foo-gold.jsonl
+func goldPath(inputPath string) string {
+	return companionPath(inputPath, "gold")
+}
+
+// loadGold loads the cases promoted with gold labels for the dataset at
+// inputPath. A dataset with none promoted has no gold log.
+func loadGold(inputPath string) ([]diffview.EvalCase, error) {
+	promoted, err := jsonl.NewLoader().Load(goldPath(inputPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return promoted, err
+}
+
+// sessionsPath returns the path for the review session log given an input
+// path. foo.jsonl -> foo-sessions.jsonl
+func sessionsPath(inputPath string) string {
+	return companionPath(inputPath, "sessions")
+}
+
+// companionPath names a file kept alongside the dataset at inputPath by
+// adding -kind before its extension.
+func companionPath(inputPath, kind string) string {
 	dir := filepath.Dir(inputPath)
 	base := filepath.Base(inputPath)
 	ext := filepath.Ext(base)
 	name := strings.TrimSuffix(base, ext)
-	return filepath.Join(dir, name+"-judgments"+ext)
+	return filepath.Join(dir, name+"-"+kind+ext)
+}
+
+// takeColorFlag removes a --color=WHEN or --color WHEN option from
+// os.Args, wherever it is, and returns when to use colors: auto, always or
+// never. Without the option, $DIFFVIEW_COLOR is used.
+func takeColorFlag() (lipgloss.ColorMode, error) {
+	value := os.Getenv("DIFFVIEW_COLOR")
+	args := []string{os.Args[0]}
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		switch {
+		case strings.HasPrefix(arg, "--color="):
+			value = strings.TrimPrefix(arg, "--color=")
+		case arg == "--color" && i+1 < len(os.Args):
+			i++
+			value = os.Args[i]
+		default:
+			args = append(args, arg)
+		}
+	}
+	os.Args = args
+	return lipgloss.ParseColorMode(value)
 }
 
 func main() {
@@ -49,14 +109,33 @@ func main() {
 }
 
 func run() error {
+	colorMode, err := takeColorFlag()
+	if err != nil {
+		return err
+	}
+	profile := colorMode.Profile(os.Stdout)
+	bubbletea.SetColorProfile(profile)
+
 	if len(os.Args) < 2 {
 		return fmt.Errorf(`usage: evalreview <command|cases.jsonl>
 
 Commands:
   collect   Extract diffs from git history
   classify  Classify eval cases from JSONL
-
-With a .jsonl file: opens the review UI`)
+  judge     Grade classifications with an LLM, alongside human judgments
+  gc        Write dataset with deleted cases removed and gold labels applied
+  export    Write a paths-only copy with code content removed for sharing
+  sessions  List earlier review sessions, or open one read-only
+  score     Score model classifications against gold labels
+  compare   Show cases two datasets classify differently, side by side
+  dataset   Merge, split, filter or sample datasets
+
+With a .jsonl file: opens the review UI
+(--color=WHEN, or DIFFVIEW_COLOR, sets when to use colors: auto, always or
+never; set DIFFVIEW_TAB_WIDTH to change the tab stop width, default 8,
+DIFFVIEW_IDLE_TIMEOUT to blank the screen after that many idle minutes,
+DIFFVIEW_THEME to colorblind, high-contrast or symbols, and
+DIFFVIEW_SYNTAX_THEME to a chroma style like dracula or a style file)`)
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -67,565 +146,995 @@ With a .jsonl file: opens the review UI`)
 		return runCollect(ctx)
 	case "classify":
 		return runClassify(ctx)
+	case "judge":
+		return runJudge(ctx)
+	case "gc":
+		return runGC()
+	case "export":
+		return runExport()
+	case "sessions":
+		return runSessions(ctx, profile)
+	case "score":
+		return runScore()
+	case "compare":
+		return runCompare(ctx)
+	case "dataset":
+		return runDataset()
 	default:
 		// Assume it's a file path - run the review UI
-		return runReview(ctx, os.Args[1])
+		return runReview(ctx, os.Args[1], profile)
 	}
 }
 
-func runReview(ctx context.Context, inputPath string) error {
+func runReview(ctx context.Context, inputPath string, profile termenv.Profile) error {
+	startedAt := time.Now()
+
 	// Load cases
 	loader := jsonl.NewLoader()
 	cases, err := loader.Load(inputPath)
 	if err != nil {
 		return fmt.Errorf("error loading cases: %w", err)
 	}
+	legacyIDs := jsonl.WithLegacyCaseIDs(diffview.NewLegacyCaseIDs(cases))
+	warnDuplicates(cases)
+	warnInvalid(cases)
+
+	// Hide cases deleted in earlier sessions
+	tombstoneStore := jsonl.NewTombstoneStore(legacyIDs)
+	tombstoneFile := tombstonesPath(inputPath)
+	tombstones, err := tombstoneStore.Load(tombstoneFile)
+	if err != nil {
+		return fmt.Errorf("error loading tombstones: %w", err)
+	}
+	cases = diffview.ExcludeTombstoned(cases, tombstones)
 
 	if len(cases) == 0 {
 		return ErrNoCases
 	}
 
+	promoted, err := loadGold(inputPath)
+	if err != nil {
+		return fmt.Errorf("error loading gold labels: %w", err)
+	}
+	cases = diffview.ApplyGold(cases, promoted)
+
 	// Load existing judgments if any
-	store := jsonl.NewStore()
+	store := jsonl.NewStore(legacyIDs)
 	outputPath := judgmentsPath(inputPath)
 	existingJudgments, err := store.Load(outputPath)
 	if err != nil {
 		return fmt.Errorf("error loading judgments: %w", err)
 	}
 
+	opts, err := evalModelOptions(profile)
+	if err != nil {
+		return err
+	}
+	opts = append(opts,
+		bubbletea.WithJudgmentStore(store, outputPath),
+		bubbletea.WithTombstoneStore(tombstoneStore, tombstoneFile),
+		bubbletea.WithGoldSaver(jsonl.NewSaver(), goldPath(inputPath)),
+	)
+	if len(existingJudgments) > 0 {
+		opts = append(opts, bubbletea.WithExistingJudgments(existingJudgments))
+	}
+
+	final, err := runEvalModel(ctx, bubbletea.NewEvalModel(cases, opts...))
+	if err != nil {
+		return err
+	}
+
+	// A save still running when the reviewer quit, or one that failed, is
+	// finished here
+	if err := final.SaveJudgments(); err != nil {
+		return fmt.Errorf("error saving judgments: %w", err)
+	}
+
+	// Record where the session left the dataset, for reviewing it later
+	session := newReviewSession(startedAt, time.Now(), final)
+	if err := jsonl.NewSessionStore().Append(sessionsPath(inputPath), session); err != nil {
+		return fmt.Errorf("error recording session: %w", err)
+	}
+	fmt.Println(SessionSummary(session))
+	return nil
+}
+
+// warnDuplicates reports cases that appear more than once in a dataset,
+// which share judgments and skew scores.
+func warnDuplicates(cases []diffview.EvalCase) {
+	if dups := diffview.DuplicateCaseIDs(cases); len(dups) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d cases appear more than once; remove the copies with evalreview dataset merge\n", len(dups))
+	}
+}
+
+// warnInvalid reports cases whose story doesn't fit their diff, as
+// diffview.ValidateStory checks it, such as by referencing hunks that don't
+// exist.
+func warnInvalid(cases []diffview.EvalCase) {
+	invalid := 0
+	for _, c := range cases {
+		if c.Story != nil && len(diffview.ValidateStory(&c.Input.Diff, c.Story)) > 0 {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d cases have stories that don't fit their diffs; the status bar counts each one's issues\n", invalid)
+	}
+}
+
+// newReviewSession records the state m ended in: the cases left in the
+// dataset and every judgment.
+func newReviewSession(startedAt, endedAt time.Time, m bubbletea.EvalModel) diffview.ReviewSession {
+	cases := m.Cases()
+	ids := make([]string, len(cases))
+	for i, c := range cases {
+		ids[i] = c.CaseID()
+	}
+	return diffview.ReviewSession{
+		StartedAt: startedAt,
+		EndedAt:   endedAt,
+		CaseIDs:   ids,
+		Judgments: m.Judgments(),
+	}
+}
+
+// evalModelOptions returns the display settings shared by every review UI,
+// read from DIFFVIEW_* environment variables, with colors for profile.
+func evalModelOptions(profile termenv.Profile) ([]bubbletea.EvalModelOption, error) {
+	tabWidth, err := bubbletea.ParseTabWidth(os.Getenv("DIFFVIEW_TAB_WIDTH"))
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout, err := bubbletea.ParseIdleTimeout(os.Getenv("DIFFVIEW_IDLE_TIMEOUT"))
+	if err != nil {
+		return nil, err
+	}
+
 	// Set up syntax highlighting
-	theme := lipgloss.DefaultTheme()
+	theme, err := lipgloss.ThemeByName(os.Getenv("DIFFVIEW_THEME"))
+	if err != nil {
+		return nil, err
+	}
+	theme = lipgloss.ForProfile(theme, profile)
+	syntaxStyle, err := chroma.SyntaxStyle(os.Getenv("DIFFVIEW_SYNTAX_THEME"), theme.Palette())
+	if err != nil {
+		return nil, err
+	}
 	detector := chroma.NewDetector()
-	tokenizer, err := chroma.NewTokenizer(chroma.StyleFromPalette(theme.Palette()))
+	tokenizer, err := chroma.NewTokenizer(syntaxStyle)
 	if err != nil {
-		return fmt.Errorf("error setting up syntax highlighting: %w", err)
+		return nil, fmt.Errorf("error setting up syntax highlighting: %w", err)
 	}
 
-	// Create model with options
-	opts := []bubbletea.EvalModelOption{
-		bubbletea.WithJudgmentStore(store, outputPath),
+	return []bubbletea.EvalModelOption{
 		bubbletea.WithEvalStyles(theme.Styles()),
 		bubbletea.WithEvalLanguageDetector(detector),
 		bubbletea.WithEvalTokenizer(tokenizer),
 		bubbletea.WithEvalWordDiffer(worddiff.NewDiffer()),
-		bubbletea.WithClipboard(clipboard.NewPBCopy()),
-	}
-	if len(existingJudgments) > 0 {
-		opts = append(opts, bubbletea.WithExistingJudgments(existingJudgments))
-	}
-
-	m := bubbletea.NewEvalModel(cases, opts...)
+		bubbletea.WithEvalTabWidth(tabWidth),
+		bubbletea.WithEvalIdleTimeout(idleTimeout),
+		bubbletea.WithClipboard(clipboard.NewSystem()),
+		bubbletea.WithPreferencesStore(fs.NewPreferencesStore(fs.DefaultPreferencesPath())),
+	}, nil
+}
 
-	// Run the TUI
+// runEvalModel runs the review UI until the user quits, returning the model
+// as it was left. If the program is killed, say by SIGTERM, it saves the
+// judgments before returning the error, so the session's work survives.
+func runEvalModel(ctx context.Context, m bubbletea.EvalModel) (bubbletea.EvalModel, error) {
 	p := tea.NewProgram(m,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
+		tea.WithReportFocus(), // review time stops while the terminal is in the background
 		tea.WithContext(ctx),
 	)
 
-	if _, err := p.Run(); err != nil {
-		return err
+	final, err := p.Run()
+	if err != nil {
+		if m, ok := final.(bubbletea.EvalModel); ok && errors.Is(err, tea.ErrProgramKilled) {
+			if saveErr := m.SaveJudgments(); saveErr != nil {
+				err = errors.Join(err, fmt.Errorf("error saving judgments: %w", saveErr))
+			}
+		}
+		return bubbletea.EvalModel{}, err
 	}
-	return nil
+	return final.(bubbletea.EvalModel), nil
 }
 
-// Collector extracts diffs from git history.
-type Collector struct {
-	Output   io.Writer
-	RepoPath string
-	RepoName string
-	Limit    int
-	MinLines int
-	MaxLines int
-	MaxBytes int // Maximum serialized case size in bytes (0 = no limit)
-	Git      diffview.GitRunner
-}
-
-// Run extracts diffs from git history and writes JSONL output.
-// It first tries to extract PR-level cases from merge commits.
-// If no merge commits are found, it falls back to individual commits.
-func (c *Collector) Run(ctx context.Context) error {
-	// Try PR-level extraction first
-	mergeHashes, err := c.Git.MergeCommits(ctx, c.RepoPath, c.Limit)
-	if err != nil {
+func runCollect(ctx context.Context) error {
+	fs := flag.NewFlagSet("collect", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "Maximum number of commits to extract")
+	repo := fs.String("repo", "", "Repository name (defaults to directory name)")
+	minLines := fs.Int("min-lines", 5, "Minimum lines changed (skip smaller commits)")
+	maxLines := fs.Int("max-lines", 2000, "Maximum lines changed (skip larger PRs/commits)")
+	maxBytes := fs.Int("max-bytes", 500000, "Maximum serialized case size in bytes (skip larger cases)")
+	branch := fs.String("branch", "", "Branch to collect from (defaults to the checked out branch)")
+	since := fs.String("since", "", "Only collect changes after this date (e.g. 2024-01-01)")
+	until := fs.String("until", "", "Only collect changes before this date")
+	pathInclude := fs.String("path-include", "", "Comma-separated globs of the files to keep (e.g. '*.go,cmd/**')")
+	pathExclude := fs.String("path-exclude", "", "Comma-separated globs of the files to drop (e.g. '*_test.go')")
+	language := fs.String("language", "", "Keep only files in this language (e.g. go)")
+	maxFiles := fs.Int("max-files", 0, "Maximum files changed, after dropping filtered files (0 = no limit)")
+	squash := fs.Bool("squash", false, "Treat commits whose subjects end in (#N) as squash-merged PRs when there are no merge commits")
+	githubCheck := fs.Bool("github", false, "With -squash, confirm PRs and fetch their branch and description from GitHub (uses GITHUB_TOKEN if set)")
+	stratify := fs.String("stratify", "", "Sample evenly across each month, author or size instead of taking the latest changes")
+
+	manifest := fs.String("manifest", "", "YAML file listing repositories to collect from instead of one")
+	workers := fs.Int("workers", 4, "With -manifest, number of repositories collected at once")
+	cloneDir := fs.String("clone-dir", "", "With -manifest, where to clone repositories without a checkout (defaults to the user cache directory)")
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
 		return err
 	}
 
-	if len(mergeHashes) > 0 {
-		return c.runPRLevel(ctx, mergeHashes)
+	opts := evalpipeline.CollectorOptions{
+		Limit:    *limit,
+		MinLines: *minLines,
+		MaxLines: *maxLines,
+		MaxBytes: *maxBytes,
+		Branch:   *branch,
+		Since:    *since,
+		Until:    *until,
+		Stratify: *stratify,
+
+		PathInclude: splitList(*pathInclude),
+		PathExclude: splitList(*pathExclude),
+		Language:    *language,
+		Languages:   chroma.NewDetector(),
+		MaxFiles:    *maxFiles,
+
+		SquashMerges: *squash,
 	}
 
-	// Fall back to commit-level extraction
-	return c.runCommitLevel(ctx)
-}
+	if *manifest != "" {
+		if *githubCheck {
+			return fmt.Errorf("-github is not supported with -manifest")
+		}
+		return runCollectManifest(ctx, *manifest, *cloneDir, *workers, opts)
+	}
 
-// runPRLevel extracts PR-level cases from merge commits.
-func (c *Collector) runPRLevel(ctx context.Context, mergeHashes []string) error {
-	parser := gitdiff.NewParser()
-	encoder := json.NewEncoder(c.Output)
+	args := fs.Args()
+	repoPath := "."
+	if len(args) > 0 {
+		repoPath = args[0]
+	}
+
+	// Derive repo name from path if not specified
+	repoName := *repo
+	if repoName == "" {
+		absPath, err := filepath.Abs(repoPath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve repo path: %w", err)
+		}
+		repoName = filepath.Base(absPath)
+	}
+	opts.RepoPath = repoPath
+	opts.RepoName = repoName
 
-	for _, mergeHash := range mergeHashes {
-		// Get the merge commit message to extract branch name
-		mergeMessage, err := c.Git.Message(ctx, c.RepoPath, mergeHash)
+	if *githubCheck {
+		client, err := newGitHubClient(ctx, repoPath)
 		if err != nil {
 			return err
 		}
+		opts.PullRequests = client
+	}
 
-		branch := ParseBranchFromMergeMessage(mergeMessage)
+	collector := evalpipeline.NewCollector(git.NewRunner(), gitdiff.NewParser(), opts)
 
-		// Get commits in the PR (merge^1..merge^2)
-		base := mergeHash + "^1"
-		head := mergeHash + "^2"
+	return collector.Run(ctx, jsonl.NewWriter(os.Stdout))
+}
+
+// runCollectManifest collects from every repository in the manifest at
+// manifestPath into one dataset on stdout.
+func runCollectManifest(ctx context.Context, manifestPath, cloneDir string, workers int, defaults evalpipeline.CollectorOptions) error {
+	manifest, err := evalpipeline.LoadManifest(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to load manifest: %w", err)
+	}
 
-		commits, err := c.Git.CommitsInRange(ctx, c.RepoPath, base, head)
+	if cloneDir == "" {
+		cacheDir, err := os.UserCacheDir()
 		if err != nil {
-			return err
+			return fmt.Errorf("no clone directory: %w", err)
 		}
+		cloneDir = filepath.Join(cacheDir, "evalreview", "repos")
+	}
 
-		// Populate per-commit diffs concurrently (best-effort; failures are ignored)
-		g, gctx := errgroup.WithContext(ctx)
-		g.SetLimit(8) // Limit concurrent git show subprocesses
-		for i := range commits {
-			g.Go(func() error {
-				commitDiffText, err := c.Git.Show(gctx, c.RepoPath, commits[i].Hash)
-				if err != nil {
-					// Per-commit diffs are optional; ignore failures
-					return nil
-				}
-				commitDiff, err := parser.Parse(strings.NewReader(commitDiffText))
-				if err != nil {
-					return nil
-				}
-				commits[i].Diff = commitDiff
-				return nil
-			})
-		}
-		_ = g.Wait() // All goroutines return nil, so error is always nil
+	batch := evalpipeline.NewBatchCollector(git.NewRunner(), gitdiff.NewParser(), evalpipeline.BatchOptions{
+		Defaults: defaults,
+		CloneDir: cloneDir,
+		Workers:  workers,
+		Warnings: os.Stderr,
+	})
+	return batch.Run(ctx, manifest.Repos, jsonl.NewWriter(os.Stdout))
+}
 
-		// Get combined diff for the PR
-		diffText, err := c.Git.DiffRange(ctx, c.RepoPath, base, head)
-		if err != nil {
-			return err
+// newGitHubClient returns a client for the GitHub repository that
+// repoPath's origin remote points to.
+func newGitHubClient(ctx context.Context, repoPath string) (*github.Client, error) {
+	remoteURL, err := git.NewRunner().RemoteURL(ctx, repoPath, "origin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the GitHub repository: %w", err)
+	}
+	remote, err := git.ParseRemote(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	return github.NewClient(remote.Path,
+		github.WithBaseURL(github.BaseURL(remote.Host)),
+		github.WithToken(os.Getenv("GITHUB_TOKEN")),
+	), nil
+}
+
+// splitList splits a comma-separated flag value, ignoring empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
+	}
+	return items
+}
 
-		diff, err := parser.Parse(strings.NewReader(diffText))
+func runClassify(ctx context.Context) error {
+	fs := flag.NewFlagSet("classify", flag.ExitOnError)
+	workers := fs.Int("workers", 4, "Number of parallel workers (1 = sequential)")
+	promptFile := fs.String("prompt", "", "Prompt template to classify with instead of the built-in one")
+	redactInput := fs.Bool("redact", false, "Redact credentials, emails and high-entropy strings before sending diffs to Gemini")
+	refuseSecrets := fs.Bool("refuse-secrets", false, "Skip cases with high-entropy secrets instead of classifying them redacted (implies -redact)")
+	var redactRules []redact.Rule
+	fs.Func("redact-rule", "Extra `name=regexp` to redact (repeatable; implies -redact)", func(spec string) error {
+		rule, err := redact.ParseRule(spec)
 		if err != nil {
 			return err
 		}
+		redactRules = append(redactRules, rule)
+		return nil
+	})
 
-		// Skip PRs with no files
-		if len(diff.Files) == 0 {
-			continue
-		}
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
 
-		// Count total lines changed
-		totalLines := countLinesChanged(diff)
+	args := fs.Args()
+	if len(args) < 1 {
+		return fmt.Errorf("usage: evalreview classify [--workers N] [--prompt FILE] [--redact] [--redact-rule NAME=REGEXP] [--refuse-secrets] <input.jsonl>")
+	}
+	inputPath := args[0]
 
-		// Apply line filters
-		if c.MinLines > 0 && totalLines < c.MinLines {
-			continue
-		}
-		if c.MaxLines > 0 && totalLines > c.MaxLines {
-			continue
+	prompt, err := gemini.DefaultPrompt()
+	if err != nil {
+		return err
+	}
+	if *promptFile != "" {
+		if prompt, err = gemini.LoadPrompt(*promptFile); err != nil {
+			return fmt.Errorf("failed to load prompt: %w", err)
 		}
+	}
 
-		evalCase := diffview.EvalCase{
-			Input: diffview.ClassificationInput{
-				Repo:    c.RepoName,
-				Branch:  branch,
-				Commits: commits,
-				Diff:    *diff,
-			},
-			Story: nil,
-		}
+	// Check for API key
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable required")
+	}
 
-		// Check byte size limit before writing
-		if c.MaxBytes > 0 {
-			data, err := json.Marshal(evalCase)
-			if err != nil {
-				return err
-			}
-			if len(data) > c.MaxBytes {
-				continue
-			}
-		}
+	// Load cases from JSONL
+	loader := jsonl.NewLoader()
+	cases, err := loader.Load(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load cases: %w", err)
+	}
 
-		if err := encoder.Encode(evalCase); err != nil {
-			return err
-		}
+	if len(cases) == 0 {
+		return fmt.Errorf("no cases found in %s", inputPath)
 	}
 
-	return nil
-}
+	// Set up Gemini classifier
+	client, err := gemini.NewClient(ctx, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	defer client.Close()
 
-// runCommitLevel extracts individual commit cases (fallback mode).
-func (c *Collector) runCommitLevel(ctx context.Context) error {
-	hashes, err := c.Git.Log(ctx, c.RepoPath, c.Limit)
+	geminiClassifier, err := gemini.NewClassifier(client, gemini.DefaultModel,
+		gemini.WithValidationRetry(2), // Retry once if LLM returns invalid hunk references
+		gemini.WithPrompt(prompt))
 	if err != nil {
 		return err
 	}
 
-	parser := gitdiff.NewParser()
-	encoder := json.NewEncoder(c.Output)
-
-	for _, hash := range hashes {
-		diffText, err := c.Git.Show(ctx, c.RepoPath, hash)
-		if err != nil {
-			return err
+	var classifier diffview.StoryClassifier = geminiClassifier
+	if *redactInput || *refuseSecrets || len(redactRules) > 0 {
+		opts := []redact.ClassifierOption{redact.WithReports(os.Stderr)}
+		if *refuseSecrets {
+			opts = append(opts, redact.RefuseSecrets())
 		}
+		classifier = redact.NewClassifier(geminiClassifier, redact.NewRedactor(redact.WithRules(redactRules...)), opts...)
+	}
 
-		diff, err := parser.Parse(strings.NewReader(diffText))
-		if err != nil {
-			return err
-		}
+	runner := evalpipeline.NewClassifyRunner(classifier, evalpipeline.ClassifyOptions{
+		Workers:       *workers,
+		Warnings:      os.Stderr,
+		PromptVersion: geminiClassifier.PromptVersion(),
+	})
 
-		// Skip commits with no files (e.g., merge commits)
-		if len(diff.Files) == 0 {
-			continue
-		}
+	return runner.Run(ctx, cases, jsonl.NewWriter(os.Stdout))
+}
+
+func runJudge(ctx context.Context) error {
+	fs := flag.NewFlagSet("judge", flag.ExitOnError)
+	model := fs.String("model", gemini.DefaultModel, "Gemini model to grade with")
+	workers := fs.Int("workers", 4, "Number of parallel workers (1 = sequential)")
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
 
-		// Count total lines changed
-		totalLines := countLinesChanged(diff)
+	args := fs.Args()
+	if len(args) != 1 {
+		return fmt.Errorf("usage: evalreview judge [--model M] [--workers N] <cases.jsonl>")
+	}
+	inputPath := args[0]
 
-		// Apply line filters
-		if c.MinLines > 0 && totalLines < c.MinLines {
-			continue
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable required")
+	}
+
+	cases, err := jsonl.NewLoader().Load(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load cases: %w", err)
+	}
+	legacyIDs := jsonl.WithLegacyCaseIDs(diffview.NewLegacyCaseIDs(cases))
+	tombstones, err := jsonl.NewTombstoneStore(legacyIDs).Load(tombstonesPath(inputPath))
+	if err != nil {
+		return fmt.Errorf("failed to load tombstones: %w", err)
+	}
+	cases = diffview.ExcludeTombstoned(cases, tombstones)
+
+	store := jsonl.NewStore(legacyIDs)
+	outputPath := judgmentsPath(inputPath)
+	existing, err := store.Load(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load judgments: %w", err)
+	}
+
+	// Cases a human has judged keep their judgment, so aren't graded
+	human := make(map[string]bool)
+	for _, j := range existing {
+		if j.Judge == "" {
+			human[j.CaseID] = true
 		}
-		if c.MaxLines > 0 && totalLines > c.MaxLines {
-			continue
+	}
+	index := make(map[string]int, len(cases))
+	var pending []diffview.EvalCase
+	for i, c := range cases {
+		index[c.CaseID()] = i
+		if !human[c.CaseID()] {
+			pending = append(pending, c)
 		}
+	}
 
-		// Get commit message
-		message, err := c.Git.Message(ctx, c.RepoPath, hash)
-		if err != nil {
-			return err
-		}
+	client, err := gemini.NewClient(ctx, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	defer client.Close()
 
-		evalCase := diffview.EvalCase{
-			Input: diffview.ClassificationInput{
-				Repo: c.RepoName,
-				Commits: []diffview.CommitBrief{
-					{Hash: hash, Message: message},
-				},
-				Diff: *diff,
-			},
-			Story: nil, // Not classified yet
-		}
+	runner := evalpipeline.NewJudgeRunner(gemini.NewJudge(client, *model), evalpipeline.JudgeOptions{
+		Workers:  *workers,
+		Warnings: os.Stderr,
+	})
+	auto, err := runner.Run(ctx, pending)
+	if err != nil {
+		return err
+	}
 
-		// Check byte size limit before writing
-		if c.MaxBytes > 0 {
-			data, err := json.Marshal(evalCase)
-			if err != nil {
-				return err
-			}
-			if len(data) > c.MaxBytes {
-				continue
-			}
+	var pass int
+	for i := range auto {
+		auto[i].Index = index[auto[i].CaseID]
+		if auto[i].Pass {
+			pass++
 		}
+	}
+	if err := store.Save(outputPath, diffview.MergeAutoJudgments(existing, auto)); err != nil {
+		return fmt.Errorf("failed to save judgments: %w", err)
+	}
 
-		if err := encoder.Encode(evalCase); err != nil {
-			return err
+	fmt.Fprintf(os.Stderr, "judged %d cases (%d pass, %d fail)\n", len(auto), pass, len(auto)-pass)
+	return nil
+}
+
+// GC materializes a dataset with tombstoned cases removed and promoted gold
+// labels applied.
+type GC struct {
+	Output     io.Writer
+	ErrOutput  io.Writer
+	Cases      []diffview.EvalCase
+	Tombstones []diffview.Tombstone
+	Gold       []diffview.EvalCase // cases as they were promoted
+}
+
+// Run writes the remaining cases as JSONL and reports how many were removed.
+func (g *GC) Run() error {
+	kept := diffview.ApplyGold(diffview.ExcludeTombstoned(g.Cases, g.Tombstones), g.Gold)
+
+	w := jsonl.NewWriter(g.Output)
+	for _, c := range kept {
+		if err := w.Write(c); err != nil {
+			return fmt.Errorf("failed to write case: %w", err)
 		}
 	}
 
+	if g.ErrOutput != nil {
+		fmt.Fprintf(g.ErrOutput, "removed %d of %d cases\n", len(g.Cases)-len(kept), len(g.Cases))
+	}
 	return nil
 }
 
-// ParseBranchFromMergeMessage extracts the branch name from a GitHub merge commit message.
-// Format: "Merge pull request #N from user/branch-name"
-func ParseBranchFromMergeMessage(message string) string {
-	// Only parse the first line (merge messages may have additional body text)
-	firstLine := message
-	if idx := strings.IndexByte(message, '\n'); idx != -1 {
-		firstLine = message[:idx]
+func runGC() error {
+	args := os.Args[2:]
+	if len(args) < 1 {
+		return fmt.Errorf("usage: evalreview gc <input.jsonl> > cleaned.jsonl")
 	}
+	inputPath := args[0]
 
-	const prefix = "Merge pull request #"
-	if !strings.HasPrefix(firstLine, prefix) {
-		return ""
+	loader := jsonl.NewLoader()
+	cases, err := loader.Load(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load cases: %w", err)
 	}
-	// Find "from user/branch"
-	fromIdx := strings.Index(firstLine, " from ")
-	if fromIdx == -1 {
-		return ""
+
+	legacyIDs := jsonl.WithLegacyCaseIDs(diffview.NewLegacyCaseIDs(cases))
+	tombstones, err := jsonl.NewTombstoneStore(legacyIDs).Load(tombstonesPath(inputPath))
+	if err != nil {
+		return fmt.Errorf("failed to load tombstones: %w", err)
 	}
-	userBranch := firstLine[fromIdx+6:] // Skip " from "
-	// Extract branch name after "user/"
-	slashIdx := strings.Index(userBranch, "/")
-	if slashIdx == -1 {
-		return userBranch
+
+	promoted, err := loadGold(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load gold labels: %w", err)
 	}
-	return userBranch[slashIdx+1:]
+
+	gc := &GC{
+		Output:     os.Stdout,
+		ErrOutput:  os.Stderr,
+		Cases:      cases,
+		Tombstones: tombstones,
+		Gold:       promoted,
+	}
+
+	return gc.Run()
 }
 
-// countLinesChanged returns the total number of added + deleted lines in a diff.
-func countLinesChanged(diff *diffview.Diff) int {
-	total := 0
-	for _, file := range diff.Files {
-		added, deleted := file.Stats()
-		total += added + deleted
+// SessionLabel describes the nth recorded session (counting from 1): when
+// it ended, how many cases it saw and how they were judged.
+func SessionLabel(n int, s diffview.ReviewSession) string {
+	var judged, pass int
+	for _, j := range s.Judgments {
+		if j.Judged {
+			judged++
+			if j.Pass {
+				pass++
+			}
+		}
 	}
-	return total
+	return fmt.Sprintf("#%d  %s  %d cases  %d judged (%d pass, %d fail)",
+		n, s.EndedAt.Format("2006-01-02 15:04"), len(s.CaseIDs), judged, pass, judged-pass)
 }
 
-func runCollect(ctx context.Context) error {
-	fs := flag.NewFlagSet("collect", flag.ExitOnError)
-	limit := fs.Int("limit", 50, "Maximum number of commits to extract")
-	repo := fs.String("repo", "", "Repository name (defaults to directory name)")
-	minLines := fs.Int("min-lines", 5, "Minimum lines changed (skip smaller commits)")
-	maxLines := fs.Int("max-lines", 2000, "Maximum lines changed (skip larger PRs/commits)")
-	maxBytes := fs.Int("max-bytes", 500000, "Maximum serialized case size in bytes (skip larger cases)")
+// SessionSummary describes the pace and outcome of a session just ended,
+// for calibrating how long reviewing a dataset takes.
+func SessionSummary(s diffview.ReviewSession) string {
+	stats := s.Stats()
+	if stats.Judged == 0 {
+		return fmt.Sprintf("Session: %s, no cases judged", stats.Duration.Round(time.Second))
+	}
+	median := "not recorded"
+	if stats.MedianTime > 0 {
+		median = stats.MedianTime.Round(time.Second).String()
+	}
+	return fmt.Sprintf("Session: %s, %d cases judged (%.1f/hour), median %s per case, %.0f%% pass",
+		stats.Duration.Round(time.Second), stats.Judged, stats.CasesPerHour(), median, 100*stats.PassRate())
+}
+
+// SessionLister lists recorded review sessions, oldest first.
+type SessionLister struct {
+	Output   io.Writer
+	Sessions []diffview.ReviewSession
+}
+
+// Run writes one line per session.
+func (l *SessionLister) Run() error {
+	if len(l.Sessions) == 0 {
+		_, err := fmt.Fprintln(l.Output, "no sessions recorded")
+		return err
+	}
+	for i, s := range l.Sessions {
+		if _, err := fmt.Fprintln(l.Output, SessionLabel(i+1, s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runSessions(ctx context.Context, profile termenv.Profile) error {
+	fs := flag.NewFlagSet("sessions", flag.ExitOnError)
+	pick := fs.Bool("pick", false, "Choose a session to open from a list")
 
 	if err := fs.Parse(os.Args[2:]); err != nil {
 		return err
 	}
 
 	args := fs.Args()
-	repoPath := "."
-	if len(args) > 0 {
-		repoPath = args[0]
+	if len(args) < 1 || len(args) > 2 || (*pick && len(args) == 2) {
+		return fmt.Errorf("usage: evalreview sessions [-pick] <input.jsonl> [N]")
 	}
+	inputPath := args[0]
 
-	// Derive repo name from path if not specified
-	repoName := *repo
-	if repoName == "" {
-		absPath, err := filepath.Abs(repoPath)
-		if err != nil {
-			return fmt.Errorf("failed to resolve repo path: %w", err)
+	cases, err := jsonl.NewLoader().Load(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load cases: %w", err)
+	}
+	legacyIDs := jsonl.WithLegacyCaseIDs(diffview.NewLegacyCaseIDs(cases))
+	sessions, err := jsonl.NewSessionStore(legacyIDs).Load(sessionsPath(inputPath))
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	n := 0
+	switch {
+	case len(args) == 2:
+		n, err = strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > len(sessions) {
+			return fmt.Errorf("no session %q: %s has %d", args[1], inputPath, len(sessions))
 		}
-		repoName = filepath.Base(absPath)
+	case *pick:
+		if len(sessions) == 0 {
+			return fmt.Errorf("no sessions recorded for %s", inputPath)
+		}
+		if n, err = pickSession(ctx, sessions); err != nil || n == 0 {
+			return err
+		}
+	default:
+		lister := &SessionLister{Output: os.Stdout, Sessions: sessions}
+		return lister.Run()
 	}
 
-	collector := &Collector{
-		Output:   os.Stdout,
-		RepoPath: repoPath,
-		RepoName: repoName,
-		Limit:    *limit,
-		MinLines: *minLines,
-		MaxLines: *maxLines,
-		MaxBytes: *maxBytes,
-		Git:      git.NewRunner(),
+	return openSession(ctx, inputPath, cases, n, sessions[n-1], profile)
+}
+
+// pickSession lets the user choose a session, newest first, and returns its
+// number, or 0 if they cancelled.
+func pickSession(ctx context.Context, sessions []diffview.ReviewSession) (int, error) {
+	labels := make([]string, len(sessions))
+	for i := range sessions {
+		n := len(sessions) - i
+		labels[i] = SessionLabel(n, sessions[n-1])
 	}
 
-	return collector.Run(ctx)
+	p := tea.NewProgram(bubbletea.NewPicker(labels),
+		tea.WithAltScreen(),
+		tea.WithContext(ctx),
+	)
+	final, err := p.Run()
+	if err != nil {
+		return 0, err
+	}
+	picked := final.(bubbletea.Picker).Picked()
+	if picked < 0 {
+		return 0, nil
+	}
+	return len(sessions) - picked, nil
 }
 
-// DefaultMaxRetries is the default number of retry attempts for classification.
-const DefaultMaxRetries = 3
+// openSession shows the dataset as session n left it, read-only. Cases
+// are taken from the current dataset file, tombstoned or not, so only cases
+// since removed by gc are missing.
+func openSession(ctx context.Context, inputPath string, cases []diffview.EvalCase, n int, session diffview.ReviewSession, profile termenv.Profile) error {
+	snapshot, missing := session.Snapshot(cases)
+	if missing > 0 {
+		fmt.Fprintf(os.Stderr, "%d of the session's cases are no longer in %s\n", missing, inputPath)
+	}
+	if len(snapshot) == 0 {
+		return ErrNoCases
+	}
 
-// ClassifyRunner classifies eval cases using an LLM classifier.
-type ClassifyRunner struct {
-	Output     io.Writer
-	ErrOutput  io.Writer
-	Cases      []diffview.EvalCase
-	Classifier diffview.StoryClassifier
-	MaxRetries int
-	// Workers sets the number of parallel workers. If <= 1, runs sequentially.
-	Workers int
-	// BackoffFn returns the backoff duration for a given attempt (1-indexed).
-	// If nil, uses exponential backoff (1s, 2s, 4s...).
-	BackoffFn func(attempt int) time.Duration
-}
-
-// Run classifies each case and writes JSONL output.
-// Cases that fail after max retries are skipped with a warning.
-func (c *ClassifyRunner) Run(ctx context.Context) error {
-	if c.Workers > 1 {
-		return c.runParallel(ctx)
-	}
-	return c.runSequential(ctx)
-}
-
-func (c *ClassifyRunner) runSequential(ctx context.Context) error {
-	encoder := json.NewEncoder(c.Output)
-	maxRetries := c.MaxRetries
-	if maxRetries == 0 {
-		maxRetries = DefaultMaxRetries
-	}
-	errOut := c.ErrOutput
-	if errOut == nil {
-		errOut = os.Stderr
-	}
-
-	for i := range c.Cases {
-		evalCase := c.Cases[i]
-
-		// Skip cases that already have a story
-		if evalCase.Story == nil {
-			story, err := c.classifyWithRetry(ctx, evalCase.Input, maxRetries)
-			if err != nil {
-				// Log warning and skip this case
-				fmt.Fprintf(errOut, "warning: skipping case %s after %d retries: %v\n",
-					evalCase.Input.FirstCommitHash(), maxRetries, err)
-				continue
-			}
-			evalCase.Story = story
-		}
+	opts, err := evalModelOptions(profile)
+	if err != nil {
+		return err
+	}
+	opts = append(opts,
+		bubbletea.WithExistingJudgments(session.Judgments),
+		bubbletea.WithReadOnly(fmt.Sprintf("session %d (%s)", n, session.EndedAt.Format("2006-01-02 15:04"))),
+	)
 
-		if err := encoder.Encode(evalCase); err != nil {
-			return err
+	_, err = runEvalModel(ctx, bubbletea.NewEvalModel(snapshot, opts...))
+	return err
+}
+
+// Exporter writes a shareable copy of a dataset with code content stripped.
+type Exporter struct {
+	Output io.Writer
+	Cases  []diffview.EvalCase
+}
+
+// Run writes each case as JSONL with its input reduced to paths and hunk
+// structure. Classifications are kept.
+func (e *Exporter) Run() error {
+	w := jsonl.NewWriter(e.Output)
+	for _, c := range e.Cases {
+		c.Input = c.Input.StripContent()
+		if err := w.Write(c); err != nil {
+			return fmt.Errorf("failed to write case: %w", err)
 		}
 	}
-
 	return nil
 }
 
-// classifyResult holds the result of classifying a single case.
-type classifyResult struct {
-	result  *diffview.EvalCase
-	skipped bool
-	skipMsg string
-}
+func runExport() error {
+	args := os.Args[2:]
+	if len(args) < 1 {
+		return fmt.Errorf("usage: evalreview export <input.jsonl> > shared.jsonl")
+	}
 
-func (c *ClassifyRunner) runParallel(ctx context.Context) error {
-	maxRetries := c.MaxRetries
-	if maxRetries == 0 {
-		maxRetries = DefaultMaxRetries
+	cases, err := jsonl.NewLoader().Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load cases: %w", err)
 	}
-	errOut := c.ErrOutput
-	if errOut == nil {
-		errOut = os.Stderr
+
+	exporter := &Exporter{
+		Output: os.Stdout,
+		Cases:  cases,
 	}
 
-	// Collect results indexed by original position
-	results := make([]classifyResult, len(c.Cases))
+	return exporter.Run()
+}
 
-	g, ctx := errgroup.WithContext(ctx)
-	g.SetLimit(c.Workers)
+// Scorer reports how closely a dataset's classifications match its gold
+// labels.
+type Scorer struct {
+	Output io.Writer
+	Cases  []diffview.EvalCase
+}
 
-	for i := range c.Cases {
-		evalCase := c.Cases[i]
+// Run writes the number of cases scored and a line per metric.
+func (sc *Scorer) Run() error {
+	score := diffview.ScoreAgainstGold(sc.Cases)
+	if score.Cases == 0 {
+		return errors.New("no cases with both a classification and a gold label")
+	}
 
-		g.Go(func() error {
-			var result classifyResult
+	_, err := fmt.Fprintf(sc.Output,
+		"cases scored        %d\n"+
+			"change_type         %5.1f%%  (%d/%d cases)\n"+
+			"hunk coverage       %5.1f%%  (%d/%d hunks)\n"+
+			"section assignment  %5.1f%%  (%d/%d hunk pairs)\n",
+		score.Cases,
+		100*score.ChangeTypeAccuracy(), score.ChangeTypeMatches, score.Cases,
+		100*score.HunkCoverage(), score.CoveredHunks, score.GoldHunks,
+		100*score.SectionAccuracy(), score.AgreeingPairs, score.HunkPairs)
+	return err
+}
 
-			// Skip cases that already have a story
-			if evalCase.Story == nil {
-				story, err := c.classifyWithRetry(ctx, evalCase.Input, maxRetries)
-				if err != nil {
-					result.skipped = true
-					result.skipMsg = fmt.Sprintf("warning: skipping case %s after %d retries: %v\n",
-						evalCase.Input.FirstCommitHash(), maxRetries, err)
-				} else {
-					evalCase.Story = story
-				}
-			}
+func runScore() error {
+	fs := flag.NewFlagSet("score", flag.ExitOnError)
+	gold := fs.String("gold", "", "Dataset or gold log to take gold labels from (default: the gold log of the scored dataset)")
 
-			if !result.skipped {
-				result.result = &evalCase
-			}
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
 
-			results[i] = result
+	args := fs.Args()
+	if len(args) != 1 {
+		return fmt.Errorf("usage: evalreview score [-gold gold.jsonl] <cases.jsonl>")
+	}
+	inputPath := args[0]
 
-			return nil
-		})
+	cases, err := jsonl.NewLoader().Load(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load cases: %w", err)
 	}
 
-	if err := g.Wait(); err != nil {
-		return err
+	var promoted []diffview.EvalCase
+	if *gold != "" {
+		promoted, err = jsonl.NewLoader().Load(*gold)
+	} else {
+		promoted, err = loadGold(inputPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load gold labels: %w", err)
 	}
 
-	// Write results in order
-	encoder := json.NewEncoder(c.Output)
-	for _, r := range results {
-		if r.skipped {
-			fmt.Fprint(errOut, r.skipMsg)
-			continue
-		}
-		if r.result != nil {
-			if err := encoder.Encode(r.result); err != nil {
-				return err
-			}
-		}
+	scorer := &Scorer{
+		Output: os.Stdout,
+		Cases:  diffview.ApplyGold(cases, promoted),
 	}
 
-	return nil
+	return scorer.Run()
 }
 
-// classifyWithRetry attempts classification with exponential backoff.
-func (c *ClassifyRunner) classifyWithRetry(ctx context.Context, input diffview.ClassificationInput, maxRetries int) (*diffview.StoryClassification, error) {
-	backoffFn := c.BackoffFn
-	if backoffFn == nil {
-		backoffFn = func(attempt int) time.Duration {
-			return time.Duration(1<<(attempt-1)) * time.Second
-		}
+func runCompare(ctx context.Context) error {
+	args := os.Args[2:]
+	if len(args) != 2 {
+		return fmt.Errorf("usage: evalreview compare <a.jsonl> <b.jsonl>")
 	}
 
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		// Check for context cancellation before each attempt
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+	loader := jsonl.NewLoader()
+	a, err := loader.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", args[0], err)
+	}
+	b, err := loader.Load(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", args[1], err)
+	}
 
-		story, err := c.Classifier.Classify(ctx, input)
-		if err == nil {
-			return story, nil
-		}
-		lastErr = err
-
-		// Don't sleep after last attempt
-		if attempt < maxRetries {
-			backoff := backoffFn(attempt)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
-			}
+	changed, shared := diffview.AlignChanged(a, b)
+	fmt.Fprintf(os.Stderr, "%d of %d cases classified in both differ\n", len(changed), shared)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	p := tea.NewProgram(bubbletea.NewCompareModel(changed, args[0], args[1]),
+		tea.WithAltScreen(),
+		tea.WithContext(ctx),
+	)
+	_, err = p.Run()
+	return err
+}
+
+const datasetUsage = `usage: evalreview dataset <command> ...
+
+Commands:
+  merge  <a.jsonl> [b.jsonl]... > merged.jsonl   Combine datasets, keeping the first case with each ID
+  split  [-test F] [-seed N] <in.jsonl> <train.jsonl> <test.jsonl>
+                                                  Divide cases at random into training and test sets
+  filter [-repo R] [-change-type T] [-min-lines N] [-max-lines N] <in.jsonl> > out.jsonl
+                                                  Keep the cases matching every given filter
+  sample [-n N] [-seed N] <in.jsonl> > out.jsonl  Choose N cases at random`
+
+func runDataset() error {
+	if len(os.Args) < 3 {
+		return errors.New(datasetUsage)
+	}
+	args := os.Args[3:]
+	switch os.Args[2] {
+	case "merge":
+		return runDatasetMerge(args)
+	case "split":
+		return runDatasetSplit(args)
+	case "filter":
+		return runDatasetFilter(args)
+	case "sample":
+		return runDatasetSample(args)
+	default:
+		return errors.New(datasetUsage)
+	}
+}
+
+func runDatasetMerge(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: evalreview dataset merge <a.jsonl> [b.jsonl]... > merged.jsonl")
+	}
+
+	var datasets [][]diffview.EvalCase
+	for _, path := range args {
+		cases, err := jsonl.NewLoader().Load(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
 		}
+		datasets = append(datasets, cases)
+	}
+
+	merged, duplicates := evalpipeline.MergeCases(datasets...)
+	if err := writeCases(os.Stdout, merged); err != nil {
+		return err
 	}
-	return nil, lastErr
+	fmt.Fprintf(os.Stderr, "merged %d cases, dropped %d duplicates\n", len(merged), duplicates)
+	return nil
 }
 
-func runClassify(ctx context.Context) error {
-	fs := flag.NewFlagSet("classify", flag.ExitOnError)
-	workers := fs.Int("workers", 4, "Number of parallel workers (1 = sequential)")
+func runDatasetSplit(args []string) error {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	testFraction := fs.Float64("test", 0.2, "Share of cases to put in the test set")
+	seed := fs.Uint64("seed", 1, "Random seed; the same seed gives the same split")
 
-	if err := fs.Parse(os.Args[2:]); err != nil {
+	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if fs.NArg() != 3 {
+		return fmt.Errorf("usage: evalreview dataset split [-test F] [-seed N] <in.jsonl> <train.jsonl> <test.jsonl>")
+	}
+	if *testFraction < 0 || *testFraction > 1 {
+		return fmt.Errorf("-test must be between 0 and 1, got %v", *testFraction)
+	}
 
-	args := fs.Args()
-	if len(args) < 1 {
-		return fmt.Errorf("usage: evalreview classify [--workers N] <input.jsonl>")
+	cases, err := jsonl.NewLoader().Load(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to load cases: %w", err)
 	}
-	inputPath := args[0]
 
-	// Check for API key
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		return fmt.Errorf("GEMINI_API_KEY environment variable required")
+	train, test := evalpipeline.SplitCases(cases, *testFraction, *seed)
+	if err := writeCasesToFile(fs.Arg(1), train); err != nil {
+		return err
+	}
+	if err := writeCasesToFile(fs.Arg(2), test); err != nil {
+		return err
 	}
+	fmt.Fprintf(os.Stderr, "%d training cases, %d test cases\n", len(train), len(test))
+	return nil
+}
 
-	// Load cases from JSONL
-	loader := jsonl.NewLoader()
-	cases, err := loader.Load(inputPath)
+func runDatasetFilter(args []string) error {
+	fs := flag.NewFlagSet("filter", flag.ExitOnError)
+	var filter evalpipeline.CaseFilter
+	fs.StringVar(&filter.Repo, "repo", "", "Keep cases from this repository")
+	fs.StringVar(&filter.ChangeType, "change-type", "", "Keep cases classified with this change type")
+	fs.IntVar(&filter.MinLines, "min-lines", 0, "Keep cases changing at least this many lines")
+	fs.IntVar(&filter.MaxLines, "max-lines", 0, "Keep cases changing at most this many lines")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: evalreview dataset filter [-repo R] [-change-type T] [-min-lines N] [-max-lines N] <in.jsonl> > out.jsonl")
+	}
+
+	cases, err := jsonl.NewLoader().Load(fs.Arg(0))
 	if err != nil {
 		return fmt.Errorf("failed to load cases: %w", err)
 	}
 
-	if len(cases) == 0 {
-		return fmt.Errorf("no cases found in %s", inputPath)
+	kept := evalpipeline.FilterCases(cases, filter)
+	if err := writeCases(os.Stdout, kept); err != nil {
+		return err
 	}
+	fmt.Fprintf(os.Stderr, "kept %d of %d cases\n", len(kept), len(cases))
+	return nil
+}
 
-	// Set up Gemini classifier
-	client, err := gemini.NewClient(ctx, apiKey)
+func runDatasetSample(args []string) error {
+	fs := flag.NewFlagSet("sample", flag.ExitOnError)
+	n := fs.Int("n", 50, "Number of cases to choose")
+	seed := fs.Uint64("seed", 1, "Random seed; the same seed gives the same sample")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *n < 0 {
+		return fmt.Errorf("usage: evalreview dataset sample [-n N] [-seed N] <in.jsonl> > out.jsonl")
+	}
+
+	cases, err := jsonl.NewLoader().Load(fs.Arg(0))
 	if err != nil {
-		return fmt.Errorf("failed to create Gemini client: %w", err)
+		return fmt.Errorf("failed to load cases: %w", err)
 	}
-	defer client.Close()
 
-	classifier := gemini.NewClassifier(client, gemini.DefaultModel,
-		gemini.WithValidationRetry(2)) // Retry once if LLM returns invalid hunk references
+	return writeCases(os.Stdout, evalpipeline.SampleCases(cases, *n, *seed))
+}
 
-	runner := &ClassifyRunner{
-		Output:     os.Stdout,
-		Cases:      cases,
-		Classifier: classifier,
-		Workers:    *workers,
+// writeCases writes cases to w as JSONL.
+func writeCases(w io.Writer, cases []diffview.EvalCase) error {
+	jw := jsonl.NewWriter(w)
+	for _, c := range cases {
+		if err := jw.Write(c); err != nil {
+			return fmt.Errorf("failed to write case: %w", err)
+		}
 	}
+	return nil
+}
 
-	return runner.Run(ctx)
+// writeCasesToFile replaces the file at path with cases as JSONL.
+func writeCasesToFile(path string, cases []diffview.EvalCase) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeCases(f, cases); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
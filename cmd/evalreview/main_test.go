@@ -2,915 +2,186 @@ package main_test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/fwojciec/diffstory"
 	main "github.com/fwojciec/diffstory/cmd/evalreview"
-	"github.com/fwojciec/diffstory/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestCollector_Run_WritesJSONL(t *testing.T) {
+func TestGC_Run_RemovesTombstonedCases(t *testing.T) {
 	t.Parallel()
 
-	diffOutput := `diff --git a/hello.go b/hello.go
-new file mode 100644
-index 0000000..e69de29
---- /dev/null
-+++ b/hello.go
-@@ -0,0 +1,3 @@
-+package main
-+
-+func hello() {}
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback to commit-level
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"abc1234"}, nil
-			},
-			ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
-				if hash == "abc1234" {
-					return diffOutput, nil
-				}
-				return "", errors.New("unknown hash")
-			},
-			MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
-				return "Add hello function", nil
-			},
-		},
-	}
-
-	err := collector.Run(context.Background())
-	require.NoError(t, err)
-
-	// Output should be JSONL with one line per commit
-	output := stdout.String()
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	require.Len(t, lines, 1)
-
-	// Line should contain commit hash and diff files (new lowercase JSON keys)
-	assert.Contains(t, lines[0], `"hash":"abc1234"`)
-	assert.Contains(t, lines[0], `"repo":"testrepo"`)
-	assert.Contains(t, lines[0], `"message":"Add hello function"`)
-	assert.Contains(t, lines[0], `"Files"`)
-}
-
-func TestCollector_Run_MultipleCommits(t *testing.T) {
-	t.Parallel()
-
-	diff1 := `diff --git a/a.go b/a.go
-new file mode 100644
---- /dev/null
-+++ b/a.go
-@@ -0,0 +1 @@
-+package a
-`
-	diff2 := `diff --git a/b.go b/b.go
-new file mode 100644
---- /dev/null
-+++ b/b.go
-@@ -0,0 +1 @@
-+package b
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback to commit-level
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"commit1", "commit2"}, nil
-			},
-			ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
-				switch hash {
-				case "commit1":
-					return diff1, nil
-				case "commit2":
-					return diff2, nil
-				}
-				return "", errors.New("unknown hash")
-			},
-			MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
-				return "Commit message for " + hash, nil
-			},
-		},
+	cases := []diffview.EvalCase{
+		{ID: "keep", Input: diffview.ClassificationInput{Repo: "repo", Branch: "keep"}},
+		{ID: "drop", Input: diffview.ClassificationInput{Repo: "repo", Branch: "drop"}},
 	}
 
-	err := collector.Run(context.Background())
-	require.NoError(t, err)
-
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 2)
-	assert.Contains(t, lines[0], `"hash":"commit1"`)
-	assert.Contains(t, lines[1], `"hash":"commit2"`)
-}
-
-func TestCollector_Run_IncludesFilePaths(t *testing.T) {
-	t.Parallel()
-
-	diffOutput := `diff --git a/src/auth/login.go b/src/auth/login.go
-new file mode 100644
---- /dev/null
-+++ b/src/auth/login.go
-@@ -0,0 +1,3 @@
-+package auth
-+
-+func Login() {}
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback to commit-level
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"abc"}, nil
-			},
-			ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return diffOutput, nil
-			},
-			MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return "Add login", nil
-			},
-		},
+	var out, errOut bytes.Buffer
+	gc := &main.GC{
+		Output:     &out,
+		ErrOutput:  &errOut,
+		Cases:      cases,
+		Tombstones: []diffview.Tombstone{{CaseID: "drop", DeletedAt: time.Now()}},
 	}
 
-	err := collector.Run(context.Background())
+	err := gc.Run()
 	require.NoError(t, err)
 
-	output := stdout.String()
-	// Output should include file path in diff structure
-	assert.Contains(t, output, `"NewPath":"src/auth/login.go"`)
-}
-
-func TestCollector_Run_GitLogError(t *testing.T) {
-	t.Parallel()
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback to commit-level
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, errors.New("not a git repository")
-			},
-			ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return "", nil
-			},
-			MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return "", nil
-			},
-		},
-	}
-
-	err := collector.Run(context.Background())
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not a git repository")
-}
-
-func TestCollector_Run_GitShowError(t *testing.T) {
-	t.Parallel()
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback to commit-level
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"abc123"}, nil
-			},
-			ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return "", errors.New("commit not found")
-			},
-			MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return "", nil
-			},
-		},
-	}
-
-	err := collector.Run(context.Background())
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "commit not found")
-}
-
-func TestCollector_Run_SkipsCommitsWithoutFiles(t *testing.T) {
-	t.Parallel()
-
-	// Some commits have no diff (e.g., empty commits)
-	emptyDiff := ""
-	realDiff := `diff --git a/a.go b/a.go
-new file mode 100644
---- /dev/null
-+++ b/a.go
-@@ -0,0 +1 @@
-+package a
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback to commit-level
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"empty-commit", "real-commit"}, nil
-			},
-			ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
-				if hash == "empty-commit" {
-					return emptyDiff, nil
-				}
-				return realDiff, nil
-			},
-			MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
-				return "Message for " + hash, nil
-			},
-		},
-	}
-
-	err := collector.Run(context.Background())
-	require.NoError(t, err)
-
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	// Should only have 1 line (real commit), empty commit skipped
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
 	require.Len(t, lines, 1)
-	assert.Contains(t, lines[0], `"hash":"real-commit"`)
-}
-
-func TestCollector_Run_GitMessageError(t *testing.T) {
-	t.Parallel()
-
-	diffOutput := `diff --git a/a.go b/a.go
-new file mode 100644
---- /dev/null
-+++ b/a.go
-@@ -0,0 +1 @@
-+package a
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback to commit-level
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"abc123"}, nil
-			},
-			ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return diffOutput, nil
-			},
-			MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return "", errors.New("failed to get commit message")
-			},
-		},
-	}
 
-	err := collector.Run(context.Background())
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to get commit message")
+	var c diffview.EvalCase
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &c))
+	assert.Equal(t, "keep", c.CaseID())
+	assert.Contains(t, errOut.String(), "removed 1 of 2 cases")
 }
 
-func TestClassifyRunner_Run_ClassifiesAllCases(t *testing.T) {
+func TestGC_Run_AppliesGoldLabels(t *testing.T) {
 	t.Parallel()
 
-	// Create test cases (as if read from JSONL)
-	testCases := []diffview.EvalCase{
-		{
-			Input: diffview.ClassificationInput{
-				Repo: "testrepo", Commits: []diffview.CommitBrief{{Hash: "abc123", Message: "Fix bug"}},
-				Diff: diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
-			},
-			Story: nil,
-		},
-		{
-			Input: diffview.ClassificationInput{
-				Repo: "testrepo", Commits: []diffview.CommitBrief{{Hash: "def456", Message: "Add feature"}},
-				Diff: diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
-			},
-			Story: nil,
-		},
+	gold := &diffview.StoryClassification{Summary: "corrected"}
+	var out bytes.Buffer
+	gc := &main.GC{
+		Output: &out,
+		Cases:  []diffview.EvalCase{{ID: "a", Input: diffview.ClassificationInput{Repo: "repo", Branch: "a"}}},
+		Gold:   []diffview.EvalCase{{ID: "a", Input: diffview.ClassificationInput{Repo: "repo", Branch: "a"}, Gold: gold}},
 	}
 
-	var classifyCalls int
-	var stdout bytes.Buffer
-	classifier := &main.ClassifyRunner{
-		Output: &stdout,
-		Cases:  testCases,
-		Classifier: &mock.StoryClassifier{
-			ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
-				classifyCalls++
-				return &diffview.StoryClassification{
-					ChangeType: "bugfix",
-					Summary:    "Fixed a bug in " + input.FirstCommitHash(),
-				}, nil
-			},
-		},
-	}
+	require.NoError(t, gc.Run())
 
-	err := classifier.Run(context.Background())
-	require.NoError(t, err)
-
-	// Should have called classify for each case
-	assert.Equal(t, 2, classifyCalls)
-
-	// Output should be JSONL with classified stories
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 2)
-	assert.Contains(t, lines[0], `"hash":"abc123"`)
-	assert.Contains(t, lines[0], `"change_type":"bugfix"`)
-	assert.Contains(t, lines[1], `"hash":"def456"`)
-	assert.Contains(t, lines[1], `"change_type":"bugfix"`)
+	var c diffview.EvalCase
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(out.Bytes()), &c))
+	assert.Equal(t, gold, c.Gold)
 }
 
-func TestClassifyRunner_Run_RetriesOnError(t *testing.T) {
+func TestScorer_Run(t *testing.T) {
 	t.Parallel()
 
-	var callCount int
-	testCases := []diffview.EvalCase{
-		{
-			Input: diffview.ClassificationInput{
-				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
-			},
-		},
-	}
+	story := &diffview.StoryClassification{ChangeType: "bugfix", Sections: []diffview.Section{
+		{Hunks: []diffview.HunkRef{{File: "a.go"}, {File: "b.go"}}},
+	}}
 
-	var stdout, stderr bytes.Buffer
-	classifier := &main.ClassifyRunner{
-		Output:     &stdout,
-		ErrOutput:  &stderr,
-		Cases:      testCases,
-		MaxRetries: 3,
-		BackoffFn:  func(_ int) time.Duration { return 0 }, // No delay in tests
-		Classifier: &mock.StoryClassifier{
-			ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
-				callCount++
-				if callCount < 3 {
-					return nil, errors.New("API rate limit exceeded")
-				}
-				// Succeed on third attempt
-				return &diffview.StoryClassification{
-					ChangeType: "bugfix",
-					Summary:    "Fixed after retry",
-				}, nil
-			},
-		},
-	}
+	t.Run("prints a line per metric", func(t *testing.T) {
+		t.Parallel()
 
-	err := classifier.Run(context.Background())
-	require.NoError(t, err)
-	assert.Equal(t, 3, callCount)
-
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 1)
-	assert.Contains(t, lines[0], `"summary":"Fixed after retry"`)
-}
-
-func TestClassifyRunner_Run_SkipsAfterMaxRetries(t *testing.T) {
-	t.Parallel()
-
-	var callCount int
-	testCases := []diffview.EvalCase{
-		{
-			Input: diffview.ClassificationInput{
-				Commits: []diffview.CommitBrief{{Hash: "fail-case"}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
-			},
-		},
-		{
-			Input: diffview.ClassificationInput{
-				Commits: []diffview.CommitBrief{{Hash: "success-case"}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
-			},
-		},
-	}
-
-	var stdout, stderr bytes.Buffer
-	classifier := &main.ClassifyRunner{
-		Output:     &stdout,
-		ErrOutput:  &stderr,
-		Cases:      testCases,
-		MaxRetries: 3,
-		BackoffFn:  func(_ int) time.Duration { return 0 }, // No delay in tests
-		Classifier: &mock.StoryClassifier{
-			ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
-				callCount++
-				if input.FirstCommitHash() == "fail-case" {
-					return nil, errors.New("persistent error")
-				}
-				return &diffview.StoryClassification{
-					ChangeType: "feature",
-					Summary:    "Success",
-				}, nil
-			},
-		},
-	}
-
-	err := classifier.Run(context.Background())
-	require.NoError(t, err)
+		var out bytes.Buffer
+		scorer := &main.Scorer{
+			Output: &out,
+			Cases:  []diffview.EvalCase{{Story: story, Gold: story}},
+		}
 
-	// First case: 3 retries, second case: 1 call
-	assert.Equal(t, 4, callCount)
+		require.NoError(t, scorer.Run())
+		assert.Equal(t, "cases scored        1\n"+
+			"change_type         100.0%  (1/1 cases)\n"+
+			"hunk coverage       100.0%  (2/2 hunks)\n"+
+			"section assignment  100.0%  (1/1 hunk pairs)\n", out.String())
+	})
 
-	// Only successful case should be in output
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 1)
-	assert.Contains(t, lines[0], `"hash":"success-case"`)
+	t.Run("fails without gold labels", func(t *testing.T) {
+		t.Parallel()
 
-	// Warning should be in stderr
-	assert.Contains(t, stderr.String(), "fail-case")
-	assert.Contains(t, stderr.String(), "skipping")
+		scorer := &main.Scorer{Output: &bytes.Buffer{}, Cases: []diffview.EvalCase{{Story: story}}}
+		assert.Error(t, scorer.Run())
+	})
 }
 
-func TestClassifyRunner_Run_PreservesExistingStories(t *testing.T) {
+func TestExporter_Run_StripsCodeContent(t *testing.T) {
 	t.Parallel()
 
-	existingStory := &diffview.StoryClassification{
-		ChangeType: "feature",
-		Summary:    "Already classified",
-	}
-	testCases := []diffview.EvalCase{
-		{
-			Input: diffview.ClassificationInput{
-				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
-			},
-			Story: existingStory, // Already has a story
-		},
+	cases := []diffview.EvalCase{
 		{
 			Input: diffview.ClassificationInput{
-				Commits: []diffview.CommitBrief{{Hash: "def456"}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
+				Repo:    "repo",
+				Branch:  "feature",
+				Commits: []diffview.CommitBrief{{Hash: "abc123", Message: "Add secret sauce"}},
+				Diff: diffview.Diff{Files: []diffview.FileDiff{{
+					NewPath: "sauce.go",
+					Hunks: []diffview.Hunk{{
+						NewStart: 1, NewCount: 1,
+						Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "recipe := 42\n", NewLineNum: 1}},
+					}},
+				}}},
 			},
-			Story: nil, // Needs classification
+			Story: &diffview.StoryClassification{ChangeType: "feature", Summary: "Adds the sauce"},
 		},
 	}
 
-	var classifyCalls int
-	var stdout bytes.Buffer
-	classifier := &main.ClassifyRunner{
-		Output: &stdout,
-		Cases:  testCases,
-		Classifier: &mock.StoryClassifier{
-			ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
-				classifyCalls++
-				return &diffview.StoryClassification{
-					ChangeType: "bugfix",
-					Summary:    "Newly classified",
-				}, nil
-			},
-		},
-	}
-
-	err := classifier.Run(context.Background())
-	require.NoError(t, err)
-
-	// Should only call classify for the case without a story
-	assert.Equal(t, 1, classifyCalls)
-
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 2)
-	// First case should preserve original classification
-	assert.Contains(t, lines[0], `"summary":"Already classified"`)
-	// Second case should have new classification
-	assert.Contains(t, lines[1], `"summary":"Newly classified"`)
-}
-
-func TestParseBranchFromMergeMessage(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name    string
-		message string
-		want    string
-	}{
-		{
-			name:    "standard GitHub merge",
-			message: "Merge pull request #42 from user/feature-branch",
-			want:    "feature-branch",
-		},
-		{
-			name:    "multi-line message",
-			message: "Merge pull request #42 from user/feature-branch\n\nThis PR adds a new feature.",
-			want:    "feature-branch",
-		},
-		{
-			name:    "nested branch path",
-			message: "Merge pull request #42 from user/bugfix/auth/login",
-			want:    "bugfix/auth/login",
-		},
-		{
-			name:    "non-GitHub merge format",
-			message: "Merge branch 'feature' into main",
-			want:    "",
-		},
-		{
-			name:    "empty message",
-			message: "",
-			want:    "",
-		},
-		{
-			name:    "no from clause",
-			message: "Merge pull request #42",
-			want:    "",
-		},
-		{
-			name:    "no slash in user/branch",
-			message: "Merge pull request #42 from just-branch-name",
-			want:    "just-branch-name",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			got := main.ParseBranchFromMergeMessage(tt.message)
-			assert.Equal(t, tt.want, got)
-		})
-	}
-}
-
-func TestCollector_Run_FallsBackToCommitLevelWithNoMergeCommits(t *testing.T) {
-	t.Parallel()
-
-	diffOutput := `diff --git a/fix.go b/fix.go
-new file mode 100644
---- /dev/null
-+++ b/fix.go
-@@ -0,0 +1,3 @@
-+package main
-+
-+func fix() {}
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// No merge commits - triggers fallback
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return nil, nil // Empty slice means no merge commits
-			},
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"abc123"}, nil
-			},
-			ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return diffOutput, nil
-			},
-			MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
-				return "Fix bug", nil
-			},
-			// PR-level methods should not be called
-			CommitsInRangeFn: func(_ context.Context, _ string, _, _ string) ([]diffview.CommitBrief, error) {
-				t.Error("CommitsInRange should not be called in fallback mode")
-				return nil, nil
-			},
-			DiffRangeFn: func(_ context.Context, _ string, _, _ string) (string, error) {
-				t.Error("DiffRange should not be called in fallback mode")
-				return "", nil
-			},
-		},
-	}
+	var out bytes.Buffer
+	exporter := &main.Exporter{Output: &out, Cases: cases}
 
-	err := collector.Run(context.Background())
+	err := exporter.Run()
 	require.NoError(t, err)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 1)
-
-	output := lines[0]
-	// Should have single commit in the commits array
-	assert.Contains(t, output, `"hash":"abc123"`)
-	assert.Contains(t, output, `"message":"Fix bug"`)
-	// Branch should be empty in fallback mode
-	assert.Contains(t, output, `"branch":""`)
+	var c diffview.EvalCase
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(out.Bytes()), &c))
+	assert.True(t, c.Input.PathsOnly)
+	assert.Equal(t, "sauce.go", c.Input.Diff.Files[0].NewPath)
+	assert.NotContains(t, out.String(), "recipe")
+	assert.NotContains(t, out.String(), "secret sauce")
+	require.NotNil(t, c.Story)
+	assert.Equal(t, "Adds the sauce", c.Story.Summary)
 }
 
-func TestCollector_Run_ExtractsPRLevelFromMergeCommits(t *testing.T) {
+func TestSessionSummary(t *testing.T) {
 	t.Parallel()
 
-	// PR diff showing combined changes from the feature branch
-	prDiff := `diff --git a/feature.go b/feature.go
-new file mode 100644
---- /dev/null
-+++ b/feature.go
-@@ -0,0 +1,5 @@
-+package main
-+
-+func newFeature() {
-+	// implementation
-+}
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			// PR-level methods
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				// Return one merge commit
-				return []string{"merge123"}, nil
-			},
-			CommitsInRangeFn: func(_ context.Context, _ string, base, head string) ([]diffview.CommitBrief, error) {
-				// Commits in the PR (base^1..base^2 where base is merge commit)
-				if base == "merge123^1" && head == "merge123^2" {
-					return []diffview.CommitBrief{
-						{Hash: "feat1", Message: "Add new feature"},
-						{Hash: "feat2", Message: "Fix tests"},
-					}, nil
-				}
-				return nil, errors.New("unexpected range")
-			},
-			DiffRangeFn: func(_ context.Context, _ string, base, head string) (string, error) {
-				if base == "merge123^1" && head == "merge123^2" {
-					return prDiff, nil
-				}
-				return "", errors.New("unexpected range")
-			},
-			MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
-				if hash == "merge123" {
-					return "Merge pull request #42 from user/feature-branch", nil
-				}
-				return "", errors.New("unknown hash")
-			},
-			// Log is deprecated for PR-level; should not be called
-			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				t.Error("Log should not be called when merge commits exist")
-				return nil, nil
-			},
-			// Show is called to get per-commit diffs
-			ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
-				// Return empty diff - per-commit diffs are optional
-				return "", nil
-			},
+	start := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	session := diffview.ReviewSession{
+		StartedAt: start,
+		EndedAt:   start.Add(30 * time.Minute),
+		Judgments: []diffview.Judgment{
+			{CaseID: "a", Judged: true, Pass: true, JudgedAt: start.Add(time.Minute), ReviewTime: 20 * time.Second},
+			{CaseID: "b", Judged: true, Pass: false, JudgedAt: start.Add(2 * time.Minute), ReviewTime: 40 * time.Second},
 		},
 	}
 
-	err := collector.Run(context.Background())
-	require.NoError(t, err)
-
-	// Should output one PR-level case
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 1)
-
-	output := lines[0]
-	// Should have branch name extracted from merge message
-	assert.Contains(t, output, `"branch":"feature-branch"`)
-	// Should have all commits from the PR
-	assert.Contains(t, output, `"hash":"feat1"`)
-	assert.Contains(t, output, `"hash":"feat2"`)
-	assert.Contains(t, output, `"message":"Add new feature"`)
-	// Should have the combined diff
-	assert.Contains(t, output, `"NewPath":"feature.go"`)
+	assert.Equal(t, "Session: 30m0s, 2 cases judged (4.0/hour), median 30s per case, 50% pass", main.SessionSummary(session))
+	assert.Equal(t, "Session: 30m0s, no cases judged",
+		main.SessionSummary(diffview.ReviewSession{StartedAt: start, EndedAt: start.Add(30 * time.Minute)}))
 }
 
-func TestClassifyRunner_Run_ParallelPreservesExistingStories(t *testing.T) {
+func TestSessionLister_Run(t *testing.T) {
 	t.Parallel()
 
-	existingStory := &diffview.StoryClassification{
-		ChangeType: "feature",
-		Summary:    "Already classified",
-	}
-	testCases := []diffview.EvalCase{
-		{
-			Input: diffview.ClassificationInput{
-				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
-			},
-			Story: existingStory,
-		},
-		{
-			Input: diffview.ClassificationInput{
-				Commits: []diffview.CommitBrief{{Hash: "def456"}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
-			},
-			Story: nil,
-		},
-	}
+	t.Run("lists sessions oldest first with judgment counts", func(t *testing.T) {
+		t.Parallel()
 
-	var classifyCalls int
-	var stdout bytes.Buffer
-	classifier := &main.ClassifyRunner{
-		Output:  &stdout,
-		Cases:   testCases,
-		Workers: 4,
-		Classifier: &mock.StoryClassifier{
-			ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
-				classifyCalls++
-				return &diffview.StoryClassification{
-					ChangeType: "bugfix",
-					Summary:    "Newly classified",
-				}, nil
+		sessions := []diffview.ReviewSession{
+			{
+				EndedAt: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+				CaseIDs: []string{"a", "b"},
 			},
-		},
-	}
-
-	err := classifier.Run(context.Background())
-	require.NoError(t, err)
-
-	// Should only call classify for the case without a story
-	assert.Equal(t, 1, classifyCalls)
-
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 2)
-	// First case should preserve original classification
-	assert.Contains(t, lines[0], `"summary":"Already classified"`)
-	// Second case should have new classification
-	assert.Contains(t, lines[1], `"summary":"Newly classified"`)
-}
-
-func TestClassifyRunner_Run_ParallelPreservesOrder(t *testing.T) {
-	t.Parallel()
-
-	// Create test cases with predictable ordering
-	testCases := make([]diffview.EvalCase, 10)
-	for i := range testCases {
-		testCases[i] = diffview.EvalCase{
-			Input: diffview.ClassificationInput{
-				Repo:    "testrepo",
-				Commits: []diffview.CommitBrief{{Hash: fmt.Sprintf("commit%d", i), Message: fmt.Sprintf("Message %d", i)}},
-				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: fmt.Sprintf("file%d.go", i)}}},
+			{
+				EndedAt: time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC),
+				CaseIDs: []string{"a", "b"},
+				Judgments: []diffview.Judgment{
+					{CaseID: "a", Judged: true, Pass: true},
+					{CaseID: "b", Judged: true, Pass: false},
+					{CaseID: "c", Critique: "critique only"},
+				},
 			},
-			Story: nil,
 		}
-	}
 
-	var stdout bytes.Buffer
-	classifier := &main.ClassifyRunner{
-		Output:  &stdout,
-		Cases:   testCases,
-		Workers: 4, // Enable parallel processing
-		Classifier: &mock.StoryClassifier{
-			ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
-				// Add random delay to simulate real API calls and test ordering
-				time.Sleep(time.Duration(10-len(input.Commits[0].Hash)%10) * time.Millisecond)
-				return &diffview.StoryClassification{
-					ChangeType: "feature",
-					Summary:    "Summary for " + input.FirstCommitHash(),
-				}, nil
-			},
-		},
-		BackoffFn: func(_ int) time.Duration { return 0 },
-	}
+		var out bytes.Buffer
+		lister := &main.SessionLister{Output: &out, Sessions: sessions}
+		require.NoError(t, lister.Run())
 
-	err := classifier.Run(context.Background())
-	require.NoError(t, err)
+		assert.Equal(t, "#1  2025-01-15 10:30  2 cases  0 judged (0 pass, 0 fail)\n"+
+			"#2  2025-01-16 09:00  2 cases  2 judged (1 pass, 1 fail)\n", out.String())
+	})
 
-	// Output should be JSONL with lines in the same order as input
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 10)
-
-	// Verify order matches input order
-	for i, line := range lines {
-		expectedHash := fmt.Sprintf("commit%d", i)
-		assert.Contains(t, line, fmt.Sprintf(`"hash":"%s"`, expectedHash), "line %d should contain %s", i, expectedHash)
-	}
-}
+	t.Run("reports when there are no sessions", func(t *testing.T) {
+		t.Parallel()
 
-func TestCollector_Run_PopulatesPerCommitDiffs(t *testing.T) {
-	t.Parallel()
-
-	// Combined PR diff
-	prDiff := `diff --git a/feature.go b/feature.go
-new file mode 100644
---- /dev/null
-+++ b/feature.go
-@@ -0,0 +1,3 @@
-+package main
-+
-+func feature() {}
-`
-	// Per-commit diffs
-	commit1Diff := `diff --git a/feature.go b/feature.go
-new file mode 100644
---- /dev/null
-+++ b/feature.go
-@@ -0,0 +1 @@
-+package main
-`
-	commit2Diff := `diff --git a/feature.go b/feature.go
---- a/feature.go
-+++ b/feature.go
-@@ -1 +1,3 @@
- package main
-+
-+func feature() {}
-`
-
-	var stdout bytes.Buffer
-	collector := &main.Collector{
-		Output:   &stdout,
-		RepoName: "testrepo",
-		Git: &mock.GitRunner{
-			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
-				return []string{"merge123"}, nil
-			},
-			CommitsInRangeFn: func(_ context.Context, _ string, base, head string) ([]diffview.CommitBrief, error) {
-				if base == "merge123^1" && head == "merge123^2" {
-					return []diffview.CommitBrief{
-						{Hash: "commit1", Message: "Initial feature"},
-						{Hash: "commit2", Message: "Add function"},
-					}, nil
-				}
-				return nil, errors.New("unexpected range")
-			},
-			DiffRangeFn: func(_ context.Context, _ string, base, head string) (string, error) {
-				if base == "merge123^1" && head == "merge123^2" {
-					return prDiff, nil
-				}
-				return "", errors.New("unexpected range")
-			},
-			MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
-				if hash == "merge123" {
-					return "Merge pull request #42 from user/feature-branch", nil
-				}
-				return "", errors.New("unknown hash")
-			},
-			ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
-				switch hash {
-				case "commit1":
-					return commit1Diff, nil
-				case "commit2":
-					return commit2Diff, nil
-				default:
-					return "", errors.New("unknown commit")
-				}
-			},
-		},
-	}
-
-	err := collector.Run(context.Background())
-	require.NoError(t, err)
-
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	require.Len(t, lines, 1)
+		var out bytes.Buffer
+		lister := &main.SessionLister{Output: &out}
+		require.NoError(t, lister.Run())
 
-	output := lines[0]
-
-	// Verify commits have per-commit diffs populated
-	// commit1's diff should show new file with just "package main"
-	assert.Contains(t, output, `"hash":"commit1"`)
-	assert.Contains(t, output, `"hash":"commit2"`)
-
-	// The per-commit diffs should be nested within commits
-	// Each commit should have a "diff" field with its own files
-	// commit1 adds feature.go with 1 line, commit2 modifies it adding 2 lines
-	//
-	// We check for the parsed structure - if diff is populated, we'll see
-	// nested diff objects within commits array
-	var evalCase diffview.EvalCase
-	err = json.Unmarshal([]byte(output), &evalCase)
-	require.NoError(t, err, "should parse as valid JSON")
-
-	require.Len(t, evalCase.Input.Commits, 2, "should have 2 commits")
-
-	// Verify commit1 has its per-commit diff populated
-	commit1 := evalCase.Input.Commits[0]
-	require.NotNil(t, commit1.Diff, "commit1 should have Diff populated")
-	require.Len(t, commit1.Diff.Files, 1, "commit1 diff should have 1 file")
-	assert.Equal(t, "feature.go", commit1.Diff.Files[0].NewPath)
-
-	// Verify commit2 has its per-commit diff populated
-	commit2 := evalCase.Input.Commits[1]
-	require.NotNil(t, commit2.Diff, "commit2 should have Diff populated")
-	require.Len(t, commit2.Diff.Files, 1, "commit2 diff should have 1 file")
+		assert.Equal(t, "no sessions recorded\n", out.String())
+	})
 }
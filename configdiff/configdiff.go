@@ -0,0 +1,306 @@
+// Package configdiff compares the parsed keys of JSON, YAML and TOML files
+// before and after a change, so that config changes read as the keys they
+// add, remove and change rather than as reindented and reordered lines.
+package configdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/fwojciec/diffstory"
+	"gopkg.in/yaml.v3"
+)
+
+// Compile-time interface verification.
+var _ diffview.StructuralDiffer = (*Differ)(nil)
+
+// Differ compares the versions of a file on either side of its diff. Added
+// and deleted files are recovered from the diff alone; for other files the
+// new version is read from source and the old one recovered by undoing the
+// diff's hunks.
+type Differ struct {
+	source func(path string) ([]byte, error)
+}
+
+// NewDiffer creates a Differ reading the new version of changed files from
+// source, given paths relative to the root of the repository.
+func NewDiffer(source func(path string) ([]byte, error)) *Differ {
+	return &Differ{source: source}
+}
+
+// DiffStructure returns the keys file adds, removes and changes, ordered
+// by key. Objects and arrays are compared member by member, so a change
+// deep in a document is reported at its own key.
+func (d *Differ) DiffStructure(file diffview.FileDiff) ([]diffview.KeyChange, error) {
+	name := file.Path()
+	parse := parser(name)
+	if parse == nil {
+		return nil, fmt.Errorf("%s: not a JSON, YAML or TOML file", name)
+	}
+	oldSrc, newSrc, err := d.versions(name, file)
+	if err != nil {
+		return nil, err
+	}
+	oldTree, err := parse(oldSrc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: parse old version: %w", name, err)
+	}
+	newTree, err := parse(newSrc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: parse new version: %w", name, err)
+	}
+	return compare("", orEmpty(oldTree), orEmpty(newTree), nil), nil
+}
+
+// versions returns the contents of file before and after its diff.
+func (d *Differ) versions(name string, file diffview.FileDiff) (old, new []byte, err error) {
+	switch file.Operation {
+	case diffview.FileAdded:
+		return nil, side(file, diffview.LineDeleted), nil
+	case diffview.FileDeleted:
+		return side(file, diffview.LineAdded), nil, nil
+	}
+	src, err := d.source(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	old, ok := unapply(file.Hunks, src)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: file doesn't match the diff", name)
+	}
+	return old, src, nil
+}
+
+// side joins the lines of file's hunks that aren't of type skip, which for
+// an added or deleted file is the whole of one version.
+func side(file diffview.FileDiff, skip diffview.LineType) []byte {
+	var buf bytes.Buffer
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type != skip {
+				buf.WriteString(trimEOL(line.Content))
+				buf.WriteByte('\n')
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// unapply returns the old version of src, the new version of a file, by
+// putting back the lines hunks delete and taking out the ones they add.
+// Reports false if the hunks' context and added lines aren't src's lines
+// at the same positions, as when src is a different version.
+func unapply(hunks []diffview.Hunk, src []byte) ([]byte, bool) {
+	lines := strings.Split(string(src), "\n")
+	var out []string
+	next := 0 // index of the first new line not yet copied
+	for _, hunk := range hunks {
+		if hunk.IsCombined() {
+			return nil, false
+		}
+		// A hunk that adds nothing starts after its line rather than at it
+		start := hunk.NewStart - 1
+		if hunk.NewCount == 0 {
+			start = hunk.NewStart
+		}
+		if start < next || start > len(lines) {
+			return nil, false
+		}
+		out = append(out, lines[next:start]...)
+		n := start
+		for _, line := range hunk.Lines {
+			content := trimEOL(line.Content)
+			if line.Type != diffview.LineDeleted {
+				if n >= len(lines) || trimEOL(lines[n]) != content {
+					return nil, false
+				}
+				n++
+			}
+			if line.Type != diffview.LineAdded {
+				out = append(out, content)
+			}
+		}
+		next = n
+	}
+	out = append(out, lines[next:]...)
+	return []byte(strings.Join(out, "\n")), true
+}
+
+func trimEOL(s string) string {
+	return strings.TrimRight(s, "\r\n")
+}
+
+// parser returns the parser for the file at name, by its extension, or nil
+// if the format isn't supported. Empty documents parse to nil.
+func parser(name string) func(src []byte) (any, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return parseJSON
+	case ".yaml", ".yml":
+		return parseYAML
+	case ".toml":
+		return func(src []byte) (any, error) { return parseTOML(src) }
+	}
+	return nil
+}
+
+// parseJSON keeps numbers as written, so that large integers aren't
+// rounded through float64.
+func parseJSON(src []byte) (any, error) {
+	if len(bytes.TrimSpace(src)) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func parseYAML(src []byte) (any, error) {
+	var v any
+	if err := yaml.Unmarshal(src, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// orEmpty treats a missing or empty document as an empty object, so that
+// an added file lists the keys it adds.
+func orEmpty(v any) any {
+	if v == nil {
+		return map[string]any{}
+	}
+	return v
+}
+
+// compare appends the changes from old to new at key to changes.
+func compare(key string, old, new any, changes []diffview.KeyChange) []diffview.KeyChange {
+	oldMap, oldIsMap := asMap(old)
+	newMap, newIsMap := asMap(new)
+	if oldIsMap && newIsMap {
+		var names []string
+		for name := range oldMap {
+			names = append(names, name)
+		}
+		for name := range newMap {
+			if _, ok := oldMap[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			o, inOld := oldMap[name]
+			n, inNew := newMap[name]
+			child := member(key, name)
+			switch {
+			case !inNew:
+				changes = append(changes, diffview.KeyChange{Op: diffview.KeyRemoved, Key: child, Old: format(o)})
+			case !inOld:
+				changes = append(changes, diffview.KeyChange{Op: diffview.KeyAdded, Key: child, New: format(n)})
+			default:
+				changes = compare(child, o, n, changes)
+			}
+		}
+		return changes
+	}
+
+	oldList, oldIsList := old.([]any)
+	newList, newIsList := new.([]any)
+	if oldIsList && newIsList {
+		for i := 0; i < max(len(oldList), len(newList)); i++ {
+			child := fmt.Sprintf("%s[%d]", key, i)
+			switch {
+			case i >= len(newList):
+				changes = append(changes, diffview.KeyChange{Op: diffview.KeyRemoved, Key: child, Old: format(oldList[i])})
+			case i >= len(oldList):
+				changes = append(changes, diffview.KeyChange{Op: diffview.KeyAdded, Key: child, New: format(newList[i])})
+			default:
+				changes = compare(child, oldList[i], newList[i], changes)
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		changes = append(changes, diffview.KeyChange{Op: diffview.KeyChanged, Key: key, Old: format(old), New: format(new)})
+	}
+	return changes
+}
+
+// asMap returns v as an object. YAML allows keys that aren't strings,
+// which are compared by how they print.
+func asMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			out[fmt.Sprint(k)] = v
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// member returns the key of name within key: dotted if name is a plain
+// identifier, else quoted in brackets.
+func member(key, name string) string {
+	plain := name != ""
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			plain = false
+			break
+		}
+	}
+	switch {
+	case !plain:
+		return key + "[" + strconv.Quote(name) + "]"
+	case key == "":
+		return name
+	default:
+		return key + "." + name
+	}
+}
+
+// format renders a value on one line: strings quoted, TOML values as
+// written, and objects and arrays inline with keys in order.
+func format(v any) string {
+	if m, ok := asMap(v); ok {
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = member("", name) + ": " + format(m[name])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+	switch v := v.(type) {
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = format(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case string:
+		return strconv.Quote(v)
+	case tomlValue:
+		return string(v)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
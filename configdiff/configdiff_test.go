@@ -0,0 +1,124 @@
+package configdiff_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/configdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// source serves files from a map, as the working tree would.
+func source(files map[string]string) func(string) ([]byte, error) {
+	return func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, errors.New("file not found")
+		}
+		return []byte(content), nil
+	}
+}
+
+func TestDiffer_DiffStructure(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports key changes in a reformatted JSON file", func(t *testing.T) {
+		t.Parallel()
+
+		// The old version was {"name": "api", "port": 8080, "tags": ["a"]}
+		// on one line; the new one is reindented with its keys reordered
+		newJSON := "{\n  \"port\": 9090,\n  \"name\": \"api\",\n  \"tags\": [\"a\", \"b\"],\n  \"tls\": {\"cert\": \"x.pem\"}\n}"
+		file := diffview.FileDiff{
+			OldPath: "a/config.json", NewPath: "b/config.json", Operation: diffview.FileModified,
+			Hunks: []diffview.Hunk{{
+				OldStart: 1, OldCount: 1, NewStart: 1, NewCount: 6,
+				Lines: []diffview.Line{
+					{Type: diffview.LineDeleted, Content: "{\"name\": \"api\", \"port\": 8080, \"tags\": [\"a\"]}\n", OldLineNum: 1},
+					{Type: diffview.LineAdded, Content: "{\n", NewLineNum: 1},
+					{Type: diffview.LineAdded, Content: "  \"port\": 9090,\n", NewLineNum: 2},
+					{Type: diffview.LineAdded, Content: "  \"name\": \"api\",\n", NewLineNum: 3},
+					{Type: diffview.LineAdded, Content: "  \"tags\": [\"a\", \"b\"],\n", NewLineNum: 4},
+					{Type: diffview.LineAdded, Content: "  \"tls\": {\"cert\": \"x.pem\"}\n", NewLineNum: 5},
+					{Type: diffview.LineAdded, Content: "}", NewLineNum: 6},
+				},
+			}},
+		}
+
+		changes, err := configdiff.NewDiffer(source(map[string]string{"config.json": newJSON})).DiffStructure(file)
+		require.NoError(t, err)
+		assert.Equal(t, []diffview.KeyChange{
+			{Op: diffview.KeyChanged, Key: "port", Old: "8080", New: "9090"},
+			{Op: diffview.KeyAdded, Key: "tags[1]", New: `"b"`},
+			{Op: diffview.KeyAdded, Key: "tls", New: `{cert: "x.pem"}`},
+		}, changes)
+	})
+
+	t.Run("recovers the old version around unchanged lines", func(t *testing.T) {
+		t.Parallel()
+
+		newYAML := "server:\n  host: example.com\n  port: 9090\nlog:\n  level: debug\n"
+		file := diffview.FileDiff{
+			OldPath: "deploy/app.yaml", NewPath: "deploy/app.yaml", Operation: diffview.FileModified,
+			Hunks: []diffview.Hunk{{
+				OldStart: 3, OldCount: 3, NewStart: 3, NewCount: 2,
+				Lines: []diffview.Line{
+					{Type: diffview.LineDeleted, Content: "  port: 8080\n", OldLineNum: 3},
+					{Type: diffview.LineDeleted, Content: "  \"user.name\": admin\n", OldLineNum: 4},
+					{Type: diffview.LineAdded, Content: "  port: 9090\n", NewLineNum: 3},
+					{Type: diffview.LineContext, Content: "log:\n", OldLineNum: 5, NewLineNum: 4},
+				},
+			}},
+		}
+
+		changes, err := configdiff.NewDiffer(source(map[string]string{"deploy/app.yaml": newYAML})).DiffStructure(file)
+		require.NoError(t, err)
+		assert.Equal(t, []diffview.KeyChange{
+			{Op: diffview.KeyChanged, Key: "server.port", Old: "8080", New: "9090"},
+			{Op: diffview.KeyRemoved, Key: `server["user.name"]`, Old: `"admin"`},
+		}, changes)
+	})
+
+	t.Run("lists the keys of an added TOML file", func(t *testing.T) {
+		t.Parallel()
+
+		var lines []diffview.Line
+		for i, content := range []string{
+			`title = "app" # shown in the UI`,
+			"[database]",
+			"ports = [",
+			"  8000,",
+			"  8001,",
+			"]",
+			"[[plugins]]",
+			`name = "auth"`,
+		} {
+			lines = append(lines, diffview.Line{Type: diffview.LineAdded, Content: content + "\n", NewLineNum: i + 1})
+		}
+		file := diffview.FileDiff{NewPath: "b/Cargo.toml", Operation: diffview.FileAdded, Hunks: []diffview.Hunk{{Lines: lines}}}
+
+		changes, err := configdiff.NewDiffer(source(nil)).DiffStructure(file)
+		require.NoError(t, err)
+		assert.Equal(t, []diffview.KeyChange{
+			{Op: diffview.KeyAdded, Key: "database", New: "{ports: [ 8000, 8001, ]}"},
+			{Op: diffview.KeyAdded, Key: "plugins", New: `[{name: "auth"}]`},
+			{Op: diffview.KeyAdded, Key: "title", New: `"app"`},
+		}, changes)
+	})
+
+	t.Run("rejects other files and diffs that don't match the file", func(t *testing.T) {
+		t.Parallel()
+
+		differ := configdiff.NewDiffer(source(map[string]string{"config.json": "{}\n"}))
+
+		_, err := differ.DiffStructure(diffview.FileDiff{NewPath: "main.go"})
+		assert.EqualError(t, err, "main.go: not a JSON, YAML or TOML file")
+
+		_, err = differ.DiffStructure(diffview.FileDiff{NewPath: "config.json", Hunks: []diffview.Hunk{{
+			NewStart: 1, NewCount: 1,
+			Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "{\"a\": 1}\n", NewLineNum: 1}},
+		}}})
+		assert.EqualError(t, err, "config.json: file doesn't match the diff")
+	})
+}
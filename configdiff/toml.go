@@ -0,0 +1,227 @@
+package configdiff
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tomlValue is a TOML value as written, less comments. Values are compared
+// as text, so 0x10 and 16 differ, but arrays and inline tables spread over
+// several lines compare equal to the same on one line.
+type tomlValue string
+
+// parseTOML parses the tables and keys of a TOML document into nested maps,
+// with arrays of tables as lists of maps and every other value a tomlValue.
+func parseTOML(src []byte) (map[string]any, error) {
+	root := map[string]any{}
+	current := root
+	var stmt strings.Builder
+	for i, line := range strings.Split(string(src), "\n") {
+		if stmt.Len() > 0 {
+			stmt.WriteByte('\n')
+		}
+		stmt.WriteString(line)
+		text, done, err := scanTOML(stmt.String())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if !done {
+			continue
+		}
+		stmt.Reset()
+		text = strings.TrimSpace(text)
+		switch {
+		case text == "":
+		case strings.HasPrefix(text, "[["):
+			if !strings.HasSuffix(text, "]]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", i+1)
+			}
+			keys, err := parseTOMLKey(text[2 : len(text)-2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if current, err = appendTable(root, keys); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		case strings.HasPrefix(text, "["):
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", i+1)
+			}
+			keys, err := parseTOMLKey(text[1 : len(text)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if current, err = table(root, keys); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		default:
+			eq := keyEnd(text)
+			if eq < 0 {
+				return nil, fmt.Errorf("line %d: expected key = value", i+1)
+			}
+			keys, err := parseTOMLKey(text[:eq])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			parent, err := table(current, keys[:len(keys)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			value := strings.TrimSpace(text[eq+1:])
+			if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+				value = strings.Join(strings.Fields(value), " ")
+			}
+			parent[keys[len(keys)-1]] = tomlValue(value)
+		}
+	}
+	if stmt.Len() > 0 {
+		return nil, errors.New("unterminated value at end of file")
+	}
+	return root, nil
+}
+
+// scanTOML strips the comments from a statement and reports whether it's
+// complete: not inside a multi-line string, array or inline table.
+func scanTOML(s string) (string, bool, error) {
+	var out strings.Builder
+	depth := 0
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], `"""`), strings.HasPrefix(s[i:], `'''`):
+			delim := s[i : i+3]
+			end := strings.Index(s[i+3:], delim)
+			if end < 0 {
+				return "", false, nil
+			}
+			end += i + 6
+			out.WriteString(s[i:end])
+			i = end
+		case s[i] == '"' || s[i] == '\'':
+			j := i + 1
+			for j < len(s) && s[j] != s[i] && s[j] != '\n' {
+				if s[i] == '"' && s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) || s[j] != s[i] {
+				return "", false, errors.New("unterminated string")
+			}
+			out.WriteString(s[i : j+1])
+			i = j + 1
+		case s[i] == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		default:
+			switch s[i] {
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+			}
+			out.WriteByte(s[i])
+			i++
+		}
+	}
+	return out.String(), depth <= 0, nil
+}
+
+// keyEnd returns the index of the = after a statement's key, or -1.
+func keyEnd(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '=':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseTOMLKey splits a dotted key into its parts, unquoting quoted ones.
+func parseTOMLKey(s string) ([]string, error) {
+	var keys []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		switch {
+		case i < len(s) && quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case i < len(s) && (s[i] == '"' || s[i] == '\''):
+			quote = s[i]
+		case i == len(s) || s[i] == '.':
+			key := strings.TrimSpace(s[start:i])
+			switch {
+			case key == "":
+				return nil, fmt.Errorf("invalid key %q", strings.TrimSpace(s))
+			case key[0] == '"':
+				unquoted, err := strconv.Unquote(key)
+				if err != nil {
+					return nil, fmt.Errorf("invalid key %q", key)
+				}
+				key = unquoted
+			case key[0] == '\'':
+				key = strings.Trim(key, "'")
+			}
+			keys = append(keys, key)
+			start = i + 1
+		}
+	}
+	return keys, nil
+}
+
+// table returns the table at keys below root, creating any that are
+// missing. A key naming an array of tables continues in its last table.
+func table(root map[string]any, keys []string) (map[string]any, error) {
+	current := root
+	for _, key := range keys {
+		switch v := current[key].(type) {
+		case nil:
+			next := map[string]any{}
+			current[key] = next
+			current = next
+		case map[string]any:
+			current = v
+		case []any:
+			last, ok := v[len(v)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("key %q is already a value", key)
+			}
+			current = last
+		default:
+			return nil, fmt.Errorf("key %q is already a value", key)
+		}
+	}
+	return current, nil
+}
+
+// appendTable adds a table to the array of tables at keys, returning it.
+func appendTable(root map[string]any, keys []string) (map[string]any, error) {
+	parent, err := table(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	name := keys[len(keys)-1]
+	var list []any
+	switch v := parent[name].(type) {
+	case nil:
+	case []any:
+		list = v
+	default:
+		return nil, fmt.Errorf("key %q is already a value", name)
+	}
+	next := map[string]any{}
+	parent[name] = append(list, next)
+	return next, nil
+}
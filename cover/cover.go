@@ -0,0 +1,172 @@
+// Package cover annotates diffs with test coverage from a Go coverage
+// profile, as written by go test -coverprofile.
+package cover
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.Annotator = (*Annotator)(nil)
+
+// Block is a span of statements in a profile and how often they ran.
+type Block struct {
+	StartLine, EndLine int
+	Count              int
+}
+
+// Profile holds the blocks of a coverage profile by file, named by import
+// path as go test writes them, such as "example.com/m/pkg/a.go".
+type Profile struct {
+	Files map[string][]Block
+}
+
+// blockPattern matches "file:startLine.startCol,endLine.endCol statements count".
+var blockPattern = regexp.MustCompile(`^(.+):(\d+)\.\d+,(\d+)\.\d+ \d+ (\d+)$`)
+
+// ParseProfile reads a coverage profile.
+func ParseProfile(r io.Reader) (*Profile, error) {
+	p := &Profile{Files: make(map[string][]Block)}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		m := blockPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: invalid coverage block %q", n, line)
+		}
+		start, _ := strconv.Atoi(m[2])
+		end, _ := strconv.Atoi(m[3])
+		count, _ := strconv.Atoi(m[4])
+		p.Files[m[1]] = append(p.Files[m[1]], Block{StartLine: start, EndLine: end, Count: count})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadProfile reads the coverage profile at path.
+func LoadProfile(path string) (*Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := ParseProfile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ModulePath returns the module path declared in dir's go.mod.
+func ModulePath(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("%s: no module directive", filepath.Join(dir, "go.mod"))
+}
+
+// Annotator marks the added lines a profile covers and those it doesn't,
+// and notes each file's coverage of its changed lines.
+type Annotator struct {
+	profile *Profile
+	module  string
+}
+
+// NewAnnotator creates an Annotator for profile, whose import paths are
+// matched to the diff's paths by removing the module path.
+func NewAnnotator(profile *Profile, module string) *Annotator {
+	return &Annotator{profile: profile, module: module}
+}
+
+// Name implements diffview.Annotator.
+func (a *Annotator) Name() string {
+	return "coverage"
+}
+
+// Annotate returns a note on each added line that has statements, saying
+// whether tests ran them, and a note on each such file with the share of
+// its changed statement lines that ran. Lines in files without coverage,
+// and lines without statements, get no note.
+func (a *Annotator) Annotate(diff *diffview.Diff) []diffview.Annotation {
+	byPath := make(map[string][]Block, len(a.profile.Files))
+	for name, blocks := range a.profile.Files {
+		byPath[strings.TrimPrefix(name, a.module+"/")] = blocks
+	}
+
+	var notes []diffview.Annotation
+	for _, file := range diff.Files {
+		if file.Operation == diffview.FileDeleted {
+			continue
+		}
+		path := file.Path()
+		blocks, ok := byPath[path]
+		if !ok {
+			continue
+		}
+		var lines []diffview.Annotation
+		covered := 0
+		for i, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				if line.Type != diffview.LineAdded {
+					continue
+				}
+				ran, ok := lineCoverage(blocks, line.NewLineNum)
+				if !ok {
+					continue
+				}
+				note := diffview.Annotation{Path: path, Hunk: i + 1, Line: line.NewLineNum, Text: "not covered", Icon: "✗"}
+				if ran {
+					note.Text, note.Icon = "covered", "✓"
+					covered++
+				}
+				lines = append(lines, note)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		percent := covered * 100 / len(lines)
+		notes = append(notes, diffview.Annotation{
+			Path:  path,
+			Text:  fmt.Sprintf("%d of %d changed lines covered", covered, len(lines)),
+			Badge: fmt.Sprintf("%d%% covered", percent),
+		})
+		notes = append(notes, lines...)
+	}
+	return notes
+}
+
+// lineCoverage reports whether any block spanning line ran, and whether
+// any block spans it at all.
+func lineCoverage(blocks []Block, line int) (ran, ok bool) {
+	for _, b := range blocks {
+		if line < b.StartLine || line > b.EndLine {
+			continue
+		}
+		ok = true
+		if b.Count > 0 {
+			return true, true
+		}
+	}
+	return false, ok
+}
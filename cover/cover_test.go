@@ -0,0 +1,73 @@
+package cover_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/cover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profile = `mode: set
+example.com/m/pkg/a.go:3.14,5.2 1 1
+example.com/m/pkg/a.go:6.9,8.3 1 0
+example.com/m/pkg/a.go:7.1,7.20 1 1
+example.com/m/other.go:1.1,2.2 1 1
+`
+
+func TestParseProfile(t *testing.T) {
+	t.Parallel()
+
+	p, err := cover.ParseProfile(strings.NewReader(profile))
+	require.NoError(t, err)
+	assert.Equal(t, []cover.Block{
+		{StartLine: 3, EndLine: 5, Count: 1},
+		{StartLine: 6, EndLine: 8, Count: 0},
+		{StartLine: 7, EndLine: 7, Count: 1},
+	}, p.Files["example.com/m/pkg/a.go"])
+
+	_, err = cover.ParseProfile(strings.NewReader("mode: set\nnot a block\n"))
+	assert.Error(t, err)
+}
+
+func TestAnnotator_Annotate(t *testing.T) {
+	t.Parallel()
+
+	p, err := cover.ParseProfile(strings.NewReader(profile))
+	require.NoError(t, err)
+	hunk := diffview.Hunk{Lines: []diffview.Line{{Type: diffview.LineContext, OldLineNum: 1, NewLineNum: 1}}}
+	for _, n := range []int{2, 4, 6, 7} {
+		hunk.Lines = append(hunk.Lines, diffview.Line{Type: diffview.LineAdded, NewLineNum: n})
+	}
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{OldPath: "a/pkg/a.go", NewPath: "b/pkg/a.go", Hunks: []diffview.Hunk{hunk}},
+		{OldPath: "a/README.md", NewPath: "b/README.md", Hunks: []diffview.Hunk{hunk}},
+	}}
+
+	notes := cover.NewAnnotator(p, "example.com/m").Annotate(diff)
+
+	assert.Equal(t, []diffview.Annotation{
+		{Path: "pkg/a.go", Text: "2 of 3 changed lines covered", Badge: "66% covered"},
+		{Path: "pkg/a.go", Hunk: 1, Line: 4, Text: "covered", Icon: "✓"},
+		{Path: "pkg/a.go", Hunk: 1, Line: 6, Text: "not covered", Icon: "✗"},
+		{Path: "pkg/a.go", Hunk: 1, Line: 7, Text: "covered", Icon: "✓"},
+	}, notes, "line 2 has no statements and README.md no coverage")
+}
+
+func TestModulePath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("// comment\nmodule example.com/m\n\ngo 1.21\n"), 0o644))
+
+	module, err := cover.ModulePath(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/m", module)
+
+	_, err = cover.ModulePath(t.TempDir())
+	assert.Error(t, err)
+}
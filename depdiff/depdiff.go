@@ -0,0 +1,236 @@
+// Package depdiff summarizes the diffs of dependency manifests and
+// lockfiles, such as go.mod, package-lock.json and Cargo.lock, as the
+// packages they add, remove, upgrade and downgrade.
+package depdiff
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.DependencySummarizer = (*Summarizer)(nil)
+
+// Parser returns the version of each dependency the changed lines of hunks
+// pin, before and after the change. Dependencies on unchanged lines are
+// left out of both.
+type Parser func(hunks []diffview.Hunk) (old, new map[string]string)
+
+// format is a Parser for the files whose base name matches pattern.
+type format struct {
+	pattern string
+	parse   Parser
+}
+
+// Summarizer reads the dependency changes out of the diffs of the files it
+// has a Parser for, chosen by file name.
+type Summarizer struct {
+	formats []format
+}
+
+// NewSummarizer creates a Summarizer reading go.mod, go.sum,
+// package-lock.json (lockfile versions 2 and 3), npm-shrinkwrap.json,
+// yarn.lock, Cargo.lock, poetry.lock and pip requirements files.
+func NewSummarizer() *Summarizer {
+	s := &Summarizer{}
+	s.Register("go.mod", PerLine(goModLine))
+	s.Register("go.sum", PerLine(goSumLine))
+	s.Register("package-lock.json", Blocks(npmPackage, npmVersion))
+	s.Register("npm-shrinkwrap.json", Blocks(npmPackage, npmVersion))
+	s.Register("yarn.lock", Blocks(yarnPackage, yarnVersion))
+	s.Register("Cargo.lock", Blocks(tomlPackage, tomlVersion))
+	s.Register("poetry.lock", Blocks(tomlPackage, tomlVersion))
+	s.Register("requirements*.txt", PerLine(requirementLine))
+	return s
+}
+
+// Register has the summarizer read the files whose base name matches
+// pattern, as path.Match matches it, with parse. Patterns registered later
+// take precedence, so a built-in format can be replaced.
+func (s *Summarizer) Register(pattern string, parse Parser) {
+	s.formats = slices.Insert(s.formats, 0, format{pattern: pattern, parse: parse})
+}
+
+// SummarizeDependencies returns the dependencies file changes, ordered by
+// name, or false if no registered pattern matches its name.
+func (s *Summarizer) SummarizeDependencies(file diffview.FileDiff) ([]diffview.DependencyChange, bool) {
+	name := file.Path()
+	base := path.Base(name)
+	for _, f := range s.formats {
+		if ok, _ := path.Match(f.pattern, base); ok {
+			old, new := f.parse(file.Hunks)
+			return compare(old, new), true
+		}
+	}
+	return nil, false
+}
+
+// PerLine returns a Parser for files that pin one dependency per line, such
+// as go.mod, where parse returns the dependency a line pins and its
+// version, or false for a line that pins none.
+func PerLine(parse func(line string) (name, version string, ok bool)) Parser {
+	return func(hunks []diffview.Hunk) (map[string]string, map[string]string) {
+		old, new := make(map[string]string), make(map[string]string)
+		for _, h := range hunks {
+			for _, l := range h.Lines {
+				name, version, ok := parse(strings.TrimRight(l.Content, "\r\n"))
+				if !ok {
+					continue
+				}
+				switch l.Type {
+				case diffview.LineDeleted:
+					pin(old, name, version)
+				case diffview.LineAdded:
+					pin(new, name, version)
+				}
+			}
+		}
+		return old, new
+	}
+}
+
+// Blocks returns a Parser for files that pin each dependency in an entry
+// of several lines, such as Cargo.lock, where name returns the dependency
+// whose entry a line starts, and version the version a line in an entry
+// gives it. Name returns an empty name and true for a line that starts an
+// entry that isn't a dependency's. Hunks are read on each side of the
+// change, so a changed version is attributed to the entry it's in even
+// when the line naming the entry is unchanged.
+func Blocks(name, version func(line string) (string, bool)) Parser {
+	return func(hunks []diffview.Hunk) (map[string]string, map[string]string) {
+		old, new := make(map[string]string), make(map[string]string)
+		for _, h := range hunks {
+			var oldName, newName string
+			for _, l := range h.Lines {
+				content := strings.TrimRight(l.Content, "\r\n")
+				if n, ok := name(content); ok {
+					switch l.Type {
+					case diffview.LineContext:
+						oldName, newName = n, n
+					case diffview.LineDeleted:
+						oldName = n
+					case diffview.LineAdded:
+						newName = n
+					}
+					continue
+				}
+				v, ok := version(content)
+				if !ok {
+					continue
+				}
+				switch {
+				case l.Type == diffview.LineDeleted && oldName != "":
+					pin(old, oldName, v)
+				case l.Type == diffview.LineAdded && newName != "":
+					pin(new, newName, v)
+				}
+			}
+		}
+		return old, new
+	}
+}
+
+// pin records version for name in versions, keeping the highest of the
+// versions a file pins one dependency at, as go.sum and Cargo.lock can.
+func pin(versions map[string]string, name, version string) {
+	if prev, ok := versions[name]; !ok || compareVersions(version, prev) > 0 {
+		versions[name] = version
+	}
+}
+
+// compare returns the changes from the dependency versions in old to those
+// in new, ordered by name.
+func compare(old, new map[string]string) []diffview.DependencyChange {
+	names := make([]string, 0, len(old)+len(new))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []diffview.DependencyChange
+	for _, name := range names {
+		before, hadOld := old[name]
+		after, hasNew := new[name]
+		change := diffview.DependencyChange{Name: name, Old: before, New: after}
+		switch {
+		case !hadOld:
+			change.Op = diffview.DependencyAdded
+		case !hasNew:
+			change.Op = diffview.DependencyRemoved
+		case before == after:
+			continue
+		case compareVersions(after, before) < 0:
+			change.Op = diffview.DependencyDowngraded
+		default:
+			change.Op = diffview.DependencyUpgraded
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// compareVersions compares version strings such as "v1.10.0" and "1.9.2",
+// returning -1, 0 or 1 as a is lower than, equal to or higher than b. Runs
+// of digits compare as numbers and other runs as strings, and a version
+// with a pre-release suffix, such as "1.0.0-rc.1", is lower than the one
+// without it.
+func compareVersions(a, b string) int {
+	ra, rb := runs(strings.TrimPrefix(a, "v")), runs(strings.TrimPrefix(b, "v"))
+	for i := 0; i < len(ra) && i < len(rb); i++ {
+		if c := compareRun(ra[i], rb[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ra) > len(rb):
+		if strings.HasPrefix(ra[len(rb)], "-") {
+			return -1
+		}
+		return 1
+	case len(ra) < len(rb):
+		if strings.HasPrefix(rb[len(ra)], "-") {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// runs splits s into runs of digits and of other characters.
+func runs(s string) []string {
+	var out []string
+	start := 0
+	for i := 1; i <= len(s); i++ {
+		if i == len(s) || isDigit(s[i]) != isDigit(s[start]) {
+			out = append(out, s[start:i])
+			start = i
+		}
+	}
+	return out
+}
+
+// compareRun compares runs from runs: numerically if both are digits.
+func compareRun(a, b string) int {
+	if isDigit(a[0]) && isDigit(b[0]) {
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
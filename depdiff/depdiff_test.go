@@ -0,0 +1,178 @@
+package depdiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/depdiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// hunk builds a hunk from lines prefixed "+", "-" or " ", as in a unified
+// diff.
+func hunk(lines ...string) diffview.Hunk {
+	var h diffview.Hunk
+	for _, l := range lines {
+		line := diffview.Line{Content: l[1:] + "\n"}
+		switch l[0] {
+		case '+':
+			line.Type = diffview.LineAdded
+		case '-':
+			line.Type = diffview.LineDeleted
+		default:
+			line.Type = diffview.LineContext
+		}
+		h.Lines = append(h.Lines, line)
+	}
+	return h
+}
+
+func file(path string, hunks ...diffview.Hunk) diffview.FileDiff {
+	return diffview.FileDiff{OldPath: "a/" + path, NewPath: "b/" + path, Operation: diffview.FileModified, Hunks: hunks}
+}
+
+func TestSummarizer_SummarizeDependencies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		file diffview.FileDiff
+		want []diffview.DependencyChange
+	}{
+		{
+			name: "go.mod",
+			file: file("go.mod", hunk(
+				" require (",
+				"-\tgithub.com/stretchr/testify v1.8.4",
+				"+\tgithub.com/stretchr/testify v1.9.0",
+				"-\tgolang.org/x/text v0.14.0 // indirect",
+				"+\tgopkg.in/yaml.v3 v3.0.1",
+				" )",
+				"-go 1.21",
+				"+go 1.22",
+			)),
+			want: []diffview.DependencyChange{
+				{Op: diffview.DependencyUpgraded, Name: "github.com/stretchr/testify", Old: "v1.8.4", New: "v1.9.0"},
+				{Op: diffview.DependencyRemoved, Name: "golang.org/x/text", Old: "v0.14.0"},
+				{Op: diffview.DependencyAdded, Name: "gopkg.in/yaml.v3", New: "v3.0.1"},
+			},
+		},
+		{
+			name: "go.sum",
+			file: file("go.sum", hunk(
+				"-github.com/stretchr/testify v1.8.4 h1:abc=",
+				"-github.com/stretchr/testify v1.8.4/go.mod h1:def=",
+				"+github.com/stretchr/testify v1.9.0 h1:ghi=",
+				"+github.com/stretchr/testify v1.9.0/go.mod h1:jkl=",
+			)),
+			want: []diffview.DependencyChange{
+				{Op: diffview.DependencyUpgraded, Name: "github.com/stretchr/testify", Old: "v1.8.4", New: "v1.9.0"},
+			},
+		},
+		{
+			name: "package-lock.json",
+			file: file("web/package-lock.json", hunk(
+				`     "": {`,
+				`-      "version": "1.0.0",`,
+				`+      "version": "1.1.0",`,
+				`     "node_modules/@babel/core": {`,
+				`-      "version": "7.24.0",`,
+				`+      "version": "7.23.9",`,
+				`       "resolved": "https://registry.npmjs.org/@babel/core/-/core-7.24.0.tgz",`,
+				`+    "node_modules/left-pad": {`,
+				`+      "version": "1.3.0",`,
+				`+    },`,
+			)),
+			want: []diffview.DependencyChange{
+				{Op: diffview.DependencyDowngraded, Name: "@babel/core", Old: "7.24.0", New: "7.23.9"},
+				{Op: diffview.DependencyAdded, Name: "left-pad", New: "1.3.0"},
+			},
+		},
+		{
+			name: "yarn.lock",
+			file: file("yarn.lock", hunk(
+				` "lodash@^4.17.0", lodash@^4.17.21:`,
+				`-  version "4.17.20"`,
+				`+  version "4.17.21"`,
+			)),
+			want: []diffview.DependencyChange{
+				{Op: diffview.DependencyUpgraded, Name: "lodash", Old: "4.17.20", New: "4.17.21"},
+			},
+		},
+		{
+			name: "Cargo.lock",
+			file: file("Cargo.lock", hunk(
+				" [[package]]",
+				` name = "serde"`,
+				`-version = "1.0.9"`,
+				`+version = "1.0.10"`,
+				` source = "registry+https://github.com/rust-lang/crates.io-index"`,
+				`-checksum = "aaa"`,
+				`+checksum = "bbb"`,
+				`-[[package]]`,
+				`-name = "syn"`,
+				`-version = "1.0.109"`,
+			)),
+			want: []diffview.DependencyChange{
+				{Op: diffview.DependencyUpgraded, Name: "serde", Old: "1.0.9", New: "1.0.10"},
+				{Op: diffview.DependencyRemoved, Name: "syn", Old: "1.0.109"},
+			},
+		},
+		{
+			name: "requirements file",
+			file: file("requirements-dev.txt", hunk(
+				"-requests[socks]==2.31.0",
+				"+requests[socks]==2.32.0rc1 ; python_version >= '3.8'",
+				" # pinned for CI",
+			)),
+			want: []diffview.DependencyChange{
+				{Op: diffview.DependencyUpgraded, Name: "requests", Old: "2.31.0", New: "2.32.0rc1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			changes, ok := depdiff.NewSummarizer().SummarizeDependencies(tt.file)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, changes)
+		})
+	}
+
+	t.Run("skips files it has no parser for", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := depdiff.NewSummarizer().SummarizeDependencies(file("main.go", hunk("+package main")))
+		assert.False(t, ok)
+	})
+
+	t.Run("orders pre-releases before their release", func(t *testing.T) {
+		t.Parallel()
+
+		changes, _ := depdiff.NewSummarizer().SummarizeDependencies(file("go.mod", hunk(
+			"-require example.com/mod v1.2.0",
+			"+require example.com/mod v1.2.0-rc.1",
+		)))
+		assert.Equal(t, []diffview.DependencyChange{
+			{Op: diffview.DependencyDowngraded, Name: "example.com/mod", Old: "v1.2.0", New: "v1.2.0-rc.1"},
+		}, changes)
+	})
+}
+
+func TestSummarizer_Register(t *testing.T) {
+	t.Parallel()
+
+	s := depdiff.NewSummarizer()
+	s.Register("*.deps", depdiff.PerLine(func(line string) (string, string, bool) {
+		name, version, ok := strings.Cut(line, "@")
+		return name, version, ok
+	}))
+
+	changes, ok := s.SummarizeDependencies(file("vendor/tools.deps", hunk("-lint@1", "+lint@2")))
+	assert.True(t, ok)
+	assert.Equal(t, []diffview.DependencyChange{
+		{Op: diffview.DependencyUpgraded, Name: "lint", Old: "1", New: "2"},
+	}, changes)
+}
@@ -0,0 +1,126 @@
+package depdiff
+
+import (
+	"regexp"
+	"strings"
+)
+
+// goModLine reads a requirement of go.mod, on its own or in a require
+// block, such as "golang.org/x/text v0.14.0 // indirect".
+func goModLine(line string) (string, string, bool) {
+	if i := strings.Index(line, "//"); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "require "))
+	if len(fields) != 2 || !strings.HasPrefix(fields[1], "v") {
+		return "", "", false
+	}
+	switch fields[0] {
+	case "module", "go", "toolchain", "replace", "exclude", "retract":
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+// goSumLine reads a checksum line of go.sum, such as
+// "golang.org/x/text v0.14.0/go.mod h1:...".
+func goSumLine(line string) (string, string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 || !strings.HasPrefix(fields[2], "h1:") {
+		return "", "", false
+	}
+	return fields[0], strings.TrimSuffix(fields[1], "/go.mod"), true
+}
+
+// requirementRe matches a pinned pip requirement, such as
+// "requests[socks]==2.31.0 ; python_version >= '3.8'".
+var requirementRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*==\s*([^\s;#]+)`)
+
+// requirementLine reads a pinned requirement of a pip requirements file.
+func requirementLine(line string) (string, string, bool) {
+	m := requirementRe.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+var (
+	npmKeyRe     = regexp.MustCompile(`^\s*"([^"]*)": \{$`)
+	npmVersionRe = regexp.MustCompile(`^\s*"version": "([^"]+)",?$`)
+)
+
+// npmPackage reads the key of an entry of a package-lock.json "packages"
+// object, such as "node_modules/@babel/core", naming the package after
+// the first node_modules/. Other objects start entries that aren't
+// packages.
+func npmPackage(line string) (string, bool) {
+	m := npmKeyRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	name, ok := strings.CutPrefix(m[1], "node_modules/")
+	if !ok {
+		return "", true
+	}
+	return name, true
+}
+
+// npmVersion reads the version of a package-lock.json entry.
+func npmVersion(line string) (string, bool) {
+	m := npmVersionRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+var yarnVersionRe = regexp.MustCompile(`^\s+version:? "?([^"\s]+)"?$`)
+
+// yarnPackage reads the unindented line starting a yarn.lock entry, which
+// lists the ranges it resolves, such as `"@babel/core@^7.0.0", "@babel/core@^7.1.0":`.
+func yarnPackage(line string) (string, bool) {
+	if line == "" || line[0] == ' ' || line[0] == '#' || !strings.HasSuffix(line, ":") {
+		return "", false
+	}
+	spec, _, _ := strings.Cut(strings.TrimSuffix(line, ":"), ",")
+	spec = strings.Trim(spec, `"`)
+	i := strings.LastIndex(spec, "@")
+	if i <= 0 {
+		return "", true
+	}
+	return spec[:i], true
+}
+
+// yarnVersion reads the version of a yarn.lock entry.
+func yarnVersion(line string) (string, bool) {
+	m := yarnVersionRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+var (
+	tomlNameRe    = regexp.MustCompile(`^name = "([^"]+)"$`)
+	tomlVersionRe = regexp.MustCompile(`^version = "([^"]+)"$`)
+)
+
+// tomlPackage reads the name of a [[package]] entry of Cargo.lock or
+// poetry.lock.
+func tomlPackage(line string) (string, bool) {
+	m := tomlNameRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// tomlVersion reads the version of a [[package]] entry.
+func tomlVersion(line string) (string, bool) {
+	m := tomlVersionRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
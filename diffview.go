@@ -3,12 +3,53 @@ package diffview
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
+	"strings"
+	"time"
 )
 
 // Diff represents a complete diff containing one or more file changes.
 type Diff struct {
-	Files []FileDiff
+	Files    []FileDiff
+	Commits  []Commit         `json:"commits,omitempty"`  // Commits the files belong to, for input like git log -p
+	Warnings []string         `json:"warnings,omitempty"` // Problems noticed while parsing, e.g. normalized line endings
+	Skipped  []SkippedSection `json:"skipped,omitempty"`  // Text a lenient parse left out
+}
+
+// SkippedSection is diff text a lenient parse left out because it couldn't
+// make sense of it, such as a hunk with a corrupted header.
+type SkippedSection struct {
+	Path   string `json:"path,omitempty"` // File the text belongs to, or "" if its header is corrupt too
+	Header string `json:"header"`         // First line of the text, such as the hunk header
+	Lines  int    `json:"lines"`          // Lines left out
+	Reason string `json:"reason"`         // What was wrong with it
+}
+
+// String describes the section in a line, like a parse warning.
+func (s SkippedSection) String() string {
+	msg := fmt.Sprintf("skipped %d line(s) from %q: %s", s.Lines, s.Header, s.Reason)
+	if s.Path == "" {
+		return msg
+	}
+	return s.Path + ": " + msg
+}
+
+// Commit is one commit of a diff spanning several, such as git log -p output.
+// A commit's files run from FirstFile up to the next commit's FirstFile; a
+// commit without changes, such as a merge, has none.
+type Commit struct {
+	Hash      string
+	Author    string // "Name <email>"
+	Date      string // As git printed it
+	Message   string // Full message, without git's indentation
+	FirstFile int    // Index in Diff.Files of the commit's first file
+}
+
+// Subject returns the first line of the commit message.
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
 }
 
 // FileDiff represents changes to a single file.
@@ -21,6 +62,18 @@ type FileDiff struct {
 	NewMode   fs.FileMode // For permission changes
 	Hunks     []Hunk
 	Extended  []string // Raw extended headers for passthrough
+	Encoding  string   // Source encoding if not UTF-8 ("utf-16le", "utf-16be", "latin-1"); content is transcoded
+	Language  string   // Language to highlight as, overriding detection; empty to detect
+}
+
+// Path returns the path of the file relative to the repository root,
+// without git's "a/" or "b/" prefix: its old path if deleted, its new path
+// otherwise. Hunk references name files by it.
+func (f FileDiff) Path() string {
+	if f.Operation == FileDeleted || f.NewPath == "" {
+		return strings.TrimPrefix(f.OldPath, "a/")
+	}
+	return strings.TrimPrefix(f.NewPath, "b/")
 }
 
 // Stats returns the number of added and deleted lines in the file.
@@ -38,6 +91,73 @@ func (f FileDiff) Stats() (added, deleted int) {
 	return added, deleted
 }
 
+// LineEnding is how the lines of one side of a file end.
+type LineEnding string
+
+// Line endings.
+const (
+	LineEndingLF    LineEnding = "LF"
+	LineEndingCRLF  LineEnding = "CRLF"
+	LineEndingMixed LineEnding = "mixed"
+)
+
+// LineEndings returns how the lines of the file's old and new sides end, as
+// far as its hunks show them. Lines without a newline don't count, and a
+// side without lines that do, such as the old side of an added file, has
+// no line ending ("").
+func (f FileDiff) LineEndings() (old, new LineEnding) {
+	var oldCRLF, oldLF, newCRLF, newLF int
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			if !strings.HasSuffix(line.Content, "\n") {
+				continue
+			}
+			crlf, lf := 0, 1
+			if line.CRLF() {
+				crlf, lf = 1, 0
+			}
+			if line.Type != LineAdded {
+				oldCRLF, oldLF = oldCRLF+crlf, oldLF+lf
+			}
+			if line.Type != LineDeleted {
+				newCRLF, newLF = newCRLF+crlf, newLF+lf
+			}
+		}
+	}
+	return lineEnding(oldCRLF, oldLF), lineEnding(newCRLF, newLF)
+}
+
+// lineEnding returns the line ending of a side with crlf lines ending in
+// CRLF and lf in LF.
+func lineEnding(crlf, lf int) LineEnding {
+	switch {
+	case crlf == 0 && lf == 0:
+		return ""
+	case crlf == 0:
+		return LineEndingLF
+	case lf == 0:
+		return LineEndingCRLF
+	}
+	return LineEndingMixed
+}
+
+// Visible reports whether viewers show the file. Binary files and mode
+// changes without hunks have nothing to show; added, deleted, renamed and
+// copied files show even when empty.
+func (f FileDiff) Visible() bool {
+	if f.IsBinary {
+		return false
+	}
+	if len(f.Hunks) > 0 {
+		return true
+	}
+	switch f.Operation {
+	case FileAdded, FileDeleted, FileRenamed, FileCopied:
+		return true
+	}
+	return false
+}
+
 // FileOp represents the type of operation performed on a file.
 type FileOp int
 
@@ -58,6 +178,22 @@ type Hunk struct {
 	NewCount int    // From @@ ...,+X,Y
 	Section  string // Optional function name after @@ ... @@
 	Lines    []Line
+
+	// Parents holds per-parent ranges for combined (merge) diffs, from
+	// @@@ -A,B -C,D +E,F @@@ headers. OldStart/OldCount mirror the first
+	// parent. Nil for regular diffs.
+	Parents []HunkRange
+}
+
+// HunkRange is a start line and line count within one side of a hunk.
+type HunkRange struct {
+	Start int
+	Count int
+}
+
+// IsCombined reports whether the hunk comes from a combined (merge) diff.
+func (h Hunk) IsCombined() bool {
+	return len(h.Parents) > 0
 }
 
 // Line represents a single line within a hunk.
@@ -67,6 +203,50 @@ type Line struct {
 	OldLineNum int  // 0 if line is Added
 	NewLineNum int  // 0 if line is Deleted
 	NoNewline  bool // "\ No newline at end of file" marker
+
+	// Markers holds one column per parent for combined diffs: LineAdded if the
+	// line is absent from that parent, LineDeleted if it only exists in that
+	// parent, LineContext otherwise. Nil for regular diffs.
+	Markers []LineType
+}
+
+// Origin identifies which side of a two-parent merge a combined diff line
+// comes from.
+type Origin int
+
+// Line origins for combined diffs.
+const (
+	OriginNone   Origin = iota // Regular diff line, or unchanged in all parents
+	OriginOurs                 // Present in the first parent only (e.g., HEAD)
+	OriginTheirs               // Present in the second parent only (e.g., MERGE_HEAD)
+	OriginBoth                 // New relative to every parent (e.g., conflict markers)
+)
+
+// CRLF reports whether the line ends in a carriage return and newline, as
+// lines of files saved on Windows do.
+func (l Line) CRLF() bool {
+	return strings.HasSuffix(l.Content, "\r\n")
+}
+
+// Origin classifies an added line in a two-parent combined diff.
+// A line absent only from the second parent came from "ours"; a line absent
+// only from the first parent came from "theirs". Returns OriginNone for
+// regular diff lines, deletions, and context.
+func (l Line) Origin() Origin {
+	if len(l.Markers) != 2 || l.Type != LineAdded {
+		return OriginNone
+	}
+	first, second := l.Markers[0] == LineAdded, l.Markers[1] == LineAdded
+	switch {
+	case first && second:
+		return OriginBoth
+	case second:
+		return OriginOurs
+	case first:
+		return OriginTheirs
+	default:
+		return OriginNone
+	}
 }
 
 // LineType represents the type of a diff line.
@@ -93,6 +273,144 @@ type WordDiffer interface {
 	Diff(old, new string) (oldSegs, newSegs []Segment)
 }
 
+// SymbolResolver replaces each hunk's Section, which git guesses from the
+// nearest line that looks like a declaration, with the declaration that
+// actually encloses the hunk's changes, found by parsing the file.
+type SymbolResolver interface {
+	// ResolveSymbols updates the Section of the hunks it can resolve,
+	// leaving the rest as git wrote them.
+	ResolveSymbols(diff *Diff)
+}
+
+// HunkGroup is a set of hunks that touch the same symbol, such as a
+// renamed function and its call sites, possibly across files.
+type HunkGroup struct {
+	Symbol string    `json:"symbol"` // Name of the symbol, or names when several touch the same hunks
+	Hunks  []HunkRef `json:"hunks"`  // In diff order; only File and HunkIndex are set
+}
+
+// HunkGrouper finds the groups of hunks in a diff that touch the same
+// symbols, to navigate between them and hint at them to the classifier.
+type HunkGrouper interface {
+	GroupHunks(diff *Diff) []HunkGroup
+}
+
+// APIChange is an exported identifier of a Go package that a diff adds,
+// removes, or changes the signature of.
+type APIChange struct {
+	Symbol string    `json:"symbol"`          // Package-qualified name, such as "server.Config.Addr"
+	Kind   string    `json:"kind"`            // added, removed, changed
+	Old    string    `json:"old,omitempty"`   // Signature before, unless added
+	New    string    `json:"new,omitempty"`   // Signature after, unless removed
+	Hunks  []HunkRef `json:"hunks,omitempty"` // Hunks changing its declaration; only File and HunkIndex are set
+}
+
+// APIDiffer compares the exported API of the packages a diff touches
+// before and after it.
+type APIDiffer interface {
+	// DiffAPI returns the identifiers the diff adds, removes or changes,
+	// ordered by name.
+	DiffAPI(diff *Diff) []APIChange
+}
+
+// MovedLines is the run of lines on one side of a Move.
+type MovedLines struct {
+	Path  string // File as the viewer shows it, without git's "a/" or "b/" prefix
+	Start int    // First line: in the old version for deleted lines, the new for added
+	Count int
+}
+
+// Move is a block of lines deleted in one place and added unchanged in
+// another, within a file or across files.
+type Move struct {
+	From MovedLines // The deleted lines
+	To   MovedLines // The added lines
+}
+
+// MoveDetector finds the blocks of lines a diff moves, so they can be shown
+// as moves rather than as unrelated deletions and additions.
+type MoveDetector interface {
+	DetectMoves(diff *Diff) []Move
+}
+
+// KeyOp is how a KeyChange changes its key.
+type KeyOp int
+
+// Key change types.
+const (
+	KeyChanged KeyOp = iota
+	KeyAdded
+	KeyRemoved
+)
+
+// KeyChange is a change to one key of a structured file, such as a JSON,
+// YAML or TOML config.
+type KeyChange struct {
+	Op  KeyOp
+	Key string // Path to the key, such as "server.ports[0]"
+	Old string // Value before, or "" if the key was added
+	New string // Value after, or "" if the key was removed
+}
+
+// StructuralDiffer compares the parsed contents of a file before and after
+// a change, for a key-level view of config files whose line diffs are
+// mostly reindentation and reordering.
+type StructuralDiffer interface {
+	// DiffStructure returns the keys file changes, in key order, or an
+	// error if its format isn't supported or a version can't be recovered
+	// or parsed.
+	DiffStructure(file FileDiff) ([]KeyChange, error)
+}
+
+// DependencyOp is how a DependencyChange changes its dependency.
+type DependencyOp int
+
+// Dependency change types.
+const (
+	DependencyUpgraded DependencyOp = iota
+	DependencyDowngraded
+	DependencyAdded
+	DependencyRemoved
+)
+
+// DependencyChange is a change to one dependency pinned by a manifest or
+// lockfile, such as go.mod or Cargo.lock.
+type DependencyChange struct {
+	Op   DependencyOp
+	Name string // Package or module, such as "golang.org/x/text"
+	Old  string // Version before, or "" if the dependency was added
+	New  string // Version after, or "" if the dependency was removed
+}
+
+// DependencySummarizer reads the dependency changes out of the diffs of
+// manifests and lockfiles, which are long, mostly checksums, and say little
+// line by line.
+type DependencySummarizer interface {
+	// SummarizeDependencies returns the dependencies file changes, ordered
+	// by name, or false if file isn't a manifest or lockfile it reads.
+	SummarizeDependencies(file FileDiff) ([]DependencyChange, bool)
+}
+
+// HistoryQuery selects commits from git history.
+type HistoryQuery struct {
+	Ref    string // Branch or other ref to walk; empty means HEAD
+	Since  string // Only commits after this date, in any format git accepts
+	Until  string // Only commits before this date, in any format git accepts
+	Merges bool   // Merge commits only, rather than only non-merge commits
+
+	// Paths limits the history to commits changing these paths, relative to
+	// the repository root. Empty means all commits.
+	Paths []string
+}
+
+// CommitMeta describes a commit well enough to choose which to sample.
+type CommitMeta struct {
+	Hash   string
+	Author string
+	Time   time.Time // Author date
+	Lines  int       // Lines added and deleted; for a merge, relative to its first parent
+}
+
 // GitRunner provides access to git operations for extracting commit history.
 type GitRunner interface {
 	// Log returns commit hashes from the repository at repoPath, limited to n commits.
@@ -108,6 +426,8 @@ type GitRunner interface {
 	// MergeCommits returns merge commit hashes from the repository, limited to n commits.
 	// Used to find PR boundaries in git history.
 	MergeCommits(ctx context.Context, repoPath string, limit int) ([]string, error)
+	// History returns every commit matching q, most recent first.
+	History(ctx context.Context, repoPath string, q HistoryQuery) ([]CommitMeta, error)
 	// CommitsInRange returns commits between base and head (base exclusive, head inclusive).
 	// For a merge commit, use merge^1..merge^2 to get all PR commits.
 	CommitsInRange(ctx context.Context, repoPath, base, head string) ([]CommitBrief, error)
@@ -124,4 +444,7 @@ type GitRunner interface {
 	// DefaultBranch returns the default branch name from origin/HEAD.
 	// Returns an error if no remote is configured.
 	DefaultBranch(ctx context.Context, repoPath string) (string, error)
+	// Clone copies the repository at url into dir with its full history,
+	// without checking out files.
+	Clone(ctx context.Context, url, dir string) error
 }
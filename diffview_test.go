@@ -7,7 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestClassificationInput_CaseID(t *testing.T) {
+func TestClassificationInput_Name(t *testing.T) {
 	t.Parallel()
 
 	t.Run("returns repo/branch format", func(t *testing.T) {
@@ -18,7 +18,7 @@ func TestClassificationInput_CaseID(t *testing.T) {
 			Branch: "feature-branch",
 		}
 
-		assert.Equal(t, "diffview/feature-branch", input.CaseID())
+		assert.Equal(t, "diffview/feature-branch", input.Name())
 	})
 
 	t.Run("handles empty repo", func(t *testing.T) {
@@ -29,7 +29,7 @@ func TestClassificationInput_CaseID(t *testing.T) {
 			Branch: "feature-branch",
 		}
 
-		assert.Equal(t, "/feature-branch", input.CaseID())
+		assert.Equal(t, "/feature-branch", input.Name())
 	})
 
 	t.Run("handles empty branch", func(t *testing.T) {
@@ -40,10 +40,134 @@ func TestClassificationInput_CaseID(t *testing.T) {
 			Branch: "",
 		}
 
-		assert.Equal(t, "diffview/", input.CaseID())
+		assert.Equal(t, "diffview/", input.Name())
 	})
 }
 
+func TestClassificationInput_ContentID(t *testing.T) {
+	t.Parallel()
+
+	input := diffview.ClassificationInput{
+		Repo:    "diffview",
+		Branch:  "feature-branch",
+		Commits: []diffview.CommitBrief{{Hash: "abc123", Message: "Add feature"}},
+		Diff: diffview.Diff{Files: []diffview.FileDiff{{
+			NewPath: "b/a.go",
+			Hunks:   []diffview.Hunk{{NewStart: 1, NewCount: 1, Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "x"}}}},
+		}}},
+	}
+	id := input.ContentID()
+
+	t.Run("is a short hash", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Len(t, id, 16)
+		assert.NotContains(t, id, "/")
+	})
+
+	t.Run("ignores names and text", func(t *testing.T) {
+		t.Parallel()
+
+		renamed := input
+		renamed.Branch = "other-branch"
+		renamed.PRTitle = "Title"
+		renamed.Commits = []diffview.CommitBrief{{Hash: "abc123", Message: "Reworded"}}
+
+		assert.Equal(t, id, renamed.ContentID())
+	})
+
+	t.Run("changes with the commits", func(t *testing.T) {
+		t.Parallel()
+
+		other := input
+		other.Commits = []diffview.CommitBrief{{Hash: "def456"}}
+
+		assert.NotEqual(t, id, other.ContentID())
+	})
+
+	t.Run("changes with the diff", func(t *testing.T) {
+		t.Parallel()
+
+		other := input
+		other.Diff = diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b/b.go"}}}
+
+		assert.NotEqual(t, id, other.ContentID())
+	})
+}
+
+func TestFileDiff_LineEndings(t *testing.T) {
+	t.Parallel()
+
+	hunk := func(lines ...diffview.Line) diffview.FileDiff {
+		return diffview.FileDiff{Hunks: []diffview.Hunk{{Lines: lines}}}
+	}
+
+	tests := []struct {
+		name     string
+		file     diffview.FileDiff
+		old, new diffview.LineEnding
+	}{
+		{"no lines", diffview.FileDiff{}, "", ""},
+		{"added", hunk(
+			diffview.Line{Type: diffview.LineAdded, Content: "a\r\n"},
+		), "", diffview.LineEndingCRLF},
+		{"LF", hunk(
+			diffview.Line{Type: diffview.LineContext, Content: "a\n"},
+			diffview.Line{Type: diffview.LineAdded, Content: "b\n"},
+		), diffview.LineEndingLF, diffview.LineEndingLF},
+		{"CRLF", hunk(
+			diffview.Line{Type: diffview.LineContext, Content: "a\r\n"},
+			diffview.Line{Type: diffview.LineDeleted, Content: "b\r\n"},
+			diffview.Line{Type: diffview.LineAdded, Content: "c\r\n"},
+		), diffview.LineEndingCRLF, diffview.LineEndingCRLF},
+		{"converted to CRLF", hunk(
+			diffview.Line{Type: diffview.LineDeleted, Content: "a\n"},
+			diffview.Line{Type: diffview.LineAdded, Content: "a\r\n"},
+		), diffview.LineEndingLF, diffview.LineEndingCRLF},
+		{"mixed", hunk(
+			diffview.Line{Type: diffview.LineContext, Content: "a\r\n"},
+			diffview.Line{Type: diffview.LineContext, Content: "b\n"},
+		), diffview.LineEndingMixed, diffview.LineEndingMixed},
+		{"last line without newline", hunk(
+			diffview.Line{Type: diffview.LineContext, Content: "a\r\n"},
+			diffview.Line{Type: diffview.LineAdded, Content: "b", NoNewline: true},
+		), diffview.LineEndingCRLF, diffview.LineEndingCRLF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			old, new := tt.file.LineEndings()
+
+			assert.Equal(t, tt.old, old)
+			assert.Equal(t, tt.new, new)
+		})
+	}
+}
+
+func TestFileDiff_Path(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		file diffview.FileDiff
+		want string
+	}{
+		{"modified", diffview.FileDiff{OldPath: "main.go", NewPath: "main.go", Operation: diffview.FileModified}, "main.go"},
+		{"renamed", diffview.FileDiff{OldPath: "old.go", NewPath: "new.go", Operation: diffview.FileRenamed}, "new.go"},
+		{"deleted", diffview.FileDiff{OldPath: "gone.go", Operation: diffview.FileDeleted}, "gone.go"},
+		{"git prefixes", diffview.FileDiff{OldPath: "a/pkg/x.go", NewPath: "b/pkg/x.go", Operation: diffview.FileModified}, "pkg/x.go"},
+		{"deleted with prefix", diffview.FileDiff{OldPath: "a/pkg/x.go", Operation: diffview.FileDeleted}, "pkg/x.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.file.Path())
+		})
+	}
+}
+
 func TestFileDiff_Stats(t *testing.T) {
 	t.Parallel()
 
@@ -128,3 +252,50 @@ func TestFileDiff_Stats(t *testing.T) {
 		assert.Equal(t, 0, deleted)
 	})
 }
+
+func TestLine_Origin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns none for regular diff lines", func(t *testing.T) {
+		t.Parallel()
+
+		line := diffview.Line{Type: diffview.LineAdded}
+
+		assert.Equal(t, diffview.OriginNone, line.Origin())
+	})
+
+	t.Run("classifies two-parent combined lines by side", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, add := diffview.LineContext, diffview.LineAdded
+		cases := []struct {
+			markers []diffview.LineType
+			want    diffview.Origin
+		}{
+			{[]diffview.LineType{ctx, add}, diffview.OriginOurs},
+			{[]diffview.LineType{add, ctx}, diffview.OriginTheirs},
+			{[]diffview.LineType{add, add}, diffview.OriginBoth},
+		}
+
+		for _, tc := range cases {
+			line := diffview.Line{Type: diffview.LineAdded, Markers: tc.markers}
+			assert.Equal(t, tc.want, line.Origin(), "markers: %v", tc.markers)
+		}
+	})
+
+	t.Run("returns none for combined deletions and context", func(t *testing.T) {
+		t.Parallel()
+
+		deleted := diffview.Line{
+			Type:    diffview.LineDeleted,
+			Markers: []diffview.LineType{diffview.LineDeleted, diffview.LineContext},
+		}
+		context := diffview.Line{
+			Type:    diffview.LineContext,
+			Markers: []diffview.LineType{diffview.LineContext, diffview.LineContext},
+		}
+
+		assert.Equal(t, diffview.OriginNone, deleted.Origin())
+		assert.Equal(t, diffview.OriginNone, context.Origin())
+	})
+}
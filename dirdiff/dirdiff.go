@@ -0,0 +1,189 @@
+// Package dirdiff compares files and directory trees on disk, producing a
+// diff without git or any other version control.
+package dirdiff
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Git's file modes, used so mode changes read the same as in git diffs.
+const (
+	modeRegular    fs.FileMode = 0o100644
+	modeExecutable fs.FileMode = 0o100755
+)
+
+// binarySniffLen is how much of a file is checked for NUL bytes when deciding
+// whether it is binary, as git does.
+const binarySniffLen = 8000
+
+// Compare diffs oldPath against newPath. Two files are compared directly and
+// labeled with the paths as given. Two directories are walked and files are
+// matched by their path relative to each root; files present on one side
+// only are added or deleted. Only regular files are compared, and .git
+// directories are skipped.
+func Compare(oldPath, newPath string) (*diffview.Diff, error) {
+	oldInfo, err := os.Stat(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newInfo, err := os.Stat(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !oldInfo.IsDir() && !newInfo.IsDir():
+		file, changed, err := compareFiles(oldPath, newPath, oldPath, newPath)
+		if err != nil {
+			return nil, err
+		}
+		diff := &diffview.Diff{}
+		if changed {
+			diff.Files = append(diff.Files, file)
+		}
+		return diff, nil
+	case oldInfo.IsDir() && newInfo.IsDir():
+		return compareDirs(oldPath, newPath)
+	default:
+		return nil, fmt.Errorf("can't compare %s with %s: one is a directory and the other a file", oldPath, newPath)
+	}
+}
+
+// compareDirs diffs every file under either root, in path order.
+func compareDirs(oldRoot, newRoot string) (*diffview.Diff, error) {
+	oldFiles, err := listFiles(oldRoot)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := listFiles(newRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(oldFiles)+len(newFiles))
+	for path := range oldFiles {
+		paths = append(paths, path)
+	}
+	for path := range newFiles {
+		if !oldFiles[path] {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	diff := &diffview.Diff{}
+	for _, path := range paths {
+		var oldFile, newFile, oldName, newName string
+		if oldFiles[path] {
+			oldFile, oldName = filepath.Join(oldRoot, filepath.FromSlash(path)), path
+		}
+		if newFiles[path] {
+			newFile, newName = filepath.Join(newRoot, filepath.FromSlash(path)), path
+		}
+		file, changed, err := compareFiles(oldFile, newFile, oldName, newName)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			diff.Files = append(diff.Files, file)
+		}
+	}
+	return diff, nil
+}
+
+// listFiles returns the slash-separated paths of the regular files under
+// root, relative to it.
+func listFiles(root string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// compareFiles diffs oldFile against newFile, where an empty path means the
+// file doesn't exist on that side, and reports whether anything changed.
+// oldName and newName label the result.
+func compareFiles(oldFile, newFile, oldName, newName string) (diffview.FileDiff, bool, error) {
+	file := diffview.FileDiff{OldPath: oldName, NewPath: newName}
+	switch {
+	case oldFile == "":
+		file.Operation = diffview.FileAdded
+	case newFile == "":
+		file.Operation = diffview.FileDeleted
+	default:
+		file.Operation = diffview.FileModified
+	}
+
+	oldData, oldMode, err := readFile(oldFile)
+	if err != nil {
+		return diffview.FileDiff{}, false, err
+	}
+	newData, newMode, err := readFile(newFile)
+	if err != nil {
+		return diffview.FileDiff{}, false, err
+	}
+	if file.Operation == diffview.FileModified && oldMode != newMode {
+		file.OldMode, file.NewMode = oldMode, newMode
+	}
+	if file.Operation == diffview.FileModified && bytes.Equal(oldData, newData) {
+		return file, file.OldMode != 0, nil
+	}
+
+	if isBinary(oldData) || isBinary(newData) {
+		file.IsBinary = true
+		return file, true, nil
+	}
+	file.Hunks = Lines(string(oldData), string(newData))
+	return file, true, nil
+}
+
+// readFile returns the content and git mode of path, or nothing if path is
+// empty.
+func readFile(path string) ([]byte, fs.FileMode, error) {
+	if path == "" {
+		return nil, 0, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	mode := modeRegular
+	if info.Mode()&0o111 != 0 {
+		mode = modeExecutable
+	}
+	return data, mode, nil
+}
+
+// isBinary reports whether data looks binary: a NUL byte near its start.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) >= 0
+}
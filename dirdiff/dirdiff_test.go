@@ -0,0 +1,205 @@
+package dirdiff_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/dirdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func numbered(from, to int) string {
+	var sb strings.Builder
+	for i := from; i <= to; i++ {
+		sb.WriteString("line ")
+		sb.WriteString(string(rune('a' + i - 1)))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func TestCompare_Files(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	oldPath, newPath := filepath.Join(dir, "old.txt"), filepath.Join(dir, "new.txt")
+	writeFile(t, oldPath, "one\ntwo\nthree\n")
+	writeFile(t, newPath, "one\n2\nthree\n")
+
+	diff, err := dirdiff.Compare(oldPath, newPath)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+
+	file := diff.Files[0]
+	assert.Equal(t, oldPath, file.OldPath)
+	assert.Equal(t, newPath, file.NewPath)
+	assert.Equal(t, diffview.FileModified, file.Operation)
+	require.Len(t, file.Hunks, 1)
+	assert.Equal(t, []diffview.Line{
+		{Type: diffview.LineContext, Content: "one\n", OldLineNum: 1, NewLineNum: 1},
+		{Type: diffview.LineDeleted, Content: "two\n", OldLineNum: 2},
+		{Type: diffview.LineAdded, Content: "2\n", NewLineNum: 2},
+		{Type: diffview.LineContext, Content: "three\n", OldLineNum: 3, NewLineNum: 3},
+	}, file.Hunks[0].Lines)
+}
+
+func TestCompare_IdenticalFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a"), "same\n")
+	writeFile(t, filepath.Join(dir, "b"), "same\n")
+
+	diff, err := dirdiff.Compare(filepath.Join(dir, "a"), filepath.Join(dir, "b"))
+	require.NoError(t, err)
+	assert.Empty(t, diff.Files)
+}
+
+func TestCompare_Directories(t *testing.T) {
+	t.Parallel()
+
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(oldDir, "same.txt"), "same\n")
+	writeFile(t, filepath.Join(newDir, "same.txt"), "same\n")
+	writeFile(t, filepath.Join(oldDir, "sub", "changed.txt"), "before\n")
+	writeFile(t, filepath.Join(newDir, "sub", "changed.txt"), "after\n")
+	writeFile(t, filepath.Join(oldDir, "gone.txt"), "bye\n")
+	writeFile(t, filepath.Join(newDir, "new.txt"), "hi\n")
+	writeFile(t, filepath.Join(newDir, ".git", "HEAD"), "ref: refs/heads/main\n")
+
+	diff, err := dirdiff.Compare(oldDir, newDir)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 3)
+
+	assert.Equal(t, "", diff.Files[0].NewPath)
+	assert.Equal(t, "gone.txt", diff.Files[0].OldPath)
+	assert.Equal(t, diffview.FileDeleted, diff.Files[0].Operation)
+
+	assert.Equal(t, "", diff.Files[1].OldPath)
+	assert.Equal(t, "new.txt", diff.Files[1].NewPath)
+	assert.Equal(t, diffview.FileAdded, diff.Files[1].Operation)
+	require.Len(t, diff.Files[1].Hunks, 1)
+	assert.Equal(t, 0, diff.Files[1].Hunks[0].OldStart)
+	assert.Equal(t, 1, diff.Files[1].Hunks[0].NewStart)
+
+	assert.Equal(t, "sub/changed.txt", diff.Files[2].OldPath)
+	assert.Equal(t, "sub/changed.txt", diff.Files[2].NewPath)
+	assert.Equal(t, diffview.FileModified, diff.Files[2].Operation)
+}
+
+func TestCompare_Binary(t *testing.T) {
+	t.Parallel()
+
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(oldDir, "image.png"), "\x89PNG\x00\x01")
+	writeFile(t, filepath.Join(newDir, "image.png"), "\x89PNG\x00\x02")
+
+	diff, err := dirdiff.Compare(oldDir, newDir)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.True(t, diff.Files[0].IsBinary)
+	assert.Empty(t, diff.Files[0].Hunks)
+}
+
+func TestCompare_ModeChange(t *testing.T) {
+	t.Parallel()
+
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(oldDir, "run.sh"), "echo hi\n")
+	writeFile(t, filepath.Join(newDir, "run.sh"), "echo hi\n")
+	require.NoError(t, os.Chmod(filepath.Join(newDir, "run.sh"), 0o755))
+
+	diff, err := dirdiff.Compare(oldDir, newDir)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, os.FileMode(0o100644), diff.Files[0].OldMode)
+	assert.Equal(t, os.FileMode(0o100755), diff.Files[0].NewMode)
+	assert.Empty(t, diff.Files[0].Hunks)
+}
+
+func TestCompare_DirectoryWithFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "file"), "x\n")
+
+	_, err := dirdiff.Compare(dir, filepath.Join(dir, "file"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "one is a directory")
+}
+
+func TestCompare_MissingPath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := dirdiff.Compare(filepath.Join(dir, "missing"), dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("splits distant changes into hunks with three lines of context", func(t *testing.T) {
+		t.Parallel()
+
+		old := numbered(1, 20)
+		new := strings.Replace(strings.Replace(old, "line b\n", "line B\n", 1), "line s\n", "line S\n", 1)
+
+		hunks := dirdiff.Lines(old, new)
+		require.Len(t, hunks, 2)
+		assert.Equal(t, diffview.Hunk{OldStart: 1, OldCount: 5, NewStart: 1, NewCount: 5}, withoutLines(hunks[0]))
+		assert.Equal(t, diffview.Hunk{OldStart: 16, OldCount: 5, NewStart: 16, NewCount: 5}, withoutLines(hunks[1]))
+	})
+
+	t.Run("merges changes whose context overlaps", func(t *testing.T) {
+		t.Parallel()
+
+		old := numbered(1, 20)
+		new := strings.Replace(strings.Replace(old, "line e\n", "line E\n", 1), "line k\n", "line K\n", 1)
+
+		hunks := dirdiff.Lines(old, new)
+		require.Len(t, hunks, 1)
+		assert.Equal(t, diffview.Hunk{OldStart: 2, OldCount: 13, NewStart: 2, NewCount: 13}, withoutLines(hunks[0]))
+	})
+
+	t.Run("marks a missing final newline", func(t *testing.T) {
+		t.Parallel()
+
+		hunks := dirdiff.Lines("a\nb", "a\nb\n")
+		require.Len(t, hunks, 1)
+		assert.Equal(t, []diffview.Line{
+			{Type: diffview.LineContext, Content: "a\n", OldLineNum: 1, NewLineNum: 1},
+			{Type: diffview.LineDeleted, Content: "b", OldLineNum: 2, NoNewline: true},
+			{Type: diffview.LineAdded, Content: "b\n", NewLineNum: 2},
+		}, hunks[0].Lines)
+	})
+
+	t.Run("starts an empty side at line zero", func(t *testing.T) {
+		t.Parallel()
+
+		hunks := dirdiff.Lines("a\nb\n", "")
+		require.Len(t, hunks, 1)
+		assert.Equal(t, diffview.Hunk{OldStart: 1, OldCount: 2, NewStart: 0, NewCount: 0}, withoutLines(hunks[0]))
+	})
+
+	t.Run("returns no hunks for equal input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, dirdiff.Lines("a\n", "a\n"))
+	})
+}
+
+func withoutLines(h diffview.Hunk) diffview.Hunk {
+	h.Lines = nil
+	return h
+}
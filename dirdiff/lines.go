@@ -0,0 +1,255 @@
+package dirdiff
+
+import (
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// contextLines is how many unchanged lines surround each change, as in git's
+// default unified diff.
+const contextLines = 3
+
+// maxEditDistance bounds the search for a shortest edit between the changed
+// middles of two files. Past it the middles are shown as replaced outright,
+// which keeps memory use in check for files that share little.
+const maxEditDistance = 2000
+
+// edit is one line of a line-by-line comparison: a line kept, deleted from
+// old or added from new, with its index on each side it appears on.
+type edit struct {
+	typ    diffview.LineType
+	oldIdx int
+	newIdx int
+}
+
+// Lines diffs old against new line by line and returns the changes as hunks
+// with three lines of context, like git diff. Line content keeps its
+// newline; a last line without one is marked NoNewline, so adding one
+// counts as a change.
+func Lines(old, new string) []diffview.Hunk {
+	a, b := splitLines(old), splitLines(new)
+	return buildHunks(a, b, diffLines(a, b))
+}
+
+// splitLines splits s after each newline, keeping the newlines so that a
+// last line without one differs from the same line with one.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the edits turning a into b.
+func diffLines(a, b []string) []edit {
+	// Trim the common prefix and suffix, which is all most changes leave
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, max(len(a), len(b)))
+	for i := range prefix {
+		edits = append(edits, edit{typ: diffview.LineContext, oldIdx: i, newIdx: i})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix, prefix)...)
+	for i := range suffix {
+		edits = append(edits, edit{typ: diffview.LineContext, oldIdx: len(a) - suffix + i, newIdx: len(b) - suffix + i})
+	}
+	return edits
+}
+
+// myers finds a shortest edit script turning a into b with Myers' algorithm,
+// offsetting indices by oldOff and newOff.
+func myers(a, b []string, oldOff, newOff int) []edit {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEditDistance)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= limit && !found; d++ {
+		// Step d only reads diagonals -d-1 through d+1 of the previous step
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // step down: add b[y]
+			} else {
+				x = v[offset+k-1] + 1 // step right: delete a[x]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return replaceAll(n, m, oldOff, newOff)
+	}
+
+	// Walk the trace back from the end, collecting edits in reverse
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v, base := trace[d], d+1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[base+k-1] < v[base+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[base+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{typ: diffview.LineContext, oldIdx: oldOff + x, newIdx: newOff + y})
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{typ: diffview.LineAdded, oldIdx: oldOff + x, newIdx: newOff + y})
+		} else {
+			x--
+			edits = append(edits, edit{typ: diffview.LineDeleted, oldIdx: oldOff + x, newIdx: newOff + y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		edits = append(edits, edit{typ: diffview.LineContext, oldIdx: oldOff + x, newIdx: newOff + y})
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return groupChanges(edits)
+}
+
+// replaceAll returns edits deleting all n old lines and adding all m new ones.
+func replaceAll(n, m, oldOff, newOff int) []edit {
+	edits := make([]edit, 0, n+m)
+	for i := range n {
+		edits = append(edits, edit{typ: diffview.LineDeleted, oldIdx: oldOff + i, newIdx: newOff})
+	}
+	for j := range m {
+		edits = append(edits, edit{typ: diffview.LineAdded, oldIdx: oldOff + n, newIdx: newOff + j})
+	}
+	return edits
+}
+
+// groupChanges reorders each run of changed lines so its deletions come
+// before its additions, as git shows them, renumbering where each falls on
+// the other side.
+func groupChanges(edits []edit) []edit {
+	for start := 0; start < len(edits); {
+		if edits[start].typ == diffview.LineContext {
+			start++
+			continue
+		}
+		end := start
+		for end < len(edits) && edits[end].typ != diffview.LineContext {
+			end++
+		}
+		run := edits[start:end]
+		oldIdx, newIdx := run[0].oldIdx, run[0].newIdx
+		var deleted, added []edit
+		for _, e := range run {
+			if e.typ == diffview.LineDeleted {
+				e.newIdx = newIdx
+				deleted = append(deleted, e)
+			} else {
+				added = append(added, e)
+			}
+		}
+		for i := range added {
+			added[i].oldIdx = oldIdx + len(deleted)
+		}
+		copy(run, append(deleted, added...))
+		start = end
+	}
+	return edits
+}
+
+// buildHunks groups edits into hunks, keeping contextLines of unchanged
+// lines around each change and merging changes whose context would overlap.
+func buildHunks(a, b []string, edits []edit) []diffview.Hunk {
+	var hunks []diffview.Hunk
+	for i := 0; i < len(edits); {
+		if edits[i].typ == diffview.LineContext {
+			i++
+			continue
+		}
+		start := max(0, i-contextLines)
+		end := i
+		for end < len(edits) {
+			if edits[end].typ != diffview.LineContext {
+				end++
+				continue
+			}
+			// Stop once the unchanged run is too long to bridge to the next change
+			run := end
+			for run < len(edits) && edits[run].typ == diffview.LineContext {
+				run++
+			}
+			if run == len(edits) || run-end > 2*contextLines {
+				end = min(run, end+contextLines)
+				break
+			}
+			end = run
+		}
+		hunks = append(hunks, newHunk(a, b, edits[start:end]))
+		i = end
+	}
+	return hunks
+}
+
+// newHunk builds a hunk from a run of edits, numbering lines from 1.
+func newHunk(a, b []string, edits []edit) diffview.Hunk {
+	hunk := diffview.Hunk{
+		OldStart: edits[0].oldIdx + 1,
+		NewStart: edits[0].newIdx + 1,
+		Lines:    make([]diffview.Line, 0, len(edits)),
+	}
+	for _, e := range edits {
+		var text string
+		line := diffview.Line{Type: e.typ}
+		if e.typ != diffview.LineAdded {
+			text = a[e.oldIdx]
+			line.OldLineNum = e.oldIdx + 1
+			hunk.OldCount++
+		}
+		if e.typ != diffview.LineDeleted {
+			text = b[e.newIdx]
+			line.NewLineNum = e.newIdx + 1
+			hunk.NewCount++
+		}
+		line.Content = text
+		line.NoNewline = !strings.HasSuffix(text, "\n")
+		hunk.Lines = append(hunk.Lines, line)
+	}
+	// An empty side starts at the line before the change, as in "@@ -0,0 +1 @@"
+	if hunk.OldCount == 0 {
+		hunk.OldStart--
+	}
+	if hunk.NewCount == 0 {
+		hunk.NewStart--
+	}
+	return hunk
+}
@@ -0,0 +1,63 @@
+// Package editor builds commands that open a file at a line in the user's
+// editor.
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Editor opens files using a command template.
+type Editor struct {
+	// Template is the command to run, split on whitespace. The placeholders
+	// {file} and {line} are replaced with the file path and line number, as
+	// in "code -g {file}:{line}". A template without placeholders, such as
+	// "vim", gets "+{line} {file}", which vi, vim, nvim, nano, emacs and
+	// micro all understand.
+	Template string
+}
+
+// FromEnv returns the editor configured in the environment: $DIFFVIEW_EDITOR,
+// then $VISUAL, then $EDITOR. Falls back to vi, except on Windows where
+// there's no editor that takes a line number to fall back to, so it returns
+// nil.
+func FromEnv(goos string, getenv func(key string) string) *Editor {
+	for _, key := range []string{"DIFFVIEW_EDITOR", "VISUAL", "EDITOR"} {
+		if t := strings.TrimSpace(getenv(key)); t != "" {
+			return &Editor{Template: t}
+		}
+	}
+	if goos == "windows" {
+		return nil
+	}
+	return &Editor{Template: "vi"}
+}
+
+// NewSystem returns the editor configured for the current process, or nil if
+// there is none.
+func NewSystem() *Editor {
+	return FromEnv(runtime.GOOS, os.Getenv)
+}
+
+// Args returns the command line that opens path at line.
+func (e *Editor) Args(path string, line int) []string {
+	fields := strings.Fields(e.Template)
+	if !strings.Contains(e.Template, "{file}") && !strings.Contains(e.Template, "{line}") {
+		fields = append(fields, "+{line}", "{file}")
+	}
+	r := strings.NewReplacer("{file}", path, "{line}", strconv.Itoa(max(line, 1)))
+	args := make([]string, len(fields))
+	for i, f := range fields {
+		args[i] = r.Replace(f)
+	}
+	return args
+}
+
+// Command returns the command that opens path at line.
+func (e *Editor) Command(path string, line int) *exec.Cmd {
+	args := e.Args(path, line)
+	return exec.Command(args[0], args[1:]...)
+}
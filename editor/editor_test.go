@@ -0,0 +1,160 @@
+package editor_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory/editor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditor_Args(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bare editor gets vi-style line argument", func(t *testing.T) {
+		t.Parallel()
+
+		e := &editor.Editor{Template: "nvim"}
+
+		assert.Equal(t, []string{"nvim", "+42", "main.go"}, e.Args("main.go", 42))
+	})
+
+	t.Run("template placeholders are replaced", func(t *testing.T) {
+		t.Parallel()
+
+		e := &editor.Editor{Template: "code --wait -g {file}:{line}"}
+
+		assert.Equal(t, []string{"code", "--wait", "-g", "pkg/a b.go:7"}, e.Args("pkg/a b.go", 7))
+	})
+
+	t.Run("line is at least one", func(t *testing.T) {
+		t.Parallel()
+
+		e := &editor.Editor{Template: "vim"}
+
+		assert.Equal(t, []string{"vim", "+1", "new.go"}, e.Args("new.go", 0))
+	})
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	t.Run("prefers DIFFVIEW_EDITOR over VISUAL and EDITOR", func(t *testing.T) {
+		t.Parallel()
+
+		e := editor.FromEnv("linux", env(map[string]string{
+			"DIFFVIEW_EDITOR": "code -g {file}:{line}",
+			"VISUAL":          "nvim",
+			"EDITOR":          "nano",
+		}))
+
+		require.NotNil(t, e)
+		assert.Equal(t, "code -g {file}:{line}", e.Template)
+	})
+
+	t.Run("prefers VISUAL over EDITOR", func(t *testing.T) {
+		t.Parallel()
+
+		e := editor.FromEnv("linux", env(map[string]string{"VISUAL": "nvim", "EDITOR": "nano"}))
+
+		require.NotNil(t, e)
+		assert.Equal(t, "nvim", e.Template)
+	})
+
+	t.Run("falls back to vi", func(t *testing.T) {
+		t.Parallel()
+
+		e := editor.FromEnv("darwin", env(nil))
+
+		require.NotNil(t, e)
+		assert.Equal(t, "vi", e.Template)
+	})
+
+	t.Run("no fallback on windows", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, editor.FromEnv("windows", env(nil)))
+	})
+}
+
+func TestNeovimFromEnv(t *testing.T) {
+	t.Parallel()
+
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	t.Run("prefers DIFFVIEW_NVIM_SERVER over NVIM", func(t *testing.T) {
+		t.Parallel()
+
+		n := editor.NeovimFromEnv(env(map[string]string{
+			"DIFFVIEW_NVIM_SERVER": "/tmp/review.sock",
+			"NVIM":                 "/run/user/1000/nvim.1.0",
+		}))
+
+		require.NotNil(t, n)
+		assert.Equal(t, "/tmp/review.sock", n.Server)
+	})
+
+	t.Run("uses NVIM inside a Neovim terminal", func(t *testing.T) {
+		t.Parallel()
+
+		n := editor.NeovimFromEnv(env(map[string]string{"NVIM": "127.0.0.1:6666"}))
+
+		require.NotNil(t, n)
+		assert.Equal(t, "127.0.0.1:6666", n.Server)
+	})
+
+	t.Run("returns nil without a server", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, editor.NeovimFromEnv(env(nil)))
+	})
+}
+
+func TestNeovim_Args(t *testing.T) {
+	t.Parallel()
+
+	t.Run("edits the absolute path at the line", func(t *testing.T) {
+		t.Parallel()
+
+		n := &editor.Neovim{Server: "/tmp/nvim.sock", Dir: "/repo"}
+
+		assert.Equal(t, []string{
+			"nvim", "--server", "/tmp/nvim.sock", "--remote-expr",
+			"execute('edit +42 ' .. fnameescape('/repo/pkg/main.go'))",
+		}, n.Args("pkg/main.go", 42))
+	})
+
+	t.Run("quotes paths with single quotes", func(t *testing.T) {
+		t.Parallel()
+
+		n := &editor.Neovim{Server: "/tmp/nvim.sock"}
+
+		args := n.Args("/repo/it's.go", 0)
+
+		assert.Equal(t, "execute('edit +1 ' .. fnameescape('/repo/it''s.go'))", args[len(args)-1])
+	})
+}
+
+func TestNeovim_QuickfixArgs(t *testing.T) {
+	t.Parallel()
+
+	n := &editor.Neovim{Server: "/tmp/nvim.sock", Dir: "/repo"}
+
+	args := n.QuickfixArgs("Don't panic", []editor.Location{
+		{Path: "a.go", Line: 3, Text: "func Parse"},
+		{Path: "/elsewhere/b.go", Line: 0, Text: "Add retries"},
+	})
+
+	assert.Equal(t, []string{"nvim", "--server", "/tmp/nvim.sock", "--remote-expr"}, args[:4])
+	assert.Equal(t,
+		`setqflist([], ' ', json_decode('{"title":"Don''t panic","items":[`+
+			`{"filename":"/repo/a.go","lnum":3,"text":"func Parse"},`+
+			`{"filename":"/elsewhere/b.go","lnum":1,"text":"Add retries"}]}')) .. execute('copen | cfirst')`,
+		args[4])
+}
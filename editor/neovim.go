@@ -0,0 +1,118 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Neovim opens files in a Neovim that's already running, through its RPC
+// server: nvim --listen, or any Neovim with a server, which is every one
+// since 0.7.
+type Neovim struct {
+	// Server is the address the running Neovim listens on: a socket path or
+	// host:port, as v:servername shows.
+	Server string
+
+	// Dir is the directory relative paths are resolved in, since the running
+	// Neovim may be in another. Empty for the current directory.
+	Dir string
+}
+
+// Location is a line of a file, for a quickfix list.
+type Location struct {
+	Path string
+	Line int
+	Text string // Shown beside the location in the list
+}
+
+// NeovimFromEnv returns the running Neovim to send files to:
+// $DIFFVIEW_NVIM_SERVER, then $NVIM, which Neovim sets for programs run in
+// its terminal. Returns nil if neither is set.
+func NeovimFromEnv(getenv func(key string) string) *Neovim {
+	for _, key := range []string{"DIFFVIEW_NVIM_SERVER", "NVIM"} {
+		if s := strings.TrimSpace(getenv(key)); s != "" {
+			return &Neovim{Server: s}
+		}
+	}
+	return nil
+}
+
+// NewSystemNeovim returns the running Neovim configured for the current
+// process, or nil if there is none.
+func NewSystemNeovim() *Neovim {
+	return NeovimFromEnv(os.Getenv)
+}
+
+// Args returns the command line that opens path at line in the running
+// Neovim.
+func (n *Neovim) Args(path string, line int) []string {
+	expr := fmt.Sprintf("execute('edit +%d ' .. fnameescape(%s))", max(line, 1), vimString(n.abs(path)))
+	return n.remoteExpr(expr)
+}
+
+// Command returns the command that opens path at line in the running Neovim.
+func (n *Neovim) Command(path string, line int) *exec.Cmd {
+	args := n.Args(path, line)
+	return exec.Command(args[0], args[1:]...)
+}
+
+// QuickfixArgs returns the command line that replaces the running Neovim's
+// quickfix list with locations under title, opens the list and jumps to the
+// first location.
+func (n *Neovim) QuickfixArgs(title string, locations []Location) []string {
+	type item struct {
+		Filename string `json:"filename"`
+		Lnum     int    `json:"lnum"`
+		Text     string `json:"text"`
+	}
+	what := struct {
+		Title string `json:"title"`
+		Items []item `json:"items"`
+	}{Title: title, Items: make([]item, len(locations))}
+	for i, loc := range locations {
+		what.Items[i] = item{Filename: n.abs(loc.Path), Lnum: max(loc.Line, 1), Text: loc.Text}
+	}
+	// Marshaling a struct of strings and ints can't fail.
+	data, _ := json.Marshal(what)
+	// Joined into a string, the result --remote-expr can print.
+	expr := fmt.Sprintf("setqflist([], ' ', json_decode(%s)) .. execute('copen | cfirst')", vimString(string(data)))
+	return n.remoteExpr(expr)
+}
+
+// QuickfixCommand returns the command that replaces the running Neovim's
+// quickfix list with locations under title.
+func (n *Neovim) QuickfixCommand(title string, locations []Location) *exec.Cmd {
+	args := n.QuickfixArgs(title, locations)
+	return exec.Command(args[0], args[1:]...)
+}
+
+// remoteExpr returns the command line that evaluates expr in the running
+// Neovim. An expression, unlike --remote, reports failure in the exit
+// status and leaves the editor's mode alone.
+func (n *Neovim) remoteExpr(expr string) []string {
+	return []string{"nvim", "--server", n.Server, "--remote-expr", expr}
+}
+
+// abs returns path resolved against the Neovim's directory.
+func (n *Neovim) abs(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	if n.Dir != "" {
+		return filepath.Join(n.Dir, path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// vimString quotes s as a Vim literal string, in which only the quote
+// itself is special.
+func vimString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
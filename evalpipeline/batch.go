@@ -0,0 +1,155 @@
+package evalpipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fwojciec/diffstory"
+	"golang.org/x/sync/errgroup"
+)
+
+// BatchOptions configures a BatchCollector.
+type BatchOptions struct {
+	// Defaults are the collector options for every repository, which its
+	// manifest entry overrides. RepoPath and RepoName are ignored.
+	Defaults CollectorOptions
+	// CloneDir holds clones of repositories without a checkout, one per
+	// directory named after the repository. Existing clones are reused.
+	CloneDir string
+	// Workers sets the number of repositories collected at once. If <= 1, runs sequentially.
+	Workers int
+	// Warnings receives a line for each skipped repository. If nil, warnings are discarded.
+	Warnings io.Writer
+}
+
+// BatchCollector extracts eval cases from several repositories into one
+// dataset.
+type BatchCollector struct {
+	git    diffview.GitRunner
+	parser diffview.Parser
+	opts   BatchOptions
+}
+
+// NewBatchCollector creates a BatchCollector that reads history through git
+// and parses diffs with parser.
+func NewBatchCollector(git diffview.GitRunner, parser diffview.Parser, opts BatchOptions) *BatchCollector {
+	if opts.Warnings == nil {
+		opts.Warnings = io.Discard
+	}
+	return &BatchCollector{
+		git:    git,
+		parser: parser,
+		opts:   opts,
+	}
+}
+
+// Run collects cases from each repository and writes them to sink as they
+// are found, so cases from different repositories interleave; each case's
+// Repo names the repository it came from. Repositories that can't be cloned
+// or read are skipped with a warning.
+func (b *BatchCollector) Run(ctx context.Context, repos []ManifestRepo, sink diffview.EvalCaseWriter) error {
+	shared := &lockedSink{sink: sink}
+	var warnMu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(b.opts.Workers, 1))
+
+	for _, repo := range repos {
+		g.Go(func() error {
+			err := b.collect(gctx, repo, shared)
+			if err == nil {
+				return nil
+			}
+			// Stop everything when the dataset can't be written or the
+			// run is cancelled, rather than skipping each repository
+			if shared.failed() || gctx.Err() != nil {
+				return err
+			}
+			warnMu.Lock()
+			fmt.Fprintf(b.opts.Warnings, "warning: skipping repo %s: %v\n", repo.Name, err)
+			warnMu.Unlock()
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// collect runs a Collector over one repository, cloning it first if needed.
+func (b *BatchCollector) collect(ctx context.Context, repo ManifestRepo, sink diffview.EvalCaseWriter) error {
+	repoPath, err := b.checkout(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	opts := b.opts.Defaults
+	opts.RepoPath = repoPath
+	opts.RepoName = repo.Name
+	if repo.Branch != "" {
+		opts.Branch = repo.Branch
+	}
+	if repo.Since != "" {
+		opts.Since = repo.Since
+	}
+	if repo.Until != "" {
+		opts.Until = repo.Until
+	}
+	if repo.Limit > 0 {
+		opts.Limit = repo.Limit
+	}
+	return NewCollector(b.git, b.parser, opts).Run(ctx, sink)
+}
+
+// checkout returns the path of the repository's checkout, cloning it to
+// Path, or into CloneDir without one, when it has none yet.
+func (b *BatchCollector) checkout(ctx context.Context, repo ManifestRepo) (string, error) {
+	if repo.Path != "" {
+		if _, err := os.Stat(repo.Path); err == nil || repo.URL == "" {
+			return repo.Path, nil
+		}
+	}
+
+	dir := repo.Path
+	if dir == "" {
+		if b.opts.CloneDir == "" {
+			return "", fmt.Errorf("no checkout and no directory to clone into")
+		}
+		dir = filepath.Join(b.opts.CloneDir, repo.Name)
+		if _, err := os.Stat(dir); err == nil {
+			return dir, nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", err
+	}
+	if err := b.git.Clone(ctx, repo.URL, dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// lockedSink lets collectors share a sink, remembering whether it failed.
+type lockedSink struct {
+	mu   sync.Mutex
+	sink diffview.EvalCaseWriter
+	err  error
+}
+
+func (s *lockedSink) Write(c diffview.EvalCase) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.err = s.sink.Write(c)
+	return s.err
+}
+
+func (s *lockedSink) failed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err != nil
+}
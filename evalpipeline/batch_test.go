@@ -0,0 +1,164 @@
+package evalpipeline_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/evalpipeline"
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchCollector_Run(t *testing.T) {
+	t.Parallel()
+
+	diffOutput := `diff --git a/fix.go b/fix.go
+--- a/fix.go
++++ b/fix.go
+@@ -1 +1 @@
+-old
++new
+`
+	checkout := t.TempDir()
+	cloneDir := t.TempDir()
+
+	var mu sync.Mutex
+	var cloned []string
+	limits := make(map[string]int)
+	gitRunner := &mock.GitRunner{
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, repoPath string, limit int) ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			limits[repoPath] = limit
+			if strings.HasSuffix(repoPath, "broken") {
+				return nil, errors.New("not a git repository")
+			}
+			return []string{"abc123"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return diffOutput, nil
+		},
+		MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "Fix bug", nil
+		},
+		CloneFn: func(_ context.Context, url, dir string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			cloned = append(cloned, url+" -> "+dir)
+			return os.Mkdir(dir, 0o755)
+		},
+	}
+	var warnings bytes.Buffer
+	batch := evalpipeline.NewBatchCollector(gitRunner, gitdiff.NewParser(), evalpipeline.BatchOptions{
+		Defaults: evalpipeline.CollectorOptions{Limit: 50, RepoName: "ignored"},
+		CloneDir: cloneDir,
+		Workers:  2,
+		Warnings: &warnings,
+	})
+	repos := []evalpipeline.ManifestRepo{
+		{Name: "local", Path: checkout, Limit: 10},
+		{Name: "remote", URL: "https://example.com/remote.git"},
+		{Name: "broken", Path: filepath.Join(checkout, "broken")},
+	}
+
+	var stdout bytes.Buffer
+	err := batch.Run(context.Background(), repos, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var c diffview.EvalCase
+		require.NoError(t, json.Unmarshal([]byte(line), &c))
+		names = append(names, c.Input.Repo)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"local", "remote"}, names)
+
+	remoteDir := filepath.Join(cloneDir, "remote")
+	assert.Equal(t, []string{"https://example.com/remote.git -> " + remoteDir}, cloned)
+	assert.Equal(t, 10, limits[checkout], "manifest limit overrides the default")
+	assert.Equal(t, 50, limits[remoteDir])
+	assert.Contains(t, warnings.String(), "warning: skipping repo broken: not a git repository")
+
+	t.Run("reuses earlier clones", func(t *testing.T) {
+		t.Parallel()
+
+		cloneDir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(cloneDir, "remote"), 0o755))
+		var mu sync.Mutex
+		clones := 0
+		gitRunner := &mock.GitRunner{
+			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+				return nil, nil
+			},
+			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+				return []string{"abc123"}, nil
+			},
+			ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+				return diffOutput, nil
+			},
+			MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+				return "Fix bug", nil
+			},
+			CloneFn: func(_ context.Context, _, _ string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				clones++
+				return nil
+			},
+		}
+		batch := evalpipeline.NewBatchCollector(gitRunner, gitdiff.NewParser(), evalpipeline.BatchOptions{
+			CloneDir: cloneDir,
+		})
+
+		var stdout bytes.Buffer
+		err := batch.Run(context.Background(), []evalpipeline.ManifestRepo{
+			{Name: "remote", URL: "https://example.com/remote.git"},
+		}, jsonl.NewWriter(&stdout))
+		require.NoError(t, err)
+
+		assert.Zero(t, clones)
+		assert.Contains(t, stdout.String(), `"repo":"remote"`)
+	})
+}
+
+func TestBatchCollector_Run_StopsWhenSinkFails(t *testing.T) {
+	t.Parallel()
+
+	gitRunner := &mock.GitRunner{
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"abc123"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n", nil
+		},
+		MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "Change", nil
+		},
+	}
+	batch := evalpipeline.NewBatchCollector(gitRunner, gitdiff.NewParser(), evalpipeline.BatchOptions{})
+	sink := &mock.EvalCaseWriter{WriteFn: func(diffview.EvalCase) error {
+		return errors.New("disk full")
+	}}
+
+	err := batch.Run(context.Background(), []evalpipeline.ManifestRepo{{Name: "a", Path: "."}}, sink)
+	assert.ErrorContains(t, err, "disk full")
+}
@@ -0,0 +1,194 @@
+package evalpipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultMaxRetries is the default number of retry attempts for classification.
+const DefaultMaxRetries = 3
+
+// ClassifyOptions configures a ClassifyRunner.
+type ClassifyOptions struct {
+	// MaxRetries is the number of classification attempts per case.
+	// If 0, DefaultMaxRetries is used.
+	MaxRetries int
+	// Workers sets the number of parallel workers. If <= 1, runs sequentially.
+	Workers int
+	// BackoffFn returns the backoff duration for a given attempt (1-indexed).
+	// If nil, uses exponential backoff (1s, 2s, 4s...).
+	BackoffFn func(attempt int) time.Duration
+	// Warnings receives a line for each skipped case. If nil, warnings are discarded.
+	Warnings io.Writer
+	// PromptVersion is recorded on each case the runner classifies.
+	PromptVersion string
+}
+
+// ClassifyRunner classifies eval cases using a story classifier.
+type ClassifyRunner struct {
+	classifier diffview.StoryClassifier
+	opts       ClassifyOptions
+}
+
+// NewClassifyRunner creates a ClassifyRunner backed by classifier.
+func NewClassifyRunner(classifier diffview.StoryClassifier, opts ClassifyOptions) *ClassifyRunner {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.BackoffFn == nil {
+		opts.BackoffFn = func(attempt int) time.Duration {
+			return time.Duration(1<<(attempt-1)) * time.Second
+		}
+	}
+	if opts.Warnings == nil {
+		opts.Warnings = io.Discard
+	}
+	return &ClassifyRunner{
+		classifier: classifier,
+		opts:       opts,
+	}
+}
+
+// Run classifies each case and writes the results to sink in input order.
+// Cases that already have a story are passed through unchanged.
+// Cases that fail after max retries are skipped with a warning.
+func (r *ClassifyRunner) Run(ctx context.Context, cases []diffview.EvalCase, sink diffview.EvalCaseWriter) error {
+	if r.opts.Workers > 1 {
+		return r.runParallel(ctx, cases, sink)
+	}
+	return r.runSequential(ctx, cases, sink)
+}
+
+func (r *ClassifyRunner) runSequential(ctx context.Context, cases []diffview.EvalCase, sink diffview.EvalCaseWriter) error {
+	for i := range cases {
+		evalCase := cases[i]
+
+		// Skip cases that already have a story
+		if evalCase.Story == nil {
+			story, err := r.classifyWithRetry(ctx, evalCase.Input)
+			if err != nil {
+				// Log warning and skip this case
+				fmt.Fprint(r.opts.Warnings, skipWarning(evalCase, r.opts.MaxRetries, err))
+				continue
+			}
+			evalCase.Story = story
+			evalCase.PromptVersion = r.opts.PromptVersion
+		}
+
+		if err := sink.Write(evalCase); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// classifyResult holds the result of classifying a single case.
+type classifyResult struct {
+	result  *diffview.EvalCase
+	skipped bool
+	skipMsg string
+}
+
+func (r *ClassifyRunner) runParallel(ctx context.Context, cases []diffview.EvalCase, sink diffview.EvalCaseWriter) error {
+	// Collect results indexed by original position
+	results := make([]classifyResult, len(cases))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(r.opts.Workers)
+
+	for i := range cases {
+		evalCase := cases[i]
+
+		g.Go(func() error {
+			var result classifyResult
+
+			// Skip cases that already have a story
+			if evalCase.Story == nil {
+				story, err := r.classifyWithRetry(gctx, evalCase.Input)
+				if err != nil {
+					result.skipped = true
+					result.skipMsg = skipWarning(evalCase, r.opts.MaxRetries, err)
+				} else {
+					evalCase.Story = story
+					evalCase.PromptVersion = r.opts.PromptVersion
+				}
+			}
+
+			if !result.skipped {
+				result.result = &evalCase
+			}
+
+			results[i] = result
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	// Write results in order
+	for _, res := range results {
+		if res.skipped {
+			fmt.Fprint(r.opts.Warnings, res.skipMsg)
+			continue
+		}
+		if res.result != nil {
+			if err := sink.Write(*res.result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// classifyWithRetry attempts classification with exponential backoff.
+func (r *ClassifyRunner) classifyWithRetry(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+	return withRetry(ctx, r.opts.MaxRetries, r.opts.BackoffFn, func() (*diffview.StoryClassification, error) {
+		return r.classifier.Classify(ctx, input)
+	})
+}
+
+// withRetry calls fn up to maxRetries times, waiting backoff(attempt)
+// between attempts, and returns the first success or the last error.
+func withRetry[T any](ctx context.Context, maxRetries int, backoff func(attempt int) time.Duration, fn func() (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		// Check for context cancellation before each attempt
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		default:
+		}
+
+		result, err := fn()
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		// Don't sleep after last attempt
+		if attempt < maxRetries {
+			select {
+			case <-ctx.Done():
+				return zero, ctx.Err()
+			case <-time.After(backoff(attempt)):
+			}
+		}
+	}
+	return zero, lastErr
+}
+
+func skipWarning(evalCase diffview.EvalCase, maxRetries int, err error) string {
+	return fmt.Sprintf("warning: skipping case %s after %d retries: %v\n",
+		evalCase.Input.FirstCommitHash(), maxRetries, err)
+}
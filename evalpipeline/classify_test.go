@@ -0,0 +1,400 @@
+package evalpipeline_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/evalpipeline"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyRunner_Run_ClassifiesAllCases(t *testing.T) {
+	t.Parallel()
+
+	// Create test cases (as if read from JSONL)
+	testCases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Repo: "testrepo", Commits: []diffview.CommitBrief{{Hash: "abc123", Message: "Fix bug"}},
+				Diff: diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
+			},
+			Story: nil,
+		},
+		{
+			Input: diffview.ClassificationInput{
+				Repo: "testrepo", Commits: []diffview.CommitBrief{{Hash: "def456", Message: "Add feature"}},
+				Diff: diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
+			},
+			Story: nil,
+		},
+	}
+
+	var classifyCalls int
+	var stdout bytes.Buffer
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			classifyCalls++
+			return &diffview.StoryClassification{
+				ChangeType: "bugfix",
+				Summary:    "Fixed a bug in " + input.FirstCommitHash(),
+			}, nil
+		},
+	}
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{})
+
+	err := runner.Run(context.Background(), testCases, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	// Should have called classify for each case
+	assert.Equal(t, 2, classifyCalls)
+
+	// Output should be JSONL with classified stories
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"hash":"abc123"`)
+	assert.Contains(t, lines[0], `"change_type":"bugfix"`)
+	assert.Contains(t, lines[1], `"hash":"def456"`)
+	assert.Contains(t, lines[1], `"change_type":"bugfix"`)
+}
+
+func TestClassifyRunner_Run_RetriesOnError(t *testing.T) {
+	t.Parallel()
+
+	var callCount int
+	testCases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
+			},
+		},
+	}
+
+	var stdout, stderr bytes.Buffer
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			callCount++
+			if callCount < 3 {
+				return nil, errors.New("API rate limit exceeded")
+			}
+			// Succeed on third attempt
+			return &diffview.StoryClassification{
+				ChangeType: "bugfix",
+				Summary:    "Fixed after retry",
+			}, nil
+		},
+	}
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{
+		MaxRetries: 3,
+		BackoffFn:  func(_ int) time.Duration { return 0 }, // No delay in tests
+		Warnings:   &stderr,
+	})
+
+	err := runner.Run(context.Background(), testCases, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+	assert.Equal(t, 3, callCount)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"summary":"Fixed after retry"`)
+}
+
+func TestClassifyRunner_Run_SkipsAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	var callCount int
+	testCases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Commits: []diffview.CommitBrief{{Hash: "fail-case"}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
+			},
+		},
+		{
+			Input: diffview.ClassificationInput{
+				Commits: []diffview.CommitBrief{{Hash: "success-case"}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
+			},
+		},
+	}
+
+	var stdout, stderr bytes.Buffer
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			callCount++
+			if input.FirstCommitHash() == "fail-case" {
+				return nil, errors.New("persistent error")
+			}
+			return &diffview.StoryClassification{
+				ChangeType: "feature",
+				Summary:    "Success",
+			}, nil
+		},
+	}
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{
+		MaxRetries: 3,
+		BackoffFn:  func(_ int) time.Duration { return 0 }, // No delay in tests
+		Warnings:   &stderr,
+	})
+
+	err := runner.Run(context.Background(), testCases, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	// First case: 3 retries, second case: 1 call
+	assert.Equal(t, 4, callCount)
+
+	// Only successful case should be in output
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"hash":"success-case"`)
+
+	// Warning should be in stderr
+	assert.Contains(t, stderr.String(), "fail-case")
+	assert.Contains(t, stderr.String(), "skipping")
+}
+
+func TestClassifyRunner_Run_PreservesExistingStories(t *testing.T) {
+	t.Parallel()
+
+	existingStory := &diffview.StoryClassification{
+		ChangeType: "feature",
+		Summary:    "Already classified",
+	}
+	testCases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
+			},
+			Story: existingStory, // Already has a story
+		},
+		{
+			Input: diffview.ClassificationInput{
+				Commits: []diffview.CommitBrief{{Hash: "def456"}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
+			},
+			Story: nil, // Needs classification
+		},
+	}
+
+	var classifyCalls int
+	var stdout bytes.Buffer
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			classifyCalls++
+			return &diffview.StoryClassification{
+				ChangeType: "bugfix",
+				Summary:    "Newly classified",
+			}, nil
+		},
+	}
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{})
+
+	err := runner.Run(context.Background(), testCases, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	// Should only call classify for the case without a story
+	assert.Equal(t, 1, classifyCalls)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	// First case should preserve original classification
+	assert.Contains(t, lines[0], `"summary":"Already classified"`)
+	// Second case should have new classification
+	assert.Contains(t, lines[1], `"summary":"Newly classified"`)
+}
+
+func TestClassifyRunner_Run_RecordsPromptVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{Commits: []diffview.CommitBrief{{Hash: "abc123"}}},
+			Story: &diffview.StoryClassification{Summary: "Already classified"},
+		},
+		{
+			Input: diffview.ClassificationInput{Commits: []diffview.CommitBrief{{Hash: "def456"}}},
+		},
+	}
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(context.Context, diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			return &diffview.StoryClassification{Summary: "Newly classified"}, nil
+		},
+	}
+
+	for _, workers := range []int{1, 2} {
+		var stdout bytes.Buffer
+		runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{
+			Workers:       workers,
+			PromptVersion: "terse@1a2b3c4d",
+		})
+
+		require.NoError(t, runner.Run(context.Background(), testCases, jsonl.NewWriter(&stdout)))
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.NotContains(t, lines[0], "prompt_version", "workers=%d", workers)
+		assert.Contains(t, lines[1], `"prompt_version":"terse@1a2b3c4d"`, "workers=%d", workers)
+	}
+}
+
+func TestClassifyRunner_Run_ParallelPreservesExistingStories(t *testing.T) {
+	t.Parallel()
+
+	existingStory := &diffview.StoryClassification{
+		ChangeType: "feature",
+		Summary:    "Already classified",
+	}
+	testCases := []diffview.EvalCase{
+		{
+			Input: diffview.ClassificationInput{
+				Commits: []diffview.CommitBrief{{Hash: "abc123"}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}},
+			},
+			Story: existingStory,
+		},
+		{
+			Input: diffview.ClassificationInput{
+				Commits: []diffview.CommitBrief{{Hash: "def456"}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: "b.go"}}},
+			},
+			Story: nil,
+		},
+	}
+
+	var classifyCalls int
+	var stdout bytes.Buffer
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			classifyCalls++
+			return &diffview.StoryClassification{
+				ChangeType: "bugfix",
+				Summary:    "Newly classified",
+			}, nil
+		},
+	}
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{
+		Workers: 4,
+	})
+
+	err := runner.Run(context.Background(), testCases, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	// Should only call classify for the case without a story
+	assert.Equal(t, 1, classifyCalls)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	// First case should preserve original classification
+	assert.Contains(t, lines[0], `"summary":"Already classified"`)
+	// Second case should have new classification
+	assert.Contains(t, lines[1], `"summary":"Newly classified"`)
+}
+
+func TestClassifyRunner_Run_ParallelPreservesOrder(t *testing.T) {
+	t.Parallel()
+
+	// Create test cases with predictable ordering
+	testCases := make([]diffview.EvalCase, 10)
+	for i := range testCases {
+		testCases[i] = diffview.EvalCase{
+			Input: diffview.ClassificationInput{
+				Repo:    "testrepo",
+				Commits: []diffview.CommitBrief{{Hash: fmt.Sprintf("commit%d", i), Message: fmt.Sprintf("Message %d", i)}},
+				Diff:    diffview.Diff{Files: []diffview.FileDiff{{NewPath: fmt.Sprintf("file%d.go", i)}}},
+			},
+			Story: nil,
+		}
+	}
+
+	var stdout bytes.Buffer
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			// Add random delay to simulate real API calls and test ordering
+			time.Sleep(time.Duration(10-len(input.Commits[0].Hash)%10) * time.Millisecond)
+			return &diffview.StoryClassification{
+				ChangeType: "feature",
+				Summary:    "Summary for " + input.FirstCommitHash(),
+			}, nil
+		},
+	}
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{
+		Workers:   4, // Enable parallel processing
+		BackoffFn: func(_ int) time.Duration { return 0 },
+	})
+
+	err := runner.Run(context.Background(), testCases, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	// Output should be JSONL with lines in the same order as input
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 10)
+
+	// Verify order matches input order
+	for i, line := range lines {
+		expectedHash := fmt.Sprintf("commit%d", i)
+		assert.Contains(t, line, fmt.Sprintf(`"hash":"%s"`, expectedHash), "line %d should contain %s", i, expectedHash)
+	}
+}
+
+func TestClassifyRunner_Run_ReturnsSinkError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []diffview.EvalCase{
+		{Input: diffview.ClassificationInput{Commits: []diffview.CommitBrief{{Hash: "abc123"}}}},
+	}
+	sinkErr := errors.New("disk full")
+
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			return &diffview.StoryClassification{ChangeType: "bugfix"}, nil
+		},
+	}
+	sink := &mock.EvalCaseWriter{
+		WriteFn: func(_ diffview.EvalCase) error {
+			return sinkErr
+		},
+	}
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{})
+
+	err := runner.Run(context.Background(), testCases, sink)
+	require.ErrorIs(t, err, sinkErr)
+}
+
+func TestClassifyRunner_Run_StopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := []diffview.EvalCase{
+		{Input: diffview.ClassificationInput{Commits: []diffview.CommitBrief{{Hash: "abc123"}}}},
+	}
+
+	var classifyCalls int
+	storyClassifier := &mock.StoryClassifier{
+		ClassifyFn: func(_ context.Context, _ diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			classifyCalls++
+			return &diffview.StoryClassification{ChangeType: "bugfix"}, nil
+		},
+	}
+	var stdout, stderr bytes.Buffer
+	runner := evalpipeline.NewClassifyRunner(storyClassifier, evalpipeline.ClassifyOptions{
+		Warnings: &stderr,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runner.Run(ctx, testCases, jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+	assert.Equal(t, 0, classifyCalls)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "context canceled")
+}
@@ -0,0 +1,486 @@
+// Package evalpipeline builds and classifies eval datasets.
+//
+// It holds the dataset pipeline used by the evalreview command so other
+// tools can collect cases from git history and classify them without
+// shelling out to the binary.
+package evalpipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+	"golang.org/x/sync/errgroup"
+)
+
+// Ways of stratifying the commits a Collector samples.
+const (
+	StratifyMonth  = "month"  // Commits authored in each calendar month
+	StratifyAuthor = "author" // Commits by each author
+	StratifySize   = "size"   // Small, medium and large changes
+)
+
+// Change size buckets used by StratifySize, by lines added and deleted.
+const (
+	smallChangeLines  = 50
+	mediumChangeLines = 300
+)
+
+// CollectorOptions configures a Collector.
+type CollectorOptions struct {
+	RepoPath string
+	RepoName string
+	Limit    int
+	MinLines int // Minimum lines changed (0 = no limit)
+	MaxLines int // Maximum lines changed (0 = no limit)
+	MaxBytes int // Maximum serialized case size in bytes (0 = no limit)
+
+	Branch   string // Branch to read history from (default: the checked out one)
+	Since    string // Only commits after this date, in any format git accepts
+	Until    string // Only commits before this date, in any format git accepts
+	Stratify string // StratifyMonth, StratifyAuthor or StratifySize to sample evenly across groups rather than take the latest commits
+
+	// File filters drop files from each case before its size is checked,
+	// and cases left with no files are skipped.
+	PathInclude []string                  // Keep only files matching one of these globs
+	PathExclude []string                  // Drop files matching any of these globs
+	Language    string                    // Keep only files in this language, such as "Go" (case-insensitive)
+	Languages   diffview.LanguageDetector // Detects file languages; required with Language
+	MaxFiles    int                       // Maximum files changed (0 = no limit)
+
+	// SquashMerges treats commits whose subjects end in a pull request
+	// number, like "Fix parser (#123)", as squash-merged pull requests when
+	// history has no merge commits, and skips the other commits.
+	SquashMerges bool
+	// PullRequests, if set, confirms each squash merge landed as its commit
+	// and supplies the pull request's branch, title and description.
+	PullRequests diffview.PullRequestFinder
+}
+
+// sampled reports whether the options select commits by more than recency.
+func (o CollectorOptions) sampled() bool {
+	return o.Branch != "" || o.Since != "" || o.Until != "" || o.Stratify != ""
+}
+
+// Collector extracts eval cases from git history.
+type Collector struct {
+	git    diffview.GitRunner
+	parser diffview.Parser
+	opts   CollectorOptions
+}
+
+// NewCollector creates a Collector that reads history through git and
+// parses diffs with parser.
+func NewCollector(git diffview.GitRunner, parser diffview.Parser, opts CollectorOptions) *Collector {
+	return &Collector{
+		git:    git,
+		parser: parser,
+		opts:   opts,
+	}
+}
+
+// Run extracts eval cases from git history and writes them to sink.
+// It first tries to extract PR-level cases from merge commits.
+// If no merge commits are found, it falls back to individual commits.
+func (c *Collector) Run(ctx context.Context, sink diffview.EvalCaseWriter) error {
+	if c.opts.Language != "" && c.opts.Languages == nil {
+		return fmt.Errorf("filtering by language %q needs a language detector", c.opts.Language)
+	}
+	if c.opts.sampled() {
+		return c.runSampled(ctx, sink)
+	}
+
+	// Try PR-level extraction first
+	mergeHashes, err := c.git.MergeCommits(ctx, c.opts.RepoPath, c.opts.Limit)
+	if err != nil {
+		return err
+	}
+
+	if len(mergeHashes) > 0 {
+		return c.runPRLevel(ctx, mergeHashes, sink)
+	}
+
+	// Fall back to commit-level extraction
+	hashes, err := c.git.Log(ctx, c.opts.RepoPath, c.opts.Limit)
+	if err != nil {
+		return err
+	}
+	return c.runCommits(ctx, hashes, sink)
+}
+
+// runCommits extracts cases from individual commits, as squash-merged pull
+// requests if SquashMerges is set.
+func (c *Collector) runCommits(ctx context.Context, hashes []string, sink diffview.EvalCaseWriter) error {
+	if c.opts.SquashMerges {
+		return c.runSquashLevel(ctx, hashes, sink)
+	}
+	return c.runCommitLevel(ctx, hashes, sink)
+}
+
+// runSampled extracts cases like Run, but from the commits on the chosen
+// branch and dates, spread across strata when Stratify is set.
+func (c *Collector) runSampled(ctx context.Context, sink diffview.EvalCaseWriter) error {
+	key, err := stratumKey(c.opts.Stratify)
+	if err != nil {
+		return err
+	}
+
+	q := diffview.HistoryQuery{
+		Ref:    c.opts.Branch,
+		Since:  c.opts.Since,
+		Until:  c.opts.Until,
+		Merges: true,
+	}
+	merges, err := c.git.History(ctx, c.opts.RepoPath, q)
+	if err != nil {
+		return err
+	}
+	if len(merges) > 0 {
+		return c.runPRLevel(ctx, sampleCommits(merges, key, c.opts.Limit), sink)
+	}
+
+	q.Merges = false
+	commits, err := c.git.History(ctx, c.opts.RepoPath, q)
+	if err != nil {
+		return err
+	}
+	return c.runCommits(ctx, sampleCommits(commits, key, c.opts.Limit), sink)
+}
+
+// stratumKey returns the function grouping commits for a Stratify option,
+// which is nil when commits aren't stratified.
+func stratumKey(stratify string) (func(diffview.CommitMeta) string, error) {
+	switch stratify {
+	case "":
+		return nil, nil
+	case StratifyMonth:
+		return func(m diffview.CommitMeta) string { return m.Time.UTC().Format("2006-01") }, nil
+	case StratifyAuthor:
+		return func(m diffview.CommitMeta) string { return m.Author }, nil
+	case StratifySize:
+		return func(m diffview.CommitMeta) string {
+			switch {
+			case m.Lines < smallChangeLines:
+				return "small"
+			case m.Lines < mediumChangeLines:
+				return "medium"
+			default:
+				return "large"
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown stratification %q: want %s, %s or %s",
+			stratify, StratifyMonth, StratifyAuthor, StratifySize)
+	}
+}
+
+// sampleCommits returns the hashes of up to limit commits, which arrive most
+// recent first. With a key, it takes the latest commit from each group in
+// turn, so groups are represented evenly until the smaller ones run out.
+func sampleCommits(commits []diffview.CommitMeta, key func(diffview.CommitMeta) string, limit int) []string {
+	if limit <= 0 || limit > len(commits) {
+		limit = len(commits)
+	}
+	hashes := make([]string, 0, limit)
+	if key == nil {
+		for _, m := range commits[:limit] {
+			hashes = append(hashes, m.Hash)
+		}
+		return hashes
+	}
+
+	// Groups are ordered by their latest commit
+	var order []string
+	groups := make(map[string][]string)
+	for _, m := range commits {
+		k := key(m)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], m.Hash)
+	}
+	for round := 0; len(hashes) < limit; round++ {
+		for _, k := range order {
+			if round < len(groups[k]) && len(hashes) < limit {
+				hashes = append(hashes, groups[k][round])
+			}
+		}
+	}
+	return hashes
+}
+
+// runPRLevel extracts PR-level cases from merge commits.
+func (c *Collector) runPRLevel(ctx context.Context, mergeHashes []string, sink diffview.EvalCaseWriter) error {
+	for _, mergeHash := range mergeHashes {
+		// Get the merge commit message to extract branch name
+		mergeMessage, err := c.git.Message(ctx, c.opts.RepoPath, mergeHash)
+		if err != nil {
+			return err
+		}
+
+		branch := ParseBranchFromMergeMessage(mergeMessage)
+
+		// Get commits in the PR (merge^1..merge^2)
+		base := mergeHash + "^1"
+		head := mergeHash + "^2"
+
+		commits, err := c.git.CommitsInRange(ctx, c.opts.RepoPath, base, head)
+		if err != nil {
+			return err
+		}
+
+		// Populate per-commit diffs concurrently (best-effort; failures are ignored)
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(8) // Limit concurrent git show subprocesses
+		for i := range commits {
+			g.Go(func() error {
+				commitDiffText, err := c.git.Show(gctx, c.opts.RepoPath, commits[i].Hash)
+				if err != nil {
+					// Per-commit diffs are optional; ignore failures
+					return nil
+				}
+				commitDiff, err := c.parser.Parse(strings.NewReader(commitDiffText))
+				if err != nil {
+					return nil
+				}
+				c.selectFiles(commitDiff)
+				commits[i].Diff = commitDiff
+				return nil
+			})
+		}
+		_ = g.Wait() // All goroutines return nil, so error is always nil
+
+		// Get combined diff for the PR
+		diffText, err := c.git.DiffRange(ctx, c.opts.RepoPath, base, head)
+		if err != nil {
+			return err
+		}
+
+		diff, err := c.parser.Parse(strings.NewReader(diffText))
+		if err != nil {
+			return err
+		}
+		c.selectFiles(diff)
+
+		// Skip PRs with no files
+		if len(diff.Files) == 0 {
+			continue
+		}
+
+		if !c.withinLimits(diff) {
+			continue
+		}
+
+		evalCase := diffview.EvalCase{
+			Input: diffview.ClassificationInput{
+				Repo:    c.opts.RepoName,
+				Branch:  branch,
+				Commits: commits,
+				Diff:    *diff,
+			},
+			Story: nil,
+		}
+
+		if err := c.write(sink, evalCase); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runCommitLevel extracts individual commit cases (fallback mode).
+func (c *Collector) runCommitLevel(ctx context.Context, hashes []string, sink diffview.EvalCaseWriter) error {
+	for _, hash := range hashes {
+		diffText, err := c.git.Show(ctx, c.opts.RepoPath, hash)
+		if err != nil {
+			return err
+		}
+
+		diff, err := c.parser.Parse(strings.NewReader(diffText))
+		if err != nil {
+			return err
+		}
+		c.selectFiles(diff)
+
+		// Skip commits with no files (e.g., merge commits)
+		if len(diff.Files) == 0 {
+			continue
+		}
+
+		if !c.withinLimits(diff) {
+			continue
+		}
+
+		// Get commit message
+		message, err := c.git.Message(ctx, c.opts.RepoPath, hash)
+		if err != nil {
+			return err
+		}
+
+		evalCase := diffview.EvalCase{
+			Input: diffview.ClassificationInput{
+				Repo: c.opts.RepoName,
+				Commits: []diffview.CommitBrief{
+					{Hash: hash, Message: message},
+				},
+				Diff: *diff,
+			},
+			Story: nil, // Not classified yet
+		}
+
+		if err := c.write(sink, evalCase); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runSquashLevel extracts PR-level cases from squash-merged pull requests.
+// Without a PullRequests finder to name its branch, a case's branch is
+// "pr-" and the pull request number.
+func (c *Collector) runSquashLevel(ctx context.Context, hashes []string, sink diffview.EvalCaseWriter) error {
+	for _, hash := range hashes {
+		message, err := c.git.Message(ctx, c.opts.RepoPath, hash)
+		if err != nil {
+			return err
+		}
+		number, ok := ParsePullRequestNumber(message)
+		if !ok {
+			continue
+		}
+
+		input := diffview.ClassificationInput{
+			Repo:    c.opts.RepoName,
+			Branch:  fmt.Sprintf("pr-%d", number),
+			Commits: []diffview.CommitBrief{{Hash: hash, Message: message}},
+		}
+		if c.opts.PullRequests != nil {
+			pr, err := c.opts.PullRequests.PullRequest(ctx, number)
+			if err != nil {
+				return err
+			}
+			// Skip reverts and cherry-picks, which keep the subject of a
+			// pull request that landed as another commit
+			if pr == nil || pr.MergeCommit != hash {
+				continue
+			}
+			input.Branch = pr.Branch
+			input.PRTitle = pr.Title
+			input.PRDescription = pr.Body
+		}
+
+		diffText, err := c.git.Show(ctx, c.opts.RepoPath, hash)
+		if err != nil {
+			return err
+		}
+		diff, err := c.parser.Parse(strings.NewReader(diffText))
+		if err != nil {
+			return err
+		}
+		c.selectFiles(diff)
+
+		if len(diff.Files) == 0 || !c.withinLimits(diff) {
+			continue
+		}
+
+		input.Diff = *diff
+		if err := c.write(sink, diffview.EvalCase{Input: input}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withinLimits reports whether the diff passes the configured MaxFiles,
+// MinLines and MaxLines filters.
+func (c *Collector) withinLimits(diff *diffview.Diff) bool {
+	if c.opts.MaxFiles > 0 && len(diff.Files) > c.opts.MaxFiles {
+		return false
+	}
+	totalLines := countLinesChanged(diff)
+	if c.opts.MinLines > 0 && totalLines < c.opts.MinLines {
+		return false
+	}
+	if c.opts.MaxLines > 0 && totalLines > c.opts.MaxLines {
+		return false
+	}
+	return true
+}
+
+// write sends the case to sink, with its ID, unless it exceeds the byte
+// size limit.
+func (c *Collector) write(sink diffview.EvalCaseWriter, evalCase diffview.EvalCase) error {
+	evalCase.ID = evalCase.Input.ContentID()
+	if c.opts.MaxBytes > 0 {
+		data, err := json.Marshal(evalCase)
+		if err != nil {
+			return err
+		}
+		if len(data) > c.opts.MaxBytes {
+			return nil
+		}
+	}
+	return sink.Write(evalCase)
+}
+
+// ParseBranchFromMergeMessage extracts the branch name from a GitHub merge commit message.
+// Format: "Merge pull request #N from user/branch-name"
+func ParseBranchFromMergeMessage(message string) string {
+	// Only parse the first line (merge messages may have additional body text)
+	firstLine := message
+	if idx := strings.IndexByte(message, '\n'); idx != -1 {
+		firstLine = message[:idx]
+	}
+
+	const prefix = "Merge pull request #"
+	if !strings.HasPrefix(firstLine, prefix) {
+		return ""
+	}
+	// Find "from user/branch"
+	fromIdx := strings.Index(firstLine, " from ")
+	if fromIdx == -1 {
+		return ""
+	}
+	userBranch := firstLine[fromIdx+6:] // Skip " from "
+	// Extract branch name after "user/"
+	slashIdx := strings.Index(userBranch, "/")
+	if slashIdx == -1 {
+		return userBranch
+	}
+	return userBranch[slashIdx+1:]
+}
+
+var pullRequestSuffix = regexp.MustCompile(`\(#(\d+)\)$`)
+
+// ParsePullRequestNumber extracts the pull request number GitHub appends to
+// the subject of a squash-merged commit.
+// Format: "Subject of the pull request (#N)"
+func ParsePullRequestNumber(message string) (int, bool) {
+	subject, _, _ := strings.Cut(message, "\n")
+	m := pullRequestSuffix.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false // Too large to be a pull request number
+	}
+	return number, true
+}
+
+// countLinesChanged returns the total number of added + deleted lines in a diff.
+func countLinesChanged(diff *diffview.Diff) int {
+	total := 0
+	for _, file := range diff.Files {
+		added, deleted := file.Stats()
+		total += added + deleted
+	}
+	return total
+}
@@ -0,0 +1,939 @@
+package evalpipeline_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/chroma"
+	"github.com/fwojciec/diffstory/evalpipeline"
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_Run_WritesJSONL(t *testing.T) {
+	t.Parallel()
+
+	diffOutput := `diff --git a/hello.go b/hello.go
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ b/hello.go
+@@ -0,0 +1,3 @@
++package main
++
++func hello() {}
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback to commit-level
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"abc1234"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
+			if hash == "abc1234" {
+				return diffOutput, nil
+			}
+			return "", errors.New("unknown hash")
+		},
+		MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
+			return "Add hello function", nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	// Output should be JSONL with one line per commit
+	output := stdout.String()
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(t, lines, 1)
+
+	// Line should contain commit hash and diff files (new lowercase JSON keys)
+	assert.Contains(t, lines[0], `"hash":"abc1234"`)
+	assert.Contains(t, lines[0], `"repo":"testrepo"`)
+	assert.Contains(t, lines[0], `"message":"Add hello function"`)
+	assert.Contains(t, lines[0], `"Files"`)
+}
+
+func TestCollector_Run_MultipleCommits(t *testing.T) {
+	t.Parallel()
+
+	diff1 := `diff --git a/a.go b/a.go
+new file mode 100644
+--- /dev/null
++++ b/a.go
+@@ -0,0 +1 @@
++package a
+`
+	diff2 := `diff --git a/b.go b/b.go
+new file mode 100644
+--- /dev/null
++++ b/b.go
+@@ -0,0 +1 @@
++package b
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback to commit-level
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"commit1", "commit2"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
+			switch hash {
+			case "commit1":
+				return diff1, nil
+			case "commit2":
+				return diff2, nil
+			}
+			return "", errors.New("unknown hash")
+		},
+		MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
+			return "Commit message for " + hash, nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"hash":"commit1"`)
+	assert.Contains(t, lines[1], `"hash":"commit2"`)
+}
+
+func TestCollector_Run_IncludesFilePaths(t *testing.T) {
+	t.Parallel()
+
+	diffOutput := `diff --git a/src/auth/login.go b/src/auth/login.go
+new file mode 100644
+--- /dev/null
++++ b/src/auth/login.go
+@@ -0,0 +1,3 @@
++package auth
++
++func Login() {}
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback to commit-level
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"abc"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return diffOutput, nil
+		},
+		MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "Add login", nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	output := stdout.String()
+	// Output should include file path in diff structure
+	assert.Contains(t, output, `"NewPath":"src/auth/login.go"`)
+}
+
+func TestCollector_Run_GitLogError(t *testing.T) {
+	t.Parallel()
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback to commit-level
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, errors.New("not a git repository")
+		},
+		ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "", nil
+		},
+		MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "", nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a git repository")
+}
+
+func TestCollector_Run_GitShowError(t *testing.T) {
+	t.Parallel()
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback to commit-level
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"abc123"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "", errors.New("commit not found")
+		},
+		MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "", nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "commit not found")
+}
+
+func TestCollector_Run_SkipsCommitsWithoutFiles(t *testing.T) {
+	t.Parallel()
+
+	// Some commits have no diff (e.g., empty commits)
+	emptyDiff := ""
+	realDiff := `diff --git a/a.go b/a.go
+new file mode 100644
+--- /dev/null
++++ b/a.go
+@@ -0,0 +1 @@
++package a
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback to commit-level
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"empty-commit", "real-commit"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
+			if hash == "empty-commit" {
+				return emptyDiff, nil
+			}
+			return realDiff, nil
+		},
+		MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
+			return "Message for " + hash, nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	// Should only have 1 line (real commit), empty commit skipped
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"hash":"real-commit"`)
+}
+
+func TestCollector_Run_GitMessageError(t *testing.T) {
+	t.Parallel()
+
+	diffOutput := `diff --git a/a.go b/a.go
+new file mode 100644
+--- /dev/null
++++ b/a.go
+@@ -0,0 +1 @@
++package a
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback to commit-level
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"abc123"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return diffOutput, nil
+		},
+		MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "", errors.New("failed to get commit message")
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get commit message")
+}
+
+func TestParseBranchFromMergeMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "standard GitHub merge",
+			message: "Merge pull request #42 from user/feature-branch",
+			want:    "feature-branch",
+		},
+		{
+			name:    "multi-line message",
+			message: "Merge pull request #42 from user/feature-branch\n\nThis PR adds a new feature.",
+			want:    "feature-branch",
+		},
+		{
+			name:    "nested branch path",
+			message: "Merge pull request #42 from user/bugfix/auth/login",
+			want:    "bugfix/auth/login",
+		},
+		{
+			name:    "non-GitHub merge format",
+			message: "Merge branch 'feature' into main",
+			want:    "",
+		},
+		{
+			name:    "empty message",
+			message: "",
+			want:    "",
+		},
+		{
+			name:    "no from clause",
+			message: "Merge pull request #42",
+			want:    "",
+		},
+		{
+			name:    "no slash in user/branch",
+			message: "Merge pull request #42 from just-branch-name",
+			want:    "just-branch-name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := evalpipeline.ParseBranchFromMergeMessage(tt.message)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCollector_Run_FallsBackToCommitLevelWithNoMergeCommits(t *testing.T) {
+	t.Parallel()
+
+	diffOutput := `diff --git a/fix.go b/fix.go
+new file mode 100644
+--- /dev/null
++++ b/fix.go
+@@ -0,0 +1,3 @@
++package main
++
++func fix() {}
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// No merge commits - triggers fallback
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return nil, nil // Empty slice means no merge commits
+		},
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"abc123"}, nil
+		},
+		ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return diffOutput, nil
+		},
+		MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+			return "Fix bug", nil
+		},
+		// PR-level methods should not be called
+		CommitsInRangeFn: func(_ context.Context, _ string, _, _ string) ([]diffview.CommitBrief, error) {
+			t.Error("CommitsInRange should not be called in fallback mode")
+			return nil, nil
+		},
+		DiffRangeFn: func(_ context.Context, _ string, _, _ string) (string, error) {
+			t.Error("DiffRange should not be called in fallback mode")
+			return "", nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+
+	output := lines[0]
+	// Should have single commit in the commits array
+	assert.Contains(t, output, `"hash":"abc123"`)
+	assert.Contains(t, output, `"message":"Fix bug"`)
+	// Branch should be empty in fallback mode
+	assert.Contains(t, output, `"branch":""`)
+}
+
+func TestCollector_Run_ExtractsPRLevelFromMergeCommits(t *testing.T) {
+	t.Parallel()
+
+	// PR diff showing combined changes from the feature branch
+	prDiff := `diff --git a/feature.go b/feature.go
+new file mode 100644
+--- /dev/null
++++ b/feature.go
+@@ -0,0 +1,5 @@
++package main
++
++func newFeature() {
++	// implementation
++}
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		// PR-level methods
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			// Return one merge commit
+			return []string{"merge123"}, nil
+		},
+		CommitsInRangeFn: func(_ context.Context, _ string, base, head string) ([]diffview.CommitBrief, error) {
+			// Commits in the PR (base^1..base^2 where base is merge commit)
+			if base == "merge123^1" && head == "merge123^2" {
+				return []diffview.CommitBrief{
+					{Hash: "feat1", Message: "Add new feature"},
+					{Hash: "feat2", Message: "Fix tests"},
+				}, nil
+			}
+			return nil, errors.New("unexpected range")
+		},
+		DiffRangeFn: func(_ context.Context, _ string, base, head string) (string, error) {
+			if base == "merge123^1" && head == "merge123^2" {
+				return prDiff, nil
+			}
+			return "", errors.New("unexpected range")
+		},
+		MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
+			if hash == "merge123" {
+				return "Merge pull request #42 from user/feature-branch", nil
+			}
+			return "", errors.New("unknown hash")
+		},
+		// Log is deprecated for PR-level; should not be called
+		LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			t.Error("Log should not be called when merge commits exist")
+			return nil, nil
+		},
+		// Show is called to get per-commit diffs
+		ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
+			// Return empty diff - per-commit diffs are optional
+			return "", nil
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	// Should output one PR-level case
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+
+	output := lines[0]
+	// Should have branch name extracted from merge message
+	assert.Contains(t, output, `"branch":"feature-branch"`)
+	// Should have all commits from the PR
+	assert.Contains(t, output, `"hash":"feat1"`)
+	assert.Contains(t, output, `"hash":"feat2"`)
+	assert.Contains(t, output, `"message":"Add new feature"`)
+	// Should have the combined diff
+	assert.Contains(t, output, `"NewPath":"feature.go"`)
+}
+
+func TestCollector_Run_PopulatesPerCommitDiffs(t *testing.T) {
+	t.Parallel()
+
+	// Combined PR diff
+	prDiff := `diff --git a/feature.go b/feature.go
+new file mode 100644
+--- /dev/null
++++ b/feature.go
+@@ -0,0 +1,3 @@
++package main
++
++func feature() {}
+`
+	// Per-commit diffs
+	commit1Diff := `diff --git a/feature.go b/feature.go
+new file mode 100644
+--- /dev/null
++++ b/feature.go
+@@ -0,0 +1 @@
++package main
+`
+	commit2Diff := `diff --git a/feature.go b/feature.go
+--- a/feature.go
++++ b/feature.go
+@@ -1 +1,3 @@
+ package main
++
++func feature() {}
+`
+
+	var stdout bytes.Buffer
+	gitRunner := &mock.GitRunner{
+		MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+			return []string{"merge123"}, nil
+		},
+		CommitsInRangeFn: func(_ context.Context, _ string, base, head string) ([]diffview.CommitBrief, error) {
+			if base == "merge123^1" && head == "merge123^2" {
+				return []diffview.CommitBrief{
+					{Hash: "commit1", Message: "Initial feature"},
+					{Hash: "commit2", Message: "Add function"},
+				}, nil
+			}
+			return nil, errors.New("unexpected range")
+		},
+		DiffRangeFn: func(_ context.Context, _ string, base, head string) (string, error) {
+			if base == "merge123^1" && head == "merge123^2" {
+				return prDiff, nil
+			}
+			return "", errors.New("unexpected range")
+		},
+		MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
+			if hash == "merge123" {
+				return "Merge pull request #42 from user/feature-branch", nil
+			}
+			return "", errors.New("unknown hash")
+		},
+		ShowFn: func(_ context.Context, _ string, hash string) (string, error) {
+			switch hash {
+			case "commit1":
+				return commit1Diff, nil
+			case "commit2":
+				return commit2Diff, nil
+			default:
+				return "", errors.New("unknown commit")
+			}
+		},
+	}
+	collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		RepoName: "testrepo",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+
+	output := lines[0]
+
+	// Verify commits have per-commit diffs populated
+	// commit1's diff should show new file with just "package main"
+	assert.Contains(t, output, `"hash":"commit1"`)
+	assert.Contains(t, output, `"hash":"commit2"`)
+
+	// The per-commit diffs should be nested within commits
+	// Each commit should have a "diff" field with its own files
+	// commit1 adds feature.go with 1 line, commit2 modifies it adding 2 lines
+	//
+	// We check for the parsed structure - if diff is populated, we'll see
+	// nested diff objects within commits array
+	var evalCase diffview.EvalCase
+	err = json.Unmarshal([]byte(output), &evalCase)
+	require.NoError(t, err, "should parse as valid JSON")
+
+	require.Len(t, evalCase.Input.Commits, 2, "should have 2 commits")
+
+	// Verify commit1 has its per-commit diff populated
+	commit1 := evalCase.Input.Commits[0]
+	require.NotNil(t, commit1.Diff, "commit1 should have Diff populated")
+	require.Len(t, commit1.Diff.Files, 1, "commit1 diff should have 1 file")
+	assert.Equal(t, "feature.go", commit1.Diff.Files[0].NewPath)
+
+	// Verify commit2 has its per-commit diff populated
+	commit2 := evalCase.Input.Commits[1]
+	require.NotNil(t, commit2.Diff, "commit2 should have Diff populated")
+	require.Len(t, commit2.Diff.Files, 1, "commit2 diff should have 1 file")
+}
+
+func TestCollector_Run_StratifiesSampledCommits(t *testing.T) {
+	t.Parallel()
+
+	diffOutput := `diff --git a/fix.go b/fix.go
+--- a/fix.go
++++ b/fix.go
+@@ -1 +1 @@
+-old
++new
+`
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC) }
+	history := []diffview.CommitMeta{
+		{Hash: "c4", Author: "ann", Time: month(3), Lines: 10},
+		{Hash: "c3", Author: "ann", Time: month(3), Lines: 20},
+		{Hash: "c2", Author: "ann", Time: month(2), Lines: 500},
+		{Hash: "c1", Author: "bob", Time: month(1), Lines: 100},
+	}
+
+	tests := []struct {
+		stratify string
+		want     []string
+	}{
+		{"", []string{"c4", "c3"}},
+		{evalpipeline.StratifyMonth, []string{"c4", "c2"}},
+		{evalpipeline.StratifyAuthor, []string{"c4", "c1"}},
+		{evalpipeline.StratifySize, []string{"c4", "c2", "c1"}},
+	}
+	for _, tt := range tests {
+		t.Run("stratify "+tt.stratify, func(t *testing.T) {
+			t.Parallel()
+
+			var queries []diffview.HistoryQuery
+			var stdout bytes.Buffer
+			gitRunner := &mock.GitRunner{
+				HistoryFn: func(_ context.Context, _ string, q diffview.HistoryQuery) ([]diffview.CommitMeta, error) {
+					queries = append(queries, q)
+					if q.Merges {
+						return nil, nil // No merges - falls back to commits
+					}
+					return history, nil
+				},
+				ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+					return diffOutput, nil
+				},
+				MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
+					return hash, nil
+				},
+			}
+			limit := len(tt.want)
+			collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+				RepoName: "testrepo",
+				Limit:    limit,
+				Branch:   "develop",
+				Since:    "2024-01-01",
+				Stratify: tt.stratify,
+			})
+
+			err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+			require.NoError(t, err)
+
+			var hashes []string
+			for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+				var c diffview.EvalCase
+				require.NoError(t, json.Unmarshal([]byte(line), &c))
+				hashes = append(hashes, c.Input.Commits[0].Hash)
+			}
+			assert.Equal(t, tt.want, hashes)
+			require.Len(t, queries, 2)
+			assert.Equal(t, diffview.HistoryQuery{Ref: "develop", Since: "2024-01-01"}, queries[1])
+		})
+	}
+}
+
+func TestCollector_Run_RejectsUnknownStratification(t *testing.T) {
+	t.Parallel()
+
+	collector := evalpipeline.NewCollector(&mock.GitRunner{}, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		Stratify: "weekday",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&bytes.Buffer{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown stratification "weekday"`)
+}
+
+func TestCollector_Run_FiltersFiles(t *testing.T) {
+	t.Parallel()
+
+	file := func(path string, lines int) string {
+		s := fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1,%d +1,%d @@\n", path, path, path, path, lines, lines)
+		for range lines {
+			s += "-old\n"
+		}
+		for range lines {
+			s += "+new\n"
+		}
+		return s
+	}
+	diffOutput := file("main.go", 1) + file("cmd/app/app_test.go", 1) + file("README.md", 10) + file("docs/guide/intro.md", 1)
+
+	tests := []struct {
+		name string
+		opts evalpipeline.CollectorOptions
+		want []string // Kept files, or nil if the commit is skipped
+	}{
+		{
+			name: "no filters",
+			opts: evalpipeline.CollectorOptions{},
+			want: []string{"main.go", "cmd/app/app_test.go", "README.md", "docs/guide/intro.md"},
+		},
+		{
+			name: "include matches names in any directory",
+			opts: evalpipeline.CollectorOptions{PathInclude: []string{"*.go"}},
+			want: []string{"main.go", "cmd/app/app_test.go"},
+		},
+		{
+			name: "double star matches any depth",
+			opts: evalpipeline.CollectorOptions{PathInclude: []string{"docs/**", "cmd/**/*.go"}},
+			want: []string{"cmd/app/app_test.go", "docs/guide/intro.md"},
+		},
+		{
+			name: "exclude wins over include",
+			opts: evalpipeline.CollectorOptions{PathInclude: []string{"*.go"}, PathExclude: []string{"*_test.go"}},
+			want: []string{"main.go"},
+		},
+		{
+			name: "language",
+			opts: evalpipeline.CollectorOptions{Language: "markdown"},
+			want: []string{"README.md", "docs/guide/intro.md"},
+		},
+		{
+			name: "line limits count only kept files",
+			opts: evalpipeline.CollectorOptions{Language: "go", MaxLines: 4},
+			want: []string{"main.go", "cmd/app/app_test.go"},
+		},
+		{
+			name: "max files counts only kept files",
+			opts: evalpipeline.CollectorOptions{PathExclude: []string{"*.md"}, MaxFiles: 2},
+			want: []string{"main.go", "cmd/app/app_test.go"},
+		},
+		{
+			name: "skips commits over max files",
+			opts: evalpipeline.CollectorOptions{MaxFiles: 3},
+		},
+		{
+			name: "skips commits with no files left",
+			opts: evalpipeline.CollectorOptions{PathInclude: []string{"*.rs"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout bytes.Buffer
+			gitRunner := &mock.GitRunner{
+				MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+					return nil, nil
+				},
+				LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+					return []string{"abc123"}, nil
+				},
+				ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+					return diffOutput, nil
+				},
+				MessageFn: func(_ context.Context, _ string, _ string) (string, error) {
+					return "Change things", nil
+				},
+			}
+			opts := tt.opts
+			opts.Languages = chroma.NewDetector()
+			collector := evalpipeline.NewCollector(gitRunner, gitdiff.NewParser(), opts)
+
+			err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+			require.NoError(t, err)
+
+			if tt.want == nil {
+				assert.Empty(t, stdout.String())
+				return
+			}
+			var c diffview.EvalCase
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &c))
+			var paths []string
+			for _, f := range c.Input.Diff.Files {
+				paths = append(paths, strings.TrimPrefix(f.NewPath, "b/"))
+			}
+			assert.Equal(t, tt.want, paths)
+		})
+	}
+}
+
+func TestCollector_Run_RequiresDetectorForLanguage(t *testing.T) {
+	t.Parallel()
+
+	collector := evalpipeline.NewCollector(&mock.GitRunner{}, gitdiff.NewParser(), evalpipeline.CollectorOptions{
+		Language: "go",
+	})
+
+	err := collector.Run(context.Background(), jsonl.NewWriter(&bytes.Buffer{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs a language detector")
+}
+
+func TestParsePullRequestNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message string
+		want    int
+		wantOK  bool
+	}{
+		{"squash merge", "Fix parser (#123)", 123, true},
+		{"multi-line message", "Fix parser (#7)\n\n* First commit\n* Second commit", 7, true},
+		{"trailing space", "Fix parser (#7) ", 7, true},
+		{"number in body only", "Fix parser\n\nSee (#7)", 0, false},
+		{"number mid-subject", "Revert (#7) change", 0, false},
+		{"plain commit", "Fix parser", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := evalpipeline.ParsePullRequestNumber(tt.message)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCollector_Run_ExtractsSquashMergedPullRequests(t *testing.T) {
+	t.Parallel()
+
+	diffOutput := `diff --git a/fix.go b/fix.go
+--- a/fix.go
++++ b/fix.go
+@@ -1 +1 @@
+-old
++new
+`
+	messages := map[string]string{
+		"c3": "Revert \"Fix parser (#12)\"",
+		"c2": "Fix parser (#12)",
+		"c1": "Direct push",
+		"c0": "Cherry-picked fix (#11)",
+	}
+	newRunner := func() *mock.GitRunner {
+		return &mock.GitRunner{
+			MergeCommitsFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+				return nil, nil
+			},
+			LogFn: func(_ context.Context, _ string, _ int) ([]string, error) {
+				return []string{"c3", "c2", "c1", "c0"}, nil
+			},
+			MessageFn: func(_ context.Context, _ string, hash string) (string, error) {
+				return messages[hash], nil
+			},
+			ShowFn: func(_ context.Context, _ string, _ string) (string, error) {
+				return diffOutput, nil
+			},
+		}
+	}
+	decode := func(t *testing.T, output string) []diffview.ClassificationInput {
+		t.Helper()
+		var inputs []diffview.ClassificationInput
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			var c diffview.EvalCase
+			require.NoError(t, json.Unmarshal([]byte(line), &c))
+			inputs = append(inputs, c.Input)
+		}
+		return inputs
+	}
+
+	t.Run("detects pull requests from commit subjects", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout bytes.Buffer
+		collector := evalpipeline.NewCollector(newRunner(), gitdiff.NewParser(), evalpipeline.CollectorOptions{
+			RepoName:     "testrepo",
+			SquashMerges: true,
+		})
+
+		err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+		require.NoError(t, err)
+
+		inputs := decode(t, stdout.String())
+		require.Len(t, inputs, 2)
+		assert.Equal(t, "pr-12", inputs[0].Branch)
+		assert.Equal(t, "c2", inputs[0].Commits[0].Hash)
+		assert.Len(t, inputs[0].Diff.Files, 1)
+		assert.Equal(t, "pr-11", inputs[1].Branch)
+	})
+
+	t.Run("confirms pull requests with the finder", func(t *testing.T) {
+		t.Parallel()
+
+		finder := &mock.PullRequestFinder{PullRequestFn: func(_ context.Context, number int) (*diffview.PullRequest, error) {
+			// #11 landed as a different commit than the cherry-pick
+			landed := map[int]string{12: "c2", 11: "other"}
+			return &diffview.PullRequest{
+				Number:      number,
+				Title:       "Fix parser",
+				Body:        "Handles empty input",
+				Branch:      "fix-parser",
+				MergeCommit: landed[number],
+			}, nil
+		}}
+		var stdout bytes.Buffer
+		collector := evalpipeline.NewCollector(newRunner(), gitdiff.NewParser(), evalpipeline.CollectorOptions{
+			RepoName:     "testrepo",
+			SquashMerges: true,
+			PullRequests: finder,
+		})
+
+		err := collector.Run(context.Background(), jsonl.NewWriter(&stdout))
+		require.NoError(t, err)
+
+		inputs := decode(t, stdout.String())
+		require.Len(t, inputs, 1)
+		assert.Equal(t, "fix-parser", inputs[0].Branch)
+		assert.Equal(t, "Fix parser", inputs[0].PRTitle)
+		assert.Equal(t, "Handles empty input", inputs[0].PRDescription)
+	})
+
+	t.Run("stops on lookup errors", func(t *testing.T) {
+		t.Parallel()
+
+		finder := &mock.PullRequestFinder{PullRequestFn: func(context.Context, int) (*diffview.PullRequest, error) {
+			return nil, errors.New("rate limited")
+		}}
+		collector := evalpipeline.NewCollector(newRunner(), gitdiff.NewParser(), evalpipeline.CollectorOptions{
+			SquashMerges: true,
+			PullRequests: finder,
+		})
+
+		err := collector.Run(context.Background(), jsonl.NewWriter(&bytes.Buffer{}))
+		assert.ErrorContains(t, err, "rate limited")
+	})
+}
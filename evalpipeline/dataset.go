@@ -0,0 +1,105 @@
+package evalpipeline
+
+import (
+	"math/rand/v2"
+	"sort"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// MergeCases concatenates datasets, keeping only the first case with each
+// ID, and returns how many duplicates were dropped.
+func MergeCases(datasets ...[]diffview.EvalCase) (merged []diffview.EvalCase, duplicates int) {
+	seen := make(map[string]bool)
+	for _, cases := range datasets {
+		for _, c := range cases {
+			id := c.CaseID()
+			if seen[id] {
+				duplicates++
+				continue
+			}
+			seen[id] = true
+			merged = append(merged, c)
+		}
+	}
+	return merged, duplicates
+}
+
+// SplitCases divides cases into a training and a test set, putting a
+// testFraction share of them, chosen at random from seed, in the test set.
+// Both sets keep the input order, and the same seed gives the same split.
+func SplitCases(cases []diffview.EvalCase, testFraction float64, seed uint64) (train, test []diffview.EvalCase) {
+	n := int(float64(len(cases))*testFraction + 0.5)
+	inTest := make(map[int]bool, n)
+	for _, i := range pick(len(cases), n, seed) {
+		inTest[i] = true
+	}
+	for i, c := range cases {
+		if inTest[i] {
+			test = append(test, c)
+		} else {
+			train = append(train, c)
+		}
+	}
+	return train, test
+}
+
+// SampleCases returns n cases chosen at random from seed, in input order,
+// or all of them if there are no more than n.
+func SampleCases(cases []diffview.EvalCase, n int, seed uint64) []diffview.EvalCase {
+	if n >= len(cases) {
+		return cases
+	}
+	sampled := make([]diffview.EvalCase, 0, n)
+	for _, i := range pick(len(cases), n, seed) {
+		sampled = append(sampled, cases[i])
+	}
+	return sampled
+}
+
+// pick returns n distinct indices below total chosen at random from seed,
+// in ascending order.
+func pick(total, n int, seed uint64) []int {
+	r := rand.New(rand.NewPCG(seed, seed))
+	indices := r.Perm(total)[:n]
+	sort.Ints(indices)
+	return indices
+}
+
+// CaseFilter selects cases by their input and classification. Zero fields
+// match everything.
+type CaseFilter struct {
+	Repo       string // Repository name
+	ChangeType string // Classified change type; unclassified cases never match
+	MinLines   int    // Minimum lines changed
+	MaxLines   int    // Maximum lines changed
+}
+
+// Match reports whether c passes the filter.
+func (f CaseFilter) Match(c diffview.EvalCase) bool {
+	if f.Repo != "" && c.Input.Repo != f.Repo {
+		return false
+	}
+	if f.ChangeType != "" && (c.Story == nil || c.Story.ChangeType != f.ChangeType) {
+		return false
+	}
+	lines := countLinesChanged(&c.Input.Diff)
+	if f.MinLines > 0 && lines < f.MinLines {
+		return false
+	}
+	if f.MaxLines > 0 && lines > f.MaxLines {
+		return false
+	}
+	return true
+}
+
+// FilterCases returns the cases that pass f, in input order.
+func FilterCases(cases []diffview.EvalCase, f CaseFilter) []diffview.EvalCase {
+	var kept []diffview.EvalCase
+	for _, c := range cases {
+		if f.Match(c) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,95 @@
+package evalpipeline_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/evalpipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func datasetCase(branch string) diffview.EvalCase {
+	return diffview.EvalCase{ID: branch, Input: diffview.ClassificationInput{Repo: "repo", Branch: branch}}
+}
+
+func caseIDs(cases []diffview.EvalCase) []string {
+	ids := make([]string, len(cases))
+	for i, c := range cases {
+		ids[i] = c.CaseID()
+	}
+	return ids
+}
+
+func TestMergeCases(t *testing.T) {
+	t.Parallel()
+
+	first := datasetCase("a")
+	first.PromptVersion = "v1"
+	later := datasetCase("a")
+	later.PromptVersion = "v2"
+
+	merged, duplicates := evalpipeline.MergeCases(
+		[]diffview.EvalCase{first, datasetCase("b")},
+		[]diffview.EvalCase{datasetCase("c"), later},
+	)
+
+	assert.Equal(t, []string{"a", "b", "c"}, caseIDs(merged))
+	assert.Equal(t, "v1", merged[0].PromptVersion)
+	assert.Equal(t, 1, duplicates)
+}
+
+func TestSplitCases(t *testing.T) {
+	t.Parallel()
+
+	var cases []diffview.EvalCase
+	for _, b := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		cases = append(cases, datasetCase(b))
+	}
+
+	train, test := evalpipeline.SplitCases(cases, 0.2, 42)
+
+	require.Len(t, test, 2)
+	require.Len(t, train, 8)
+	assert.ElementsMatch(t, caseIDs(cases), append(caseIDs(train), caseIDs(test)...))
+	assert.IsIncreasing(t, caseIDs(train), "keeps input order")
+
+	again, againTest := evalpipeline.SplitCases(cases, 0.2, 42)
+	assert.Equal(t, train, again)
+	assert.Equal(t, test, againTest)
+}
+
+func TestSampleCases(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{datasetCase("a"), datasetCase("b"), datasetCase("c"), datasetCase("d")}
+
+	sampled := evalpipeline.SampleCases(cases, 2, 7)
+	require.Len(t, sampled, 2)
+	assert.IsIncreasing(t, caseIDs(sampled))
+	assert.Equal(t, sampled, evalpipeline.SampleCases(cases, 2, 7))
+
+	assert.Equal(t, cases, evalpipeline.SampleCases(cases, 10, 7))
+}
+
+func TestFilterCases(t *testing.T) {
+	t.Parallel()
+
+	lines := func(n int) diffview.Diff {
+		hunk := diffview.Hunk{}
+		for range n {
+			hunk.Lines = append(hunk.Lines, diffview.Line{Type: diffview.LineAdded})
+		}
+		return diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go", Hunks: []diffview.Hunk{hunk}}}}
+	}
+	cases := []diffview.EvalCase{
+		{ID: "small", Input: diffview.ClassificationInput{Repo: "api", Branch: "small", Diff: lines(3)}, Story: &diffview.StoryClassification{ChangeType: "bugfix"}},
+		{ID: "large", Input: diffview.ClassificationInput{Repo: "api", Branch: "large", Diff: lines(50)}, Story: &diffview.StoryClassification{ChangeType: "feature"}},
+		{ID: "unclassified", Input: diffview.ClassificationInput{Repo: "web", Branch: "unclassified", Diff: lines(10)}},
+	}
+
+	assert.Equal(t, []string{"small", "large"}, caseIDs(evalpipeline.FilterCases(cases, evalpipeline.CaseFilter{Repo: "api"})))
+	assert.Equal(t, []string{"large"}, caseIDs(evalpipeline.FilterCases(cases, evalpipeline.CaseFilter{ChangeType: "feature"})))
+	assert.Equal(t, []string{"unclassified"}, caseIDs(evalpipeline.FilterCases(cases, evalpipeline.CaseFilter{MinLines: 5, MaxLines: 20})))
+	assert.Len(t, evalpipeline.FilterCases(cases, evalpipeline.CaseFilter{}), 3)
+}
@@ -0,0 +1,78 @@
+package evalpipeline
+
+import (
+	"path"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// selectFiles drops the files of diff that the path and language filters
+// reject, so the size limits only count the files a case keeps.
+func (c *Collector) selectFiles(diff *diffview.Diff) {
+	if len(c.opts.PathInclude) == 0 && len(c.opts.PathExclude) == 0 && c.opts.Language == "" {
+		return
+	}
+	kept := diff.Files[:0]
+	for _, file := range diff.Files {
+		if c.keepFile(file.Path()) {
+			kept = append(kept, file)
+		}
+	}
+	diff.Files = kept
+}
+
+// keepFile reports whether the file at p passes the path and language filters.
+func (c *Collector) keepFile(p string) bool {
+	if len(c.opts.PathInclude) > 0 && !matchAny(c.opts.PathInclude, p) {
+		return false
+	}
+	if matchAny(c.opts.PathExclude, p) {
+		return false
+	}
+	if c.opts.Language != "" && !strings.EqualFold(c.opts.Languages.DetectFromPath(p), c.opts.Language) {
+		return false
+	}
+	return true
+}
+
+// matchAny reports whether p matches any of patterns.
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if matchPath(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath reports whether p matches a glob pattern. As in .gitignore, a
+// pattern without a slash matches the file name in any directory, and a
+// "**" segment matches any number of directories.
+func matchPath(pattern, p string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p)) // Bad patterns match nothing
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+// matchSegments matches path segments against pattern segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segments[0]) // Bad patterns match nothing
+	return ok && matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,101 @@
+package evalpipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"golang.org/x/sync/errgroup"
+)
+
+// JudgeOptions configures a JudgeRunner.
+type JudgeOptions struct {
+	// MaxRetries is the number of grading attempts per case.
+	// If 0, DefaultMaxRetries is used.
+	MaxRetries int
+	// Workers sets the number of cases graded at once. If <= 1, runs sequentially.
+	Workers int
+	// BackoffFn returns the backoff duration for a given attempt (1-indexed).
+	// If nil, uses exponential backoff (1s, 2s, 4s...).
+	BackoffFn func(attempt int) time.Duration
+	// Warnings receives a line for each skipped case. If nil, warnings are discarded.
+	Warnings io.Writer
+}
+
+// JudgeRunner grades eval cases using an LLM judge.
+type JudgeRunner struct {
+	judge diffview.CaseJudge
+	opts  JudgeOptions
+}
+
+// NewJudgeRunner creates a JudgeRunner backed by judge.
+func NewJudgeRunner(judge diffview.CaseJudge, opts JudgeOptions) *JudgeRunner {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.BackoffFn == nil {
+		opts.BackoffFn = func(attempt int) time.Duration {
+			return time.Duration(1<<(attempt-1)) * time.Second
+		}
+	}
+	if opts.Warnings == nil {
+		opts.Warnings = io.Discard
+	}
+	return &JudgeRunner{
+		judge: judge,
+		opts:  opts,
+	}
+}
+
+// Run grades each classified case and returns the judgments in input
+// order. Unclassified cases are passed over; cases that fail after max
+// retries are skipped with a warning.
+func (r *JudgeRunner) Run(ctx context.Context, cases []diffview.EvalCase) ([]diffview.Judgment, error) {
+	judgments := make([]*diffview.Judgment, len(cases))
+	warnings := make([]string, len(cases))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(r.opts.Workers, 1))
+
+	for i := range cases {
+		evalCase := cases[i]
+		if evalCase.Story == nil {
+			continue
+		}
+
+		g.Go(func() error {
+			j, err := withRetry(gctx, r.opts.MaxRetries, r.opts.BackoffFn, func() (*diffview.Judgment, error) {
+				return r.judge.Judge(gctx, evalCase)
+			})
+			if err != nil {
+				// Stop everything on cancellation rather than skipping each case
+				if gctx.Err() != nil {
+					return gctx.Err()
+				}
+				warnings[i] = fmt.Sprintf("warning: skipping case %s after %d retries: %v\n",
+					evalCase.Input.Name(), r.opts.MaxRetries, err)
+				return nil
+			}
+			j.CaseID = evalCase.CaseID()
+			judgments[i] = j
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var judged []diffview.Judgment
+	for i, j := range judgments {
+		if warnings[i] != "" {
+			fmt.Fprint(r.opts.Warnings, warnings[i])
+		}
+		if j != nil {
+			judged = append(judged, *j)
+		}
+	}
+	return judged, nil
+}
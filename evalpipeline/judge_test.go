@@ -0,0 +1,87 @@
+package evalpipeline_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/evalpipeline"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJudgeRunner_Run(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{ChangeType: "bugfix"}
+	cases := []diffview.EvalCase{
+		{ID: "a", Input: diffview.ClassificationInput{Repo: "repo", Branch: "a"}, Story: story},
+		{ID: "unclassified", Input: diffview.ClassificationInput{Repo: "repo", Branch: "unclassified"}},
+		{ID: "c", Input: diffview.ClassificationInput{Repo: "repo", Branch: "c"}, Story: story},
+	}
+	noBackoff := func(int) time.Duration { return 0 }
+
+	t.Run("grades classified cases in input order", func(t *testing.T) {
+		t.Parallel()
+
+		judge := &mock.CaseJudge{JudgeFn: func(_ context.Context, c diffview.EvalCase) (*diffview.Judgment, error) {
+			return &diffview.Judgment{Judged: true, Pass: c.Input.Branch == "a", Judge: "grader"}, nil
+		}}
+		runner := evalpipeline.NewJudgeRunner(judge, evalpipeline.JudgeOptions{Workers: 2})
+
+		judgments, err := runner.Run(context.Background(), cases)
+		require.NoError(t, err)
+
+		require.Len(t, judgments, 2)
+		assert.Equal(t, "a", judgments[0].CaseID)
+		assert.True(t, judgments[0].Pass)
+		assert.Equal(t, "c", judgments[1].CaseID)
+		assert.False(t, judgments[1].Pass)
+		assert.Equal(t, "grader", judgments[1].Judge)
+	})
+
+	t.Run("retries and then skips a case with a warning", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		judge := &mock.CaseJudge{JudgeFn: func(_ context.Context, c diffview.EvalCase) (*diffview.Judgment, error) {
+			if c.Input.Branch == "c" {
+				calls.Add(1)
+				return nil, errors.New("rate limited")
+			}
+			return &diffview.Judgment{Judged: true, Pass: true}, nil
+		}}
+		var warnings bytes.Buffer
+		runner := evalpipeline.NewJudgeRunner(judge, evalpipeline.JudgeOptions{
+			MaxRetries: 2,
+			BackoffFn:  noBackoff,
+			Warnings:   &warnings,
+		})
+
+		judgments, err := runner.Run(context.Background(), cases)
+		require.NoError(t, err)
+
+		require.Len(t, judgments, 1)
+		assert.Equal(t, "a", judgments[0].CaseID)
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Contains(t, warnings.String(), "skipping case repo/c after 2 retries: rate limited")
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		judge := &mock.CaseJudge{JudgeFn: func(context.Context, diffview.EvalCase) (*diffview.Judgment, error) {
+			return &diffview.Judgment{}, nil
+		}}
+
+		_, err := evalpipeline.NewJudgeRunner(judge, evalpipeline.JudgeOptions{}).Run(ctx, cases)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
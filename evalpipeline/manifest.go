@@ -0,0 +1,94 @@
+package evalpipeline
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest lists the repositories to collect a combined dataset from.
+//
+//	repos:
+//	  - url: https://github.com/org/api.git
+//	    limit: 100
+//	  - path: ../web
+//	    branch: develop
+type Manifest struct {
+	Repos []ManifestRepo `yaml:"repos"`
+}
+
+// ManifestRepo is one repository in a Manifest. Fields left empty take the
+// batch's defaults.
+type ManifestRepo struct {
+	Name   string `yaml:"name"`   // Repository name in cases; defaults to the base of Path or URL
+	URL    string `yaml:"url"`    // Where to clone from when there's no checkout at Path
+	Path   string `yaml:"path"`   // Existing checkout
+	Branch string `yaml:"branch"` // Branch to collect from
+	Since  string `yaml:"since"`  // Only commits after this date
+	Until  string `yaml:"until"`  // Only commits before this date
+	Limit  int    `yaml:"limit"`  // Maximum commits to extract
+}
+
+// LoadManifest reads a manifest from the YAML file at path, resolving
+// relative checkout paths against the file's directory.
+func LoadManifest(manifestPath string) (*Manifest, error) {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := ParseManifest(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestPath, err)
+	}
+	for i, repo := range m.Repos {
+		if repo.Path != "" && !filepath.IsAbs(repo.Path) {
+			m.Repos[i].Path = filepath.Join(filepath.Dir(manifestPath), repo.Path)
+		}
+	}
+	return m, nil
+}
+
+// ParseManifest reads a manifest from YAML, filling in repository names and
+// checking each repository can be found and is named uniquely.
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for i := range m.Repos {
+		repo := &m.Repos[i]
+		if repo.URL == "" && repo.Path == "" {
+			return nil, fmt.Errorf("repo %d has neither url nor path", i+1)
+		}
+		if repo.Name == "" {
+			repo.Name = repoName(repo)
+		}
+		if names[repo.Name] {
+			return nil, fmt.Errorf("repo name %q is used twice; set name to tell them apart", repo.Name)
+		}
+		names[repo.Name] = true
+	}
+	return &m, nil
+}
+
+// repoName derives a repository name from its checkout path or clone URL.
+func repoName(repo *ManifestRepo) string {
+	if repo.Path != "" {
+		return filepath.Base(filepath.Clean(repo.Path))
+	}
+	// Clone URLs may be scp-like, as in git@github.com:org/repo.git
+	url := strings.TrimSuffix(strings.TrimRight(repo.URL, "/"), ".git")
+	url = url[strings.LastIndex(url, ":")+1:]
+	return path.Base(url)
+}
@@ -0,0 +1,73 @@
+package evalpipeline_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory/evalpipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("names repositories after their path or URL", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := evalpipeline.ParseManifest(strings.NewReader(`
+repos:
+  - url: https://github.com/org/api.git
+    limit: 100
+  - url: git@github.com:org/web.git
+    branch: develop
+  - path: /src/tools/
+  - name: cli
+    path: /src/other
+`))
+
+		require.NoError(t, err)
+		require.Len(t, m.Repos, 4)
+		assert.Equal(t, evalpipeline.ManifestRepo{Name: "api", URL: "https://github.com/org/api.git", Limit: 100}, m.Repos[0])
+		assert.Equal(t, "web", m.Repos[1].Name)
+		assert.Equal(t, "develop", m.Repos[1].Branch)
+		assert.Equal(t, "tools", m.Repos[2].Name)
+		assert.Equal(t, "cli", m.Repos[3].Name)
+	})
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"repo without location", "repos:\n  - branch: main\n", "repo 1 has neither url nor path"},
+		{"duplicate names", "repos:\n  - path: a/api\n  - path: b/api\n", `repo name "api" is used twice`},
+		{"unknown field", "repos:\n  - path: api\n    limt: 5\n", "invalid manifest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := evalpipeline.ParseManifest(strings.NewReader(tt.yaml))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadManifest_ResolvesPathsAgainstManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "repos.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("repos:\n  - path: ../api\n  - path: /abs/web\n"), 0o644))
+
+	m, err := evalpipeline.LoadManifest(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(dir), "api"), m.Repos[0].Path)
+	assert.Equal(t, "/abs/web", m.Repos[1].Path)
+}
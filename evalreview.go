@@ -1,21 +1,242 @@
 package diffview
 
-import "time"
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
 
 // EvalCase represents a case for evaluation: a diff with its LLM-generated classification.
 type EvalCase struct {
-	Input ClassificationInput  `json:"input"` // The input for classification
-	Story *StoryClassification `json:"story"` // The LLM-generated classification (nil if not yet classified)
+	ID    string               `json:"id,omitempty"` // Input.ContentID() when the case was collected or first loaded
+	Input ClassificationInput  `json:"input"`        // The input for classification
+	Story *StoryClassification `json:"story"`        // The LLM-generated classification (nil if not yet classified)
+
+	// PromptVersion is the version of the prompt Story was classified with,
+	// or empty if not recorded.
+	PromptVersion string `json:"prompt_version,omitempty"`
+
+	// Gold is a reviewer-corrected classification promoted to a reference
+	// answer, for fine-tuning and evals. Nil if none has been promoted.
+	Gold *StoryClassification `json:"gold,omitempty"`
+}
+
+// CaseID returns the case's ID: the one it was stored with, or else the
+// content ID of its input.
+func (c EvalCase) CaseID() string {
+	if c.ID != "" {
+		return c.ID
+	}
+	return c.Input.ContentID()
 }
 
-// Judgment represents a human reviewer's evaluation of an EvalCase.
+// JudgmentVersion is the version of the Judgment schema written today.
+// Version 1, unmarked in files, had no category or severity; version 2
+// added them, version 3 the gold label, version 4 the judge, version 5
+// replaced repo/branch case IDs with content IDs and version 6 added the
+// review time.
+const JudgmentVersion = 6
+
+// Judgment represents a reviewer's evaluation of an EvalCase: a human's, or
+// an LLM grader's when Judge is set.
 type Judgment struct {
-	CaseID   string    `json:"case_id"`   // Links to EvalCase.Input.CaseID() (repo/branch)
-	Index    int       `json:"index"`     // Position in input file (0-based)
-	Judged   bool      `json:"judged"`    // Whether pass/fail has been explicitly set
-	Pass     bool      `json:"pass"`      // Whether the classification is acceptable
-	Critique string    `json:"critique"`  // Explanation for failure (empty if pass)
-	JudgedAt time.Time `json:"judged_at"` // When judgment was recorded
+	Version  int              `json:"version"`            // Schema version the judgment was written with
+	CaseID   string           `json:"case_id"`            // Links to EvalCase.CaseID()
+	Index    int              `json:"index"`              // Position in input file (0-based)
+	Judged   bool             `json:"judged"`             // Whether pass/fail has been explicitly set
+	Pass     bool             `json:"pass"`               // Whether the classification is acceptable
+	Critique string           `json:"critique"`           // Explanation for failure (empty if pass)
+	Category CritiqueCategory `json:"category,omitempty"` // Kind of error the critique describes
+	Severity Severity         `json:"severity,omitempty"` // How much the error matters
+	JudgedAt time.Time        `json:"judged_at"`          // When judgment was recorded
+	Judge    string           `json:"judge,omitempty"`    // Model that made the judgment, or empty for a human
+
+	// ReviewTime is how long the case has been on screen, across sessions,
+	// while the reviewer's terminal had focus and wasn't idle. Zero if
+	// unrecorded. Written in nanoseconds.
+	ReviewTime time.Duration `json:"review_time,omitempty"`
+
+	// Gold is the classification as the reviewer corrected it, until it's
+	// promoted to the case's own gold label (see EvalCase.Gold). Nil if left
+	// uncorrected or promoted since.
+	Gold *StoryClassification `json:"gold,omitempty"`
+}
+
+// CritiqueCategory is the kind of error a critique describes.
+type CritiqueCategory string
+
+// Critique categories.
+const (
+	CategoryWrongNarrative CritiqueCategory = "wrong_narrative" // The story misdescribes the change
+	CategoryMissingHunk    CritiqueCategory = "missing_hunk"    // A hunk belongs to no section
+	CategoryBadGrouping    CritiqueCategory = "bad_grouping"    // Hunks are split or combined wrongly
+	CategoryHallucination  CritiqueCategory = "hallucination"   // The story claims what the diff doesn't show
+)
+
+// CritiqueCategories returns the categories in the order reviewers choose
+// from them.
+func CritiqueCategories() []CritiqueCategory {
+	return []CritiqueCategory{
+		CategoryWrongNarrative,
+		CategoryMissingHunk,
+		CategoryBadGrouping,
+		CategoryHallucination,
+	}
+}
+
+// Label returns the category as reviewers read it, e.g. "wrong narrative".
+func (c CritiqueCategory) Label() string {
+	return strings.ReplaceAll(string(c), "_", " ")
+}
+
+// Severity is how much a critiqued error matters.
+type Severity string
+
+// Severities, from least to most serious.
+const (
+	SeverityMinor    Severity = "minor"    // Cosmetic; the story still helps
+	SeverityMajor    Severity = "major"    // Misleads the reader in places
+	SeverityCritical Severity = "critical" // The story can't be trusted
+)
+
+// Severities returns the severities from least to most serious.
+func Severities() []Severity {
+	return []Severity{SeverityMinor, SeverityMajor, SeverityCritical}
+}
+
+// Tombstone marks an EvalCase as permanently excluded from a dataset.
+// Tombstones are appended alongside the dataset rather than rewriting it;
+// the cleaned dataset is materialized later (see evalreview gc).
+type Tombstone struct {
+	CaseID    string    `json:"case_id"`    // Links to EvalCase.CaseID()
+	DeletedAt time.Time `json:"deleted_at"` // When the case was deleted
+}
+
+// ReviewSession records the state of a dataset at the end of one review
+// session, so the dataset can later be viewed as it was then. Sessions are
+// appended to a log and never rewritten.
+type ReviewSession struct {
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   time.Time  `json:"ended_at"`
+	CaseIDs   []string   `json:"case_ids"`  // Cases in the dataset, in order, excluding deleted ones
+	Judgments []Judgment `json:"judgments"` // Every judgment as it stood when the session ended
+}
+
+// SessionStats summarizes the pace and outcome of a review session.
+type SessionStats struct {
+	Duration   time.Duration // From the start of the session to its end
+	Judged     int           // Cases a human judged pass or fail during the session
+	Passed     int           // Of those, the ones judged pass
+	MedianTime time.Duration // Median review time of the cases judged, or zero if unrecorded
+}
+
+// CasesPerHour returns the rate at which the session judged cases, or zero
+// for a session with no duration.
+func (s SessionStats) CasesPerHour() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Judged) / s.Duration.Hours()
+}
+
+// PassRate returns the fraction of the cases judged that passed, or zero if
+// none were judged.
+func (s SessionStats) PassRate() float64 {
+	if s.Judged == 0 {
+		return 0
+	}
+	return float64(s.Passed) / float64(s.Judged)
+}
+
+// Stats summarizes the session from the judgments a human made during it,
+// leaving out those from earlier sessions and LLM graders.
+func (s ReviewSession) Stats() SessionStats {
+	stats := SessionStats{Duration: s.EndedAt.Sub(s.StartedAt)}
+	var times []time.Duration
+	for _, j := range s.Judgments {
+		if !j.Judged || j.Judge != "" || j.JudgedAt.Before(s.StartedAt) || j.JudgedAt.After(s.EndedAt) {
+			continue
+		}
+		stats.Judged++
+		if j.Pass {
+			stats.Passed++
+		}
+		if j.ReviewTime > 0 {
+			times = append(times, j.ReviewTime)
+		}
+	}
+	if len(times) > 0 {
+		sort.Slice(times, func(i, k int) bool { return times[i] < times[k] })
+		mid := len(times) / 2
+		stats.MedianTime = times[mid]
+		if len(times)%2 == 0 {
+			stats.MedianTime = (times[mid-1] + times[mid]) / 2
+		}
+	}
+	return stats
+}
+
+// Snapshot returns the cases the session saw, in the order it saw them, taken
+// from cases by ID. Cases since removed from the dataset, such as by gc, are
+// left out and counted in missing.
+func (s ReviewSession) Snapshot(cases []EvalCase) (snapshot []EvalCase, missing int) {
+	byID := make(map[string]EvalCase, len(cases))
+	for _, c := range cases {
+		byID[c.CaseID()] = c
+	}
+	snapshot = make([]EvalCase, 0, len(s.CaseIDs))
+	for _, id := range s.CaseIDs {
+		c, ok := byID[id]
+		if !ok {
+			missing++
+			continue
+		}
+		snapshot = append(snapshot, c)
+	}
+	return snapshot, missing
+}
+
+// LegacyCaseIDs maps the repo/branch names that identified cases before
+// content IDs to the cases' IDs, for reading judgments, tombstones and
+// sessions saved under names. Cases sharing a name shared their judgments
+// too, so a shared name maps to the first case with it.
+type LegacyCaseIDs map[string]string
+
+// NewLegacyCaseIDs returns the legacy ID mapping for cases.
+func NewLegacyCaseIDs(cases []EvalCase) LegacyCaseIDs {
+	ids := make(LegacyCaseIDs, len(cases))
+	for _, c := range cases {
+		if _, ok := ids[c.Input.Name()]; !ok {
+			ids[c.Input.Name()] = c.CaseID()
+		}
+	}
+	return ids
+}
+
+// Forward returns the current ID for id, or id itself if it isn't the name
+// of one of the cases.
+func (l LegacyCaseIDs) Forward(id string) string {
+	if current, ok := l[id]; ok {
+		return current
+	}
+	return id
+}
+
+// DuplicateCaseIDs returns the IDs shared by more than one of cases, in the
+// order they first appear.
+func DuplicateCaseIDs(cases []EvalCase) []string {
+	seen := make(map[string]int, len(cases))
+	var duplicates []string
+	for _, c := range cases {
+		id := c.CaseID()
+		seen[id]++
+		if seen[id] == 2 {
+			duplicates = append(duplicates, id)
+		}
+	}
+	return duplicates
 }
 
 // EvalCaseLoader loads evaluation cases from a source.
@@ -23,12 +244,126 @@ type EvalCaseLoader interface {
 	Load(path string) ([]EvalCase, error)
 }
 
+// MergeAutoJudgments returns existing with auto, judgments made by an LLM
+// grader, added. An automatic judgment replaces an earlier automatic one for
+// the same case, but never a human's. The result is ordered by index.
+func MergeAutoJudgments(existing, auto []Judgment) []Judgment {
+	byCase := make(map[string]int, len(existing))
+	merged := append([]Judgment(nil), existing...)
+	for i, j := range merged {
+		byCase[j.CaseID] = i
+	}
+	for _, j := range auto {
+		i, ok := byCase[j.CaseID]
+		switch {
+		case !ok:
+			byCase[j.CaseID] = len(merged)
+			merged = append(merged, j)
+		case merged[i].Judge != "":
+			merged[i] = j
+		}
+	}
+	sort.SliceStable(merged, func(i, k int) bool { return merged[i].Index < merged[k].Index })
+	return merged
+}
+
+// CaseJudge grades a case's classification in place of a human reviewer.
+type CaseJudge interface {
+	Judge(ctx context.Context, c EvalCase) (*Judgment, error)
+}
+
 // JudgmentStore persists and retrieves judgments.
 type JudgmentStore interface {
 	Load(path string) ([]Judgment, error)
 	Save(path string, judgments []Judgment) error
 }
 
+// EvalCaseWriter is a sink for eval cases produced by a pipeline stage.
+type EvalCaseWriter interface {
+	Write(c EvalCase) error
+}
+
+// TombstoneStore records and retrieves case deletions.
+type TombstoneStore interface {
+	Load(path string) ([]Tombstone, error)
+	Append(path string, t Tombstone) error
+}
+
+// ReviewSessionStore records review sessions and retrieves them in the
+// order they were recorded.
+type ReviewSessionStore interface {
+	Load(path string) ([]ReviewSession, error)
+	Append(path string, s ReviewSession) error
+}
+
+// ExcludeTombstoned returns the cases whose IDs have not been tombstoned,
+// preserving order.
+func ExcludeTombstoned(cases []EvalCase, tombstones []Tombstone) []EvalCase {
+	if len(tombstones) == 0 {
+		return cases
+	}
+	deleted := make(map[string]bool, len(tombstones))
+	for _, t := range tombstones {
+		deleted[t.CaseID] = true
+	}
+	kept := make([]EvalCase, 0, len(cases))
+	for _, c := range cases {
+		if !deleted[c.CaseID()] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// ApplyGold returns cases with Gold set from promoted, a log of cases saved
+// as they were promoted or any dataset with gold labels. The latest gold
+// label for a case wins; promoted cases without one are ignored.
+func ApplyGold(cases []EvalCase, promoted []EvalCase) []EvalCase {
+	if len(promoted) == 0 {
+		return cases
+	}
+	gold := make(map[string]*StoryClassification, len(promoted))
+	for _, p := range promoted {
+		if p.Gold != nil {
+			gold[p.CaseID()] = p.Gold
+		}
+	}
+	applied := make([]EvalCase, len(cases))
+	for i, c := range cases {
+		if g, ok := gold[c.CaseID()]; ok {
+			c.Gold = g
+		}
+		applied[i] = c
+	}
+	return applied
+}
+
+// CasePair is one case as classified in two datasets, A and B.
+type CasePair struct {
+	A, B EvalCase
+}
+
+// AlignChanged pairs the cases of a and b by ID and returns those
+// classified in both whose classifications differ, in a's order. shared
+// counts the cases classified in both.
+func AlignChanged(a, b []EvalCase) (changed []CasePair, shared int) {
+	byID := make(map[string]EvalCase, len(b))
+	for _, c := range b {
+		byID[c.CaseID()] = c
+	}
+	for _, ca := range a {
+		cb, ok := byID[ca.CaseID()]
+		if !ok || ca.Story == nil || cb.Story == nil {
+			continue
+		}
+		shared++
+		if !reflect.DeepEqual(ca.Story, cb.Story) {
+			changed = append(changed, CasePair{A: ca, B: cb})
+		}
+	}
+	return changed, shared
+}
+
 // Clipboard provides copy-to-clipboard functionality.
 type Clipboard interface {
 	Copy(content string) error
@@ -0,0 +1,194 @@
+package diffview_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcludeTombstoned(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{ID: "a", Input: diffview.ClassificationInput{Repo: "repo", Branch: "a"}},
+		{ID: "b", Input: diffview.ClassificationInput{Repo: "repo", Branch: "b"}},
+		{ID: "c", Input: diffview.ClassificationInput{Repo: "repo", Branch: "c"}},
+	}
+
+	t.Run("removes tombstoned cases preserving order", func(t *testing.T) {
+		t.Parallel()
+
+		kept := diffview.ExcludeTombstoned(cases, []diffview.Tombstone{{CaseID: "b"}})
+
+		assert.Len(t, kept, 2)
+		assert.Equal(t, "a", kept[0].CaseID())
+		assert.Equal(t, "c", kept[1].CaseID())
+	})
+
+	t.Run("returns all cases without tombstones", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, cases, diffview.ExcludeTombstoned(cases, nil))
+	})
+}
+
+func TestApplyGold(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{ID: "a", Input: diffview.ClassificationInput{Repo: "repo", Branch: "a"}},
+		{ID: "b", Input: diffview.ClassificationInput{Repo: "repo", Branch: "b"}},
+	}
+	first := &diffview.StoryClassification{Summary: "first"}
+	latest := &diffview.StoryClassification{Summary: "latest"}
+
+	applied := diffview.ApplyGold(cases, []diffview.EvalCase{
+		{ID: "b", Input: diffview.ClassificationInput{Repo: "repo", Branch: "b"}, Gold: first},
+		{ID: "gone", Input: diffview.ClassificationInput{Repo: "repo", Branch: "gone"}, Gold: first},
+		{ID: "b", Input: diffview.ClassificationInput{Repo: "repo", Branch: "b"}, Gold: latest},
+		{ID: "b", Input: diffview.ClassificationInput{Repo: "repo", Branch: "b"}},
+	})
+
+	assert.Len(t, applied, 2)
+	assert.Nil(t, applied[0].Gold)
+	assert.Equal(t, latest, applied[1].Gold)
+	assert.Nil(t, cases[1].Gold, "input cases are left unchanged")
+}
+
+func TestMergeAutoJudgments(t *testing.T) {
+	t.Parallel()
+
+	existing := []diffview.Judgment{
+		{CaseID: "human", Index: 2, Judged: true, Pass: true},
+		{CaseID: "auto", Index: 0, Judged: true, Pass: true, Judge: "old-model"},
+	}
+	merged := diffview.MergeAutoJudgments(existing, []diffview.Judgment{
+		{CaseID: "human", Index: 2, Judged: true, Judge: "grader"},
+		{CaseID: "auto", Index: 0, Judged: true, Judge: "grader"},
+		{CaseID: "new", Index: 1, Judged: true, Judge: "grader"},
+	})
+
+	assert.Equal(t, []diffview.Judgment{
+		{CaseID: "auto", Index: 0, Judged: true, Judge: "grader"},
+		{CaseID: "new", Index: 1, Judged: true, Judge: "grader"},
+		{CaseID: "human", Index: 2, Judged: true, Pass: true},
+	}, merged)
+	assert.Equal(t, "old-model", existing[1].Judge, "existing judgments are left unchanged")
+}
+
+func TestAlignChanged(t *testing.T) {
+	t.Parallel()
+
+	input := func(branch string) diffview.ClassificationInput {
+		return diffview.ClassificationInput{Repo: "repo", Branch: branch}
+	}
+	story := func(summary string) *diffview.StoryClassification {
+		return &diffview.StoryClassification{Summary: summary}
+	}
+	a := []diffview.EvalCase{
+		{ID: "same", Input: input("same"), Story: story("same")},
+		{ID: "changed", Input: input("changed"), Story: story("before")},
+		{ID: "only-a", Input: input("only-a"), Story: story("a")},
+		{ID: "unclassified-b", Input: input("unclassified-b"), Story: story("a")},
+	}
+	b := []diffview.EvalCase{
+		{ID: "unclassified-b", Input: input("unclassified-b")},
+		{ID: "changed", Input: input("changed"), Story: story("after"), PromptVersion: "v2"},
+		{ID: "same", Input: input("same"), Story: story("same")},
+	}
+
+	changed, shared := diffview.AlignChanged(a, b)
+
+	assert.Equal(t, 2, shared)
+	assert.Equal(t, []diffview.CasePair{{A: a[1], B: b[1]}}, changed)
+}
+
+func TestReviewSession_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{
+		{ID: "a", Input: diffview.ClassificationInput{Repo: "repo", Branch: "a"}},
+		{ID: "b", Input: diffview.ClassificationInput{Repo: "repo", Branch: "b"}},
+		{ID: "c", Input: diffview.ClassificationInput{Repo: "repo", Branch: "c"}},
+	}
+	session := diffview.ReviewSession{CaseIDs: []string{"c", "gone", "a"}}
+
+	snapshot, missing := session.Snapshot(cases)
+
+	assert.Equal(t, 1, missing)
+	assert.Len(t, snapshot, 2)
+	assert.Equal(t, "c", snapshot[0].CaseID())
+	assert.Equal(t, "a", snapshot[1].CaseID())
+}
+
+func TestReviewSession_Stats(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	during := start.Add(10 * time.Minute)
+	session := diffview.ReviewSession{
+		StartedAt: start,
+		EndedAt:   start.Add(30 * time.Minute),
+		Judgments: []diffview.Judgment{
+			{CaseID: "a", Judged: true, Pass: true, JudgedAt: during, ReviewTime: 20 * time.Second},
+			{CaseID: "b", Judged: true, Pass: false, JudgedAt: during, ReviewTime: 40 * time.Second},
+			{CaseID: "c", Judged: true, Pass: true, JudgedAt: during, ReviewTime: 90 * time.Second},
+			{CaseID: "d", Judged: true, Pass: true, JudgedAt: during},
+			{CaseID: "earlier", Judged: true, Pass: true, JudgedAt: start.Add(-time.Hour), ReviewTime: time.Hour},
+			{CaseID: "graded", Judged: true, Pass: true, JudgedAt: during, Judge: "claude"},
+			{CaseID: "critique", Critique: "unjudged", JudgedAt: during},
+		},
+	}
+
+	stats := session.Stats()
+
+	assert.Equal(t, 4, stats.Judged)
+	assert.Equal(t, 3, stats.Passed)
+	assert.Equal(t, 40*time.Second, stats.MedianTime, "cases without a review time are left out")
+	assert.InDelta(t, 8.0, stats.CasesPerHour(), 1e-9)
+	assert.InDelta(t, 0.75, stats.PassRate(), 1e-9)
+	assert.Zero(t, diffview.ReviewSession{}.Stats().PassRate())
+}
+
+func TestCritiqueCategory_Label(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "wrong narrative", diffview.CategoryWrongNarrative.Label())
+	assert.Equal(t, "hallucination", diffview.CategoryHallucination.Label())
+}
+
+func TestEvalCase_CaseID(t *testing.T) {
+	t.Parallel()
+
+	input := diffview.ClassificationInput{Repo: "repo", Commits: []diffview.CommitBrief{{Hash: "abc123"}}}
+
+	assert.Equal(t, input.ContentID(), diffview.EvalCase{Input: input}.CaseID())
+	assert.Equal(t, "stored", diffview.EvalCase{ID: "stored", Input: input}.CaseID())
+}
+
+func TestLegacyCaseIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := diffview.NewLegacyCaseIDs([]diffview.EvalCase{
+		{ID: "first", Input: diffview.ClassificationInput{Repo: "repo", Branch: "fix"}},
+		{ID: "second", Input: diffview.ClassificationInput{Repo: "repo", Branch: "feature"}},
+		{ID: "reused", Input: diffview.ClassificationInput{Repo: "repo", Branch: "fix"}},
+	})
+
+	assert.Equal(t, "first", ids.Forward("repo/fix"), "a shared name maps to its first case")
+	assert.Equal(t, "second", ids.Forward("repo/feature"))
+	assert.Equal(t, "repo/gone", ids.Forward("repo/gone"))
+	assert.Equal(t, "second", ids.Forward("second"))
+	assert.Equal(t, "repo/fix", diffview.LegacyCaseIDs(nil).Forward("repo/fix"))
+}
+
+func TestDuplicateCaseIDs(t *testing.T) {
+	t.Parallel()
+
+	cases := []diffview.EvalCase{{ID: "a"}, {ID: "b"}, {ID: "a"}, {ID: "c"}, {ID: "a"}, {ID: "c"}}
+
+	assert.Equal(t, []string{"a", "c"}, diffview.DuplicateCaseIDs(cases))
+	assert.Empty(t, diffview.DuplicateCaseIDs(cases[:2]))
+}
@@ -0,0 +1,60 @@
+package diffview
+
+import (
+	"path"
+	"strings"
+)
+
+// GroupBy is how a view groups a diff's files above the file level.
+type GroupBy int
+
+const (
+	GroupNone     GroupBy = iota // No groups
+	GroupPackage                 // By directory, which for Go is the package
+	GroupTopLevel                // By the first element of the path
+)
+
+// FileGroup is a run of consecutive files of a diff in one directory or
+// package, with their line counts combined.
+type FileGroup struct {
+	Name      string // Directory, or "." for the repository's root
+	FirstFile int    // Index in Diff.Files of the group's first file
+	Files     int    // Files in the group
+	Added     int
+	Deleted   int
+}
+
+// GroupFiles splits diff's files into groups by their directory. Files are
+// grouped as they run, so a directory the diff comes back to opens a new
+// group. There are no groups for a diff of several commits, which its
+// commits group already, or one whose files all fall in a single group.
+func GroupFiles(diff *Diff, by GroupBy) []FileGroup {
+	if diff == nil || by == GroupNone || len(diff.Commits) > 0 {
+		return nil
+	}
+	var groups []FileGroup
+	for i, file := range diff.Files {
+		name := groupName(file.Path(), by)
+		if len(groups) == 0 || groups[len(groups)-1].Name != name {
+			groups = append(groups, FileGroup{Name: name, FirstFile: i})
+		}
+		g := &groups[len(groups)-1]
+		added, deleted := file.Stats()
+		g.Files++
+		g.Added += added
+		g.Deleted += deleted
+	}
+	if len(groups) < 2 {
+		return nil
+	}
+	return groups
+}
+
+// groupName returns the name of the group the file at p falls in.
+func groupName(p string, by GroupBy) string {
+	dir := path.Dir(p)
+	if by == GroupTopLevel {
+		dir, _, _ = strings.Cut(dir, "/")
+	}
+	return dir
+}
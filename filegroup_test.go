@@ -0,0 +1,64 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupFiles(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{NewPath: "b/pkg/server/server.go", Hunks: []diffview.Hunk{{Lines: []diffview.Line{
+			{Type: diffview.LineAdded}, {Type: diffview.LineAdded}, {Type: diffview.LineDeleted},
+		}}}},
+		{NewPath: "b/pkg/server/routes.go", Hunks: []diffview.Hunk{{Lines: []diffview.Line{
+			{Type: diffview.LineAdded}, {Type: diffview.LineContext},
+		}}}},
+		{OldPath: "a/pkg/client/client.go", Operation: diffview.FileDeleted, Hunks: []diffview.Hunk{{Lines: []diffview.Line{
+			{Type: diffview.LineDeleted},
+		}}}},
+		{NewPath: "b/README.md"},
+	}}
+
+	t.Run("groups by package", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []diffview.FileGroup{
+			{Name: "pkg/server", FirstFile: 0, Files: 2, Added: 3, Deleted: 1},
+			{Name: "pkg/client", FirstFile: 2, Files: 1, Deleted: 1},
+			{Name: ".", FirstFile: 3, Files: 1},
+		}, diffview.GroupFiles(diff, diffview.GroupPackage))
+	})
+
+	t.Run("groups by top-level directory", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []diffview.FileGroup{
+			{Name: "pkg", FirstFile: 0, Files: 3, Added: 3, Deleted: 2},
+			{Name: ".", FirstFile: 3, Files: 1},
+		}, diffview.GroupFiles(diff, diffview.GroupTopLevel))
+	})
+
+	t.Run("has no groups when ungrouped", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, diffview.GroupFiles(diff, diffview.GroupNone))
+	})
+
+	t.Run("has no groups for a single directory", func(t *testing.T) {
+		t.Parallel()
+
+		single := &diffview.Diff{Files: diff.Files[:2]}
+		assert.Nil(t, diffview.GroupFiles(single, diffview.GroupPackage))
+	})
+
+	t.Run("leaves grouping to commits", func(t *testing.T) {
+		t.Parallel()
+
+		log := &diffview.Diff{Files: diff.Files, Commits: []diffview.Commit{{FirstFile: 0}}}
+		assert.Nil(t, diffview.GroupFiles(log, diffview.GroupPackage))
+	})
+}
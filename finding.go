@@ -0,0 +1,102 @@
+package diffview
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindingLevel is how much a finding matters to a CI check.
+type FindingLevel string
+
+// Finding levels, from least to most serious.
+const (
+	FindingNotice  FindingLevel = "notice"
+	FindingWarning FindingLevel = "warning"
+	FindingError   FindingLevel = "error"
+)
+
+// Finding is something a CI check reports on a change, such as an
+// Annotator's note or a risk the classifier saw, for output formats like
+// GitHub Actions workflow commands or SARIF.
+type Finding struct {
+	Rule      string       // What found it, such as "vet", "todo" or "risk"
+	Level     FindingLevel // How much it matters
+	Title     string       // Short heading, or empty
+	Message   string       // What was found, possibly over several lines
+	Path      string       // File it concerns, or empty for the change as a whole
+	StartLine int          // First line in the new version, or 0 for the whole file
+	EndLine   int          // Last line, the same as StartLine for a single line
+}
+
+// AnnotationFinding returns annotation a of diff as a finding at level. A
+// hunk annotation spans the hunk's lines in the new version.
+func AnnotationFinding(diff *Diff, a Annotation, level FindingLevel) Finding {
+	f := Finding{
+		Rule:    a.Source,
+		Level:   level,
+		Message: a.Text,
+		Path:    a.Path,
+	}
+	if f.Rule == "" {
+		f.Rule = "review"
+	}
+	switch {
+	case a.Line > 0:
+		f.StartLine, f.EndLine = a.Line, a.Line
+	case a.Hunk > 0:
+		if hunk, ok := annotatedHunk(diff, a); ok && hunk.NewCount > 0 {
+			f.StartLine, f.EndLine = hunk.NewStart, hunk.NewStart+hunk.NewCount-1
+		}
+	}
+	return f
+}
+
+// annotatedHunk returns the hunk a is attached to, if diff has it.
+func annotatedHunk(diff *Diff, a Annotation) (Hunk, bool) {
+	if diff == nil {
+		return Hunk{}, false
+	}
+	for _, file := range diff.Files {
+		if file.NewPath == a.Path || (file.NewPath == "" && file.OldPath == a.Path) {
+			if a.Hunk <= len(file.Hunks) {
+				return file.Hunks[a.Hunk-1], true
+			}
+			return Hunk{}, false
+		}
+	}
+	return Hunk{}, false
+}
+
+// RiskFinding returns the classifier's risk assessment as a finding on the
+// change as a whole: an error for high risk, a warning for medium and a
+// notice otherwise, listing what breaks, how to migrate and the APIs
+// affected.
+func RiskFinding(risk *Risk) Finding {
+	level := FindingNotice
+	switch risk.Level {
+	case "high":
+		level = FindingError
+	case "medium":
+		level = FindingWarning
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Risk: %s", risk.Level)
+	writeList := func(heading string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n%s:", heading)
+		for _, item := range items {
+			fmt.Fprintf(&sb, "\n- %s", item)
+		}
+	}
+	writeList("Breaking", risk.Breaking)
+	writeList("Migration", risk.Migration)
+	writeList("Affected APIs", risk.AffectedAPIs)
+	return Finding{
+		Rule:    "risk",
+		Level:   level,
+		Title:   fmt.Sprintf("%s risk", risk.Level),
+		Message: sb.String(),
+	}
+}
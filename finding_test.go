@@ -0,0 +1,90 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotationFinding(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{OldPath: "api.go", NewPath: "api.go", Hunks: []diffview.Hunk{
+			{NewStart: 1, NewCount: 3},
+			{NewStart: 20, NewCount: 5},
+		}},
+		{OldPath: "gone.go", Hunks: []diffview.Hunk{{OldStart: 1, OldCount: 2}}},
+	}}
+
+	tests := []struct {
+		name       string
+		annotation diffview.Annotation
+		want       diffview.Finding
+	}{
+		{
+			"line",
+			diffview.Annotation{Path: "api.go", Hunk: 2, Line: 22, Text: "unreachable code", Source: "vet"},
+			diffview.Finding{Rule: "vet", Level: diffview.FindingWarning, Message: "unreachable code", Path: "api.go", StartLine: 22, EndLine: 22},
+		},
+		{
+			"hunk spans its new lines",
+			diffview.Annotation{Path: "api.go", Hunk: 2, Text: "check this"},
+			diffview.Finding{Rule: "review", Level: diffview.FindingWarning, Message: "check this", Path: "api.go", StartLine: 20, EndLine: 24},
+		},
+		{
+			"file",
+			diffview.Annotation{Path: "api.go", Text: "40% covered", Source: "coverage"},
+			diffview.Finding{Rule: "coverage", Level: diffview.FindingWarning, Message: "40% covered", Path: "api.go"},
+		},
+		{
+			"hunk of a deleted file has no new lines",
+			diffview.Annotation{Path: "gone.go", Hunk: 1, Text: "why?"},
+			diffview.Finding{Rule: "review", Level: diffview.FindingWarning, Message: "why?", Path: "gone.go"},
+		},
+		{
+			"hunk missing from the diff",
+			diffview.Annotation{Path: "api.go", Hunk: 9, Text: "stale"},
+			diffview.Finding{Rule: "review", Level: diffview.FindingWarning, Message: "stale", Path: "api.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, diffview.AnnotationFinding(diff, tt.annotation, diffview.FindingWarning))
+		})
+	}
+}
+
+func TestRiskFinding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists what breaks", func(t *testing.T) {
+		t.Parallel()
+
+		f := diffview.RiskFinding(&diffview.Risk{
+			Level:        "high",
+			Breaking:     []string{"Parse returns an error for empty input"},
+			Migration:    []string{"Check for ErrEmpty"},
+			AffectedAPIs: []string{"Parse"},
+		})
+
+		assert.Equal(t, diffview.Finding{
+			Rule:  "risk",
+			Level: diffview.FindingError,
+			Title: "high risk",
+			Message: "Risk: high\nBreaking:\n- Parse returns an error for empty input\n" +
+				"Migration:\n- Check for ErrEmpty\nAffected APIs:\n- Parse",
+		}, f)
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, diffview.FindingWarning, diffview.RiskFinding(&diffview.Risk{Level: "medium"}).Level)
+		assert.Equal(t, diffview.FindingNotice, diffview.RiskFinding(&diffview.Risk{Level: "low"}).Level)
+		assert.Equal(t, "Risk: low", diffview.RiskFinding(&diffview.Risk{Level: "low"}).Message)
+	})
+}
@@ -57,12 +57,16 @@ func (f *DefaultFormatter) Format(input ClassificationInput) string {
 	for _, file := range input.Diff.Files {
 		// File header
 		sb.WriteString(fmt.Sprintf("=== FILE: %s (%s) ===\n\n",
-			filePath(file), operationName(file.Operation)))
+			file.Path(), operationName(file.Operation)))
 
 		// Hunks
 		for _, hunk := range file.Hunks {
-			sb.WriteString(fmt.Sprintf("--- HUNK H%d (@@ -%d,%d +%d,%d @@) ---\n",
-				hunkNum, hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount))
+			section := ""
+			if hunk.Section != "" {
+				section = " " + hunk.Section
+			}
+			sb.WriteString(fmt.Sprintf("--- HUNK H%d (@@ -%d,%d +%d,%d @@%s) ---\n",
+				hunkNum, hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount, section))
 			for _, line := range hunk.Lines {
 				prefix := linePrefix(line.Type)
 				sb.WriteString(prefix)
@@ -77,14 +81,66 @@ func (f *DefaultFormatter) Format(input ClassificationInput) string {
 	}
 
 	sb.WriteString("</diff>")
+	formatGroups(&sb, input.Groups, input.Diff)
+	formatAPIChanges(&sb, input.APIChanges)
 	return sb.String()
 }
 
-func filePath(file FileDiff) string {
-	if file.NewPath != "" {
-		return file.NewPath
+// formatAPIChanges writes the exported API changes after the diff, with the
+// signatures before and after, or nothing if there are none.
+func formatAPIChanges(sb *strings.Builder, changes []APIChange) {
+	if len(changes) == 0 {
+		return
+	}
+	sb.WriteString("\n\n<api-changes>\n")
+	sb.WriteString("Exported Go API the diff adds, removes or changes:\n")
+	for _, c := range changes {
+		switch c.Kind {
+		case "added":
+			fmt.Fprintf(sb, "- added %s: %s\n", c.Symbol, c.New)
+		case "removed":
+			fmt.Fprintf(sb, "- removed %s: %s\n", c.Symbol, c.Old)
+		default:
+			fmt.Fprintf(sb, "- changed %s: %s -> %s\n", c.Symbol, c.Old, c.New)
+		}
+	}
+	sb.WriteString("</api-changes>")
+}
+
+// formatGroups writes the hunk groups as hints after the diff, naming hunks
+// by their IDs there. Groups with fewer than two hunks in the diff are left
+// out, and with them the section if none are left.
+func formatGroups(sb *strings.Builder, groups []HunkGroup, diff Diff) {
+	ids := make(map[string][]int)
+	n := 1
+	for _, file := range diff.Files {
+		for range file.Hunks {
+			ids[file.Path()] = append(ids[file.Path()], n)
+			n++
+		}
+	}
+
+	var hints []string
+	for _, g := range groups {
+		var refs []string
+		for _, h := range g.Hunks {
+			if h.HunkIndex >= 0 && h.HunkIndex < len(ids[h.File]) {
+				refs = append(refs, fmt.Sprintf("H%d", ids[h.File][h.HunkIndex]))
+			}
+		}
+		if len(refs) > 1 {
+			hints = append(hints, fmt.Sprintf("- %s: %s\n", g.Symbol, strings.Join(refs, ", ")))
+		}
+	}
+	if len(hints) == 0 {
+		return
+	}
+	sb.WriteString("\n\n<related-hunks>\n")
+	sb.WriteString("Hunks that touch the same symbol, such as a function and its callers:\n")
+	for _, hint := range hints {
+		sb.WriteString(hint)
 	}
-	return file.OldPath
+	sb.WriteString("</related-hunks>")
 }
 
 func operationName(op FileOp) string {
@@ -131,7 +187,7 @@ func formatDiffSummary(sb *strings.Builder, diff *Diff) {
 	for _, file := range diff.Files {
 		adds, dels := file.Stats()
 		fmt.Fprintf(sb, "  %s (%s): +%d/-%d\n",
-			filePath(file), operationName(file.Operation), adds, dels)
+			file.Path(), operationName(file.Operation), adds, dels)
 	}
 	sb.WriteString("\n")
 }
@@ -65,6 +65,82 @@ func TestDefaultFormatter_Format(t *testing.T) {
 	assert.Contains(t, result, "+    if a.isExpired(token) {")
 }
 
+func TestDefaultFormatter_Format_HunkSection(t *testing.T) {
+	t.Parallel()
+
+	input := diffview.ClassificationInput{
+		Repo: "testrepo",
+		Diff: diffview.Diff{
+			Files: []diffview.FileDiff{
+				{
+					NewPath:   "server.go",
+					Operation: diffview.FileModified,
+					Hunks: []diffview.Hunk{
+						{OldStart: 10, OldCount: 1, NewStart: 10, NewCount: 2, Section: "func (s *Server) Start"},
+					},
+				},
+			},
+		},
+	}
+
+	result := (&diffview.DefaultFormatter{}).Format(input)
+
+	assert.Contains(t, result, "--- HUNK H1 (@@ -10,1 +10,2 @@ func (s *Server) Start) ---")
+}
+
+func TestDefaultFormatter_Format_RelatedHunks(t *testing.T) {
+	t.Parallel()
+
+	twoHunks := []diffview.Hunk{{OldStart: 1, NewStart: 1}, {OldStart: 20, NewStart: 20}}
+	input := diffview.ClassificationInput{
+		Repo: "testrepo",
+		Diff: diffview.Diff{Files: []diffview.FileDiff{
+			{NewPath: "server.go", Hunks: twoHunks},
+			{NewPath: "main.go", Hunks: twoHunks},
+		}},
+		Groups: []diffview.HunkGroup{
+			{Symbol: "Start", Hunks: []diffview.HunkRef{{File: "server.go", HunkIndex: 1}, {File: "main.go", HunkIndex: 0}}},
+			{Symbol: "gone", Hunks: []diffview.HunkRef{{File: "server.go", HunkIndex: 0}, {File: "old.go", HunkIndex: 0}}},
+		},
+	}
+
+	result := (&diffview.DefaultFormatter{}).Format(input)
+
+	assert.True(t, strings.HasSuffix(result, "</diff>\n\n<related-hunks>\n"+
+		"Hunks that touch the same symbol, such as a function and its callers:\n"+
+		"- Start: H2, H3\n"+
+		"</related-hunks>"), result)
+
+	input.Groups = input.Groups[1:]
+	assert.NotContains(t, (&diffview.DefaultFormatter{}).Format(input), "<related-hunks>", "groups with one hunk in the diff are left out")
+}
+
+func TestDefaultFormatter_Format_APIChanges(t *testing.T) {
+	t.Parallel()
+
+	input := diffview.ClassificationInput{
+		Repo: "testrepo",
+		Diff: diffview.Diff{Files: []diffview.FileDiff{{NewPath: "server.go"}}},
+		APIChanges: []diffview.APIChange{
+			{Symbol: "server.New", Kind: "added", New: "func() *Server"},
+			{Symbol: "server.Server.Start", Kind: "changed", Old: "*Server func() error", New: "*Server func(force bool) error"},
+			{Symbol: "server.Server.Stop", Kind: "removed", Old: "*Server func()"},
+		},
+	}
+
+	result := (&diffview.DefaultFormatter{}).Format(input)
+
+	assert.True(t, strings.HasSuffix(result, "</diff>\n\n<api-changes>\n"+
+		"Exported Go API the diff adds, removes or changes:\n"+
+		"- added server.New: func() *Server\n"+
+		"- changed server.Server.Start: *Server func() error -> *Server func(force bool) error\n"+
+		"- removed server.Server.Stop: *Server func()\n"+
+		"</api-changes>"), result)
+
+	input.APIChanges = nil
+	assert.NotContains(t, (&diffview.DefaultFormatter{}).Format(input), "<api-changes>")
+}
+
 func TestDefaultFormatter_Format_MultipleFiles(t *testing.T) {
 	t.Parallel()
 
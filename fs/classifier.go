@@ -28,6 +28,14 @@ func NewClassifier(inner diffview.StoryClassifier, cacheDir string) *Classifier
 	}
 }
 
+// Cache returns middleware that caches classifications in cacheDir, for
+// composing with others through diffview.Chain.
+func Cache(cacheDir string) diffview.ClassifierMiddleware {
+	return func(next diffview.StoryClassifier) diffview.StoryClassifier {
+		return NewClassifier(next, cacheDir)
+	}
+}
+
 // Classify returns a cached classification or delegates to inner classifier.
 func (c *Classifier) Classify(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
 	hash := c.hashInput(input)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/fwojciec/diffstory"
@@ -134,17 +135,35 @@ func TestDefaultCacheDir_UsesXDGIfSet(t *testing.T) {
 
 	dir := fs.DefaultCacheDir()
 
-	assert.Equal(t, "/custom/cache/diffstory", dir)
+	assert.Equal(t, filepath.Join("/custom/cache", "diffstory"), dir)
 }
 
-func TestDefaultCacheDir_FallsBackToHomeCache(t *testing.T) {
+func TestDefaultCacheDir_FallsBackToUserCacheDir(t *testing.T) {
 	// Can't use t.Parallel with t.Setenv
 	t.Setenv("XDG_CACHE_HOME", "")
 
 	dir := fs.DefaultCacheDir()
 
-	home, _ := os.UserHomeDir()
-	assert.Equal(t, filepath.Join(home, ".cache", "diffstory"), dir)
+	userCache, err := os.UserCacheDir()
+	require.NoError(t, err)
+	want := filepath.Join(userCache, "diffstory")
+	if runtime.GOOS == "windows" {
+		want = filepath.Join(want, "cache")
+	}
+	assert.Equal(t, want, dir)
+}
+
+func TestDefaultCacheDir_FallsBackToTempDirWithoutUserCacheDir(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" || runtime.GOOS == "plan9" {
+		t.Skip("the user cache directory comes from HOME only on Unix")
+	}
+	// Can't use t.Parallel with t.Setenv
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "")
+
+	dir := fs.DefaultCacheDir()
+
+	assert.Equal(t, filepath.Join(os.TempDir(), "diffstory"), dir)
 }
 
 func TestClassifier_CorruptedCache_TreatedAsMiss(t *testing.T) {
@@ -191,3 +210,24 @@ func TestClassifier_CorruptedCache_TreatedAsMiss(t *testing.T) {
 	assert.Equal(t, 2, callCount, "corrupted cache should trigger new inner call")
 	assert.Equal(t, expected, result)
 }
+
+func TestCache_ChainsInFrontOfClassifier(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	inner := &mock.StoryClassifier{
+		ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			calls++
+			return &diffview.StoryClassification{ChangeType: "feature"}, nil
+		},
+	}
+	classifier := diffview.Chain(fs.Cache(t.TempDir()))(inner)
+	input := diffview.ClassificationInput{Diff: diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}}}
+
+	for range 2 {
+		result, err := classifier.Classify(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "feature", result.ChangeType)
+	}
+	assert.Equal(t, 1, calls)
+}
@@ -3,18 +3,75 @@ package fs
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 )
 
-// DefaultCacheDir returns the default cache directory for diffstory.
-// Uses XDG_CACHE_HOME if set, otherwise falls back to ~/.cache/diffstory,
-// or system temp directory if home is unavailable.
+// DefaultCacheDir returns the cache directory for diffstory: under
+// XDG_CACHE_HOME if set, else under the platform's user cache directory,
+// such as ~/.cache, ~/Library/Caches or %LocalAppData%, or the system temp
+// directory if there is none. On Windows, where the state directory shares
+// %LocalAppData%\diffstory, the cache is in a cache directory inside it.
 func DefaultCacheDir() string {
 	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
 		return filepath.Join(xdg, "diffstory")
 	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "diffstory")
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, "diffstory", "cache")
+	}
+	return filepath.Join(dir, "diffstory")
+}
+
+// DefaultStateDir returns the default state directory for diffstory on the
+// current platform. See StateDirFor.
+func DefaultStateDir() string {
+	return StateDirFor(runtime.GOOS)
+}
+
+// StateDirFor returns the state directory for diffstory on goos.
+// Uses XDG_STATE_HOME if set; on Windows, %LocalAppData%\diffstory\state;
+// otherwise ~/.local/state/diffstory, or the system temp directory if home is
+// unavailable.
+func StateDirFor(goos string) string {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "diffstory")
+	}
+	if goos == "windows" {
+		if local := os.Getenv("LocalAppData"); local != "" {
+			return filepath.Join(local, "diffstory", "state")
+		}
+	}
 	home, err := os.UserHomeDir()
 	if err != nil || home == "" {
-		return filepath.Join(os.TempDir(), "diffstory")
+		return filepath.Join(os.TempDir(), "diffstory-state")
+	}
+	return filepath.Join(home, ".local", "state", "diffstory")
+}
+
+// DefaultConfigDir returns the default config directory for diffstory on the
+// current platform. See ConfigDirFor.
+func DefaultConfigDir() string {
+	return ConfigDirFor(runtime.GOOS)
+}
+
+// ConfigDirFor returns the config directory for diffstory on goos.
+// Uses XDG_CONFIG_HOME if set; on Windows, %AppData%\diffstory; otherwise
+// ~/.config/diffstory, or the system temp directory if home is unavailable.
+func ConfigDirFor(goos string) string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "diffstory")
+	}
+	if goos == "windows" {
+		if roaming := os.Getenv("AppData"); roaming != "" {
+			return filepath.Join(roaming, "diffstory")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return filepath.Join(os.TempDir(), "diffstory-config")
 	}
-	return filepath.Join(home, ".cache", "diffstory")
+	return filepath.Join(home, ".config", "diffstory")
 }
@@ -0,0 +1,40 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fwojciec/diffstory/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDirFor_UsesXDGConfigHome(t *testing.T) {
+	// Can't use t.Parallel with t.Setenv
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join("/tmp", "config"))
+
+	dir := fs.ConfigDirFor("linux")
+
+	assert.Equal(t, filepath.Join("/tmp", "config", "diffstory"), dir)
+}
+
+func TestConfigDirFor_FallsBackToHomeConfig(t *testing.T) {
+	// Can't use t.Parallel with t.Setenv
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("AppData", "/ignored")
+
+	dir := fs.ConfigDirFor("linux")
+
+	home, _ := os.UserHomeDir()
+	assert.Equal(t, filepath.Join(home, ".config", "diffstory"), dir)
+}
+
+func TestConfigDirFor_UsesAppDataOnWindows(t *testing.T) {
+	// Can't use t.Parallel with t.Setenv
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("AppData", filepath.Join("C:", "Users", "me", "AppData", "Roaming"))
+
+	dir := fs.ConfigDirFor("windows")
+
+	assert.Equal(t, filepath.Join("C:", "Users", "me", "AppData", "Roaming", "diffstory"), dir)
+}
@@ -0,0 +1,60 @@
+package fs
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.PreferencesStore = (*PreferencesStore)(nil)
+
+// PreferencesStore keeps preferences in a JSON file.
+type PreferencesStore struct {
+	path string
+}
+
+// NewPreferencesStore creates a preferences store reading and writing path.
+func NewPreferencesStore(path string) *PreferencesStore {
+	return &PreferencesStore{path: path}
+}
+
+// DefaultPreferencesPath returns where preferences are kept by default:
+// preferences.json in the state directory.
+func DefaultPreferencesPath() string {
+	return filepath.Join(DefaultStateDir(), "preferences.json")
+}
+
+// Load returns the saved preferences, or nil if there are none.
+func (s *PreferencesStore) Load() (*diffview.Preferences, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var prefs diffview.Preferences
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// Save writes prefs, replacing any saved before.
+func (s *PreferencesStore) Save(prefs diffview.Preferences) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
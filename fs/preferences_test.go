@@ -0,0 +1,46 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferencesStore_RoundTripsPreferences(t *testing.T) {
+	t.Parallel()
+
+	store := fs.NewPreferencesStore(filepath.Join(t.TempDir(), "state", "preferences.json"))
+	prefs := diffview.Preferences{SplitRatio: 50, RawMode: true, NoWordDiff: true}
+
+	require.NoError(t, store.Save(prefs))
+
+	got, err := store.Load()
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, prefs, *got)
+}
+
+func TestPreferencesStore_LoadWithoutFile(t *testing.T) {
+	t.Parallel()
+
+	store := fs.NewPreferencesStore(filepath.Join(t.TempDir(), "preferences.json"))
+
+	got, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestPreferencesStore_LoadCorruptFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "preferences.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+
+	_, err := fs.NewPreferencesStore(path).Load()
+	assert.Error(t, err)
+}
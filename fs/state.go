@@ -0,0 +1,63 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.ViewStateStore = (*StateStore)(nil)
+
+// StateStore keeps one view state file per diff in a directory, named by a
+// hash of the diff's content.
+type StateStore struct {
+	dir string
+}
+
+// NewStateStore creates a state store writing to dir.
+func NewStateStore(dir string) *StateStore {
+	return &StateStore{dir: dir}
+}
+
+// Load returns the saved state for diff, or nil if there is none.
+func (s *StateStore) Load(diff *diffview.Diff) (*diffview.ViewState, error) {
+	data, err := os.ReadFile(s.statePath(diff))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state diffview.ViewState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Save writes the state for diff, replacing any saved before.
+func (s *StateStore) Save(diff *diffview.Diff, state diffview.ViewState) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.statePath(diff), data, 0644)
+}
+
+func (s *StateStore) statePath(diff *diffview.Diff) string {
+	data, _ := json.Marshal(diff)
+	sum := sha256.Sum256(data)
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
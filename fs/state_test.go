@@ -0,0 +1,112 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stateTestDiff(content string) *diffview.Diff {
+	return &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{
+				NewPath:   "b/main.go",
+				Operation: diffview.FileModified,
+				Hunks: []diffview.Hunk{
+					{Lines: []diffview.Line{{Type: diffview.LineAdded, Content: content, NewLineNum: 1}}},
+				},
+			},
+		},
+	}
+}
+
+func TestStateStore_RoundTripsStatePerDiff(t *testing.T) {
+	t.Parallel()
+
+	store := fs.NewStateStore(filepath.Join(t.TempDir(), "state"))
+	state := diffview.ViewState{
+		Offset:        42,
+		ActiveSection: 2,
+		Wrap:          true,
+		Collapsed:     []diffview.HunkRef{{File: "main.go", HunkIndex: 0}},
+	}
+
+	require.NoError(t, store.Save(stateTestDiff("one\n"), state))
+
+	// The same content in a fresh Diff value finds the state
+	got, err := store.Load(stateTestDiff("one\n"))
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, state, *got)
+
+	// A different diff has no state
+	got, err = store.Load(stateTestDiff("two\n"))
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestStateStore_SaveReplacesEarlierState(t *testing.T) {
+	t.Parallel()
+
+	store := fs.NewStateStore(t.TempDir())
+	diff := stateTestDiff("one\n")
+
+	require.NoError(t, store.Save(diff, diffview.ViewState{Offset: 1}))
+	require.NoError(t, store.Save(diff, diffview.ViewState{Offset: 2}))
+
+	got, err := store.Load(diff)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Offset)
+}
+
+func TestStateStore_CorruptedStateIsAnError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := fs.NewStateStore(dir)
+	diff := stateTestDiff("one\n")
+	require.NoError(t, store.Save(diff, diffview.ViewState{}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, entries[0].Name()), []byte("not json"), 0644))
+
+	_, err = store.Load(diff)
+	assert.Error(t, err)
+}
+
+func TestStateDirFor_UsesXDGStateHome(t *testing.T) {
+	// Can't use t.Parallel with t.Setenv
+	t.Setenv("XDG_STATE_HOME", filepath.Join("/tmp", "state"))
+
+	dir := fs.StateDirFor("linux")
+
+	assert.Equal(t, filepath.Join("/tmp", "state", "diffstory"), dir)
+}
+
+func TestStateDirFor_FallsBackToHomeState(t *testing.T) {
+	// Can't use t.Parallel with t.Setenv
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("LocalAppData", "/ignored")
+
+	dir := fs.StateDirFor("linux")
+
+	home, _ := os.UserHomeDir()
+	assert.Equal(t, filepath.Join(home, ".local", "state", "diffstory"), dir)
+}
+
+func TestStateDirFor_UsesLocalAppDataOnWindows(t *testing.T) {
+	// Can't use t.Parallel with t.Setenv
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("LocalAppData", filepath.Join("C:", "Users", "me", "AppData", "Local"))
+
+	dir := fs.StateDirFor("windows")
+
+	assert.Equal(t, filepath.Join("C:", "Users", "me", "AppData", "Local", "diffstory", "state"), dir)
+}
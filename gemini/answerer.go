@@ -0,0 +1,78 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.DiffAnswerer = (*Answerer)(nil)
+
+// Answerer implements diffview.DiffAnswerer using Google Gemini.
+type Answerer struct {
+	client    GenerativeClient
+	model     string
+	formatter diffview.PromptFormatter
+}
+
+// NewAnswerer creates a new Answerer answering with model.
+func NewAnswerer(client GenerativeClient, model string) *Answerer {
+	return &Answerer{
+		client:    client,
+		model:     model,
+		formatter: &diffview.DefaultFormatter{},
+	}
+}
+
+// Answer answers question about diff, following on from history.
+func (a *Answerer) Answer(ctx context.Context, diff *diffview.Diff, story *diffview.StoryClassification, history []diffview.ChatTurn, question string) (string, error) {
+	var formatted string
+	if diff != nil {
+		formatted = a.formatter.Format(diffview.ClassificationInput{Diff: *diff})
+	}
+	contents := []*Content{{
+		Parts: []*Part{{Text: BuildAnswerPrompt(formatted, story, history, question)}},
+	}}
+	resp, err := a.client.GenerateContent(ctx, a.model, contents, BuildAnswerConfig())
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", fmt.Errorf("gemini: returned nil response")
+	}
+	return strings.TrimSpace(resp.Text), nil
+}
+
+// BuildAnswerPrompt creates the prompt for a question about a formatted
+// diff, with the story's summary for context and the conversation so far.
+func BuildAnswerPrompt(formattedDiff string, story *diffview.StoryClassification, history []diffview.ChatTurn, question string) string {
+	var sb strings.Builder
+	sb.WriteString("Answer a reviewer's question about this code change.\n\n")
+	if story != nil && story.Summary != "" {
+		fmt.Fprintf(&sb, "## Change summary\n\n%s\n\n", story.Summary)
+	}
+	sb.WriteString(formattedDiff)
+	if len(history) > 0 {
+		sb.WriteString("\n\n## Conversation so far\n")
+		for _, turn := range history {
+			fmt.Fprintf(&sb, "\nQ: %s\nA: %s\n", turn.Question, turn.Answer)
+		}
+	}
+	fmt.Fprintf(&sb, "\n\n## Question\n\n%s", question)
+	return sb.String()
+}
+
+// BuildAnswerConfig returns config for answering calls.
+func BuildAnswerConfig() *GenerateContentConfig {
+	return &GenerateContentConfig{
+		SystemInstruction: &Content{
+			Parts: []*Part{{
+				Text: `You are an experienced code reviewer helping a colleague understand a diff. Answer in a few short plain-text paragraphs without Markdown headings. Refer to code by file and function name, and say so when the diff doesn't show enough to answer.`,
+			}},
+		},
+		ThinkingLevel: "low",
+	}
+}
@@ -0,0 +1,46 @@
+package gemini_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gemini"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswerer_Answer(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{{
+			NewPath:   "auth.go",
+			Operation: diffview.FileModified,
+			Hunks: []diffview.Hunk{{
+				OldStart: 1, OldCount: 1, NewStart: 1, NewCount: 0,
+				Lines: []diffview.Line{{Type: diffview.LineDeleted, Content: "func legacyLogin() {}"}},
+			}},
+		}},
+	}
+	story := &diffview.StoryClassification{Summary: "Drop the legacy login"}
+	history := []diffview.ChatTurn{{Question: "What changed?", Answer: "A function was removed."}}
+
+	var prompt string
+	client := &gemini.MockGenerativeClient{
+		GenerateContentFn: func(_ context.Context, model string, contents []*gemini.Content, _ *gemini.GenerateContentConfig) (*gemini.GenerateContentResponse, error) {
+			assert.Equal(t, "answerer", model)
+			prompt = contents[0].Parts[0].Text
+			return &gemini.GenerateContentResponse{Text: "  It had no callers left.\n"}, nil
+		},
+	}
+
+	answer, err := gemini.NewAnswerer(client, "answerer").Answer(context.Background(), diff, story, history, "Why was legacyLogin removed?")
+	require.NoError(t, err)
+
+	assert.Equal(t, "It had no callers left.", answer)
+	assert.Contains(t, prompt, "Drop the legacy login")
+	assert.Contains(t, prompt, "func legacyLogin() {}")
+	assert.Contains(t, prompt, "Q: What changed?\nA: A function was removed.")
+	assert.Contains(t, prompt, "## Question\n\nWhy was legacyLogin removed?")
+}
@@ -31,6 +31,7 @@ type Classifier struct {
 	retryEnabled           bool
 	maxValidationRetries   int
 	validationRetryEnabled bool
+	prompt                 Prompt
 }
 
 // ClassifierOption configures a Classifier.
@@ -66,8 +67,16 @@ func WithValidationRetry(maxRetries int) ClassifierOption {
 	}
 }
 
-// NewClassifier creates a new Classifier.
-func NewClassifier(client GenerativeClient, model string, opts ...ClassifierOption) *Classifier {
+// WithPrompt sets the prompt template used instead of the built-in one.
+func WithPrompt(p Prompt) ClassifierOption {
+	return func(c *Classifier) {
+		c.prompt = p
+	}
+}
+
+// NewClassifier creates a new Classifier. It returns an error if the
+// built-in prompt, used without WithPrompt, fails to parse.
+func NewClassifier(client GenerativeClient, model string, opts ...ClassifierOption) (*Classifier, error) {
 	c := &Classifier{
 		client:    client,
 		model:     model,
@@ -77,7 +86,19 @@ func NewClassifier(client GenerativeClient, model string, opts ...ClassifierOpti
 	for _, opt := range opts {
 		opt(c)
 	}
-	return c
+	if c.prompt.tmpl == nil {
+		prompt, err := DefaultPrompt()
+		if err != nil {
+			return nil, err
+		}
+		c.prompt = prompt
+	}
+	return c, nil
+}
+
+// PromptVersion returns the version of the prompt the classifier uses.
+func (c *Classifier) PromptVersion() string {
+	return c.prompt.Version
 }
 
 // Classify produces a StoryClassification from classification input.
@@ -87,7 +108,10 @@ func (c *Classifier) Classify(ctx context.Context, input diffview.Classification
 	defer cancel()
 
 	formattedInput := c.formatter.Format(input)
-	prompt := BuildClassificationPrompt(formattedInput)
+	prompt, err := c.prompt.Build(formattedInput)
+	if err != nil {
+		return nil, err
+	}
 
 	maxValidationAttempts := 1
 	if c.validationRetryEnabled {
@@ -223,106 +247,15 @@ func (c *Classifier) backoffDelay(attempt int) time.Duration {
 	return time.Duration(delay+jitter) * time.Millisecond
 }
 
-// BuildClassificationPrompt creates the user prompt for classification.
+// BuildClassificationPrompt creates the user prompt for classification from
+// the built-in prompt.
 // Note: JSON schema is provided via ResponseSchema, not in the prompt (per Google's recommendation).
-func BuildClassificationPrompt(formattedInput string) string {
-	return fmt.Sprintf(`Analyze this code change and classify it into a structured narrative.
-
-%s
-
-## Why Narrative Structure Matters
-
-Code reviews are cognitively demanding. Research shows that developers process changes more effectively when presented as stories rather than lists. Each narrative follows a three-act structure:
-
-- **Exposition**: Context and setup (what exists, what's the problem)
-- **Confrontation**: The change itself (the fix, new feature, transformation)
-- **Resolution**: Validation and cleanup (tests proving it works, supporting changes)
-
-## Classifying the Change
-
-Determine the **change_type** (bugfix, feature, refactor, chore, docs) and select a **narrative** that best tells the story:
-
-1. **Is it fixing a bug or issue?** (change_type: bugfix) → cause-effect
-   - Shows the problem, then the fix, then proof it works
-   - Exposition: the buggy code (problem)
-   - Confrontation: the fix
-   - Resolution: tests validating the fix
-
-2. **Is it replacing an old pattern with a new one?** (change_type: refactor) → before-after
-   - Shows the transformation from old to new
-   - Exposition: what's being removed (cleanup)
-   - Confrontation: the new pattern (core)
-   - Resolution: tests proving the new pattern works
-
-3. **Is it adding a new API/interface with implementation?** (change_type: feature) → entry-implementation
-   - Shows the contract first, then the implementation
-   - Exposition: the interface/API (interface)
-   - Confrontation: the implementation (core)
-   - Resolution: tests and supporting changes
-
-4. **Is it applying the same pattern in multiple places?** (change_type: refactor) → rule-instances
-   - Shows the pattern, then its applications
-   - Exposition: the pattern (pattern)
-   - Confrontation: applications of the pattern (core)
-   - Resolution: tests validating the applications
-
-5. **Otherwise (feature, enhancement, general change)?** (change_type: feature/chore/docs) → core-periphery
-   - Shows the central change and its ripple effects
-   - Exposition: the core change (core)
-   - Confrontation: supporting updates (supporting)
-   - Resolution: tests and cleanup
-
-## Section Ordering: Two-Pass Process
-
-The array order in your output determines reading order. Follow this two-pass approach:
-
-### Pass 1: Narrative-Driven Ordering
-Start with the standard ordering for your chosen narrative:
-- cause-effect: problem → fix → test → supporting → cleanup
-- core-periphery: core → supporting → test → cleanup
-- before-after: cleanup (old pattern) → core (new pattern) → supporting → test
-- rule-instances: pattern → core → test → supporting → cleanup
-- entry-implementation: interface → core → test → supporting → cleanup
-
-Principles for this ordering:
-1. **Context before detail**: Show "why" before "what" (exposition before action)
-2. **High-impact first**: Core changes before peripheral ones
-3. **Tests as validation**: Tests belong near the end as proof (resolution/denouement)
-
-### Pass 2: Sink Fully-Collapsed Sections
-After establishing narrative order, identify sections where EVERY hunk is collapsed=true. These are "empty slides" in the story - they contain no visible content for the reviewer.
-
-**Move fully-collapsed sections to the very end**, preserving their relative order. This prevents "empty slides" from interrupting the narrative flow.
-
-Example: If your narrative order produces [problem, fix, cleanup, test] but "cleanup" has all hunks collapsed, the final order should be [problem, fix, test, cleanup].
-
-## Classifying Hunks
-
-For each hunk, determine:
-- **category**: refactoring (restructure without behavior change), systematic (mechanical changes like renames), core (essential logic change), noise (formatting, whitespace)
-- **collapsed**: whether to collapse in a diff viewer (true for noise, often true for systematic; never collapse tests - they verify intent and are essential for review)
-
-Group hunks into sections with meaningful roles that tell the story of the change.
-
-## Rules
-- Every hunk from the input must appear in exactly one section
-- **CRITICAL: hunk_index is 0-based.** If a file has N hunks, valid indices are 0 through N-1. For example, a file with 7 hunks has valid indices 0, 1, 2, 3, 4, 5, 6 (NOT 7).
-- collapse_text provides a summary when collapsed is true
-
-## Commit History and Evolution
-
-When the input includes multiple commits with per-commit diffs, use this history to understand how the change developed:
-
-**Using commit progression:**
-- The commit sequence shows the author's development journey
-- Early commits often establish foundations; later commits add polish, edge cases, or tests
-- Section explanations can reference specific commits when relevant (e.g., "Added in commit 2 after initial implementation")
-
-**The evolution field:**
-- Populate "evolution" when commit history reveals meaningful progression
-- Good examples: "Initial feature in commit 1, refined API based on usage in commit 2, added edge case handling in commit 3"
-- Omit or leave empty for single-commit PRs or when commits are mechanical (formatting, renames)
-- The evolution should help reviewers understand the development thought process, not just list commits`, formattedInput)
+func BuildClassificationPrompt(formattedInput string) (string, error) {
+	prompt, err := DefaultPrompt()
+	if err != nil {
+		return "", err
+	}
+	return prompt.Build(formattedInput)
 }
 
 // BuildClassificationConfig returns config for classification calls.
@@ -431,8 +364,36 @@ func classificationSchema() *Schema {
 					PropertyOrdering: []string{"role", "title", "hunks", "explanation"},
 				},
 			},
+			"risk": {
+				Type:        "object",
+				Description: "What the change may break for code and people depending on it",
+				Properties: map[string]*Schema{
+					"level": {
+						Type:        "string",
+						Enum:        []string{"low", "medium", "high"},
+						Description: "How likely the change is to break something for its users: high for breaking changes to public APIs, data or config",
+					},
+					"breaking": {
+						Type:        "array",
+						Items:       &Schema{Type: "string"},
+						Description: "Each way the change breaks existing callers, stored data or configuration. Empty if nothing breaks.",
+					},
+					"migration": {
+						Type:        "array",
+						Items:       &Schema{Type: "string"},
+						Description: "Steps users or operators need to take to upgrade, in order. Empty if none are needed.",
+					},
+					"affected_apis": {
+						Type:        "array",
+						Items:       &Schema{Type: "string"},
+						Description: "Exported functions, types, endpoints or flags whose signature or behavior changes",
+					},
+				},
+				Required:         []string{"level", "breaking", "migration", "affected_apis"},
+				PropertyOrdering: []string{"breaking", "migration", "affected_apis", "level"},
+			},
 		},
-		Required:         []string{"change_type", "narrative", "summary", "sections"},
-		PropertyOrdering: []string{"change_type", "narrative", "summary", "evolution", "sections"},
+		Required:         []string{"change_type", "narrative", "summary", "sections", "risk"},
+		PropertyOrdering: []string{"change_type", "narrative", "summary", "evolution", "sections", "risk"},
 	}
 }
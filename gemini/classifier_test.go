@@ -41,7 +41,8 @@ func TestClassifier_Classify_ReturnsStoryClassification(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Repo: "test",
 		Commits: []diffview.CommitBrief{
@@ -91,12 +92,13 @@ func TestClassifier_Classify_PropagatesAPIError(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
 
-	_, err := classifier.Classify(context.Background(), input)
+	_, err = classifier.Classify(context.Background(), input)
 
 	require.Error(t, err)
 	assert.Equal(t, expectedErr, err)
@@ -111,12 +113,13 @@ func TestClassifier_Classify_ReturnsErrorOnInvalidJSON(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
 
-	_, err := classifier.Classify(context.Background(), input)
+	_, err = classifier.Classify(context.Background(), input)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse")
@@ -131,12 +134,13 @@ func TestClassifier_Classify_ReturnsErrorOnNilResponse(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
 
-	_, err := classifier.Classify(context.Background(), input)
+	_, err = classifier.Classify(context.Background(), input)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "nil response")
@@ -153,7 +157,8 @@ Test message
 === FILE: test.go (modified) ===
 </diff>`
 
-	prompt := gemini.BuildClassificationPrompt(formattedInput)
+	prompt, err := gemini.BuildClassificationPrompt(formattedInput)
+	require.NoError(t, err)
 
 	assert.Contains(t, prompt, "<commit_message>")
 	assert.Contains(t, prompt, "Test message")
@@ -164,7 +169,8 @@ Test message
 func TestBuildClassificationPrompt_IncludesInstructions(t *testing.T) {
 	t.Parallel()
 
-	prompt := gemini.BuildClassificationPrompt("test input")
+	prompt, err := gemini.BuildClassificationPrompt("test input")
+	require.NoError(t, err)
 
 	// Check key instruction elements
 	assert.Contains(t, prompt, "change_type")
@@ -226,13 +232,14 @@ func TestClassifier_Classify_TimesOutOnSlowAPI(t *testing.T) {
 
 	// Use very short timeout for test
 	timeout := 10 * time.Millisecond
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel, gemini.WithTimeout(timeout))
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel, gemini.WithTimeout(timeout))
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
 
 	// Act
-	_, err := classifier.Classify(context.Background(), input)
+	_, err = classifier.Classify(context.Background(), input)
 
 	// Assert
 	require.Error(t, err)
@@ -251,7 +258,8 @@ func TestClassifier_Classify_RespectsCallerContextDeadline(t *testing.T) {
 	}
 
 	// Classifier has long timeout, but caller context is short
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel, gemini.WithTimeout(time.Hour))
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel, gemini.WithTimeout(time.Hour))
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
@@ -261,7 +269,7 @@ func TestClassifier_Classify_RespectsCallerContextDeadline(t *testing.T) {
 	defer cancel()
 
 	// Act
-	_, err := classifier.Classify(ctx, input)
+	_, err = classifier.Classify(ctx, input)
 
 	// Assert: should timeout from caller's context, not classifier's
 	require.Error(t, err)
@@ -292,8 +300,9 @@ func TestClassifier_Classify_RetriesOnTransientErrors(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel,
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel,
 		gemini.WithRetry(3, 1*time.Millisecond, 10*time.Millisecond))
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
@@ -316,13 +325,14 @@ func TestClassifier_Classify_DoesNotRetryNonRetryableErrors(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel,
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel,
 		gemini.WithRetry(3, 1*time.Millisecond, 10*time.Millisecond))
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
 
-	_, err := classifier.Classify(context.Background(), input)
+	_, err = classifier.Classify(context.Background(), input)
 
 	require.Error(t, err)
 	assert.Equal(t, 1, callCount, "should not retry non-retryable errors")
@@ -339,13 +349,14 @@ func TestClassifier_Classify_FailsAfterMaxRetries(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel,
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel,
 		gemini.WithRetry(3, 1*time.Millisecond, 10*time.Millisecond))
+	require.NoError(t, err)
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
 	}
 
-	_, err := classifier.Classify(context.Background(), input)
+	_, err = classifier.Classify(context.Background(), input)
 
 	require.Error(t, err)
 	assert.Equal(t, 3, callCount, "should try maxRetries times")
@@ -364,6 +375,11 @@ func TestBuildClassificationConfig_SetsResponseSchema(t *testing.T) {
 	assert.Contains(t, config.ResponseSchema.Properties, "narrative")
 	assert.Contains(t, config.ResponseSchema.Properties, "summary")
 	assert.Contains(t, config.ResponseSchema.Properties, "sections")
+	assert.Contains(t, config.ResponseSchema.Required, "risk")
+	risk := config.ResponseSchema.Properties["risk"]
+	require.NotNil(t, risk)
+	assert.Equal(t, []string{"low", "medium", "high"}, risk.Properties["level"].Enum)
+	assert.ElementsMatch(t, []string{"breaking", "migration", "affected_apis", "level"}, risk.Required)
 }
 
 func TestClassifier_Classify_RetriesOnInvalidHunkReferences(t *testing.T) {
@@ -424,8 +440,9 @@ func TestClassifier_Classify_RetriesOnInvalidHunkReferences(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel,
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel,
 		gemini.WithValidationRetry(2)) // Enable validation retry
+	require.NoError(t, err)
 
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "Add feature"}},
@@ -481,8 +498,9 @@ func TestClassifier_Classify_FailsAfterMaxValidationRetries(t *testing.T) {
 		},
 	}
 
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel,
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel,
 		gemini.WithValidationRetry(2))
+	require.NoError(t, err)
 
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
@@ -534,7 +552,8 @@ func TestClassifier_Classify_ValidatesWithoutRetryWhenDisabled(t *testing.T) {
 	}
 
 	// No WithValidationRetry - should not validate/retry
-	classifier := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	classifier, err := gemini.NewClassifier(mockClient, gemini.DefaultModel)
+	require.NoError(t, err)
 
 	input := diffview.ClassificationInput{
 		Commits: []diffview.CommitBrief{{Message: "test"}},
@@ -0,0 +1,163 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.CaseJudge = (*Judge)(nil)
+
+// Judge implements diffview.CaseJudge using Google Gemini as the grader.
+type Judge struct {
+	client    GenerativeClient
+	model     string
+	formatter diffview.PromptFormatter
+}
+
+// NewJudge creates a new Judge grading with model.
+func NewJudge(client GenerativeClient, model string) *Judge {
+	return &Judge{
+		client:    client,
+		model:     model,
+		formatter: &diffview.DefaultFormatter{},
+	}
+}
+
+// judgeResponse is the grader's structured verdict.
+type judgeResponse struct {
+	Pass     bool   `json:"pass"`
+	Category string `json:"category"`
+	Severity string `json:"severity"`
+	Critique string `json:"critique"`
+}
+
+// Judge grades the case's classification against the rubric. The judgment
+// names the model as its judge.
+func (j *Judge) Judge(ctx context.Context, c diffview.EvalCase) (*diffview.Judgment, error) {
+	if c.Story == nil {
+		return nil, fmt.Errorf("gemini: case %s has no classification to judge", c.Input.Name())
+	}
+	story, err := json.MarshalIndent(c.Story, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	contents := []*Content{{
+		Parts: []*Part{{Text: BuildJudgePrompt(j.formatter.Format(c.Input), string(story))}},
+	}}
+	resp, err := j.client.GenerateContent(ctx, j.model, contents, BuildJudgeConfig())
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("gemini: returned nil response")
+	}
+
+	var verdict judgeResponse
+	if err := json.Unmarshal([]byte(resp.Text), &verdict); err != nil {
+		return nil, fmt.Errorf("gemini: failed to parse response: %w", err)
+	}
+
+	judgment := &diffview.Judgment{
+		CaseID:   c.CaseID(),
+		Judged:   true,
+		Pass:     verdict.Pass,
+		Critique: verdict.Critique,
+		JudgedAt: time.Now(),
+		Judge:    j.model,
+	}
+	// The schema can't leave an enum empty, so "none" stands for unset
+	if verdict.Category != "none" {
+		judgment.Category = diffview.CritiqueCategory(verdict.Category)
+	}
+	if verdict.Severity != "none" {
+		judgment.Severity = diffview.Severity(verdict.Severity)
+	}
+	return judgment, nil
+}
+
+// BuildJudgePrompt creates the grading prompt for a formatted case input and
+// its classification as JSON.
+func BuildJudgePrompt(formattedInput, story string) string {
+	return fmt.Sprintf(`Grade how well this classification tells the story of the code change.
+
+%s
+
+## Classification
+
+%s
+
+## Rubric
+
+A classification passes when a reviewer reading it would understand the change correctly and could review it section by section. Fail it if any of these hold:
+
+- **wrong_narrative**: the change type, narrative or summary misdescribes what the change does
+- **missing_hunk**: a hunk that matters to the change is in no section
+- **bad_grouping**: a section mixes unrelated hunks, or related hunks are split without reason
+- **hallucination**: the classification describes code or behavior the diff doesn't contain
+
+Pick the category of the most serious problem, and its severity:
+
+- **minor**: the story is still usable
+- **major**: a reviewer would be misled about part of the change
+- **critical**: the story is wrong or unusable
+
+For a pass, use "none" for category and severity. Write the critique as a reviewer would: name the sections and hunks (as file:H<index>) involved, in a few sentences.`, formattedInput, story)
+}
+
+// BuildJudgeConfig returns config for grading calls.
+func BuildJudgeConfig() *GenerateContentConfig {
+	return &GenerateContentConfig{
+		SystemInstruction: &Content{
+			Parts: []*Part{{
+				Text: `You are an experienced code reviewer grading narrative classifications of code changes. Grade strictly against the rubric, and only fail a classification for problems you can point to in the diff.`,
+			}},
+		},
+		ResponseMIMEType: "application/json",
+		ResponseSchema:   judgeSchema(),
+		ThinkingLevel:    "medium",
+	}
+}
+
+// judgeSchema returns the JSON schema for the grader's verdict.
+func judgeSchema() *Schema {
+	categories := []string{"none"}
+	for _, c := range diffview.CritiqueCategories() {
+		categories = append(categories, string(c))
+	}
+	severities := []string{"none"}
+	for _, s := range diffview.Severities() {
+		severities = append(severities, string(s))
+	}
+
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"pass": {
+				Type:        "boolean",
+				Description: "Whether the classification is acceptable",
+			},
+			"category": {
+				Type:        "string",
+				Enum:        categories,
+				Description: "Kind of the most serious problem, or none for a pass",
+			},
+			"severity": {
+				Type:        "string",
+				Enum:        severities,
+				Description: "How much the problem matters, or none for a pass",
+			},
+			"critique": {
+				Type:        "string",
+				Description: "The reasoning behind the grade",
+			},
+		},
+		Required:         []string{"pass", "category", "severity", "critique"},
+		PropertyOrdering: []string{"critique", "pass", "category", "severity"},
+	}
+}
@@ -0,0 +1,73 @@
+package gemini_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gemini"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJudge_Judge(t *testing.T) {
+	t.Parallel()
+
+	evalCase := diffview.EvalCase{
+		ID:    "fix-case",
+		Input: diffview.ClassificationInput{Repo: "repo", Branch: "fix", Commits: []diffview.CommitBrief{{Hash: "abc123", Message: "Fix expiry"}}},
+		Story: &diffview.StoryClassification{ChangeType: "bugfix", Summary: "Fix token expiry"},
+	}
+
+	t.Run("returns the verdict as a judgment by the model", func(t *testing.T) {
+		t.Parallel()
+
+		var prompt string
+		client := &gemini.MockGenerativeClient{
+			GenerateContentFn: func(_ context.Context, model string, contents []*gemini.Content, config *gemini.GenerateContentConfig) (*gemini.GenerateContentResponse, error) {
+				assert.Equal(t, "grader", model)
+				require.NotNil(t, config.ResponseSchema)
+				prompt = contents[0].Parts[0].Text
+				return &gemini.GenerateContentResponse{Text: `{"critique":"auth.go:H1 is in no section","pass":false,"category":"missing_hunk","severity":"major"}`}, nil
+			},
+		}
+
+		j, err := gemini.NewJudge(client, "grader").Judge(context.Background(), evalCase)
+		require.NoError(t, err)
+
+		assert.Contains(t, prompt, `"summary": "Fix token expiry"`)
+		assert.Contains(t, prompt, "abc123")
+		assert.Equal(t, "fix-case", j.CaseID)
+		assert.True(t, j.Judged)
+		assert.False(t, j.Pass)
+		assert.Equal(t, diffview.CategoryMissingHunk, j.Category)
+		assert.Equal(t, diffview.SeverityMajor, j.Severity)
+		assert.Equal(t, "auth.go:H1 is in no section", j.Critique)
+		assert.Equal(t, "grader", j.Judge)
+		assert.False(t, j.JudgedAt.IsZero())
+	})
+
+	t.Run("leaves category and severity unset for a pass", func(t *testing.T) {
+		t.Parallel()
+
+		client := &gemini.MockGenerativeClient{
+			GenerateContentFn: func(context.Context, string, []*gemini.Content, *gemini.GenerateContentConfig) (*gemini.GenerateContentResponse, error) {
+				return &gemini.GenerateContentResponse{Text: `{"critique":"Clear story","pass":true,"category":"none","severity":"none"}`}, nil
+			},
+		}
+
+		j, err := gemini.NewJudge(client, "grader").Judge(context.Background(), evalCase)
+		require.NoError(t, err)
+
+		assert.True(t, j.Pass)
+		assert.Empty(t, j.Category)
+		assert.Empty(t, j.Severity)
+	})
+
+	t.Run("rejects an unclassified case", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := gemini.NewJudge(&gemini.MockGenerativeClient{}, "grader").Judge(context.Background(), diffview.EvalCase{})
+		assert.Error(t, err)
+	})
+}
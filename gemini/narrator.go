@@ -0,0 +1,109 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.StoryNarrator = (*Narrator)(nil)
+
+// Narrator implements diffview.StoryNarrator using Google Gemini.
+type Narrator struct {
+	client    GenerativeClient
+	model     string
+	formatter diffview.PromptFormatter
+}
+
+// NewNarrator creates a new Narrator writing with model.
+func NewNarrator(client GenerativeClient, model string) *Narrator {
+	return &Narrator{
+		client:    client,
+		model:     model,
+		formatter: &diffview.DefaultFormatter{},
+	}
+}
+
+// narrationResponse is the model's narration of each section.
+type narrationResponse struct {
+	Narrations []string `json:"narrations"`
+}
+
+// Narrate returns a short spoken narration for each section of story.
+func (n *Narrator) Narrate(ctx context.Context, input diffview.ClassificationInput, story *diffview.StoryClassification) ([]string, error) {
+	if story == nil || len(story.Sections) == 0 {
+		return nil, nil
+	}
+	storyJSON, err := json.MarshalIndent(story, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	contents := []*Content{{
+		Parts: []*Part{{Text: BuildNarrationPrompt(n.formatter.Format(input), string(storyJSON), len(story.Sections))}},
+	}}
+	resp, err := n.client.GenerateContent(ctx, n.model, contents, BuildNarrationConfig())
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("gemini: returned nil response")
+	}
+
+	var parsed narrationResponse
+	if err := json.Unmarshal([]byte(resp.Text), &parsed); err != nil {
+		return nil, fmt.Errorf("gemini: failed to parse response: %w", err)
+	}
+	if len(parsed.Narrations) != len(story.Sections) {
+		return nil, fmt.Errorf("gemini: got %d narrations for %d sections", len(parsed.Narrations), len(story.Sections))
+	}
+	return parsed.Narrations, nil
+}
+
+// BuildNarrationPrompt creates the narration prompt for a formatted input
+// and its classification as JSON, which has sections sections.
+func BuildNarrationPrompt(formattedInput, story string, sections int) string {
+	return fmt.Sprintf(`Write what a presenter would say while walking a new teammate through this code change, one section at a time.
+
+%s
+
+## Classification
+
+%s
+
+## Narration
+
+Write exactly %d narrations, one per section in the order listed. Each is two to four sentences meant to be read aloud: plain spoken English, no Markdown, no code, no file paths or hunk references. Say what the section does and why it matters to the change, and lead into the next section where it helps the story flow.`, formattedInput, story, sections)
+}
+
+// BuildNarrationConfig returns config for narration calls.
+func BuildNarrationConfig() *GenerateContentConfig {
+	return &GenerateContentConfig{
+		SystemInstruction: &Content{
+			Parts: []*Part{{
+				Text: `You are a senior engineer recording an onboarding walkthrough of a code change. You speak clearly and concisely, and only describe what the diff shows.`,
+			}},
+		},
+		ResponseMIMEType: "application/json",
+		ResponseSchema:   narrationSchema(),
+		ThinkingLevel:    "low",
+	}
+}
+
+// narrationSchema returns the JSON schema for the section narrations.
+func narrationSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"narrations": {
+				Type:        "array",
+				Items:       &Schema{Type: "string"},
+				Description: "Spoken narration of each section, in section order",
+			},
+		},
+		Required: []string{"narrations"},
+	}
+}
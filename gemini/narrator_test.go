@@ -0,0 +1,67 @@
+package gemini_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gemini"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNarrator_Narrate(t *testing.T) {
+	t.Parallel()
+
+	input := diffview.ClassificationInput{Repo: "repo", Branch: "fix", Commits: []diffview.CommitBrief{{Hash: "abc123", Message: "Fix expiry"}}}
+	story := &diffview.StoryClassification{
+		Summary: "Fix token expiry",
+		Sections: []diffview.Section{
+			{Role: "problem", Title: "Expired tokens accepted"},
+			{Role: "fix", Title: "Check expiry"},
+		},
+	}
+
+	t.Run("returns a narration per section", func(t *testing.T) {
+		t.Parallel()
+
+		var prompt string
+		client := &gemini.MockGenerativeClient{
+			GenerateContentFn: func(_ context.Context, model string, contents []*gemini.Content, config *gemini.GenerateContentConfig) (*gemini.GenerateContentResponse, error) {
+				assert.Equal(t, "narrator", model)
+				require.NotNil(t, config.ResponseSchema)
+				prompt = contents[0].Parts[0].Text
+				return &gemini.GenerateContentResponse{Text: `{"narrations":["Tokens never expired.","Now we check."]}`}, nil
+			},
+		}
+
+		narrations, err := gemini.NewNarrator(client, "narrator").Narrate(context.Background(), input, story)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Tokens never expired.", "Now we check."}, narrations)
+		assert.Contains(t, prompt, `"title": "Check expiry"`)
+		assert.Contains(t, prompt, "exactly 2 narrations")
+		assert.Contains(t, prompt, "abc123")
+	})
+
+	t.Run("rejects a narration count that doesn't match the sections", func(t *testing.T) {
+		t.Parallel()
+
+		client := &gemini.MockGenerativeClient{
+			GenerateContentFn: func(context.Context, string, []*gemini.Content, *gemini.GenerateContentConfig) (*gemini.GenerateContentResponse, error) {
+				return &gemini.GenerateContentResponse{Text: `{"narrations":["Only one."]}`}, nil
+			},
+		}
+
+		_, err := gemini.NewNarrator(client, "narrator").Narrate(context.Background(), input, story)
+		assert.ErrorContains(t, err, "got 1 narrations for 2 sections")
+	})
+
+	t.Run("skips a story without sections", func(t *testing.T) {
+		t.Parallel()
+
+		narrations, err := gemini.NewNarrator(&gemini.MockGenerativeClient{}, "narrator").Narrate(context.Background(), input, &diffview.StoryClassification{})
+		require.NoError(t, err)
+		assert.Empty(t, narrations)
+	})
+}
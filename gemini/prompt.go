@@ -0,0 +1,75 @@
+package gemini
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultPromptVersion is the version of the built-in classification prompt.
+// Bump it whenever prompts/classify-v1.tmpl changes meaning, renaming the
+// file to match.
+const DefaultPromptVersion = "v1"
+
+//go:embed prompts/classify-v1.tmpl
+var defaultPromptText string
+
+// Prompt is a versioned template for the classification prompt. The
+// template is given the formatted case input as {{.Input}}.
+type Prompt struct {
+	Version string
+	tmpl    *template.Template
+}
+
+// DefaultPrompt parses and returns the built-in classification prompt.
+func DefaultPrompt() (Prompt, error) {
+	return ParsePrompt(DefaultPromptVersion, defaultPromptText)
+}
+
+// ParsePrompt parses a prompt template, which must use {{.Input}}.
+// Trailing newlines are dropped.
+func ParsePrompt(version, text string) (Prompt, error) {
+	tmpl, err := template.New(version).Option("missingkey=error").Parse(strings.TrimRight(text, "\n"))
+	if err != nil {
+		return Prompt{}, fmt.Errorf("prompt %s: %w", version, err)
+	}
+	p := Prompt{Version: version, tmpl: tmpl}
+
+	// A template that drops the input would classify nothing
+	const probe = "\x00input\x00"
+	built, err := p.Build(probe)
+	if err != nil {
+		return Prompt{}, err
+	}
+	if !strings.Contains(built, probe) {
+		return Prompt{}, fmt.Errorf("prompt %s doesn't use {{.Input}}", version)
+	}
+	return p, nil
+}
+
+// LoadPrompt reads a prompt template from a file. Its version is the file
+// name without extension followed by a hash of its content, such as
+// "terse@1a2b3c4d", so editing the file changes the version.
+func LoadPrompt(path string) (Prompt, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Prompt{}, err
+	}
+	sum := sha256.Sum256(data)
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParsePrompt(name+"@"+hex.EncodeToString(sum[:4]), string(data))
+}
+
+// Build returns the prompt for formattedInput.
+func (p Prompt) Build(formattedInput string) (string, error) {
+	var sb strings.Builder
+	if err := p.tmpl.Execute(&sb, struct{ Input string }{formattedInput}); err != nil {
+		return "", fmt.Errorf("prompt %s: %w", p.Version, err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,99 @@
+package gemini_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gemini"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPrompt(t *testing.T) {
+	t.Parallel()
+
+	p, err := gemini.DefaultPrompt()
+	require.NoError(t, err)
+	assert.Equal(t, gemini.DefaultPromptVersion, p.Version)
+
+	prompt, err := p.Build("the input")
+	require.NoError(t, err)
+	built, err := gemini.BuildClassificationPrompt("the input")
+	require.NoError(t, err)
+	assert.Equal(t, built, prompt)
+	assert.NotRegexp(t, `\n$`, prompt)
+}
+
+func TestParsePrompt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builds the prompt around the input", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := gemini.ParsePrompt("terse", "Classify:\n{{.Input}}\n")
+		require.NoError(t, err)
+
+		prompt, err := p.Build("diff here")
+		require.NoError(t, err)
+		assert.Equal(t, "Classify:\ndiff here", prompt)
+	})
+
+	t.Run("rejects a template without the input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := gemini.ParsePrompt("broken", "Classify this")
+		assert.ErrorContains(t, err, "doesn't use {{.Input}}")
+	})
+
+	t.Run("rejects an unknown field", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := gemini.ParsePrompt("broken", "{{.Input}} {{.Diff}}")
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadPrompt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "terse.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("Classify:\n{{.Input}}\n"), 0o644))
+
+	p, err := gemini.LoadPrompt(path)
+	require.NoError(t, err)
+	assert.Regexp(t, `^terse@[0-9a-f]{8}$`, p.Version)
+
+	require.NoError(t, os.WriteFile(path, []byte("Classify carefully:\n{{.Input}}\n"), 0o644))
+	edited, err := gemini.LoadPrompt(path)
+	require.NoError(t, err)
+	assert.NotEqual(t, p.Version, edited.Version)
+}
+
+func TestClassifier_WithPrompt(t *testing.T) {
+	t.Parallel()
+
+	p, err := gemini.ParsePrompt("terse", "Classify:\n{{.Input}}")
+	require.NoError(t, err)
+
+	var sent string
+	client := &gemini.MockGenerativeClient{
+		GenerateContentFn: func(_ context.Context, _ string, contents []*gemini.Content, _ *gemini.GenerateContentConfig) (*gemini.GenerateContentResponse, error) {
+			sent = contents[0].Parts[0].Text
+			return &gemini.GenerateContentResponse{Text: `{"change_type":"bugfix"}`}, nil
+		},
+	}
+	classifier, err := gemini.NewClassifier(client, gemini.DefaultModel, gemini.WithPrompt(p))
+	require.NoError(t, err)
+
+	_, err = classifier.Classify(context.Background(), diffview.ClassificationInput{Repo: "repo"})
+	require.NoError(t, err)
+	assert.Equal(t, "terse", classifier.PromptVersion())
+	assert.Regexp(t, `^Classify:\n`, sent)
+	classifier, err = gemini.NewClassifier(client, gemini.DefaultModel)
+	require.NoError(t, err)
+	assert.Equal(t, gemini.DefaultPromptVersion, classifier.PromptVersion())
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Remote identifies a repository on a git hosting service.
+type Remote struct {
+	Host string // e.g. "github.com"
+	Path string // repository path on the host, e.g. "owner/repo" or "group/sub/repo"
+}
+
+// ParseRemote parses a remote URL in any of the forms git accepts for
+// hosted repositories: https://host/owner/repo.git, ssh://git@host/owner/repo.git
+// and the scp-like git@host:owner/repo.git.
+func ParseRemote(rawURL string) (Remote, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	var host, path string
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote URL %q: %w", rawURL, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(rawURL, ":"); ok && !strings.Contains(at, "/") {
+		// scp-like syntax: [user@]host:path
+		host, path = at[strings.LastIndex(at, "@")+1:], rest
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, fmt.Errorf("remote URL %q does not name a hosted repository", rawURL)
+	}
+	return Remote{Host: host, Path: path}, nil
+}
+
+// Permalink returns the web URL showing line of file at commit. GitLab and
+// Bitbucket hosts get their own URL layouts; any other host is assumed to
+// lay out URLs like GitHub, as GitHub Enterprise and Gitea do.
+func (r Remote) Permalink(commit, file string, line int) string {
+	base := "https://" + r.Host + "/" + r.Path
+	file = escapePath(file)
+	switch {
+	case strings.Contains(r.Host, "gitlab"):
+		return fmt.Sprintf("%s/-/blob/%s/%s#L%d", base, commit, file, line)
+	case strings.Contains(r.Host, "bitbucket"):
+		return fmt.Sprintf("%s/src/%s/%s#lines-%d", base, commit, file, line)
+	default:
+		return fmt.Sprintf("%s/blob/%s/%s#L%d", base, commit, file, line)
+	}
+}
+
+// CommitURL returns the web URL showing commit. GitLab and Bitbucket hosts
+// get their own URL layouts, as for Permalink.
+func (r Remote) CommitURL(commit string) string {
+	base := "https://" + r.Host + "/" + r.Path
+	switch {
+	case strings.Contains(r.Host, "gitlab"):
+		return base + "/-/commit/" + commit
+	case strings.Contains(r.Host, "bitbucket"):
+		return base + "/commits/" + commit
+	default:
+		return base + "/commit/" + commit
+	}
+}
+
+// escapePath escapes each segment of a slash-separated path for use in a URL.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -5,7 +5,10 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fwojciec/diffstory"
 )
@@ -13,6 +16,14 @@ import (
 // Compile-time interface verification.
 var _ diffview.GitRunner = (*Runner)(nil)
 
+// patchFlags returns the flags that make git diff and git show print a
+// plain patch whatever the user's config: textconv drivers from
+// .gitattributes are applied, as in the terminal, but not external diff
+// programs, which replace the patch and may be diffview itself, or colors.
+func patchFlags() []string {
+	return []string{"--textconv", "--no-ext-diff", "--no-color"}
+}
+
 // Runner executes git commands via shell.
 type Runner struct{}
 
@@ -33,20 +44,13 @@ func (r *Runner) Log(ctx context.Context, repoPath string, limit int) ([]string,
 		return nil, fmt.Errorf("git log failed: %w", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	// Filter empty lines
-	var hashes []string
-	for _, line := range lines {
-		if line != "" {
-			hashes = append(hashes, line)
-		}
-	}
-	return hashes, nil
+	return splitLines(string(output)), nil
 }
 
 // Show returns the diff for a specific commit hash.
 func (r *Runner) Show(ctx context.Context, repoPath string, hash string) (string, error) {
-	args := []string{"-C", repoPath, "show", "--format=", hash}
+	args := append([]string{"-C", repoPath, "show", "--format="}, patchFlags()...)
+	args = append(args, hash)
 	cmd := exec.CommandContext(ctx, "git", args...)
 	output, err := cmd.Output()
 	if err != nil {
@@ -84,18 +88,75 @@ func (r *Runner) MergeCommits(ctx context.Context, repoPath string, limit int) (
 		return nil, fmt.Errorf("git log --merges failed: %w", err)
 	}
 
-	trimmed := strings.TrimSpace(string(output))
-	if trimmed == "" {
-		return nil, nil
+	return splitLines(string(output)), nil
+}
+
+// History returns every commit matching q, most recent first.
+func (r *Runner) History(ctx context.Context, repoPath string, q diffview.HistoryQuery) ([]diffview.CommitMeta, error) {
+	// Each commit starts with a record separator, then hash<NUL>author<NUL>date,
+	// then its --shortstat line if it changed anything
+	args := []string{"-C", repoPath, "log", "--format=%x1e%H%x00%an%x00%aI", "--shortstat"}
+	if q.Merges {
+		// Merges show no stats unless diffed against their first parent,
+		// which gives the changes the merged branch brought in
+		args = append(args, "--merges", "--diff-merges=first-parent")
+	} else {
+		args = append(args, "--no-merges")
 	}
-	lines := strings.Split(trimmed, "\n")
-	hashes := make([]string, 0, len(lines))
-	for _, line := range lines {
-		if line != "" {
-			hashes = append(hashes, line)
+	if q.Since != "" {
+		args = append(args, "--since="+q.Since)
+	}
+	if q.Until != "" {
+		args = append(args, "--until="+q.Until)
+	}
+	if q.Ref != "" {
+		args = append(args, q.Ref)
+	}
+	args = append(args, "--")
+	args = append(args, q.Paths...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git log failed: %s", string(exitErr.Stderr))
 		}
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	var commits []diffview.CommitMeta
+	for _, record := range strings.Split(string(output), "\x1e") {
+		lines := splitLines(record)
+		if len(lines) == 0 {
+			continue
+		}
+		parts := strings.SplitN(lines[0], "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("git log returned bad date %q: %w", parts[2], err)
+		}
+		commit := diffview.CommitMeta{Hash: parts[0], Author: parts[1], Time: date}
+		for _, stat := range lines[1:] {
+			commit.Lines += parseShortStat(stat)
+		}
+		commits = append(commits, commit)
 	}
-	return hashes, nil
+	return commits, nil
+}
+
+var shortStatCount = regexp.MustCompile(`(\d+) (?:insertion|deletion)`)
+
+// parseShortStat returns the lines added and deleted in a --shortstat line
+// such as " 2 files changed, 10 insertions(+), 3 deletions(-)".
+func parseShortStat(line string) int {
+	total := 0
+	for _, m := range shortStatCount.FindAllStringSubmatch(line, -1) {
+		n, _ := strconv.Atoi(m[1]) // The pattern only matches digits
+		total += n
+	}
+	return total
 }
 
 // CommitsInRange returns commits between base and head (base exclusive, head inclusive).
@@ -113,11 +174,10 @@ func (r *Runner) CommitsInRange(ctx context.Context, repoPath, base, head string
 		return nil, fmt.Errorf("git log failed: %w", err)
 	}
 
-	trimmed := strings.TrimSpace(string(output))
-	if trimmed == "" {
+	lines := splitLines(string(output))
+	if len(lines) == 0 {
 		return nil, nil
 	}
-	lines := strings.Split(trimmed, "\n")
 	commits := make([]diffview.CommitBrief, 0, len(lines))
 	for _, line := range lines {
 		parts := strings.SplitN(line, "\x00", 2)
@@ -143,7 +203,8 @@ func (r *Runner) DiffRange(ctx context.Context, repoPath, base, head string) (st
 // Diff returns the diff for a raw range specification.
 // The rangeSpec is passed directly to git diff.
 func (r *Runner) Diff(ctx context.Context, repoPath, rangeSpec string) (string, error) {
-	args := []string{"-C", repoPath, "diff", rangeSpec}
+	args := append([]string{"-C", repoPath, "diff"}, patchFlags()...)
+	args = append(args, rangeSpec)
 	cmd := exec.CommandContext(ctx, "git", args...)
 	output, err := cmd.Output()
 	if err != nil {
@@ -204,3 +265,228 @@ func (r *Runner) DefaultBranch(ctx context.Context, repoPath string) (string, er
 	branch := strings.TrimPrefix(ref, "refs/remotes/origin/")
 	return branch, nil
 }
+
+// Clone copies the repository at url into dir with its full history,
+// without checking out files.
+func (r *Runner) Clone(ctx context.Context, url, dir string) error {
+	args := []string{"clone", "--quiet", "--no-checkout", "--", url, dir}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if _, err := cmd.Output(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("git clone failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+	return nil
+}
+
+// RemoteURL returns the fetch URL configured for the named remote.
+func (r *Runner) RemoteURL(ctx context.Context, repoPath, remote string) (string, error) {
+	args := []string{"-C", repoPath, "remote", "get-url", remote}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git remote get-url failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git remote get-url failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResolveCommit returns the full hash of the commit ref points to.
+func (r *Runner) ResolveCommit(ctx context.Context, repoPath, ref string) (string, error) {
+	args := []string{"-C", repoPath, "rev-parse", "--verify", ref + "^{commit}"}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git rev-parse failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// FileAt returns the content of the file at path, relative to the root of
+// the repository, as of ref.
+func (r *Runner) FileAt(ctx context.Context, repoPath, ref, path string) ([]byte, error) {
+	args := []string{"-C", repoPath, "show", ref + ":" + path}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git show failed: %s", string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("git show failed: %w", err)
+	}
+	return output, nil
+}
+
+// WorktreeDiff returns the output of git diff run with args, such as the
+// unstaged changes in the working tree when args is empty.
+func (r *Runner) WorktreeDiff(ctx context.Context, repoPath string, args ...string) (string, error) {
+	args = append(append([]string{"-C", repoPath, "diff"}, patchFlags()...), args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git diff failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// TopLevel returns the root directory of the working tree containing repoPath.
+func (r *Runner) TopLevel(ctx context.Context, repoPath string) (string, error) {
+	args := []string{"-C", repoPath, "rev-parse", "--show-toplevel"}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git rev-parse failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GitPath returns the absolute path of name inside the repository's git
+// directory, such as COMMIT_EDITMSG, resolved for linked worktrees.
+func (r *Runner) GitPath(ctx context.Context, repoPath, name string) (string, error) {
+	args := []string{"-C", repoPath, "rev-parse", "--path-format=absolute", "--git-path", name}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git rev-parse failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DiffFiles returns the diff between two files, which needn't be in a
+// repository, such as the versions of a file git hands an external diff
+// program. Either may be /dev/null for an added or deleted file.
+func (r *Runner) DiffFiles(ctx context.Context, oldFile, newFile string) (string, error) {
+	// External diff programs must not be run again, or git would call back
+	// into the program asking for this diff
+	args := []string{"diff", "--no-index", "--no-ext-diff", "--no-color", "--", oldFile, newFile}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			// Exit status 1 means the files differ, unless there's no diff
+			// because git couldn't read them
+			if exitErr.ExitCode() == 1 && len(output) > 0 {
+				return string(output), nil
+			}
+			return "", fmt.Errorf("git diff --no-index failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git diff --no-index failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// TextConv returns the textconv command that the diff attribute of path
+// selects in the repository at repoPath, or "" if there is none.
+func (r *Runner) TextConv(ctx context.Context, repoPath, path string) (string, error) {
+	args := []string{"-C", repoPath, "check-attr", "diff", "--", path}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git check-attr failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git check-attr failed: %w", err)
+	}
+	// Output is like "x.plist: diff: plist"; set, unset and unspecified
+	// name no driver
+	line := strings.TrimSpace(string(output))
+	driver := line[strings.LastIndex(line, ": ")+2:]
+	switch driver {
+	case "set", "unset", "unspecified":
+		return "", nil
+	}
+
+	args = []string{"-C", repoPath, "config", "--get", "diff." + driver + ".textconv"}
+	cmd = exec.CommandContext(ctx, "git", args...)
+	output, err = cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			// Exit status 1 means the driver has no textconv
+			if exitErr.ExitCode() == 1 {
+				return "", nil
+			}
+			return "", fmt.Errorf("git config failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git config failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ConvertText runs a textconv command on file the way git does, through the
+// shell with the file name appended, and returns its output.
+func (r *Runner) ConvertText(ctx context.Context, command, file string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command+` "$@"`, command, file)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("textconv %q failed: %s", command, string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("textconv %q failed: %w", command, err)
+	}
+	return string(output), nil
+}
+
+// SetConfig sets a git configuration value in the user's global config when
+// global is true, or else in the config of the repository at repoPath.
+func (r *Runner) SetConfig(ctx context.Context, repoPath string, global bool, key, value string) error {
+	args := []string{"-C", repoPath, "config"}
+	if global {
+		args = append(args, "--global")
+	}
+	args = append(args, key, value)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if _, err := cmd.Output(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("git config %s failed: %s", key, string(exitErr.Stderr))
+		}
+		return fmt.Errorf("git config %s failed: %w", key, err)
+	}
+	return nil
+}
+
+// Apply applies patch to the working tree of the repository at repoPath, or
+// reverses it if reverse is set. Paths in the patch are relative to the
+// repository root. Nothing is changed unless the whole patch applies.
+func (r *Runner) Apply(ctx context.Context, repoPath, patch string, reverse bool) error {
+	args := []string{"-C", repoPath, "apply"}
+	if reverse {
+		args = append(args, "-R")
+	}
+	cmd := exec.CommandContext(ctx, "git", append(args, "-")...)
+	cmd.Stdin = strings.NewReader(patch)
+	if _, err := cmd.Output(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("git apply failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fmt.Errorf("git apply failed: %w", err)
+	}
+	return nil
+}
+
+// splitLines splits git output into non-empty lines, tolerating CRLF line
+// endings (e.g. from git for Windows with core.autocrlf or wrapper scripts).
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/git"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -127,6 +129,81 @@ func TestRunner_MergeCommits(t *testing.T) {
 	})
 }
 
+func TestRunner_History(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTestRepo(t)
+	commitAs := func(author, date, file, content string) {
+		writeFile(t, dir, file, content)
+		runGit(t, dir, "add", ".")
+		cmd := exec.Command("git", "commit", "-m", "Change "+file, "--author", author+" <a@example.com>", "--date", date)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	runGit(t, dir, "checkout", "-b", "feature")
+	commitAs("Ann", "2024-01-10T12:00:00Z", "a.txt", "one\ntwo\n")
+	commitAs("Bob", "2024-03-10T12:00:00Z", "b.txt", "one\n")
+	runGit(t, dir, "checkout", "main")
+	runGit(t, dir, "merge", "--no-ff", "-m", "Merge feature", "feature")
+
+	runner := git.NewRunner()
+	ctx := context.Background()
+
+	t.Run("returns non-merge commits with authors, dates and sizes", func(t *testing.T) {
+		t.Parallel()
+
+		commits, err := runner.History(ctx, dir, diffview.HistoryQuery{Ref: "feature", Until: "2025-01-01"})
+
+		require.NoError(t, err)
+		require.Len(t, commits, 2)
+		assert.Equal(t, "Bob", commits[0].Author)
+		assert.Equal(t, 1, commits[0].Lines)
+		assert.Equal(t, "Ann", commits[1].Author)
+		assert.Equal(t, 2, commits[1].Lines)
+		assert.Equal(t, time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC), commits[1].Time.UTC())
+	})
+
+	t.Run("filters by date", func(t *testing.T) {
+		t.Parallel()
+
+		commits, err := runner.History(ctx, dir, diffview.HistoryQuery{Since: "2024-01-01", Until: "2024-02-01"})
+
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+		assert.Equal(t, "Ann", commits[0].Author)
+	})
+
+	t.Run("filters by path", func(t *testing.T) {
+		t.Parallel()
+
+		commits, err := runner.History(ctx, dir, diffview.HistoryQuery{Paths: []string{"b.txt"}})
+
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+		assert.Equal(t, "Bob", commits[0].Author)
+	})
+
+	t.Run("sizes merges by what they brought in", func(t *testing.T) {
+		t.Parallel()
+
+		commits, err := runner.History(ctx, dir, diffview.HistoryQuery{Merges: true})
+
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+		assert.Equal(t, 3, commits[0].Lines)
+	})
+
+	t.Run("reports an unknown branch", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := runner.History(ctx, dir, diffview.HistoryQuery{Ref: "missing"})
+
+		require.Error(t, err)
+	})
+}
+
 func TestRunner_CommitsInRange(t *testing.T) {
 	t.Parallel()
 
@@ -377,3 +454,275 @@ func TestRunner_DefaultBranch(t *testing.T) {
 		assert.Contains(t, err.Error(), "origin/HEAD not set")
 	})
 }
+
+func TestRunner_Clone(t *testing.T) {
+	t.Parallel()
+
+	t.Run("copies history without checking out files", func(t *testing.T) {
+		t.Parallel()
+		source := setupTestRepo(t)
+		dir := filepath.Join(t.TempDir(), "clone")
+
+		runner := git.NewRunner()
+		ctx := context.Background()
+
+		err := runner.Clone(ctx, source, dir)
+
+		require.NoError(t, err)
+		hashes, err := runner.Log(ctx, dir, 10)
+		require.NoError(t, err)
+		assert.Len(t, hashes, 1)
+		assert.NoFileExists(t, filepath.Join(dir, "README.md"))
+	})
+
+	t.Run("reports a missing repository", func(t *testing.T) {
+		t.Parallel()
+
+		err := git.NewRunner().Clone(context.Background(), filepath.Join(t.TempDir(), "missing"), t.TempDir()+"/clone")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "git clone failed")
+	})
+}
+
+func TestRunner_RemoteURL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the remote's URL", func(t *testing.T) {
+		t.Parallel()
+		dir := setupTestRepo(t)
+		runGit(t, dir, "remote", "add", "origin", "git@github.com:owner/repo.git")
+
+		url, err := git.NewRunner().RemoteURL(context.Background(), dir, "origin")
+
+		require.NoError(t, err)
+		assert.Equal(t, "git@github.com:owner/repo.git", url)
+	})
+
+	t.Run("fails for unknown remotes", func(t *testing.T) {
+		t.Parallel()
+		dir := setupTestRepo(t)
+
+		_, err := git.NewRunner().RemoteURL(context.Background(), dir, "origin")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRunner_ResolveCommit(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTestRepo(t)
+	head := strings.TrimSpace(runGit(t, dir, "rev-parse", "HEAD"))
+	runGit(t, dir, "tag", "-a", "v1", "-m", "release")
+
+	runner := git.NewRunner()
+	ctx := context.Background()
+
+	// Annotated tags resolve to the commit they point at
+	for _, ref := range []string{"HEAD", "main", "v1"} {
+		hash, err := runner.ResolveCommit(ctx, dir, ref)
+		require.NoError(t, err, "ref %q", ref)
+		assert.Equal(t, head, hash, "ref %q", ref)
+	}
+
+	_, err := runner.ResolveCommit(ctx, dir, "no-such-branch")
+	assert.Error(t, err)
+}
+
+func TestRunner_FileAt(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTestRepo(t)
+	writeFile(t, dir, "README.md", "# Changed\n")
+
+	content, err := git.NewRunner().FileAt(context.Background(), dir, "HEAD", "README.md")
+
+	require.NoError(t, err)
+	assert.Equal(t, "# Test Repo\n", string(content))
+
+	_, err = git.NewRunner().FileAt(context.Background(), dir, "HEAD", "missing.md")
+	assert.Error(t, err)
+}
+
+func TestRunner_WorktreeDiff(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTestRepo(t)
+	writeFile(t, dir, "README.md", "# Test Repo\n\nMore docs\n")
+	writeFile(t, dir, "notes.txt", "staged\n")
+	runGit(t, dir, "add", "notes.txt")
+
+	runner := git.NewRunner()
+	ctx := context.Background()
+
+	// Without args only unstaged changes show
+	diff, err := runner.WorktreeDiff(ctx, dir)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+More docs")
+	assert.NotContains(t, diff, "notes.txt")
+
+	diff, err = runner.WorktreeDiff(ctx, dir, "--cached")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+staged")
+	assert.NotContains(t, diff, "README.md")
+}
+
+func TestRunner_TopLevel(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTestRepo(t)
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0755))
+
+	top, err := git.NewRunner().TopLevel(context.Background(), sub)
+
+	require.NoError(t, err)
+	want, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	got, err := filepath.EvalSymlinks(top)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestRunner_GitPath(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTestRepo(t)
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0755))
+
+	path, err := git.NewRunner().GitPath(context.Background(), sub, "COMMIT_EDITMSG")
+
+	require.NoError(t, err)
+	want, err := filepath.EvalSymlinks(filepath.Join(dir, ".git"))
+	require.NoError(t, err)
+	got, err := filepath.EvalSymlinks(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, "COMMIT_EDITMSG", filepath.Base(path))
+}
+
+func TestRunner_DiffFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "old.txt", "one\ntwo\n")
+	writeFile(t, dir, "new.txt", "one\nthree\n")
+	runner := git.NewRunner()
+	ctx := context.Background()
+
+	diff, err := runner.DiffFiles(ctx, filepath.Join(dir, "old.txt"), filepath.Join(dir, "new.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, diff, "-two")
+	assert.Contains(t, diff, "+three")
+
+	// Added files are diffed against /dev/null
+	diff, err = runner.DiffFiles(ctx, os.DevNull, filepath.Join(dir, "new.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, diff, "new file mode")
+
+	diff, err = runner.DiffFiles(ctx, filepath.Join(dir, "old.txt"), filepath.Join(dir, "old.txt"))
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	_, err = runner.DiffFiles(ctx, filepath.Join(dir, "missing.txt"), filepath.Join(dir, "new.txt"))
+	assert.Error(t, err)
+}
+
+func TestRunner_SetConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTestRepo(t)
+
+	err := git.NewRunner().SetConfig(context.Background(), dir, false, "pager.diff", "diffview")
+
+	require.NoError(t, err)
+	assert.Equal(t, "diffview\n", runGit(t, dir, "config", "pager.diff"))
+}
+
+func TestRunner_Apply(t *testing.T) {
+	t.Parallel()
+
+	patch := `diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,1 +1,1 @@
+-# Test Repo
++# Patched Repo
+`
+
+	t.Run("applies and reverts a patch in the working tree", func(t *testing.T) {
+		t.Parallel()
+		dir := setupTestRepo(t)
+		runner := git.NewRunner()
+
+		require.NoError(t, runner.Apply(context.Background(), dir, patch, false))
+		content, err := os.ReadFile(filepath.Join(dir, "README.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "# Patched Repo\n", string(content))
+
+		require.NoError(t, runner.Apply(context.Background(), dir, patch, true))
+		content, err = os.ReadFile(filepath.Join(dir, "README.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "# Test Repo\n", string(content))
+	})
+
+	t.Run("reports a patch that doesn't apply", func(t *testing.T) {
+		t.Parallel()
+		dir := setupTestRepo(t)
+
+		err := git.NewRunner().Apply(context.Background(), dir, patch, true)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "git apply failed")
+	})
+}
+
+// setupTextConvRepo returns a repo where *.dat files diff through a textconv
+// driver that prefixes each line with "T:".
+func setupTextConvRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := setupTestRepo(t)
+	runGit(t, dir, "config", "diff.tagged.textconv", "sed s/^/T:/")
+	writeFile(t, dir, ".gitattributes", "*.dat diff=tagged\n")
+	writeFile(t, dir, "data.dat", "one\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "Add data")
+	return dir
+}
+
+func TestRunner_WorktreeDiff_AppliesTextConvAndSkipsExternalDiff(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTextConvRepo(t)
+	runGit(t, dir, "config", "diff.external", "false")
+	writeFile(t, dir, "data.dat", "two\n")
+
+	diff, err := git.NewRunner().WorktreeDiff(context.Background(), dir)
+
+	require.NoError(t, err)
+	assert.Contains(t, diff, "-T:one")
+	assert.Contains(t, diff, "+T:two")
+}
+
+func TestRunner_TextConv(t *testing.T) {
+	t.Parallel()
+
+	dir := setupTextConvRepo(t)
+	runner := git.NewRunner()
+	ctx := context.Background()
+
+	command, err := runner.TextConv(ctx, dir, "sub/other.dat")
+	require.NoError(t, err)
+	assert.Equal(t, "sed s/^/T:/", command)
+
+	command, err = runner.TextConv(ctx, dir, "README.md")
+	require.NoError(t, err)
+	assert.Empty(t, command)
+
+	out, err := runner.ConvertText(ctx, "sed s/^/T:/", filepath.Join(dir, "data.dat"))
+	require.NoError(t, err)
+	assert.Equal(t, "T:one\n", out)
+}
@@ -0,0 +1,272 @@
+package gitdiff
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// trimCombinedHeader strips the "diff --cc " or "diff --combined " prefix git
+// uses for combined diff file headers. The boolean reports whether a prefix
+// was present.
+func trimCombinedHeader(line string) (string, bool) {
+	if rest, ok := strings.CutPrefix(line, "diff --cc "); ok {
+		return rest, true
+	}
+	return strings.CutPrefix(line, "diff --combined ")
+}
+
+// chunk is a contiguous run of diff text, either regular or combined.
+type chunk struct {
+	text     string
+	combined bool
+}
+
+// splitChunks splits diff text at file headers, grouping consecutive regular
+// file sections together so they can be handed to go-gitdiff in one pass.
+// Any preamble before the first header is kept with the first regular chunk.
+func splitChunks(input string) []chunk {
+	var chunks []chunk
+	var current strings.Builder
+	currentCombined := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, chunk{text: current.String(), combined: currentCombined})
+			current.Reset()
+		}
+	}
+
+	for _, line := range strings.SplitAfter(input, "\n") {
+		if strings.HasPrefix(line, "diff --") {
+			_, combined := trimCombinedHeader(line)
+			// Every combined section stands alone; regular sections are grouped.
+			if combined || currentCombined {
+				flush()
+			}
+			currentCombined = combined
+		}
+		current.WriteString(line)
+	}
+	flush()
+
+	return chunks
+}
+
+// parseCombinedFile parses a single "diff --cc" or "diff --combined" section.
+func parseCombinedFile(text string) (diffview.FileDiff, error) {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var fd diffview.FileDiff
+	header, _ := trimCombinedHeader(strings.TrimSuffix(lines[0], "\n"))
+	name := unquotePath(header)
+	fd.OldPath = name
+	fd.NewPath = name
+	fd.Operation = diffview.FileModified
+
+	i := 1
+	// Extended headers run until the first hunk.
+	for ; i < len(lines) && !strings.HasPrefix(lines[i], "@@@"); i++ {
+		line := strings.TrimSuffix(lines[i], "\n")
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			if path := strings.TrimPrefix(line, "--- "); path == "/dev/null" {
+				fd.OldPath = ""
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if path := strings.TrimPrefix(line, "+++ "); path == "/dev/null" {
+				fd.NewPath = ""
+			}
+		case strings.HasPrefix(line, "new file mode "):
+			fd.Operation = diffview.FileAdded
+			fd.NewMode = parseMode(strings.TrimPrefix(line, "new file mode "))
+		case strings.HasPrefix(line, "deleted file mode "):
+			fd.Operation = diffview.FileDeleted
+		case strings.HasPrefix(line, "mode "):
+			// "mode 100644,100644..100755"
+			if idx := strings.Index(line, ".."); idx != -1 {
+				fd.NewMode = parseMode(line[idx+2:])
+			}
+		case strings.HasPrefix(line, "Binary files "):
+			fd.IsBinary = true
+		}
+		fd.Extended = append(fd.Extended, line)
+	}
+
+	for i < len(lines) {
+		hunk, next, err := parseCombinedHunk(lines, i)
+		if err != nil {
+			return diffview.FileDiff{}, fmt.Errorf("%s: %w", name, err)
+		}
+		fd.Hunks = append(fd.Hunks, hunk)
+		i = next
+	}
+
+	return fd, nil
+}
+
+// parseCombinedHunk parses a hunk starting at lines[start] and returns it
+// along with the index of the first line after the hunk.
+func parseCombinedHunk(lines []string, start int) (diffview.Hunk, int, error) {
+	header := strings.TrimSuffix(lines[start], "\n")
+	hunk, err := parseCombinedHunkHeader(header)
+	if err != nil {
+		return diffview.Hunk{}, 0, err
+	}
+	parents := len(hunk.Parents)
+
+	oldLineNum := hunk.OldStart
+	newLineNum := hunk.NewStart
+
+	i := start + 1
+	for ; i < len(lines) && !strings.HasPrefix(lines[i], "@@@"); i++ {
+		raw := lines[i]
+		if strings.HasPrefix(raw, `\`) {
+			// "\ No newline at end of file" applies to the preceding line
+			if n := len(hunk.Lines); n > 0 {
+				hunk.Lines[n-1].NoNewline = true
+			}
+			continue
+		}
+		if body := strings.TrimSuffix(raw, "\n"); len(body) < parents {
+			// Tolerate blank lines whose marker columns were trimmed
+			raw = body + strings.Repeat(" ", parents-len(body)) + raw[len(body):]
+		}
+
+		line := diffview.Line{
+			Content: raw[parents:],
+			Markers: make([]diffview.LineType, parents),
+		}
+		var added, deleted bool
+		for p := 0; p < parents; p++ {
+			switch raw[p] {
+			case '+':
+				line.Markers[p] = diffview.LineAdded
+				added = true
+			case '-':
+				line.Markers[p] = diffview.LineDeleted
+				deleted = true
+			case ' ':
+				line.Markers[p] = diffview.LineContext
+			default:
+				return diffview.Hunk{}, 0, fmt.Errorf("invalid combined diff line %q", strings.TrimSuffix(lines[i], "\n"))
+			}
+		}
+
+		switch {
+		case deleted:
+			line.Type = diffview.LineDeleted
+			if line.Markers[0] == diffview.LineDeleted {
+				line.OldLineNum = oldLineNum
+				oldLineNum++
+			}
+		case added:
+			line.Type = diffview.LineAdded
+			line.NewLineNum = newLineNum
+			if line.Markers[0] != diffview.LineAdded {
+				line.OldLineNum = oldLineNum
+				oldLineNum++
+			}
+			newLineNum++
+		default:
+			line.Type = diffview.LineContext
+			line.OldLineNum = oldLineNum
+			line.NewLineNum = newLineNum
+			oldLineNum++
+			newLineNum++
+		}
+
+		hunk.Lines = append(hunk.Lines, line)
+	}
+
+	return hunk, i, nil
+}
+
+// parseCombinedHunkHeader parses "@@@ -A,B -C,D +E,F @@@ section".
+// The number of '@' characters is one more than the number of parents.
+func parseCombinedHunkHeader(header string) (diffview.Hunk, error) {
+	marks := 0
+	for marks < len(header) && header[marks] == '@' {
+		marks++
+	}
+	closing := strings.Repeat("@", marks)
+	rest := strings.TrimPrefix(header[marks:], " ")
+	end := strings.Index(rest, " "+closing)
+	if marks < 3 || end == -1 {
+		return diffview.Hunk{}, fmt.Errorf("invalid combined hunk header %q", header)
+	}
+
+	var hunk diffview.Hunk
+	hunk.Section = strings.TrimSpace(rest[end+1+marks:])
+
+	fields := strings.Fields(rest[:end])
+	if len(fields) != marks {
+		return diffview.Hunk{}, fmt.Errorf("invalid combined hunk header %q", header)
+	}
+	for _, field := range fields[:marks-1] {
+		if !strings.HasPrefix(field, "-") {
+			return diffview.Hunk{}, fmt.Errorf("invalid combined hunk header %q", header)
+		}
+		r, err := parseRange(field[1:])
+		if err != nil {
+			return diffview.Hunk{}, fmt.Errorf("invalid combined hunk header %q: %w", header, err)
+		}
+		hunk.Parents = append(hunk.Parents, r)
+	}
+	result := fields[marks-1]
+	if !strings.HasPrefix(result, "+") {
+		return diffview.Hunk{}, fmt.Errorf("invalid combined hunk header %q", header)
+	}
+	r, err := parseRange(result[1:])
+	if err != nil {
+		return diffview.Hunk{}, fmt.Errorf("invalid combined hunk header %q: %w", header, err)
+	}
+
+	hunk.OldStart = hunk.Parents[0].Start
+	hunk.OldCount = hunk.Parents[0].Count
+	hunk.NewStart = r.Start
+	hunk.NewCount = r.Count
+	return hunk, nil
+}
+
+// parseRange parses "start,count" or "start" (count defaults to 1).
+func parseRange(s string) (diffview.HunkRange, error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return diffview.HunkRange{}, err
+	}
+	count := 1
+	if hasCount {
+		count, err = strconv.Atoi(countStr)
+		if err != nil {
+			return diffview.HunkRange{}, err
+		}
+	}
+	return diffview.HunkRange{Start: start, Count: count}, nil
+}
+
+// parseMode parses an octal git file mode such as "100644".
+func parseMode(s string) fs.FileMode {
+	mode, err := strconv.ParseUint(strings.TrimSpace(s), 8, 32)
+	if err != nil {
+		return 0
+	}
+	return fs.FileMode(mode)
+}
+
+// unquotePath removes C-style quoting git applies to unusual paths.
+func unquotePath(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package gitdiff
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// commitHeader matches the line that starts each commit in git log -p and
+// git show output, such as "commit 1a2b3c4 (HEAD -> main)".
+var commitHeader = regexp.MustCompile(`^commit ([0-9a-f]{7,64})\b`)
+
+// commitText is the diff text of one commit, or of input with no commits.
+type commitText struct {
+	commit *diffview.Commit // nil for text before the first commit
+	text   string
+}
+
+// splitCommits splits input at commit headers, parsing each commit's
+// header and message and leaving the diff that follows as its text. Input
+// without commit headers comes back whole as a single part.
+func splitCommits(input string) []commitText {
+	lines := strings.SplitAfter(input, "\n")
+	var parts []commitText
+	var text strings.Builder
+	var commit *diffview.Commit
+	flush := func() {
+		if commit != nil || text.Len() > 0 {
+			parts = append(parts, commitText{commit: commit, text: text.String()})
+		}
+		text.Reset()
+	}
+
+	for i := 0; i < len(lines); i++ {
+		m := commitHeader.FindStringSubmatch(lines[i])
+		if m == nil {
+			text.WriteString(lines[i])
+			continue
+		}
+		flush()
+		commit = &diffview.Commit{Hash: m[1]}
+		i = parseCommitHeader(lines, i+1, commit) - 1
+	}
+	flush()
+	return parts
+}
+
+// parseCommitHeader reads the header fields and message following a commit
+// line, starting at lines[start], and returns the index of the first line
+// after them.
+func parseCommitHeader(lines []string, start int, commit *diffview.Commit) int {
+	i := start
+	// Fields like "Author: ..." run up to the blank line before the message
+	for ; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\n")
+		if line == "" {
+			i++
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return i
+		}
+		switch name {
+		case "Author":
+			commit.Author = strings.TrimSpace(value)
+		case "Date", "AuthorDate":
+			commit.Date = strings.TrimSpace(value)
+		}
+	}
+
+	// The message is indented by four spaces; blank lines may separate
+	// paragraphs and end the message
+	var message []string
+	for ; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\n")
+		if body, ok := strings.CutPrefix(line, "    "); ok {
+			message = append(message, body)
+			continue
+		}
+		if line != "" {
+			break
+		}
+		message = append(message, "")
+	}
+	commit.Message = strings.TrimSpace(strings.Join(message, "\n"))
+	return i
+}
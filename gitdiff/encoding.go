@@ -0,0 +1,158 @@
+package gitdiff
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Encoding names recorded in FileDiff.Encoding.
+const (
+	encodingUTF16LE = "utf-16le"
+	encodingUTF16BE = "utf-16be"
+	encodingLatin1  = "latin-1"
+)
+
+// nulRatioUTF16 is the fraction of NUL bytes above which content is treated
+// as UTF-16. Mostly-ASCII UTF-16 text is close to half NULs.
+const nulRatioUTF16 = 0.3
+
+// transcodeFile detects the encoding of a file's line content and rewrites
+// the content as UTF-8, recording the detected encoding on the file.
+// Files that are already valid UTF-8 are left untouched.
+func transcodeFile(fd *diffview.FileDiff) {
+	encoding := detectEncoding(fd.Hunks)
+	if encoding == "" {
+		return
+	}
+	fd.Encoding = encoding
+
+	for h := range fd.Hunks {
+		for l := range fd.Hunks[h].Lines {
+			line := &fd.Hunks[h].Lines[l]
+			line.Content = transcode(line.Content, encoding)
+		}
+	}
+}
+
+// detectEncoding inspects the line content of all of a file's hunks and
+// returns the source encoding, or "" for UTF-8. Git splits lines on the
+// 0x0A byte, so UTF-16 content arrives as NUL-heavy byte strings rather
+// than as invalid UTF-8.
+//
+// The byte order comes from the byte order mark when a hunk starts the
+// file, and otherwise from where the newline's NUL byte lands: at the start
+// of little-endian lines, other than the file's first, and at the end of
+// big-endian ones. Where a character's other byte is NUL too, as in ASCII
+// text, both byte orders decode alike.
+func detectEncoding(hunks []diffview.Hunk) string {
+	var total, nuls int
+	valid := true
+	bom := ""
+	var littleEndian, bigEndian int
+	for _, hunk := range hunks {
+		for _, line := range hunk.Lines {
+			body, hasNewline := strings.CutSuffix(line.Content, "\n")
+			total += len(body)
+			nuls += strings.Count(body, "\x00")
+			if valid && !utf8.ValidString(body) {
+				valid = false
+			}
+			if body == "" {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(body, "\xfe\xff"):
+				bom = encodingUTF16BE
+			case strings.HasPrefix(body, "\xff\xfe"):
+				bom = encodingUTF16LE
+			}
+			if body[0] != 0 && line.OldLineNum != 1 && line.NewLineNum != 1 {
+				bigEndian++
+			}
+			if hasNewline && body[len(body)-1] != 0 {
+				littleEndian++
+			}
+		}
+	}
+
+	switch {
+	case total > 0 && float64(nuls)/float64(total) >= nulRatioUTF16:
+		if bom != "" {
+			return bom
+		}
+		if bigEndian > littleEndian {
+			return encodingUTF16BE
+		}
+		return encodingUTF16LE
+	case !valid:
+		// Latin-1 maps every byte to a code point, so it never fails
+		return encodingLatin1
+	default:
+		return ""
+	}
+}
+
+// transcode converts one line of content from encoding to UTF-8,
+// preserving a trailing newline.
+func transcode(content, encoding string) string {
+	body, hasNewline := strings.CutSuffix(content, "\n")
+
+	var out string
+	switch encoding {
+	case encodingUTF16LE, encodingUTF16BE:
+		out = decodeUTF16(body, encoding == encodingUTF16BE)
+	case encodingLatin1:
+		out = decodeLatin1(body)
+	default:
+		out = body
+	}
+
+	if hasNewline {
+		out += "\n"
+	}
+	return out
+}
+
+// decodeUTF16 decodes a UTF-16 line. The newline's second byte ends up on
+// the wrong side of the split: at the start of the next line for
+// little-endian and at the end of the line for big-endian.
+func decodeUTF16(body string, bigEndian bool) string {
+	if len(body)%2 == 1 {
+		if bigEndian {
+			body = strings.TrimSuffix(body, "\x00")
+		} else {
+			body = strings.TrimPrefix(body, "\x00")
+		}
+	}
+	// Drop the newline's stray byte if it is still unbalanced
+	if len(body)%2 == 1 {
+		body = body[:len(body)-1]
+	}
+
+	units := make([]uint16, 0, len(body)/2)
+	for i := 0; i+1 < len(body); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(body[i])<<8|uint16(body[i+1]))
+		} else {
+			units = append(units, uint16(body[i+1])<<8|uint16(body[i]))
+		}
+	}
+	// Strip the byte order mark
+	if len(units) > 0 && units[0] == 0xfeff {
+		units = units[1:]
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodeLatin1 maps each byte to the code point of the same value.
+func decodeLatin1(body string) string {
+	var sb strings.Builder
+	sb.Grow(len(body))
+	for i := 0; i < len(body); i++ {
+		sb.WriteRune(rune(body[i]))
+	}
+	return sb.String()
+}
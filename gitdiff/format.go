@@ -0,0 +1,104 @@
+package gitdiff
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Format writes diff as a git patch that git apply accepts. Binary files
+// and combined (merge) hunks have no patch text and are left out.
+func Format(diff *diffview.Diff) string {
+	var sb strings.Builder
+	for _, file := range diff.Files {
+		if file.IsBinary {
+			continue
+		}
+		writeFileHeader(&sb, file)
+		for _, hunk := range file.Hunks {
+			if hunk.IsCombined() {
+				continue
+			}
+			writeHunk(&sb, hunk)
+		}
+	}
+	return sb.String()
+}
+
+// writeFileHeader writes the diff --git line, extended headers and ---/+++
+// lines for file.
+func writeFileHeader(sb *strings.Builder, file diffview.FileDiff) {
+	oldPath, newPath := file.OldPath, file.NewPath
+	if oldPath == "" {
+		oldPath = newPath
+	}
+	if newPath == "" {
+		newPath = oldPath
+	}
+	fmt.Fprintf(sb, "diff --git a/%s b/%s\n", oldPath, newPath)
+
+	switch file.Operation {
+	case diffview.FileAdded:
+		fmt.Fprintf(sb, "new file mode %o\n", modeOr(file.NewMode))
+	case diffview.FileDeleted:
+		fmt.Fprintf(sb, "deleted file mode %o\n", modeOr(file.OldMode))
+	default:
+		if file.OldMode != 0 && file.NewMode != 0 && file.OldMode != file.NewMode {
+			fmt.Fprintf(sb, "old mode %o\nnew mode %o\n", uint32(file.OldMode), uint32(file.NewMode))
+		}
+		switch file.Operation {
+		case diffview.FileRenamed:
+			fmt.Fprintf(sb, "rename from %s\nrename to %s\n", oldPath, newPath)
+		case diffview.FileCopied:
+			fmt.Fprintf(sb, "copy from %s\ncopy to %s\n", oldPath, newPath)
+		}
+	}
+
+	if len(file.Hunks) == 0 {
+		return
+	}
+	from, to := "a/"+oldPath, "b/"+newPath
+	if file.Operation == diffview.FileAdded {
+		from = "/dev/null"
+	}
+	if file.Operation == diffview.FileDeleted {
+		to = "/dev/null"
+	}
+	fmt.Fprintf(sb, "--- %s\n+++ %s\n", from, to)
+}
+
+// modeOr returns mode as git writes it, or a regular file's mode if the
+// mode is unknown.
+func modeOr(mode fs.FileMode) uint32 {
+	if mode == 0 {
+		return 0o100644
+	}
+	return uint32(mode)
+}
+
+// writeHunk writes a hunk's header and lines, marking lines without a
+// trailing newline as git does.
+func writeHunk(sb *strings.Builder, hunk diffview.Hunk) {
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@", hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount)
+	if hunk.Section != "" {
+		sb.WriteString(" " + hunk.Section)
+	}
+	sb.WriteString("\n")
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case diffview.LineAdded:
+			sb.WriteString("+")
+		case diffview.LineDeleted:
+			sb.WriteString("-")
+		default:
+			sb.WriteString(" ")
+		}
+		sb.WriteString(strings.TrimSuffix(line.Content, "\n"))
+		sb.WriteString("\n")
+		if line.NoNewline {
+			sb.WriteString("\\ No newline at end of file\n")
+		}
+	}
+}
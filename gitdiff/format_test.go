@@ -0,0 +1,95 @@
+package gitdiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat_RoundTripsParsedPatch(t *testing.T) {
+	t.Parallel()
+
+	patch := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@ package main
+ package main
+-func main() {}
++func main() {
++}
+\ No newline at end of file
+diff --git a/new.txt b/new.txt
+new file mode 100644
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,1 @@
++hello
+diff --git a/old.txt b/old.txt
+deleted file mode 100755
+--- a/old.txt
++++ /dev/null
+@@ -1,1 +0,0 @@
+-bye
+diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+diff --git a/before.go b/after.go
+rename from before.go
+rename to after.go
+--- a/before.go
++++ b/after.go
+@@ -1,1 +1,1 @@
+-package before
++package after
+`
+
+	diff, err := gitdiff.NewParser().Parse(strings.NewReader(patch))
+	require.NoError(t, err)
+
+	assert.Equal(t, patch, gitdiff.Format(diff))
+}
+
+func TestFormat_RoundTripsLineEndings(t *testing.T) {
+	t.Parallel()
+
+	// CRLF content in an LF patch, and last lines without a newline, are
+	// written back byte for byte so that git apply still matches them
+	patch := "diff --git a/win.txt b/win.txt\n" +
+		"--- a/win.txt\n" +
+		"+++ b/win.txt\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" first\r\n" +
+		"-last\r\n" +
+		"+last\r\n" +
+		"\\ No newline at end of file\n" +
+		"diff --git a/mixed.txt b/mixed.txt\n" +
+		"--- a/mixed.txt\n" +
+		"+++ b/mixed.txt\n" +
+		"@@ -1,1 +1,2 @@\n" +
+		"-unix\n" +
+		"+dos\r\n" +
+		"+end\r\n" +
+		"\\ No newline at end of file\n"
+
+	diff, err := gitdiff.NewParser().Parse(strings.NewReader(patch))
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 2)
+	assert.True(t, diff.Files[0].Hunks[0].Lines[0].CRLF())
+	assert.True(t, diff.Files[0].Hunks[0].Lines[2].NoNewline)
+
+	assert.Equal(t, patch, gitdiff.Format(diff))
+}
+
+func TestFormat_SkipsBinaryFiles(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{OldPath: "image.png", NewPath: "image.png", IsBinary: true},
+	}}
+
+	assert.Empty(t, gitdiff.Format(diff))
+}
@@ -0,0 +1,89 @@
+package gitdiff_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corpus returns the real-world diffs in testdata: commits, git log -p and
+// format-patch output, binary, CRLF, colored and merge conflict diffs.
+func corpus(tb testing.TB) map[string][]byte {
+	tb.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.diff"))
+	require.NoError(tb, err)
+	require.NotEmpty(tb, paths)
+	diffs := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		require.NoError(tb, err)
+		diffs[filepath.Base(path)] = data
+	}
+	return diffs
+}
+
+func TestParser_Parse_Corpus(t *testing.T) {
+	t.Parallel()
+
+	for name, data := range corpus(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diff, err := gitdiff.NewParser().Parse(bytes.NewReader(data))
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, diff.Files)
+			assert.Empty(t, diff.Skipped)
+			checkDiff(t, diff)
+		})
+	}
+}
+
+func FuzzParser_Parse(f *testing.F) {
+	for _, data := range corpus(f) {
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// A strict parse may fail, but a lenient one leaves out what fails
+		if diff, err := gitdiff.NewParser().Parse(bytes.NewReader(data)); err == nil {
+			checkDiff(t, diff)
+		}
+		diff, err := gitdiff.NewParser(gitdiff.WithLenient()).Parse(bytes.NewReader(data))
+		require.NoError(t, err)
+		checkDiff(t, diff)
+	})
+}
+
+// checkDiff checks what holds of any diff the parser returns.
+func checkDiff(t *testing.T, diff *diffview.Diff) {
+	t.Helper()
+	require.NotNil(t, diff.Files)
+	for i, c := range diff.Commits {
+		assert.LessOrEqual(t, c.FirstFile, len(diff.Files), "commit %d", i)
+		if i > 0 {
+			assert.GreaterOrEqual(t, c.FirstFile, diff.Commits[i-1].FirstFile, "commit %d", i)
+		}
+	}
+	for _, file := range diff.Files {
+		for _, hunk := range file.Hunks {
+			// Lines of a combined hunk count against each parent
+			if hunk.IsCombined() {
+				continue
+			}
+			for _, line := range hunk.Lines {
+				switch line.Type {
+				case diffview.LineAdded:
+					assert.Zero(t, line.OldLineNum, "%s: added line", file.NewPath)
+				case diffview.LineDeleted:
+					assert.Zero(t, line.NewLineNum, "%s: deleted line", file.NewPath)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package gitdiff
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/fwojciec/diffstory"
+)
+
+// parseLeniently parses text, which go-gitdiff failed to parse whole, file
+// by file, and a file that fails hunk by hunk. What still fails is left out
+// and recorded in result's Skipped sections. It returns the files parsed
+// and the text before the first of them.
+func parseLeniently(text string, result *diffview.Diff) ([]*gitdiff.File, string) {
+	preamble, sections := splitFileSections(text)
+	var files []*gitdiff.File
+	for _, section := range sections {
+		parsed, pre, err := gitdiff.Parse(strings.NewReader(section))
+		if err == nil {
+			// Text without git headers has no preamble split off before it
+			if len(files) == 0 && preamble == "" {
+				preamble = pre
+			}
+			files = append(files, parsed...)
+			continue
+		}
+		if file, ok := parseHunks(section, result); ok {
+			files = append(files, file)
+		}
+	}
+	return files, preamble
+}
+
+// parseHunks parses a file's section hunk by hunk, leaving out the hunks
+// that fail. It reports false, leaving out the whole section, if the file's
+// own header fails.
+func parseHunks(section string, result *diffview.Diff) (*gitdiff.File, bool) {
+	header, hunks := splitHunks(section)
+	parsed, _, err := gitdiff.Parse(strings.NewReader(header))
+	if err == nil && len(parsed) != 1 {
+		err = errNoFileHeader
+	}
+	if err != nil {
+		result.Skipped = append(result.Skipped, skipped("", section, err))
+		return nil, false
+	}
+
+	file := parsed[0]
+	path := file.NewName
+	if path == "" {
+		path = file.OldName
+	}
+	for _, hunk := range hunks {
+		withHunk, _, err := gitdiff.Parse(strings.NewReader(header + hunk))
+		if err != nil {
+			result.Skipped = append(result.Skipped, skipped(path, hunk, err))
+			continue
+		}
+		file.TextFragments = append(file.TextFragments, withHunk[0].TextFragments...)
+	}
+	return file, true
+}
+
+// errNoFileHeader is why a section that doesn't start a file is skipped.
+var errNoFileHeader = errors.New("no file header")
+
+// splitFileSections splits text at the "diff --git" line that starts each
+// file, returning what comes before the first apart. Text without such
+// lines, such as output of plain diff -u, is a single section.
+func splitFileSections(text string) (preamble string, sections []string) {
+	var current strings.Builder
+	started := false
+	flush := func() {
+		if started {
+			sections = append(sections, current.String())
+		} else {
+			preamble = current.String()
+		}
+		current.Reset()
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			started = true
+		}
+		current.WriteString(line)
+	}
+	if !started {
+		return "", []string{text}
+	}
+	flush()
+	return preamble, sections
+}
+
+// splitHunks splits a file's section into its header and its hunks, each
+// starting at its "@@" line.
+func splitHunks(section string) (header string, hunks []string) {
+	var current strings.Builder
+	inHunks := false
+	for _, line := range strings.SplitAfter(section, "\n") {
+		if strings.HasPrefix(line, "@@ ") {
+			if inHunks {
+				hunks = append(hunks, current.String())
+			} else {
+				header = current.String()
+			}
+			inHunks = true
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if inHunks {
+		hunks = append(hunks, current.String())
+	} else {
+		header = current.String()
+	}
+	return header, hunks
+}
+
+// lineError matches the position go-gitdiff starts its errors with, which
+// counts from the start of the part it was given rather than the diff.
+var lineError = regexp.MustCompile(`^gitdiff: line \d+: `)
+
+// skipped returns the record of leaving text, of the file at path, out for
+// err.
+func skipped(path, text string, err error) diffview.SkippedSection {
+	lines := strings.Count(text, "\n")
+	if !strings.HasSuffix(text, "\n") {
+		lines++
+	}
+	reason := lineError.ReplaceAllString(err.Error(), "")
+	reason = strings.TrimPrefix(reason, path+": ")
+	return diffview.SkippedSection{
+		Path:   path,
+		Header: firstLine(text),
+		Lines:  lines,
+		Reason: reason,
+	}
+}
+
+// firstLine returns text's first line, without its newline.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,106 @@
+package gitdiff
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkRange matches a hunk header, capturing its line counts.
+var hunkRange = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+// requoteNames rewrites the escaped backslashes in text's quoted file names
+// as octal escapes. go-gitdiff takes the closing quote of a name ending in
+// a backslash, such as "a/dir\\", for an escaped one and fails the whole
+// diff; as \134 the backslash reads the same without ending in one. Hunk
+// content is left alone, even where a line of it looks like a header.
+func requoteNames(text string) string {
+	if !strings.Contains(text, `\\`) {
+		return text
+	}
+	var sb strings.Builder
+	sb.Grow(len(text))
+	oldLeft, newLeft := 0, 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if oldLeft > 0 || newLeft > 0 {
+			content := true
+			switch {
+			case strings.HasPrefix(line, " "):
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, "+"):
+				newLeft--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				// The hunk is shorter than its header says
+				oldLeft, newLeft, content = 0, 0, false
+			}
+			if content {
+				sb.WriteString(line)
+				continue
+			}
+		}
+		if m := hunkRange.FindStringSubmatch(line); m != nil {
+			oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[2])
+		} else if isNameHeader(line) {
+			line = requoteLine(line)
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// hunkCount returns a hunk header's line count, which is 1 when omitted.
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// isNameHeader reports whether line is a header line naming a file, which
+// git quotes C-style when the name has unusual characters, such as
+// "a/weird\tname.go".
+func isNameHeader(line string) bool {
+	for _, prefix := range []string{"diff --git ", "--- ", "+++ ", "rename from ", "rename to ", "copy from ", "copy to "} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// requoteLine rewrites the escaped backslashes in line's quoted names as
+// \134, leaving the rest of the line as it is.
+func requoteLine(line string) string {
+	var sb strings.Builder
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case !quoted:
+			// A quote opens a name only at its start, not inside an unquoted one
+			quoted = c == '"' && (i == 0 || line[i-1] == ' ')
+		case c == '\\' && i+1 < len(line):
+			i++
+			if line[i] == '\\' {
+				sb.WriteString(`\134`)
+			} else {
+				sb.WriteByte(c)
+				sb.WriteByte(line[i])
+			}
+			continue
+		case c == '"':
+			quoted = false
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
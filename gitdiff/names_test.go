@@ -0,0 +1,135 @@
+package gitdiff_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gitdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitQuote quotes name with prefix the way git does in file headers with
+// core.quotePath on: C-style, with bytes outside ASCII as octal escapes,
+// and only when name has characters that need it.
+func gitQuote(prefix, name string) string {
+	var sb strings.Builder
+	quote := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+			quote = true
+		case c == '\t':
+			sb.WriteString(`\t`)
+			quote = true
+		case c == '\n':
+			sb.WriteString(`\n`)
+			quote = true
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&sb, `\%03o`, c)
+			quote = true
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	if quote {
+		return `"` + prefix + sb.String() + `"`
+	}
+	return prefix + sb.String()
+}
+
+func TestParser_Parse_FileNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"quoted with spaces", `"a/weird name.go" "b/weird name.go"`, "weird name.go"},
+		{"unquoted with spaces", `a/weird name.go b/weird name.go`, "weird name.go"},
+		{"octal escaped unicode", `"a/caf\303\251.go" "b/caf\303\251.go"`, "café.go"},
+		{"raw unicode", `a/日本語.txt b/日本語.txt`, "日本語.txt"},
+		{"escaped tab", `"a/tab\there" "b/tab\there"`, "tab\there"},
+		{"escaped quote", `"a/say \"hi\"" "b/say \"hi\""`, `say "hi"`},
+		{"ends in a backslash", `"a/dir\\" "b/dir\\"`, `dir\`},
+		{"contains b/", `a/x b/y.go b/x b/y.go`, "x b/y.go"},
+		{"starts with a/", `a/a/b.go b/a/b.go`, "a/b.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Mode changes name the file only in the diff --git line
+			input := "diff --git " + tt.header + "\nold mode 100644\nnew mode 100755\n"
+
+			diff, err := gitdiff.NewParser().Parse(strings.NewReader(input))
+
+			require.NoError(t, err)
+			require.Len(t, diff.Files, 1)
+			assert.Equal(t, tt.want, diff.Files[0].OldPath)
+			assert.Equal(t, tt.want, diff.Files[0].NewPath)
+		})
+	}
+}
+
+func TestParser_Parse_QuotedNamesInFileHeaders(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --git "a/dir\\" "b/new\\name.go"
+similarity index 90%
+rename from "dir\\"
+rename to "new\\name.go"
+--- "a/dir\\"
++++ "b/new\\name.go"
+@@ -1,2 +1,2 @@
+--- "a/dir\\"
+-old
++++ "b/dir\\"
++new
+`
+
+	diff, err := gitdiff.NewParser().Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	f := diff.Files[0]
+	assert.Equal(t, `dir\`, f.OldPath)
+	assert.Equal(t, `new\name.go`, f.NewPath)
+	assert.Equal(t, diffview.FileRenamed, f.Operation)
+	// Lines of content that look like headers are left as they are
+	require.Len(t, f.Hunks, 1)
+	assert.Equal(t, `-- "a/dir\\"`+"\n", f.Hunks[0].Lines[0].Content)
+	assert.Equal(t, `++ "b/dir\\"`+"\n", f.Hunks[0].Lines[2].Content)
+}
+
+func FuzzParser_Parse_FileNames(f *testing.F) {
+	for _, name := range []string{"main.go", "weird name.go", "café.go", "x b/y.go", `dir\`, `say "hi"`, "tab\there", "a/b/c"} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		// Git's own names: relative, without empty path elements, NUL or
+		// invalid UTF-8, which would need an encoding to compare in
+		if name == "" || strings.ContainsRune(name, 0) || !utf8.ValidString(name) ||
+			strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.Contains(name, "//") {
+			t.Skip()
+		}
+		old, new := gitQuote("a/", name), gitQuote("b/", name)
+		for _, input := range []string{
+			fmt.Sprintf("diff --git %s %s\nold mode 100644\nnew mode 100755\n", old, new),
+			fmt.Sprintf("diff --git %s %s\n--- %s\n+++ %s\n@@ -1 +1 @@\n-a\n+b\n", old, new, old, new),
+		} {
+			diff, err := gitdiff.NewParser().Parse(strings.NewReader(input))
+			require.NoError(t, err, input)
+			require.Len(t, diff.Files, 1, input)
+			assert.Equal(t, name, diff.Files[0].OldPath, input)
+			assert.Equal(t, name, diff.Files[0].NewPath, input)
+		}
+	})
+}
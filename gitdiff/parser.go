@@ -2,7 +2,10 @@
 package gitdiff
 
 import (
+	"fmt"
 	"io"
+	"regexp"
+	"strings"
 
 	"github.com/bluekeyes/go-gitdiff/gitdiff"
 	"github.com/fwojciec/diffstory"
@@ -11,33 +14,162 @@ import (
 // Compile-time interface verification.
 var _ diffview.Parser = (*Parser)(nil)
 
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithLenient makes the parser leave out what it can't parse, such as a
+// hunk with a corrupted header or a file whose header is garbled, instead
+// of failing the whole diff. What it leaves out is recorded in the diff's
+// Skipped sections.
+func WithLenient() Option {
+	return func(p *Parser) {
+		p.lenient = true
+	}
+}
+
 // Parser parses unified diff content using go-gitdiff.
-type Parser struct{}
+type Parser struct {
+	lenient bool
+}
 
-// NewParser creates a new Parser.
-func NewParser() *Parser {
-	return &Parser{}
+// NewParser creates a new Parser, which fails on malformed input unless
+// made lenient with WithLenient.
+func NewParser(opts ...Option) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // Parse reads diff content and returns the parsed result.
+// Combined diffs ("diff --cc", produced by git diff during a conflicted merge)
+// are parsed natively, since go-gitdiff skips them. Content in UTF-16 or
+// Latin-1 is transcoded to UTF-8 and the source encoding noted on the file.
+// Diffs with CRLF line endings throughout (e.g. saved on Windows) are
+// normalized to LF before parsing, and colored diffs (e.g. piped from git
+// acting as a pager) have their color codes removed. Each of these, and any
+// text ignored before the first file, is noted in the diff's warnings.
+// Output of several commits, such as from git log -p, keeps each commit's
+// header and message and which files it changed. A lenient parser leaves
+// out the files and hunks it can't parse instead of failing. File names
+// git quoted, such as "a/weird\tname.go", are unquoted.
 func (p *Parser) Parse(r io.Reader) (*diffview.Diff, error) {
-	files, _, err := gitdiff.Parse(r)
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &diffview.Diff{
-		Files: make([]diffview.FileDiff, 0, len(files)),
+	result := &diffview.Diff{}
+	text := stripColor(string(data))
+	if len(text) < len(data) {
+		result.Warnings = append(result.Warnings, "removed ANSI color codes")
+	}
+	if normalized := normalizeLineEndings(text); len(normalized) < len(text) {
+		result.Warnings = append(result.Warnings, "normalized CRLF line endings to LF")
+		text = normalized
+	}
+	for _, part := range splitCommits(text) {
+		where := "the first file"
+		if part.commit != nil {
+			part.commit.FirstFile = len(result.Files)
+			result.Commits = append(result.Commits, *part.commit)
+			where = "the first file of commit " + shortHash(part.commit.Hash)
+		}
+		if err := p.parseFiles(part.text, where, result); err != nil {
+			return nil, err
+		}
 	}
 
-	for _, f := range files {
-		fileDiff := convertFile(f)
-		result.Files = append(result.Files, fileDiff)
+	for _, f := range result.Files {
+		if f.Encoding == "" {
+			continue
+		}
+		path := f.NewPath
+		if path == "" {
+			path = f.OldPath
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s: transcoded from %s", path, f.Encoding))
 	}
 
+	if result.Files == nil {
+		result.Files = []diffview.FileDiff{}
+	}
 	return result, nil
 }
 
+// parseFiles parses the files in text, appending them to result. where
+// describes the start of text for the warning about ignored lines.
+func (p *Parser) parseFiles(text, where string, result *diffview.Diff) error {
+	for _, c := range splitChunks(text) {
+		if c.combined {
+			fileDiff, err := parseCombinedFile(c.text)
+			if err != nil {
+				if !p.lenient {
+					return err
+				}
+				header, _ := trimCombinedHeader(firstLine(c.text))
+				result.Skipped = append(result.Skipped, skipped(unquotePath(header), c.text, err))
+				continue
+			}
+			transcodeFile(&fileDiff)
+			result.Files = append(result.Files, fileDiff)
+			continue
+		}
+
+		text := requoteNames(c.text)
+		files, preamble, err := gitdiff.Parse(strings.NewReader(text))
+		if err != nil {
+			if !p.lenient {
+				return err
+			}
+			files, preamble = parseLeniently(text, result)
+		}
+		if preamble = strings.TrimSpace(preamble); preamble != "" {
+			n := strings.Count(preamble, "\n") + 1
+			result.Warnings = append(result.Warnings, fmt.Sprintf("ignored %d line(s) of text before %s", n, where))
+		}
+		for _, f := range files {
+			fileDiff := convertFile(f)
+			transcodeFile(&fileDiff)
+			result.Files = append(result.Files, fileDiff)
+		}
+	}
+	return nil
+}
+
+// shortHash abbreviates a commit hash the way git usually shows it.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// normalizeLineEndings converts CRLF to LF when the diff's own header lines
+// use CRLF. Diffs with LF headers are left alone so that CRLF inside file
+// content is preserved as part of the change.
+func normalizeLineEndings(text string) string {
+	first, _, found := strings.Cut(text, "\n")
+	if !found || !strings.HasSuffix(first, "\r") {
+		return text
+	}
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
+
+// colorCode matches an ANSI SGR escape sequence, which sets text color.
+var colorCode = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripColor removes color codes when the diff starts with one, as git's
+// colored output does. Diffs that start uncolored are left alone so that
+// escape sequences inside file content are preserved as part of the change.
+func stripColor(text string) string {
+	if !strings.HasPrefix(text, "\x1b[") {
+		return text
+	}
+	return colorCode.ReplaceAllString(text, "")
+}
+
 func convertFile(f *gitdiff.File) diffview.FileDiff {
 	fd := diffview.FileDiff{
 		OldPath:  f.OldName,
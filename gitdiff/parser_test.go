@@ -330,6 +330,116 @@ func TestParser_Parse_MalformedInput(t *testing.T) {
 	assert.Nil(t, diff)
 }
 
+func TestParser_Parse_LenientSkipsMalformedHunk(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,2 @@
+ package main
+-var a = 1
++var a = 2
+@@ -10,2 +10,x @@
+ func f() {
+-	return 1
++	return 2
+@@ -20,2 +20,2 @@
+ func g() {
+-	return 3
++	return 4
+diff --git a/util.go b/util.go
+--- a/util.go
++++ b/util.go
+@@ -1 +1 @@
+-var b = 1
++var b = 2
+`
+
+	_, err := gitdiff.NewParser().Parse(strings.NewReader(input))
+	require.Error(t, err)
+
+	diff, err := gitdiff.NewParser(gitdiff.WithLenient()).Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 2)
+	require.Len(t, diff.Files[0].Hunks, 2)
+	assert.Equal(t, 1, diff.Files[0].Hunks[0].OldStart)
+	assert.Equal(t, 20, diff.Files[0].Hunks[1].OldStart)
+	assert.Len(t, diff.Files[1].Hunks, 1)
+	assert.Equal(t, []diffview.SkippedSection{{
+		Path:   "main.go",
+		Header: "@@ -10,2 +10,x @@",
+		Lines:  4,
+		Reason: "invalid fragment header: bad end of range: x: invalid syntax",
+	}}, diff.Skipped)
+	assert.Equal(t, `main.go: skipped 4 line(s) from "@@ -10,2 +10,x @@": invalid fragment header: bad end of range: x: invalid syntax`,
+		diff.Skipped[0].String())
+}
+
+func TestParser_Parse_LenientSkipsMalformedFile(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --git a/file.go
+@@ -1,1 +1,1 @@ incomplete header
+-a
++b
+diff --git a/util.go b/util.go
+--- a/util.go
++++ b/util.go
+@@ -1 +1 @@
+-var b = 1
++var b = 2
+`
+
+	diff, err := gitdiff.NewParser(gitdiff.WithLenient()).Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, "util.go", diff.Files[0].NewPath)
+	require.Len(t, diff.Skipped, 1)
+	assert.Empty(t, diff.Skipped[0].Path)
+	assert.Equal(t, "diff --git a/file.go", diff.Skipped[0].Header)
+	assert.Equal(t, 4, diff.Skipped[0].Lines)
+}
+
+func TestParser_Parse_LenientSkipsMalformedCombinedFile(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --cc file.txt
+--- a/file.txt
++++ b/file.txt
+@@@ -1,1 +1,1 @@@
+  same
+`
+
+	diff, err := gitdiff.NewParser(gitdiff.WithLenient()).Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	assert.Empty(t, diff.Files)
+	require.Len(t, diff.Skipped, 1)
+	assert.Equal(t, "file.txt", diff.Skipped[0].Path)
+	assert.Equal(t, 5, diff.Skipped[0].Lines)
+	assert.NotContains(t, diff.Skipped[0].Reason, "file.txt:")
+}
+
+func TestParser_Parse_LenientKeepsWellFormedDiff(t *testing.T) {
+	t.Parallel()
+
+	input := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-var x = 1\n" +
+		"+var x = 2\n"
+
+	diff, err := gitdiff.NewParser(gitdiff.WithLenient()).Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Empty(t, diff.Skipped)
+}
+
 func TestParser_Parse_ModeChange(t *testing.T) {
 	t.Parallel()
 
@@ -351,3 +461,448 @@ new mode 100755
 	assert.Equal(t, diffview.FileModified, f.Operation)
 	assert.Empty(t, f.Hunks)
 }
+
+func TestParser_Parse_CombinedDiff(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --cc file.txt
+index 1234567,89abcde..0000000
+--- a/file.txt
++++ b/file.txt
+@@@ -1,3 -1,3 +1,7 @@@ func main
+  first
+++<<<<<<< HEAD
+ +ours
+++=======
++ theirs
+++>>>>>>> feature
+  last
+`
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+
+	f := diff.Files[0]
+	assert.Equal(t, "file.txt", f.OldPath)
+	assert.Equal(t, "file.txt", f.NewPath)
+	assert.Equal(t, diffview.FileModified, f.Operation)
+
+	require.Len(t, f.Hunks, 1)
+	h := f.Hunks[0]
+	assert.True(t, h.IsCombined())
+	assert.Equal(t, []diffview.HunkRange{{Start: 1, Count: 3}, {Start: 1, Count: 3}}, h.Parents)
+	assert.Equal(t, 1, h.OldStart)
+	assert.Equal(t, 3, h.OldCount)
+	assert.Equal(t, 1, h.NewStart)
+	assert.Equal(t, 7, h.NewCount)
+	assert.Equal(t, "func main", h.Section)
+
+	require.Len(t, h.Lines, 7)
+
+	assert.Equal(t, diffview.LineContext, h.Lines[0].Type)
+	assert.Equal(t, "first\n", h.Lines[0].Content)
+	assert.Equal(t, 1, h.Lines[0].OldLineNum)
+	assert.Equal(t, 1, h.Lines[0].NewLineNum)
+
+	assert.Equal(t, diffview.LineAdded, h.Lines[1].Type)
+	assert.Equal(t, "<<<<<<< HEAD\n", h.Lines[1].Content)
+	assert.Equal(t, diffview.OriginBoth, h.Lines[1].Origin())
+
+	assert.Equal(t, "ours\n", h.Lines[2].Content)
+	assert.Equal(t, diffview.OriginOurs, h.Lines[2].Origin())
+	assert.Equal(t, 2, h.Lines[2].OldLineNum)
+	assert.Equal(t, 3, h.Lines[2].NewLineNum)
+
+	assert.Equal(t, "theirs\n", h.Lines[4].Content)
+	assert.Equal(t, diffview.OriginTheirs, h.Lines[4].Origin())
+	assert.Equal(t, 0, h.Lines[4].OldLineNum)
+	assert.Equal(t, 5, h.Lines[4].NewLineNum)
+
+	assert.Equal(t, diffview.LineContext, h.Lines[6].Type)
+	assert.Equal(t, 3, h.Lines[6].OldLineNum)
+	assert.Equal(t, 7, h.Lines[6].NewLineNum)
+}
+
+func TestParser_Parse_CombinedDiffDeletedLines(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --combined file.txt
+index 1234567,89abcde..0000000
+--- a/file.txt
++++ b/file.txt
+@@@ -1,2 -1,1 +1,1 @@@
+- removed
+  kept
+`
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	require.Len(t, diff.Files[0].Hunks, 1)
+
+	lines := diff.Files[0].Hunks[0].Lines
+	require.Len(t, lines, 2)
+	assert.Equal(t, diffview.LineDeleted, lines[0].Type)
+	assert.Equal(t, []diffview.LineType{diffview.LineDeleted, diffview.LineContext}, lines[0].Markers)
+	assert.Equal(t, 1, lines[0].OldLineNum)
+	assert.Equal(t, 0, lines[0].NewLineNum)
+	assert.Equal(t, diffview.LineContext, lines[1].Type)
+	assert.Equal(t, 2, lines[1].OldLineNum)
+	assert.Equal(t, 1, lines[1].NewLineNum)
+}
+
+func TestParser_Parse_MixedCombinedAndRegular(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --git a/a.go b/a.go
+index 1234567..abcdefg 100644
+--- a/a.go
++++ b/a.go
+@@ -1 +1 @@
+-old
++new
+diff --cc b.txt
+index 1234567,89abcde..0000000
+--- a/b.txt
++++ b/b.txt
+@@@ -1,1 -1,1 +1,2 @@@
+  same
+ +ours
+diff --git a/c.go b/c.go
+index 1234567..abcdefg 100644
+--- a/c.go
++++ b/c.go
+@@ -1 +1 @@
+-old
++new
+`
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 3)
+	assert.Equal(t, "a.go", diff.Files[0].NewPath)
+	assert.False(t, diff.Files[0].Hunks[0].IsCombined())
+	assert.Equal(t, "b.txt", diff.Files[1].NewPath)
+	assert.True(t, diff.Files[1].Hunks[0].IsCombined())
+	assert.Equal(t, "c.go", diff.Files[2].NewPath)
+	assert.False(t, diff.Files[2].Hunks[0].IsCombined())
+}
+
+func TestParser_Parse_InvalidCombinedHunkHeader(t *testing.T) {
+	t.Parallel()
+
+	input := `diff --cc file.txt
+--- a/file.txt
++++ b/file.txt
+@@@ -1,1 +1,1 @@@
+  same
+`
+
+	p := gitdiff.NewParser()
+
+	_, err := p.Parse(strings.NewReader(input))
+
+	assert.Error(t, err)
+}
+
+func TestParser_Parse_Latin1Content(t *testing.T) {
+	t.Parallel()
+
+	input := "diff --git a/names.txt b/names.txt\n" +
+		"--- a/names.txt\n" +
+		"+++ b/names.txt\n" +
+		"@@ -1 +1 @@\n" +
+		"-Jos\xe9\n" +
+		"+Jos\xe9 Mar\xeda\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	file := diff.Files[0]
+	assert.Equal(t, "latin-1", file.Encoding)
+	require.Len(t, file.Hunks[0].Lines, 2)
+	assert.Equal(t, "José\n", file.Hunks[0].Lines[0].Content)
+	assert.Equal(t, "José María\n", file.Hunks[0].Lines[1].Content)
+	assert.Equal(t, []string{"names.txt: transcoded from latin-1"}, diff.Warnings)
+}
+
+func TestParser_Parse_UTF16LEContent(t *testing.T) {
+	t.Parallel()
+
+	// "hi\nyo\n" in UTF-16LE with BOM: git splits on the 0x0A byte, leaving the
+	// newline's high byte at the start of the following line.
+	input := "diff --git a/win.txt b/win.txt\n" +
+		"--- a/win.txt\n" +
+		"+++ b/win.txt\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" \xff\xfeh\x00i\x00\r\x00\n" +
+		"-\x00y\x00o\x00\r\x00\n" +
+		"+\x00y\x00a\x00\r\x00\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	file := diff.Files[0]
+	assert.Equal(t, "utf-16le", file.Encoding)
+	require.Len(t, file.Hunks[0].Lines, 3)
+	assert.Equal(t, "hi\r\n", file.Hunks[0].Lines[0].Content)
+	assert.Equal(t, "yo\r\n", file.Hunks[0].Lines[1].Content)
+	assert.Equal(t, "ya\r\n", file.Hunks[0].Lines[2].Content)
+}
+
+func TestParser_Parse_UTF16BEContentWithoutBOM(t *testing.T) {
+	t.Parallel()
+
+	// "hi\r\n中\r\n" changed to "hi\r\n文\r\n" at line 5 of a UTF-16BE
+	// file: no byte order mark in sight, but the newline's high byte ends
+	// each line rather than starting the next.
+	input := "diff --git a/win.txt b/win.txt\n" +
+		"--- a/win.txt\n" +
+		"+++ b/win.txt\n" +
+		"@@ -5,2 +5,2 @@\n" +
+		" \x00h\x00i\x00\r\x00\n" +
+		"-\x4e\x2d\x00\r\x00\n" +
+		"+\x65\x87\x00\r\x00\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	file := diff.Files[0]
+	assert.Equal(t, "utf-16be", file.Encoding)
+	require.Len(t, file.Hunks[0].Lines, 3)
+	assert.Equal(t, "hi\r\n", file.Hunks[0].Lines[0].Content)
+	assert.Equal(t, "中\r\n", file.Hunks[0].Lines[1].Content)
+	assert.Equal(t, "文\r\n", file.Hunks[0].Lines[2].Content)
+}
+
+func TestParser_Parse_UTF8ContentHasNoEncoding(t *testing.T) {
+	t.Parallel()
+
+	input := "diff --git a/names.txt b/names.txt\n" +
+		"--- a/names.txt\n" +
+		"+++ b/names.txt\n" +
+		"@@ -1 +1 @@\n" +
+		"-José\n" +
+		"+José María\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Empty(t, diff.Files[0].Encoding)
+	assert.Empty(t, diff.Warnings)
+	assert.Equal(t, "José María\n", diff.Files[0].Hunks[0].Lines[1].Content)
+}
+
+func TestParser_Parse_CRLFDiff(t *testing.T) {
+	t.Parallel()
+
+	// A diff saved on Windows has CRLF on every line, including headers.
+	input := "diff --git a/main.go b/main.go\r\n" +
+		"index abc123..def456 100644\r\n" +
+		"--- a/main.go\r\n" +
+		"+++ b/main.go\r\n" +
+		"@@ -1,2 +1,2 @@\r\n" +
+		" package main\r\n" +
+		"-var x = 1\r\n" +
+		"+var x = 2\r\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	file := diff.Files[0]
+	assert.Equal(t, "main.go", file.NewPath)
+	require.Len(t, file.Hunks[0].Lines, 3)
+	assert.Equal(t, "package main\n", file.Hunks[0].Lines[0].Content)
+	assert.Equal(t, "var x = 2\n", file.Hunks[0].Lines[2].Content)
+	assert.Equal(t, []string{"normalized CRLF line endings to LF"}, diff.Warnings)
+}
+
+func TestParser_Parse_PreservesCRLFContentInLFDiff(t *testing.T) {
+	t.Parallel()
+
+	// Git emits LF headers; CRLF in content is part of the file and is kept.
+	input := "diff --git a/win.txt b/win.txt\n" +
+		"--- a/win.txt\n" +
+		"+++ b/win.txt\n" +
+		"@@ -1 +1 @@\n" +
+		"-old\r\n" +
+		"+new\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, "old\r\n", diff.Files[0].Hunks[0].Lines[0].Content)
+	assert.Equal(t, "new\n", diff.Files[0].Hunks[0].Lines[1].Content)
+}
+
+func TestParser_Parse_WarnsAboutPreamble(t *testing.T) {
+	t.Parallel()
+
+	// Output of git format-patch starts with an email header.
+	input := "From: Someone <someone@example.com>\n" +
+		"Subject: [PATCH] Fix the thing\n" +
+		"\n" +
+		"---\n" +
+		"diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-var x = 1\n" +
+		"+var x = 2\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, []string{"ignored 4 line(s) of text before the first file"}, diff.Warnings)
+}
+
+func TestParser_Parse_ColoredDiff(t *testing.T) {
+	t.Parallel()
+
+	// Git colors its output when acting as a pager, e.g. pager.diff=diffview.
+	input := "\x1b[1mdiff --git a/main.go b/main.go\x1b[m\n" +
+		"\x1b[1m--- a/main.go\x1b[m\n" +
+		"\x1b[1m+++ b/main.go\x1b[m\n" +
+		"\x1b[36m@@ -1 +1 @@\x1b[m\n" +
+		"\x1b[31m-var x = 1\x1b[m\n" +
+		"\x1b[32m+\x1b[m\x1b[32mvar x = 2\x1b[m\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	file := diff.Files[0]
+	assert.Equal(t, "main.go", file.NewPath)
+	require.Len(t, file.Hunks[0].Lines, 2)
+	assert.Equal(t, "var x = 2\n", file.Hunks[0].Lines[1].Content)
+	assert.Equal(t, []string{"removed ANSI color codes"}, diff.Warnings)
+}
+
+func TestParser_Parse_LogWithSeveralCommits(t *testing.T) {
+	t.Parallel()
+
+	input := "commit 1111111111111111111111111111111111111111 (HEAD -> main)\n" +
+		"Author: Ada <ada@example.com>\n" +
+		"Date:   Mon Jan 6 10:00:00 2025 +0000\n" +
+		"\n" +
+		"    Change x\n" +
+		"\n" +
+		"    Explain why.\n" +
+		"\n" +
+		"diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-var x = 1\n" +
+		"+var x = 2\n" +
+		"commit 2222222222222222222222222222222222222222\n" +
+		"Merge: aaaaaaa bbbbbbb\n" +
+		"Author: Bob <bob@example.com>\n" +
+		"Date:   Sun Jan 5 10:00:00 2025 +0000\n" +
+		"\n" +
+		"    Merge branch 'feature'\n" +
+		"\n" +
+		"commit 3333333333333333333333333333333333333333\n" +
+		"Author: Ada <ada@example.com>\n" +
+		"Date:   Sat Jan 4 10:00:00 2025 +0000\n" +
+		"\n" +
+		"    Add files\n" +
+		"\n" +
+		"diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-var x = 0\n" +
+		"+var x = 1\n" +
+		"diff --git a/util.go b/util.go\n" +
+		"--- a/util.go\n" +
+		"+++ b/util.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-var y = 0\n" +
+		"+var y = 1\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 3)
+	assert.Equal(t, "var x = 1\n", diff.Files[1].Hunks[0].Lines[1].Content)
+	assert.Equal(t, []diffview.Commit{
+		{
+			Hash:      "1111111111111111111111111111111111111111",
+			Author:    "Ada <ada@example.com>",
+			Date:      "Mon Jan 6 10:00:00 2025 +0000",
+			Message:   "Change x\n\nExplain why.",
+			FirstFile: 0,
+		},
+		{
+			Hash:      "2222222222222222222222222222222222222222",
+			Author:    "Bob <bob@example.com>",
+			Date:      "Sun Jan 5 10:00:00 2025 +0000",
+			Message:   "Merge branch 'feature'",
+			FirstFile: 1,
+		},
+		{
+			Hash:      "3333333333333333333333333333333333333333",
+			Author:    "Ada <ada@example.com>",
+			Date:      "Sat Jan 4 10:00:00 2025 +0000",
+			Message:   "Add files",
+			FirstFile: 1,
+		},
+	}, diff.Commits)
+	assert.Equal(t, "Change x", diff.Commits[0].Subject())
+	assert.Empty(t, diff.Warnings)
+}
+
+func TestParser_Parse_PlainDiffHasNoCommits(t *testing.T) {
+	t.Parallel()
+
+	input := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-commit 1234567\n" +
+		"+commit 7654321\n"
+
+	p := gitdiff.NewParser()
+
+	diff, err := p.Parse(strings.NewReader(input))
+
+	require.NoError(t, err)
+	assert.Empty(t, diff.Commits)
+	require.Len(t, diff.Files, 1)
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// WriteWorkflowCommands writes findings as GitHub Actions workflow
+// commands, such as "::warning file=api.go,line=42,title=vet::message",
+// which a workflow step's output turns into annotations on the pull
+// request's files.
+func WriteWorkflowCommands(w io.Writer, findings []diffview.Finding) error {
+	var sb strings.Builder
+	for _, f := range findings {
+		var props []string
+		if f.Path != "" {
+			props = append(props, "file="+escapeProperty(f.Path))
+			if f.StartLine > 0 {
+				props = append(props, fmt.Sprintf("line=%d", f.StartLine))
+				if f.EndLine > f.StartLine {
+					props = append(props, fmt.Sprintf("endLine=%d", f.EndLine))
+				}
+			}
+		}
+		title := f.Title
+		if title == "" {
+			title = f.Rule
+		}
+		if title != "" {
+			props = append(props, "title="+escapeProperty(title))
+		}
+		fmt.Fprintf(&sb, "::%s", commandLevel(f.Level))
+		if len(props) > 0 {
+			fmt.Fprintf(&sb, " %s", strings.Join(props, ","))
+		}
+		fmt.Fprintf(&sb, "::%s\n", escapeData(f.Message))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// commandLevel returns the workflow command for level, which is named the
+// same, defaulting to a warning.
+func commandLevel(level diffview.FindingLevel) string {
+	switch level {
+	case diffview.FindingNotice, diffview.FindingError:
+		return string(level)
+	default:
+		return string(diffview.FindingWarning)
+	}
+}
+
+// escapeData escapes a workflow command's message, which ends at a line
+// break.
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeProperty escapes a workflow command's property value, which also
+// ends at a colon or comma.
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
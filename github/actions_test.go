@@ -0,0 +1,32 @@
+package github_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteWorkflowCommands(t *testing.T) {
+	t.Parallel()
+
+	findings := []diffview.Finding{
+		{Rule: "vet", Level: diffview.FindingWarning, Message: "unreachable code", Path: "api.go", StartLine: 42, EndLine: 42},
+		{Rule: "review", Level: diffview.FindingNotice, Message: "check this", Path: "util.go", StartLine: 3, EndLine: 7},
+		{Rule: "coverage", Level: diffview.FindingNotice, Message: "40% covered", Path: "dir,with:odd/name.go"},
+		{Rule: "risk", Level: diffview.FindingError, Title: "high risk", Message: "Risk: high\nBreaking:\n- 100% of callers"},
+	}
+	var sb strings.Builder
+
+	err := github.WriteWorkflowCommands(&sb, findings)
+
+	require.NoError(t, err)
+	assert.Equal(t, "::warning file=api.go,line=42,title=vet::unreachable code\n"+
+		"::notice file=util.go,line=3,endLine=7,title=review::check this\n"+
+		"::notice file=dir%2Cwith%3Aodd/name.go,title=coverage::40%25 covered\n"+
+		"::error title=high risk::Risk: high%0ABreaking:%0A- 100%25 of callers\n",
+		sb.String())
+}
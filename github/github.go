@@ -0,0 +1,122 @@
+// Package github looks up pull requests through the GitHub REST API.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.PullRequestFinder = (*Client)(nil)
+
+// DefaultBaseURL is the REST API root for github.com.
+const DefaultBaseURL = "https://api.github.com"
+
+// BaseURL returns the REST API root for repositories on host, which GitHub
+// Enterprise Server serves under /api/v3.
+func BaseURL(host string) string {
+	if host == "github.com" {
+		return DefaultBaseURL
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithBaseURL sets the REST API root, DefaultBaseURL by default.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithToken authenticates requests with token, raising the rate limit and
+// giving access to private repositories.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// Client looks up pull requests in one GitHub repository.
+type Client struct {
+	repo    string
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient creates a Client for repo, given as "owner/name".
+func NewClient(repo string, opts ...ClientOption) *Client {
+	c := &Client{
+		repo:    repo,
+		baseURL: DefaultBaseURL,
+		http:    http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// pullRequest is the part of the API's pull request resource we use.
+type pullRequest struct {
+	Number         int    `json:"number"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	Merged         bool   `json:"merged"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Head           struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+}
+
+// PullRequest implements diffview.PullRequestFinder.
+func (c *Client) PullRequest(ctx context.Context, number int) (*diffview.PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d", c.baseURL, c.repo, number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request #%d: %w", number, err)
+	}
+	defer resp.Body.Close()
+
+	// Issue numbers are shared with pull requests, so a number may name an issue
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) // Only used to explain the error
+		return nil, fmt.Errorf("failed to fetch pull request #%d: %s: %s", number, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var pr pullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return nil, fmt.Errorf("failed to decode pull request #%d: %w", number, err)
+	}
+	if !pr.Merged {
+		return nil, nil
+	}
+	return &diffview.PullRequest{
+		Number:      pr.Number,
+		Title:       pr.Title,
+		Body:        pr.Body,
+		Branch:      pr.Head.Ref,
+		MergeCommit: pr.MergeCommitSHA,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package github_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_PullRequest(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/12":
+			w.Write([]byte(`{"number":12,"title":"Fix parser","body":"Details","merged":true,"merge_commit_sha":"abc123","head":{"ref":"fix-parser"}}`))
+		case "/repos/owner/repo/pulls/13":
+			w.Write([]byte(`{"number":13,"merged":false}`))
+		case "/repos/owner/repo/pulls/14":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient("owner/repo", github.WithBaseURL(server.URL+"/"), github.WithToken("secret"))
+	ctx := context.Background()
+
+	t.Run("returns a merged pull request", func(t *testing.T) {
+		t.Parallel()
+
+		pr, err := client.PullRequest(ctx, 12)
+
+		require.NoError(t, err)
+		assert.Equal(t, &diffview.PullRequest{
+			Number:      12,
+			Title:       "Fix parser",
+			Body:        "Details",
+			Branch:      "fix-parser",
+			MergeCommit: "abc123",
+		}, pr)
+	})
+
+	t.Run("returns nil for unmerged and missing pull requests", func(t *testing.T) {
+		t.Parallel()
+
+		pr, err := client.PullRequest(ctx, 13)
+		require.NoError(t, err)
+		assert.Nil(t, pr)
+
+		pr, err = client.PullRequest(ctx, 14)
+		require.NoError(t, err)
+		assert.Nil(t, pr)
+	})
+
+	t.Run("reports other failures", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := client.PullRequest(ctx, 15)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403 Forbidden")
+		assert.Contains(t, err.Error(), "API rate limit exceeded")
+	})
+}
+
+func TestBaseURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "https://api.github.com", github.BaseURL("github.com"))
+	assert.Equal(t, "https://git.example.com/api/v3", github.BaseURL("git.example.com"))
+}
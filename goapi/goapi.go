@@ -0,0 +1,252 @@
+// Package goapi compares the exported API of the Go packages a diff
+// touches, parsing each changed file as it was and as it is. Tests,
+// commands and internal packages have no exported API.
+package goapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.APIDiffer = (*Differ)(nil)
+
+// Source returns a version of the file at path, which is relative to the
+// root of the repository, such as "pkg/server.go".
+type Source func(path string) ([]byte, error)
+
+// Differ compares exported API between the files old and new return.
+type Differ struct {
+	old Source
+	new Source
+}
+
+// NewDiffer creates a Differ reading the files before a change from old
+// and after it from new.
+func NewDiffer(old, new Source) *Differ {
+	return &Differ{old: old, new: new}
+}
+
+// decl is the signature of an exported identifier and where it's declared:
+// the diff's file and the lines in that version of the file.
+type decl struct {
+	sig        string
+	file       int
+	start, end int
+}
+
+// DiffAPI returns the exported identifiers the diff adds, removes or
+// changes the signature of, ordered by name, with the hunks changing their
+// declarations. Struct fields count on their own, so adding one doesn't
+// change the struct. Files that can't be read or parsed are left out.
+func (d *Differ) DiffAPI(diff *diffview.Diff) []diffview.APIChange {
+	// Identifiers are compared across all files at once, so a declaration
+	// moved between files of a package is neither removed nor added.
+	before, after := make(map[string]decl), make(map[string]decl)
+	for i, file := range diff.Files {
+		// A renamed file's old version is at its old path
+		oldPath, newPath := strings.TrimPrefix(file.OldPath, "a/"), file.Path()
+		if !hasAPI(oldPath) && !hasAPI(newPath) {
+			continue
+		}
+		var oldAPI, newAPI map[string]decl
+		var ok bool
+		if file.Operation != diffview.FileAdded {
+			if oldAPI, ok = exportedAPI(d.old, oldPath, i); !ok {
+				continue
+			}
+		}
+		if file.Operation != diffview.FileDeleted {
+			if newAPI, ok = exportedAPI(d.new, newPath, i); !ok {
+				continue
+			}
+		}
+		for name, dc := range oldAPI {
+			before[name] = dc
+		}
+		for name, dc := range newAPI {
+			after[name] = dc
+		}
+	}
+
+	var changes []diffview.APIChange
+	for name, old := range before {
+		switch new, ok := after[name]; {
+		case !ok:
+			changes = append(changes, diffview.APIChange{Symbol: name, Kind: "removed", Old: old.sig,
+				Hunks: touching(diff, old, nil)})
+		case new.sig != old.sig:
+			changes = append(changes, diffview.APIChange{Symbol: name, Kind: "changed", Old: old.sig, New: new.sig,
+				Hunks: touching(diff, old, &new)})
+		}
+	}
+	for name, new := range after {
+		if _, ok := before[name]; !ok {
+			changes = append(changes, diffview.APIChange{Symbol: name, Kind: "added", New: new.sig,
+				Hunks: touching(diff, decl{file: -1}, &new)})
+		}
+	}
+	slices.SortFunc(changes, func(a, b diffview.APIChange) int {
+		return strings.Compare(a.Symbol, b.Symbol)
+	})
+	return changes
+}
+
+// touching returns the hunks deleting lines of old or adding lines of new,
+// if any. An old with a negative file has no lines.
+func touching(diff *diffview.Diff, old decl, new *decl) []diffview.HunkRef {
+	var refs []diffview.HunkRef
+	for i, file := range diff.Files {
+		if i != old.file && (new == nil || i != new.file) {
+			continue
+		}
+		for j, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				inOld := i == old.file && line.Type == diffview.LineDeleted &&
+					old.start <= line.OldLineNum && line.OldLineNum <= old.end
+				inNew := new != nil && i == new.file && line.Type == diffview.LineAdded &&
+					new.start <= line.NewLineNum && line.NewLineNum <= new.end
+				if inOld || inNew {
+					refs = append(refs, diffview.HunkRef{File: file.Path(), HunkIndex: j})
+					break
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// hasAPI reports whether the file at p can declare exported API: a Go file
+// that isn't a test or in an internal package.
+func hasAPI(p string) bool {
+	if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+		return false
+	}
+	return !slices.Contains(strings.Split(path.Dir(p), "/"), "internal")
+}
+
+// exportedAPI returns the exported identifiers the file at p declares,
+// keyed by package-qualified name such as "symbols.Resolver.ResolveSymbols",
+// as declared in the diff's file at index file. Returns false if the file
+// can't be read or parsed, and no identifiers for a main package.
+func exportedAPI(source Source, p string, file int) (map[string]decl, bool) {
+	if !hasAPI(p) {
+		return nil, true
+	}
+	src, err := source(p)
+	if err != nil {
+		return nil, false
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, p, src, 0)
+	if err != nil {
+		return nil, false
+	}
+	api := make(map[string]decl)
+	pkg := f.Name.Name
+	if pkg == "main" {
+		return api, true
+	}
+	add := func(name, sig string, node ast.Node) {
+		api[name] = decl{sig: sig, file: file, start: fset.Position(node.Pos()).Line, end: fset.Position(node.End()).Line}
+	}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				add(pkg+"."+d.Name.Name, types.ExprString(d.Type), d)
+				continue
+			}
+			recv := d.Recv.List[0].Type
+			if base := typeName(recv); ast.IsExported(base) {
+				add(pkg+"."+base+"."+d.Name.Name, types.ExprString(recv)+" "+types.ExprString(d.Type), d)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						addType(add, pkg+"."+s.Name.Name, s)
+					}
+				case *ast.ValueSpec:
+					sig := d.Tok.String()
+					if s.Type != nil {
+						sig += " " + types.ExprString(s.Type)
+					}
+					for _, name := range s.Names {
+						if name.IsExported() {
+							add(pkg+"."+name.Name, sig, s)
+						}
+					}
+				}
+			}
+		}
+	}
+	return api, true
+}
+
+// addType adds the type s declares under name, and for a struct, each of
+// its exported fields.
+func addType(add func(name, sig string, node ast.Node), name string, s *ast.TypeSpec) {
+	var sig strings.Builder
+	sig.WriteString("type")
+	if s.TypeParams != nil {
+		for _, param := range s.TypeParams.List {
+			sig.WriteString(" [" + types.ExprString(param.Type) + "]")
+		}
+	}
+	if s.Assign.IsValid() {
+		sig.WriteString(" =")
+	}
+	st, ok := s.Type.(*ast.StructType)
+	if !ok {
+		add(name, sig.String()+" "+types.ExprString(s.Type), s)
+		return
+	}
+	// The struct's own lines are its header, so a changed field touches
+	// only the field's hunk
+	add(name, sig.String()+" struct", s.Name)
+	for _, field := range st.Fields.List {
+		fieldType := types.ExprString(field.Type)
+		if len(field.Names) == 0 {
+			// An embedded field is named by its type
+			if embedded := typeName(field.Type); ast.IsExported(embedded) {
+				add(name+"."+embedded, fieldType, field)
+			}
+			continue
+		}
+		for _, fieldName := range field.Names {
+			if fieldName.IsExported() {
+				add(name+"."+fieldName.Name, fieldType, field)
+			}
+		}
+	}
+}
+
+// typeName returns the name of the type in a receiver or embedded field,
+// such as "Server" for *Server, pkg.Server or Server[T].
+func typeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return typeName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return typeName(e.X)
+	case *ast.IndexListExpr:
+		return typeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package goapi_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// mapSource returns a Source reading files from m.
+func mapSource(m map[string]string) goapi.Source {
+	return func(path string) ([]byte, error) {
+		src, ok := m[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(src), nil
+	}
+}
+
+// deleted returns a line deleted from line n of the old file.
+func deleted(n int, content string) diffview.Line {
+	return diffview.Line{Type: diffview.LineDeleted, Content: content, OldLineNum: n}
+}
+
+// added returns a line added as line n of the new file.
+func added(n int, content string) diffview.Line {
+	return diffview.Line{Type: diffview.LineAdded, Content: content, NewLineNum: n}
+}
+
+const serverBefore = `package server
+
+type Config struct {
+	Addr string
+	port int
+}
+
+type Server struct{}
+
+func (s *Server) Start() error { return nil }
+
+func (s *Server) Stop() {}
+
+func New(cfg Config) *Server { return nil }
+`
+
+const serverAfter = `package server
+
+type Config struct {
+	Addr    string
+	Timeout int
+}
+
+type Server struct{}
+
+func (s *Server) Start(force bool) error { return nil }
+
+func New(cfg Config) *Server { return nil }
+`
+
+func TestDiffer_DiffAPI(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{{
+		OldPath:   "server/server.go",
+		NewPath:   "server/server.go",
+		Operation: diffview.FileModified,
+		Hunks: []diffview.Hunk{
+			{Lines: []diffview.Line{
+				deleted(4, "\tAddr string"), deleted(5, "\tport int"),
+				added(4, "\tAddr    string"), added(5, "\tTimeout int"),
+			}},
+			{Lines: []diffview.Line{
+				deleted(10, "func (s *Server) Start() error { return nil }"),
+				added(10, "func (s *Server) Start(force bool) error { return nil }"),
+			}},
+			{Lines: []diffview.Line{deleted(11, ""), deleted(12, "func (s *Server) Stop() {}")}},
+		},
+	}}}
+	d := goapi.NewDiffer(
+		mapSource(map[string]string{"server/server.go": serverBefore}),
+		mapSource(map[string]string{"server/server.go": serverAfter}),
+	)
+
+	changes := d.DiffAPI(diff)
+
+	assert.Equal(t, []diffview.APIChange{
+		{
+			Symbol: "server.Config.Timeout",
+			Kind:   "added",
+			New:    "int",
+			Hunks:  []diffview.HunkRef{{File: "server/server.go", HunkIndex: 0}},
+		},
+		{
+			Symbol: "server.Server.Start",
+			Kind:   "changed",
+			Old:    "*Server func() error",
+			New:    "*Server func(force bool) error",
+			Hunks:  []diffview.HunkRef{{File: "server/server.go", HunkIndex: 1}},
+		},
+		{
+			Symbol: "server.Server.Stop",
+			Kind:   "removed",
+			Old:    "*Server func()",
+			Hunks:  []diffview.HunkRef{{File: "server/server.go", HunkIndex: 2}},
+		},
+	}, changes, "realigned fields and unexported ones aren't changes")
+}
+
+func TestDiffer_DiffAPI_MovedBetweenFiles(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{OldPath: "server/server.go", NewPath: "server/server.go", Operation: diffview.FileModified},
+		{NewPath: "server/config.go", Operation: diffview.FileAdded},
+	}}
+	d := goapi.NewDiffer(
+		mapSource(map[string]string{"server/server.go": serverBefore}),
+		mapSource(map[string]string{
+			"server/server.go": "package server\n\ntype Server struct{}\n\nfunc (s *Server) Start() error { return nil }\n\nfunc (s *Server) Stop() {}\n\nfunc New(cfg Config) *Server { return nil }\n",
+			"server/config.go": "package server\n\ntype Config struct {\n\tAddr string\n}\n",
+		}),
+	)
+
+	assert.Empty(t, d.DiffAPI(diff))
+}
+
+func TestDiffer_DiffAPI_IgnoresFilesWithoutAPI(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{OldPath: "server/server_test.go", Operation: diffview.FileDeleted},
+		{OldPath: "internal/x/x.go", Operation: diffview.FileDeleted},
+		{OldPath: "cmd/tool/main.go", Operation: diffview.FileDeleted},
+		{OldPath: "README.md", Operation: diffview.FileDeleted},
+		{OldPath: "server/unreadable.go", NewPath: "server/unreadable.go", Operation: diffview.FileModified},
+	}}
+	d := goapi.NewDiffer(
+		mapSource(map[string]string{
+			"server/server_test.go": "package server\n\nfunc TestX() {}\n",
+			"internal/x/x.go":       "package x\n\nfunc X() {}\n",
+			"cmd/tool/main.go":      "package main\n\nfunc Run() {}\n",
+			"server/unreadable.go":  "package server\n\nfunc Gone() {}\n",
+		}),
+		mapSource(nil),
+	)
+
+	assert.Empty(t, d.DiffAPI(diff))
+}
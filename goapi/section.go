@@ -0,0 +1,36 @@
+package goapi
+
+import (
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Section returns a story section on changes: the hunks changing their
+// declarations, explained by what's added, removed and changed.
+func Section(changes []diffview.APIChange) diffview.Section {
+	section := diffview.Section{Role: "api", Title: "Exported API changes"}
+	byKind := make(map[string][]string)
+	seen := make(map[diffview.HunkRef]bool)
+	for _, c := range changes {
+		byKind[c.Kind] = append(byKind[c.Kind], c.Symbol)
+		for _, ref := range c.Hunks {
+			if !seen[ref] {
+				seen[ref] = true
+				section.Hunks = append(section.Hunks, ref)
+			}
+		}
+	}
+	var sentences []string
+	for _, k := range []struct{ kind, verb string }{
+		{"added", "Adds"},
+		{"removed", "Removes"},
+		{"changed", "Changes the signature of"},
+	} {
+		if names := byKind[k.kind]; len(names) > 0 {
+			sentences = append(sentences, k.verb+" "+strings.Join(names, ", ")+".")
+		}
+	}
+	section.Explanation = strings.Join(sentences, " ")
+	return section
+}
@@ -0,0 +1,30 @@
+package goapi_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSection(t *testing.T) {
+	t.Parallel()
+
+	h0 := diffview.HunkRef{File: "server.go", HunkIndex: 0}
+	h1 := diffview.HunkRef{File: "server.go", HunkIndex: 1}
+
+	section := goapi.Section([]diffview.APIChange{
+		{Symbol: "server.Config.Timeout", Kind: "added", Hunks: []diffview.HunkRef{h1}},
+		{Symbol: "server.New", Kind: "added", Hunks: []diffview.HunkRef{h0}},
+		{Symbol: "server.Server.Start", Kind: "changed", Hunks: []diffview.HunkRef{h1}},
+		{Symbol: "server.Server.Stop", Kind: "removed"},
+	})
+
+	assert.Equal(t, diffview.Section{
+		Role:        "api",
+		Title:       "Exported API changes",
+		Hunks:       []diffview.HunkRef{h1, h0},
+		Explanation: "Adds server.Config.Timeout, server.New. Removes server.Server.Stop. Changes the signature of server.Server.Start.",
+	}, section)
+}
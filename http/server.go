@@ -0,0 +1,185 @@
+// Package http serves the diff parser and story classifier over HTTP, so
+// CI systems and editors can use them without shelling out to diffstory.
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// DefaultMaxConcurrent is how many requests a server handles at once by
+// default. Classifying a diff takes seconds of a model's time.
+const DefaultMaxConcurrent = 4
+
+// DefaultMaxBodySize is the largest diff, in bytes, a server accepts by
+// default.
+const DefaultMaxBodySize = 10 << 20
+
+// Server handles the endpoints, each taking a raw diff as its body:
+//
+//	POST /parse     the parsed diff, as the JSON model of diffview.Diff
+//	POST /classify  the diff's story, as the JSON model of diffview.StoryClassification
+//
+// Errors are JSON objects with an "error" field.
+type Server struct {
+	parser      diffview.Parser
+	classifier  diffview.StoryClassifier
+	apiKeys     []string
+	maxBodySize int64
+	slots       chan struct{}
+	mux         *http.ServeMux
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithAPIKeys requires requests to carry one of keys, as a bearer token in
+// the Authorization header or in the X-API-Key header. Without keys every
+// request is served, which suits only a server on a private address.
+func WithAPIKeys(keys ...string) ServerOption {
+	return func(s *Server) {
+		s.apiKeys = keys
+	}
+}
+
+// WithMaxConcurrent sets how many requests the server handles at once.
+// Requests beyond that are turned away with 429 Too Many Requests rather
+// than queued, so callers can back off.
+func WithMaxConcurrent(n int) ServerOption {
+	return func(s *Server) {
+		if n > 0 {
+			s.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithMaxBodySize sets the largest diff, in bytes, the server accepts.
+func WithMaxBodySize(n int64) ServerOption {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxBodySize = n
+		}
+	}
+}
+
+// NewServer creates a server parsing diffs with parser and classifying them
+// with classifier.
+func NewServer(parser diffview.Parser, classifier diffview.StoryClassifier, opts ...ServerOption) *Server {
+	s := &Server{
+		parser:      parser,
+		classifier:  classifier,
+		maxBodySize: DefaultMaxBodySize,
+		slots:       make(chan struct{}, DefaultMaxConcurrent),
+		mux:         http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("POST /parse", s.handleParse)
+	s.mux.HandleFunc("POST /classify", s.handleClassify)
+	return s
+}
+
+// ServeHTTP authenticates the request and, if a slot is free, routes it.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API key"))
+		return
+	}
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, errors.New("too many concurrent requests"))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether r carries one of the server's API keys, or
+// the server needs none.
+func (s *Server) authorized(r *http.Request) bool {
+	if len(s.apiKeys) == 0 {
+		return true
+	}
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if key == "" {
+		return false
+	}
+	ok := false
+	for _, k := range s.apiKeys {
+		// Every key is compared, in constant time, not to leak which matched
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+func (s *Server) handleParse(w http.ResponseWriter, r *http.Request) {
+	diff, ok := s.parse(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
+
+func (s *Server) handleClassify(w http.ResponseWriter, r *http.Request) {
+	diff, ok := s.parse(w, r)
+	if !ok {
+		return
+	}
+	if len(diff.Files) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no changes to classify"))
+		return
+	}
+	story, err := s.classifier.Classify(r.Context(), diffview.ClassificationInput{Diff: *diff})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to classify diff: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, story)
+}
+
+// parse parses the request's body as a diff, or writes the error and
+// reports false.
+func (s *Server) parse(w http.ResponseWriter, r *http.Request) (*diffview.Diff, bool) {
+	diff, err := s.parser.Parse(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("diff larger than %d bytes", tooLarge.Limit))
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to parse diff: %w", err))
+		return nil, false
+	}
+	return diff, true
+}
+
+// writeJSON writes v as the response's JSON body with status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// The status is sent; a failed write leaves nothing to report to
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response with status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,232 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/gitdiff"
+	diffhttp "github.com/fwojciec/diffstory/http"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rawDiff = "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-old\n+new\n"
+
+func storyClassifier(story *diffview.StoryClassification) *mock.StoryClassifier {
+	return &mock.StoryClassifier{
+		ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			return story, nil
+		},
+	}
+}
+
+func post(t *testing.T, handler http.Handler, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorMessage(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error string `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Error
+}
+
+func TestServer_Parse(t *testing.T) {
+	t.Parallel()
+
+	server := diffhttp.NewServer(gitdiff.NewParser(), storyClassifier(nil))
+
+	rec := post(t, server, "/parse", rawDiff, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	diff, err := diffview.DecodeDiff(rec.Body)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, "main.go", diff.Files[0].NewPath)
+}
+
+func TestServer_Parse_MalformedDiff(t *testing.T) {
+	t.Parallel()
+
+	parser := &mock.Parser{ParseFn: func(r io.Reader) (*diffview.Diff, error) {
+		return nil, errors.New("bad hunk header")
+	}}
+	server := diffhttp.NewServer(parser, storyClassifier(nil))
+
+	rec := post(t, server, "/parse", "garbage", nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, errorMessage(t, rec), "bad hunk header")
+}
+
+func TestServer_Classify(t *testing.T) {
+	t.Parallel()
+
+	var got diffview.ClassificationInput
+	classifier := &mock.StoryClassifier{
+		ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			got = input
+			return &diffview.StoryClassification{ChangeType: "bugfix", Summary: "Fix it"}, nil
+		},
+	}
+	server := diffhttp.NewServer(gitdiff.NewParser(), classifier)
+
+	rec := post(t, server, "/classify", rawDiff, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var story diffview.StoryClassification
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &story))
+	assert.Equal(t, "bugfix", story.ChangeType)
+	assert.Equal(t, "Fix it", story.Summary)
+	require.Len(t, got.Diff.Files, 1)
+	assert.Equal(t, "main.go", got.Diff.Files[0].NewPath)
+}
+
+func TestServer_Classify_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty diff", func(t *testing.T) {
+		t.Parallel()
+
+		server := diffhttp.NewServer(gitdiff.NewParser(), storyClassifier(nil))
+
+		rec := post(t, server, "/classify", "", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "no changes to classify", errorMessage(t, rec))
+	})
+
+	t.Run("classifier fails", func(t *testing.T) {
+		t.Parallel()
+
+		classifier := &mock.StoryClassifier{
+			ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				return nil, errors.New("quota exceeded")
+			},
+		}
+		server := diffhttp.NewServer(gitdiff.NewParser(), classifier)
+
+		rec := post(t, server, "/classify", rawDiff, nil)
+
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Contains(t, errorMessage(t, rec), "quota exceeded")
+	})
+}
+
+func TestServer_Routes(t *testing.T) {
+	t.Parallel()
+
+	server := diffhttp.NewServer(gitdiff.NewParser(), storyClassifier(nil))
+
+	t.Run("wrong method", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/parse", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		t.Parallel()
+
+		rec := post(t, server, "/nope", rawDiff, nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestServer_APIKeys(t *testing.T) {
+	t.Parallel()
+
+	server := diffhttp.NewServer(gitdiff.NewParser(), storyClassifier(nil),
+		diffhttp.WithAPIKeys("first-key", "second-key"))
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"no key", nil, http.StatusUnauthorized},
+		{"wrong key", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"bearer token", map[string]string{"Authorization": "Bearer first-key"}, http.StatusOK},
+		{"X-API-Key header", map[string]string{"X-API-Key": "second-key"}, http.StatusOK},
+		{"other scheme", map[string]string{"Authorization": "Basic first-key"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := post(t, server, "/parse", rawDiff, tt.header)
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestServer_MaxConcurrent(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	classifier := &mock.StoryClassifier{
+		ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			started <- struct{}{}
+			<-release
+			return &diffview.StoryClassification{}, nil
+		},
+	}
+	server := diffhttp.NewServer(gitdiff.NewParser(), classifier, diffhttp.WithMaxConcurrent(1))
+
+	var wg sync.WaitGroup
+	var first *httptest.ResponseRecorder
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		first = post(t, server, "/classify", rawDiff, nil)
+	}()
+	<-started
+
+	busy := post(t, server, "/parse", rawDiff, nil)
+	close(release)
+	wg.Wait()
+	after := post(t, server, "/parse", rawDiff, nil)
+
+	assert.Equal(t, http.StatusTooManyRequests, busy.Code)
+	assert.Equal(t, "1", busy.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, after.Code)
+}
+
+func TestServer_MaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	server := diffhttp.NewServer(gitdiff.NewParser(), storyClassifier(nil), diffhttp.WithMaxBodySize(16))
+
+	rec := post(t, server, "/parse", rawDiff, nil)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, "diff larger than 16 bytes", errorMessage(t, rec))
+}
@@ -0,0 +1,40 @@
+package diffview
+
+// commitType returns the conventional commit type of the classifier's
+// change type. Any other change type is a chore.
+func commitType(changeType string) string {
+	switch changeType {
+	case "feature":
+		return "feat"
+	case "bugfix":
+		return "fix"
+	case "refactor", "docs":
+		return changeType
+	}
+	return "chore"
+}
+
+// InferImpact returns the commit type of story and the version bump its
+// change calls for given the exported API it changes: major if it removes
+// or changes identifiers or its risk names breaking changes, minor if it
+// adds identifiers or is a feature, and patch otherwise.
+func InferImpact(story *StoryClassification, changes []APIChange) Impact {
+	impact := Impact{CommitType: commitType(story.ChangeType)}
+	for _, c := range changes {
+		if c.Kind == "added" {
+			impact.Added = append(impact.Added, c.Symbol)
+		} else {
+			impact.Breaking = append(impact.Breaking, c.Symbol)
+		}
+	}
+
+	switch {
+	case len(impact.Breaking) > 0 || (story.Risk != nil && len(story.Risk.Breaking) > 0):
+		impact.Bump = "major"
+	case len(impact.Added) > 0 || impact.CommitType == "feat":
+		impact.Bump = "minor"
+	default:
+		impact.Bump = "patch"
+	}
+	return impact
+}
@@ -0,0 +1,69 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferImpact(t *testing.T) {
+	t.Parallel()
+
+	t.Run("removed and changed identifiers are breaking", func(t *testing.T) {
+		t.Parallel()
+
+		impact := diffview.InferImpact(&diffview.StoryClassification{ChangeType: "feature"}, []diffview.APIChange{
+			{Symbol: "server.Config.Timeout", Kind: "added"},
+			{Symbol: "server.Server.Start", Kind: "changed"},
+			{Symbol: "server.Server.Stop", Kind: "removed"},
+		})
+
+		assert.Equal(t, diffview.Impact{
+			CommitType: "feat",
+			Bump:       "major",
+			Breaking:   []string{"server.Server.Start", "server.Server.Stop"},
+			Added:      []string{"server.Config.Timeout"},
+		}, impact)
+		assert.Equal(t, "feat!", impact.ConventionalType())
+	})
+
+	t.Run("added identifiers are a minor bump", func(t *testing.T) {
+		t.Parallel()
+
+		impact := diffview.InferImpact(&diffview.StoryClassification{ChangeType: "refactor"}, []diffview.APIChange{
+			{Symbol: "server.Server.Addr", Kind: "added"},
+		})
+
+		assert.Equal(t, "refactor", impact.CommitType)
+		assert.Equal(t, "minor", impact.Bump)
+		assert.Equal(t, "refactor", impact.ConventionalType())
+	})
+
+	t.Run("no API changes are a patch", func(t *testing.T) {
+		t.Parallel()
+
+		impact := diffview.InferImpact(&diffview.StoryClassification{ChangeType: "bugfix"}, nil)
+
+		assert.Equal(t, diffview.Impact{CommitType: "fix", Bump: "patch"}, impact)
+	})
+
+	t.Run("breaking risk is a major bump", func(t *testing.T) {
+		t.Parallel()
+
+		impact := diffview.InferImpact(&diffview.StoryClassification{
+			ChangeType: "chore",
+			Risk:       &diffview.Risk{Level: "high", Breaking: []string{"Config file renamed"}},
+		}, nil)
+
+		assert.Equal(t, "major", impact.Bump)
+	})
+
+	t.Run("unknown change types are chores", func(t *testing.T) {
+		t.Parallel()
+
+		impact := diffview.InferImpact(&diffview.StoryClassification{ChangeType: "perf"}, nil)
+
+		assert.Equal(t, "chore", impact.CommitType)
+	})
+}
@@ -0,0 +1,87 @@
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.AnnotationStore = (*AnnotationStore)(nil)
+
+// AnnotationStore records review notes as an append-only JSONL log.
+type AnnotationStore struct{}
+
+// NewAnnotationStore creates a new AnnotationStore.
+func NewAnnotationStore() *AnnotationStore {
+	return &AnnotationStore{}
+}
+
+// Load reads annotations from a JSONL file. Returns empty slice if file doesn't exist.
+func (s *AnnotationStore) Load(path string) ([]diffview.Annotation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var annotations []diffview.Annotation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, maxLineSize), maxLineSize)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var a diffview.Annotation
+		if err := json.Unmarshal([]byte(line), &a); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		annotations = append(annotations, a)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+// Append adds an annotation to a JSONL file, creating parent directories if needed.
+func (s *AnnotationStore) Append(path string, a diffview.Annotation) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	if _, err := f.WriteString("\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
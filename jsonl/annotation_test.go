@@ -0,0 +1,61 @@
+package jsonl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationStore_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns empty slice for non-existent file", func(t *testing.T) {
+		t.Parallel()
+
+		store := jsonl.NewAnnotationStore()
+		annotations, err := store.Load("/nonexistent/path.jsonl")
+
+		require.NoError(t, err)
+		assert.Empty(t, annotations)
+	})
+
+	t.Run("returns error for malformed JSON", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "bad.jsonl")
+		content := `{"path":"api.go","text":"ok"}
+not valid json`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		store := jsonl.NewAnnotationStore()
+		_, err := store.Load(path)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
+
+func TestAnnotationStore_Append(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "notes.jsonl")
+	createdAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	store := jsonl.NewAnnotationStore()
+	first := diffview.Annotation{Path: "api.go", Text: "Needs tests", CreatedAt: createdAt}
+	second := diffview.Annotation{Path: "api.go", Hunk: 1, Line: 12, Text: "Off by one?", CreatedAt: createdAt.Add(time.Minute)}
+	require.NoError(t, store.Append(path, first))
+	require.NoError(t, store.Append(path, second))
+
+	annotations, err := store.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []diffview.Annotation{first, second}, annotations)
+}
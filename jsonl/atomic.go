@@ -0,0 +1,87 @@
+package jsonl
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// backupSuffix names the copy of a file kept from before its last rewrite.
+const backupSuffix = ".bak"
+
+// replaceFile replaces the file at path with what write writes, creating
+// parent directories if needed. The new content goes to a temporary file
+// that is synced and then renamed over path, so a crash leaves either the
+// old file or the new one, never a truncated mix. The old file is kept as
+// path+".bak", replacing the previous backup.
+func replaceFile(path string, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	w := bufio.NewWriter(tmp)
+	if err := write(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp makes the file private; judgments are as readable as before
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	if err := backup(path); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// backup links the file at path to its backup name, replacing the previous
+// backup. There's nothing to back up if the file doesn't exist yet.
+func backup(path string) error {
+	bak := path + backupSuffix
+	if err := os.Remove(bak); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Link(path, bak); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// syncDir flushes dir's entries, so a rename in it survives a crash. It's
+// best-effort: some platforms can't sync directories, and the rename has
+// already happened.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	_ = d.Sync()
+}
@@ -0,0 +1,34 @@
+package jsonl
+
+import "github.com/fwojciec/diffstory"
+
+// StoreOption configures how a store reads records that refer to cases.
+type StoreOption func(*caseRefs)
+
+// WithLegacyCaseIDs has a store map the case IDs of records saved before
+// content IDs, which were repo/branch names, forward to the IDs of the
+// cases they name.
+func WithLegacyCaseIDs(ids diffview.LegacyCaseIDs) StoreOption {
+	return func(r *caseRefs) {
+		r.legacy = ids
+	}
+}
+
+// caseRefs rewrites the case IDs records were saved with to current ones.
+type caseRefs struct {
+	legacy diffview.LegacyCaseIDs
+}
+
+func newCaseRefs(opts []StoreOption) caseRefs {
+	var r caseRefs
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// forward returns the current ID for id. Content IDs never look like
+// repo/branch names, so only legacy IDs change.
+func (r caseRefs) forward(id string) string {
+	return r.legacy.Forward(id)
+}
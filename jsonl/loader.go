@@ -26,7 +26,8 @@ func NewLoader() *Loader {
 // This accommodates large PR-level diffs while preventing memory issues.
 const maxLineSize = 4 * 1024 * 1024
 
-// Load reads a JSONL file and returns all EvalCase records.
+// Load reads a JSONL file and returns all EvalCase records. Cases saved
+// without an ID, before content IDs, are given their content ID.
 func (l *Loader) Load(path string) ([]diffview.EvalCase, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -50,6 +51,9 @@ func (l *Loader) Load(path string) ([]diffview.EvalCase, error) {
 		if err := json.Unmarshal([]byte(line), &c); err != nil {
 			return nil, fmt.Errorf("line %d: %w", lineNum, err)
 		}
+		if c.ID == "" {
+			c.ID = c.Input.ContentID()
+		}
 		cases = append(cases, c)
 	}
 
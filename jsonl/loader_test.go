@@ -111,3 +111,21 @@ not valid json
 		assert.Equal(t, "abc123", cases[0].Input.FirstCommitHash())
 	})
 }
+
+func TestLoader_Load_PathsOnlyCases(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shared.jsonl")
+	content := `{"input":{"repo":"r","branch":"b","commits":[{"hash":"abc","message":""}],"diff":{"Files":[{"NewPath":"a.go","Hunks":[{"NewStart":1,"NewCount":1,"Lines":[{"Type":1,"Content":"","NewLineNum":1}]}]}]},"paths_only":true},"story":{"change_type":"feature","narrative":"","summary":"s","sections":null}}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	loader := jsonl.NewLoader()
+	cases, err := loader.Load(path)
+
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	assert.True(t, cases[0].Input.PathsOnly)
+	added, _ := cases[0].Input.Diff.Files[0].Stats()
+	assert.Equal(t, 1, added)
+}
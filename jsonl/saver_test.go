@@ -93,6 +93,27 @@ func TestSaver_Save(t *testing.T) {
 		require.NoError(t, err)
 		assert.FileExists(t, path)
 	})
+
+	t.Run("round-trips the gold classification through the loader", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "gold.jsonl")
+
+		evalCase := diffview.EvalCase{
+			Input: diffview.ClassificationInput{Repo: "test"},
+			Story: &diffview.StoryClassification{Summary: "model"},
+			Gold: &diffview.StoryClassification{Summary: "corrected", Sections: []diffview.Section{
+				{Title: "The fix", Hunks: []diffview.HunkRef{{File: "a.go", HunkIndex: 0, Category: "core"}}},
+			}},
+		}
+		require.NoError(t, jsonl.NewSaver().Save(path, evalCase))
+
+		loaded, err := jsonl.NewLoader().Load(path)
+		require.NoError(t, err)
+		require.Len(t, loaded, 1)
+		assert.Equal(t, evalCase.Gold, loaded[0].Gold)
+	})
 }
 
 func splitLines(s string) []string {
@@ -0,0 +1,96 @@
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.ReviewSessionStore = (*SessionStore)(nil)
+
+// SessionStore records review sessions as an append-only JSONL log.
+type SessionStore struct {
+	refs caseRefs
+}
+
+// NewSessionStore creates a new SessionStore.
+func NewSessionStore(opts ...StoreOption) *SessionStore {
+	return &SessionStore{refs: newCaseRefs(opts)}
+}
+
+// Load reads sessions from a JSONL file. Returns empty slice if file doesn't exist.
+func (s *SessionStore) Load(path string) ([]diffview.ReviewSession, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var sessions []diffview.ReviewSession
+	scanner := bufio.NewScanner(f)
+	// Each session lists every case and judgment, so lines can be long
+	scanner.Buffer(make([]byte, maxLineSize), maxLineSize)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var session diffview.ReviewSession
+		if err := json.Unmarshal([]byte(line), &session); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		for i, id := range session.CaseIDs {
+			session.CaseIDs[i] = s.refs.forward(id)
+		}
+		for i := range session.Judgments {
+			session.Judgments[i].CaseID = s.refs.forward(session.Judgments[i].CaseID)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Append adds a session to a JSONL file, creating parent directories if needed.
+func (s *SessionStore) Append(path string, session diffview.ReviewSession) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	if _, err := f.WriteString("\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package jsonl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStore_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns empty slice for non-existent file", func(t *testing.T) {
+		t.Parallel()
+
+		store := jsonl.NewSessionStore()
+		sessions, err := store.Load("/nonexistent/path.jsonl")
+
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("returns error for malformed JSON", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "bad.jsonl")
+		content := `{"case_ids":["repo/a"]}
+not valid json`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		store := jsonl.NewSessionStore()
+		_, err := store.Load(path)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
+
+func TestSessionStore_Append(t *testing.T) {
+	t.Parallel()
+
+	t.Run("appends sessions without rewriting earlier ones", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "nested", "sessions.jsonl")
+		endedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+		store := jsonl.NewSessionStore()
+		require.NoError(t, store.Append(path, diffview.ReviewSession{
+			EndedAt: endedAt,
+			CaseIDs: []string{"repo/a", "repo/b"},
+		}))
+		require.NoError(t, store.Append(path, diffview.ReviewSession{
+			EndedAt:   endedAt.Add(time.Hour),
+			CaseIDs:   []string{"repo/a"},
+			Judgments: []diffview.Judgment{{CaseID: "repo/a", Judged: true, Pass: true}},
+		}))
+
+		sessions, err := store.Load(path)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, []string{"repo/a", "repo/b"}, sessions[0].CaseIDs)
+		assert.Empty(t, sessions[0].Judgments)
+		assert.True(t, endedAt.Add(time.Hour).Equal(sessions[1].EndedAt))
+		require.Len(t, sessions[1].Judgments, 1)
+		assert.True(t, sessions[1].Judgments[0].Pass)
+	})
+}
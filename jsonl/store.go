@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/fwojciec/diffstory"
@@ -16,11 +16,13 @@ import (
 var _ diffview.JudgmentStore = (*Store)(nil)
 
 // Store persists and retrieves Judgment records as JSONL.
-type Store struct{}
+type Store struct {
+	refs caseRefs
+}
 
 // NewStore creates a new Store.
-func NewStore() *Store {
-	return &Store{}
+func NewStore(opts ...StoreOption) *Store {
+	return &Store{refs: newCaseRefs(opts)}
 }
 
 // Load reads judgments from a JSONL file. Returns empty slice if file doesn't exist.
@@ -49,6 +51,10 @@ func (s *Store) Load(path string) ([]diffview.Judgment, error) {
 		if err := json.Unmarshal([]byte(line), &j); err != nil {
 			return nil, fmt.Errorf("line %d: %w", lineNum, err)
 		}
+		if err := migrateJudgment(&j); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		j.CaseID = s.refs.forward(j.CaseID)
 		judgments = append(judgments, j)
 	}
 
@@ -59,30 +65,34 @@ func (s *Store) Load(path string) ([]diffview.Judgment, error) {
 	return judgments, nil
 }
 
-// Save writes judgments to a JSONL file, creating parent directories if needed.
-func (s *Store) Save(path string, judgments []diffview.Judgment) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
+// migrateJudgment upgrades j, as read from a file, to the current schema.
+// Judgments from before versioning are version 1. A version newer than this
+// build knows is an error, since saving would drop the fields it can't read.
+func migrateJudgment(j *diffview.Judgment) error {
+	if j.Version == 0 {
+		j.Version = 1
 	}
-
-	f, err := os.Create(path)
-	if err != nil {
-		return err
+	if j.Version > diffview.JudgmentVersion {
+		return fmt.Errorf("judgment version %d is newer than supported version %d", j.Version, diffview.JudgmentVersion)
 	}
-	defer f.Close()
+	// Later versions only added optional fields, which older judgments leave
+	// unset, and changed case IDs, which the store maps forward
+	j.Version = diffview.JudgmentVersion
+	return nil
+}
 
-	for _, j := range judgments {
-		data, err := json.Marshal(j)
-		if err != nil {
-			return err
-		}
-		if _, err := f.Write(data); err != nil {
-			return err
-		}
-		if _, err := f.WriteString("\n"); err != nil {
-			return err
+// Save writes judgments to a JSONL file, creating parent directories if
+// needed. Every judgment is written with the current schema version. The
+// file is replaced atomically, keeping the previous one as path+".bak".
+func (s *Store) Save(path string, judgments []diffview.Judgment) error {
+	return replaceFile(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		for _, j := range judgments {
+			j.Version = diffview.JudgmentVersion
+			if err := enc.Encode(j); err != nil {
+				return err
+			}
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
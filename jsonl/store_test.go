@@ -46,6 +46,38 @@ func TestStore_Load(t *testing.T) {
 		assert.Empty(t, judgments)
 	})
 
+	t.Run("migrates unversioned judgments to the current version", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "judgments.jsonl")
+		content := `{"case_id":"repo/branch","index":0,"judged":true,"pass":false,"critique":"Wrong story","judged_at":"2025-01-15T10:30:00Z"}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		judgments, err := jsonl.NewStore().Load(path)
+
+		require.NoError(t, err)
+		require.Len(t, judgments, 1)
+		assert.Equal(t, diffview.JudgmentVersion, judgments[0].Version)
+		assert.Equal(t, "Wrong story", judgments[0].Critique)
+		assert.Empty(t, judgments[0].Category)
+		assert.Empty(t, judgments[0].Severity)
+	})
+
+	t.Run("rejects judgments from a newer version", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "judgments.jsonl")
+		content := `{"version":99,"case_id":"repo/branch","index":0}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		_, err := jsonl.NewStore().Load(path)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 1: judgment version 99 is newer")
+	})
+
 	t.Run("returns error for malformed JSON", func(t *testing.T) {
 		t.Parallel()
 
@@ -105,6 +137,54 @@ func TestStore_Save(t *testing.T) {
 		assert.Equal(t, "Wrong analysis", loaded[1].Critique)
 	})
 
+	t.Run("writes structured critique fields with the current version", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "judgments.jsonl")
+
+		store := jsonl.NewStore()
+		require.NoError(t, store.Save(path, []diffview.Judgment{{
+			CaseID:   "repo/branch",
+			Judged:   true,
+			Critique: "Section 2 mixes the API and the store",
+			Category: diffview.CategoryBadGrouping,
+			Severity: diffview.SeverityMajor,
+		}}))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"version":6`)
+		assert.Contains(t, string(data), `"category":"bad_grouping","severity":"major"`)
+
+		loaded, err := store.Load(path)
+		require.NoError(t, err)
+		require.Len(t, loaded, 1)
+		assert.Equal(t, diffview.CategoryBadGrouping, loaded[0].Category)
+		assert.Equal(t, diffview.SeverityMajor, loaded[0].Severity)
+	})
+
+	t.Run("round-trips the gold label", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "judgments.jsonl")
+		gold := &diffview.StoryClassification{
+			Summary: "Fix the cache",
+			Sections: []diffview.Section{{Title: "The fix", Hunks: []diffview.HunkRef{
+				{File: "cache.go", HunkIndex: 0, Category: "core"},
+			}}},
+		}
+
+		store := jsonl.NewStore()
+		require.NoError(t, store.Save(path, []diffview.Judgment{{CaseID: "repo/branch", Gold: gold}}))
+
+		loaded, err := store.Load(path)
+		require.NoError(t, err)
+		require.Len(t, loaded, 1)
+		assert.Equal(t, gold, loaded[0].Gold)
+	})
+
 	t.Run("overwrites existing file", func(t *testing.T) {
 		t.Parallel()
 
@@ -127,6 +207,31 @@ func TestStore_Save(t *testing.T) {
 		assert.Equal(t, "repo/new-branch", loaded[0].CaseID)
 	})
 
+	t.Run("keeps the previous file as a backup and no temporary files", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "judgments.jsonl")
+		require.NoError(t, os.WriteFile(path, []byte("old content"), 0o644))
+
+		store := jsonl.NewStore()
+		require.NoError(t, store.Save(path, []diffview.Judgment{{CaseID: "repo/first", Pass: true}}))
+		require.NoError(t, store.Save(path, []diffview.Judgment{{CaseID: "repo/second", Pass: true}}))
+
+		backup, err := store.Load(path + ".bak")
+		require.NoError(t, err)
+		require.Len(t, backup, 1)
+		assert.Equal(t, "repo/first", backup[0].CaseID)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		assert.ElementsMatch(t, []string{"judgments.jsonl", "judgments.jsonl.bak"}, names)
+	})
+
 	t.Run("creates parent directories", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,89 @@
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.TombstoneStore = (*TombstoneStore)(nil)
+
+// TombstoneStore records case deletions as an append-only JSONL log.
+type TombstoneStore struct {
+	refs caseRefs
+}
+
+// NewTombstoneStore creates a new TombstoneStore.
+func NewTombstoneStore(opts ...StoreOption) *TombstoneStore {
+	return &TombstoneStore{refs: newCaseRefs(opts)}
+}
+
+// Load reads tombstones from a JSONL file. Returns empty slice if file doesn't exist.
+func (s *TombstoneStore) Load(path string) ([]diffview.Tombstone, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var tombstones []diffview.Tombstone
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var t diffview.Tombstone
+		if err := json.Unmarshal([]byte(line), &t); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		t.CaseID = s.refs.forward(t.CaseID)
+		tombstones = append(tombstones, t)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return tombstones, nil
+}
+
+// Append adds a tombstone to a JSONL file, creating parent directories if needed.
+func (s *TombstoneStore) Append(path string, t diffview.Tombstone) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	if _, err := f.WriteString("\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package jsonl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTombstoneStore_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads valid tombstones file", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "tombstones.jsonl")
+		content := `{"case_id":"repo/branch-a","deleted_at":"2025-01-15T10:30:00Z"}
+
+{"case_id":"repo/branch-b","deleted_at":"2025-01-15T10:31:00Z"}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		store := jsonl.NewTombstoneStore()
+		tombstones, err := store.Load(path)
+
+		require.NoError(t, err)
+		require.Len(t, tombstones, 2)
+		assert.Equal(t, "repo/branch-a", tombstones[0].CaseID)
+		assert.Equal(t, "repo/branch-b", tombstones[1].CaseID)
+	})
+
+	t.Run("returns empty slice for non-existent file", func(t *testing.T) {
+		t.Parallel()
+
+		store := jsonl.NewTombstoneStore()
+		tombstones, err := store.Load("/nonexistent/path.jsonl")
+
+		require.NoError(t, err)
+		assert.Empty(t, tombstones)
+	})
+
+	t.Run("returns error for malformed JSON", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "bad.jsonl")
+		content := `{"case_id":"repo/branch"}
+not valid json`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		store := jsonl.NewTombstoneStore()
+		_, err := store.Load(path)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
+
+func TestTombstoneStore_Append(t *testing.T) {
+	t.Parallel()
+
+	t.Run("appends tombstones without rewriting existing entries", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "nested", "tombstones.jsonl")
+		deletedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+		store := jsonl.NewTombstoneStore()
+		require.NoError(t, store.Append(path, diffview.Tombstone{CaseID: "repo/a", DeletedAt: deletedAt}))
+		require.NoError(t, store.Append(path, diffview.Tombstone{CaseID: "repo/b", DeletedAt: deletedAt}))
+
+		tombstones, err := store.Load(path)
+		require.NoError(t, err)
+		require.Len(t, tombstones, 2)
+		assert.Equal(t, "repo/a", tombstones[0].CaseID)
+		assert.Equal(t, "repo/b", tombstones[1].CaseID)
+		assert.True(t, deletedAt.Equal(tombstones[1].DeletedAt))
+	})
+}
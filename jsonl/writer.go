@@ -0,0 +1,26 @@
+package jsonl
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.EvalCaseWriter = (*Writer)(nil)
+
+// Writer streams EvalCase records as JSONL to an io.Writer.
+type Writer struct {
+	enc *json.Encoder
+}
+
+// NewWriter creates a Writer that encodes cases to w, one per line.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{enc: json.NewEncoder(w)}
+}
+
+// Write encodes a single EvalCase followed by a newline.
+func (w *Writer) Write(c diffview.EvalCase) error {
+	return w.enc.Encode(c)
+}
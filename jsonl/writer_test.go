@@ -0,0 +1,27 @@
+package jsonl_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/jsonl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := jsonl.NewWriter(&buf)
+
+	require.NoError(t, w.Write(diffview.EvalCase{Input: diffview.ClassificationInput{Repo: "repo", Branch: "a"}}))
+	require.NoError(t, w.Write(diffview.EvalCase{Input: diffview.ClassificationInput{Repo: "repo", Branch: "b"}}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"branch":"a"`)
+	assert.Contains(t, lines[1], `"branch":"b"`)
+}
@@ -0,0 +1,159 @@
+package diffview
+
+// Layout is where a view of a diff draws each commit header, file and
+// hunk, in rows from the top of the view. Views navigate by it and report
+// positions from it, so that every view agrees with what it draws.
+type Layout struct {
+	Commits []int // Row of each commit header, for diffs of several commits
+	Groups  []int // Row of each file group's header, when files are grouped
+	Files   []int // First row of each visible file: its header, or a banner above it
+	Hunks   []int // Row of each visible hunk's header, or of its line when collapsed
+	Rows    int   // Rows in all
+}
+
+// LayoutOptions describes what a view draws of a diff besides its headers
+// and lines. Each func is optional; file and hunk index the diff's Files
+// and that file's Hunks.
+type LayoutOptions struct {
+	// LineRows returns the rows a line takes, more than one when it's
+	// soft-wrapped. Nil means one row each.
+	LineRows func(line Line) int
+
+	// Banner reports whether a banner row opens the hunk. The banner of a
+	// file's first hunk goes above the file's header.
+	Banner func(file, hunk int) bool
+
+	// Note reports whether a note row sits above the hunk's header.
+	Note func(file, hunk int) bool
+
+	// Collapsed reports whether the hunk is drawn as a single row in place
+	// of its header and lines.
+	Collapsed func(file, hunk int) bool
+
+	// Groups are the file groups the view draws a header row above.
+	Groups []FileGroup
+}
+
+// NewLayout lays out diff as viewers draw it: each commit's header above
+// its first file, each file group's header below that, then each visible
+// file's header followed by its hunks, or by an "(empty)" row if it has
+// none, and each hunk's header followed by its lines.
+func NewLayout(diff *Diff, opts LayoutOptions) Layout {
+	var l Layout
+	if diff == nil {
+		return l
+	}
+	has := func(f func(file, hunk int) bool, file, hunk int) bool {
+		return f != nil && f(file, hunk)
+	}
+
+	nextCommit := 0
+	commits := func(fileIdx int) {
+		for ; nextCommit < len(diff.Commits) && diff.Commits[nextCommit].FirstFile <= fileIdx; nextCommit++ {
+			l.Commits = append(l.Commits, l.Rows)
+			l.Rows++
+		}
+	}
+	nextGroup := 0
+	for fileIdx, file := range diff.Files {
+		commits(fileIdx)
+		if nextGroup < len(opts.Groups) && opts.Groups[nextGroup].FirstFile == fileIdx {
+			l.Groups = append(l.Groups, l.Rows)
+			l.Rows++
+			nextGroup++
+		}
+		if !file.Visible() {
+			continue
+		}
+		l.Files = append(l.Files, l.Rows)
+		if len(file.Hunks) > 0 && has(opts.Banner, fileIdx, 0) {
+			l.Rows++
+		}
+		l.Rows++ // file header
+		if len(file.Hunks) == 0 {
+			l.Rows++ // "(empty)"
+			continue
+		}
+		for hunkIdx, hunk := range file.Hunks {
+			if hunkIdx > 0 && has(opts.Banner, fileIdx, hunkIdx) {
+				l.Rows++
+			}
+			if has(opts.Note, fileIdx, hunkIdx) {
+				l.Rows++
+			}
+			l.Hunks = append(l.Hunks, l.Rows)
+			l.Rows++ // header, or the collapsed hunk
+			if has(opts.Collapsed, fileIdx, hunkIdx) {
+				continue
+			}
+			if opts.LineRows == nil {
+				l.Rows += len(hunk.Lines)
+				continue
+			}
+			for _, line := range hunk.Lines {
+				l.Rows += opts.LineRows(line)
+			}
+		}
+	}
+	commits(len(diff.Files))
+	return l
+}
+
+// Append adds other below l, as when a view draws several diffs one after
+// another.
+func (l *Layout) Append(other Layout) {
+	for _, row := range other.Commits {
+		l.Commits = append(l.Commits, l.Rows+row)
+	}
+	for _, row := range other.Groups {
+		l.Groups = append(l.Groups, l.Rows+row)
+	}
+	for _, row := range other.Files {
+		l.Files = append(l.Files, l.Rows+row)
+	}
+	for _, row := range other.Hunks {
+		l.Hunks = append(l.Hunks, l.Rows+row)
+	}
+	l.Rows += other.Rows
+}
+
+// Position returns which of positions, rows such as a Layout's Files, row
+// is at, counting from 1: the last one at or above row, or the first if
+// row is above them all. It also returns how many positions there are.
+// Both are 0 without positions.
+func Position(positions []int, row int) (current, total int) {
+	if len(positions) == 0 {
+		return 0, 0
+	}
+	current = 1
+	for i, pos := range positions {
+		if pos > row {
+			break
+		}
+		current = i + 1
+	}
+	return current, len(positions)
+}
+
+// At returns the file and hunk row falls in, as indexes into Files and
+// Hunks, or -1 for none. A row between a file's start and its first hunk,
+// such as the file's header, is in the file but in no hunk.
+func (l Layout) At(row int) (file, hunk int) {
+	file, hunk = -1, -1
+	for i, start := range l.Files {
+		if start > row {
+			break
+		}
+		file = i
+	}
+	for i, start := range l.Hunks {
+		if start > row {
+			break
+		}
+		hunk = i
+	}
+	if hunk >= 0 && (file < 0 || l.Hunks[hunk] < l.Files[file]) {
+		hunk = -1
+	}
+	return file, hunk
+}
@@ -0,0 +1,136 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func hunkOf(n int) diffview.Hunk {
+	return diffview.Hunk{Lines: make([]diffview.Line, n)}
+}
+
+func TestNewLayout(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{NewPath: "a.go", Hunks: []diffview.Hunk{hunkOf(2), hunkOf(3)}},
+			{NewPath: "logo.png", IsBinary: true},
+			{NewPath: "empty.txt", Operation: diffview.FileAdded},
+			{NewPath: "b.go", Hunks: []diffview.Hunk{hunkOf(1)}},
+		},
+		Commits: []diffview.Commit{{FirstFile: 0}, {FirstFile: 3}},
+	}
+
+	t.Run("counts headers, lines and empty files", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, diffview.Layout{
+			Commits: []int{0, 11},
+			Files:   []int{1, 9, 12},
+			Hunks:   []int{2, 5, 13},
+			Rows:    15,
+		}, diffview.NewLayout(diff, diffview.LayoutOptions{}))
+	})
+
+	t.Run("adds banners, notes, collapsed hunks and wrapped lines", func(t *testing.T) {
+		t.Parallel()
+
+		layout := diffview.NewLayout(diff, diffview.LayoutOptions{
+			LineRows:  func(diffview.Line) int { return 2 },
+			Banner:    func(file, hunk int) bool { return hunk == 0 },
+			Note:      func(file, hunk int) bool { return file == 0 && hunk == 1 },
+			Collapsed: func(file, hunk int) bool { return file == 3 },
+		})
+
+		// The first hunk's banner goes above its file's header
+		assert.Equal(t, diffview.Layout{
+			Commits: []int{0, 18},
+			Files:   []int{1, 16, 19},
+			Hunks:   []int{3, 9, 21},
+			Rows:    22,
+		}, layout)
+	})
+
+	t.Run("adds file group headers below commit headers", func(t *testing.T) {
+		t.Parallel()
+
+		layout := diffview.NewLayout(diff, diffview.LayoutOptions{
+			Groups: []diffview.FileGroup{{FirstFile: 0}, {FirstFile: 3}},
+		})
+
+		assert.Equal(t, diffview.Layout{
+			Commits: []int{0, 12},
+			Groups:  []int{1, 13},
+			Files:   []int{2, 10, 14},
+			Hunks:   []int{3, 6, 15},
+			Rows:    17,
+		}, layout)
+	})
+
+	t.Run("lays out nothing for a nil diff", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, diffview.Layout{}, diffview.NewLayout(nil, diffview.LayoutOptions{}))
+	})
+}
+
+func TestLayout_Append(t *testing.T) {
+	t.Parallel()
+
+	layout := diffview.Layout{Files: []int{0}, Hunks: []int{1}, Rows: 4}
+	layout.Append(diffview.Layout{Commits: []int{0}, Groups: []int{1}, Files: []int{1}, Hunks: []int{2, 5}, Rows: 8})
+
+	assert.Equal(t, diffview.Layout{
+		Commits: []int{4},
+		Groups:  []int{5},
+		Files:   []int{0, 5},
+		Hunks:   []int{1, 6, 9},
+		Rows:    12,
+	}, layout)
+}
+
+func TestPosition(t *testing.T) {
+	t.Parallel()
+
+	positions := []int{3, 10, 20}
+	for _, tc := range []struct {
+		row, want int
+	}{
+		{row: 0, want: 1},
+		{row: 3, want: 1},
+		{row: 9, want: 1},
+		{row: 10, want: 2},
+		{row: 99, want: 3},
+	} {
+		current, total := diffview.Position(positions, tc.row)
+		assert.Equal(t, tc.want, current, "row %d", tc.row)
+		assert.Equal(t, 3, total)
+	}
+
+	current, total := diffview.Position(nil, 5)
+	assert.Zero(t, current)
+	assert.Zero(t, total)
+}
+
+func TestLayout_At(t *testing.T) {
+	t.Parallel()
+
+	layout := diffview.Layout{Files: []int{1, 9}, Hunks: []int{2, 5, 10}, Rows: 14}
+	for _, tc := range []struct {
+		row, file, hunk int
+	}{
+		{row: 0, file: -1, hunk: -1},
+		{row: 1, file: 0, hunk: -1},
+		{row: 2, file: 0, hunk: 0},
+		{row: 8, file: 0, hunk: 1},
+		{row: 9, file: 1, hunk: -1},
+		{row: 13, file: 1, hunk: 2},
+	} {
+		file, hunk := layout.At(tc.row)
+		assert.Equal(t, tc.file, file, "file at row %d", tc.row)
+		assert.Equal(t, tc.hunk, hunk, "hunk at row %d", tc.row)
+	}
+}
@@ -0,0 +1,251 @@
+// Package lint annotates diffs with linter findings on the lines they add,
+// running go vet, staticcheck or any linter that reports findings as
+// "file:line: message".
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.Annotator = (*Annotator)(nil)
+
+// DefaultTimeout bounds how long each linter may run.
+const DefaultTimeout = 2 * time.Minute
+
+// Placeholders expanded in linter commands.
+const (
+	PackagesArg = "{packages}" // The changed Go packages, as "./dir" patterns
+	FilesArg    = "{files}"    // The changed files
+)
+
+// Linter is a command that reports findings one per line as
+// "file:line: message" or "file:line:column: message", with paths relative
+// to the directory it runs in.
+type Linter struct {
+	Name    string   // Source of its annotations, such as "vet"
+	Command []string // Program and arguments, which may include PackagesArg or FilesArg
+}
+
+// Vet returns the linter running go vet on the changed packages.
+func Vet() Linter {
+	return Linter{Name: "vet", Command: []string{"go", "vet", PackagesArg}}
+}
+
+// Staticcheck returns the linter running staticcheck on the changed
+// packages.
+func Staticcheck() Linter {
+	return Linter{Name: "staticcheck", Command: []string{"staticcheck", PackagesArg}}
+}
+
+// ParseLinter parses "name=command args", splitting the command on spaces.
+// The names vet and staticcheck alone give Vet and Staticcheck.
+func ParseLinter(spec string) (Linter, error) {
+	switch spec {
+	case Vet().Name:
+		return Vet(), nil
+	case Staticcheck().Name:
+		return Staticcheck(), nil
+	}
+	name, command, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || strings.TrimSpace(command) == "" {
+		return Linter{}, fmt.Errorf("invalid linter %q: want vet, staticcheck or name=command", spec)
+	}
+	return Linter{Name: name, Command: strings.Fields(command)}, nil
+}
+
+// Option configures an Annotator.
+type Option func(*Annotator)
+
+// WithTimeout sets how long each linter may run. Defaults to DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(a *Annotator) {
+		a.timeout = d
+	}
+}
+
+// Annotator runs linters over the files a diff changes and notes the
+// findings on added lines, so reviewers see the issues a change introduces
+// rather than those already there.
+type Annotator struct {
+	dir     string
+	linters []Linter
+	timeout time.Duration
+}
+
+// NewAnnotator creates an Annotator that runs linters in dir, the root of
+// the repository the diff's paths are relative to.
+func NewAnnotator(dir string, linters []Linter, opts ...Option) *Annotator {
+	a := &Annotator{
+		dir:     dir,
+		linters: linters,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Name implements diffview.Annotator.
+func (a *Annotator) Name() string {
+	return "lint"
+}
+
+// Annotate runs each linter and returns its findings on added lines. A
+// linter that can't be run adds nothing.
+func (a *Annotator) Annotate(diff *diffview.Diff) []diffview.Annotation {
+	added := addedLines(diff)
+	if len(added) == 0 {
+		return nil
+	}
+	files := make([]string, 0, len(added))
+	for file := range added {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var notes []diffview.Annotation
+	for _, linter := range a.linters {
+		args, ok := expandArgs(linter.Command, files)
+		if !ok {
+			continue
+		}
+		for _, f := range a.run(args) {
+			if hunk, ok := added[f.path][f.line]; ok {
+				notes = append(notes, diffview.Annotation{
+					Path:   f.path,
+					Hunk:   hunk,
+					Line:   f.line,
+					Text:   f.message,
+					Source: linter.Name,
+					Icon:   "▲",
+				})
+			}
+		}
+	}
+	return notes
+}
+
+// finding is one line of linter output.
+type finding struct {
+	path    string
+	line    int
+	message string
+}
+
+// findingPattern matches "file:line: message", with an optional column.
+var findingPattern = regexp.MustCompile(`^(.+?):(\d+)(?::\d+)?: (.+)$`)
+
+// run runs a linter command in the annotator's directory and parses its
+// findings. Linters exit non-zero when they find something, so the exit
+// status is ignored.
+func (a *Annotator) run(args []string) []finding {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = a.dir
+	output, _ := cmd.CombinedOutput()
+
+	var findings []finding
+	for _, line := range strings.Split(string(output), "\n") {
+		m := findingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		findings = append(findings, finding{path: a.relative(m[1]), line: n, message: m[3]})
+	}
+	return findings
+}
+
+// relative returns a path from linter output relative to the annotator's
+// directory, with forward slashes as in diffs.
+func (a *Annotator) relative(p string) string {
+	if filepath.IsAbs(p) {
+		if rel, err := filepath.Rel(a.dir, p); err == nil {
+			p = rel
+		}
+	}
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// expandArgs replaces PackagesArg with the packages of the changed Go
+// files and FilesArg with the files. It reports false if a placeholder
+// expands to nothing, as the linter would then check everything.
+func expandArgs(command []string, files []string) ([]string, bool) {
+	if len(command) == 0 {
+		return nil, false
+	}
+	var args []string
+	for _, arg := range command {
+		switch arg {
+		case PackagesArg:
+			packages := goPackages(files)
+			if len(packages) == 0 {
+				return nil, false
+			}
+			args = append(args, packages...)
+		case FilesArg:
+			args = append(args, files...)
+		default:
+			args = append(args, arg)
+		}
+	}
+	return args, true
+}
+
+// goPackages returns "./dir" patterns for the directories of Go files.
+func goPackages(files []string) []string {
+	seen := make(map[string]bool)
+	var packages []string
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		pkg := "./" + path.Dir(file)
+		if pkg == "./." {
+			pkg = "."
+		}
+		if !seen[pkg] {
+			seen[pkg] = true
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// addedLines maps each file the diff keeps to its added lines, and each
+// line to its hunk, numbered from 1.
+func addedLines(diff *diffview.Diff) map[string]map[int]int {
+	added := make(map[string]map[int]int)
+	for _, file := range diff.Files {
+		if file.Operation == diffview.FileDeleted {
+			continue
+		}
+		p := file.Path()
+		for i, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				if line.Type != diffview.LineAdded {
+					continue
+				}
+				if added[p] == nil {
+					added[p] = make(map[int]int)
+				}
+				added[p][line.NewLineNum] = i + 1
+			}
+		}
+	}
+	return added
+}
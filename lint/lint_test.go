@@ -0,0 +1,93 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addedFile returns a diff adding lines at the given line numbers of path,
+// after an unchanged first line.
+func addedFile(path string, lines ...int) diffview.FileDiff {
+	hunk := diffview.Hunk{Lines: []diffview.Line{{Type: diffview.LineContext, OldLineNum: 1, NewLineNum: 1}}}
+	for _, n := range lines {
+		hunk.Lines = append(hunk.Lines, diffview.Line{Type: diffview.LineAdded, NewLineNum: n})
+	}
+	return diffview.FileDiff{OldPath: "a/" + path, NewPath: "b/" + path, Hunks: []diffview.Hunk{hunk}}
+}
+
+func TestAnnotator_Annotate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("notes findings on added lines only", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("no sh on Windows")
+		}
+
+		linter := lint.Linter{Name: "fake", Command: []string{"sh", "-c", `
+			echo "./pkg/a.go:3:5: new problem"
+			echo "pkg/a.go:1: old problem"
+			echo "other.go:3: not in the diff"
+			echo "summary line"`}}
+		diff := &diffview.Diff{Files: []diffview.FileDiff{addedFile("pkg/a.go", 2, 3)}}
+
+		notes := lint.NewAnnotator(t.TempDir(), []lint.Linter{linter}).Annotate(diff)
+
+		assert.Equal(t, []diffview.Annotation{
+			{Path: "pkg/a.go", Hunk: 1, Line: 3, Text: "new problem", Source: "fake", Icon: "▲"},
+		}, notes)
+	})
+
+	t.Run("runs go vet on the changed packages", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.21\n"), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "a.go"), []byte(
+			"package pkg\n\nimport \"fmt\"\n\nfunc F() {\n\tfmt.Printf(\"%d\\n\", \"x\")\n}\n"), 0o644))
+		diff := &diffview.Diff{Files: []diffview.FileDiff{addedFile("pkg/a.go", 5, 6, 7)}}
+
+		notes := lint.NewAnnotator(dir, []lint.Linter{lint.Vet()}).Annotate(diff)
+
+		require.Len(t, notes, 1)
+		assert.Equal(t, "pkg/a.go", notes[0].Path)
+		assert.Equal(t, 6, notes[0].Line)
+		assert.Equal(t, "vet", notes[0].Source)
+		assert.Contains(t, notes[0].Text, "Printf")
+	})
+
+	t.Run("skips package linters when no Go files changed", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("no sh on Windows")
+		}
+
+		linter := lint.Linter{Name: "fake", Command: []string{"sh", "-c", `echo "README.md:2: ran anyway"`, lint.PackagesArg}}
+		diff := &diffview.Diff{Files: []diffview.FileDiff{addedFile("README.md", 2)}}
+
+		assert.Empty(t, lint.NewAnnotator(t.TempDir(), []lint.Linter{linter}).Annotate(diff))
+	})
+}
+
+func TestParseLinter(t *testing.T) {
+	t.Parallel()
+
+	linter, err := lint.ParseLinter("vet")
+	require.NoError(t, err)
+	assert.Equal(t, lint.Vet(), linter)
+
+	linter, err = lint.ParseLinter("golangci=golangci-lint run --out-format=line-number {packages}")
+	require.NoError(t, err)
+	assert.Equal(t, lint.Linter{Name: "golangci", Command: []string{"golangci-lint", "run", "--out-format=line-number", "{packages}"}}, linter)
+
+	_, err = lint.ParseLinter("eslint")
+	assert.Error(t, err)
+}
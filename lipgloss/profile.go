@@ -0,0 +1,281 @@
+package lipgloss
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/muesli/termenv"
+)
+
+// ColorMode is when output is colored, as chosen with a --color flag.
+type ColorMode int
+
+const (
+	ColorAuto   ColorMode = iota // When writing to a terminal and $NO_COLOR isn't set
+	ColorAlways                  // Even when writing to a file or pipe
+	ColorNever                   // Never
+)
+
+// ParseColorMode parses a color setting: "auto", "always" or "never". An
+// empty string selects auto.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch s {
+	case "", "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	}
+	return ColorAuto, fmt.Errorf("invalid color mode %q: must be auto, always or never", s)
+}
+
+// Profile returns the colors to write to out with. Auto uses what out's
+// terminal shows, and none if out isn't a terminal or $NO_COLOR is set.
+// Always uses what out's terminal shows too, but true color for a file or
+// pipe, such as less -R reads.
+func (m ColorMode) Profile(out io.Writer) termenv.Profile {
+	output := termenv.NewOutput(out)
+	switch m {
+	case ColorNever:
+		return termenv.Ascii
+	case ColorAlways:
+		if p := output.ColorProfile(); p != termenv.Ascii {
+			return p
+		}
+		return termenv.TrueColor
+	}
+	return output.EnvColorProfile()
+}
+
+// ForProfile returns t with colors a terminal showing profile can show.
+// Rather than leave it to the terminal library's nearest match, colors
+// are chosen so that changes stay visible:
+//
+//   - 256 colors: the nearest of the fixed xterm colors, never the first
+//     16 that terminal themes redefine, and the tints of changed lines
+//     never rounded away into the background.
+//   - 16 colors: the diff colors' nearest basic hues as foregrounds, on the
+//     terminal's own background. Syntax highlighting is off, since it
+//     would paint over them.
+//   - No colors: SymbolsTheme, marking changes with symbols and emphasis.
+func ForProfile(t *Theme, profile termenv.Profile) *Theme {
+	if t.styles.Symbols {
+		return t
+	}
+	switch profile {
+	case termenv.ANSI256:
+		return ansi256Theme(t)
+	case termenv.ANSI:
+		return ansiTheme(t.palette)
+	case termenv.Ascii:
+		return SymbolsTheme()
+	}
+	return t
+}
+
+// ansi256Theme maps each of t's colors to the nearest fixed xterm color.
+// Changed lines, gutters and changed words keep a tint distinct from the
+// background where the nearest color would be the background's own.
+func ansi256Theme(t *Theme) *Theme {
+	bg := nearest256(string(t.palette.Background), -1)
+	color := func(hex string) string {
+		if hex == "" {
+			return ""
+		}
+		return fmt.Sprint(nearest256(hex, -1))
+	}
+	tinted := func(hex string) string {
+		if hex == "" {
+			return ""
+		}
+		return fmt.Sprint(nearest256(hex, bg))
+	}
+	pair := func(cp diffview.ColorPair) diffview.ColorPair {
+		return diffview.ColorPair{Foreground: color(cp.Foreground), Background: color(cp.Background)}
+	}
+	tintedPair := func(cp diffview.ColorPair) diffview.ColorPair {
+		return diffview.ColorPair{Foreground: color(cp.Foreground), Background: tinted(cp.Background)}
+	}
+
+	s := t.styles
+	styles := diffview.Styles{
+		Added:            tintedPair(s.Added),
+		Deleted:          tintedPair(s.Deleted),
+		Context:          pair(s.Context),
+		HunkHeader:       pair(s.HunkHeader),
+		FileHeader:       pair(s.FileHeader),
+		FileSeparator:    pair(s.FileSeparator),
+		LineNumber:       pair(s.LineNumber),
+		AddedGutter:      tintedPair(s.AddedGutter),
+		DeletedGutter:    tintedPair(s.DeletedGutter),
+		AddedHighlight:   tintedPair(s.AddedHighlight),
+		DeletedHighlight: tintedPair(s.DeletedHighlight),
+		Ours:             tintedPair(s.Ours),
+		Theirs:           tintedPair(s.Theirs),
+		Moved:            tintedPair(s.Moved),
+		SectionBanner:    pair(s.SectionBanner),
+	}
+	if s.SectionRoles != nil {
+		styles.SectionRoles = make(map[string]diffview.ColorPair, len(s.SectionRoles))
+		for role, cp := range s.SectionRoles {
+			styles.SectionRoles[role] = pair(cp)
+		}
+	}
+
+	p := t.palette
+	c := func(col diffview.Color) diffview.Color {
+		return diffview.Color(color(string(col)))
+	}
+	palette := diffview.Palette{
+		Background:   c(p.Background),
+		Foreground:   c(p.Foreground),
+		Added:        c(p.Added),
+		Deleted:      c(p.Deleted),
+		Modified:     c(p.Modified),
+		Context:      c(p.Context),
+		Keyword:      c(p.Keyword),
+		String:       c(p.String),
+		Number:       c(p.Number),
+		Comment:      c(p.Comment),
+		Operator:     c(p.Operator),
+		Function:     c(p.Function),
+		Type:         c(p.Type),
+		Constant:     c(p.Constant),
+		Punctuation:  c(p.Punctuation),
+		UIBackground: c(p.UIBackground),
+		UIForeground: c(p.UIForeground),
+		UIAccent:     c(p.UIAccent),
+	}
+	return &Theme{styles: styles, palette: palette}
+}
+
+// xterm256 returns the RGB of xterm color i, for i from 16: the color
+// cube, then 24 grays.
+func xterm256(i int) (r, g, b int) {
+	if i >= 232 {
+		v := 8 + 10*(i-232)
+		return v, v, v
+	}
+	// Channel values of the 6×6×6 color cube
+	levels := [6]int{0, 95, 135, 175, 215, 255}
+	i -= 16
+	return levels[i/36], levels[i/6%6], levels[i%6]
+}
+
+// nearest256 returns the fixed xterm color, 16 to 255, nearest hex, other
+// than avoid.
+func nearest256(hex string, avoid int) int {
+	r, g, b := parseHex(hex)
+	best, bestDist := 16, math.MaxInt
+	for i := 16; i < 256; i++ {
+		if i == avoid {
+			continue
+		}
+		cr, cg, cb := xterm256(i)
+		if d := (r-cr)*(r-cr) + (g-cg)*(g-cg) + (b-cb)*(b-cb); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// nearestHue returns the basic ANSI color whose hue is nearest hex's.
+func nearestHue(hex string) string {
+	// The basic ANSI colors with a hue, by the hue in degrees they're
+	// usually drawn with
+	hues := [...]struct {
+		color string
+		hue   float64
+	}{
+		{"1", 0},   // red
+		{"3", 60},  // yellow
+		{"2", 120}, // green
+		{"6", 180}, // cyan
+		{"4", 240}, // blue
+		{"5", 300}, // magenta
+	}
+	r, g, b := parseHex(hex)
+	hue := hueOf(r, g, b)
+	best, bestDist := hues[0].color, 360.0
+	for _, h := range hues {
+		d := math.Abs(hue - h.hue)
+		d = math.Min(d, 360-d)
+		if d < bestDist {
+			best, bestDist = h.color, d
+		}
+	}
+	return best
+}
+
+// hueOf returns the hue of an RGB color in degrees, from 0 to 360.
+func hueOf(r, g, b int) float64 {
+	fr, fg, fb := float64(r)/255, float64(g)/255, float64(b)/255
+	hi, lo := math.Max(fr, math.Max(fg, fb)), math.Min(fr, math.Min(fg, fb))
+	if hi == lo {
+		return 0
+	}
+	var h float64
+	switch hi {
+	case fr:
+		h = math.Mod((fg-fb)/(hi-lo), 6)
+	case fg:
+		h = (fb-fr)/(hi-lo) + 2
+	default:
+		h = (fr-fg)/(hi-lo) + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// ansiTheme returns a theme of basic ANSI colors for p: its diff colors'
+// nearest hues as foregrounds, words changed within a line reversed out
+// of them, and headers and the gutter in the terminal's dim and accent
+// colors. Syntax colors are left out so changed lines keep their color.
+func ansiTheme(p diffview.Palette) *Theme {
+	added, deleted, modified := nearestHue(string(p.Added)), nearestHue(string(p.Deleted)), nearestHue(string(p.Modified))
+	const dim, accent, black, white = "8", "4", "0", "7"
+	roles := map[string]diffview.ColorPair{
+		"problem":    {Foreground: deleted},
+		"fix":        {Foreground: added},
+		"core":       {Foreground: accent},
+		"test":       {Foreground: "5"},
+		"interface":  {Foreground: "6"},
+		"pattern":    {Foreground: "5"},
+		"supporting": {Foreground: modified},
+		"cleanup":    {Foreground: dim},
+	}
+	return &Theme{
+		styles: diffview.Styles{
+			Added:            diffview.ColorPair{Foreground: added},
+			Deleted:          diffview.ColorPair{Foreground: deleted},
+			HunkHeader:       diffview.ColorPair{Foreground: "6"},
+			FileHeader:       diffview.ColorPair{Foreground: modified},
+			FileSeparator:    diffview.ColorPair{Foreground: dim},
+			LineNumber:       diffview.ColorPair{Foreground: dim},
+			AddedGutter:      diffview.ColorPair{Foreground: added},
+			DeletedGutter:    diffview.ColorPair{Foreground: deleted},
+			AddedHighlight:   diffview.ColorPair{Foreground: black, Background: added},
+			DeletedHighlight: diffview.ColorPair{Foreground: black, Background: deleted},
+			Ours:             diffview.ColorPair{Foreground: accent},
+			Theirs:           diffview.ColorPair{Foreground: modified},
+			Moved:            diffview.ColorPair{Foreground: "5"},
+			SectionBanner:    diffview.ColorPair{Foreground: white},
+			SectionRoles:     roles,
+		},
+		palette: diffview.Palette{
+			Added:        diffview.Color(added),
+			Deleted:      diffview.Color(deleted),
+			Modified:     diffview.Color(modified),
+			Context:      dim,
+			UIBackground: black,
+			UIForeground: white,
+			UIAccent:     accent,
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package lipgloss_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/fwojciec/diffstory/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColorMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want lipgloss.ColorMode
+	}{
+		{"", lipgloss.ColorAuto},
+		{"auto", lipgloss.ColorAuto},
+		{"always", lipgloss.ColorAlways},
+		{"never", lipgloss.ColorNever},
+	}
+	for _, tt := range tests {
+		got, err := lipgloss.ParseColorMode(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := lipgloss.ParseColorMode("yes")
+	assert.EqualError(t, err, `invalid color mode "yes": must be auto, always or never`)
+}
+
+func TestColorMode_Profile(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	assert.Equal(t, termenv.Ascii, lipgloss.ColorNever.Profile(&out))
+	// Not a terminal, so only always colors
+	assert.Equal(t, termenv.TrueColor, lipgloss.ColorAlways.Profile(&out))
+	assert.Equal(t, termenv.Ascii, lipgloss.ColorAuto.Profile(&out))
+}
+
+func TestForProfile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("true color keeps the theme", func(t *testing.T) {
+		t.Parallel()
+
+		theme := lipgloss.DefaultTheme()
+		assert.Same(t, theme, lipgloss.ForProfile(theme, termenv.TrueColor))
+	})
+
+	t.Run("256 colors avoids the terminal's own 16", func(t *testing.T) {
+		t.Parallel()
+
+		theme := lipgloss.ForProfile(lipgloss.DefaultTheme(), termenv.ANSI256)
+		styles := theme.Styles()
+
+		for _, c := range []string{
+			styles.Added.Foreground, styles.Added.Background,
+			styles.Deleted.Background, styles.AddedGutter.Background,
+			styles.HunkHeader.Foreground, string(theme.Palette().Keyword),
+		} {
+			n, err := strconv.Atoi(c)
+			require.NoError(t, err, c)
+			assert.GreaterOrEqual(t, n, 16, c)
+			assert.LessOrEqual(t, n, 255, c)
+		}
+	})
+
+	t.Run("256 colors keeps changed lines tinted", func(t *testing.T) {
+		t.Parallel()
+
+		theme := lipgloss.ForProfile(lipgloss.DefaultTheme(), termenv.ANSI256)
+		styles := theme.Styles()
+		bg := string(theme.Palette().Background)
+
+		assert.NotEqual(t, bg, styles.Added.Background)
+		assert.NotEqual(t, bg, styles.Deleted.Background)
+		assert.NotEqual(t, styles.Added.Background, styles.Deleted.Background)
+	})
+
+	t.Run("16 colors uses the nearest hues", func(t *testing.T) {
+		t.Parallel()
+
+		styles := lipgloss.ForProfile(lipgloss.DefaultTheme(), termenv.ANSI).Styles()
+
+		assert.Equal(t, "2", styles.Added.Foreground)
+		assert.Empty(t, styles.Added.Background)
+		assert.Equal(t, "1", styles.Deleted.Foreground)
+		assert.Equal(t, "2", styles.AddedHighlight.Background)
+	})
+
+	t.Run("16 colors keeps the colorblind theme apart from red and green", func(t *testing.T) {
+		t.Parallel()
+
+		styles := lipgloss.ForProfile(lipgloss.ColorblindTheme(), termenv.ANSI).Styles()
+
+		assert.Equal(t, "6", styles.Added.Foreground)
+		assert.Equal(t, "3", styles.Deleted.Foreground)
+	})
+
+	t.Run("16 colors leaves syntax highlighting out", func(t *testing.T) {
+		t.Parallel()
+
+		palette := lipgloss.ForProfile(lipgloss.DefaultTheme(), termenv.ANSI).Palette()
+
+		assert.Empty(t, palette.Keyword)
+		assert.Empty(t, palette.String)
+	})
+
+	t.Run("no colors marks changes with symbols", func(t *testing.T) {
+		t.Parallel()
+
+		styles := lipgloss.ForProfile(lipgloss.DefaultTheme(), termenv.Ascii).Styles()
+
+		assert.True(t, styles.Symbols)
+	})
+}
@@ -3,6 +3,7 @@ package lipgloss
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fwojciec/diffstory"
 )
@@ -28,22 +29,31 @@ func (t *Theme) Palette() diffview.Palette {
 
 // NewTheme creates a Theme from a Palette, deriving all styles from the palette colors.
 func NewTheme(p diffview.Palette) *Theme {
+	// Changed lines stay subtle, with the gutter and changed words standing
+	// out
 	return &Theme{
 		palette: p,
-		styles:  stylesFromPalette(p),
+		styles:  stylesFromPalette(p, tint{line: 0.15, gutter: 0.35}),
 	}
 }
 
-// stylesFromPalette derives Styles from a Palette.
-func stylesFromPalette(p diffview.Palette) diffview.Styles {
+// tint is how strongly added and deleted colors show through the
+// background: on the lines, and on their gutters and changed words.
+type tint struct {
+	line, gutter float64
+}
+
+// stylesFromPalette derives Styles from a Palette, tinting changed lines
+// by t.
+func stylesFromPalette(p diffview.Palette, t tint) diffview.Styles {
 	return diffview.Styles{
 		Added: diffview.ColorPair{
 			Foreground: string(p.Foreground),
-			Background: blendWithBackground(p.Added, p.Background, 0.15),
+			Background: blendWithBackground(p.Added, p.Background, t.line),
 		},
 		Deleted: diffview.ColorPair{
 			Foreground: string(p.Foreground),
-			Background: blendWithBackground(p.Deleted, p.Background, 0.15),
+			Background: blendWithBackground(p.Deleted, p.Background, t.line),
 		},
 		Context: diffview.ColorPair{
 			Foreground: string(p.Context),
@@ -63,19 +73,45 @@ func stylesFromPalette(p diffview.Palette) diffview.Styles {
 		},
 		AddedGutter: diffview.ColorPair{
 			Foreground: string(p.Foreground), // Same as code line foreground
-			Background: blendWithBackground(p.Added, p.Background, 0.35),
+			Background: blendWithBackground(p.Added, p.Background, t.gutter),
 		},
 		DeletedGutter: diffview.ColorPair{
 			Foreground: string(p.Foreground), // Same as code line foreground
-			Background: blendWithBackground(p.Deleted, p.Background, 0.35),
+			Background: blendWithBackground(p.Deleted, p.Background, t.gutter),
 		},
 		AddedHighlight: diffview.ColorPair{
-			Foreground: string(p.Foreground),                             // Same as code line foreground (neutral)
-			Background: blendWithBackground(p.Added, p.Background, 0.35), // Same as gutter
+			Foreground: string(p.Foreground),                                 // Same as code line foreground (neutral)
+			Background: blendWithBackground(p.Added, p.Background, t.gutter), // Same as gutter
 		},
 		DeletedHighlight: diffview.ColorPair{
-			Foreground: string(p.Foreground),                               // Same as code line foreground (neutral)
-			Background: blendWithBackground(p.Deleted, p.Background, 0.35), // Same as gutter
+			Foreground: string(p.Foreground),                                   // Same as code line foreground (neutral)
+			Background: blendWithBackground(p.Deleted, p.Background, t.gutter), // Same as gutter
+		},
+		Ours: diffview.ColorPair{
+			Foreground: string(p.Foreground),
+			Background: blendWithBackground(p.UIAccent, p.Background, 0.20),
+		},
+		Theirs: diffview.ColorPair{
+			Foreground: string(p.Foreground),
+			Background: blendWithBackground(p.Modified, p.Background, 0.20),
+		},
+		Moved: diffview.ColorPair{
+			Foreground: string(p.Foreground),
+			Background: blendWithBackground(p.Function, p.Background, 0.15),
+		},
+		SectionBanner: diffview.ColorPair{
+			Foreground: string(p.Foreground),
+			Background: string(p.UIBackground),
+		},
+		SectionRoles: map[string]diffview.ColorPair{
+			"problem":    {Foreground: string(p.Deleted), Background: string(p.UIBackground)},
+			"fix":        {Foreground: string(p.Added), Background: string(p.UIBackground)},
+			"core":       {Foreground: string(p.UIAccent), Background: string(p.UIBackground)},
+			"test":       {Foreground: string(p.Function), Background: string(p.UIBackground)},
+			"interface":  {Foreground: string(p.Type), Background: string(p.UIBackground)},
+			"pattern":    {Foreground: string(p.Keyword), Background: string(p.UIBackground)},
+			"supporting": {Foreground: string(p.Modified), Background: string(p.UIBackground)},
+			"cleanup":    {Foreground: string(p.Comment), Background: string(p.UIBackground)},
 		},
 	}
 }
@@ -85,6 +121,49 @@ func DefaultTheme() *Theme {
 	return NewTheme(githubDarkPalette())
 }
 
+// ColorblindTheme returns a dark theme for red-green color blindness, with
+// additions in blue and deletions in orange from the Okabe-Ito palette.
+func ColorblindTheme() *Theme {
+	return NewTheme(colorblindPalette())
+}
+
+// HighContrastTheme returns a theme of pure black and white, with changed
+// lines strongly tinted for low vision.
+func HighContrastTheme() *Theme {
+	p := highContrastPalette()
+	return &Theme{
+		palette: p,
+		styles:  stylesFromPalette(p, tint{line: 0.35, gutter: 0.6}),
+	}
+}
+
+// SymbolsTheme returns a theme without colors, marking changes with
+// symbols and emphasis instead. See diffview.Styles.Symbols.
+func SymbolsTheme() *Theme {
+	return &Theme{styles: diffview.Styles{Symbols: true}}
+}
+
+// ThemeNames returns the names ThemeByName accepts, the default first.
+func ThemeNames() []string {
+	return []string{"default", "colorblind", "high-contrast", "symbols"}
+}
+
+// ThemeByName returns the built-in theme called name, or the default theme
+// for an empty name.
+func ThemeByName(name string) (*Theme, error) {
+	switch name {
+	case "", "default":
+		return DefaultTheme(), nil
+	case "colorblind":
+		return ColorblindTheme(), nil
+	case "high-contrast":
+		return HighContrastTheme(), nil
+	case "symbols":
+		return SymbolsTheme(), nil
+	}
+	return nil, fmt.Errorf("unknown theme %q: must be one of %s", name, strings.Join(ThemeNames(), ", "))
+}
+
 // TestTheme returns a theme with predictable, pure colors for testing.
 // Uses simple hex colors that are easy to assert in tests.
 func TestTheme() *Theme {
@@ -154,6 +233,45 @@ func githubDarkPalette() diffview.Palette {
 	}
 }
 
+// colorblindPalette returns the GitHub dark palette with diff colors
+// told apart by hue and brightness without red and green: Okabe-Ito sky
+// blue, orange and yellow.
+func colorblindPalette() diffview.Palette {
+	p := githubDarkPalette()
+	p.Added = "#56b4e9"
+	p.Deleted = "#e69f00"
+	p.Modified = "#f0e442"
+	return p
+}
+
+// highContrastPalette returns a palette of pure black and white with
+// saturated accents.
+func highContrastPalette() diffview.Palette {
+	return diffview.Palette{
+		Background: "#000000",
+		Foreground: "#ffffff",
+
+		Added:    "#00a2ff",
+		Deleted:  "#ff9f00",
+		Modified: "#ffff00",
+		Context:  "#d0d0d0",
+
+		Keyword:     "#ff80ff",
+		String:      "#80ffff",
+		Number:      "#80c0ff",
+		Comment:     "#c0c0c0",
+		Operator:    "#ffffff",
+		Function:    "#ffd080",
+		Type:        "#80ff80",
+		Constant:    "#80c0ff",
+		Punctuation: "#ffffff",
+
+		UIBackground: "#1a1a1a",
+		UIForeground: "#ffffff",
+		UIAccent:     "#ffff00",
+	}
+}
+
 // blendWithBackground creates a subtle background color by blending
 // the accent color with the background color at the given ratio.
 // ratio of 0.15 means 15% accent, 85% background.
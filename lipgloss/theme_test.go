@@ -6,6 +6,7 @@ import (
 	"github.com/fwojciec/diffstory"
 	"github.com/fwojciec/diffstory/lipgloss"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTheme(t *testing.T) {
@@ -165,6 +166,60 @@ func TestNewTheme(t *testing.T) {
 		assert.NotEmpty(t, styles.DeletedHighlight.Background)
 		assert.Equal(t, styles.DeletedGutter.Background, styles.DeletedHighlight.Background) // Same as gutter
 	})
+
+	t.Run("derives distinct ours and theirs styles for merge conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		palette := diffview.Palette{
+			Background: "#000000",
+			Foreground: "#ffffff",
+			Modified:   "#ffff00",
+			UIAccent:   "#0000ff",
+		}
+
+		theme := lipgloss.NewTheme(palette)
+		styles := theme.Styles()
+
+		assert.Equal(t, "#ffffff", styles.Ours.Foreground)
+		assert.Equal(t, "#ffffff", styles.Theirs.Foreground)
+		assert.Equal(t, "#000033", styles.Ours.Background)   // 20% blend of accent
+		assert.Equal(t, "#333300", styles.Theirs.Background) // 20% blend of modified
+	})
+
+	t.Run("colors moved lines with the function color", func(t *testing.T) {
+		t.Parallel()
+
+		palette := diffview.Palette{
+			Background: "#000000",
+			Foreground: "#ffffff",
+			Function:   "#ff00ff",
+		}
+
+		styles := lipgloss.NewTheme(palette).Styles()
+
+		assert.Equal(t, "#ffffff", styles.Moved.Foreground)
+		assert.Equal(t, "#260026", styles.Moved.Background) // 15% blend of function
+	})
+
+	t.Run("colors section banners by role", func(t *testing.T) {
+		t.Parallel()
+
+		palette := diffview.Palette{
+			Foreground:   "#ffffff",
+			Added:        "#00ff00",
+			Deleted:      "#ff0000",
+			UIBackground: "#333333",
+			UIAccent:     "#0000ff",
+		}
+
+		styles := lipgloss.NewTheme(palette).Styles()
+
+		assert.Equal(t, "#ff0000", styles.SectionRoles["problem"].Foreground)
+		assert.Equal(t, "#00ff00", styles.SectionRoles["fix"].Foreground)
+		assert.Equal(t, "#0000ff", styles.SectionRoles["core"].Foreground)
+		assert.Equal(t, "#333333", styles.SectionRoles["core"].Background)
+		assert.Equal(t, "#ffffff", styles.SectionBanner.Foreground)
+	})
 }
 
 func TestDefaultTheme(t *testing.T) {
@@ -278,3 +333,53 @@ func TestTestTheme(t *testing.T) {
 		assert.Equal(t, string(palette.Foreground), styles.Deleted.Foreground)
 	})
 }
+
+func TestColorblindTheme(t *testing.T) {
+	t.Parallel()
+
+	palette := lipgloss.ColorblindTheme().Palette()
+
+	// Blue and orange rather than green and red
+	assert.Equal(t, diffview.Color("#56b4e9"), palette.Added)
+	assert.Equal(t, diffview.Color("#e69f00"), palette.Deleted)
+}
+
+func TestHighContrastTheme(t *testing.T) {
+	t.Parallel()
+
+	theme := lipgloss.HighContrastTheme()
+	palette := theme.Palette()
+	styles := theme.Styles()
+
+	assert.Equal(t, diffview.Color("#000000"), palette.Background)
+	assert.Equal(t, diffview.Color("#ffffff"), palette.Foreground)
+	// Changed lines are tinted as strongly as NewTheme tints gutters
+	assert.Equal(t, lipgloss.NewTheme(palette).Styles().AddedGutter.Background, styles.Added.Background)
+}
+
+func TestSymbolsTheme(t *testing.T) {
+	t.Parallel()
+
+	styles := lipgloss.SymbolsTheme().Styles()
+
+	assert.True(t, styles.Symbols)
+	assert.Equal(t, diffview.Styles{Symbols: true}, styles, "has no colors")
+	assert.Equal(t, diffview.Palette{}, lipgloss.SymbolsTheme().Palette())
+}
+
+func TestThemeByName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range append([]string{""}, lipgloss.ThemeNames()...) {
+		theme, err := lipgloss.ThemeByName(name)
+		require.NoError(t, err, name)
+		assert.NotNil(t, theme, name)
+	}
+
+	theme, err := lipgloss.ThemeByName("")
+	require.NoError(t, err)
+	assert.Equal(t, lipgloss.DefaultTheme(), theme)
+
+	_, err = lipgloss.ThemeByName("solarized")
+	assert.EqualError(t, err, `unknown theme "solarized": must be one of default, colorblind, high-contrast, symbols`)
+}
@@ -0,0 +1,97 @@
+package diffview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// StoryClassifierFunc adapts a function to a StoryClassifier.
+type StoryClassifierFunc func(ctx context.Context, input ClassificationInput) (*StoryClassification, error)
+
+// Classify calls f.
+func (f StoryClassifierFunc) Classify(ctx context.Context, input ClassificationInput) (*StoryClassification, error) {
+	return f(ctx, input)
+}
+
+// ClassifierMiddleware wraps a StoryClassifier in behavior of its own, such
+// as caching, retries or logging.
+type ClassifierMiddleware func(next StoryClassifier) StoryClassifier
+
+// Chain returns middleware that wraps a classifier in each of middlewares,
+// the first outermost, so that Chain(cache, retry)(gemini) looks in the
+// cache before retrying gemini.
+func Chain(middlewares ...ClassifierMiddleware) ClassifierMiddleware {
+	return func(next StoryClassifier) StoryClassifier {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// RetryClassifier retries failed classifications until attempts have been
+// made in all, waiting delay before the first retry and twice as long
+// before each one after. It gives up early when the context is done.
+func RetryClassifier(attempts int, delay time.Duration) ClassifierMiddleware {
+	return func(next StoryClassifier) StoryClassifier {
+		return StoryClassifierFunc(func(ctx context.Context, input ClassificationInput) (*StoryClassification, error) {
+			wait := delay
+			for attempt := 1; ; attempt++ {
+				story, err := next.Classify(ctx, input)
+				if err == nil || attempt >= attempts || ctx.Err() != nil {
+					return story, err
+				}
+				select {
+				case <-ctx.Done():
+					return nil, err
+				case <-time.After(wait):
+				}
+				wait *= 2
+			}
+		})
+	}
+}
+
+// FallbackClassifier classifies with fallback, such as another provider or
+// model, whatever the wrapped classifier fails to. Failures because the
+// context is done aren't passed on. If both fail, the error holds both.
+func FallbackClassifier(fallback StoryClassifier) ClassifierMiddleware {
+	return func(next StoryClassifier) StoryClassifier {
+		return StoryClassifierFunc(func(ctx context.Context, input ClassificationInput) (*StoryClassification, error) {
+			story, err := next.Classify(ctx, input)
+			if err == nil || ctx.Err() != nil {
+				return story, err
+			}
+			story, fallbackErr := fallback.Classify(ctx, input)
+			if fallbackErr != nil {
+				return nil, errors.Join(err, fmt.Errorf("fallback: %w", fallbackErr))
+			}
+			return story, nil
+		})
+	}
+}
+
+// LogClassifier writes a line to w for each classification: the input's
+// name, how long it took, and how many sections it found or why it failed.
+func LogClassifier(w io.Writer) ClassifierMiddleware {
+	var mu sync.Mutex // Serializes lines from parallel callers
+	return func(next StoryClassifier) StoryClassifier {
+		return StoryClassifierFunc(func(ctx context.Context, input ClassificationInput) (*StoryClassification, error) {
+			start := time.Now()
+			story, err := next.Classify(ctx, input)
+			took := time.Since(start).Round(time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(w, "classifying %s failed after %s: %v\n", input.Name(), took, err)
+			} else {
+				fmt.Fprintf(w, "classified %s in %s: %d section(s)\n", input.Name(), took, len(story.Sections))
+			}
+			return story, err
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package diffview_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingClassifier fails its first failures calls, then classifies.
+func failingClassifier(failures int, calls *int) *mock.StoryClassifier {
+	return &mock.StoryClassifier{
+		ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+			*calls++
+			if *calls <= failures {
+				return nil, errors.New("unavailable")
+			}
+			return &diffview.StoryClassification{Sections: []diffview.Section{{Role: "core"}}}, nil
+		},
+	}
+}
+
+func TestChain_FirstMiddlewareIsOutermost(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	tag := func(name string) diffview.ClassifierMiddleware {
+		return func(next diffview.StoryClassifier) diffview.StoryClassifier {
+			return diffview.StoryClassifierFunc(func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				order = append(order, name)
+				return next.Classify(ctx, input)
+			})
+		}
+	}
+	calls := 0
+
+	_, err := diffview.Chain(tag("outer"), tag("inner"))(failingClassifier(0, &calls)).Classify(context.Background(), diffview.ClassificationInput{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryClassifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries until it succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		classifier := diffview.RetryClassifier(3, time.Millisecond)(failingClassifier(2, &calls))
+
+		story, err := classifier.Classify(context.Background(), diffview.ClassificationInput{})
+
+		require.NoError(t, err)
+		assert.Len(t, story.Sections, 1)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after the attempts", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		classifier := diffview.RetryClassifier(2, time.Millisecond)(failingClassifier(5, &calls))
+
+		_, err := classifier.Classify(context.Background(), diffview.ClassificationInput{})
+
+		assert.EqualError(t, err, "unavailable")
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		classifier := diffview.RetryClassifier(5, time.Hour)(failingClassifier(5, &calls))
+
+		_, err := classifier.Classify(ctx, diffview.ClassificationInput{})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestFallbackClassifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("is unused while the classifier succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		primary, secondary := 0, 0
+		classifier := diffview.FallbackClassifier(failingClassifier(0, &secondary))(failingClassifier(0, &primary))
+
+		_, err := classifier.Classify(context.Background(), diffview.ClassificationInput{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, primary)
+		assert.Equal(t, 0, secondary)
+	})
+
+	t.Run("classifies what the classifier fails to", func(t *testing.T) {
+		t.Parallel()
+
+		primary, secondary := 0, 0
+		classifier := diffview.FallbackClassifier(failingClassifier(0, &secondary))(failingClassifier(1, &primary))
+
+		story, err := classifier.Classify(context.Background(), diffview.ClassificationInput{})
+
+		require.NoError(t, err)
+		assert.Len(t, story.Sections, 1)
+		assert.Equal(t, 1, secondary)
+	})
+
+	t.Run("reports both failures", func(t *testing.T) {
+		t.Parallel()
+
+		primary, secondary := 0, 0
+		classifier := diffview.FallbackClassifier(failingClassifier(1, &secondary))(failingClassifier(1, &primary))
+
+		_, err := classifier.Classify(context.Background(), diffview.ClassificationInput{})
+
+		assert.EqualError(t, err, "unavailable\nfallback: unavailable")
+	})
+}
+
+func TestLogClassifier(t *testing.T) {
+	t.Parallel()
+
+	var log bytes.Buffer
+	calls := 0
+	classifier := diffview.LogClassifier(&log)(failingClassifier(1, &calls))
+	input := diffview.ClassificationInput{Repo: "api", Branch: "retries"}
+
+	_, err := classifier.Classify(context.Background(), input)
+	require.Error(t, err)
+	_, err = classifier.Classify(context.Background(), input)
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(log.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^classifying api/retries failed after \S+: unavailable$`, string(lines[0]))
+	assert.Regexp(t, `^classified api/retries in \S+: 1 section\(s\)$`, string(lines[1]))
+}
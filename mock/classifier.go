@@ -17,3 +17,27 @@ type StoryClassifier struct {
 func (c *StoryClassifier) Classify(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
 	return c.ClassifyFn(ctx, input)
 }
+
+// Compile-time interface verification.
+var _ diffview.StoryNarrator = (*StoryNarrator)(nil)
+
+// StoryNarrator is a mock implementation of diffview.StoryNarrator.
+type StoryNarrator struct {
+	NarrateFn func(ctx context.Context, input diffview.ClassificationInput, story *diffview.StoryClassification) ([]string, error)
+}
+
+func (n *StoryNarrator) Narrate(ctx context.Context, input diffview.ClassificationInput, story *diffview.StoryClassification) ([]string, error) {
+	return n.NarrateFn(ctx, input, story)
+}
+
+// Compile-time interface verification.
+var _ diffview.DiffAnswerer = (*DiffAnswerer)(nil)
+
+// DiffAnswerer is a mock implementation of diffview.DiffAnswerer.
+type DiffAnswerer struct {
+	AnswerFn func(ctx context.Context, diff *diffview.Diff, story *diffview.StoryClassification, history []diffview.ChatTurn, question string) (string, error)
+}
+
+func (a *DiffAnswerer) Answer(ctx context.Context, diff *diffview.Diff, story *diffview.StoryClassification, history []diffview.ChatTurn, question string) (string, error) {
+	return a.AnswerFn(ctx, diff, story, history, question)
+}
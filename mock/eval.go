@@ -8,11 +8,15 @@ import (
 
 // Compile-time interface verification.
 var (
-	_ diffview.EvalCaseLoader = (*EvalCaseLoader)(nil)
-	_ diffview.JudgmentStore  = (*JudgmentStore)(nil)
-	_ diffview.RubricJudge    = (*RubricJudge)(nil)
-	_ diffview.Clipboard      = (*Clipboard)(nil)
-	_ diffview.EvalCaseSaver  = (*EvalCaseSaver)(nil)
+	_ diffview.EvalCaseLoader     = (*EvalCaseLoader)(nil)
+	_ diffview.JudgmentStore      = (*JudgmentStore)(nil)
+	_ diffview.RubricJudge        = (*RubricJudge)(nil)
+	_ diffview.Clipboard          = (*Clipboard)(nil)
+	_ diffview.EvalCaseSaver      = (*EvalCaseSaver)(nil)
+	_ diffview.EvalCaseWriter     = (*EvalCaseWriter)(nil)
+	_ diffview.TombstoneStore     = (*TombstoneStore)(nil)
+	_ diffview.ReviewSessionStore = (*ReviewSessionStore)(nil)
+	_ diffview.CaseJudge          = (*CaseJudge)(nil)
 )
 
 // EvalCaseLoader is a mock implementation of diffview.EvalCaseLoader.
@@ -64,3 +68,49 @@ type EvalCaseSaver struct {
 func (s *EvalCaseSaver) Save(path string, c diffview.EvalCase) error {
 	return s.SaveFn(path, c)
 }
+
+// TombstoneStore is a mock implementation of diffview.TombstoneStore.
+type TombstoneStore struct {
+	LoadFn   func(path string) ([]diffview.Tombstone, error)
+	AppendFn func(path string, t diffview.Tombstone) error
+}
+
+func (s *TombstoneStore) Load(path string) ([]diffview.Tombstone, error) {
+	return s.LoadFn(path)
+}
+
+func (s *TombstoneStore) Append(path string, t diffview.Tombstone) error {
+	return s.AppendFn(path, t)
+}
+
+// ReviewSessionStore is a mock implementation of diffview.ReviewSessionStore.
+type ReviewSessionStore struct {
+	LoadFn   func(path string) ([]diffview.ReviewSession, error)
+	AppendFn func(path string, s diffview.ReviewSession) error
+}
+
+func (s *ReviewSessionStore) Load(path string) ([]diffview.ReviewSession, error) {
+	return s.LoadFn(path)
+}
+
+func (s *ReviewSessionStore) Append(path string, session diffview.ReviewSession) error {
+	return s.AppendFn(path, session)
+}
+
+// EvalCaseWriter is a mock implementation of diffview.EvalCaseWriter.
+type EvalCaseWriter struct {
+	WriteFn func(c diffview.EvalCase) error
+}
+
+func (w *EvalCaseWriter) Write(c diffview.EvalCase) error {
+	return w.WriteFn(c)
+}
+
+// CaseJudge is a mock implementation of diffview.CaseJudge.
+type CaseJudge struct {
+	JudgeFn func(ctx context.Context, c diffview.EvalCase) (*diffview.Judgment, error)
+}
+
+func (j *CaseJudge) Judge(ctx context.Context, c diffview.EvalCase) (*diffview.Judgment, error) {
+	return j.JudgeFn(ctx, c)
+}
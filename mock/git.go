@@ -18,12 +18,14 @@ type GitRunner struct {
 
 	// PR-level extraction methods
 	MergeCommitsFn   func(ctx context.Context, repoPath string, limit int) ([]string, error)
+	HistoryFn        func(ctx context.Context, repoPath string, q diffview.HistoryQuery) ([]diffview.CommitMeta, error)
 	CommitsInRangeFn func(ctx context.Context, repoPath, base, head string) ([]diffview.CommitBrief, error)
 	DiffRangeFn      func(ctx context.Context, repoPath, base, head string) (string, error)
 	DiffFn           func(ctx context.Context, repoPath, rangeSpec string) (string, error)
 	CurrentBranchFn  func(ctx context.Context, repoPath string) (string, error)
 	MergeBaseFn      func(ctx context.Context, repoPath, ref1, ref2 string) (string, error)
 	DefaultBranchFn  func(ctx context.Context, repoPath string) (string, error)
+	CloneFn          func(ctx context.Context, url, dir string) error
 }
 
 func (g *GitRunner) Log(ctx context.Context, repoPath string, limit int) ([]string, error) {
@@ -42,6 +44,10 @@ func (g *GitRunner) MergeCommits(ctx context.Context, repoPath string, limit int
 	return g.MergeCommitsFn(ctx, repoPath, limit)
 }
 
+func (g *GitRunner) History(ctx context.Context, repoPath string, q diffview.HistoryQuery) ([]diffview.CommitMeta, error) {
+	return g.HistoryFn(ctx, repoPath, q)
+}
+
 func (g *GitRunner) CommitsInRange(ctx context.Context, repoPath, base, head string) ([]diffview.CommitBrief, error) {
 	return g.CommitsInRangeFn(ctx, repoPath, base, head)
 }
@@ -65,3 +71,7 @@ func (g *GitRunner) MergeBase(ctx context.Context, repoPath, ref1, ref2 string)
 func (g *GitRunner) DefaultBranch(ctx context.Context, repoPath string) (string, error) {
 	return g.DefaultBranchFn(ctx, repoPath)
 }
+
+func (g *GitRunner) Clone(ctx context.Context, url, dir string) error {
+	return g.CloneFn(ctx, url, dir)
+}
@@ -18,3 +18,75 @@ type Parser struct {
 func (p *Parser) Parse(r io.Reader) (*diffview.Diff, error) {
 	return p.ParseFn(r)
 }
+
+// Compile-time interface verification.
+var _ diffview.SymbolResolver = (*SymbolResolver)(nil)
+
+// SymbolResolver is a mock implementation of diffview.SymbolResolver.
+type SymbolResolver struct {
+	ResolveSymbolsFn func(diff *diffview.Diff)
+}
+
+func (r *SymbolResolver) ResolveSymbols(diff *diffview.Diff) {
+	r.ResolveSymbolsFn(diff)
+}
+
+// Compile-time interface verification.
+var _ diffview.HunkGrouper = (*HunkGrouper)(nil)
+
+// HunkGrouper is a mock implementation of diffview.HunkGrouper.
+type HunkGrouper struct {
+	GroupHunksFn func(diff *diffview.Diff) []diffview.HunkGroup
+}
+
+func (g *HunkGrouper) GroupHunks(diff *diffview.Diff) []diffview.HunkGroup {
+	return g.GroupHunksFn(diff)
+}
+
+// Compile-time interface verification.
+var _ diffview.APIDiffer = (*APIDiffer)(nil)
+
+// APIDiffer is a mock implementation of diffview.APIDiffer.
+type APIDiffer struct {
+	DiffAPIFn func(diff *diffview.Diff) []diffview.APIChange
+}
+
+func (d *APIDiffer) DiffAPI(diff *diffview.Diff) []diffview.APIChange {
+	return d.DiffAPIFn(diff)
+}
+
+// Compile-time interface verification.
+var _ diffview.MoveDetector = (*MoveDetector)(nil)
+
+// MoveDetector is a mock implementation of diffview.MoveDetector.
+type MoveDetector struct {
+	DetectMovesFn func(diff *diffview.Diff) []diffview.Move
+}
+
+func (d *MoveDetector) DetectMoves(diff *diffview.Diff) []diffview.Move {
+	return d.DetectMovesFn(diff)
+}
+
+// Compile-time interface verification.
+var _ diffview.StructuralDiffer = (*StructuralDiffer)(nil)
+
+// StructuralDiffer is a mock implementation of diffview.StructuralDiffer.
+type StructuralDiffer struct {
+	DiffStructureFn func(file diffview.FileDiff) ([]diffview.KeyChange, error)
+}
+
+func (d *StructuralDiffer) DiffStructure(file diffview.FileDiff) ([]diffview.KeyChange, error) {
+	return d.DiffStructureFn(file)
+}
+
+// Compile-time interface verification.
+var _ diffview.DependencySummarizer = (*DependencySummarizer)(nil)
+
+// DependencySummarizer is a mock implementation of diffview.DependencySummarizer.
+type DependencySummarizer struct {
+	SummarizeDependenciesFn func(file diffview.FileDiff) ([]diffview.DependencyChange, bool)
+}
+
+func (s *DependencySummarizer) SummarizeDependencies(file diffview.FileDiff) ([]diffview.DependencyChange, bool) {
+	return s.SummarizeDependenciesFn(file)
+}
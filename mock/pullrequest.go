@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.PullRequestFinder = (*PullRequestFinder)(nil)
+
+// PullRequestFinder is a mock implementation of diffview.PullRequestFinder.
+type PullRequestFinder struct {
+	PullRequestFn func(ctx context.Context, number int) (*diffview.PullRequest, error)
+}
+
+func (f *PullRequestFinder) PullRequest(ctx context.Context, number int) (*diffview.PullRequest, error) {
+	return f.PullRequestFn(ctx, number)
+}
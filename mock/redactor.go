@@ -0,0 +1,27 @@
+package mock
+
+import "github.com/fwojciec/diffstory"
+
+// Compile-time interface verification.
+var (
+	_ diffview.Redactor       = (*Redactor)(nil)
+	_ diffview.SecretDetector = (*SecretDetector)(nil)
+)
+
+// Redactor is a mock implementation of diffview.Redactor.
+type Redactor struct {
+	RedactFn func(input diffview.ClassificationInput) (diffview.ClassificationInput, diffview.RedactionReport)
+}
+
+func (r *Redactor) Redact(input diffview.ClassificationInput) (diffview.ClassificationInput, diffview.RedactionReport) {
+	return r.RedactFn(input)
+}
+
+// SecretDetector is a mock implementation of diffview.SecretDetector.
+type SecretDetector struct {
+	DetectSecretFn func(content string) string
+}
+
+func (d *SecretDetector) DetectSecret(content string) string {
+	return d.DetectSecretFn(content)
+}
@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"iter"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.LanguageDetector = (*LanguageDetector)(nil)
+
+// LanguageDetector is a mock implementation of diffview.LanguageDetector.
+type LanguageDetector struct {
+	DetectFromPathFn    func(path string) string
+	DetectFromContentFn func(content string) string
+}
+
+func (d *LanguageDetector) DetectFromPath(path string) string {
+	if d.DetectFromPathFn == nil {
+		return ""
+	}
+	return d.DetectFromPathFn(path)
+}
+
+func (d *LanguageDetector) DetectFromContent(content string) string {
+	if d.DetectFromContentFn == nil {
+		return ""
+	}
+	return d.DetectFromContentFn(content)
+}
+
+// Compile-time interface verification.
+var _ diffview.Tokenizer = (*Tokenizer)(nil)
+
+// Tokenizer is a mock implementation of diffview.Tokenizer.
+type Tokenizer struct {
+	TokenizeLinesFn func(language, source string) [][]diffview.Token
+	StreamLinesFn   func(language, source string) iter.Seq[[]diffview.Token]
+}
+
+func (t *Tokenizer) TokenizeLines(language, source string) [][]diffview.Token {
+	return t.TokenizeLinesFn(language, source)
+}
+
+func (t *Tokenizer) StreamLines(language, source string) iter.Seq[[]diffview.Token] {
+	return t.StreamLinesFn(language, source)
+}
@@ -17,3 +17,71 @@ type Viewer struct {
 func (v *Viewer) View(ctx context.Context, diff *diffview.Diff) error {
 	return v.ViewFn(ctx, diff)
 }
+
+// Compile-time interface verification.
+var _ diffview.ViewStateStore = (*ViewStateStore)(nil)
+
+// ViewStateStore is a mock implementation of diffview.ViewStateStore.
+type ViewStateStore struct {
+	LoadFn func(diff *diffview.Diff) (*diffview.ViewState, error)
+	SaveFn func(diff *diffview.Diff, state diffview.ViewState) error
+}
+
+func (s *ViewStateStore) Load(diff *diffview.Diff) (*diffview.ViewState, error) {
+	return s.LoadFn(diff)
+}
+
+func (s *ViewStateStore) Save(diff *diffview.Diff, state diffview.ViewState) error {
+	return s.SaveFn(diff, state)
+}
+
+// Compile-time interface verification.
+var _ diffview.PreferencesStore = (*PreferencesStore)(nil)
+
+// PreferencesStore is a mock implementation of diffview.PreferencesStore.
+type PreferencesStore struct {
+	LoadFn func() (*diffview.Preferences, error)
+	SaveFn func(prefs diffview.Preferences) error
+}
+
+func (s *PreferencesStore) Load() (*diffview.Preferences, error) {
+	return s.LoadFn()
+}
+
+func (s *PreferencesStore) Save(prefs diffview.Preferences) error {
+	return s.SaveFn(prefs)
+}
+
+// Compile-time interface verification.
+var _ diffview.AnnotationStore = (*AnnotationStore)(nil)
+
+// AnnotationStore is a mock implementation of diffview.AnnotationStore.
+type AnnotationStore struct {
+	LoadFn   func(path string) ([]diffview.Annotation, error)
+	AppendFn func(path string, a diffview.Annotation) error
+}
+
+func (s *AnnotationStore) Load(path string) ([]diffview.Annotation, error) {
+	return s.LoadFn(path)
+}
+
+func (s *AnnotationStore) Append(path string, a diffview.Annotation) error {
+	return s.AppendFn(path, a)
+}
+
+// Compile-time interface verification.
+var _ diffview.Annotator = (*Annotator)(nil)
+
+// Annotator is a mock implementation of diffview.Annotator.
+type Annotator struct {
+	NameFn     func() string
+	AnnotateFn func(diff *diffview.Diff) []diffview.Annotation
+}
+
+func (a *Annotator) Name() string {
+	return a.NameFn()
+}
+
+func (a *Annotator) Annotate(diff *diffview.Diff) []diffview.Annotation {
+	return a.AnnotateFn(diff)
+}
@@ -0,0 +1,147 @@
+// Package moves detects blocks of lines a diff deletes in one place and
+// adds unchanged in another.
+package moves
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.MoveDetector = (*Detector)(nil)
+
+// MinAlnum is the fewest letters and digits a block must have to count as
+// moved, as in git's --color-moved, so that runs of braces and blank lines
+// that happen to match aren't reported.
+const MinAlnum = 20
+
+// Detector matches runs of deleted lines against runs of added lines.
+type Detector struct{}
+
+// NewDetector creates a Detector.
+func NewDetector() *Detector {
+	return &Detector{}
+}
+
+// run is a stretch of consecutive deleted or added lines in a hunk.
+type run struct {
+	path  string
+	lines []diffview.Line
+	used  []bool // added lines already matched by a move
+
+	// replacement is the added run directly after a deleted run, which
+	// edits rather than moves its lines, or -1 if there's none
+	replacement int
+}
+
+// DetectMoves returns the moves in diff, in the order of their deleted
+// lines. Lines match when they're equal apart from trailing whitespace.
+// Each deleted line is matched to the longest block of added lines it
+// begins, and each added line is matched at most once. Added lines that
+// directly replace the deleted ones are an edit, not a move, so aren't
+// matched, and combined diffs aren't searched.
+func (d *Detector) DetectMoves(diff *diffview.Diff) []diffview.Move {
+	deleted, added := runs(diff)
+
+	type position struct{ run, line int }
+	index := make(map[string][]position)
+	for r, a := range added {
+		for l, line := range a.lines {
+			if key := normalize(line.Content); key != "" {
+				index[key] = append(index[key], position{r, l})
+			}
+		}
+	}
+
+	var moves []diffview.Move
+	for _, del := range deleted {
+		for i := 0; i < len(del.lines); {
+			var best position
+			length := 0
+			for _, pos := range index[normalize(del.lines[i].Content)] {
+				if pos.run == del.replacement {
+					continue
+				}
+				a := added[pos.run]
+				n := 0
+				for i+n < len(del.lines) && pos.line+n < len(a.lines) && !a.used[pos.line+n] &&
+					normalize(del.lines[i+n].Content) == normalize(a.lines[pos.line+n].Content) {
+					n++
+				}
+				if n > length {
+					best, length = pos, n
+				}
+			}
+			if length == 0 || alnum(del.lines[i:i+length]) < MinAlnum {
+				i++
+				continue
+			}
+			a := added[best.run]
+			for n := 0; n < length; n++ {
+				a.used[best.line+n] = true
+			}
+			moves = append(moves, diffview.Move{
+				From: diffview.MovedLines{Path: del.path, Start: del.lines[i].OldLineNum, Count: length},
+				To:   diffview.MovedLines{Path: a.path, Start: a.lines[best.line].NewLineNum, Count: length},
+			})
+			i += length
+		}
+	}
+	return moves
+}
+
+// runs returns the runs of deleted and added lines in diff.
+func runs(diff *diffview.Diff) (deleted, added []*run) {
+	for _, file := range diff.Files {
+		path := file.Path()
+		for _, hunk := range file.Hunks {
+			if hunk.IsCombined() {
+				continue
+			}
+			var current *run
+			var currentType diffview.LineType
+			for _, line := range hunk.Lines {
+				if line.Type == diffview.LineContext {
+					current = nil
+					continue
+				}
+				if current == nil || line.Type != currentType {
+					previous := current
+					current = &run{path: path, replacement: -1}
+					currentType = line.Type
+					if line.Type == diffview.LineDeleted {
+						deleted = append(deleted, current)
+					} else {
+						if previous != nil {
+							previous.replacement = len(added)
+						}
+						added = append(added, current)
+					}
+				}
+				current.lines = append(current.lines, line)
+				current.used = append(current.used, false)
+			}
+		}
+	}
+	return deleted, added
+}
+
+// normalize returns a line's content without trailing whitespace.
+func normalize(content string) string {
+	return strings.TrimRightFunc(content, unicode.IsSpace)
+}
+
+// alnum counts the letters and digits in lines.
+func alnum(lines []diffview.Line) int {
+	n := 0
+	for _, line := range lines {
+		for _, r := range line.Content {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				n++
+			}
+		}
+	}
+	return n
+}
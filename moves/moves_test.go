@@ -0,0 +1,83 @@
+package moves_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/moves"
+	"github.com/stretchr/testify/assert"
+)
+
+// lines returns lines of typ with the given contents, numbered from start
+// on their side.
+func lines(typ diffview.LineType, start int, contents ...string) []diffview.Line {
+	var out []diffview.Line
+	for i, c := range contents {
+		line := diffview.Line{Type: typ, Content: c + "\n"}
+		if typ == diffview.LineDeleted {
+			line.OldLineNum = start + i
+		} else {
+			line.NewLineNum = start + i
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func hunk(groups ...[]diffview.Line) diffview.Hunk {
+	var h diffview.Hunk
+	for _, g := range groups {
+		h.Lines = append(h.Lines, g...)
+	}
+	return h
+}
+
+var block = []string{
+	"func validate(token string) error {",
+	"\tif token == \"\" {",
+	"\t\treturn errEmptyToken",
+	"\t}",
+}
+
+func TestDetector_DetectMoves(t *testing.T) {
+	t.Parallel()
+
+	t.Run("matches a block moved to another file", func(t *testing.T) {
+		t.Parallel()
+
+		diff := &diffview.Diff{Files: []diffview.FileDiff{
+			{OldPath: "a/auth.go", NewPath: "b/auth.go", Hunks: []diffview.Hunk{hunk(
+				lines(diffview.LineContext, 0, "package auth"),
+				lines(diffview.LineDeleted, 10, append([]string{"// validate checks tokens"}, block...)...),
+			)}},
+			{OldPath: "a/token.go", NewPath: "b/token.go", Hunks: []diffview.Hunk{hunk(
+				lines(diffview.LineAdded, 3, append(append([]string{}, block...), "\treturn nil  ")...),
+			)}},
+		}}
+
+		assert.Equal(t, []diffview.Move{{
+			From: diffview.MovedLines{Path: "auth.go", Start: 11, Count: 4},
+			To:   diffview.MovedLines{Path: "token.go", Start: 3, Count: 4},
+		}}, moves.NewDetector().DetectMoves(diff))
+	})
+
+	t.Run("ignores edits and short blocks", func(t *testing.T) {
+		t.Parallel()
+
+		diff := &diffview.Diff{Files: []diffview.FileDiff{
+			{NewPath: "auth.go", Hunks: []diffview.Hunk{
+				hunk(
+					lines(diffview.LineDeleted, 1, block...),
+					lines(diffview.LineAdded, 1, block[0]+" ", block[1], block[2], block[3]),
+				),
+				hunk(
+					lines(diffview.LineDeleted, 20, "\t}", "}"),
+					lines(diffview.LineContext, 0, "var x = 1"),
+					lines(diffview.LineAdded, 30, "\t}", "}"),
+				),
+			}},
+		}}
+
+		assert.Empty(t, moves.NewDetector().DetectMoves(diff))
+	})
+}
@@ -0,0 +1,126 @@
+// Package owners suggests who should review a diff, from the repository's
+// CODEOWNERS rules and from who changed the same files before.
+package owners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// CodeOwnersPaths returns where a CODEOWNERS file is looked for, in the
+// order GitHub looks for one.
+func CodeOwnersPaths() []string {
+	return []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+}
+
+// rule assigns owners to the paths a CODEOWNERS pattern matches.
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// CodeOwners holds the rules of a CODEOWNERS file. As on GitHub, the last
+// rule matching a path decides its owners.
+type CodeOwners struct {
+	rules []rule
+}
+
+// ParseCodeOwners reads CODEOWNERS rules from r: one per line, a gitignore
+// style pattern followed by its owners. A pattern without owners leaves the
+// paths it matches unowned.
+func ParseCodeOwners(r io.Reader) (*CodeOwners, error) {
+	c := &CodeOwners{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		pattern, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("CODEOWNERS line %d: %w", n, err)
+		}
+		r := rule{pattern: pattern}
+		if len(fields) > 1 {
+			r.owners = fields[1:]
+		}
+		c.rules = append(c.rules, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadCodeOwners parses the first CODEOWNERS file read finds at one of
+// CodeOwnersPaths, or returns nil if there is none. Paths read fails for
+// count as missing.
+func LoadCodeOwners(read func(path string) ([]byte, error)) (*CodeOwners, error) {
+	for _, path := range CodeOwnersPaths() {
+		data, err := read(path)
+		if err != nil {
+			continue
+		}
+		return ParseCodeOwners(bytes.NewReader(data))
+	}
+	return nil, nil
+}
+
+// Owners returns the owners of path, relative to the repository root, or
+// nil if no rule assigns it any. A nil CodeOwners owns nothing.
+func (c *CodeOwners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compilePattern turns a gitignore style pattern into a regular expression
+// matching the paths it covers. A pattern with a slash other than at its end
+// is anchored at the root; others match at any depth. A pattern matching a
+// directory covers everything in it.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+	if trimmed == "" {
+		return nil, fmt.Errorf("empty pattern %q", pattern)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			sb.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	sb.WriteString("(/.*)?$")
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,87 @@
+package owners_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory/owners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwners_Owners(t *testing.T) {
+	t.Parallel()
+
+	rules, err := owners.ParseCodeOwners(strings.NewReader(`# Default owners
+*                @org/core
+
+*.md             @org/docs   # prose
+/build/          @org/infra
+docs/**/api.md   @ann
+cmd/*/main.go    @bob @carol
+gen/             # generated, no owners
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"@org/core"}},
+		{"README.md", []string{"@org/docs"}},
+		{"pkg/notes.md", []string{"@org/docs"}},
+		{"build/ci/deploy.sh", []string{"@org/infra"}},
+		{"pkg/build/x.go", []string{"@org/core"}},
+		{"docs/api.md", []string{"@ann"}},
+		{"docs/v2/http/api.md", []string{"@ann"}},
+		{"cmd/tool/main.go", []string{"@bob", "@carol"}},
+		{"cmd/tool/sub/main.go", []string{"@org/core"}},
+		{"gen/types.go", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, rules.Owners(tt.path))
+		})
+	}
+}
+
+func TestCodeOwners_NilOwnsNothing(t *testing.T) {
+	t.Parallel()
+
+	var rules *owners.CodeOwners
+	assert.Nil(t, rules.Owners("main.go"))
+}
+
+func TestLoadCodeOwners(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads the first file found", func(t *testing.T) {
+		t.Parallel()
+
+		var tried []string
+		rules, err := owners.LoadCodeOwners(func(path string) ([]byte, error) {
+			tried = append(tried, path)
+			if path == "CODEOWNERS" {
+				return []byte("* @root\n"), nil
+			}
+			return nil, errors.New("not found")
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{".github/CODEOWNERS", "CODEOWNERS"}, tried)
+		assert.Equal(t, []string{"@root"}, rules.Owners("a.go"))
+	})
+
+	t.Run("returns nil without a file", func(t *testing.T) {
+		t.Parallel()
+
+		rules, err := owners.LoadCodeOwners(func(string) ([]byte, error) {
+			return nil, errors.New("not found")
+		})
+
+		require.NoError(t, err)
+		assert.Nil(t, rules)
+	})
+}
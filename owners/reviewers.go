@@ -0,0 +1,125 @@
+package owners
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.ReviewerSuggester = (*Suggester)(nil)
+
+// Suggestions are drawn from the last historySince of history, credit each
+// changed file's topAuthors most frequent authors, and stop at
+// maxReviewers.
+const (
+	historySince = "1 year ago"
+	topAuthors   = 2
+	maxReviewers = 5
+)
+
+// Suggester implements diffview.ReviewerSuggester: the CODEOWNERS owners
+// of the changed files come first, then the authors who changed them most.
+type Suggester struct {
+	runner   diffview.GitRunner
+	repoPath string
+	ref      string
+	owners   *CodeOwners
+	exclude  map[string]bool
+}
+
+// NewSuggester creates a Suggester reading history of the repository at
+// repoPath as of ref, usually the base branch. owners may be nil. Authors
+// named in exclude, such as the change's own, aren't suggested.
+func NewSuggester(runner diffview.GitRunner, repoPath, ref string, owners *CodeOwners, exclude ...string) *Suggester {
+	s := &Suggester{
+		runner:   runner,
+		repoPath: repoPath,
+		ref:      ref,
+		owners:   owners,
+		exclude:  make(map[string]bool, len(exclude)),
+	}
+	for _, name := range exclude {
+		s.exclude[name] = true
+	}
+	return s
+}
+
+// SuggestReviewers returns up to maxReviewers reviewers for diff: owners
+// first, then authors, each ordered by how many changed files they cover.
+func (s *Suggester) SuggestReviewers(ctx context.Context, diff *diffview.Diff) ([]diffview.Reviewer, error) {
+	byName := make(map[string]*diffview.Reviewer)
+	credit := func(name string, owner bool, path string, commits int) {
+		r, ok := byName[name]
+		if !ok {
+			r = &diffview.Reviewer{Name: name, Owner: owner}
+			byName[name] = r
+		}
+		r.Files = append(r.Files, path)
+		r.Commits += commits
+	}
+
+	for _, file := range diff.Files {
+		path := file.Path()
+		for _, owner := range s.owners.Owners(path) {
+			credit(owner, true, path, 0)
+		}
+
+		commits, err := s.runner.History(ctx, s.repoPath, diffview.HistoryQuery{Ref: s.ref, Since: historySince, Paths: []string{path}})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range s.topAuthors(commits) {
+			credit(a.name, false, path, a.commits)
+		}
+	}
+
+	reviewers := make([]diffview.Reviewer, 0, len(byName))
+	for _, r := range byName {
+		reviewers = append(reviewers, *r)
+	}
+	slices.SortFunc(reviewers, func(a, b diffview.Reviewer) int {
+		if a.Owner != b.Owner {
+			if a.Owner {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			cmp.Compare(len(b.Files), len(a.Files)),
+			cmp.Compare(b.Commits, a.Commits),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	if len(reviewers) > maxReviewers {
+		reviewers = reviewers[:maxReviewers]
+	}
+	return reviewers, nil
+}
+
+// authorCount is how many of a file's commits an author made.
+type authorCount struct {
+	name    string
+	commits int
+}
+
+// topAuthors returns the topAuthors authors of commits with the most
+// commits, leaving out excluded authors.
+func (s *Suggester) topAuthors(commits []diffview.CommitMeta) []authorCount {
+	counts := make(map[string]int)
+	for _, c := range commits {
+		if !s.exclude[c.Author] {
+			counts[c.Author]++
+		}
+	}
+	authors := make([]authorCount, 0, len(counts))
+	for name, n := range counts {
+		authors = append(authors, authorCount{name: name, commits: n})
+	}
+	slices.SortFunc(authors, func(a, b authorCount) int {
+		return cmp.Or(cmp.Compare(b.commits, a.commits), cmp.Compare(a.name, b.name))
+	})
+	return authors[:min(len(authors), topAuthors)]
+}
@@ -0,0 +1,68 @@
+package owners_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/fwojciec/diffstory/owners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggester_SuggestReviewers(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{NewPath: "b/api/handler.go", Operation: diffview.FileModified},
+		{NewPath: "b/api/routes.go", Operation: diffview.FileModified},
+		{OldPath: "a/legacy.go", Operation: diffview.FileDeleted},
+	}}
+	history := map[string][]string{
+		"api/handler.go": {"Ann", "Ann", "Bob", "Me", "Me", "Me", "Cid"},
+		"api/routes.go":  {"Bob", "Ann"},
+		"legacy.go":      {"Dee"},
+	}
+	runner := &mock.GitRunner{
+		HistoryFn: func(_ context.Context, repoPath string, q diffview.HistoryQuery) ([]diffview.CommitMeta, error) {
+			assert.Equal(t, "/repo", repoPath)
+			assert.Equal(t, "main", q.Ref)
+			assert.NotEmpty(t, q.Since)
+			require.Len(t, q.Paths, 1)
+			var commits []diffview.CommitMeta
+			for _, author := range history[q.Paths[0]] {
+				commits = append(commits, diffview.CommitMeta{Author: author})
+			}
+			return commits, nil
+		},
+	}
+	rules, err := owners.ParseCodeOwners(strings.NewReader("api/ @org/api\n"))
+	require.NoError(t, err)
+
+	reviewers, err := owners.NewSuggester(runner, "/repo", "main", rules, "Me").SuggestReviewers(context.Background(), diff)
+	require.NoError(t, err)
+
+	assert.Equal(t, []diffview.Reviewer{
+		{Name: "@org/api", Owner: true, Files: []string{"api/handler.go", "api/routes.go"}},
+		{Name: "Ann", Files: []string{"api/handler.go", "api/routes.go"}, Commits: 3},
+		{Name: "Bob", Files: []string{"api/handler.go", "api/routes.go"}, Commits: 2},
+		{Name: "Dee", Files: []string{"legacy.go"}, Commits: 1},
+	}, reviewers)
+}
+
+func TestSuggester_SuggestReviewers_HistoryError(t *testing.T) {
+	t.Parallel()
+
+	runner := &mock.GitRunner{
+		HistoryFn: func(context.Context, string, diffview.HistoryQuery) ([]diffview.CommitMeta, error) {
+			return nil, errors.New("git log failed")
+		},
+	}
+	diff := &diffview.Diff{Files: []diffview.FileDiff{{NewPath: "a.go"}}}
+
+	_, err := owners.NewSuggester(runner, "/repo", "main", nil).SuggestReviewers(context.Background(), diff)
+	assert.Error(t, err)
+}
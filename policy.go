@@ -0,0 +1,136 @@
+package diffview
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy is what a change's story is held to before it's pushed or
+// merged, such as by diffstory check in a pre-push hook. Rules left at
+// their zero value are off.
+//
+//	require_tests: true
+//	max_noise_ratio: 0.3
+//	max_risk: medium
+//	forbid_breaking: true
+//	required_roles: [core]
+type Policy struct {
+	// RequireTests fails a story with core or fix sections but no test
+	// section.
+	RequireTests bool `yaml:"require_tests"`
+
+	// MaxNoiseRatio is the largest share, from 0 to 1, of the story's hunks
+	// the classifier may categorize as noise. Nil for no limit.
+	MaxNoiseRatio *float64 `yaml:"max_noise_ratio"`
+
+	// MaxRisk is the highest risk level allowed: low, medium or high, or
+	// empty for any. Stories without a risk assessment pass.
+	MaxRisk string `yaml:"max_risk"`
+
+	// ForbidBreaking fails a story whose risk assessment lists breaking
+	// changes.
+	ForbidBreaking bool `yaml:"forbid_breaking"`
+
+	// RequiredRoles are section roles every story must have, such as
+	// "test".
+	RequiredRoles []string `yaml:"required_roles"`
+}
+
+// Policy rules, as violations name them.
+const (
+	RuleRequireTests   = "require_tests"
+	RuleMaxNoiseRatio  = "max_noise_ratio"
+	RuleMaxRisk        = "max_risk"
+	RuleForbidBreaking = "forbid_breaking"
+	RuleRequiredRoles  = "required_roles"
+)
+
+// riskRank orders the risk levels from lowest to highest, starting at 1,
+// and returns 0 for an unknown level.
+func riskRank(level string) int {
+	switch level {
+	case "low":
+		return 1
+	case "medium":
+		return 2
+	case "high":
+		return 3
+	}
+	return 0
+}
+
+// Validate returns an error if the policy's settings are out of range.
+func (p Policy) Validate() error {
+	if p.MaxNoiseRatio != nil && (*p.MaxNoiseRatio < 0 || *p.MaxNoiseRatio > 1) {
+		return fmt.Errorf("max_noise_ratio %v is out of range: want 0 to 1", *p.MaxNoiseRatio)
+	}
+	if p.MaxRisk != "" && riskRank(p.MaxRisk) == 0 {
+		return fmt.Errorf("unknown max_risk %q (want low, medium or high)", p.MaxRisk)
+	}
+	return nil
+}
+
+// PolicyViolation is a rule of a Policy a story breaks.
+type PolicyViolation struct {
+	Rule    string // Setting of the policy broken, such as "max_risk"
+	Message string // How the story breaks it
+}
+
+// String describes the violation in a line.
+func (v PolicyViolation) String() string {
+	return v.Rule + ": " + v.Message
+}
+
+// Check returns the rules story breaks, in the order Policy lists them.
+func (p Policy) Check(story *StoryClassification) []PolicyViolation {
+	var violations []PolicyViolation
+	roles := make(map[string]bool)
+	hunks, noise := 0, 0
+	for _, s := range story.Sections {
+		roles[s.Role] = true
+		for _, ref := range s.Hunks {
+			hunks++
+			if ref.Category == "noise" {
+				noise++
+			}
+		}
+	}
+
+	if p.RequireTests && (roles["core"] || roles["fix"]) && !roles["test"] {
+		violations = append(violations, PolicyViolation{
+			Rule:    RuleRequireTests,
+			Message: "the change has core or fix sections but no test section",
+		})
+	}
+	if p.MaxNoiseRatio != nil && hunks > 0 {
+		if ratio := float64(noise) / float64(hunks); ratio > *p.MaxNoiseRatio {
+			violations = append(violations, PolicyViolation{
+				Rule:    RuleMaxNoiseRatio,
+				Message: fmt.Sprintf("%d of %d hunks (%.0f%%) are noise, more than %.0f%%", noise, hunks, ratio*100, *p.MaxNoiseRatio*100),
+			})
+		}
+	}
+	if p.MaxRisk != "" && story.Risk != nil {
+		if riskRank(story.Risk.Level) > riskRank(p.MaxRisk) {
+			violations = append(violations, PolicyViolation{
+				Rule:    RuleMaxRisk,
+				Message: fmt.Sprintf("the change's risk is %s, above %s", story.Risk.Level, p.MaxRisk),
+			})
+		}
+	}
+	if p.ForbidBreaking && story.Risk != nil && len(story.Risk.Breaking) > 0 {
+		violations = append(violations, PolicyViolation{
+			Rule:    RuleForbidBreaking,
+			Message: fmt.Sprintf("the change breaks: %s", strings.Join(story.Risk.Breaking, "; ")),
+		})
+	}
+	for _, role := range p.RequiredRoles {
+		if !roles[role] {
+			violations = append(violations, PolicyViolation{
+				Rule:    RuleRequiredRoles,
+				Message: fmt.Sprintf("the story has no %s section", role),
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,90 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+
+	ratio := 0.25
+	story := &diffview.StoryClassification{
+		Sections: []diffview.Section{
+			{Role: "core", Hunks: []diffview.HunkRef{{File: "a.go", Category: "core"}, {File: "a.go", HunkIndex: 1, Category: "noise"}}},
+			{Role: "cleanup", Hunks: []diffview.HunkRef{{File: "b.go", Category: "noise"}, {File: "c.go", Category: "refactoring"}}},
+		},
+		Risk: &diffview.Risk{Level: "high", Breaking: []string{"Removes Parse", "Renames Load"}},
+	}
+
+	t.Run("every rule broken", func(t *testing.T) {
+		t.Parallel()
+
+		policy := diffview.Policy{
+			RequireTests:   true,
+			MaxNoiseRatio:  &ratio,
+			MaxRisk:        "medium",
+			ForbidBreaking: true,
+			RequiredRoles:  []string{"cleanup", "interface"},
+		}
+
+		assert.Equal(t, []diffview.PolicyViolation{
+			{Rule: diffview.RuleRequireTests, Message: "the change has core or fix sections but no test section"},
+			{Rule: diffview.RuleMaxNoiseRatio, Message: "2 of 4 hunks (50%) are noise, more than 25%"},
+			{Rule: diffview.RuleMaxRisk, Message: "the change's risk is high, above medium"},
+			{Rule: diffview.RuleForbidBreaking, Message: "the change breaks: Removes Parse; Renames Load"},
+			{Rule: diffview.RuleRequiredRoles, Message: "the story has no interface section"},
+		}, policy.Check(story))
+	})
+
+	t.Run("zero policy passes anything", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, diffview.Policy{}.Check(story))
+	})
+
+	t.Run("passing story", func(t *testing.T) {
+		t.Parallel()
+
+		half := 0.5
+		policy := diffview.Policy{RequireTests: true, MaxNoiseRatio: &half, MaxRisk: "medium", ForbidBreaking: true}
+		passing := &diffview.StoryClassification{
+			Sections: []diffview.Section{
+				{Role: "fix", Hunks: []diffview.HunkRef{{File: "a.go", Category: "core"}}},
+				{Role: "test", Hunks: []diffview.HunkRef{{File: "a_test.go", Category: "noise"}}},
+			},
+			Risk: &diffview.Risk{Level: "low"},
+		}
+
+		assert.Empty(t, policy.Check(passing))
+	})
+
+	t.Run("story without risk assessment", func(t *testing.T) {
+		t.Parallel()
+
+		policy := diffview.Policy{MaxRisk: "low", ForbidBreaking: true}
+
+		assert.Empty(t, policy.Check(&diffview.StoryClassification{}))
+	})
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tooHigh := 1.5
+	ok := 0.0
+
+	assert.NoError(t, diffview.Policy{MaxNoiseRatio: &ok, MaxRisk: "low"}.Validate())
+	assert.EqualError(t, diffview.Policy{MaxNoiseRatio: &tooHigh}.Validate(), "max_noise_ratio 1.5 is out of range: want 0 to 1")
+	assert.EqualError(t, diffview.Policy{MaxRisk: "critical"}.Validate(), `unknown max_risk "critical" (want low, medium or high)`)
+}
+
+func TestPolicyViolation_String(t *testing.T) {
+	t.Parallel()
+
+	v := diffview.PolicyViolation{Rule: "max_risk", Message: "too risky"}
+
+	assert.Equal(t, "max_risk: too risky", v.String())
+}
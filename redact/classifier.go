@@ -0,0 +1,75 @@
+package redact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.StoryClassifier = (*Classifier)(nil)
+
+// ErrSecretsDetected is returned by a Classifier that refuses inputs with
+// high-entropy secrets.
+var ErrSecretsDetected = errors.New("high-entropy secrets detected")
+
+// ClassifierOption configures a Classifier.
+type ClassifierOption func(*Classifier)
+
+// WithReports writes a line to w for each input with redactions, naming
+// the input and counting redactions per rule.
+func WithReports(w io.Writer) ClassifierOption {
+	return func(c *Classifier) {
+		c.reports = w
+	}
+}
+
+// RefuseSecrets makes the Classifier fail with ErrSecretsDetected, rather
+// than classify the redacted input, when it finds high-entropy secrets.
+func RefuseSecrets() ClassifierOption {
+	return func(c *Classifier) {
+		c.refuseSecrets = true
+	}
+}
+
+// Classifier wraps a StoryClassifier, redacting each input before passing
+// it on.
+type Classifier struct {
+	inner         diffview.StoryClassifier
+	redactor      diffview.Redactor
+	reports       io.Writer
+	refuseSecrets bool
+	mu            sync.Mutex // Serializes report lines from parallel callers
+}
+
+// NewClassifier creates a classifier that redacts inputs with redactor
+// before inner classifies them.
+func NewClassifier(inner diffview.StoryClassifier, redactor diffview.Redactor, opts ...ClassifierOption) *Classifier {
+	c := &Classifier{
+		inner:    inner,
+		redactor: redactor,
+		reports:  io.Discard,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Classify redacts input and delegates to the inner classifier.
+func (c *Classifier) Classify(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+	redacted, report := c.redactor.Redact(input)
+	if len(report.Redactions) > 0 {
+		c.mu.Lock()
+		fmt.Fprintf(c.reports, "redacted %s: %s\n", input.Name(), report)
+		c.mu.Unlock()
+	}
+	if n := report.HighEntropy(); c.refuseSecrets && n > 0 {
+		return nil, fmt.Errorf("%w: %d in %s", ErrSecretsDetected, n, input.Name())
+	}
+	return c.inner.Classify(ctx, redacted)
+}
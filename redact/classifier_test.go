@@ -0,0 +1,63 @@
+package redact_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/fwojciec/diffstory/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifier_Classify(t *testing.T) {
+	t.Parallel()
+
+	redactor := &mock.Redactor{
+		RedactFn: func(input diffview.ClassificationInput) (diffview.ClassificationInput, diffview.RedactionReport) {
+			input.PRTitle = "[REDACTED:email]"
+			return input, diffview.RedactionReport{Redactions: []diffview.Redaction{
+				{Rule: "email", Location: "pr title"},
+				{Rule: diffview.HighEntropyRule, Location: "main.go:3"},
+			}}
+		},
+	}
+	input := diffview.ClassificationInput{Repo: "repo", Branch: "branch", PRTitle: "ops@example.com"}
+
+	t.Run("classifies the redacted input and reports redactions", func(t *testing.T) {
+		t.Parallel()
+
+		var sent diffview.ClassificationInput
+		inner := &mock.StoryClassifier{
+			ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				sent = input
+				return &diffview.StoryClassification{ChangeType: "feature"}, nil
+			},
+		}
+		var reports bytes.Buffer
+
+		story, err := redact.NewClassifier(inner, redactor, redact.WithReports(&reports)).Classify(context.Background(), input)
+
+		require.NoError(t, err)
+		assert.Equal(t, "feature", story.ChangeType)
+		assert.Equal(t, "[REDACTED:email]", sent.PRTitle)
+		assert.Equal(t, "redacted repo/branch: email ×1, high-entropy ×1\n", reports.String())
+	})
+
+	t.Run("refuses inputs with high-entropy secrets", func(t *testing.T) {
+		t.Parallel()
+
+		inner := &mock.StoryClassifier{
+			ClassifyFn: func(ctx context.Context, input diffview.ClassificationInput) (*diffview.StoryClassification, error) {
+				t.Fatal("inner classifier should not be called")
+				return nil, nil
+			},
+		}
+
+		_, err := redact.NewClassifier(inner, redactor, redact.RefuseSecrets()).Classify(context.Background(), input)
+
+		assert.ErrorIs(t, err, redact.ErrSecretsDetected)
+	})
+}
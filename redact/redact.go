@@ -0,0 +1,315 @@
+// Package redact scrubs credentials, email addresses and other sensitive
+// content from classification input before it leaves the machine.
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var (
+	_ diffview.Redactor       = (*Redactor)(nil)
+	_ diffview.SecretDetector = (*Redactor)(nil)
+)
+
+// DefaultEntropyThreshold is the Shannon entropy, in bits per character,
+// above which a long token is treated as a secret.
+const DefaultEntropyThreshold = 4.0
+
+// minSecretLength is the shortest token checked for entropy. Shorter tokens
+// can't reach the threshold often enough to tell secrets from identifiers.
+const minSecretLength = 24
+
+// Rule redacts the text its pattern matches. If the pattern has a group
+// named "secret", only that group is redacted, so a rule can match
+// "api_key = ..." and keep the key's name.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// NewRule compiles a rule from a regular expression.
+func NewRule(name, expr string) (Rule, error) {
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule %s: %w", name, err)
+	}
+	return Rule{Name: name, Pattern: pattern}, nil
+}
+
+// ParseRule compiles a rule from "name=regexp", or a bare regular
+// expression, which is named "custom".
+func ParseRule(spec string) (Rule, error) {
+	name, expr, ok := strings.Cut(spec, "=")
+	if !ok || !isRuleName(name) {
+		return NewRule("custom", spec)
+	}
+	return NewRule(name, expr)
+}
+
+// isRuleName reports whether s can name a rule, telling "name=regexp" apart
+// from a regular expression that contains "=".
+func isRuleName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultRules returns the rules a Redactor applies unless told otherwise:
+// CredentialRules and email addresses.
+func DefaultRules() []Rule {
+	return append(CredentialRules(),
+		Rule{Name: "email", Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)})
+}
+
+// CredentialRules returns rules for well-known credential formats and key
+// assignments.
+func CredentialRules() []Rule {
+	return []Rule{
+		{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+		{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+		{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+		{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+		{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+		{Name: "api-key", Pattern: regexp.MustCompile(`(?i)(?:api[_-]?key|secret|token|passw(?:or)?d)["']?\s*[:=]\s*["']?(?P<secret>[^\s"'\x60\[(][^\s"'\x60()]{7,})(?:$|[\s"'\x60,;])`)},
+	}
+}
+
+// Option configures a Redactor.
+type Option func(*Redactor)
+
+// WithRules adds rules to those the Redactor applies.
+func WithRules(rules ...Rule) Option {
+	return func(r *Redactor) {
+		r.custom = append(r.custom, rules...)
+	}
+}
+
+// WithoutDefaultRules drops DefaultRules, leaving only rules added with
+// WithRules.
+func WithoutDefaultRules() Option {
+	return func(r *Redactor) {
+		r.noDefaults = true
+	}
+}
+
+// WithEntropyThreshold sets the entropy above which long tokens are
+// redacted as secrets. Zero turns entropy checks off.
+func WithEntropyThreshold(bits float64) Option {
+	return func(r *Redactor) {
+		r.entropy = bits
+	}
+}
+
+// Redactor replaces what its rules match, and long high-entropy tokens,
+// with a "[REDACTED:rule]" marker.
+type Redactor struct {
+	rules      []Rule
+	custom     []Rule
+	noDefaults bool
+	entropy    float64
+}
+
+// NewRedactor creates a Redactor that applies DefaultRules and entropy
+// checks at DefaultEntropyThreshold.
+func NewRedactor(opts ...Option) *Redactor {
+	r := &Redactor{entropy: DefaultEntropyThreshold}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if !r.noDefaults {
+		r.rules = DefaultRules()
+	}
+	r.rules = append(r.rules, r.custom...)
+	return r
+}
+
+// NewSecretDetector creates a Redactor for flagging credentials: it applies
+// CredentialRules, and entropy checks at DefaultEntropyThreshold, but
+// leaves emails alone.
+func NewSecretDetector(opts ...Option) *Redactor {
+	return NewRedactor(append([]Option{WithoutDefaultRules(), WithRules(CredentialRules()...)}, opts...)...)
+}
+
+// DetectSecret returns the name of the first rule content matches, or
+// HighEntropyRule for a high-entropy token, or "" if it looks safe.
+func (r *Redactor) DetectSecret(content string) string {
+	for _, rule := range r.rules {
+		if rule.Pattern.MatchString(content) {
+			return rule.Name
+		}
+	}
+	if r.entropy > 0 {
+		for _, token := range tokenPattern.FindAllString(content, -1) {
+			if isSecret(token, r.entropy) {
+				return diffview.HighEntropyRule
+			}
+		}
+	}
+	return ""
+}
+
+// Redact returns a copy of input with sensitive content replaced in its
+// diff, PR title and description, and commit messages and diffs. Commit
+// diffs repeat the combined diff, so their redactions aren't reported again.
+func (r *Redactor) Redact(input diffview.ClassificationInput) (diffview.ClassificationInput, diffview.RedactionReport) {
+	var report diffview.RedactionReport
+	out := input
+	out.PRTitle = r.redact(input.PRTitle, "pr title", &report)
+	out.PRDescription = r.redact(input.PRDescription, "pr description", &report)
+	out.Diff = r.redactDiff(input.Diff, &report)
+
+	out.Commits = make([]diffview.CommitBrief, len(input.Commits))
+	for i, commit := range input.Commits {
+		commit.Message = r.redact(commit.Message, "commit "+shortHash(commit.Hash), &report)
+		if commit.Diff != nil {
+			d := r.redactDiff(*commit.Diff, nil)
+			commit.Diff = &d
+		}
+		out.Commits[i] = commit
+	}
+	if input.Commits == nil {
+		out.Commits = nil
+	}
+	return out, report
+}
+
+// redactDiff returns a copy of d with line content redacted. If report is
+// nil, redactions go unreported.
+func (r *Redactor) redactDiff(d diffview.Diff, report *diffview.RedactionReport) diffview.Diff {
+	files := make([]diffview.FileDiff, len(d.Files))
+	for i, f := range d.Files {
+		hunks := make([]diffview.Hunk, len(f.Hunks))
+		for j, h := range f.Hunks {
+			lines := make([]diffview.Line, len(h.Lines))
+			for k, l := range h.Lines {
+				l.Content = r.redact(l.Content, lineLocation(f, l), report)
+				lines[k] = l
+			}
+			h.Lines = lines
+			hunks[j] = h
+		}
+		f.Hunks = hunks
+		files[i] = f
+	}
+	return diffview.Diff{Files: files}
+}
+
+// redact applies the rules and entropy check to s, adding what it replaces
+// to report at location.
+func (r *Redactor) redact(s, location string, report *diffview.RedactionReport) string {
+	if s == "" {
+		return s
+	}
+	add := func(rule string) {
+		if report != nil {
+			report.Redactions = append(report.Redactions, diffview.Redaction{Rule: rule, Location: location})
+		}
+	}
+	for _, rule := range r.rules {
+		s = replaceRule(s, rule, add)
+	}
+	if r.entropy > 0 {
+		s = tokenPattern.ReplaceAllStringFunc(s, func(token string) string {
+			if !isSecret(token, r.entropy) {
+				return token
+			}
+			add(diffview.HighEntropyRule)
+			return marker(diffview.HighEntropyRule)
+		})
+	}
+	return s
+}
+
+// replaceRule replaces each match of rule in s, or its "secret" group,
+// calling add for each.
+func replaceRule(s string, rule Rule, add func(rule string)) string {
+	group := rule.Pattern.SubexpIndex("secret")
+	matches := rule.Pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group > 0 && m[2*group] >= 0 {
+			start, end = m[2*group], m[2*group+1]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(marker(rule.Name))
+		last = end
+		add(rule.Name)
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func marker(rule string) string {
+	return "[REDACTED:" + rule + "]"
+}
+
+// tokenPattern matches runs of the characters keys and tokens are encoded
+// with: letters, digits, and base64 and URL-safe punctuation.
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_=-]{` + fmt.Sprint(minSecretLength) + `,}`)
+
+// isSecret reports whether token looks randomly generated: it mixes letters
+// and digits, and its entropy reaches threshold.
+func isSecret(token string, threshold float64) bool {
+	var letters, digits bool
+	for _, c := range token {
+		switch {
+		case c >= '0' && c <= '9':
+			digits = true
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			letters = true
+		}
+	}
+	return letters && digits && entropy(token) >= threshold
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+	n := float64(len(s))
+	var bits float64
+	for _, count := range counts {
+		p := float64(count) / n
+		bits -= p * math.Log2(p)
+	}
+	return bits
+}
+
+// lineLocation returns "path:line" for a line of f, numbered in the new
+// file unless the line was deleted.
+func lineLocation(f diffview.FileDiff, l diffview.Line) string {
+	path, num := f.Path(), l.NewLineNum
+	if l.Type == diffview.LineDeleted || num == 0 {
+		// Deleted lines of a renamed file are in the old one
+		path = strings.TrimPrefix(f.OldPath, "a/")
+		num = l.OldLineNum
+	}
+	return fmt.Sprintf("%s:%d", path, num)
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
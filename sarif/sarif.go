@@ -0,0 +1,129 @@
+// Package sarif writes findings as SARIF 2.1.0 logs, the format code
+// scanning tools such as GitHub's take results in.
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Version is the SARIF version written.
+const Version = "2.1.0"
+
+// SchemaURI is the JSON schema of the logs written.
+const SchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Tool names what produced a log.
+type Tool struct {
+	Name           string
+	Version        string // Optional
+	InformationURI string // Optional
+}
+
+// Write writes findings, as tool's results, to w as a SARIF log with a
+// single run. Each finding's rule becomes a rule of the tool. Findings on
+// the change as a whole have no location, which some consumers, GitHub
+// code scanning among them, leave out.
+func Write(w io.Writer, tool Tool, findings []diffview.Finding) error {
+	driver := driver{
+		Name:           tool.Name,
+		Version:        tool.Version,
+		InformationURI: tool.InformationURI,
+		Rules:          []rule{},
+	}
+	seen := make(map[string]bool)
+	results := make([]result, 0, len(findings))
+	for _, f := range findings {
+		if !seen[f.Rule] {
+			seen[f.Rule] = true
+			driver.Rules = append(driver.Rules, rule{ID: f.Rule})
+		}
+		r := result{
+			RuleID:  f.Rule,
+			Level:   resultLevel(f.Level),
+			Message: message{Text: f.Message},
+		}
+		if f.Title != "" {
+			r.Message.Text = f.Title + ": " + f.Message
+		}
+		if f.Path != "" {
+			loc := location{PhysicalLocation: physicalLocation{ArtifactLocation: artifactLocation{URI: f.Path}}}
+			if f.StartLine > 0 {
+				loc.PhysicalLocation.Region = &region{StartLine: f.StartLine, EndLine: max(f.EndLine, f.StartLine)}
+			}
+			r.Locations = []location{loc}
+		}
+		results = append(results, r)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log{
+		Version: Version,
+		Schema:  SchemaURI,
+		Runs:    []run{{Tool: toolComponent{Driver: driver}, Results: results}},
+	})
+}
+
+// resultLevel returns the SARIF level for level: SARIF calls a notice a
+// note.
+func resultLevel(level diffview.FindingLevel) string {
+	switch level {
+	case diffview.FindingNotice:
+		return "note"
+	case diffview.FindingError:
+		return "error"
+	default:
+		return "warning"
+	}
+}
+
+// The parts of a SARIF log written, as the specification names them.
+type (
+	log struct {
+		Version string `json:"version"`
+		Schema  string `json:"$schema"`
+		Runs    []run  `json:"runs"`
+	}
+	run struct {
+		Tool    toolComponent `json:"tool"`
+		Results []result      `json:"results"`
+	}
+	toolComponent struct {
+		Driver driver `json:"driver"`
+	}
+	driver struct {
+		Name           string `json:"name"`
+		Version        string `json:"version,omitempty"`
+		InformationURI string `json:"informationUri,omitempty"`
+		Rules          []rule `json:"rules"`
+	}
+	rule struct {
+		ID string `json:"id"`
+	}
+	result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations,omitempty"`
+	}
+	message struct {
+		Text string `json:"text"`
+	}
+	location struct {
+		PhysicalLocation physicalLocation `json:"physicalLocation"`
+	}
+	physicalLocation struct {
+		ArtifactLocation artifactLocation `json:"artifactLocation"`
+		Region           *region          `json:"region,omitempty"`
+	}
+	artifactLocation struct {
+		URI string `json:"uri"`
+	}
+	region struct {
+		StartLine int `json:"startLine"`
+		EndLine   int `json:"endLine"`
+	}
+)
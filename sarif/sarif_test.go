@@ -0,0 +1,62 @@
+package sarif_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/sarif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	findings := []diffview.Finding{
+		{Rule: "vet", Level: diffview.FindingWarning, Message: "unreachable code", Path: "api.go", StartLine: 42, EndLine: 42},
+		{Rule: "todo", Level: diffview.FindingNotice, Message: "TODO: tidy", Path: "util.go"},
+		{Rule: "risk", Level: diffview.FindingError, Title: "high risk", Message: "Risk: high"},
+		{Rule: "vet", Level: diffview.FindingWarning, Message: "shadowed err", Path: "api.go", StartLine: 50, EndLine: 52},
+	}
+	var sb strings.Builder
+
+	err := sarif.Write(&sb, sarif.Tool{Name: "diffstory", Version: "1.0.0"}, findings)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": [{
+			"tool": {"driver": {
+				"name": "diffstory",
+				"version": "1.0.0",
+				"rules": [{"id": "vet"}, {"id": "todo"}, {"id": "risk"}]
+			}},
+			"results": [
+				{"ruleId": "vet", "level": "warning", "message": {"text": "unreachable code"},
+				 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "api.go"}, "region": {"startLine": 42, "endLine": 42}}}]},
+				{"ruleId": "todo", "level": "note", "message": {"text": "TODO: tidy"},
+				 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "util.go"}}}]},
+				{"ruleId": "risk", "level": "error", "message": {"text": "high risk: Risk: high"}},
+				{"ruleId": "vet", "level": "warning", "message": {"text": "shadowed err"},
+				 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "api.go"}, "region": {"startLine": 50, "endLine": 52}}}]}
+			]
+		}]
+	}`, sb.String())
+}
+
+func TestWrite_NoFindings(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+
+	err := sarif.Write(&sb, sarif.Tool{Name: "diffstory"}, nil)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": [{"tool": {"driver": {"name": "diffstory", "rules": []}}, "results": []}]
+	}`, sb.String())
+}
@@ -0,0 +1,161 @@
+package diffview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion is the version of the JSON model of Diff,
+// StoryClassification, EvalCase and Judgment written today, as each
+// object's "schema_version" field. Version 1 is the first marked; objects
+// written before have the same fields and decode as version 1 too.
+// Judgment's own Version tracks the fields of a judgment record apart.
+const SchemaVersion = 1
+
+// SchemaVersionError is returned when decoding an object written with a
+// newer schema than this build reads, rather than silently dropping what
+// it doesn't know.
+type SchemaVersionError struct {
+	Type    string // Type decoded, such as "Diff"
+	Version int    // Version the object was written with
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("%s: unsupported schema version %d (this build reads up to %d)", e.Type, e.Version, SchemaVersion)
+}
+
+// checkSchemaVersion returns an error if version, as decoded for typ, is
+// newer than SchemaVersion. Unmarked objects decode as zero and pass.
+func checkSchemaVersion(typ string, version int) error {
+	if version > SchemaVersion {
+		return &SchemaVersionError{Type: typ, Version: version}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the diff with its schema version.
+func (d Diff) MarshalJSON() ([]byte, error) {
+	type plain Diff
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}{SchemaVersion, plain(d)})
+}
+
+// UnmarshalJSON decodes a diff, failing if it was written with a newer
+// schema.
+func (d *Diff) UnmarshalJSON(data []byte) error {
+	type plain Diff
+	var v struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkSchemaVersion("Diff", v.SchemaVersion); err != nil {
+		return err
+	}
+	*d = Diff(v.plain)
+	return nil
+}
+
+// MarshalJSON encodes the classification with its schema version.
+func (s StoryClassification) MarshalJSON() ([]byte, error) {
+	type plain StoryClassification
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}{SchemaVersion, plain(s)})
+}
+
+// UnmarshalJSON decodes a classification, failing if it was written with
+// a newer schema.
+func (s *StoryClassification) UnmarshalJSON(data []byte) error {
+	type plain StoryClassification
+	var v struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkSchemaVersion("StoryClassification", v.SchemaVersion); err != nil {
+		return err
+	}
+	*s = StoryClassification(v.plain)
+	return nil
+}
+
+// MarshalJSON encodes the case with its schema version.
+func (c EvalCase) MarshalJSON() ([]byte, error) {
+	type plain EvalCase
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}{SchemaVersion, plain(c)})
+}
+
+// UnmarshalJSON decodes a case, failing if it was written with a newer
+// schema.
+func (c *EvalCase) UnmarshalJSON(data []byte) error {
+	type plain EvalCase
+	var v struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkSchemaVersion("EvalCase", v.SchemaVersion); err != nil {
+		return err
+	}
+	*c = EvalCase(v.plain)
+	return nil
+}
+
+// MarshalJSON encodes the judgment with its schema version.
+func (j Judgment) MarshalJSON() ([]byte, error) {
+	type plain Judgment
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}{SchemaVersion, plain(j)})
+}
+
+// UnmarshalJSON decodes a judgment, failing if it was written with a newer
+// schema.
+func (j *Judgment) UnmarshalJSON(data []byte) error {
+	type plain Judgment
+	var v struct {
+		SchemaVersion int `json:"schema_version"`
+		plain
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkSchemaVersion("Judgment", v.SchemaVersion); err != nil {
+		return err
+	}
+	*j = Judgment(v.plain)
+	return nil
+}
+
+// EncodeDiff writes diff to w as indented JSON, for tools downstream of
+// diffview parse --json.
+func EncodeDiff(w io.Writer, diff *Diff) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diff)
+}
+
+// DecodeDiff reads a diff written by EncodeDiff, or any JSON object of the
+// same schema, from r.
+func DecodeDiff(r io.Reader) (*Diff, error) {
+	var diff Diff
+	if err := json.NewDecoder(r).Decode(&diff); err != nil {
+		return nil, fmt.Errorf("failed to decode diff: %w", err)
+	}
+	return &diff, nil
+}
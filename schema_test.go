@@ -0,0 +1,133 @@
+package diffview_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_MarshalsVersion(t *testing.T) {
+	t.Parallel()
+
+	for name, v := range map[string]any{
+		"Diff":                diffview.Diff{},
+		"StoryClassification": diffview.StoryClassification{},
+		"EvalCase":            diffview.EvalCase{},
+		"Judgment":            diffview.Judgment{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(v)
+			require.NoError(t, err)
+
+			var fields map[string]any
+			require.NoError(t, json.Unmarshal(data, &fields))
+			assert.EqualValues(t, diffview.SchemaVersion, fields["schema_version"])
+		})
+	}
+}
+
+func TestSchema_RoundTrips(t *testing.T) {
+	t.Parallel()
+
+	story := &diffview.StoryClassification{
+		ChangeType: "bugfix",
+		Narrative:  "cause-effect",
+		Summary:    "Fix the thing",
+		Sections:   []diffview.Section{{Role: "fix", Title: "Fix", Hunks: []diffview.HunkRef{{File: "main.go", HunkIndex: 0}}}},
+	}
+	evalCase := diffview.EvalCase{
+		ID: "abc",
+		Input: diffview.ClassificationInput{Diff: diffview.Diff{Files: []diffview.FileDiff{{
+			NewPath: "main.go",
+			Hunks:   []diffview.Hunk{{NewStart: 1, NewCount: 1, Lines: []diffview.Line{{Type: diffview.LineAdded, Content: "x\n", NewLineNum: 1}}}},
+		}}}},
+		Story: story,
+	}
+	judgment := diffview.Judgment{
+		Version:  diffview.JudgmentVersion,
+		CaseID:   "abc",
+		Judged:   true,
+		JudgedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Gold:     story,
+	}
+
+	data, err := json.Marshal(evalCase)
+	require.NoError(t, err)
+	var gotCase diffview.EvalCase
+	require.NoError(t, json.Unmarshal(data, &gotCase))
+	assert.Equal(t, evalCase, gotCase)
+
+	data, err = json.Marshal(judgment)
+	require.NoError(t, err)
+	var gotJudgment diffview.Judgment
+	require.NoError(t, json.Unmarshal(data, &gotJudgment))
+	assert.Equal(t, judgment, gotJudgment)
+}
+
+func TestSchema_DecodesUnmarkedObjects(t *testing.T) {
+	t.Parallel()
+
+	var story diffview.StoryClassification
+	err := json.Unmarshal([]byte(`{"change_type":"feature","summary":"Add it"}`), &story)
+
+	require.NoError(t, err)
+	assert.Equal(t, "feature", story.ChangeType)
+	assert.Equal(t, "Add it", story.Summary)
+}
+
+func TestSchema_RejectsNewerVersion(t *testing.T) {
+	t.Parallel()
+
+	var judgment diffview.Judgment
+	err := json.Unmarshal([]byte(`{"schema_version":99,"case_id":"abc"}`), &judgment)
+
+	var versionErr *diffview.SchemaVersionError
+	require.ErrorAs(t, err, &versionErr)
+	assert.Equal(t, "Judgment", versionErr.Type)
+	assert.Equal(t, 99, versionErr.Version)
+}
+
+func TestEncodeDiff_DecodeDiff(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{{
+			OldPath:   "main.go",
+			NewPath:   "main.go",
+			Operation: diffview.FileModified,
+			Hunks: []diffview.Hunk{{
+				OldStart: 1, OldCount: 1, NewStart: 1, NewCount: 1,
+				Lines: []diffview.Line{
+					{Type: diffview.LineDeleted, Content: "old\n", OldLineNum: 1},
+					{Type: diffview.LineAdded, Content: "new\n", NewLineNum: 1},
+				},
+			}},
+		}},
+		Skipped: []diffview.SkippedSection{{Path: "util.go", Header: "@@ bad", Lines: 2, Reason: "bad header"}},
+	}
+	var buf bytes.Buffer
+
+	require.NoError(t, diffview.EncodeDiff(&buf, diff))
+	got, err := diffview.DecodeDiff(&buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, diff, got)
+}
+
+func TestDecodeDiff_NewerVersion(t *testing.T) {
+	t.Parallel()
+
+	_, err := diffview.DecodeDiff(strings.NewReader(`{"schema_version":2,"Files":[]}`))
+
+	var versionErr *diffview.SchemaVersionError
+	require.ErrorAs(t, err, &versionErr)
+	assert.Equal(t, "Diff", versionErr.Type)
+}
@@ -0,0 +1,114 @@
+package diffview
+
+// Score measures how closely model classifications match gold labels, as
+// counts summed over the scored cases.
+type Score struct {
+	Cases int // cases with both a model classification and a gold label
+
+	ChangeTypeMatches int // cases whose change type matches gold
+
+	GoldHunks    int // hunks placed in sections by the gold labels
+	CoveredHunks int // of those, hunks the model also placed in a section
+
+	HunkPairs     int // pairs of gold hunks within the same case
+	AgreeingPairs int // of those, pairs both put together, or both apart
+}
+
+// ScoreAgainstGold scores the model classification of every case that has a
+// gold label. Hunks are identified by file and index; a hunk the model left
+// out counts as a section of its own.
+func ScoreAgainstGold(cases []EvalCase) Score {
+	var s Score
+	for _, c := range cases {
+		if c.Story == nil || c.Gold == nil {
+			continue
+		}
+		s.Cases++
+		if c.Story.ChangeType == c.Gold.ChangeType {
+			s.ChangeTypeMatches++
+		}
+
+		model := sectionsByHunk(c.Story)
+		gold := sectionsByHunk(c.Gold)
+		hunks := goldHunks(c.Gold)
+		for i, h := range hunks {
+			s.GoldHunks++
+			if _, ok := model[h]; ok {
+				s.CoveredHunks++
+			}
+			for _, other := range hunks[i+1:] {
+				s.HunkPairs++
+				if sameSection(model, h, other) == sameSection(gold, h, other) {
+					s.AgreeingPairs++
+				}
+			}
+		}
+	}
+	return s
+}
+
+// ChangeTypeAccuracy returns the fraction of cases whose change type
+// matches gold, or 0 if no cases were scored.
+func (s Score) ChangeTypeAccuracy() float64 {
+	return ratio(s.ChangeTypeMatches, s.Cases)
+}
+
+// HunkCoverage returns the fraction of gold hunks the model placed in a
+// section.
+func (s Score) HunkCoverage() float64 {
+	return ratio(s.CoveredHunks, s.GoldHunks)
+}
+
+// SectionAccuracy returns the fraction of hunk pairs the model grouped as
+// gold does, together or apart.
+func (s Score) SectionAccuracy() float64 {
+	return ratio(s.AgreeingPairs, s.HunkPairs)
+}
+
+func ratio(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(n) / float64(of)
+}
+
+// hunkID identifies a hunk within a case.
+type hunkID struct {
+	file  string
+	index int
+}
+
+// sectionsByHunk maps each hunk in story to the section it is in.
+func sectionsByHunk(story *StoryClassification) map[hunkID]int {
+	sections := make(map[hunkID]int)
+	for i, section := range story.Sections {
+		for _, h := range section.Hunks {
+			sections[hunkID{h.File, h.HunkIndex}] = i
+		}
+	}
+	return sections
+}
+
+// goldHunks returns the distinct hunks in story, in section order.
+func goldHunks(story *StoryClassification) []hunkID {
+	var hunks []hunkID
+	seen := make(map[hunkID]bool)
+	for _, section := range story.Sections {
+		for _, h := range section.Hunks {
+			id := hunkID{h.File, h.HunkIndex}
+			if !seen[id] {
+				seen[id] = true
+				hunks = append(hunks, id)
+			}
+		}
+	}
+	return hunks
+}
+
+// sameSection reports whether a and b are in the same section, counting a
+// hunk in no section as alone.
+func sameSection(sections map[hunkID]int, a, b hunkID) bool {
+	sa, okA := sections[a]
+	sb, okB := sections[b]
+	return okA && okB && sa == sb
+}
@@ -0,0 +1,60 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreAgainstGold(t *testing.T) {
+	t.Parallel()
+
+	hunk := func(file string) diffview.HunkRef { return diffview.HunkRef{File: file} }
+	gold := &diffview.StoryClassification{
+		ChangeType: "bugfix",
+		Sections: []diffview.Section{
+			{Hunks: []diffview.HunkRef{hunk("a.go"), hunk("b.go")}},
+			{Hunks: []diffview.HunkRef{hunk("a_test.go")}},
+		},
+	}
+
+	t.Run("counts matches against the gold label", func(t *testing.T) {
+		t.Parallel()
+
+		// b.go is left out; a.go and a_test.go are wrongly grouped
+		model := &diffview.StoryClassification{
+			ChangeType: "feature",
+			Sections: []diffview.Section{
+				{Hunks: []diffview.HunkRef{hunk("a.go"), hunk("a_test.go")}},
+			},
+		}
+		score := diffview.ScoreAgainstGold([]diffview.EvalCase{
+			{Story: model, Gold: gold},
+			{Story: gold, Gold: gold},
+			{Story: model}, // no gold label, not scored
+		})
+
+		assert.Equal(t, diffview.Score{
+			Cases:             2,
+			ChangeTypeMatches: 1,
+			GoldHunks:         6,
+			CoveredHunks:      5,
+			HunkPairs:         6,
+			AgreeingPairs:     4,
+		}, score)
+		assert.InDelta(t, 0.5, score.ChangeTypeAccuracy(), 1e-9)
+		assert.InDelta(t, 5.0/6, score.HunkCoverage(), 1e-9)
+		assert.InDelta(t, 4.0/6, score.SectionAccuracy(), 1e-9)
+	})
+
+	t.Run("scores nothing without gold labels", func(t *testing.T) {
+		t.Parallel()
+
+		score := diffview.ScoreAgainstGold([]diffview.EvalCase{{Story: gold}})
+
+		assert.Zero(t, score.Cases)
+		assert.Zero(t, score.ChangeTypeAccuracy())
+		assert.Zero(t, score.SectionAccuracy())
+	})
+}
@@ -0,0 +1,129 @@
+package diffview
+
+import "sort"
+
+// StoryChanges describes how a second classification of a case differs
+// from the first, for spotting what a new prompt changed.
+type StoryChanges struct {
+	ChangeType bool             // The change types differ
+	Summary    bool             // The summaries differ
+	Retitled   []SectionRetitle // Sections whose titles changed
+	Moved      []HunkMove       // Hunks placed in a different section
+}
+
+// SectionRetitle is a section the second classification titles differently.
+type SectionRetitle struct {
+	Index    int // Position of the section in the second classification
+	From, To string
+}
+
+// HunkMove is a hunk the second classification places in a different
+// section, named by the sections' titles. An empty title means no section.
+type HunkMove struct {
+	File      string
+	HunkIndex int
+	From, To  string
+}
+
+// Empty reports whether nothing the changes track differs, though the
+// wording of explanations and the like may.
+func (c StoryChanges) Empty() bool {
+	return !c.ChangeType && !c.Summary && len(c.Retitled) == 0 && len(c.Moved) == 0
+}
+
+// CompareStories returns how b differs from a. Sections are matched one to
+// one: each section of a to the section of b sharing the most of its hunks,
+// and a section of b sharing none to the unmatched section of a at the same
+// position. A hunk moved if it's in a section not matched to the one it was
+// in. Either story being nil means no changes.
+func CompareStories(a, b *StoryClassification) StoryChanges {
+	var changes StoryChanges
+	if a == nil || b == nil {
+		return changes
+	}
+	changes.ChangeType = a.ChangeType != b.ChangeType
+	changes.Summary = a.Summary != b.Summary
+
+	before, after := sectionsByHunk(a), sectionsByHunk(b)
+	match := matchSections(a, b, before)
+	for i, section := range b.Sections {
+		if s := match[i]; s >= 0 && a.Sections[s].Title != section.Title {
+			changes.Retitled = append(changes.Retitled, SectionRetitle{Index: i, From: a.Sections[s].Title, To: section.Title})
+		}
+	}
+
+	title := func(story *StoryClassification, sections map[hunkID]int, id hunkID) string {
+		if s, ok := sections[id]; ok {
+			return story.Sections[s].Title
+		}
+		return ""
+	}
+	moved := func(id hunkID) bool {
+		sa, inA := before[id]
+		sb, inB := after[id]
+		if !inA || !inB {
+			return inA != inB
+		}
+		return match[sb] != sa
+	}
+	seen := make(map[hunkID]bool)
+	for _, id := range append(goldHunks(a), goldHunks(b)...) {
+		if seen[id] || !moved(id) {
+			continue
+		}
+		seen[id] = true
+		changes.Moved = append(changes.Moved, HunkMove{
+			File: id.file, HunkIndex: id.index,
+			From: title(a, before, id), To: title(b, after, id),
+		})
+	}
+	return changes
+}
+
+// matchSections returns the section of a matched to each section of b, or
+// -1 for a section of b that's new, as CompareStories describes. before
+// maps a's hunks to their sections.
+func matchSections(a, b *StoryClassification, before map[hunkID]int) []int {
+	type pair struct{ b, a, shared int }
+	var pairs []pair
+	sharesAny := make([]bool, len(b.Sections))
+	for i, section := range b.Sections {
+		shared := make(map[int]int)
+		for _, h := range section.Hunks {
+			if s, ok := before[hunkID{h.File, h.HunkIndex}]; ok {
+				shared[s]++
+			}
+		}
+		for s, n := range shared {
+			pairs = append(pairs, pair{b: i, a: s, shared: n})
+		}
+		sharesAny[i] = len(shared) > 0
+	}
+	// Pairs sharing the most hunks match first
+	sort.Slice(pairs, func(i, k int) bool {
+		if pairs[i].shared != pairs[k].shared {
+			return pairs[i].shared > pairs[k].shared
+		}
+		if pairs[i].b != pairs[k].b {
+			return pairs[i].b < pairs[k].b
+		}
+		return pairs[i].a < pairs[k].a
+	})
+
+	match := make([]int, len(b.Sections))
+	for i := range match {
+		match[i] = -1
+	}
+	matched := make([]bool, len(a.Sections))
+	for _, p := range pairs {
+		if match[p.b] < 0 && !matched[p.a] {
+			match[p.b], matched[p.a] = p.a, true
+		}
+	}
+	for i := range b.Sections {
+		if match[i] < 0 && !sharesAny[i] && i < len(a.Sections) && !matched[i] {
+			match[i], matched[i] = i, true
+		}
+	}
+	return match
+}
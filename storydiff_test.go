@@ -0,0 +1,54 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareStories(t *testing.T) {
+	t.Parallel()
+
+	ref := func(file string, idx int) diffview.HunkRef { return diffview.HunkRef{File: file, HunkIndex: idx} }
+	before := &diffview.StoryClassification{
+		ChangeType: "bugfix",
+		Summary:    "Fix the cache",
+		Sections: []diffview.Section{
+			{Title: "The fix", Hunks: []diffview.HunkRef{ref("cache.go", 0), ref("cache.go", 1)}},
+			{Title: "Tests", Hunks: []diffview.HunkRef{ref("cache_test.go", 0)}},
+		},
+	}
+
+	t.Run("finds nothing in identical stories", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, diffview.CompareStories(before, before).Empty())
+		assert.True(t, diffview.CompareStories(before, nil).Empty())
+	})
+
+	t.Run("finds changed summaries, titles and moved hunks", func(t *testing.T) {
+		t.Parallel()
+
+		after := &diffview.StoryClassification{
+			ChangeType: "bugfix",
+			Summary:    "Invalidate the cache on write",
+			Sections: []diffview.Section{
+				{Title: "Tests first", Hunks: []diffview.HunkRef{ref("cache_test.go", 0)}},
+				{Title: "The fix", Hunks: []diffview.HunkRef{ref("cache.go", 0), ref("config.go", 0)}},
+				{Title: "Cleanup", Hunks: []diffview.HunkRef{ref("cache.go", 1)}},
+			},
+		}
+
+		changes := diffview.CompareStories(before, after)
+
+		assert.False(t, changes.ChangeType)
+		assert.True(t, changes.Summary)
+		assert.Equal(t, []diffview.SectionRetitle{{Index: 0, From: "Tests", To: "Tests first"}}, changes.Retitled,
+			"a section split off isn't a retitle")
+		assert.Equal(t, []diffview.HunkMove{
+			{File: "cache.go", HunkIndex: 1, From: "The fix", To: "Cleanup"},
+			{File: "config.go", HunkIndex: 0, From: "", To: "The fix"},
+		}, changes.Moved)
+	})
+}
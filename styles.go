@@ -1,11 +1,12 @@
 package diffview
 
-// Color is a hex string in "#RRGGBB" format (e.g., "#ff0000" for red).
-// Empty string indicates no color (use terminal default).
+// Color is a hex string in "#RRGGBB" format (e.g., "#ff0000" for red), or
+// an ANSI color index (e.g., "1" or "196") in themes for terminals with
+// fewer colors. Empty string indicates no color (use terminal default).
 type Color string
 
 // Palette defines semantic colors for a theme.
-// All colors are hex strings in "#RRGGBB" format.
+// All colors are in the formats of Color.
 type Palette struct {
 	// Base colors
 	Background Color // Primary background
@@ -35,8 +36,7 @@ type Palette struct {
 }
 
 // ColorPair represents a foreground and background color combination.
-// Colors should be hex strings in "#RRGGBB" format (e.g., "#ff0000" for red).
-// Empty strings are valid and indicate no color override (use terminal default).
+// Colors are in the same formats as Color. Empty strings are valid and indicate no color override (use terminal default).
 type ColorPair struct {
 	Foreground string
 	Background string
@@ -55,6 +55,17 @@ type Styles struct {
 	DeletedGutter    ColorPair // Style for gutter on deleted lines (stronger background)
 	AddedHighlight   ColorPair // Style for changed text within added lines (word-level diff)
 	DeletedHighlight ColorPair // Style for changed text within deleted lines (word-level diff)
+	Ours             ColorPair // Style for merge lines that came from the first parent (combined diffs)
+	Theirs           ColorPair // Style for merge lines that came from the second parent (combined diffs)
+	Moved            ColorPair // Style for deleted and added lines moved elsewhere in the diff, unchanged
+	SectionBanner    ColorPair // Style for story section banners whose role has no entry in SectionRoles
+	// SectionRoles colors story section banners by section role ("problem",
+	// "fix", "core", ...). Roles without an entry use SectionBanner.
+	SectionRoles map[string]ColorPair
+	// Symbols sets changes apart without relying on color: added and
+	// deleted lines get a marker beside the gutter, added lines are bold,
+	// changed words are underlined, and syntax highlighting is off.
+	Symbols bool
 }
 
 // Theme provides styles for rendering diffs.
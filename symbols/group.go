@@ -0,0 +1,127 @@
+package symbols
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.HunkGrouper = (*Grouper)(nil)
+
+// declPattern matches the name in a declaration of a function, method,
+// type or class, in Go and other common languages.
+var declPattern = regexp.MustCompile(`\b(?:func(?:\s*\([^)]*\))?|type|def|class|function)\s+([A-Za-z_]\w*)`)
+
+// identPattern matches an identifier.
+var identPattern = regexp.MustCompile(`[A-Za-z_]\w*`)
+
+// minSymbolLength leaves out short names, such as loop variables and
+// receivers, which match too widely to relate hunks.
+const minSymbolLength = 3
+
+// Grouper groups hunks by the symbols they declare and use, working from
+// the text of the diff rather than a parse of the files.
+type Grouper struct{}
+
+// NewGrouper creates a Grouper.
+func NewGrouper() *Grouper {
+	return &Grouper{}
+}
+
+// GroupHunks returns a group for each symbol that two or more hunks touch,
+// in the order of their first hunks. A hunk touches the symbols declared on
+// its changed lines and the one its Section names, as do hunks whose
+// changed lines use any of them. Symbols touching the same hunks, such as
+// a function's old and new names, share a group.
+func (g *Grouper) GroupHunks(diff *diffview.Diff) []diffview.HunkGroup {
+	type hunkInfo struct {
+		ref      diffview.HunkRef
+		declared []string
+		used     map[string]bool
+	}
+	var hunks []hunkInfo
+	for _, file := range diff.Files {
+		path := file.NewPath
+		if path == "" {
+			path = file.OldPath
+		}
+		for i, hunk := range file.Hunks {
+			info := hunkInfo{ref: diffview.HunkRef{File: path, HunkIndex: i}, used: make(map[string]bool)}
+			if m := declPattern.FindStringSubmatch(hunk.Section); m != nil {
+				info.declared = append(info.declared, m[1])
+			}
+			for _, line := range hunk.Lines {
+				if line.Type == diffview.LineContext {
+					continue
+				}
+				for _, m := range declPattern.FindAllStringSubmatch(line.Content, -1) {
+					info.declared = append(info.declared, m[1])
+				}
+				for _, ident := range identPattern.FindAllString(line.Content, -1) {
+					info.used[ident] = true
+				}
+			}
+			hunks = append(hunks, info)
+		}
+	}
+
+	// Hunks touching each symbol, as indices into hunks in diff order
+	touching := make(map[string][]int)
+	for _, h := range hunks {
+		for _, name := range h.declared {
+			if len(name) < minSymbolLength || touching[name] != nil {
+				continue
+			}
+			for j, other := range hunks {
+				if other.used[name] || slices.Contains(other.declared, name) {
+					touching[name] = append(touching[name], j)
+				}
+			}
+		}
+	}
+
+	// Merge symbols touching the same hunks, in sorted order so names
+	// join the same way each time
+	names := make([]string, 0, len(touching))
+	for name, indices := range touching {
+		if len(indices) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var groups []diffview.HunkGroup
+	var firsts []int
+	byHunks := make(map[string]int)
+	for _, name := range names {
+		indices := touching[name]
+		key := fmt.Sprint(indices)
+		if i, ok := byHunks[key]; ok {
+			groups[i].Symbol += ", " + name
+			continue
+		}
+		group := diffview.HunkGroup{Symbol: name}
+		for _, j := range indices {
+			group.Hunks = append(group.Hunks, hunks[j].ref)
+		}
+		byHunks[key] = len(groups)
+		groups = append(groups, group)
+		firsts = append(firsts, indices[0])
+	}
+
+	// Order groups by their first hunk; the sort is stable, so groups
+	// starting at the same hunk stay in name order
+	order := make([]int, len(groups))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return firsts[order[a]] < firsts[order[b]] })
+	sorted := make([]diffview.HunkGroup, len(groups))
+	for i, j := range order {
+		sorted[i] = groups[j]
+	}
+	return sorted
+}
@@ -0,0 +1,41 @@
+package symbols_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+// changed returns a hunk under section that deletes old and adds new, with
+// a context line using neither.
+func changed(section, old, new string) diffview.Hunk {
+	return diffview.Hunk{Section: section, Lines: []diffview.Line{
+		{Type: diffview.LineContext, Content: "\tstart := time.Now()\n"},
+		{Type: diffview.LineDeleted, Content: old},
+		{Type: diffview.LineAdded, Content: new},
+	}}
+}
+
+func TestGrouper_GroupHunks(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{NewPath: "server.go", Hunks: []diffview.Hunk{
+			changed("", "func listen(addr string) error {\n", "func bind(addr string) error {\n"),
+			changed("func (s *Server) Start(ctx context.Context) error {", "\tif err := listen(s.addr); err != nil {\n", "\tif err := bind(s.addr); err != nil {\n"),
+		}},
+		{NewPath: "main.go", Hunks: []diffview.Hunk{
+			changed("func main", "\tsrv.Start(nil)\n", "\tsrv.Start(ctx)\n"),
+			changed("func usage", "\tfmt.Println(\"start\")\n", "\tfmt.Println(\"serve\")\n"),
+		}},
+	}}
+
+	groups := symbols.NewGrouper().GroupHunks(diff)
+
+	assert.Equal(t, []diffview.HunkGroup{
+		{Symbol: "bind, listen", Hunks: []diffview.HunkRef{{File: "server.go", HunkIndex: 0}, {File: "server.go", HunkIndex: 1}}},
+		{Symbol: "Start", Hunks: []diffview.HunkRef{{File: "server.go", HunkIndex: 1}, {File: "main.go", HunkIndex: 0}}},
+	}, groups, "main and usage touch only their own hunks")
+}
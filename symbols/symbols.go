@@ -0,0 +1,177 @@
+// Package symbols finds the declarations that enclose each hunk of a diff,
+// for hunk headers and classifier input. Go files are parsed with go/parser;
+// hunks in other files keep the section git chose.
+package symbols
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.SymbolResolver = (*Resolver)(nil)
+
+// Source returns the new version of the file at path, which is relative to
+// the root of the repository, such as "pkg/server.go".
+type Source func(path string) ([]byte, error)
+
+// DirSource reads files from the working tree rooted at dir.
+func DirSource(dir string) Source {
+	return func(path string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	}
+}
+
+// Resolver resolves hunk sections from the files a Source returns.
+type Resolver struct {
+	source Source
+}
+
+// NewResolver creates a Resolver reading files from source.
+func NewResolver(source Source) *Resolver {
+	return &Resolver{source: source}
+}
+
+// ResolveSymbols sets the Section of each hunk in a Go file to the
+// declaration enclosing its first change, such as "func (s *Server) Start"
+// or "type Config", or clears it for changes outside any declaration.
+// Files that can't be read or parsed, and hunks whose lines don't match
+// the file, as when the diff isn't of the version source returns, are left
+// as they are.
+func (r *Resolver) ResolveSymbols(diff *diffview.Diff) {
+	for i := range diff.Files {
+		file := &diff.Files[i]
+		if file.Operation == diffview.FileDeleted || len(file.Hunks) == 0 {
+			continue
+		}
+		path := file.Path()
+		if !strings.HasSuffix(path, ".go") {
+			continue
+		}
+		src, err := r.source(path)
+		if err != nil {
+			continue
+		}
+		fset := token.NewFileSet()
+		parsed, _ := parser.ParseFile(fset, path, src, parser.ParseComments|parser.SkipObjectResolution)
+		if parsed == nil {
+			continue
+		}
+		lines := strings.Split(string(src), "\n")
+		for j := range file.Hunks {
+			hunk := &file.Hunks[j]
+			if !matches(*hunk, lines) {
+				continue
+			}
+			if line, ok := firstChange(*hunk); ok {
+				hunk.Section = enclosing(fset, parsed, line)
+			}
+		}
+	}
+}
+
+// matches reports whether the hunk's context and added lines are the
+// file's lines at the same positions.
+func matches(hunk diffview.Hunk, lines []string) bool {
+	for _, line := range hunk.Lines {
+		if line.Type == diffview.LineDeleted {
+			continue
+		}
+		n := line.NewLineNum
+		if n < 1 || n > len(lines) || trimEOL(lines[n-1]) != trimEOL(line.Content) {
+			return false
+		}
+	}
+	return true
+}
+
+func trimEOL(s string) string {
+	return strings.TrimRight(s, "\r\n")
+}
+
+// firstChange returns the line in the new file of the hunk's first change:
+// the added line, or for a deletion the line that follows it.
+func firstChange(hunk diffview.Hunk) (int, bool) {
+	next := hunk.NewStart
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case diffview.LineAdded:
+			return line.NewLineNum, true
+		case diffview.LineDeleted:
+			return next, true
+		default:
+			next = line.NewLineNum + 1
+		}
+	}
+	return 0, false
+}
+
+// enclosing describes the top-level declaration spanning line, including
+// its doc comment, or returns "" if none does. Imports declare nothing a
+// change could be in, so lines among them have none.
+func enclosing(fset *token.FileSet, file *ast.File, line int) string {
+	within := func(start, end token.Pos) bool {
+		return fset.Position(start).Line <= line && line <= fset.Position(end).Line
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			start := d.Pos()
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+			if within(start, d.End()) {
+				return funcName(d)
+			}
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			start := d.Pos()
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+			if !within(start, d.End()) {
+				continue
+			}
+			for _, spec := range d.Specs {
+				if within(spec.Pos(), spec.End()) {
+					return d.Tok.String() + " " + specName(spec)
+				}
+			}
+			return d.Tok.String()
+		}
+	}
+	return ""
+}
+
+// funcName returns "func Name" or, for a method, "func (r *T) Name".
+func funcName(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return "func " + d.Name.Name
+	}
+	recv := d.Recv.List[0]
+	receiver := types.ExprString(recv.Type)
+	if len(recv.Names) > 0 {
+		receiver = recv.Names[0].Name + " " + receiver
+	}
+	return "func (" + receiver + ") " + d.Name.Name
+}
+
+// specName returns the name a type, var or const spec declares.
+func specName(spec ast.Spec) string {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Name.Name
+	case *ast.ValueSpec:
+		return s.Names[0].Name
+	}
+	return ""
+}
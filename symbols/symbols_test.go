@@ -0,0 +1,122 @@
+package symbols_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/symbols"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const server = `package server
+
+import "context"
+
+// Config configures a Server.
+type Config struct {
+	Addr string
+}
+
+// Start runs the server.
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.listen(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func helper() {}
+`
+
+// hunk returns a hunk of the lines of src from start to end, inclusive,
+// with the line numbered added marked as added, or none if added is 0.
+func hunk(src string, start, end, added int) diffview.Hunk {
+	lines := strings.Split(src, "\n")
+	h := diffview.Hunk{NewStart: start, NewCount: end - start + 1, Section: "git's guess"}
+	for n := start; n <= end; n++ {
+		typ := diffview.LineContext
+		if n == added {
+			typ = diffview.LineAdded
+		}
+		h.Lines = append(h.Lines, diffview.Line{Type: typ, Content: lines[n-1] + "\n", NewLineNum: n})
+	}
+	return h
+}
+
+func TestResolver_ResolveSymbols(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server.go"), []byte(server), 0o644))
+	stale := hunk(server, 11, 13, 12)
+	stale.Lines[1].Content = "changed since\n"
+	deletion := hunk(server, 17, 18, 0)
+	deletion.Lines = []diffview.Line{deletion.Lines[0], {Type: diffview.LineDeleted, Content: "// removed\n"}, deletion.Lines[1]}
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{NewPath: "b/server.go", Hunks: []diffview.Hunk{
+			hunk(server, 5, 8, 7),
+			hunk(server, 10, 13, 12),
+			hunk(server, 9, 11, 10),
+			deletion,
+			hunk(server, 1, 3, 3),
+			hunk(server, 15, 17, 17),
+			stale,
+		}},
+		{NewPath: "b/README.md", Hunks: []diffview.Hunk{hunk("# Server\n", 1, 1, 1)}},
+	}}
+
+	symbols.NewResolver(symbols.DirSource(dir)).ResolveSymbols(diff)
+
+	var sections []string
+	for _, h := range diff.Files[0].Hunks {
+		sections = append(sections, h.Section)
+	}
+	assert.Equal(t, []string{
+		"type Config",
+		"func (s *Server) Start",
+		"func (s *Server) Start", // doc comments belong to their declaration
+		"func helper",            // a deletion is placed before the line after it
+		"",                       // imports aren't declarations
+		"",
+		"git's guess", // lines don't match the file
+	}, sections)
+	assert.Equal(t, "git's guess", diff.Files[1].Hunks[0].Section, "only Go files are resolved")
+}
+
+func TestResolver_ResolveSymbols_ImportBlock(t *testing.T) {
+	t.Parallel()
+
+	src := `package component
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var _ tea.Model
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "component.go"), []byte(src), 0o644))
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{NewPath: "b/component.go", Hunks: []diffview.Hunk{hunk(src, 1, 4, 4)}},
+	}}
+
+	symbols.NewResolver(symbols.DirSource(dir)).ResolveSymbols(diff)
+
+	assert.Empty(t, diff.Files[0].Hunks[0].Section)
+}
+
+func TestResolver_ResolveSymbols_UnreadableFile(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{NewPath: "b/server.go", Hunks: []diffview.Hunk{hunk(server, 10, 13, 12)}},
+	}}
+
+	symbols.NewResolver(symbols.DirSource(t.TempDir())).ResolveSymbols(diff)
+
+	assert.Equal(t, "git's guess", diff.Files[0].Hunks[0].Section)
+}
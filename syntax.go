@@ -1,5 +1,10 @@
 package diffview
 
+import (
+	"iter"
+	"strings"
+)
+
 // Token represents a syntax-highlighted segment of code.
 type Token struct {
 	Text  string // The text content of this token
@@ -12,23 +17,78 @@ type Style struct {
 	Bold       bool   // Whether the text should be bold
 }
 
-// Tokenizer extracts syntax tokens from source code.
+// Tokenizer extracts syntax tokens from source code, lexing it whole so that
+// lexer state carries from line to line, and returns them split by line.
+// Viewers and stories render each side of a hunk with TokenizeLines;
+// StreamLines is for callers that may stop before the end.
 type Tokenizer interface {
-	// Tokenize splits source code into syntax-highlighted tokens for the given language.
-	// Returns nil if the language is not supported.
-	Tokenize(language, source string) []Token
-
 	// TokenizeLines tokenizes multi-line source code with full context,
 	// returning tokens split by line. This correctly handles multi-line
 	// constructs like /* */ comments and JSDoc.
 	// Returns nil if the language is not supported.
 	TokenizeLines(language, source string) [][]Token
+
+	// StreamLines tokenizes source like TokenizeLines, yielding each line's
+	// tokens as soon as they're lexed.
+	// Yields nothing if the language is not supported.
+	StreamLines(language, source string) iter.Seq[[]Token]
 }
 
-// LanguageDetector determines the programming language from a file path.
+// LanguageDetector determines the programming language of a file from its
+// path or its content.
 type LanguageDetector interface {
 	// DetectFromPath returns the language name for the given path,
 	// or an empty string if the language cannot be determined.
 	// Accepts paths with or without "a/" or "b/" prefixes (common in diffs).
 	DetectFromPath(path string) string
+
+	// DetectFromContent returns the language name for a file starting
+	// with content, from a shebang, modeline or similar, or an empty
+	// string if the language cannot be determined.
+	DetectFromContent(content string) string
+}
+
+// openingLines caps how much of a file's start is read to detect its
+// language.
+const openingLines = 20
+
+// DetectLanguage returns the language to highlight file as: its Language
+// if set, else what d detects from its path, else from its opening lines
+// when the diff shows them, as it does for new files and changes at the
+// top of a file. An empty string means no highlighting.
+func DetectLanguage(d LanguageDetector, file FileDiff) string {
+	if file.Language != "" || d == nil {
+		return file.Language
+	}
+	path := file.NewPath
+	if path == "" {
+		path = file.OldPath
+	}
+	if lang := d.DetectFromPath(path); lang != "" {
+		return lang
+	}
+	if len(file.Hunks) == 0 {
+		return ""
+	}
+	// Deleted files only have their old lines
+	hunk := file.Hunks[0]
+	skip, start := LineDeleted, hunk.NewStart
+	if file.Operation == FileDeleted {
+		skip, start = LineAdded, hunk.OldStart
+	}
+	if start > 1 {
+		return ""
+	}
+	var sb strings.Builder
+	n := 0
+	for _, line := range hunk.Lines {
+		if line.Type == skip {
+			continue
+		}
+		sb.WriteString(line.Content)
+		if n++; n == openingLines {
+			break
+		}
+	}
+	return d.DetectFromContent(sb.String())
 }
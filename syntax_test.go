@@ -0,0 +1,73 @@
+package diffview_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	t.Parallel()
+
+	// Knows .go paths, and Python files by their shebang
+	detector := &mock.LanguageDetector{
+		DetectFromPathFn: func(path string) string {
+			if path == "b/main.go" {
+				return "Go"
+			}
+			return ""
+		},
+		DetectFromContentFn: func(content string) string {
+			if content == "#!/usr/bin/env python3\nprint()\n" {
+				return "Python"
+			}
+			return ""
+		},
+	}
+	script := []diffview.Line{
+		{Type: diffview.LineAdded, Content: "#!/usr/bin/env python3\n"},
+		{Type: diffview.LineDeleted, Content: "#!/bin/sh\n"},
+		{Type: diffview.LineAdded, Content: "print()\n"},
+	}
+
+	t.Run("language override wins", func(t *testing.T) {
+		t.Parallel()
+		file := diffview.FileDiff{NewPath: "b/main.go", Language: "Rust"}
+		assert.Equal(t, "Rust", diffview.DetectLanguage(detector, file))
+	})
+
+	t.Run("path", func(t *testing.T) {
+		t.Parallel()
+		file := diffview.FileDiff{NewPath: "b/main.go"}
+		assert.Equal(t, "Go", diffview.DetectLanguage(detector, file))
+	})
+
+	t.Run("content of the file's opening lines", func(t *testing.T) {
+		t.Parallel()
+		file := diffview.FileDiff{NewPath: "b/bin/run", Hunks: []diffview.Hunk{{NewStart: 1, Lines: script}}}
+		assert.Equal(t, "Python", diffview.DetectLanguage(detector, file))
+	})
+
+	t.Run("old lines of a deleted file", func(t *testing.T) {
+		t.Parallel()
+		file := diffview.FileDiff{OldPath: "a/bin/run", Operation: diffview.FileDeleted, Hunks: []diffview.Hunk{{OldStart: 1, Lines: []diffview.Line{
+			{Type: diffview.LineDeleted, Content: "#!/usr/bin/env python3\n"},
+			{Type: diffview.LineDeleted, Content: "print()\n"},
+		}}}}
+		assert.Equal(t, "Python", diffview.DetectLanguage(detector, file))
+	})
+
+	t.Run("not from the middle of a file", func(t *testing.T) {
+		t.Parallel()
+		file := diffview.FileDiff{NewPath: "b/bin/run", Hunks: []diffview.Hunk{{NewStart: 40, Lines: script}}}
+		assert.Empty(t, diffview.DetectLanguage(detector, file))
+	})
+
+	t.Run("without a detector only the override", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "Rust", diffview.DetectLanguage(nil, diffview.FileDiff{NewPath: "b/main.go", Language: "Rust"}))
+		assert.Empty(t, diffview.DetectLanguage(nil, diffview.FileDiff{NewPath: "b/main.go"}))
+	})
+}
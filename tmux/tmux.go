@@ -0,0 +1,106 @@
+// Package tmux opens commands in a tmux pane beside the one a program runs
+// in, so the program stays on screen alongside.
+package tmux
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Placement is where a pane opens relative to the program's.
+type Placement string
+
+// Placements a pane can open in.
+const (
+	PlaceRight  Placement = "right"  // Split the window, the new pane to the right
+	PlaceBelow  Placement = "below"  // Split the window, the new pane below
+	PlaceWindow Placement = "window" // A new window
+)
+
+// ParsePlacement parses a placement name, empty for PlaceRight.
+func ParsePlacement(s string) (Placement, error) {
+	switch p := Placement(strings.TrimSpace(s)); p {
+	case "":
+		return PlaceRight, nil
+	case PlaceRight, PlaceBelow, PlaceWindow:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown tmux pane placement %q (want right, below or window)", s)
+	}
+}
+
+// Pane opens commands in a new tmux pane, typing them into its shell with
+// send-keys so that the shell stays once they finish.
+type Pane struct {
+	Placement Placement
+	Dir       string // Directory the pane's shell starts in
+}
+
+// FromEnv returns the pane to open commands in when running inside tmux,
+// placed as $DIFFVIEW_TMUX_PANE says. Returns nil outside tmux.
+func FromEnv(getenv func(key string) string) (*Pane, error) {
+	if getenv("TMUX") == "" {
+		return nil, nil
+	}
+	placement, err := ParsePlacement(getenv("DIFFVIEW_TMUX_PANE"))
+	if err != nil {
+		return nil, err
+	}
+	return &Pane{Placement: placement}, nil
+}
+
+// Args returns the tmux command line that opens a pane and runs command, a
+// shell command line, in it. Commands after the split go to the new pane,
+// which tmux makes current.
+func (p *Pane) Args(command string) []string {
+	args := []string{"tmux"}
+	switch p.Placement {
+	case PlaceWindow:
+		args = append(args, "new-window")
+	case PlaceBelow:
+		args = append(args, "split-window", "-v")
+	default:
+		args = append(args, "split-window", "-h")
+	}
+	if p.Dir != "" {
+		args = append(args, "-c", p.Dir)
+	}
+	return append(args, ";", "send-keys", "-l", command, ";", "send-keys", "Enter")
+}
+
+// Command returns the command that opens a pane and runs command in it.
+func (p *Pane) Command(command string) *exec.Cmd {
+	args := p.Args(command)
+	return exec.Command(args[0], args[1:]...)
+}
+
+// FileCommand returns the shell command that pages through path from line.
+func FileCommand(path string, line int) string {
+	return fmt.Sprintf("less -N +%dg %s", max(line, 1), Quote(path))
+}
+
+// BlameCommand returns the shell command that pages through the blame of
+// path from line.
+func BlameCommand(path string, line int) string {
+	return fmt.Sprintf("git blame -- %s | less +%dg", Quote(path), max(line, 1))
+}
+
+// LogCommand returns the shell command that shows the history of path,
+// following renames.
+func LogCommand(path string) string {
+	return "git log -p --follow -- " + Quote(path)
+}
+
+// Quote quotes s as a single word for the shell.
+func Quote(s string) string {
+	if s != "" && strings.IndexFunc(s, needsQuote) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// needsQuote reports whether r is special to the shell, or might be.
+func needsQuote(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./+=:,@%", r))
+}
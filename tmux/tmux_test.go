@@ -0,0 +1,85 @@
+package tmux_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory/tmux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	t.Run("returns nil outside tmux", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := tmux.FromEnv(env(map[string]string{"DIFFVIEW_TMUX_PANE": "below"}))
+
+		require.NoError(t, err)
+		assert.Nil(t, p)
+	})
+
+	t.Run("splits to the right by default", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := tmux.FromEnv(env(map[string]string{"TMUX": "/tmp/tmux-1000/default,123,0"}))
+
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, tmux.PlaceRight, p.Placement)
+	})
+
+	t.Run("reads the placement", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := tmux.FromEnv(env(map[string]string{"TMUX": "/tmp/tmux", "DIFFVIEW_TMUX_PANE": "window"}))
+
+		require.NoError(t, err)
+		assert.Equal(t, tmux.PlaceWindow, p.Placement)
+	})
+
+	t.Run("rejects an unknown placement", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := tmux.FromEnv(env(map[string]string{"TMUX": "/tmp/tmux", "DIFFVIEW_TMUX_PANE": "left"}))
+
+		assert.ErrorContains(t, err, `unknown tmux pane placement "left"`)
+	})
+}
+
+func TestPane_Args(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		placement tmux.Placement
+		want      []string
+	}{
+		{"right", tmux.PlaceRight, []string{"tmux", "split-window", "-h", "-c", "/repo"}},
+		{"below", tmux.PlaceBelow, []string{"tmux", "split-window", "-v", "-c", "/repo"}},
+		{"window", tmux.PlaceWindow, []string{"tmux", "new-window", "-c", "/repo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &tmux.Pane{Placement: tt.placement, Dir: "/repo"}
+
+			want := append(tt.want, ";", "send-keys", "-l", "git log", ";", "send-keys", "Enter")
+			assert.Equal(t, want, p.Args("git log"))
+		})
+	}
+}
+
+func TestCommands(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "less -N +42g pkg/main.go", tmux.FileCommand("pkg/main.go", 42))
+	assert.Equal(t, "git blame -- 'a b.go' | less +1g", tmux.BlameCommand("a b.go", 0))
+	assert.Equal(t, `git log -p --follow -- 'it'\''s.go'`, tmux.LogCommand("it's.go"))
+}
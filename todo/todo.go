@@ -0,0 +1,64 @@
+// Package todo annotates the TODO, FIXME, HACK and XXX comments a diff adds.
+package todo
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fwojciec/diffstory"
+)
+
+// Compile-time interface verification.
+var _ diffview.Annotator = (*Annotator)(nil)
+
+// markerPattern matches a marker word, an optional owner in parentheses
+// and the text after it.
+var markerPattern = regexp.MustCompile(`\b(TODO|FIXME|HACK|XXX)\b(\([^)]*\))?:?(.*)`)
+
+// Annotator notes each added line with a TODO-style marker.
+type Annotator struct{}
+
+// NewAnnotator creates an Annotator.
+func NewAnnotator() *Annotator {
+	return &Annotator{}
+}
+
+// Name implements diffview.Annotator.
+func (a *Annotator) Name() string {
+	return "todo"
+}
+
+// Annotate returns a line note for each added line with a marker, reading
+// the marker and what follows it.
+func (a *Annotator) Annotate(diff *diffview.Diff) []diffview.Annotation {
+	var notes []diffview.Annotation
+	for _, file := range diff.Files {
+		if file.Operation == diffview.FileDeleted {
+			continue
+		}
+		path := file.Path()
+		for i, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				if line.Type != diffview.LineAdded {
+					continue
+				}
+				m := markerPattern.FindStringSubmatch(line.Content)
+				if m == nil {
+					continue
+				}
+				text := m[1] + m[2]
+				if rest := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[3]), "*/")); rest != "" {
+					text += ": " + rest
+				}
+				notes = append(notes, diffview.Annotation{
+					Path: path,
+					Hunk: i + 1,
+					Line: line.NewLineNum,
+					Text: text,
+					Icon: "◇",
+				})
+			}
+		}
+	}
+	return notes
+}
@@ -0,0 +1,43 @@
+package todo_test
+
+import (
+	"testing"
+
+	"github.com/fwojciec/diffstory"
+	"github.com/fwojciec/diffstory/todo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotator_Annotate(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{Files: []diffview.FileDiff{
+		{
+			OldPath: "a/api.go",
+			NewPath: "b/api.go",
+			Hunks: []diffview.Hunk{
+				{Lines: []diffview.Line{
+					{Type: diffview.LineContext, Content: "// TODO: already there", OldLineNum: 1, NewLineNum: 1},
+					{Type: diffview.LineDeleted, Content: "// FIXME: removed", OldLineNum: 2},
+				}},
+				{Lines: []diffview.Line{
+					{Type: diffview.LineAdded, Content: "\t// TODO(alice): retry on timeout", NewLineNum: 10},
+					{Type: diffview.LineAdded, Content: "/* HACK */", NewLineNum: 11},
+					{Type: diffview.LineAdded, Content: "todoList := nil", NewLineNum: 12},
+				}},
+			},
+		},
+		{
+			OldPath:   "a/old.go",
+			Operation: diffview.FileDeleted,
+			Hunks:     []diffview.Hunk{{Lines: []diffview.Line{{Type: diffview.LineDeleted, Content: "// XXX", OldLineNum: 1}}}},
+		},
+	}}
+
+	notes := todo.NewAnnotator().Annotate(diff)
+
+	assert.Equal(t, []diffview.Annotation{
+		{Path: "api.go", Hunk: 2, Line: 10, Text: "TODO(alice): retry on timeout", Icon: "◇"},
+		{Path: "api.go", Hunk: 2, Line: 11, Text: "HACK", Icon: "◇"},
+	}, notes)
+}
@@ -1,6 +1,9 @@
 package diffview
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 // ValidationReason identifies why a HunkRef is invalid.
 type ValidationReason string
@@ -43,19 +46,7 @@ func (e ValidationError) Error() string {
 // are valid for the given diff. Returns a slice of validation errors, or nil
 // if the classification is valid.
 func ValidateClassification(diff *Diff, classification *StoryClassification) []ValidationError {
-	// Build a map of file paths to their hunk counts for fast lookup
-	hunkCounts := make(map[string]int)
-	for _, file := range diff.Files {
-		// Use NewPath as the canonical path (handles renames/additions)
-		path := file.NewPath
-		if path == "" {
-			path = file.OldPath // For deletions
-		}
-		if path == "" {
-			continue // Skip malformed file entries
-		}
-		hunkCounts[path] = len(file.Hunks)
-	}
+	hunkCounts := hunkCounts(diff)
 
 	var errors []ValidationError
 
@@ -84,3 +75,109 @@ func ValidateClassification(diff *Diff, classification *StoryClassification) []V
 
 	return errors
 }
+
+// hunkCounts maps the path of each file in diff to its number of hunks, as
+// hunk references name files.
+func hunkCounts(diff *Diff) map[string]int {
+	counts := make(map[string]int)
+	for _, file := range diff.Files {
+		if path := refPath(file); path != "" {
+			counts[path] = len(file.Hunks)
+		}
+	}
+	return counts
+}
+
+// refPath returns the path hunk references name file by: its new path, or
+// its old one if it was deleted. Empty for malformed file entries.
+func refPath(file FileDiff) string {
+	if file.NewPath != "" {
+		return file.NewPath
+	}
+	return file.OldPath
+}
+
+// HunkCategories returns the categories a classifier may give a hunk.
+func HunkCategories() []string {
+	return []string{"core", "refactoring", "systematic", "noise"}
+}
+
+// IssueKind is what's wrong with a story, as ValidateStory finds it.
+type IssueKind string
+
+// Kinds of issues with a story.
+const (
+	IssueInvalidRef      IssueKind = "invalid_ref"      // A hunk reference names no hunk of the diff
+	IssueDuplicate       IssueKind = "duplicate"        // A hunk is in more than one place
+	IssueUnknownCategory IssueKind = "unknown_category" // A hunk's category isn't one of HunkCategories
+	IssueEmptySection    IssueKind = "empty_section"    // A section has no hunks
+	IssueUncovered       IssueKind = "uncovered"        // A hunk of the diff is in no section
+)
+
+// Issue is something wrong with a story told about a diff.
+type Issue struct {
+	Kind    IssueKind
+	Section int     // Index of the section, or -1 for a hunk in none
+	HunkRef HunkRef // The hunk concerned, if any
+	Message string  // What's wrong, for people
+}
+
+// String describes the issue in a line.
+func (i Issue) String() string {
+	if i.Section < 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("section %d: %s", i.Section, i.Message)
+}
+
+// ValidateStory checks story against diff: that its hunk references name
+// hunks of the diff, that every hunk is in exactly one section, that each
+// hunk's category is one of HunkCategories, and that no section is empty.
+// Returns the issues found, each section's in turn and then the hunks in no
+// section, or nil if there are none.
+func ValidateStory(diff *Diff, story *StoryClassification) []Issue {
+	counts := hunkCounts(diff)
+	seen := make(map[HunkRef]bool) // by file and index only
+	var issues []Issue
+	for sectionIdx, section := range story.Sections {
+		add := func(kind IssueKind, ref HunkRef, format string, args ...any) {
+			issues = append(issues, Issue{Kind: kind, Section: sectionIdx, HunkRef: ref, Message: fmt.Sprintf(format, args...)})
+		}
+		if len(section.Hunks) == 0 {
+			add(IssueEmptySection, HunkRef{}, "section %q has no hunks", section.Title)
+		}
+		for _, ref := range section.Hunks {
+			key := HunkRef{File: ref.File, HunkIndex: ref.HunkIndex}
+			count, found := counts[ref.File]
+			switch {
+			case !found:
+				add(IssueInvalidRef, ref, "file %q not found in diff", ref.File)
+			case ref.HunkIndex < 0 || ref.HunkIndex >= count:
+				add(IssueInvalidRef, ref, "file %q hunk_index %d is out of bounds (%d hunks)", ref.File, ref.HunkIndex, count)
+			case seen[key]:
+				add(IssueDuplicate, ref, "file %q hunk_index %d is already in a section", ref.File, ref.HunkIndex)
+			}
+			seen[key] = true
+			if !slices.Contains(HunkCategories(), ref.Category) {
+				add(IssueUnknownCategory, ref, "file %q hunk_index %d has unknown category %q", ref.File, ref.HunkIndex, ref.Category)
+			}
+		}
+	}
+	for _, file := range diff.Files {
+		path := refPath(file)
+		if path == "" {
+			continue
+		}
+		for i := range file.Hunks {
+			if ref := (HunkRef{File: path, HunkIndex: i}); !seen[ref] {
+				issues = append(issues, Issue{
+					Kind:    IssueUncovered,
+					Section: -1,
+					HunkRef: ref,
+					Message: fmt.Sprintf("file %q hunk_index %d is in no section", path, i),
+				})
+			}
+		}
+	}
+	return issues
+}
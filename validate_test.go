@@ -253,3 +253,85 @@ func TestValidateClassification_PR83Case(t *testing.T) {
 	assert.Contains(t, errMsg, "hunk_index 7")
 	assert.Contains(t, errMsg, "valid: 0-6")
 }
+
+func TestValidateStory(t *testing.T) {
+	t.Parallel()
+
+	diff := &diffview.Diff{
+		Files: []diffview.FileDiff{
+			{NewPath: "foo.go", Hunks: make([]diffview.Hunk, 2)},
+			{OldPath: "gone.go", NewPath: "", Hunks: make([]diffview.Hunk, 1)},
+		},
+	}
+
+	t.Run("valid story has no issues", func(t *testing.T) {
+		t.Parallel()
+
+		story := &diffview.StoryClassification{
+			Sections: []diffview.Section{
+				{Title: "Core", Hunks: []diffview.HunkRef{
+					{File: "foo.go", HunkIndex: 0, Category: "core"},
+					{File: "foo.go", HunkIndex: 1, Category: "refactoring"},
+				}},
+				{Title: "Cleanup", Hunks: []diffview.HunkRef{
+					{File: "gone.go", HunkIndex: 0, Category: "noise"},
+				}},
+			},
+		}
+
+		assert.Nil(t, diffview.ValidateStory(diff, story))
+	})
+
+	t.Run("reports every kind of issue in order", func(t *testing.T) {
+		t.Parallel()
+
+		story := &diffview.StoryClassification{
+			Sections: []diffview.Section{
+				{Title: "Core", Hunks: []diffview.HunkRef{
+					{File: "foo.go", HunkIndex: 0, Category: "core"},
+					{File: "foo.go", HunkIndex: 5, Category: "core"},
+					{File: "missing.go", HunkIndex: 0, Category: "core"},
+				}},
+				{Title: "Empty"},
+				{Title: "Again", Hunks: []diffview.HunkRef{
+					{File: "foo.go", HunkIndex: 0, Category: "bogus"},
+				}},
+			},
+		}
+
+		issues := diffview.ValidateStory(diff, story)
+
+		kinds := make([]diffview.IssueKind, len(issues))
+		for i, issue := range issues {
+			kinds[i] = issue.Kind
+		}
+		assert.Equal(t, []diffview.IssueKind{
+			diffview.IssueInvalidRef,
+			diffview.IssueInvalidRef,
+			diffview.IssueEmptySection,
+			diffview.IssueDuplicate,
+			diffview.IssueUnknownCategory,
+			diffview.IssueUncovered,
+			diffview.IssueUncovered,
+		}, kinds)
+		assert.Equal(t, 2, issues[3].Section)
+		assert.Equal(t, diffview.HunkRef{File: "foo.go", HunkIndex: 1}, issues[5].HunkRef)
+		assert.Equal(t, diffview.HunkRef{File: "gone.go", HunkIndex: 0}, issues[6].HunkRef)
+	})
+
+	t.Run("string prefixes the section", func(t *testing.T) {
+		t.Parallel()
+
+		story := &diffview.StoryClassification{
+			Sections: []diffview.Section{
+				{Title: "Core", Hunks: []diffview.HunkRef{{File: "missing.go", Category: "core"}}},
+			},
+		}
+
+		issues := diffview.ValidateStory(diff, story)
+
+		require.Len(t, issues, 4)
+		assert.Equal(t, `section 0: file "missing.go" not found in diff`, issues[0].String())
+		assert.Equal(t, `file "foo.go" hunk_index 0 is in no section`, issues[1].String())
+	})
+}
@@ -7,3 +7,36 @@ type Viewer interface {
 	// View displays the diff and blocks until the user exits.
 	View(ctx context.Context, diff *Diff) error
 }
+
+// ViewState is a reader's place in a diff, saved so that reopening the same
+// diff resumes where they left off.
+type ViewState struct {
+	Offset        int       `json:"offset"`                 // first visible row
+	ActiveSection int       `json:"active_section"`         // section being read, counting the intro slide
+	AllSections   bool      `json:"all_sections,omitempty"` // all sections shown at once
+	Wrap          bool      `json:"wrap,omitempty"`         // long lines soft-wrapped
+	Collapsed     []HunkRef `json:"collapsed,omitempty"`    // every collapsed hunk
+}
+
+// ViewStateStore persists view state per diff. Diffs with the same content
+// share a state.
+type ViewStateStore interface {
+	// Load returns the saved state for diff, or nil if there is none.
+	Load(diff *Diff) (*ViewState, error)
+	Save(diff *Diff, state ViewState) error
+}
+
+// Preferences are a reviewer's display choices, kept from one session to
+// the next instead of starting from the defaults on each launch.
+type Preferences struct {
+	SplitRatio int  `json:"split_ratio,omitempty"`  // percentage of the height for the story pane
+	RawMode    bool `json:"raw_mode,omitempty"`     // open cases as the whole diff rather than section by section
+	NoWordDiff bool `json:"no_word_diff,omitempty"` // word-level highlighting turned off
+}
+
+// PreferencesStore persists a user's preferences.
+type PreferencesStore interface {
+	// Load returns the saved preferences, or nil if there are none.
+	Load() (*Preferences, error)
+	Save(prefs Preferences) error
+}
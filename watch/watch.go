@@ -0,0 +1,93 @@
+// Package watch reports changes to a git working tree using fsnotify.
+package watch
+
+import (
+	"context"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long changes must settle before a notification,
+// so a burst of writes such as a save or a checkout produces just one.
+const DefaultDebounce = 200 * time.Millisecond
+
+// Watch calls onChange whenever files under root change, once the changes
+// have settled for debounce. It blocks until ctx is done. Directories
+// created later are watched too. Inside .git only the top level and refs
+// are watched, which is enough to notice staging, commits and checkouts.
+func Watch(ctx context.Context, root string, debounce time.Duration, onChange func()) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer w.Close()
+
+	if err := addTree(w, root); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", root, err)
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if ignored(event) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// Best-effort: a directory removed right away needs no watch
+					_ = addTree(w, event.Name)
+				}
+			}
+			timer.Reset(debounce)
+		case <-w.Errors:
+			// Dropped events still leave the tree changed; refresh to be safe
+			timer.Reset(debounce)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
+
+// addTree watches dir and every directory below it.
+func addTree(w *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			// Skip directories that vanish or can't be read
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" {
+			if err := w.Add(path); err != nil {
+				return err
+			}
+			// Best-effort: bare-bones repos may have no refs directory yet
+			_ = addTree(w, filepath.Join(path, "refs"))
+			return filepath.SkipDir
+		}
+		return w.Add(path)
+	})
+}
+
+// ignored reports whether an event can't change a diff: permission changes
+// and git's lock files, which come and go around every git command.
+func ignored(event fsnotify.Event) bool {
+	return event.Op == fsnotify.Chmod || strings.HasSuffix(event.Name, ".lock")
+}
@@ -0,0 +1,102 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fwojciec/diffstory/watch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startWatch watches dir until the test ends and returns a channel that
+// receives each notification.
+func startWatch(t *testing.T, dir string) <-chan struct{} {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan struct{}, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- watch.Watch(ctx, dir, 50*time.Millisecond, func() { changes <- struct{}{} })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	// Give the watcher time to register before changing files
+	time.Sleep(50 * time.Millisecond)
+	return changes
+}
+
+func waitForChange(t *testing.T, changes <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+}
+
+func TestWatch_CoalescesBurstsOfWrites(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	changes := startWatch(t, dir)
+
+	for i := range 5 {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte{byte('a' + i)}, 0644))
+	}
+
+	waitForChange(t, changes)
+	select {
+	case <-changes:
+		t.Fatal("burst reported more than once")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatch_WatchesNewDirectories(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	changes := startWatch(t, dir)
+
+	sub := filepath.Join(dir, "pkg")
+	require.NoError(t, os.Mkdir(sub, 0755))
+	waitForChange(t, changes)
+
+	require.NoError(t, os.WriteFile(filepath.Join(sub, "pkg.go"), []byte("package pkg\n"), 0644))
+	waitForChange(t, changes)
+}
+
+func TestWatch_IgnoresGitLockFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	gitDir := filepath.Join(dir, ".git")
+	require.NoError(t, os.MkdirAll(filepath.Join(gitDir, "objects"), 0755))
+
+	var count atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watch.Watch(ctx, dir, 50*time.Millisecond, func() { count.Add(1) })
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Lock files and object writes don't change what a diff shows
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "index.lock"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "objects", "ab"), nil, 0644))
+	time.Sleep(200 * time.Millisecond)
+	assert.Zero(t, count.Load())
+
+	// The index itself does
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "index"), nil, 0644))
+	assert.Eventually(t, func() bool { return count.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+}